@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+type memberList []models.TeamMember
+
+func (m *memberList) String() string {
+	return fmt.Sprint(*m)
+}
+
+// Set parses ID:USERNAME[:inactive].
+func (m *memberList) Set(value string) error {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return fmt.Errorf("member must be ID:USERNAME[:inactive], got %q", value)
+	}
+	member := models.TeamMember{UserID: parts[0], Username: parts[1], IsActive: true}
+	if len(parts) == 3 {
+		if parts[2] != "inactive" {
+			return fmt.Errorf("unknown member flag %q", parts[2])
+		}
+		member.IsActive = false
+	}
+	*m = append(*m, member)
+	return nil
+}
+
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func (a *app) teamAdd(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("team add", flag.ContinueOnError)
+	name := fs.String("name", "", "team name")
+	file := fs.String("file", "", "JSON file with {team_name, members}")
+	var members memberList
+	fs.Var(&members, "member", "team member as ID:USERNAME[:inactive], repeatable")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var team models.Team
+	if *file != "" {
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &team); err != nil {
+			return fmt.Errorf("parse %s: %w", *file, err)
+		}
+	}
+	if *name != "" {
+		team.TeamName = *name
+	}
+	team.Members = append(team.Members, members...)
+	if team.TeamName == "" {
+		return errors.New("--name or --file is required")
+	}
+
+	created, err := a.client.CreateTeam(ctx, team)
+	if err != nil {
+		return err
+	}
+	return a.printTeam(created)
+}
+
+func (a *app) teamGet(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("team get", flag.ContinueOnError)
+	name := fs.String("name", "", "team name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *name == "" {
+		return errors.New("--name is required")
+	}
+
+	team, err := a.client.GetTeam(ctx, *name)
+	if err != nil {
+		return err
+	}
+	return a.printTeam(team)
+}
+
+func (a *app) prCreate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("pr create", flag.ContinueOnError)
+	id := fs.String("id", "", "pull request id")
+	name := fs.String("name", "", "pull request name")
+	author := fs.String("author", "", "author user id")
+	repo := fs.String("repo", "", "repository or repository/component")
+	priority := fs.String("priority", "", "LOW, NORMAL or URGENT")
+	draft := fs.Bool("draft", false, "create as a draft without reviewers")
+	lines := fs.Int("lines", 0, "number of changed lines")
+	files := fs.Int("files", 0, "number of changed files")
+	var paths, preferred stringList
+	fs.Var(&paths, "path", "changed file path, repeatable")
+	fs.Var(&preferred, "reviewer", "preferred reviewer user id, repeatable")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == "" || *name == "" || *author == "" {
+		return errors.New("--id, --name and --author are required")
+	}
+
+	pr, err := a.client.CreatePullRequest(ctx, dto.CreatePullRequestRequest{
+		PullRequestID:      *id,
+		PullRequestName:    *name,
+		AuthorID:           *author,
+		ChangedPaths:       paths,
+		Repository:         *repo,
+		Priority:           models.PullRequestPriority(*priority),
+		IsDraft:            *draft,
+		LinesChanged:       *lines,
+		FilesChanged:       *files,
+		PreferredReviewers: preferred,
+	})
+	if err != nil {
+		return err
+	}
+	return a.printPullRequest(pr)
+}
+
+func (a *app) prReady(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("pr ready", flag.ContinueOnError)
+	id := fs.String("id", "", "pull request id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == "" {
+		return errors.New("--id is required")
+	}
+
+	pr, err := a.client.MarkPullRequestReady(ctx, *id)
+	if err != nil {
+		return err
+	}
+	return a.printPullRequest(pr)
+}
+
+func (a *app) prMerge(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("pr merge", flag.ContinueOnError)
+	id := fs.String("id", "", "pull request id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == "" {
+		return errors.New("--id is required")
+	}
+
+	pr, err := a.client.MergePullRequest(ctx, *id)
+	if err != nil {
+		return err
+	}
+	return a.printPullRequest(pr)
+}
+
+func (a *app) prReassign(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("pr reassign", flag.ContinueOnError)
+	id := fs.String("id", "", "pull request id")
+	oldUser := fs.String("old-user", "", "reviewer to replace")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == "" || *oldUser == "" {
+		return errors.New("--id and --old-user are required")
+	}
+
+	resp, err := a.client.ReassignReviewer(ctx, *id, *oldUser)
+	if err != nil {
+		return err
+	}
+	if a.output == "json" {
+		return printJSON(resp)
+	}
+	fmt.Printf("%s replaced by %s\n", *oldUser, resp.ReplacedBy)
+	return a.printPullRequest(&resp.PR)
+}
+
+func (a *app) userSetActive(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("user set-active", flag.ContinueOnError)
+	id := fs.String("id", "", "user id")
+	active := fs.Bool("active", true, "new activity flag")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == "" {
+		return errors.New("--id is required")
+	}
+
+	user, err := a.client.SetUserActive(ctx, *id, *active)
+	if err != nil {
+		return err
+	}
+	if a.output == "json" {
+		return printJSON(user)
+	}
+	t := newTable("USER_ID", "USERNAME", "TEAM", "ACTIVE")
+	t.row(user.UserID, user.Username, user.TeamName, user.IsActive)
+	return t.flush()
+}
+
+func (a *app) userReviews(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("user reviews", flag.ContinueOnError)
+	id := fs.String("id", "", "user id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == "" {
+		return errors.New("--id is required")
+	}
+
+	resp, err := a.client.GetUserReviews(ctx, *id)
+	if err != nil {
+		return err
+	}
+	if a.output == "json" {
+		return printJSON(resp)
+	}
+	t := newTable("PR_ID", "NAME", "AUTHOR", "STATUS")
+	for _, pr := range resp.PullRequestsShort {
+		t.row(pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status)
+	}
+	return t.flush()
+}
+
+func (a *app) stats(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	stats, err := a.client.GetStatistics(ctx)
+	if err != nil {
+		return err
+	}
+	if a.output == "json" {
+		return printJSON(stats)
+	}
+
+	t := newTable("METRIC", "VALUE")
+	t.row("total_teams", stats.TotalTeams)
+	t.row("total_users", stats.TotalUsers)
+	t.row("active_users", stats.ActiveUsers)
+	t.row("total_prs", stats.TotalPRs)
+	t.row("open_prs", stats.OpenPRs)
+	t.row("merged_prs", stats.MergedPRs)
+	t.row("draft_prs", stats.DraftPRs)
+	t.row("open_low_prs", stats.OpenByPriority[models.PriorityLow])
+	t.row("open_normal_prs", stats.OpenByPriority[models.PriorityNormal])
+	t.row("open_urgent_prs", stats.OpenByPriority[models.PriorityUrgent])
+	if err := t.flush(); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	t = newTable("USER_ID", "USERNAME", "OPEN", "COMPLETED", "TOTAL")
+	for _, rs := range stats.TopReviewers {
+		t.row(rs.UserID, rs.Username, rs.OpenReviews, rs.CompletedReviews, rs.TotalReviews)
+	}
+	return t.flush()
+}
+
+func (a *app) printTeam(team *models.Team) error {
+	if a.output == "json" {
+		return printJSON(team)
+	}
+	fmt.Printf("team: %s\n", team.TeamName)
+	t := newTable("USER_ID", "USERNAME", "ACTIVE")
+	for _, m := range team.Members {
+		t.row(m.UserID, m.Username, m.IsActive)
+	}
+	return t.flush()
+}
+
+func (a *app) printPullRequest(pr *models.PullRequest) error {
+	if a.output == "json" {
+		return printJSON(pr)
+	}
+	t := newTable("PR_ID", "NAME", "AUTHOR", "STATUS", "REVIEWERS")
+	t.row(pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, strings.Join(pr.AssignedReviewers, ","))
+	return t.flush()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Thorlik/avito_internship/internal/client"
+)
+
+const usage = `Usage: cli [global flags] <command> [flags]
+
+Commands:
+  team add       --name NAME --member ID:USERNAME[:inactive] ... | --file team.json
+  team get       --name NAME
+  pr create      --id ID --name NAME --author USER_ID [--path FILE ...]
+  pr ready       --id ID
+  pr merge       --id ID
+  pr reassign    --id ID --old-user USER_ID
+  user set-active --id USER_ID [--active=false]
+  user reviews   --id USER_ID
+  stats
+
+Global flags:
+`
+
+type app struct {
+	client *client.Client
+	output string
+}
+
+func main() {
+	global := flag.NewFlagSet("cli", flag.ExitOnError)
+	baseURL := global.String("url", envOr("PR_API_URL", "http://localhost:8080"), "service base URL (env PR_API_URL)")
+	apiKey := global.String("api-key", os.Getenv("PR_API_KEY"), "API key sent as X-API-Key (env PR_API_KEY)")
+	output := global.String("output", "table", "output format: table or json")
+	global.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		global.PrintDefaults()
+	}
+	_ = global.Parse(os.Args[1:])
+
+	if *output != "table" && *output != "json" {
+		fatalf("unknown output format %q", *output)
+	}
+
+	args := global.Args()
+	if len(args) == 0 {
+		global.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	a := &app{client: client.New(*baseURL, *apiKey), output: *output}
+	if err := a.run(ctx, args); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func (a *app) run(ctx context.Context, args []string) error {
+	command := args[0]
+	if command == "stats" {
+		return a.stats(ctx, args[1:])
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("%s: missing subcommand", command)
+	}
+
+	sub, rest := args[1], args[2:]
+	switch command + " " + sub {
+	case "team add":
+		return a.teamAdd(ctx, rest)
+	case "team get":
+		return a.teamGet(ctx, rest)
+	case "pr create":
+		return a.prCreate(ctx, rest)
+	case "pr ready":
+		return a.prReady(ctx, rest)
+	case "pr merge":
+		return a.prMerge(ctx, rest)
+	case "pr reassign":
+		return a.prReassign(ctx, rest)
+	case "user set-active":
+		return a.userSetActive(ctx, rest)
+	case "user reviews":
+		return a.userReviews(ctx, rest)
+	}
+	return fmt.Errorf("unknown command %q", command+" "+sub)
+}
+
+func envOr(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "error: "+format+"\n", args...)
+	os.Exit(1)
+}
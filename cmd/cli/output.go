@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+type table struct {
+	w *tabwriter.Writer
+}
+
+func newTable(headers ...string) *table {
+	t := &table{w: tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)}
+	fmt.Fprintln(t.w, strings.Join(headers, "\t"))
+	return t
+}
+
+func (t *table) row(cells ...interface{}) {
+	values := make([]string, len(cells))
+	for i, c := range cells {
+		values[i] = fmt.Sprint(c)
+	}
+	fmt.Fprintln(t.w, strings.Join(values, "\t"))
+}
+
+func (t *table) flush() error {
+	return t.w.Flush()
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/client"
+)
+
+const usage = `Usage: loadtest [flags]
+
+Generates a traffic mix against the service and reports latency percentiles
+per operation. Budgets turn the run into a pass/fail check: the exit status is
+1 when any budget is exceeded.
+
+Scenarios (weights for -mix):
+  team    create a team
+  pr      create a pull request
+  burst   create -burst-size pull requests in one team at once
+  storm   reassign every reviewer of an open pull request at once
+  merge   merge an open pull request
+
+Operations (for -budget): create_team, create_pr, reassign, merge, all.
+
+Examples:
+  loadtest -duration 1m -concurrency 20 -mix pr=8,storm=2
+  loadtest -rate 200 -budget create_pr:p99=100ms -budget all:errors=1%
+
+Flags:
+`
+
+func main() {
+	fs := flag.NewFlagSet("loadtest", flag.ExitOnError)
+	baseURL := fs.String("url", envOr("PR_API_URL", "http://localhost:8080"), "service base URL (env PR_API_URL)")
+	apiKey := fs.String("api-key", os.Getenv("PR_API_KEY"), "API key sent as X-API-Key (env PR_API_KEY)")
+	duration := fs.Duration("duration", 30*time.Second, "how long to generate traffic")
+	concurrency := fs.Int("concurrency", 10, "number of concurrent workers")
+	rate := fs.Float64("rate", 0, "scenarios started per second across all workers; 0 is unlimited")
+	mixFlag := fs.String("mix", "pr=6,burst=1,storm=2,team=1", "scenario weights as name=weight,...")
+	teams := fs.Int("teams", 5, "teams created before the run for PR scenarios")
+	teamSize := fs.Int("team-size", 8, "members per created team")
+	burstSize := fs.Int("burst-size", 10, "pull requests per burst")
+	timeout := fs.Duration("timeout", 10*time.Second, "per-request timeout")
+	output := fs.String("output", "table", "output format: table or json")
+	var budgets budgetList
+	fs.Var(&budgets, "budget", "latency or error budget as OP:pNN=DURATION or OP:errors=PERCENT%, repeatable")
+	fs.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(os.Args[1:])
+
+	if *output != "table" && *output != "json" {
+		fatalf("unknown output format %q", *output)
+	}
+	if *concurrency < 1 || *teams < 1 || *teamSize < 2 || *burstSize < 1 {
+		fatalf("-concurrency, -teams and -burst-size must be positive and -team-size at least 2")
+	}
+	mix, err := parseMix(*mixFlag)
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// The default transport keeps only two idle connections per host, which
+	// would have most workers dialing a new connection for every request.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = *concurrency * *burstSize
+	c := client.New(*baseURL, *apiKey, client.WithHTTPClient(&http.Client{
+		Timeout:   *timeout,
+		Transport: transport,
+	}))
+
+	r := newRunner(c, mix, runConfig{
+		runID:     fmt.Sprintf("lt%d", time.Now().Unix()),
+		teamSize:  *teamSize,
+		burstSize: *burstSize,
+	})
+	if err := r.setup(ctx, *teams); err != nil {
+		fatalf("setup: %v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Running %s with %d workers against %s\n", *duration, *concurrency, *baseURL)
+	runCtx, cancel := context.WithTimeout(ctx, *duration)
+	defer cancel()
+	elapsed := r.run(runCtx, *concurrency, *rate)
+
+	rep := r.stats.report(elapsed)
+	results := budgets.check(rep)
+	if *output == "json" {
+		err = printJSON(struct {
+			Report  report         `json:"report"`
+			Budgets []budgetResult `json:"budgets,omitempty"`
+		}{rep, results})
+	} else {
+		err = printReport(rep, results)
+	}
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	for _, res := range results {
+		if !res.Passed {
+			os.Exit(1)
+		}
+	}
+}
+
+func envOr(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "error: "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/client"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// Operation names under which request latencies are recorded.
+const (
+	opCreateTeam = "create_team"
+	opCreatePR   = "create_pr"
+	opReassign   = "reassign"
+	opMerge      = "merge"
+)
+
+// maxOpenPRs bounds the pool of open pull requests storms and merges draw
+// from; older ones are dropped once it is full.
+const maxOpenPRs = 1000
+
+type weighted struct {
+	name   string
+	weight int
+}
+
+// parseMix parses scenario weights like "pr=6,storm=2".
+func parseMix(value string) ([]weighted, error) {
+	var mix []weighted
+	for _, part := range strings.Split(value, ",") {
+		name, w, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("mix entry must be NAME=WEIGHT, got %q", part)
+		}
+		if _, known := scenarios[name]; !known {
+			return nil, fmt.Errorf("unknown scenario %q", name)
+		}
+		weight, err := strconv.Atoi(w)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("weight of %s must be a non-negative integer, got %q", name, w)
+		}
+		if weight > 0 {
+			mix = append(mix, weighted{name: name, weight: weight})
+		}
+	}
+	if len(mix) == 0 {
+		return nil, errors.New("mix has no scenario with a positive weight")
+	}
+	return mix, nil
+}
+
+var scenarios = map[string]func(r *runner, ctx context.Context, rng *rand.Rand){
+	"team":  (*runner).createTeam,
+	"pr":    (*runner).createPR,
+	"burst": (*runner).burst,
+	"storm": (*runner).storm,
+	"merge": (*runner).merge,
+}
+
+type runConfig struct {
+	// runID prefixes every created ID so repeated runs do not collide.
+	runID     string
+	teamSize  int
+	burstSize int
+}
+
+type runner struct {
+	client *client.Client
+	mix    []weighted
+	total  int
+	cfg    runConfig
+	stats  *recorder
+
+	seq int64
+
+	mu     sync.Mutex
+	teams  []models.Team
+	openPR []*models.PullRequest
+}
+
+func newRunner(c *client.Client, mix []weighted, cfg runConfig) *runner {
+	r := &runner{client: c, mix: mix, cfg: cfg, stats: newRecorder()}
+	for _, w := range mix {
+		r.total += w.weight
+	}
+	return r
+}
+
+// setup creates the teams PR scenarios pick authors from. Its requests are
+// not part of the report.
+func (r *runner) setup(ctx context.Context, teams int) error {
+	for i := 0; i < teams; i++ {
+		if _, err := r.newTeam(ctx); err != nil {
+			return err
+		}
+	}
+	r.stats = newRecorder()
+	return nil
+}
+
+// run starts workers that pick scenarios from the mix until ctx is done and
+// returns how long they ran. A positive rate paces scenario starts across all
+// workers.
+func (r *runner) run(ctx context.Context, workers int, rate float64) time.Duration {
+	var tick <-chan time.Time
+	if rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for {
+				if tick != nil {
+					select {
+					case <-tick:
+					case <-ctx.Done():
+						return
+					}
+				}
+				if ctx.Err() != nil {
+					return
+				}
+				scenarios[r.pick(rng)](r, ctx, rng)
+			}
+		}(start.UnixNano() + int64(i))
+	}
+	wg.Wait()
+	return time.Since(start)
+}
+
+func (r *runner) pick(rng *rand.Rand) string {
+	n := rng.Intn(r.total)
+	for _, w := range r.mix {
+		if n < w.weight {
+			return w.name
+		}
+		n -= w.weight
+	}
+	return r.mix[len(r.mix)-1].name
+}
+
+func (r *runner) nextID(kind string) string {
+	return fmt.Sprintf("%s-%s-%d", r.cfg.runID, kind, atomic.AddInt64(&r.seq, 1))
+}
+
+func (r *runner) newTeam(ctx context.Context) (*models.Team, error) {
+	team := models.Team{TeamName: r.nextID("team")}
+	for i := 0; i < r.cfg.teamSize; i++ {
+		id := fmt.Sprintf("%s-u%d", team.TeamName, i)
+		team.Members = append(team.Members, models.TeamMember{UserID: id, Username: id, IsActive: true})
+	}
+
+	var created *models.Team
+	err := r.stats.time(ctx, opCreateTeam, func() (err error) {
+		created, err = r.client.CreateTeam(ctx, team)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.teams = append(r.teams, *created)
+	r.mu.Unlock()
+	return created, nil
+}
+
+func (r *runner) createTeam(ctx context.Context, _ *rand.Rand) {
+	_, _ = r.newTeam(ctx)
+}
+
+func (r *runner) createPR(ctx context.Context, rng *rand.Rand) {
+	r.newPR(ctx, r.randomTeam(rng), rng)
+}
+
+func (r *runner) newPR(ctx context.Context, team models.Team, rng *rand.Rand) {
+	author := team.Members[rng.Intn(len(team.Members))]
+	req := dto.CreatePullRequestRequest{
+		PullRequestID:   r.nextID("pr"),
+		PullRequestName: "Load test change",
+		AuthorID:        author.UserID,
+		LinesChanged:    rng.Intn(500),
+	}
+
+	var pr *models.PullRequest
+	err := r.stats.time(ctx, opCreatePR, func() (err error) {
+		pr, err = r.client.CreatePullRequest(ctx, req)
+		return err
+	})
+	if err == nil && len(pr.AssignedReviewers) > 0 {
+		r.pushOpen(pr)
+	}
+}
+
+// burst creates burstSize pull requests in one team concurrently, so they
+// all contend for its assignment lock.
+func (r *runner) burst(ctx context.Context, rng *rand.Rand) {
+	team := r.randomTeam(rng)
+	var wg sync.WaitGroup
+	for i := 0; i < r.cfg.burstSize; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r.newPR(ctx, team, rand.New(rand.NewSource(seed)))
+		}(rng.Int63())
+	}
+	wg.Wait()
+}
+
+// storm reassigns every reviewer of an open pull request concurrently.
+func (r *runner) storm(ctx context.Context, rng *rand.Rand) {
+	pr := r.popOpen(rng)
+	if pr == nil {
+		r.createPR(ctx, rng)
+		return
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	latest := pr
+	for _, reviewer := range pr.AssignedReviewers {
+		wg.Add(1)
+		go func(reviewer string) {
+			defer wg.Done()
+			var resp *dto.ReassignResponse
+			err := r.stats.time(ctx, opReassign, func() (err error) {
+				resp, err = r.client.ReassignReviewer(ctx, pr.PullRequestID, reviewer)
+				return err
+			})
+			if err == nil {
+				mu.Lock()
+				latest = &resp.PR
+				mu.Unlock()
+			}
+		}(reviewer)
+	}
+	wg.Wait()
+	r.pushOpen(latest)
+}
+
+func (r *runner) merge(ctx context.Context, rng *rand.Rand) {
+	pr := r.popOpen(rng)
+	if pr == nil {
+		r.createPR(ctx, rng)
+		return
+	}
+	_ = r.stats.time(ctx, opMerge, func() error {
+		_, err := r.client.MergePullRequest(ctx, pr.PullRequestID)
+		return err
+	})
+}
+
+func (r *runner) randomTeam(rng *rand.Rand) models.Team {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.teams[rng.Intn(len(r.teams))]
+}
+
+func (r *runner) pushOpen(pr *models.PullRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.openPR) == maxOpenPRs {
+		r.openPR = r.openPR[1:]
+	}
+	r.openPR = append(r.openPR, pr)
+}
+
+// popOpen takes a random open pull request out of the pool, so concurrent
+// storms and merges do not work on the same one.
+func (r *runner) popOpen(rng *rand.Rand) *models.PullRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.openPR) == 0 {
+		return nil
+	}
+	i := rng.Intn(len(r.openPR))
+	pr := r.openPR[i]
+	r.openPR[i] = r.openPR[len(r.openPR)-1]
+	r.openPR = r.openPR[:len(r.openPR)-1]
+	return pr
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
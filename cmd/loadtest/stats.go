@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/client"
+)
+
+// opAll aggregates every operation in reports and budgets.
+const opAll = "all"
+
+var percentiles = []int{50, 90, 95, 99}
+
+type sample struct {
+	latencies []time.Duration
+	// errors counts failed requests by HTTP status, or by "transport" when
+	// no response arrived.
+	errors map[string]int
+}
+
+type recorder struct {
+	mu  sync.Mutex
+	ops map[string]*sample
+}
+
+func newRecorder() *recorder {
+	return &recorder{ops: make(map[string]*sample)}
+}
+
+// time runs fn and records its latency and outcome under op. Requests cut
+// short by the end of the run are not recorded.
+func (rec *recorder) time(ctx context.Context, op string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	latency := time.Since(start)
+	if ctx.Err() != nil {
+		return err
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	s, ok := rec.ops[op]
+	if !ok {
+		s = &sample{errors: make(map[string]int)}
+		rec.ops[op] = s
+	}
+	s.latencies = append(s.latencies, latency)
+	if err != nil {
+		s.errors[errorKind(err)]++
+	}
+	return err
+}
+
+func errorKind(err error) string {
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		return strconv.Itoa(apiErr.StatusCode)
+	}
+	return "transport"
+}
+
+type opReport struct {
+	Operation   string                   `json:"operation"`
+	Requests    int                      `json:"requests"`
+	Errors      int                      `json:"errors"`
+	ErrorsBy    map[string]int           `json:"errors_by,omitempty"`
+	RPS         float64                  `json:"rps"`
+	Percentiles map[string]time.Duration `json:"-"`
+	Max         time.Duration            `json:"-"`
+
+	sorted []time.Duration
+}
+
+// MarshalJSON reports latencies in milliseconds.
+func (o opReport) MarshalJSON() ([]byte, error) {
+	type plain opReport
+	ms := make(map[string]float64, len(o.Percentiles))
+	for p, d := range o.Percentiles {
+		ms[p] = millis(d)
+	}
+	return json.Marshal(struct {
+		plain
+		PercentilesMS map[string]float64 `json:"percentiles_ms"`
+		MaxMS         float64            `json:"max_ms"`
+	}{plain(o), ms, millis(o.Max)})
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func (o opReport) errorRate() float64 {
+	if o.Requests == 0 {
+		return 0
+	}
+	return float64(o.Errors) / float64(o.Requests)
+}
+
+type report struct {
+	Duration        time.Duration `json:"-"`
+	DurationSeconds float64       `json:"duration_seconds"`
+	Operations      []opReport    `json:"operations"`
+}
+
+func (r report) operation(name string) (opReport, bool) {
+	for _, o := range r.Operations {
+		if o.Operation == name {
+			return o, true
+		}
+	}
+	return opReport{}, false
+}
+
+// report summarizes the recorded requests, one entry per operation followed
+// by their aggregate.
+func (rec *recorder) report(elapsed time.Duration) report {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	all := &sample{errors: make(map[string]int)}
+	rep := report{Duration: elapsed, DurationSeconds: elapsed.Seconds()}
+	for _, op := range sortedKeys(rec.ops) {
+		s := rec.ops[op]
+		all.latencies = append(all.latencies, s.latencies...)
+		for kind, n := range s.errors {
+			all.errors[kind] += n
+		}
+		rep.Operations = append(rep.Operations, summarize(op, s, elapsed))
+	}
+	rep.Operations = append(rep.Operations, summarize(opAll, all, elapsed))
+	return rep
+}
+
+func summarize(op string, s *sample, elapsed time.Duration) opReport {
+	sorted := append([]time.Duration(nil), s.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	o := opReport{
+		Operation:   op,
+		Requests:    len(sorted),
+		ErrorsBy:    s.errors,
+		Percentiles: make(map[string]time.Duration, len(percentiles)),
+		sorted:      sorted,
+	}
+	for _, n := range s.errors {
+		o.Errors += n
+	}
+	if elapsed > 0 {
+		o.RPS = float64(o.Requests) / elapsed.Seconds()
+	}
+	for _, p := range percentiles {
+		o.Percentiles["p"+strconv.Itoa(p)] = percentile(sorted, p)
+	}
+	if len(sorted) > 0 {
+		o.Max = sorted[len(sorted)-1]
+	}
+	return o
+}
+
+// percentile returns the nearest-rank percentile p of sorted latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// budget is a limit on one operation: either a latency percentile or, when
+// percentile is zero, its error rate.
+type budget struct {
+	op         string
+	percentile int
+	latency    time.Duration
+	errorRate  float64
+}
+
+func (b budget) String() string {
+	if b.percentile == 0 {
+		return fmt.Sprintf("%s:errors=%g%%", b.op, b.errorRate*100)
+	}
+	return fmt.Sprintf("%s:p%d=%s", b.op, b.percentile, b.latency)
+}
+
+type budgetList []budget
+
+func (l *budgetList) String() string {
+	parts := make([]string, len(*l))
+	for i, b := range *l {
+		parts[i] = b.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set parses OP:pNN=DURATION or OP:errors=PERCENT%.
+func (l *budgetList) Set(value string) error {
+	op, limit, ok := strings.Cut(value, ":")
+	if !ok || op == "" {
+		return fmt.Errorf("budget must be OP:pNN=DURATION or OP:errors=PERCENT%%, got %q", value)
+	}
+	metric, threshold, ok := strings.Cut(limit, "=")
+	if !ok {
+		return fmt.Errorf("budget %q has no threshold", value)
+	}
+
+	b := budget{op: op}
+	if metric == "errors" {
+		pct, err := strconv.ParseFloat(strings.TrimSuffix(threshold, "%"), 64)
+		if err != nil || pct < 0 || pct > 100 {
+			return fmt.Errorf("error budget must be a percentage, got %q", threshold)
+		}
+		b.errorRate = pct / 100
+	} else {
+		p, err := strconv.Atoi(strings.TrimPrefix(metric, "p"))
+		if err != nil || !strings.HasPrefix(metric, "p") || p < 1 || p > 100 {
+			return fmt.Errorf("budget metric must be pNN or errors, got %q", metric)
+		}
+		latency, err := time.ParseDuration(threshold)
+		if err != nil {
+			return fmt.Errorf("latency budget: %w", err)
+		}
+		b.percentile, b.latency = p, latency
+	}
+	*l = append(*l, b)
+	return nil
+}
+
+type budgetResult struct {
+	Budget string `json:"budget"`
+	Actual string `json:"actual"`
+	Passed bool   `json:"passed"`
+}
+
+// check evaluates every budget against rep. A budget on an operation that
+// never ran fails, so a misspelled name cannot pass a gate silently.
+func (l budgetList) check(rep report) []budgetResult {
+	results := make([]budgetResult, 0, len(l))
+	for _, b := range l {
+		res := budgetResult{Budget: b.String(), Actual: "no requests"}
+		o, ok := rep.operation(b.op)
+		if ok && o.Requests > 0 {
+			if b.percentile == 0 {
+				res.Actual = fmt.Sprintf("%.2f%%", o.errorRate()*100)
+				res.Passed = o.errorRate() <= b.errorRate
+			} else {
+				latency := percentile(o.sorted, b.percentile)
+				res.Actual = round(latency).String()
+				res.Passed = latency <= b.latency
+			}
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+func printReport(rep report, results []budgetResult) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Duration: %s\n\n", rep.Duration.Round(time.Millisecond))
+	fmt.Fprintln(w, "OPERATION\tREQUESTS\tERRORS\tRPS\tP50\tP90\tP95\tP99\tMAX")
+	for _, o := range rep.Operations {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f", o.Operation, o.Requests, o.Errors, o.RPS)
+		for _, p := range percentiles {
+			fmt.Fprintf(w, "\t%s", round(o.Percentiles["p"+strconv.Itoa(p)]))
+		}
+		fmt.Fprintf(w, "\t%s\n", round(o.Max))
+	}
+	for _, o := range rep.Operations {
+		if o.Errors > 0 && o.Operation != opAll {
+			fmt.Fprintf(w, "\n%s errors:", o.Operation)
+			for _, kind := range sortedKeys(o.ErrorsBy) {
+				fmt.Fprintf(w, " %s=%d", kind, o.ErrorsBy[kind])
+			}
+		}
+	}
+	fmt.Fprintln(w)
+
+	if len(results) > 0 {
+		fmt.Fprintln(w, "\nBUDGET\tACTUAL\tRESULT")
+		for _, res := range results {
+			result := "PASS"
+			if !res.Passed {
+				result = "FAIL"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", res.Budget, res.Actual, result)
+		}
+	}
+	return w.Flush()
+}
+
+func round(d time.Duration) time.Duration {
+	if d > time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(10 * time.Microsecond)
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,149 @@
+// Package auth identifies the calling user from a JWT bearer token.
+package auth
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Config describes which tokens are accepted. Tokens are verified with the
+// HMAC Secret (HS256/384/512) or, when PublicKeyFile is set, with the RSA or
+// ECDSA public key in that PEM file.
+type Config struct {
+	Secret        string
+	PublicKeyFile string
+	Issuer        string
+	Audience      string
+	UserClaim     string
+}
+
+type contextKey struct{}
+
+// Authenticator validates bearer tokens and stores the user they identify in
+// the request context.
+type Authenticator struct {
+	parser    *jwt.Parser
+	key       interface{}
+	userClaim string
+}
+
+func NewAuthenticator(cfg Config) (*Authenticator, error) {
+	a := &Authenticator{userClaim: cfg.UserClaim}
+	if a.userClaim == "" {
+		a.userClaim = "sub"
+	}
+
+	var methods []string
+	if cfg.PublicKeyFile != "" {
+		key, err := loadPublicKey(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		a.key = key
+		methods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+	} else {
+		a.key = []byte(cfg.Secret)
+		methods = []string{"HS256", "HS384", "HS512"}
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	a.parser = jwt.NewParser(opts...)
+
+	return a, nil
+}
+
+// Authenticate returns the user_id carried by the token.
+func (a *Authenticator) Authenticate(token string) (string, error) {
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return a.key, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	userID, _ := claims[a.userClaim].(string)
+	if userID == "" {
+		return "", fmt.Errorf("%w: missing %s claim", ErrInvalidToken, a.userClaim)
+	}
+	return userID, nil
+}
+
+// Middleware authenticates requests that carry a bearer token. Requests
+// without one pass through anonymously; requests with an invalid token are
+// rejected by onError.
+func (a *Authenticator) Middleware(onError func(w http.ResponseWriter, err error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userID, err := a.Authenticate(token)
+			if err != nil {
+				onError(w, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithUserID(r.Context(), userID)))
+		})
+	}
+}
+
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, userID)
+}
+
+// UserID returns the authenticated user of the request, if any.
+func UserID(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(contextKey{}).(string)
+	return userID, ok && userID != ""
+}
+
+// bearerToken reads the Authorization header. WebSocket upgrades may pass
+// the token as the access_token query parameter instead, since browsers
+// cannot set headers on them.
+func bearerToken(r *http.Request) (string, bool) {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") && r.Header.Get("Authorization") == "" {
+		token := r.URL.Query().Get("access_token")
+		return token, token != ""
+	}
+
+	header := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func loadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read JWT public key: %w", err)
+	}
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	key, err := jwt.ParseECPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse JWT public key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,84 @@
+package bootstrap
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
+)
+
+// addAdminServer registers the admin server, which serves pprof profiles at
+// /debug/pprof/, expvar at /debug/vars and the sanitized configuration at
+// /debug/config. It listens on a port of its own, never on the public one.
+func (a *App) addAdminServer() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/config", a.debugConfig)
+
+	srv := &http.Server{
+		Addr:        ":" + a.cfg.Admin.Port,
+		Handler:     a.adminAuth(mux),
+		ReadTimeout: 10 * time.Second,
+		// No write timeout: CPU profiles and traces take as long as the
+		// seconds they are asked for.
+		IdleTimeout: 60 * time.Second,
+	}
+	a.lc.Append(Hook{
+		Name: "admin",
+		Start: func(context.Context) error {
+			ln, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			a.logger.Printf("Starting admin server on port %s", a.cfg.Admin.Port)
+			go func() {
+				if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					a.errc <- fmt.Errorf("admin server: %w", err)
+				}
+			}()
+			return nil
+		},
+		Stop: srv.Shutdown,
+	})
+}
+
+// adminAuth lets through only requests bearing the admin token, when one is
+// configured.
+func (a *App) adminAuth(next http.Handler) http.Handler {
+	token := []byte(a.cfg.Admin.Token)
+	if len(token) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), token) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// debugConfig shows the configuration the server runs with, secrets
+// replaced.
+func (a *App) debugConfig(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(a.cfg.Sanitized()); err != nil {
+		a.logger.Printf("Cannot write config: %v", err)
+	}
+}
@@ -0,0 +1,474 @@
+// Package bootstrap wires the server together: it builds every component
+// from the configuration in dependency order and starts and stops them
+// through a Lifecycle.
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Thorlik/avito_internship/internal/app/auth"
+	"github.com/Thorlik/avito_internship/internal/app/config"
+	"github.com/Thorlik/avito_internship/internal/app/handlers"
+	"github.com/Thorlik/avito_internship/internal/app/reqlog"
+	"github.com/Thorlik/avito_internship/internal/app/router"
+	"github.com/Thorlik/avito_internship/internal/app/scheduler"
+	"github.com/Thorlik/avito_internship/internal/app/webhook"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+	"github.com/Thorlik/avito_internship/internal/domain/service"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/cache"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/directory"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/events"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/github"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/jira"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/lock"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/notify"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/persistence"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/sentry"
+)
+
+// shutdownTimeout bounds the whole ordered shutdown.
+const shutdownTimeout = 10 * time.Second
+
+type App struct {
+	cfg    *config.Config
+	logger *log.Logger
+	lc     Lifecycle
+	// redis is the client of the Redis cache, nil when there is none.
+	redis redis.UniversalClient
+
+	// errc receives the error of a component that fails after starting.
+	errc chan error
+}
+
+// New builds the application: storage, caches and locks, service, handlers,
+// background jobs and the HTTP server. On failure everything opened so far
+// is closed again.
+func New(cfg *config.Config, logger *log.Logger) (_ *App, err error) {
+	a := &App{cfg: cfg, logger: logger, errc: make(chan error, 1)}
+	defer func() {
+		if err != nil {
+			if stopErr := a.lc.Stop(context.Background()); stopErr != nil {
+				logger.Printf("Cleanup after failed start: %v", stopErr)
+			}
+		}
+	}()
+
+	store, err := a.openStorage()
+	if err != nil {
+		return nil, err
+	}
+	storage, locker := a.newCaches(store)
+	bus := events.NewBus()
+	notifier := notify.NewWebhookNotifier(cfg.Notify.WebhookURL, models.NotificationProvider(cfg.Notify.Provider), cfg.Notify.WebhookTimeout)
+
+	opts := []service.Option{
+		service.WithLocker(locker),
+		service.WithEventPublisher(events.NewLog(storage, bus)),
+		service.WithNotifier(notifier),
+		service.WithWebhookDeliveries(notifier, cfg.Notify.WebhookAttempts),
+		service.WithRequiredApprovals(cfg.Review.RequiredApprovals),
+		service.WithSizeBuckets(sizeBuckets(cfg.Review.SizeBuckets)),
+		service.WithUndoWindow(cfg.Review.UndoWindow),
+		service.WithRequireRepository(cfg.Review.RequireRepository),
+		service.WithStaleAutoReassign(cfg.Stale.AutoReassign),
+		service.WithDefaultLocale(models.Locale(cfg.API.DefaultLocale)),
+		service.WithStatisticsMaxAge(2 * cfg.Statistics.RefreshInterval),
+		service.WithAnomalyThresholds(service.AnomalyThresholds{
+			Window:             cfg.Anomaly.Window,
+			MaxAssignmentShare: float64(cfg.Anomaly.AssignmentSharePercent) / 100,
+			MinAssignments:     cfg.Anomaly.MinAssignments,
+			MaxMergeDrop:       float64(cfg.Anomaly.MergeDropPercent) / 100,
+			MinWeeklyMerges:    cfg.Anomaly.MinWeeklyMerges,
+			Notify:             cfg.Anomaly.Notify,
+		}),
+		service.WithFeatureFlags(featureFlags(cfg.Features.Flags)),
+	}
+	if cfg.Directory.Enabled() {
+		source, err := directorySource(cfg.Directory)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, service.WithDirectory(source, models.DirectoryConflictPolicy(cfg.Directory.ConflictPolicy)))
+	}
+	if cfg.Jira.Enabled() {
+		tracker := jira.New(jira.Config{
+			URL:               cfg.Jira.URL,
+			User:              cfg.Jira.User,
+			Token:             cfg.Jira.Token,
+			Projects:          cfg.Jira.Projects,
+			DefaultProject:    cfg.Jira.DefaultProject,
+			IssueType:         cfg.Jira.IssueType,
+			SubtaskType:       cfg.Jira.SubtaskType,
+			ApproveTransition: cfg.Jira.ApproveTransition,
+			MergeTransition:   cfg.Jira.MergeTransition,
+			AssigneeField:     cfg.Jira.AssigneeField,
+			Timeout:           cfg.Jira.Timeout,
+		})
+		opts = append(opts, service.WithIssueTracker(tracker, cfg.Outbox.MaxAttempts))
+	}
+	if cfg.GitHub.Enabled() {
+		requester := github.New(github.Config{
+			URL:          cfg.GitHub.URL,
+			Token:        cfg.GitHub.Token,
+			Repositories: cfg.GitHub.Repositories,
+			Timeout:      cfg.GitHub.Timeout,
+		})
+		opts = append(opts, service.WithReviewRequests(requester, cfg.Outbox.MaxAttempts))
+	}
+	if cfg.Database.SchemaCheck {
+		opts = append(opts, service.WithSchemaVersion(persistence.SchemaVersion))
+	}
+	svc := service.NewService(storage, opts...)
+	if err := svc.CheckSchema(context.Background()); err != nil {
+		return nil, err
+	}
+	handler := handlers.NewHandler(svc)
+	if cfg.Seed.File != "" {
+		a.addSeed(svc)
+	}
+
+	jobs := newScheduler(cfg, svc, store, logger)
+	a.lc.Append(Hook{
+		Name: "scheduler",
+		Start: func(context.Context) error {
+			jobs.Start()
+			return nil
+		},
+		Stop: jobs.Stop,
+	})
+
+	root, err := a.newRouter(handler, jobs, bus, store.QueryStats)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.addServer(root); err != nil {
+		return nil, err
+	}
+	if cfg.Admin.Enabled() {
+		a.addAdminServer()
+	}
+	// Stopped before the server, so open event streams end and do not hold
+	// up its graceful shutdown.
+	a.lc.Append(Hook{
+		Name: "events",
+		Stop: func(context.Context) error {
+			bus.Close()
+			return nil
+		},
+	})
+	a.addReloader(svc)
+
+	return a, nil
+}
+
+// Run starts every component and blocks until ctx is done or a component
+// fails, then shuts everything down in reverse order.
+func (a *App) Run(ctx context.Context) error {
+	if err := a.lc.Start(ctx); err != nil {
+		return err
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		a.logger.Println("Shutting down server...")
+	case runErr = <-a.errc:
+		a.logger.Printf("Shutting down after failure: %v", runErr)
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return errors.Join(runErr, a.lc.Stop(stopCtx))
+}
+
+func (a *App) openStorage() (*persistence.PostgresStorage, error) {
+	db := a.cfg.Database
+	opts := persistence.Options{
+		MaxOpenConns:       db.MaxOpenConns,
+		MinConns:           db.MinConns,
+		ConnMaxLifetime:    db.ConnMaxLifetime,
+		StatementTimeout:   db.StatementTimeout,
+		QueryTimeout:       db.QueryTimeout,
+		BackupTimeout:      db.BackupTimeout,
+		ReplicaDSN:         db.ReplicaDSN,
+		SlowQueryThreshold: db.SlowQueryThreshold,
+	}
+
+	var store *persistence.PostgresStorage
+	var err error
+	for attempt := 1; attempt <= db.ConnectRetries; attempt++ {
+		store, err = persistence.NewPostgresStorage(a.cfg.GetDSN(), opts)
+		if err == nil {
+			break
+		}
+		a.logger.Printf("Failed to connect to database (attempt %d/%d): %v", attempt, db.ConnectRetries, err)
+		if attempt < db.ConnectRetries {
+			time.Sleep(db.ConnectDelay(attempt))
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("connect to database after retries: %w", err)
+	}
+
+	a.lc.Append(Hook{
+		Name: "postgres",
+		Stop: func(context.Context) error { return store.Close() },
+	})
+	return store, nil
+}
+
+// newCaches layers the Redis and in-process caches over store and picks the
+// team locker: distributed when Redis is configured, local otherwise.
+func (a *App) newCaches(store *persistence.PostgresStorage) (repository.Storage, repository.Locker) {
+	var storage repository.Storage = store
+	var locker repository.Locker = lock.NewLocalLocker()
+
+	if rc := a.cfg.Redis; rc.Enabled() {
+		rdb := redis.NewClient(&redis.Options{
+			Addr:     rc.Addr,
+			Password: rc.Password,
+			DB:       rc.DB,
+		})
+		a.lc.Append(Hook{
+			Name: "redis",
+			Stop: func(context.Context) error { return rdb.Close() },
+		})
+
+		if err := rdb.Ping(context.Background()).Err(); err != nil {
+			a.logger.Printf("Redis is unavailable, continuing with fallbacks: %v", err)
+		}
+		a.redis = rdb
+		storage = cache.NewRedisStorage(storage, rdb, rc.CacheTTL)
+		locker = lock.NewRedisLocker(rdb, rc.LockTTL, locker)
+	}
+	if a.cfg.Cache.TTL > 0 {
+		storage = cache.NewCachedStorage(storage, a.cfg.Cache.TTL)
+	}
+	return storage, locker
+}
+
+func (a *App) newRouter(handler *handlers.Handler, jobs *scheduler.Scheduler, bus *events.Bus, queries func() []persistence.QueryStats) (http.Handler, error) {
+	opts := router.Options{
+		Events:   bus,
+		Jobs:     jobs.Stats,
+		Leader:   jobs.Leader,
+		Queries:  queries,
+		V1Sunset: a.cfg.API.V1Sunset,
+		Compress: router.CompressOptions(a.cfg.Compress),
+		StatisticsCache: router.CacheOptions{
+			TTL:                  a.cfg.Statistics.CacheTTL,
+			StaleWhileRevalidate: a.cfg.Statistics.CacheStaleWhileRevalidate,
+		},
+		DefaultLocale: models.Locale(a.cfg.API.DefaultLocale),
+		Maintenance:   true,
+	}
+	if a.cfg.JWT.Enabled() {
+		authn, err := auth.NewAuthenticator(auth.Config(a.cfg.JWT))
+		if err != nil {
+			return nil, fmt.Errorf("configure JWT authentication: %w", err)
+		}
+		opts.Auth = authn.Middleware(handler.Unauthorized)
+	}
+	if a.cfg.Sentry.Enabled() {
+		reporter, err := sentry.New(sentry.Config{
+			DSN:         a.cfg.Sentry.DSN,
+			Environment: string(a.cfg.Env),
+			ServerName:  instanceID(a.cfg),
+			Timeout:     a.cfg.Sentry.Timeout,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("configure Sentry: %w", err)
+		}
+		opts.Panics = reporter
+	}
+	if a.cfg.Webhooks.Enabled() {
+		opts.Webhooks = a.newWebhookGuard()
+	}
+	if c := a.cfg.Capture; c.Enabled() {
+		rec := reqlog.New(reqlog.Config(c), a.logger)
+		opts.Capture = rec.Middleware
+		if c.BufferSize > 0 {
+			opts.Requests = rec.Entries
+		}
+	}
+	return a.logRequests(router.New(handler, opts)), nil
+}
+
+// newWebhookGuard verifies the webhooks of the Git hosts that have a
+// secret. Delivery IDs are shared through Redis when it is configured.
+func (a *App) newWebhookGuard() *webhook.Guard {
+	cfg := a.cfg.Webhooks
+	var nonces webhook.NonceStore = webhook.NewMemoryNonces()
+	if a.redis != nil {
+		nonces = webhook.NewRedisNonces(a.redis, nonces)
+	}
+
+	var sources []webhook.Source
+	if cfg.GitHubSecret != "" {
+		sources = append(sources, webhook.GitHub(cfg.GitHubSecret))
+	}
+	if cfg.GitLabToken != "" {
+		sources = append(sources, webhook.GitLab(cfg.GitLabToken))
+	}
+	if cfg.BitbucketSecret != "" {
+		sources = append(sources, webhook.Bitbucket(cfg.BitbucketSecret))
+	}
+	if cfg.GerritToken != "" {
+		sources = append(sources, webhook.Gerrit(cfg.GerritToken))
+	}
+	if cfg.BotToken != "" {
+		sources = append(sources, webhook.Bot(cfg.BotToken))
+	}
+	return webhook.NewGuard(nonces, cfg.ReplayWindow, sources...)
+}
+
+// addServer registers the HTTP server, serving HTTPS when a certificate is
+// configured. It listens when started, so a port that is already taken fails
+// Run right away.
+func (a *App) addServer(root http.Handler) error {
+	srv := &http.Server{
+		Addr:         ":" + a.cfg.Server.Port,
+		Handler:      root,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	serve, scheme := srv.Serve, "HTTP"
+	if a.cfg.Server.TLSEnabled() {
+		certs, err := newCertReloader(a.cfg.Server, a.logger)
+		if err != nil {
+			return err
+		}
+		srv.TLSConfig = certs.tlsConfig()
+		serve = func(ln net.Listener) error {
+			return srv.ServeTLS(ln, "", "")
+		}
+		scheme = "HTTPS"
+		if a.cfg.Server.TLSClientCAFile != "" {
+			scheme = "HTTPS with client certificates"
+		}
+	}
+
+	a.lc.Append(Hook{
+		Name: "http",
+		Start: func(context.Context) error {
+			ln, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			a.logger.Printf("Starting %s server on port %s", scheme, a.cfg.Server.Port)
+			go func() {
+				if err := serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					a.errc <- fmt.Errorf("http server: %w", err)
+				}
+			}()
+			return nil
+		},
+		Stop: srv.Shutdown,
+	})
+	return nil
+}
+
+// addReloader re-reads the configuration on SIGHUP and applies its runtime
+// settings. A configuration that fails to load leaves the current one in
+// place.
+func (a *App) addReloader(svc *service.Service) {
+	sighup := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	a.lc.Append(Hook{
+		Name: "reload",
+		Start: func(context.Context) error {
+			signal.Notify(sighup, syscall.SIGHUP)
+			go func() {
+				for {
+					select {
+					case <-sighup:
+						a.reload(svc)
+					case <-done:
+						return
+					}
+				}
+			}()
+			return nil
+		},
+		Stop: func(context.Context) error {
+			signal.Stop(sighup)
+			close(done)
+			return nil
+		},
+	})
+}
+
+func (a *App) reload(svc *service.Service) {
+	cfg, err := a.cfg.Reload()
+	if err != nil {
+		a.logger.Printf("Config reload failed, keeping current settings: %v", err)
+		return
+	}
+
+	rt := cfg.Runtime()
+	svc.SetRequiredApprovals(rt.RequiredApprovals)
+	svc.SetStaleAutoReassign(rt.StaleAutoReassign)
+	a.logger.Printf("Config reloaded: %+v", rt)
+}
+
+func (a *App) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		a.logger.Printf("%s %s %s", r.Method, r.RequestURI, time.Since(start))
+	})
+}
+
+func featureFlags(flags []config.FeatureFlag) []models.FeatureFlag {
+	result := make([]models.FeatureFlag, len(flags))
+	for i, f := range flags {
+		result[i] = models.FeatureFlag{Name: f.Name, Enabled: f.Enabled, Percentage: f.Percentage}
+	}
+	return result
+}
+
+func directorySource(cfg config.DirectoryConfig) (repository.DirectorySource, error) {
+	if cfg.Source == "scim" {
+		return directory.NewSCIMSource(directory.SCIMConfig{
+			URL:         cfg.SCIMURL,
+			Token:       cfg.SCIMToken,
+			GroupPrefix: cfg.GroupPrefix,
+			Timeout:     cfg.Timeout,
+		}), nil
+	}
+	return directory.NewLDAPSource(directory.LDAPConfig{
+		URL:          cfg.LDAPURL,
+		BindDN:       cfg.LDAPBindDN,
+		BindPassword: cfg.LDAPBindPassword,
+		BaseDN:       cfg.LDAPBaseDN,
+		UserFilter:   cfg.LDAPUserFilter,
+		UserIDAttr:   cfg.LDAPUserIDAttr,
+		NameAttr:     cfg.LDAPNameAttr,
+		GroupAttr:    cfg.LDAPGroupAttr,
+		GroupPrefix:  cfg.GroupPrefix,
+		Timeout:      cfg.Timeout,
+	})
+}
+
+func sizeBuckets(buckets []config.SizeBucket) []service.SizeBucket {
+	result := make([]service.SizeBucket, len(buckets))
+	for i, b := range buckets {
+		result[i] = service.SizeBucket{MaxLines: b.MaxLines, Weight: b.Weight}
+	}
+	return result
+}
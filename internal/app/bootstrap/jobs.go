@@ -0,0 +1,168 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/app/config"
+	"github.com/Thorlik/avito_internship/internal/app/scheduler"
+	"github.com/Thorlik/avito_internship/internal/domain/service"
+)
+
+// schedulerLease names the lease whose holder runs the background jobs.
+const schedulerLease = "scheduler"
+
+// newScheduler registers the periodic jobs enabled by cfg. A job whose
+// interval is zero is not scheduled. With leader election the jobs run only
+// on the instance holding the scheduler lease in leases. Every job writes,
+// so none runs while the service is in maintenance, whether switched on or
+// because the schema is newer than this instance.
+func newScheduler(cfg *config.Config, svc *service.Service, leases scheduler.Leases, logger *log.Logger) *scheduler.Scheduler {
+	opts := []scheduler.Option{scheduler.WithPause(func(ctx context.Context) bool {
+		return svc.CurrentMaintenance(ctx).Enabled
+	})}
+	if cfg.Scheduler.LeaderElection {
+		opts = append(opts, scheduler.WithLeaderElection(leases, schedulerLease, instanceID(cfg), cfg.Scheduler.LeaseTTL))
+	}
+	s := scheduler.New(opts...)
+
+	var archiveInterval time.Duration
+	if cfg.Archive.AfterDays > 0 {
+		archiveInterval = cfg.Archive.Interval
+	}
+	olderThan := time.Duration(cfg.Archive.AfterDays) * 24 * time.Hour
+	s.Add("archive_merged_prs", archiveInterval, func(ctx context.Context) error {
+		n, err := svc.ArchiveMergedPullRequests(ctx, olderThan)
+		if n > 0 {
+			logger.Printf("Archived %d merged PRs", n)
+		}
+		return err
+	})
+
+	// Teams may override the threshold, but the job only runs when a global
+	// threshold is configured.
+	var staleInterval time.Duration
+	if cfg.Stale.Threshold > 0 {
+		staleInterval = cfg.Stale.CheckInterval
+	}
+	s.Add("detect_stale_prs", staleInterval, func(ctx context.Context) error {
+		n, err := svc.DetectStalePullRequests(ctx, cfg.Stale.Threshold)
+		if n > 0 {
+			logger.Printf("Flagged %d stale PRs", n)
+		}
+		return err
+	})
+
+	s.Add("escalate_idle_reviews", cfg.Stale.EscalationInterval, func(ctx context.Context) error {
+		n, err := svc.EscalateIdleReviews(ctx)
+		if n > 0 {
+			logger.Printf("Took %d review escalation steps", n)
+		}
+		return err
+	})
+
+	var eraseInterval, purgeInterval, purgeDeletedInterval time.Duration
+	if cfg.Retention.EraseInactiveAfterDays > 0 {
+		eraseInterval = cfg.Retention.Interval
+	}
+	if cfg.Retention.PurgeArchivedAfterDays > 0 {
+		purgeInterval = cfg.Retention.Interval
+	}
+	if cfg.Retention.PurgeDeletedAfterDays > 0 {
+		purgeDeletedInterval = cfg.Retention.Interval
+	}
+	inactiveFor := time.Duration(cfg.Retention.EraseInactiveAfterDays) * 24 * time.Hour
+	s.Add("erase_inactive_users", eraseInterval, func(ctx context.Context) error {
+		n, err := svc.EraseInactiveUsers(ctx, inactiveFor)
+		if n > 0 {
+			logger.Printf("Erased %d inactive users", n)
+		}
+		return err
+	})
+	archivedFor := time.Duration(cfg.Retention.PurgeArchivedAfterDays) * 24 * time.Hour
+	s.Add("purge_archived_prs", purgeInterval, func(ctx context.Context) error {
+		n, err := svc.PurgeArchivedPullRequests(ctx, archivedFor)
+		if n > 0 {
+			logger.Printf("Purged %d archived PRs", n)
+		}
+		return err
+	})
+	deletedFor := time.Duration(cfg.Retention.PurgeDeletedAfterDays) * 24 * time.Hour
+	s.Add("purge_deleted", purgeDeletedInterval, func(ctx context.Context) error {
+		users, teams, err := svc.PurgeDeleted(ctx, deletedFor)
+		if users > 0 || teams > 0 {
+			logger.Printf("Purged %d deleted users and %d deleted teams", users, teams)
+		}
+		return err
+	})
+
+	s.Add("refresh_statistics", cfg.Statistics.RefreshInterval, func(ctx context.Context) error {
+		_, err := svc.RefreshStatistics(ctx)
+		return err
+	})
+
+	s.Add("detect_anomalies", cfg.Anomaly.Interval, func(ctx context.Context) error {
+		n, err := svc.DetectAnomalies(ctx)
+		if n > 0 {
+			logger.Printf("Found %d new anomalies", n)
+		}
+		return err
+	})
+
+	var directoryInterval time.Duration
+	if cfg.Directory.Enabled() {
+		directoryInterval = cfg.Directory.SyncInterval
+	}
+	s.Add("sync_directory", directoryInterval, func(ctx context.Context) error {
+		report, err := svc.SyncDirectory(ctx, false)
+		if report != nil && report.Applied > 0 {
+			logger.Printf("Directory sync applied %d of %d changes (%d conflicts, %d skipped)",
+				report.Applied, len(report.Changes), report.Conflicts, report.Skipped)
+		}
+		return err
+	})
+
+	s.Add("send_digests", cfg.Notify.DigestInterval, func(ctx context.Context) error {
+		n, err := svc.SendDigests(ctx)
+		if n > 0 {
+			logger.Printf("Sent %d review digests", n)
+		}
+		return err
+	})
+
+	s.Add("deliver_webhooks", cfg.Notify.RetryInterval, func(ctx context.Context) error {
+		n, err := svc.DeliverWebhooks(ctx)
+		if n > 0 {
+			logger.Printf("Delivered %d webhook retries", n)
+		}
+		return err
+	})
+
+	// Jira review tasks and GitHub review requests go through the outbox.
+	var outboxInterval time.Duration
+	if cfg.Jira.Enabled() || cfg.GitHub.Enabled() {
+		outboxInterval = cfg.Outbox.Interval
+	}
+	s.Add("deliver_outbox", outboxInterval, func(ctx context.Context) error {
+		_, err := svc.DeliverOutbox(ctx)
+		return err
+	})
+
+	return s
+}
+
+// instanceID is SCHEDULER_INSTANCE_ID or, by default, the host name and
+// process ID, which tell replicas apart in the lease table.
+func instanceID(cfg *config.Config) string {
+	if cfg.Scheduler.InstanceID != "" {
+		return cfg.Scheduler.InstanceID
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
@@ -0,0 +1,63 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Hook ties a component into the application lifecycle. A hook without
+// Start describes a resource that is already open when it is appended, such
+// as a database pool: it is stopped even if Start never runs.
+type Hook struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+// Lifecycle starts hooks in the order they were appended and stops them in
+// reverse, so every component is stopped before the ones it depends on.
+type Lifecycle struct {
+	hooks   []Hook
+	started []bool
+}
+
+func (l *Lifecycle) Append(h Hook) {
+	l.hooks = append(l.hooks, h)
+	l.started = append(l.started, h.Start == nil)
+}
+
+// Start runs the start hooks in order. When one fails the components started
+// so far are stopped again.
+func (l *Lifecycle) Start(ctx context.Context) error {
+	for i, h := range l.hooks {
+		if l.started[i] || h.Start == nil {
+			continue
+		}
+		if err := h.Start(ctx); err != nil {
+			return errors.Join(fmt.Errorf("start %s: %w", h.Name, err), l.Stop(ctx))
+		}
+		l.started[i] = true
+	}
+	return nil
+}
+
+// Stop runs the stop hooks of started components in reverse order. It keeps
+// going past failures and returns all of them.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	var errs []error
+	for i := len(l.hooks) - 1; i >= 0; i-- {
+		h := l.hooks[i]
+		if !l.started[i] {
+			continue
+		}
+		l.started[i] = false
+		if h.Stop == nil {
+			continue
+		}
+		if err := h.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", h.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,105 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/app/validation"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/service"
+)
+
+// seedFixture is the layout of SEED_FILE. Entries go through the service
+// like API requests, so PRs get their reviewers assigned as usual.
+type seedFixture struct {
+	Teams        []models.Team     `json:"teams"`
+	PullRequests []seedPullRequest `json:"pull_requests"`
+}
+
+type seedPullRequest struct {
+	dto.CreatePullRequestRequest
+	// Merged merges the PR right after creating it.
+	Merged bool `json:"merged,omitempty"`
+}
+
+// addSeed loads the seed fixture before the scheduler and server start.
+// Teams and PRs that already exist are skipped, so restarting with the same
+// fixture is harmless.
+func (a *App) addSeed(svc *service.Service) {
+	a.lc.Append(Hook{
+		Name: "seed",
+		Start: func(ctx context.Context) error {
+			return a.seed(ctx, svc, a.cfg.Seed.File)
+		},
+	})
+}
+
+func (a *App) seed(ctx context.Context, svc *service.Service, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read seed file: %w", err)
+	}
+	var fixture seedFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return fmt.Errorf("parse seed file %s: %w", path, err)
+	}
+
+	var teams, prs int
+	for _, team := range fixture.Teams {
+		if err := validation.Validate(&team); err != nil {
+			return fmt.Errorf("seed team %q: %w", team.TeamName, err)
+		}
+		_, err := svc.CreateTeam(ctx, &team)
+		if isSeeded(err, models.ErrTeamExists) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("seed team %q: %w", team.TeamName, err)
+		}
+		teams++
+	}
+
+	for _, req := range fixture.PullRequests {
+		if err := validation.Validate(&req.CreatePullRequestRequest); err != nil {
+			return fmt.Errorf("seed PR %q: %w", req.PullRequestID, err)
+		}
+		_, err := svc.CreatePullRequest(ctx, service.CreatePullRequestParams{
+			PullRequestID:      req.PullRequestID,
+			PullRequestName:    req.PullRequestName,
+			AuthorID:           req.AuthorID,
+			ChangedPaths:       req.ChangedPaths,
+			Labels:             req.Labels,
+			Repository:         req.Repository,
+			Priority:           req.Priority,
+			IsDraft:            req.IsDraft,
+			LinesChanged:       req.LinesChanged,
+			FilesChanged:       req.FilesChanged,
+			PreferredReviewers: req.PreferredReviewers,
+		})
+		if isSeeded(err, models.ErrPRExists) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("seed PR %q: %w", req.PullRequestID, err)
+		}
+		if req.Merged {
+			if _, err := svc.MergePullRequest(ctx, req.PullRequestID); err != nil {
+				return fmt.Errorf("seed PR %q: merge: %w", req.PullRequestID, err)
+			}
+		}
+		prs++
+	}
+
+	a.logger.Printf("Seeded %d teams and %d PRs from %s", teams, prs, path)
+	return nil
+}
+
+// isSeeded reports whether err says the entry already exists.
+func isSeeded(err error, code models.ErrorCode) bool {
+	var svcErr *service.ServiceError
+	return errors.As(err, &svcErr) && svcErr.Code == code
+}
@@ -0,0 +1,136 @@
+package bootstrap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/app/config"
+)
+
+// certReloader serves the server certificate and client CA pool from disk.
+// During handshakes it checks the files at most once per interval and loads
+// them again when they changed, so rotated certificates are picked up
+// without a restart. A rotation that fails to load keeps the previous
+// files in use.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+	interval time.Duration
+	logger   *log.Logger
+
+	mu        sync.Mutex
+	checked   time.Time
+	modTime   time.Time
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+}
+
+func newCertReloader(cfg config.ServerConfig, logger *log.Logger) (*certReloader, error) {
+	r := &certReloader{
+		certFile: cfg.TLSCertFile,
+		keyFile:  cfg.TLSKeyFile,
+		caFile:   cfg.TLSClientCAFile,
+		interval: cfg.TLSReloadInterval,
+		logger:   logger,
+	}
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+	r.checked = time.Now()
+	return r, nil
+}
+
+// tlsConfig returns the server TLS configuration with HTTP/2 negotiated
+// through ALPN.
+func (r *certReloader) tlsConfig() *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, _ := r.current()
+			return cert, nil
+		},
+	}
+	if r.caFile != "" {
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, clientCAs := r.current()
+			conn := cfg.Clone()
+			conn.GetConfigForClient = nil
+			conn.Certificates = []tls.Certificate{*cert}
+			conn.ClientCAs = clientCAs
+			return conn, nil
+		}
+	}
+	return cfg
+}
+
+func (r *certReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) >= r.interval {
+		r.checked = time.Now()
+		modTime, err := r.latestModTime()
+		if err != nil {
+			r.logger.Printf("TLS certificate check failed, keeping current: %v", err)
+		} else if modTime.After(r.modTime) {
+			if err := r.load(modTime); err != nil {
+				r.logger.Printf("TLS certificate reload failed, keeping current: %v", err)
+			} else {
+				r.logger.Printf("Reloaded TLS certificate from %s", r.certFile)
+			}
+		}
+	}
+	return r.cert, r.clientCAs
+}
+
+func (r *certReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load TLS key pair: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("read client CA: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return errors.New("client CA file contains no certificates")
+		}
+	}
+
+	r.cert, r.clientCAs, r.modTime = &cert, clientCAs, modTime
+	return nil
+}
+
+func (r *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, name := range []string{r.certFile, r.keyFile, r.caFile} {
+		if name == "" {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -1,19 +1,70 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+type Environment string
+
+const (
+	EnvDevelopment Environment = "development"
+	EnvProduction  Environment = "production"
+)
+
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
+	Env Environment
+
+	Server     ServerConfig
+	Database   DatabaseConfig
+	Cache      CacheConfig
+	Redis      RedisConfig
+	Review     ReviewConfig
+	Archive    ArchiveConfig
+	Stale      StaleConfig
+	Statistics StatisticsConfig
+	Anomaly    AnomalyConfig
+	Retention  RetentionConfig
+	Scheduler  SchedulerConfig
+	Seed       SeedConfig
+	Notify     NotifyConfig
+	Directory  DirectoryConfig
+	Jira       JiraConfig
+	GitHub     GitHubConfig
+	Outbox     OutboxConfig
+	Webhooks   WebhooksConfig
+	JWT        JWTConfig
+	API        APIConfig
+	Compress   CompressConfig
+	Capture    CaptureConfig
+	Admin      AdminConfig
+	Sentry     SentryConfig
+	Features   FeaturesConfig
+
+	// args are the command-line arguments Load was called with, kept for
+	// Reload.
+	args []string
 }
 
+// ServerConfig serves HTTPS when TLSCertFile and TLSKeyFile are set. With
+// TLSClientCAFile clients must also present a certificate signed by that CA.
+// The files are checked for changes every TLSReloadInterval.
 type ServerConfig struct {
 	Port string
+
+	TLSCertFile       string
+	TLSKeyFile        string
+	TLSClientCAFile   string
+	TLSReloadInterval time.Duration
+}
+
+func (s ServerConfig) TLSEnabled() bool {
+	return s.TLSCertFile != ""
 }
 
 type DatabaseConfig struct {
@@ -23,28 +74,843 @@ type DatabaseConfig struct {
 	Password string
 	Name     string
 	SSLMode  string
+	// ReplicaDSN is the connection string of an optional read replica.
+	ReplicaDSN string
+
+	MaxOpenConns     int
+	MinConns         int
+	ConnMaxLifetime  time.Duration
+	StatementTimeout time.Duration
+	QueryTimeout     time.Duration
+	// BackupTimeout bounds a backup or restore instead of StatementTimeout
+	// and QueryTimeout; zero means no limit.
+	BackupTimeout time.Duration
+	// SlowQueryThreshold logs storage calls taking at least this long; zero
+	// disables the log.
+	SlowQueryThreshold time.Duration
+
+	ConnectRetries    int
+	ConnectBackoff    time.Duration
+	ConnectMaxBackoff time.Duration
+
+	// SchemaCheck refuses to start on a schema older than the code needs
+	// and serves reads only on a newer one.
+	SchemaCheck bool
+}
+
+// CacheConfig controls the in-process storage cache. A zero TTL disables it.
+type CacheConfig struct {
+	TTL time.Duration
+}
+
+// RedisConfig enables the shared Redis cache and distributed locks when Addr
+// is set.
+type RedisConfig struct {
+	Addr     string
+	Password string
+	DB       int
+	CacheTTL time.Duration
+	LockTTL  time.Duration
+}
+
+// ReviewConfig controls merge gating and review load. With
+// RequiredApprovals at zero PRs can be merged without approvals. SizeBuckets
+// weigh open reviews by the lines their PR changed; without them every
+// review counts 1. A reassignment can be undone for UndoWindow. With
+// RequireRepository every new PR must name a registered repository.
+type ReviewConfig struct {
+	RequiredApprovals int
+	SizeBuckets       []SizeBucket
+	UndoWindow        time.Duration
+	RequireRepository bool
+}
+
+// SizeBucket applies Weight to PRs with at most MaxLines changed lines.
+type SizeBucket struct {
+	MaxLines int
+	Weight   float64
+}
+
+// ArchiveConfig controls the background archival of merged PRs. Zero
+// AfterDays disables it.
+type ArchiveConfig struct {
+	AfterDays int
+	Interval  time.Duration
+}
+
+// StaleConfig controls stale PR detection. Zero Threshold disables it.
+// The escalation policies of teams are applied every EscalationInterval,
+// zero disabling them.
+type StaleConfig struct {
+	Threshold          time.Duration
+	CheckInterval      time.Duration
+	AutoReassign       bool
+	EscalationInterval time.Duration
+}
+
+// RetentionConfig controls the retention jobs, which run every Interval.
+// Users deactivated more than EraseInactiveAfterDays ago are erased, PRs
+// archived more than PurgeArchivedAfterDays ago are deleted and users and
+// teams deleted more than PurgeDeletedAfterDays ago are purged; zero
+// disables a policy.
+type RetentionConfig struct {
+	EraseInactiveAfterDays int
+	PurgeArchivedAfterDays int
+	PurgeDeletedAfterDays  int
+	Interval               time.Duration
+}
+
+// SeedConfig loads File, a JSON fixture of teams and PRs, at startup. It is
+// meant for development and demo environments and refused in production.
+type SeedConfig struct {
+	File string
+}
+
+// StatisticsConfig controls how often the pre-aggregated statistics are
+// recomputed. Zero RefreshInterval leaves them to the refresh endpoint.
+// GET /statistics responses are cached for CacheTTL, then served stale for
+// up to CacheStaleWhileRevalidate while being refreshed; zero CacheTTL
+// disables the cache.
+type StatisticsConfig struct {
+	RefreshInterval           time.Duration
+	CacheTTL                  time.Duration
+	CacheStaleWhileRevalidate time.Duration
+}
+
+// AnomalyConfig controls the anomaly check, run every Interval, zero
+// disabling it. A member is flagged when they got more than
+// AssignmentSharePercent of their team's reviewer assignments over Window,
+// once the team had MinAssignments; a team when its merges of the last week
+// fell more than MergeDropPercent below the weekly average of the four
+// weeks before, once that average is MinWeeklyMerges. Notify sends new
+// findings to the team's notification channel.
+type AnomalyConfig struct {
+	Interval               time.Duration
+	Window                 time.Duration
+	AssignmentSharePercent int
+	MinAssignments         int
+	MergeDropPercent       int
+	MinWeeklyMerges        int
+	Notify                 bool
 }
 
-func Load() (*Config, error) {
+// SchedulerConfig controls which instance runs the background jobs. With
+// LeaderElection only the instance holding a lease in the database runs
+// them; the lease expires LeaseTTL after its holder stops renewing it.
+// InstanceID names this instance as the lease holder.
+type SchedulerConfig struct {
+	LeaderElection bool
+	LeaseTTL       time.Duration
+	InstanceID     string
+}
+
+// NotifyConfig selects where notifications are delivered. Without
+// WebhookURL they are only logged. Provider is the format posted to
+// WebhookURL and to team channels that do not name their own. Failed
+// webhook posts are retried every RetryInterval, zero disabling retries,
+// until WebhookAttempts attempts. Due daily digests are looked for every
+// DigestInterval, zero disabling them.
+type NotifyConfig struct {
+	WebhookURL      string
+	Provider        string
+	WebhookTimeout  time.Duration
+	RetryInterval   time.Duration
+	WebhookAttempts int
+	DigestInterval  time.Duration
+}
+
+// DirectoryConfig enables the directory sync when Source is scim or ldap:
+// users and team membership are pulled from it every SyncInterval, zero
+// leaving syncs to the admin endpoint. Teams are the directory groups named
+// with GroupPrefix. ConflictPolicy decides about local users the sync does
+// not manage yet: directory takes them over, local leaves them alone.
+type DirectoryConfig struct {
+	Source         string
+	SyncInterval   time.Duration
+	ConflictPolicy string
+	GroupPrefix    string
+	Timeout        time.Duration
+
+	SCIMURL   string
+	SCIMToken string
+
+	LDAPURL          string
+	LDAPBindDN       string
+	LDAPBindPassword string
+	LDAPBaseDN       string
+	LDAPUserFilter   string
+	LDAPUserIDAttr   string
+	LDAPNameAttr     string
+	LDAPGroupAttr    string
+}
+
+func (d DirectoryConfig) Enabled() bool {
+	return d.Source != ""
+}
+
+// JiraConfig enables Jira review tasks when URL is set: a sub-task for
+// every reviewer under an issue for the PR, filed in the project Projects
+// maps the author's team to, or DefaultProject. With User the Token is an
+// API token for basic auth (Jira Cloud), otherwise a personal access token
+// (Jira Server). AssigneeField is accountId on Jira Cloud and name on Jira
+// Server.
+type JiraConfig struct {
+	URL   string
+	User  string
+	Token string
+
+	Projects          map[string]string
+	DefaultProject    string
+	IssueType         string
+	SubtaskType       string
+	ApproveTransition string
+	MergeTransition   string
+	AssigneeField     string
+	Timeout           time.Duration
+}
+
+func (j JiraConfig) Enabled() bool {
+	return j.URL != ""
+}
+
+// GitHubConfig enables review requests on GitHub when Token is set: the
+// reviewers of PRs in the repositories Repositories maps to GitHub's
+// owner/repo are requested on the GitHub PR their ID, owner/repo#number,
+// refers to.
+// URL is the API of GitHub Enterprise Server, https://HOST/api/v3, or
+// github.com's.
+type GitHubConfig struct {
+	URL          string
+	Token        string
+	Repositories map[string]string
+	Timeout      time.Duration
+}
+
+func (g GitHubConfig) Enabled() bool {
+	return g.Token != ""
+}
+
+// OutboxConfig sets how often calls to external systems queued in the
+// outbox are delivered, and after how many failed attempts one is given
+// up on.
+type OutboxConfig struct {
+	Interval    time.Duration
+	MaxAttempts int
+}
+
+// WebhooksConfig holds the secrets inbound webhooks are verified with; a
+// Git host without one cannot send webhooks. A delivery is accepted once per
+// ReplayWindow.
+type WebhooksConfig struct {
+	GitHubSecret    string
+	GitLabToken     string
+	BitbucketSecret string
+	// GerritToken authenticates the bridge forwarding Gerrit's
+	// stream-events, which Gerrit does not sign itself.
+	GerritToken string
+	// BotToken authenticates the chat or PR comment bot that runs slash
+	// commands for other users.
+	BotToken     string
+	ReplayWindow time.Duration
+}
+
+func (w WebhooksConfig) Enabled() bool {
+	return w.GitHubSecret != "" || w.GitLabToken != "" || w.BitbucketSecret != "" || w.GerritToken != "" || w.BotToken != ""
+}
+
+// JWTConfig enables bearer token authentication when Secret or
+// PublicKeyFile is set. UserClaim names the claim holding the user_id.
+type JWTConfig struct {
+	Secret        string
+	PublicKeyFile string
+	Issuer        string
+	Audience      string
+	UserClaim     string
+}
+
+// APIConfig controls API versioning. A non-zero V1Sunset deprecates the v1
+// response format and announces its removal date to clients. DefaultLocale
+// is the language of error messages and notifications for callers and
+// teams that choose none.
+type APIConfig struct {
+	V1Sunset      time.Time
+	DefaultLocale string
+}
+
+// CompressConfig controls response compression. Responses of the listed
+// media types are compressed once they reach MinSize bytes; an empty Types
+// disables compression.
+type CompressConfig struct {
+	MinSize int
+	Types   []string
+}
+
+// CaptureConfig controls capturing request and response bodies for
+// troubleshooting. Bodies are cut off after MaxBodySize bytes and the values
+// of the Redact fields are hidden. Log writes every exchange to the log;
+// BufferSize keeps the latest exchanges for GET /debug/requests.
+type CaptureConfig struct {
+	Log         bool
+	BufferSize  int
+	MaxBodySize int
+	Redact      []string
+}
+
+func (c CaptureConfig) Enabled() bool {
+	return c.Log || c.BufferSize > 0
+}
+
+// AdminConfig serves the profiling and runtime debug endpoints on a port of
+// their own, so they can be kept off the public network; an empty Port
+// disables them. With Token set requests must carry it as a bearer token.
+type AdminConfig struct {
+	Port  string
+	Token string
+}
+
+func (a AdminConfig) Enabled() bool {
+	return a.Port != ""
+}
+
+// SentryConfig reports panics recovered from handlers to the Sentry project
+// of DSN when it is set, giving up on a report after Timeout.
+type SentryConfig struct {
+	DSN     string
+	Timeout time.Duration
+}
+
+func (s SentryConfig) Enabled() bool {
+	return s.DSN != ""
+}
+
+// FeaturesConfig sets the feature flags that apply while no flag of the
+// same name is stored through the admin API.
+type FeaturesConfig struct {
+	Flags []FeatureFlag
+}
+
+// FeatureFlag is on for Percentage percent of teams when Enabled.
+type FeatureFlag struct {
+	Name       string
+	Enabled    bool
+	Percentage int
+}
+
+func (j JWTConfig) Enabled() bool {
+	return j.Secret != "" || j.PublicKeyFile != ""
+}
+
+func (r RedisConfig) Enabled() bool {
+	return r.Addr != ""
+}
+
+// Load reads the configuration. Each setting is named by its environment
+// variable and may also come from a YAML or JSON file (-config or
+// CONFIG_FILE) or a -set KEY=VALUE flag in args; flags win over the
+// environment, which wins over the file.
+func Load(args []string) (*Config, error) {
 	_ = godotenv.Load()
 
+	l, err := newLoader(args)
+	if err != nil {
+		return nil, err
+	}
+
+	env := Environment(l.getString("APP_ENV", string(EnvDevelopment)))
+	// Production must not silently fall back to the development password.
+	defaultPassword := "postgres"
+	if env == EnvProduction {
+		defaultPassword = ""
+	}
+
 	cfg := &Config{
+		Env:  env,
+		args: args,
 		Server: ServerConfig{
-			Port: getEnv("PORT", "8080"),
+			Port: l.getString("PORT", "8080"),
+
+			TLSCertFile:       l.getString("TLS_CERT_FILE", ""),
+			TLSKeyFile:        l.getString("TLS_KEY_FILE", ""),
+			TLSClientCAFile:   l.getString("TLS_CLIENT_CA_FILE", ""),
+			TLSReloadInterval: l.getDuration("TLS_RELOAD_INTERVAL", time.Minute),
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
-			Name:     getEnv("DB_NAME", "pr_reviewer"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+			Host:     l.getString("DB_HOST", "localhost"),
+			Port:     l.getString("DB_PORT", "5432"),
+			User:     l.getString("DB_USER", "postgres"),
+			Password: l.getString("DB_PASSWORD", defaultPassword),
+			Name:     l.getString("DB_NAME", "pr_reviewer"),
+			SSLMode:  l.getString("DB_SSLMODE", "disable"),
+
+			ReplicaDSN: l.getString("DB_REPLICA_DSN", ""),
+
+			MaxOpenConns:       l.getInt("DB_MAX_OPEN_CONNS", 25),
+			MinConns:           l.getInt("DB_MIN_CONNS", 0),
+			ConnMaxLifetime:    l.getDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+			StatementTimeout:   l.getDuration("DB_STATEMENT_TIMEOUT", 30*time.Second),
+			QueryTimeout:       l.getDuration("DB_QUERY_TIMEOUT", 5*time.Second),
+			BackupTimeout:      l.getDuration("DB_BACKUP_TIMEOUT", 10*time.Minute),
+			SlowQueryThreshold: l.getDuration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+
+			ConnectRetries:    l.getInt("DB_CONNECT_RETRIES", 10),
+			ConnectBackoff:    l.getDuration("DB_CONNECT_BACKOFF", 2*time.Second),
+			ConnectMaxBackoff: l.getDuration("DB_CONNECT_MAX_BACKOFF", 30*time.Second),
+
+			SchemaCheck: l.getBool("DB_SCHEMA_CHECK", true),
+		},
+		Cache: CacheConfig{
+			TTL: l.getDuration("CACHE_TTL", 30*time.Second),
+		},
+		Redis: RedisConfig{
+			Addr:     l.getString("REDIS_ADDR", ""),
+			Password: l.getString("REDIS_PASSWORD", ""),
+			DB:       l.getInt("REDIS_DB", 0),
+			CacheTTL: l.getDuration("REDIS_CACHE_TTL", time.Minute),
+			LockTTL:  l.getDuration("REDIS_LOCK_TTL", 10*time.Second),
+		},
+		Review: ReviewConfig{
+			RequiredApprovals: l.getInt("REQUIRED_APPROVALS", 0),
+			SizeBuckets:       l.getSizeBuckets("REVIEW_SIZE_BUCKETS", "100:1,500:2,2000:3"),
+			UndoWindow:        l.getDuration("REASSIGN_UNDO_WINDOW", time.Hour),
+			RequireRepository: l.getBool("REQUIRE_REPOSITORY", false),
+		},
+		Archive: ArchiveConfig{
+			AfterDays: l.getInt("ARCHIVE_AFTER_DAYS", 90),
+			Interval:  l.getDuration("ARCHIVE_INTERVAL", time.Hour),
+		},
+		Stale: StaleConfig{
+			Threshold:          l.getDuration("STALE_PR_THRESHOLD", 72*time.Hour),
+			CheckInterval:      l.getDuration("STALE_CHECK_INTERVAL", 15*time.Minute),
+			AutoReassign:       l.getBool("STALE_AUTO_REASSIGN", false),
+			EscalationInterval: l.getDuration("STALE_ESCALATION_INTERVAL", 15*time.Minute),
+		},
+		Retention: RetentionConfig{
+			EraseInactiveAfterDays: l.getInt("RETENTION_ERASE_INACTIVE_DAYS", 0),
+			PurgeArchivedAfterDays: l.getInt("RETENTION_PURGE_ARCHIVED_DAYS", 0),
+			PurgeDeletedAfterDays:  l.getInt("RETENTION_PURGE_DELETED_DAYS", 0),
+			Interval:               l.getDuration("RETENTION_INTERVAL", 24*time.Hour),
+		},
+		Seed: SeedConfig{
+			File: l.getString("SEED_FILE", ""),
+		},
+		Statistics: StatisticsConfig{
+			RefreshInterval:           l.getDuration("STATISTICS_REFRESH_INTERVAL", time.Minute),
+			CacheTTL:                  l.getDuration("STATISTICS_CACHE_TTL", 5*time.Second),
+			CacheStaleWhileRevalidate: l.getDuration("STATISTICS_CACHE_STALE", 30*time.Second),
+		},
+		Anomaly: AnomalyConfig{
+			Interval:               l.getDuration("ANOMALY_CHECK_INTERVAL", time.Hour),
+			Window:                 l.getDuration("ANOMALY_WINDOW", 14*24*time.Hour),
+			AssignmentSharePercent: l.getInt("ANOMALY_ASSIGNMENT_SHARE_PERCENT", 50),
+			MinAssignments:         l.getInt("ANOMALY_MIN_ASSIGNMENTS", 10),
+			MergeDropPercent:       l.getInt("ANOMALY_MERGE_DROP_PERCENT", 50),
+			MinWeeklyMerges:        l.getInt("ANOMALY_MIN_WEEKLY_MERGES", 3),
+			Notify:                 l.getBool("ANOMALY_NOTIFY", false),
+		},
+		Scheduler: SchedulerConfig{
+			LeaderElection: l.getBool("SCHEDULER_LEADER_ELECTION", true),
+			LeaseTTL:       l.getDuration("SCHEDULER_LEASE_TTL", 15*time.Second),
+			InstanceID:     l.getString("SCHEDULER_INSTANCE_ID", ""),
+		},
+		Notify: NotifyConfig{
+			WebhookURL:      l.getString("NOTIFY_WEBHOOK_URL", ""),
+			Provider:        l.getString("NOTIFY_PROVIDER", "webhook"),
+			WebhookTimeout:  l.getDuration("NOTIFY_WEBHOOK_TIMEOUT", 5*time.Second),
+			RetryInterval:   l.getDuration("NOTIFY_RETRY_INTERVAL", 10*time.Second),
+			WebhookAttempts: l.getInt("NOTIFY_WEBHOOK_ATTEMPTS", 10),
+			DigestInterval:  l.getDuration("NOTIFY_DIGEST_INTERVAL", 5*time.Minute),
+		},
+		Directory: DirectoryConfig{
+			Source:         l.getString("DIRECTORY_SOURCE", ""),
+			SyncInterval:   l.getDuration("DIRECTORY_SYNC_INTERVAL", time.Hour),
+			ConflictPolicy: l.getString("DIRECTORY_CONFLICT_POLICY", "directory"),
+			GroupPrefix:    l.getString("DIRECTORY_GROUP_PREFIX", ""),
+			Timeout:        l.getDuration("DIRECTORY_TIMEOUT", 30*time.Second),
+
+			SCIMURL:   l.getString("DIRECTORY_SCIM_URL", ""),
+			SCIMToken: l.getString("DIRECTORY_SCIM_TOKEN", ""),
+
+			LDAPURL:          l.getString("DIRECTORY_LDAP_URL", ""),
+			LDAPBindDN:       l.getString("DIRECTORY_LDAP_BIND_DN", ""),
+			LDAPBindPassword: l.getString("DIRECTORY_LDAP_BIND_PASSWORD", ""),
+			LDAPBaseDN:       l.getString("DIRECTORY_LDAP_BASE_DN", ""),
+			LDAPUserFilter:   l.getString("DIRECTORY_LDAP_USER_FILTER", "(objectClass=person)"),
+			LDAPUserIDAttr:   l.getString("DIRECTORY_LDAP_USER_ID_ATTR", "uid"),
+			LDAPNameAttr:     l.getString("DIRECTORY_LDAP_NAME_ATTR", "cn"),
+			LDAPGroupAttr:    l.getString("DIRECTORY_LDAP_GROUP_ATTR", "memberOf"),
+		},
+		Jira: JiraConfig{
+			URL:   l.getString("JIRA_URL", ""),
+			User:  l.getString("JIRA_USER", ""),
+			Token: l.getString("JIRA_TOKEN", ""),
+
+			Projects:          l.getMapping("JIRA_PROJECTS"),
+			DefaultProject:    l.getString("JIRA_DEFAULT_PROJECT", ""),
+			IssueType:         l.getString("JIRA_ISSUE_TYPE", "Task"),
+			SubtaskType:       l.getString("JIRA_SUBTASK_TYPE", "Sub-task"),
+			ApproveTransition: l.getString("JIRA_APPROVE_TRANSITION", "Done"),
+			MergeTransition:   l.getString("JIRA_MERGE_TRANSITION", "Done"),
+			AssigneeField:     l.getString("JIRA_ASSIGNEE_FIELD", "accountId"),
+			Timeout:           l.getDuration("JIRA_TIMEOUT", 10*time.Second),
+		},
+		GitHub: GitHubConfig{
+			URL:          l.getString("GITHUB_API_URL", "https://api.github.com"),
+			Token:        l.getString("GITHUB_TOKEN", ""),
+			Repositories: l.getMapping("GITHUB_REPOSITORIES"),
+			Timeout:      l.getDuration("GITHUB_TIMEOUT", 10*time.Second),
+		},
+		Outbox: OutboxConfig{
+			Interval:    l.getDuration("OUTBOX_INTERVAL", 10*time.Second),
+			MaxAttempts: l.getInt("OUTBOX_MAX_ATTEMPTS", 10),
+		},
+		Webhooks: WebhooksConfig{
+			GitHubSecret:    l.getString("WEBHOOK_GITHUB_SECRET", ""),
+			GitLabToken:     l.getString("WEBHOOK_GITLAB_TOKEN", ""),
+			BitbucketSecret: l.getString("WEBHOOK_BITBUCKET_SECRET", ""),
+			GerritToken:     l.getString("WEBHOOK_GERRIT_TOKEN", ""),
+			BotToken:        l.getString("WEBHOOK_BOT_TOKEN", ""),
+			ReplayWindow:    l.getDuration("WEBHOOK_REPLAY_WINDOW", 24*time.Hour),
+		},
+		JWT: JWTConfig{
+			Secret:        l.getString("JWT_SECRET", ""),
+			PublicKeyFile: l.getString("JWT_PUBLIC_KEY_FILE", ""),
+			Issuer:        l.getString("JWT_ISSUER", ""),
+			Audience:      l.getString("JWT_AUDIENCE", ""),
+			UserClaim:     l.getString("JWT_USER_CLAIM", "sub"),
+		},
+		API: APIConfig{
+			V1Sunset:      l.getDate("API_V1_SUNSET"),
+			DefaultLocale: l.getString("API_DEFAULT_LOCALE", "en"),
+		},
+		Compress: CompressConfig{
+			MinSize: l.getInt("COMPRESS_MIN_SIZE", 1024),
+			Types:   l.getList("COMPRESS_TYPES", "application/json,text/csv"),
+		},
+		Capture: CaptureConfig{
+			Log:         l.getBool("CAPTURE_LOG", false),
+			BufferSize:  l.getInt("CAPTURE_BUFFER_SIZE", 0),
+			MaxBodySize: l.getInt("CAPTURE_MAX_BODY_SIZE", 4096),
+			Redact:      l.getList("CAPTURE_REDACT", "password,secret,token,access_token,refresh_token,authorization,api_key,bind_password,notification_channel,channel"),
+		},
+		Admin: AdminConfig{
+			Port:  l.getString("ADMIN_PORT", ""),
+			Token: l.getString("ADMIN_TOKEN", ""),
+		},
+		Sentry: SentryConfig{
+			DSN:     l.getString("SENTRY_DSN", ""),
+			Timeout: l.getDuration("SENTRY_TIMEOUT", 5*time.Second),
+		},
+		Features: FeaturesConfig{
+			Flags: l.getFeatureFlags("FEATURE_FLAGS"),
 		},
 	}
 
+	if err := l.err(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
+// Reload reads the configuration again from the same sources. Only the
+// settings in Runtime take effect without a restart.
+func (c *Config) Reload() (*Config, error) {
+	return Load(c.args)
+}
+
+// Runtime holds the settings a running server applies on reload.
+type Runtime struct {
+	RequiredApprovals int
+	StaleAutoReassign bool
+}
+
+func (c *Config) Runtime() Runtime {
+	return Runtime{
+		RequiredApprovals: c.Review.RequiredApprovals,
+		StaleAutoReassign: c.Stale.AutoReassign,
+	}
+}
+
+// redacted stands in for secrets in Sanitized.
+const redacted = "[REDACTED]"
+
+// Sanitized returns a copy of the configuration fit for showing to
+// operators: passwords, tokens and the URLs that may embed them are
+// replaced.
+func (c *Config) Sanitized() Config {
+	s := *c
+	s.args = nil
+	secrets := []*string{
+		&s.Database.Password,
+		&s.Database.ReplicaDSN,
+		&s.Redis.Password,
+		&s.Notify.WebhookURL,
+		&s.Directory.SCIMToken,
+		&s.Directory.LDAPBindPassword,
+		&s.Jira.Token,
+		&s.GitHub.Token,
+		&s.Webhooks.GitHubSecret,
+		&s.Webhooks.GitLabToken,
+		&s.Webhooks.BitbucketSecret,
+		&s.Webhooks.GerritToken,
+		&s.Webhooks.BotToken,
+		&s.JWT.Secret,
+		&s.Admin.Token,
+		&s.Sentry.DSN,
+	}
+	for _, secret := range secrets {
+		if *secret != "" {
+			*secret = redacted
+		}
+	}
+	return s
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+	db := c.Database
+
+	if c.Env != EnvDevelopment && c.Env != EnvProduction {
+		errs = append(errs, fmt.Errorf("APP_ENV must be %s or %s", EnvDevelopment, EnvProduction))
+	}
+	if c.Env == EnvProduction && db.Password == "" {
+		errs = append(errs, errors.New("DB_PASSWORD is required in production"))
+	}
+	if c.Env == EnvProduction && c.Seed.File != "" {
+		errs = append(errs, errors.New("SEED_FILE is not allowed in production"))
+	}
+	if c.Env == EnvProduction && c.Admin.Enabled() && c.Admin.Token == "" {
+		errs = append(errs, errors.New("ADMIN_TOKEN is required in production when ADMIN_PORT is set"))
+	}
+	if c.Admin.Enabled() && c.Admin.Port == c.Server.Port {
+		errs = append(errs, errors.New("ADMIN_PORT must differ from PORT"))
+	}
+
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together"))
+	}
+	if c.Server.TLSClientCAFile != "" && !c.Server.TLSEnabled() {
+		errs = append(errs, errors.New("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE"))
+	}
+	if c.Server.TLSEnabled() && c.Server.TLSReloadInterval <= 0 {
+		errs = append(errs, errors.New("TLS_RELOAD_INTERVAL must be positive"))
+	}
+	if db.MaxOpenConns <= 0 {
+		errs = append(errs, errors.New("DB_MAX_OPEN_CONNS must be positive"))
+	}
+	if db.MinConns < 0 || db.MinConns > db.MaxOpenConns {
+		errs = append(errs, errors.New("DB_MIN_CONNS must be between 0 and DB_MAX_OPEN_CONNS"))
+	}
+	if db.ConnMaxLifetime < 0 {
+		errs = append(errs, errors.New("DB_CONN_MAX_LIFETIME must not be negative"))
+	}
+	if db.StatementTimeout < 0 {
+		errs = append(errs, errors.New("DB_STATEMENT_TIMEOUT must not be negative"))
+	}
+	if db.QueryTimeout < 0 {
+		errs = append(errs, errors.New("DB_QUERY_TIMEOUT must not be negative"))
+	}
+	if db.BackupTimeout < 0 {
+		errs = append(errs, errors.New("DB_BACKUP_TIMEOUT must not be negative"))
+	}
+	if db.SlowQueryThreshold < 0 {
+		errs = append(errs, errors.New("DB_SLOW_QUERY_THRESHOLD must not be negative"))
+	}
+	if db.ConnectRetries < 1 {
+		errs = append(errs, errors.New("DB_CONNECT_RETRIES must be at least 1"))
+	}
+	if db.ConnectBackoff <= 0 || db.ConnectMaxBackoff < db.ConnectBackoff {
+		errs = append(errs, errors.New("DB_CONNECT_BACKOFF must be positive and not exceed DB_CONNECT_MAX_BACKOFF"))
+	}
+	if c.Cache.TTL < 0 {
+		errs = append(errs, errors.New("CACHE_TTL must not be negative"))
+	}
+	if c.Redis.Enabled() && (c.Redis.CacheTTL <= 0 || c.Redis.LockTTL <= 0) {
+		errs = append(errs, errors.New("REDIS_CACHE_TTL and REDIS_LOCK_TTL must be positive"))
+	}
+	if c.Review.RequiredApprovals < 0 {
+		errs = append(errs, errors.New("REQUIRED_APPROVALS must not be negative"))
+	}
+	for i, bucket := range c.Review.SizeBuckets {
+		if bucket.MaxLines <= 0 || bucket.Weight <= 0 {
+			errs = append(errs, errors.New("REVIEW_SIZE_BUCKETS lines and weights must be positive"))
+			break
+		}
+		if i > 0 && bucket.MaxLines <= c.Review.SizeBuckets[i-1].MaxLines {
+			errs = append(errs, errors.New("REVIEW_SIZE_BUCKETS must be sorted by lines"))
+			break
+		}
+	}
+	if c.Review.UndoWindow <= 0 {
+		errs = append(errs, errors.New("REASSIGN_UNDO_WINDOW must be positive"))
+	}
+	for _, flag := range c.Features.Flags {
+		if flag.Percentage < 0 || flag.Percentage > 100 {
+			errs = append(errs, errors.New("FEATURE_FLAGS percentages must be between 0 and 100"))
+			break
+		}
+	}
+	if c.Archive.AfterDays < 0 {
+		errs = append(errs, errors.New("ARCHIVE_AFTER_DAYS must not be negative"))
+	}
+	if c.Archive.AfterDays > 0 && c.Archive.Interval <= 0 {
+		errs = append(errs, errors.New("ARCHIVE_INTERVAL must be positive"))
+	}
+	if c.Stale.Threshold < 0 {
+		errs = append(errs, errors.New("STALE_PR_THRESHOLD must not be negative"))
+	}
+	if c.Stale.Threshold > 0 && c.Stale.CheckInterval <= 0 {
+		errs = append(errs, errors.New("STALE_CHECK_INTERVAL must be positive"))
+	}
+	if c.Stale.EscalationInterval < 0 {
+		errs = append(errs, errors.New("STALE_ESCALATION_INTERVAL must not be negative"))
+	}
+	if c.Retention.EraseInactiveAfterDays < 0 {
+		errs = append(errs, errors.New("RETENTION_ERASE_INACTIVE_DAYS must not be negative"))
+	}
+	if c.Retention.PurgeArchivedAfterDays < 0 {
+		errs = append(errs, errors.New("RETENTION_PURGE_ARCHIVED_DAYS must not be negative"))
+	}
+	if c.Retention.PurgeDeletedAfterDays < 0 {
+		errs = append(errs, errors.New("RETENTION_PURGE_DELETED_DAYS must not be negative"))
+	}
+	if (c.Retention.EraseInactiveAfterDays > 0 || c.Retention.PurgeArchivedAfterDays > 0 || c.Retention.PurgeDeletedAfterDays > 0) && c.Retention.Interval <= 0 {
+		errs = append(errs, errors.New("RETENTION_INTERVAL must be positive"))
+	}
+	if c.Statistics.RefreshInterval < 0 {
+		errs = append(errs, errors.New("STATISTICS_REFRESH_INTERVAL must not be negative"))
+	}
+	if c.Statistics.CacheTTL < 0 || c.Statistics.CacheStaleWhileRevalidate < 0 {
+		errs = append(errs, errors.New("STATISTICS_CACHE_TTL and STATISTICS_CACHE_STALE must not be negative"))
+	}
+	if c.Anomaly.Interval < 0 {
+		errs = append(errs, errors.New("ANOMALY_CHECK_INTERVAL must not be negative"))
+	}
+	if c.Anomaly.Window <= 0 {
+		errs = append(errs, errors.New("ANOMALY_WINDOW must be positive"))
+	}
+	if c.Anomaly.AssignmentSharePercent <= 0 || c.Anomaly.AssignmentSharePercent > 100 {
+		errs = append(errs, errors.New("ANOMALY_ASSIGNMENT_SHARE_PERCENT must be between 1 and 100"))
+	}
+	if c.Anomaly.MergeDropPercent <= 0 || c.Anomaly.MergeDropPercent > 100 {
+		errs = append(errs, errors.New("ANOMALY_MERGE_DROP_PERCENT must be between 1 and 100"))
+	}
+	if c.Anomaly.MinAssignments < 0 || c.Anomaly.MinWeeklyMerges < 0 {
+		errs = append(errs, errors.New("ANOMALY_MIN_ASSIGNMENTS and ANOMALY_MIN_WEEKLY_MERGES must not be negative"))
+	}
+	// The lease is renewed every third of its TTL; a shorter TTL would
+	// renew it more than once a second.
+	if c.Scheduler.LeaderElection && c.Scheduler.LeaseTTL < 3*time.Second {
+		errs = append(errs, errors.New("SCHEDULER_LEASE_TTL must be at least 3s"))
+	}
+	if c.JWT.Secret != "" && c.JWT.PublicKeyFile != "" {
+		errs = append(errs, errors.New("set only one of JWT_SECRET and JWT_PUBLIC_KEY_FILE"))
+	}
+	switch c.API.DefaultLocale {
+	case "en", "ru":
+	default:
+		errs = append(errs, errors.New("API_DEFAULT_LOCALE must be en or ru"))
+	}
+	if c.Compress.MinSize < 0 {
+		errs = append(errs, errors.New("COMPRESS_MIN_SIZE must not be negative"))
+	}
+	if c.Capture.BufferSize < 0 {
+		errs = append(errs, errors.New("CAPTURE_BUFFER_SIZE must not be negative"))
+	}
+	if c.Capture.Enabled() && c.Capture.MaxBodySize <= 0 {
+		errs = append(errs, errors.New("CAPTURE_MAX_BODY_SIZE must be positive"))
+	}
+	if c.Sentry.Enabled() && c.Sentry.Timeout <= 0 {
+		errs = append(errs, errors.New("SENTRY_TIMEOUT must be positive"))
+	}
+	if c.Notify.WebhookTimeout <= 0 {
+		errs = append(errs, errors.New("NOTIFY_WEBHOOK_TIMEOUT must be positive"))
+	}
+	if c.Notify.RetryInterval < 0 {
+		errs = append(errs, errors.New("NOTIFY_RETRY_INTERVAL must not be negative"))
+	}
+	if c.Notify.WebhookAttempts < 1 {
+		errs = append(errs, errors.New("NOTIFY_WEBHOOK_ATTEMPTS must be at least 1"))
+	}
+	if c.Notify.DigestInterval < 0 {
+		errs = append(errs, errors.New("NOTIFY_DIGEST_INTERVAL must not be negative"))
+	}
+	switch c.Notify.Provider {
+	case "webhook", "slack", "msteams", "mattermost":
+	default:
+		errs = append(errs, errors.New("NOTIFY_PROVIDER must be webhook, slack, msteams or mattermost"))
+	}
+	switch dir := c.Directory; dir.Source {
+	case "":
+	case "scim":
+		if dir.SCIMURL == "" {
+			errs = append(errs, errors.New("DIRECTORY_SCIM_URL is required for the scim directory source"))
+		}
+	case "ldap":
+		if dir.LDAPURL == "" || dir.LDAPBaseDN == "" {
+			errs = append(errs, errors.New("DIRECTORY_LDAP_URL and DIRECTORY_LDAP_BASE_DN are required for the ldap directory source"))
+		}
+	default:
+		errs = append(errs, errors.New("DIRECTORY_SOURCE must be scim or ldap"))
+	}
+	if c.Directory.Enabled() {
+		if c.Directory.ConflictPolicy != "directory" && c.Directory.ConflictPolicy != "local" {
+			errs = append(errs, errors.New("DIRECTORY_CONFLICT_POLICY must be directory or local"))
+		}
+		if c.Directory.SyncInterval < 0 {
+			errs = append(errs, errors.New("DIRECTORY_SYNC_INTERVAL must not be negative"))
+		}
+		if c.Directory.Timeout <= 0 {
+			errs = append(errs, errors.New("DIRECTORY_TIMEOUT must be positive"))
+		}
+	}
+
+	if c.Jira.Enabled() {
+		if c.Jira.Token == "" {
+			errs = append(errs, errors.New("JIRA_TOKEN is required for Jira review tasks"))
+		}
+		if c.Jira.AssigneeField != "accountId" && c.Jira.AssigneeField != "name" {
+			errs = append(errs, errors.New("JIRA_ASSIGNEE_FIELD must be accountId or name"))
+		}
+		if c.Jira.Timeout <= 0 {
+			errs = append(errs, errors.New("JIRA_TIMEOUT must be positive"))
+		}
+	}
+	if c.GitHub.Enabled() {
+		if len(c.GitHub.Repositories) == 0 {
+			errs = append(errs, errors.New("GITHUB_REPOSITORIES is required for GitHub review requests"))
+		}
+		names := make([]string, 0, len(c.GitHub.Repositories))
+		for name := range c.GitHub.Repositories {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			repo := c.GitHub.Repositories[name]
+			if owner, slug, ok := strings.Cut(repo, "/"); !ok || owner == "" || slug == "" || strings.Contains(slug, "/") {
+				errs = append(errs, fmt.Errorf("GITHUB_REPOSITORIES: %s maps to %q, want OWNER/REPO", name, repo))
+			}
+		}
+		if c.GitHub.Timeout <= 0 {
+			errs = append(errs, errors.New("GITHUB_TIMEOUT must be positive"))
+		}
+	}
+	if c.Webhooks.Enabled() && c.Webhooks.ReplayWindow <= 0 {
+		errs = append(errs, errors.New("WEBHOOK_REPLAY_WINDOW must be positive"))
+	}
+	if c.Outbox.Interval < 0 {
+		errs = append(errs, errors.New("OUTBOX_INTERVAL must not be negative"))
+	}
+	if c.Outbox.MaxAttempts < 1 {
+		errs = append(errs, errors.New("OUTBOX_MAX_ATTEMPTS must be at least 1"))
+	}
+
+	return errors.Join(errs...)
+}
+
 func (c *Config) GetDSN() string {
 	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
@@ -57,9 +923,15 @@ func (c *Config) GetDSN() string {
 	)
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// ConnectDelay returns the pause before the given retry attempt (starting at
+// 1): the backoff doubles each time and is capped by ConnectMaxBackoff.
+func (c *DatabaseConfig) ConnectDelay(attempt int) time.Duration {
+	delay := c.ConnectBackoff
+	for i := 1; i < attempt && delay < c.ConnectMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > c.ConnectMaxBackoff {
+		delay = c.ConnectMaxBackoff
 	}
-	return defaultValue
+	return delay
 }
@@ -0,0 +1,278 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loader resolves settings by their environment variable name. Command-line
+// flags win over the environment, which wins over the config file; defaults
+// apply when none of them sets a value.
+type loader struct {
+	flags map[string]string
+	file  map[string]string
+	used  map[string]bool
+	errs  []error
+}
+
+type setFlag map[string]string
+
+func (f setFlag) String() string { return "" }
+
+func (f setFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("want KEY=VALUE, got %q", value)
+	}
+	f[strings.ToUpper(key)] = val
+	return nil
+}
+
+func newLoader(args []string) (*loader, error) {
+	l := &loader{
+		flags: make(map[string]string),
+		file:  make(map[string]string),
+		used:  make(map[string]bool),
+	}
+
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "YAML or JSON config `file`")
+	fs.Var(setFlag(l.flags), "set", "override a setting, e.g. -set DB_HOST=db (repeatable)")
+	seed := fs.String("seed", "", "load a fixture `file` at startup, same as -set SEED_FILE=file")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *seed != "" {
+		l.flags["SEED_FILE"] = *seed
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	if *configFile != "" {
+		if err := l.readFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// readFile loads a YAML or JSON file. Nested keys are joined with "_", so
+// {db: {host: x}} and {DB_HOST: x} both set DB_HOST.
+func (l *loader) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	var doc map[string]interface{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &doc)
+	} else {
+		err = yaml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return flatten("", doc, l.file)
+}
+
+func flatten(prefix string, doc map[string]interface{}, out map[string]string) error {
+	for key, value := range doc {
+		key = strings.ToUpper(key)
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		switch value := value.(type) {
+		case map[string]interface{}:
+			if err := flatten(key, value, out); err != nil {
+				return err
+			}
+		case []interface{}:
+			return fmt.Errorf("config file: %s must not be a list", key)
+		case nil:
+			out[key] = ""
+		default:
+			out[key] = fmt.Sprint(value)
+		}
+	}
+	return nil
+}
+
+func (l *loader) lookup(key string) string {
+	l.used[key] = true
+	if value, ok := l.flags[key]; ok {
+		return value
+	}
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return l.file[key]
+}
+
+// unknown reports file and flag keys that no setting read, which are most
+// likely typos.
+func (l *loader) unknown() error {
+	var keys []string
+	for _, src := range []map[string]string{l.flags, l.file} {
+		for key := range src {
+			if !l.used[key] {
+				keys = append(keys, key)
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	return fmt.Errorf("unknown settings: %s", strings.Join(keys, ", "))
+}
+
+func (l *loader) getString(key, defaultValue string) string {
+	if value := l.lookup(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getList splits a comma-separated value, dropping empty items. The literal
+// value "none" yields an empty list, overriding defaultValue.
+func (l *loader) getList(key, defaultValue string) []string {
+	value := l.getString(key, defaultValue)
+	if value == "none" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (l *loader) getInt(key string, defaultValue int) int {
+	value := l.lookup(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: invalid integer %q", key, value))
+		return defaultValue
+	}
+	return n
+}
+
+func (l *loader) getDuration(key string, defaultValue time.Duration) time.Duration {
+	value := l.lookup(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: invalid duration %q", key, value))
+		return defaultValue
+	}
+	return d
+}
+
+func (l *loader) getBool(key string, defaultValue bool) bool {
+	value := l.lookup(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: invalid boolean %q", key, value))
+		return defaultValue
+	}
+	return b
+}
+
+// getSizeBuckets parses a getList value of LINES:WEIGHT items.
+func (l *loader) getSizeBuckets(key, defaultValue string) []SizeBucket {
+	var buckets []SizeBucket
+	for _, item := range l.getList(key, defaultValue) {
+		lines, weight, _ := strings.Cut(item, ":")
+		maxLines, errLines := strconv.Atoi(lines)
+		w, errWeight := strconv.ParseFloat(weight, 64)
+		if errLines != nil || errWeight != nil {
+			l.errs = append(l.errs, fmt.Errorf("%s: invalid bucket %q, want LINES:WEIGHT", key, item))
+			return nil
+		}
+		buckets = append(buckets, SizeBucket{MaxLines: maxLines, Weight: w})
+	}
+	return buckets
+}
+
+// getFeatureFlags parses a getList value of NAME:on, NAME:off or
+// NAME:PERCENT items.
+func (l *loader) getFeatureFlags(key string) []FeatureFlag {
+	var flags []FeatureFlag
+	for _, item := range l.getList(key, "") {
+		name, value, _ := strings.Cut(item, ":")
+		flag := FeatureFlag{Name: name, Enabled: true, Percentage: 100}
+		var err error
+		switch value {
+		case "on":
+		case "off":
+			flag.Enabled, flag.Percentage = false, 0
+		default:
+			flag.Percentage, err = strconv.Atoi(value)
+		}
+		if err != nil || name == "" {
+			l.errs = append(l.errs, fmt.Errorf("%s: invalid flag %q, want NAME:on, NAME:off or NAME:PERCENT", key, item))
+			return nil
+		}
+		flags = append(flags, flag)
+	}
+	return flags
+}
+
+// getMapping parses a getList value of KEY:VALUE items.
+func (l *loader) getMapping(key string) map[string]string {
+	mapping := map[string]string{}
+	for _, item := range l.getList(key, "") {
+		k, v, _ := strings.Cut(item, ":")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if k == "" || v == "" {
+			l.errs = append(l.errs, fmt.Errorf("%s: invalid item %q, want KEY:VALUE", key, item))
+			return nil
+		}
+		mapping[k] = v
+	}
+	return mapping
+}
+
+// getDate parses a YYYY-MM-DD date as midnight UTC. Unset means the zero
+// time.
+func (l *loader) getDate(key string) time.Time {
+	value := l.lookup(key)
+	if value == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: invalid date %q, want YYYY-MM-DD", key, value))
+		return time.Time{}
+	}
+	return t
+}
+
+func (l *loader) err() error {
+	if err := l.unknown(); err != nil {
+		l.errs = append(l.errs, err)
+	}
+	return errors.Join(l.errs...)
+}
@@ -1,33 +1,346 @@
 package dto
 
 import (
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/app/reqlog"
+	"github.com/Thorlik/avito_internship/internal/app/scheduler"
 	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/persistence"
 )
 
 type CreatePullRequestRequest struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+	PullRequestID      string                     `json:"pull_request_id" validate:"required,max=255"`
+	PullRequestName    string                     `json:"pull_request_name" validate:"required,max=255"`
+	AuthorID           string                     `json:"author_id" validate:"required,max=255"`
+	ChangedPaths       []string                   `json:"changed_paths,omitempty" validate:"max=1000,dive,required,max=1024"`
+	Labels             []string                   `json:"labels,omitempty" validate:"max=50,dive,required,max=255"`
+	Repository         string                     `json:"repository,omitempty" validate:"max=255"`
+	Priority           models.PullRequestPriority `json:"priority,omitempty" validate:"oneof=LOW NORMAL URGENT"`
+	IsDraft            bool                       `json:"is_draft,omitempty"`
+	LinesChanged       int                        `json:"lines_changed,omitempty" validate:"min=0"`
+	FilesChanged       int                        `json:"files_changed,omitempty" validate:"min=0"`
+	PreferredReviewers []string                   `json:"preferred_reviewers,omitempty" validate:"max=10,dive,required,max=255"`
+}
+
+// UpdatePullRequestRequest changes the fields present; an empty list clears
+// labels or changed_paths.
+type UpdatePullRequestRequest struct {
+	PullRequestID   string                     `json:"pull_request_id" path:"id" validate:"required"`
+	PullRequestName *string                    `json:"pull_request_name,omitempty" validate:"max=255"`
+	Labels          []string                   `json:"labels,omitempty" validate:"max=50,dive,required,max=255"`
+	ChangedPaths    []string                   `json:"changed_paths,omitempty" validate:"max=1000,dive,required,max=1024"`
+	Repository      *string                    `json:"repository,omitempty" validate:"max=255"`
+	Priority        models.PullRequestPriority `json:"priority,omitempty" validate:"oneof=LOW NORMAL URGENT"`
+}
+
+// SimulateAssignmentRequest describes a hypothetical PR. The settings
+// fields, when present, replace the team's for the simulation; excluded
+// lists users to leave out as if excluded from the author's PRs.
+type SimulateAssignmentRequest struct {
+	AuthorID           string                     `json:"author_id" validate:"required,max=255"`
+	ChangedPaths       []string                   `json:"changed_paths,omitempty" validate:"max=1000,dive,required,max=1024"`
+	Labels             []string                   `json:"labels,omitempty" validate:"max=50,dive,required,max=255"`
+	Repository         string                     `json:"repository,omitempty" validate:"max=255"`
+	Priority           models.PullRequestPriority `json:"priority,omitempty" validate:"oneof=LOW NORMAL URGENT"`
+	LinesChanged       int                        `json:"lines_changed,omitempty" validate:"min=0"`
+	PreferredReviewers []string                   `json:"preferred_reviewers,omitempty" validate:"max=10,dive,required,max=255"`
+
+	ReviewerCount      *int     `json:"reviewer_count,omitempty"`
+	AssignmentStrategy *string  `json:"assignment_strategy,omitempty"`
+	MaxOpenReviews     *int     `json:"max_open_reviews,omitempty"`
+	PreferWorkingHours *bool    `json:"prefer_working_hours,omitempty"`
+	RequireSenior      *bool    `json:"require_senior,omitempty"`
+	PeerReviewOnly     *bool    `json:"peer_review_only,omitempty"`
+	Excluded           []string `json:"excluded,omitempty" validate:"max=100,dive,required,max=255"`
+}
+
+type SimulateAssignmentResponse struct {
+	Simulation models.AssignmentSimulation `json:"simulation"`
+}
+
+// BotCommandRequest is a slash command to run. A verified bot names the
+// actor; otherwise the caller's bearer token does. actor and @mentions are
+// user IDs, or external IDs when provider is set.
+type BotCommandRequest struct {
+	PullRequestID string                  `json:"pull_request_id" validate:"required,max=255"`
+	Command       string                  `json:"command" validate:"required,max=10000"`
+	Actor         string                  `json:"actor,omitempty" validate:"max=255"`
+	Provider      models.IdentityProvider `json:"provider,omitempty" validate:"oneof=github gitlab slack email"`
+}
+
+type BotCommandResponse struct {
+	Command string             `json:"command"`
+	Reply   string             `json:"reply"`
+	PR      models.PullRequest `json:"pr"`
+}
+
+// SuggestReviewersRequest names an existing PR by pull_request_id or a
+// hypothetical one by author_id, changed_paths and repository.
+type SuggestReviewersRequest struct {
+	PullRequestID string   `json:"pull_request_id,omitempty" validate:"max=255"`
+	AuthorID      string   `json:"author_id,omitempty" validate:"max=255"`
+	ChangedPaths  []string `json:"changed_paths,omitempty" validate:"max=1000,dive,required,max=1024"`
+	Repository    string   `json:"repository,omitempty" validate:"max=255"`
+	Count         int      `json:"count,omitempty" validate:"min=0,max=50"`
+}
+
+type SuggestReviewersResponse struct {
+	Suggestions models.ReviewerSuggestions `json:"suggestions"`
+}
+
+type SetLabelsRequest struct {
+	PullRequestID string   `json:"pull_request_id" path:"id" validate:"required"`
+	Labels        []string `json:"labels" validate:"max=50,dive,required,max=255"`
 }
 
 type MergePullRequestRequest struct {
-	PullRequestID string `json:"pull_request_id"`
+	PullRequestID string `json:"pull_request_id" path:"id" validate:"required"`
+}
+
+type MarkReadyRequest struct {
+	PullRequestID string `json:"pull_request_id" path:"id" validate:"required"`
 }
 
 type ReassignReviewerRequest struct {
-	PullRequestID string `json:"pull_request_id"`
-	OldUserID     string `json:"old_user_id"`
+	PullRequestID string `json:"pull_request_id" path:"id" validate:"required"`
+	OldUserID     string `json:"old_user_id" validate:"required"`
+}
+
+type UndoReassignRequest struct {
+	PullRequestID string `json:"pull_request_id" path:"id" validate:"required"`
+}
+
+type SwapReviewersRequest struct {
+	PullRequestID      string `json:"pull_request_id" validate:"required"`
+	UserID             string `json:"user_id" validate:"required"`
+	OtherPullRequestID string `json:"other_pull_request_id" validate:"required"`
+	OtherUserID        string `json:"other_user_id" validate:"required"`
+}
+
+type AddReviewerRequest struct {
+	PullRequestID string `json:"pull_request_id" path:"id" validate:"required"`
+	UserID        string `json:"user_id" validate:"required"`
+}
+
+type RemoveReviewerRequest struct {
+	PullRequestID string `json:"pull_request_id" path:"id" validate:"required"`
+	UserID        string `json:"user_id" path:"user_id" validate:"required"`
+}
+
+type SubmitReviewRequest struct {
+	PullRequestID string             `json:"pull_request_id" path:"id" validate:"required"`
+	ReviewerID    string             `json:"reviewer_id" validate:"required"`
+	State         models.ReviewState `json:"state" validate:"required,oneof=APPROVED CHANGES_REQUESTED COMMENTED"`
+	Comment       string             `json:"comment,omitempty" validate:"max=10000"`
+}
+
+type EraseUserRequest struct {
+	UserID string `json:"user_id" path:"id" validate:"required"`
+}
+
+// UserIDRequest names the user to delete or restore.
+type UserIDRequest struct {
+	UserID string `json:"user_id" path:"id" validate:"required"`
+}
+
+// TeamNameRequest names the team to delete or restore.
+type TeamNameRequest struct {
+	TeamName string `json:"team_name" path:"name" validate:"required"`
 }
 
 type SetUserActiveRequest struct {
-	UserID   string `json:"user_id"`
+	UserID   string `json:"user_id" path:"id" validate:"required"`
 	IsActive bool   `json:"is_active"`
 }
 
+type CreateRoutingRuleRequest struct {
+	TeamName string `json:"team_name" path:"name" validate:"required"`
+	Label    string `json:"label" validate:"required,max=255"`
+	UserID   string `json:"user_id" validate:"required"`
+}
+
+type RoutingRuleResponse struct {
+	Rule models.RoutingRule `json:"rule"`
+}
+
+type RoutingRulesResponse struct {
+	TeamName string               `json:"team_name"`
+	Rules    []models.RoutingRule `json:"rules"`
+}
+
+type CreateRepositoryRuleRequest struct {
+	TeamName   string   `json:"team_name" path:"name" validate:"required"`
+	Repository string   `json:"repository" validate:"required,max=255"`
+	UserIDs    []string `json:"user_ids" validate:"required,max=50,dive,required"`
+}
+
+type RepositoryRuleResponse struct {
+	Rule models.RepositoryRule `json:"rule"`
+}
+
+type RepositoryRulesResponse struct {
+	TeamName string                  `json:"team_name"`
+	Rules    []models.RepositoryRule `json:"rules"`
+}
+
+type CreateExclusionRuleRequest struct {
+	TeamName   string `json:"team_name" path:"name" validate:"required"`
+	ReviewerID string `json:"reviewer_id" validate:"required"`
+	AuthorID   string `json:"author_id" validate:"required"`
+}
+
+type ExclusionRuleResponse struct {
+	Rule models.ExclusionRule `json:"rule"`
+}
+
+type ExclusionRulesResponse struct {
+	TeamName string                 `json:"team_name"`
+	Rules    []models.ExclusionRule `json:"rules"`
+}
+
+type TeamSettingsResponse struct {
+	Settings models.TeamSettings `json:"settings"`
+}
+
+type AnomaliesResponse struct {
+	Anomalies []models.Anomaly `json:"anomalies"`
+}
+
+type EscalationsResponse struct {
+	TeamName    string              `json:"team_name"`
+	Escalations []models.Escalation `json:"escalations"`
+}
+
+type RepositoryResponse struct {
+	Repository models.Repository `json:"repository"`
+}
+
+type RepositoriesResponse struct {
+	Repositories []models.Repository `json:"repositories"`
+}
+
+// SetCodeOwnersRequest carries a CODEOWNERS file: one path pattern per
+// line followed by the user IDs owning it.
+type SetCodeOwnersRequest struct {
+	Repository string `json:"repository" path:"name" validate:"required"`
+	Content    string `json:"content" validate:"max=100000"`
+}
+
+// PreviewCodeOwnersRequest resolves changed_paths against content, or
+// against the stored mapping when content is empty.
+type PreviewCodeOwnersRequest struct {
+	Repository   string   `json:"repository" path:"name" validate:"required"`
+	Content      string   `json:"content,omitempty" validate:"max=100000"`
+	ChangedPaths []string `json:"changed_paths" validate:"required,max=1000,dive,required,max=1024"`
+}
+
+type CodeOwnersResponse struct {
+	CodeOwners models.CodeOwners `json:"code_owners"`
+}
+
+type CodeOwnersPreviewResponse struct {
+	Preview models.CodeOwnersPreview `json:"preview"`
+}
+
+type FeatureFlagResponse struct {
+	Flag models.FeatureFlag `json:"flag"`
+}
+
+type MaintenanceResponse struct {
+	Maintenance models.Maintenance `json:"maintenance"`
+}
+
+type SchemaResponse struct {
+	Schema models.SchemaStatus `json:"schema"`
+}
+
+type FeatureFlagsResponse struct {
+	Flags []models.FeatureFlag `json:"flags"`
+}
+
+type RebalanceTeamRequest struct {
+	TeamName string `json:"team_name" path:"name" validate:"required"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+type SyncDirectoryRequest struct {
+	DryRun bool `json:"dry_run"`
+}
+
+type SetMyActiveRequest struct {
+	IsActive bool `json:"is_active"`
+}
+
+type SetUserMenteeRequest struct {
+	UserID   string `json:"user_id" path:"id" validate:"required"`
+	IsMentee bool   `json:"is_mentee"`
+}
+
+type SetUserReviewWeightRequest struct {
+	UserID       string  `json:"user_id" path:"id" validate:"required"`
+	ReviewWeight float64 `json:"review_weight"`
+}
+
+type SetUserWorkingHoursRequest struct {
+	UserID       string `json:"user_id" path:"id" validate:"required"`
+	Timezone     string `json:"timezone" validate:"max=64"`
+	WorkingHours string `json:"working_hours" validate:"max=11"`
+}
+
+type SetUserSeniorityRequest struct {
+	UserID    string           `json:"user_id" path:"id" validate:"required"`
+	Seniority models.Seniority `json:"seniority" validate:"oneof=junior middle senior"`
+}
+
+type SetUserManagerRequest struct {
+	UserID    string `json:"user_id" path:"id" validate:"required"`
+	ManagerID string `json:"manager_id" validate:"max=255"`
+}
+
+type SetUserIdentityRequest struct {
+	UserID     string                  `json:"user_id" path:"id" validate:"required"`
+	Provider   models.IdentityProvider `json:"provider" path:"provider" validate:"required,oneof=github gitlab slack email jira bitbucket gerrit"`
+	ExternalID string                  `json:"external_id" validate:"required,max=255"`
+}
+
+type UserIdentityResponse struct {
+	Identity models.UserIdentity `json:"identity"`
+}
+
+type DigestPreferencesResponse struct {
+	Digest models.DigestPreferences `json:"digest"`
+}
+
+type UserIdentitiesResponse struct {
+	UserID     string                `json:"user_id"`
+	Identities []models.UserIdentity `json:"identities"`
+}
+
 type TeamResponse struct {
 	Team models.Team `json:"team"`
 }
 
+type TeamListResponse struct {
+	Teams []models.TeamSummary `json:"teams"`
+}
+
+type UsersResponse struct {
+	Users []models.User `json:"users"`
+}
+
+type UserListResponse struct {
+	Users []models.UserSummary `json:"users"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Deliveries []models.WebhookDelivery `json:"deliveries"`
+}
+
+type WebhookDeliveryResponse struct {
+	Delivery models.WebhookDelivery `json:"delivery"`
+}
+
 type UserResponse struct {
 	User models.User `json:"user"`
 }
@@ -41,7 +354,288 @@ type ReassignResponse struct {
 	ReplacedBy string             `json:"replaced_by"`
 }
 
+// UndoReassignResponse carries the PR after the undo and the history entry
+// recording it: user_id is the restored reviewer, replaced_user_id the
+// replacement taken off.
+type UndoReassignResponse struct {
+	PR   models.PullRequest     `json:"pr"`
+	Undo models.AssignmentEvent `json:"undo"`
+}
+
+// SwapReviewersResponse carries both PRs after the swap, in request order.
+type SwapReviewersResponse struct {
+	PR      models.PullRequest `json:"pr"`
+	OtherPR models.PullRequest `json:"other_pr"`
+}
+
+type ReviewResponse struct {
+	Review models.Review `json:"review"`
+}
+
+type PullRequestListResponse struct {
+	PullRequests []models.PullRequestShort `json:"pull_requests"`
+}
+
+type StalePullRequestsResponse struct {
+	PullRequests []models.StalePullRequest `json:"pull_requests"`
+}
+
 type UserReviewsResponse struct {
 	UserID            string                    `json:"user_id"`
 	PullRequestsShort []models.PullRequestShort `json:"pull_requests"`
 }
+
+type UserHistoryResponse struct {
+	UserID  string                      `json:"user_id"`
+	History []models.ReviewHistoryEntry `json:"history"`
+}
+
+type JobsResponse struct {
+	// Leader tells whether this instance runs the jobs; other instances
+	// report the stats of their last term.
+	Leader *bool                `json:"leader,omitempty"`
+	Jobs   []scheduler.JobStats `json:"jobs"`
+}
+
+type QueriesResponse struct {
+	Queries []persistence.QueryStats `json:"queries"`
+}
+
+type RequestsResponse struct {
+	Requests []reqlog.Entry `json:"requests"`
+}
+
+type QueueUpdateType string
+
+const (
+	QueueSnapshot   QueueUpdateType = "snapshot"
+	QueueAssigned   QueueUpdateType = "assigned"
+	QueueUnassigned QueueUpdateType = "unassigned"
+	QueueUpdated    QueueUpdateType = "updated"
+	QueueMerged     QueueUpdateType = "merged"
+	QueueClosed     QueueUpdateType = "closed"
+)
+
+// QueueSnapshotMessage is the first message on a reviewer's WebSocket: the
+// open review queue at the time of connecting.
+type QueueSnapshotMessage struct {
+	Type  QueueUpdateType           `json:"type"`
+	Queue []models.PullRequestShort `json:"queue"`
+}
+
+// QueueUpdate is pushed to a reviewer's WebSocket for every later change to
+// their queue.
+type QueueUpdate struct {
+	Type           QueueUpdateType     `json:"type"`
+	PullRequestID  string              `json:"pull_request_id"`
+	PullRequest    *models.PullRequest `json:"pull_request,omitempty"`
+	ReplacedUserID string              `json:"replaced_user_id,omitempty"`
+	NewUserID      string              `json:"new_user_id,omitempty"`
+}
+
+// GrafanaRange is the time range of a Grafana panel.
+type GrafanaRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// GrafanaSearchRequest lists the metrics of the JSON datasource; target is
+// what the user typed so far.
+type GrafanaSearchRequest struct {
+	Target string `json:"target"`
+}
+
+type GrafanaTarget struct {
+	Target string `json:"target"`
+	RefID  string `json:"refId"`
+	Type   string `json:"type"`
+}
+
+// GrafanaQueryRequest asks for the series of targets over range, at about
+// intervalMs resolution.
+type GrafanaQueryRequest struct {
+	Range         GrafanaRange    `json:"range"`
+	IntervalMs    int64           `json:"intervalMs"`
+	MaxDataPoints int             `json:"maxDataPoints"`
+	Targets       []GrafanaTarget `json:"targets"`
+}
+
+// GrafanaSeries is a time series answer; datapoints are [value, unix ms]
+// pairs.
+type GrafanaSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+type GrafanaColumn struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+// GrafanaTable is a table answer; rows hold a value for every column.
+type GrafanaTable struct {
+	Type    string          `json:"type"`
+	Columns []GrafanaColumn `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+type GrafanaAnnotationQuery struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// GrafanaAnnotationsRequest asks for the events of annotation.query, merges
+// or escalations, over range.
+type GrafanaAnnotationsRequest struct {
+	Range      GrafanaRange           `json:"range"`
+	Annotation GrafanaAnnotationQuery `json:"annotation"`
+}
+
+// GrafanaAnnotation echoes the annotation query it answers; time is in unix
+// ms.
+type GrafanaAnnotation struct {
+	Annotation GrafanaAnnotationQuery `json:"annotation"`
+	Time       int64                  `json:"time"`
+	Title      string                 `json:"title"`
+	Text       string                 `json:"text"`
+	Tags       []string               `json:"tags"`
+}
+
+// GitHubPullRequestEvent is a pull_request webhook of GitHub. Only the
+// fields the service uses are decoded.
+type GitHubPullRequestEvent struct {
+	Action            string            `json:"action"`
+	PullRequest       GitHubPullRequest `json:"pull_request"`
+	RequestedReviewer GitHubUser        `json:"requested_reviewer"`
+	Repository        GitHubRepository  `json:"repository"`
+}
+
+type GitHubPullRequest struct {
+	Number    int64      `json:"number" validate:"required"`
+	Title     string     `json:"title"`
+	Draft     bool       `json:"draft"`
+	Merged    bool       `json:"merged"`
+	Additions int        `json:"additions"`
+	Deletions int        `json:"deletions"`
+	User      GitHubUser `json:"user"`
+}
+
+type GitHubUser struct {
+	Login string `json:"login"`
+}
+
+// GitHubRepository is named owner/name by FullName.
+type GitHubRepository struct {
+	Name     string `json:"name"`
+	FullName string `json:"full_name" validate:"required"`
+}
+
+// GitLabMergeRequestEvent is a Merge Request Hook of GitLab. User is who
+// acted; GitLab names the author only by a numeric ID.
+type GitLabMergeRequestEvent struct {
+	User             GitLabUser         `json:"user"`
+	Project          GitLabProject      `json:"project"`
+	ObjectAttributes GitLabMergeRequest `json:"object_attributes"`
+}
+
+type GitLabMergeRequest struct {
+	IID    int64  `json:"iid" validate:"required"`
+	Title  string `json:"title"`
+	Action string `json:"action"`
+	Draft  bool   `json:"draft"`
+}
+
+type GitLabUser struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// GitLabProject is named group/project, with any subgroups, by
+// PathWithNamespace.
+type GitLabProject struct {
+	PathWithNamespace string `json:"path_with_namespace" validate:"required"`
+}
+
+// BitbucketCloudEvent is a pull request webhook of Bitbucket Cloud. Only
+// the fields the service uses are decoded.
+type BitbucketCloudEvent struct {
+	PullRequest BitbucketCloudPullRequest `json:"pullrequest"`
+}
+
+type BitbucketCloudPullRequest struct {
+	ID          int64              `json:"id" validate:"required"`
+	Title       string             `json:"title"`
+	Draft       bool               `json:"draft"`
+	Author      BitbucketCloudUser `json:"author"`
+	Destination struct {
+		Repository BitbucketCloudRepository `json:"repository"`
+	} `json:"destination"`
+}
+
+type BitbucketCloudUser struct {
+	AccountID string `json:"account_id"`
+}
+
+// BitbucketCloudRepository is named workspace/slug by FullName.
+type BitbucketCloudRepository struct {
+	FullName string `json:"full_name" validate:"required"`
+}
+
+// BitbucketServerEvent is a pull request webhook of Bitbucket Server and
+// Data Center.
+type BitbucketServerEvent struct {
+	PullRequest BitbucketServerPullRequest `json:"pullRequest"`
+}
+
+type BitbucketServerPullRequest struct {
+	ID     int64  `json:"id" validate:"required"`
+	Title  string `json:"title"`
+	Draft  bool   `json:"draft"`
+	Author struct {
+		User BitbucketServerUser `json:"user"`
+	} `json:"author"`
+	ToRef struct {
+		Repository BitbucketServerRepository `json:"repository"`
+	} `json:"toRef"`
+}
+
+type BitbucketServerUser struct {
+	Name         string `json:"name"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+type BitbucketServerRepository struct {
+	Slug    string `json:"slug" validate:"required"`
+	Project struct {
+		Key string `json:"key" validate:"required"`
+	} `json:"project"`
+}
+
+// GerritEvent is one event of Gerrit's stream-events. Only the fields the
+// change events use are decoded; other events carry no change at all.
+type GerritEvent struct {
+	Type     string         `json:"type"`
+	Change   GerritChange   `json:"change"`
+	PatchSet GerritPatchSet `json:"patchSet"`
+	Reviewer GerritAccount  `json:"reviewer"`
+}
+
+type GerritChange struct {
+	Project string        `json:"project"`
+	Number  int64         `json:"number"`
+	Subject string        `json:"subject"`
+	Owner   GerritAccount `json:"owner"`
+	WIP     bool          `json:"wip"`
+}
+
+// GerritPatchSet is a revision of a change. SizeDeletions is negative.
+type GerritPatchSet struct {
+	Number         int `json:"number"`
+	SizeInsertions int `json:"sizeInsertions"`
+	SizeDeletions  int `json:"sizeDeletions"`
+}
+
+type GerritAccount struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
@@ -0,0 +1,47 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// Writer receives a table row by row. Close must be called to flush
+// buffered output; implementations do not close the underlying io.Writer.
+type Writer interface {
+	WriteRow(cells []string) error
+	Close() error
+}
+
+func ParseFormat(value string) (Format, error) {
+	switch Format(value) {
+	case FormatCSV, FormatXLSX:
+		return Format(value), nil
+	}
+	return "", fmt.Errorf("unsupported export format %q", value)
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+func New(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w), nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (h *Handler) Backup(w http.ResponseWriter, r *http.Request) {
+	clearDeadlines(w)
+	backup, err := h.service.Backup(r.Context())
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="backup.json"`)
+	h.writeJSON(w, http.StatusOK, backup)
+}
+
+// Restore takes the backup as the request body. dry_run=true validates it
+// without keeping the restored data.
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	var dryRun bool
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			h.writeError(w, models.ErrValidation, "dry_run must be a boolean")
+			return
+		}
+		dryRun = parsed
+	}
+
+	clearDeadlines(w)
+	var backup models.Backup
+	if !h.decode(w, r, &backup) {
+		return
+	}
+
+	result, err := h.service.Restore(r.Context(), &backup, dryRun)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+// clearDeadlines lifts the server's read and write timeouts for a backup or
+// restore, which storage bounds with its own timeout instead.
+func clearDeadlines(w http.ResponseWriter) {
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/service"
+)
+
+// bitbucketActions maps the X-Event-Key of the PR events Bitbucket Cloud
+// (pullrequest:*) and Bitbucket Server (pr:*) send to what they report.
+var bitbucketActions = map[string]service.GitAction{
+	"pullrequest:created":   service.GitOpened,
+	"pullrequest:fulfilled": service.GitMerged,
+	"pullrequest:rejected":  service.GitDeclined,
+	"pr:opened":             service.GitOpened,
+	"pr:merged":             service.GitMerged,
+	"pr:declined":           service.GitDeclined,
+}
+
+// BitbucketWebhook applies the PR events of Bitbucket Cloud and Server and
+// answers with the PR as it is then. PRs are identified as
+// bitbucket:<workspace or project>:<repository slug>:<id> and their
+// repository is the slug. Other events, such as Server's connection test,
+// are acknowledged and ignored.
+func (h *Handler) BitbucketWebhook(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("X-Event-Key")
+	action, ok := bitbucketActions[key]
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	params := service.GitEventParams{Provider: models.ProviderBitbucket, Action: action}
+	if strings.HasPrefix(key, "pr:") {
+		var event dto.BitbucketServerEvent
+		if !h.decode(w, r, &event) {
+			return
+		}
+		pr := event.PullRequest
+		repo := pr.ToRef.Repository
+		params.PullRequestID = bitbucketPullRequestID(repo.Project.Key, repo.Slug, pr.ID)
+		params.PullRequestName = pr.Title
+		params.Repository = repo.Slug
+		params.Author = pr.Author.User.Name
+		params.AuthorEmail = pr.Author.User.EmailAddress
+		params.IsDraft = pr.Draft
+	} else {
+		var event dto.BitbucketCloudEvent
+		if !h.decode(w, r, &event) {
+			return
+		}
+		pr := event.PullRequest
+		workspace, slug, _ := strings.Cut(pr.Destination.Repository.FullName, "/")
+		params.PullRequestID = bitbucketPullRequestID(workspace, slug, pr.ID)
+		params.PullRequestName = pr.Title
+		params.Repository = slug
+		params.Author = pr.Author.AccountID
+		params.IsDraft = pr.Draft
+	}
+
+	pr, err := h.service.ApplyGitEvent(r.Context(), params)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.PullRequestResponse{PR: *pr})
+}
+
+func bitbucketPullRequestID(owner, slug string, id int64) string {
+	return strings.ToLower("bitbucket:"+owner+":"+slug+":") + strconv.FormatInt(id, 10)
+}
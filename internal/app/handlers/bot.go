@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/service"
+)
+
+// BotCommand runs a slash command as the bearer token's user and answers
+// with the reply to post; mentions are user IDs. Only a verified bot may
+// act for someone else, through BotWebhook, so an actor naming another user
+// is refused.
+func (h *Handler) BotCommand(w http.ResponseWriter, r *http.Request) {
+	var req dto.BotCommandRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	userID, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+	if req.Actor != "" && req.Actor != userID {
+		h.writeError(w, models.ErrForbidden, "only a verified bot can name the actor")
+		return
+	}
+
+	h.runBotCommand(w, r, service.BotCommandParams{
+		PullRequestID: req.PullRequestID,
+		Actor:         userID,
+		Text:          req.Command,
+	})
+}
+
+// BotWebhook runs a slash command forwarded by a chat or PR comment bot as
+// the actor the bot names. The webhook guard verifies the bot in front of
+// it; the actor and mentions are user IDs, or external IDs of provider.
+func (h *Handler) BotWebhook(w http.ResponseWriter, r *http.Request) {
+	var req dto.BotCommandRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	h.runBotCommand(w, r, service.BotCommandParams{
+		PullRequestID: req.PullRequestID,
+		Provider:      req.Provider,
+		Actor:         req.Actor,
+		Text:          req.Command,
+	})
+}
+
+func (h *Handler) runBotCommand(w http.ResponseWriter, r *http.Request, params service.BotCommandParams) {
+	reply, err := h.service.RunBotCommand(r.Context(), params)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.BotCommandResponse{
+		Command: reply.Command,
+		Reply:   reply.Reply,
+		PR:      reply.PR,
+	})
+}
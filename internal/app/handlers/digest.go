@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (h *Handler) GetDigestPreferences(w http.ResponseWriter, r *http.Request) {
+	prefs, err := h.service.GetDigestPreferences(r.Context(), param(r, "id", "user_id"))
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.DigestPreferencesResponse{Digest: *prefs})
+}
+
+func (h *Handler) SetDigestPreferences(w http.ResponseWriter, r *http.Request) {
+	var prefs models.DigestPreferences
+	if !h.decode(w, r, &prefs) {
+		return
+	}
+
+	updated, err := h.service.SetDigestPreferences(r.Context(), &prefs)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.DigestPreferencesResponse{Digest: *updated})
+}
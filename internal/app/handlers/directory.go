@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+)
+
+// SyncDirectory runs a directory sync now and returns its report; with
+// dry_run the changes are only planned.
+func (h *Handler) SyncDirectory(w http.ResponseWriter, r *http.Request) {
+	var req dto.SyncDirectoryRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	report, err := h.service.SyncDirectory(r.Context(), req.DryRun)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
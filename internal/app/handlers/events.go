@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// sseHeartbeat is how often an idle stream gets a comment line, so proxies
+// do not close it.
+const sseHeartbeat = 15 * time.Second
+
+// EventSubscriber is the source of live events for EventStream.
+type EventSubscriber interface {
+	Subscribe() (<-chan models.Event, func())
+}
+
+// EventStream streams PR lifecycle events as Server-Sent Events. The
+// optional types query parameter limits the stream to a comma-separated
+// list of event types.
+func (h *Handler) EventStream(events EventSubscriber) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		types := make(map[models.EventType]bool)
+		for _, t := range strings.Split(r.URL.Query().Get("types"), ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types[models.EventType(t)] = true
+			}
+		}
+
+		// The stream is meant to outlive the server's write timeout.
+		rc := http.NewResponseController(w)
+		rc.SetWriteDeadline(time.Time{})
+
+		ch, cancel := events.Subscribe()
+		defer cancel()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(sseHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			case event, ok := <-ch:
+				if !ok {
+					return
+				}
+				if len(types) > 0 && !types[event.Type] {
+					continue
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
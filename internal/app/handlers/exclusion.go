@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (h *Handler) ListExclusionRules(w http.ResponseWriter, r *http.Request) {
+	teamName := param(r, "name", "team_name")
+	if teamName == "" {
+		h.writeError(w, models.ErrMissingParam, "team_name is required")
+		return
+	}
+
+	rules, err := h.service.GetExclusionRules(r.Context(), teamName)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.ExclusionRulesResponse{TeamName: teamName, Rules: rules})
+}
+
+func (h *Handler) CreateExclusionRule(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateExclusionRuleRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	rule, err := h.service.CreateExclusionRule(r.Context(), &models.ExclusionRule{
+		TeamName:   req.TeamName,
+		ReviewerID: req.ReviewerID,
+		AuthorID:   req.AuthorID,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, dto.ExclusionRuleResponse{Rule: *rule})
+}
+
+func (h *Handler) DeleteExclusionRule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(param(r, "id", "id"), 10, 64)
+	if err != nil {
+		h.writeError(w, models.ErrValidation, "id must be an integer")
+		return
+	}
+
+	if err := h.service.DeleteExclusionRule(r.Context(), id); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Thorlik/avito_internship/internal/app/exporter"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// rowSource produces the rows of an export one at a time, handing each to
+// write as soon as it is built and stopping at the first error write returns.
+type rowSource func(write func(cells []string) error) error
+
+// writeExport sends the rows of source to the client as they are produced,
+// so CSV exports go out without being built up in memory first.
+func (h *Handler) writeExport(w http.ResponseWriter, format exporter.Format, filename string, source rowSource) {
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+	w.WriteHeader(http.StatusOK)
+
+	ew, err := exporter.New(format, w)
+	if err != nil {
+		log.Printf("export %s: %v", filename, err)
+		return
+	}
+	if err := source(ew.WriteRow); err != nil {
+		log.Printf("export %s: %v", filename, err)
+		return
+	}
+	if err := ew.Close(); err != nil {
+		log.Printf("export %s: %v", filename, err)
+	}
+}
+
+// writeAll hands rows to write in order.
+func writeAll(write func(cells []string) error, rows ...[]string) error {
+	for _, row := range rows {
+		if err := write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func statisticsRows(stats *models.Statistics) rowSource {
+	return func(write func(cells []string) error) error {
+		err := writeAll(write,
+			[]string{"metric", "value"},
+			[]string{"total_teams", strconv.Itoa(stats.TotalTeams)},
+			[]string{"total_users", strconv.Itoa(stats.TotalUsers)},
+			[]string{"active_users", strconv.Itoa(stats.ActiveUsers)},
+			[]string{"total_prs", strconv.Itoa(stats.TotalPRs)},
+			[]string{"open_prs", strconv.Itoa(stats.OpenPRs)},
+			[]string{"merged_prs", strconv.Itoa(stats.MergedPRs)},
+			[]string{"draft_prs", strconv.Itoa(stats.DraftPRs)},
+			[]string{"open_low_prs", strconv.Itoa(stats.OpenByPriority[models.PriorityLow])},
+			[]string{"open_normal_prs", strconv.Itoa(stats.OpenByPriority[models.PriorityNormal])},
+			[]string{"open_urgent_prs", strconv.Itoa(stats.OpenByPriority[models.PriorityUrgent])},
+			[]string{},
+			[]string{"user_id", "username", "open_reviews", "completed_reviews", "total_reviews"},
+		)
+		if err != nil {
+			return err
+		}
+		for _, rs := range stats.TopReviewers {
+			err := write([]string{
+				rs.UserID,
+				rs.Username,
+				strconv.Itoa(rs.OpenReviews),
+				strconv.Itoa(rs.CompletedReviews),
+				strconv.Itoa(rs.TotalReviews),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if err := writeAll(write, []string{}, []string{"repository", "total_prs", "open_prs", "merged_prs", "draft_prs"}); err != nil {
+			return err
+		}
+		for _, rs := range stats.ByRepository {
+			err := write([]string{
+				rs.Repository,
+				strconv.Itoa(rs.TotalPRs),
+				strconv.Itoa(rs.OpenPRs),
+				strconv.Itoa(rs.MergedPRs),
+				strconv.Itoa(rs.DraftPRs),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func workloadRows(workload *models.Workload) rowSource {
+	return func(write func(cells []string) error) error {
+		err := write([]string{"user_id", "username", "team_name", "open_reviews", "assigned_last_7d", "assigned_last_30d", "fairness_index"})
+		if err != nil {
+			return err
+		}
+		for _, uw := range workload.Users {
+			err := write([]string{
+				uw.UserID,
+				uw.Username,
+				uw.TeamName,
+				strconv.Itoa(uw.OpenReviews),
+				strconv.Itoa(uw.Assigned7d),
+				strconv.Itoa(uw.Assigned30d),
+				strconv.FormatFloat(uw.FairnessIndex, 'f', 2, 64),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
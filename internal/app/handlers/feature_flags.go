@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (h *Handler) ListFeatureFlags(w http.ResponseWriter, r *http.Request) {
+	flags, err := h.service.ListFeatureFlags(r.Context())
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.FeatureFlagsResponse{Flags: flags})
+}
+
+func (h *Handler) SetFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	var flag models.FeatureFlag
+	if !h.decode(w, r, &flag) {
+		return
+	}
+
+	updated, err := h.service.SetFeatureFlag(r.Context(), &flag)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.FeatureFlagResponse{Flag: *updated})
+}
+
+func (h *Handler) DeleteFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	name := param(r, "name", "name")
+	if name == "" {
+		h.writeError(w, models.ErrMissingParam, "name is required")
+		return
+	}
+
+	if err := h.service.DeleteFeatureFlag(r.Context(), name); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/service"
+)
+
+// gerritActions maps the types of the change events of Gerrit's
+// stream-events to what they report. A patchset-created event reports an
+// opened change only for its first patch set.
+var gerritActions = map[string]service.GitAction{
+	"patchset-created":  service.GitUpdated,
+	"wip-state-changed": service.GitUpdated,
+	"change-merged":     service.GitMerged,
+	"change-abandoned":  service.GitDeclined,
+	"change-restored":   service.GitOpened,
+	"reviewer-added":    service.GitReviewerAdded,
+	"reviewer-deleted":  service.GitReviewerRemoved,
+}
+
+// GerritWebhook applies one change event of Gerrit's stream-events, as a
+// bridge forwards them, and answers with the PR as it is then. Changes are
+// identified as gerrit:<change number> and their repository is the last
+// segment of the project. Other events are acknowledged and ignored.
+func (h *Handler) GerritWebhook(w http.ResponseWriter, r *http.Request) {
+	var event dto.GerritEvent
+	if !h.decode(w, r, &event) {
+		return
+	}
+	action, ok := gerritActions[event.Type]
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	change := event.Change
+	if change.Number <= 0 {
+		h.writeError(w, models.ErrValidation, "change.number is required")
+		return
+	}
+	if event.Type == "patchset-created" && event.PatchSet.Number == 1 {
+		action = service.GitOpened
+	}
+
+	project := change.Project
+	if i := strings.LastIndex(project, "/"); i >= 0 {
+		project = project[i+1:]
+	}
+	pr, err := h.service.ApplyGitEvent(r.Context(), service.GitEventParams{
+		Provider:        models.ProviderGerrit,
+		Action:          action,
+		PullRequestID:   "gerrit:" + strconv.FormatInt(change.Number, 10),
+		PullRequestName: change.Subject,
+		Repository:      project,
+		Author:          change.Owner.Username,
+		AuthorEmail:     change.Owner.Email,
+		IsDraft:         change.WIP,
+		LinesChanged:    event.PatchSet.SizeInsertions - event.PatchSet.SizeDeletions,
+		Reviewer:        event.Reviewer.Username,
+		ReviewerEmail:   event.Reviewer.Email,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.PullRequestResponse{PR: *pr})
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/service"
+)
+
+// githubActions maps the actions of GitHub's pull_request events to what
+// they report. A closed PR is merged or declined depending on merged.
+var githubActions = map[string]service.GitAction{
+	"opened":                 service.GitOpened,
+	"reopened":               service.GitOpened,
+	"closed":                 service.GitDeclined,
+	"edited":                 service.GitUpdated,
+	"synchronize":            service.GitUpdated,
+	"ready_for_review":       service.GitUpdated,
+	"converted_to_draft":     service.GitUpdated,
+	"review_requested":       service.GitReviewerAdded,
+	"review_request_removed": service.GitReviewerRemoved,
+}
+
+// GitHubWebhook applies the pull_request events of GitHub and answers with
+// the PR as it is then. PRs are identified as <owner>/<repository>#<number>,
+// as the GitHub integration expects, and their repository is the
+// repository's name. Other events and actions, such as the ping sent when
+// the webhook is added, are acknowledged and ignored.
+func (h *Handler) GitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-GitHub-Event") != "pull_request" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	var event dto.GitHubPullRequestEvent
+	if !h.decode(w, r, &event) {
+		return
+	}
+	action, ok := githubActions[event.Action]
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	pr := event.PullRequest
+	if event.Action == "closed" && pr.Merged {
+		action = service.GitMerged
+	}
+
+	result, err := h.service.ApplyGitEvent(r.Context(), service.GitEventParams{
+		Provider:        models.ProviderGitHub,
+		Action:          action,
+		PullRequestID:   strings.ToLower(event.Repository.FullName) + "#" + strconv.FormatInt(pr.Number, 10),
+		PullRequestName: pr.Title,
+		Repository:      event.Repository.Name,
+		Author:          pr.User.Login,
+		IsDraft:         pr.Draft,
+		LinesChanged:    pr.Additions + pr.Deletions,
+		Reviewer:        event.RequestedReviewer.Login,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.PullRequestResponse{PR: *result})
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/service"
+)
+
+// gitlabActions maps the actions of GitLab's merge request events to what
+// they report.
+var gitlabActions = map[string]service.GitAction{
+	"open":   service.GitOpened,
+	"reopen": service.GitOpened,
+	"update": service.GitUpdated,
+	"merge":  service.GitMerged,
+	"close":  service.GitDeclined,
+}
+
+// GitLabWebhook applies the Merge Request Hook events of GitLab and answers
+// with the PR as it is then. Merge requests are identified as
+// gitlab:<project path>!<iid> and their repository is the last segment of
+// the project path. GitLab names the author only by a numeric ID, so the
+// user who acted is taken as the author of a merge request not seen before.
+// Other events and actions, such as approvals, are acknowledged and ignored.
+func (h *Handler) GitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Gitlab-Event") != "Merge Request Hook" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	var event dto.GitLabMergeRequestEvent
+	if !h.decode(w, r, &event) {
+		return
+	}
+	mr := event.ObjectAttributes
+	action, ok := gitlabActions[mr.Action]
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	path := strings.ToLower(event.Project.PathWithNamespace)
+	repository := event.Project.PathWithNamespace
+	if i := strings.LastIndex(repository, "/"); i >= 0 {
+		repository = repository[i+1:]
+	}
+	pr, err := h.service.ApplyGitEvent(r.Context(), service.GitEventParams{
+		Provider:        models.ProviderGitLab,
+		Action:          action,
+		PullRequestID:   "gitlab:" + path + "!" + strconv.FormatInt(mr.IID, 10),
+		PullRequestName: mr.Title,
+		Repository:      repository,
+		Author:          event.User.Username,
+		AuthorEmail:     event.User.Email,
+		IsDraft:         mr.Draft,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.PullRequestResponse{PR: *pr})
+}
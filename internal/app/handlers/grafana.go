@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// grafanaReviewerLoad is the table target listing the workload of every
+// reviewer; the other targets are series metrics.
+const grafanaReviewerLoad = "reviewer_load"
+
+var grafanaTargets = []string{
+	string(models.SeriesPullRequestsOpened),
+	string(models.SeriesPullRequestsMerged),
+	string(models.SeriesReviewsSubmitted),
+	string(models.SeriesReviewerAssignments),
+	grafanaReviewerLoad,
+}
+
+// GrafanaTest answers the connection test of the JSON datasource.
+func (h *Handler) GrafanaTest(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// GrafanaSearch lists the targets starting with what the user typed.
+func (h *Handler) GrafanaSearch(w http.ResponseWriter, r *http.Request) {
+	var req dto.GrafanaSearchRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	targets := []string{}
+	for _, t := range grafanaTargets {
+		if strings.HasPrefix(t, req.Target) {
+			targets = append(targets, t)
+		}
+	}
+	h.writeJSON(w, http.StatusOK, targets)
+}
+
+// GrafanaQuery answers every target of the panel in order: series metrics
+// as time series at the panel's interval, reviewer_load as a table.
+func (h *Handler) GrafanaQuery(w http.ResponseWriter, r *http.Request) {
+	var req dto.GrafanaQueryRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	step := time.Duration(req.IntervalMs) * time.Millisecond
+	if req.MaxDataPoints > 0 {
+		if least := req.Range.To.Sub(req.Range.From) / time.Duration(req.MaxDataPoints); step < least {
+			step = least
+		}
+	}
+
+	results := []interface{}{}
+	for _, target := range req.Targets {
+		switch target.Target {
+		case "":
+			continue
+		case grafanaReviewerLoad:
+			workload, err := h.service.GetWorkload(r.Context())
+			if err != nil {
+				h.handleServiceError(w, err)
+				return
+			}
+			results = append(results, reviewerLoadTable(workload))
+		default:
+			points, err := h.service.GetSeries(r.Context(), models.SeriesMetric(target.Target), req.Range.From, req.Range.To, step)
+			if err != nil {
+				h.handleServiceError(w, err)
+				return
+			}
+			series := dto.GrafanaSeries{Target: target.Target, Datapoints: make([][2]float64, len(points))}
+			for i, p := range points {
+				series.Datapoints[i] = [2]float64{p.Value, float64(p.Time.UnixMilli())}
+			}
+			results = append(results, series)
+		}
+	}
+	h.writeJSON(w, http.StatusOK, results)
+}
+
+// GrafanaAnnotations marks the events the annotation query names, merges
+// or escalations, on the panel.
+func (h *Handler) GrafanaAnnotations(w http.ResponseWriter, r *http.Request) {
+	var req dto.GrafanaAnnotationsRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	kind := models.AnnotationKind(strings.TrimSpace(req.Annotation.Query))
+	annotations, err := h.service.GetAnnotations(r.Context(), kind, req.Range.From, req.Range.To)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+	results := make([]dto.GrafanaAnnotation, len(annotations))
+	for i, a := range annotations {
+		results[i] = dto.GrafanaAnnotation{
+			Annotation: req.Annotation,
+			Time:       a.Time.UnixMilli(),
+			Title:      a.Title,
+			Text:       a.Text,
+			Tags:       a.Tags,
+		}
+	}
+	h.writeJSON(w, http.StatusOK, results)
+}
+
+func reviewerLoadTable(workload *models.Workload) dto.GrafanaTable {
+	table := dto.GrafanaTable{
+		Type: "table",
+		Columns: []dto.GrafanaColumn{
+			{Text: "user_id", Type: "string"},
+			{Text: "username", Type: "string"},
+			{Text: "team_name", Type: "string"},
+			{Text: "open_reviews", Type: "number"},
+			{Text: "assigned_last_7d", Type: "number"},
+			{Text: "assigned_last_30d", Type: "number"},
+			{Text: "fairness_index", Type: "number"},
+		},
+		Rows: make([][]interface{}, len(workload.Users)),
+	}
+	for i, u := range workload.Users {
+		table.Rows[i] = []interface{}{u.UserID, u.Username, u.TeamName, u.OpenReviews, u.Assigned7d, u.Assigned30d, u.FairnessIndex}
+	}
+	return table
+}
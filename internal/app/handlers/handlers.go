@@ -1,26 +1,27 @@
 package handlers
 
 import (
-	"encoding/json"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/app/exporter"
 	"github.com/Thorlik/avito_internship/internal/domain/models"
 	"github.com/Thorlik/avito_internship/internal/domain/service"
 )
 
 type Handler struct {
-	service *service.Service
+	service Service
 }
 
-func NewHandler(service *service.Service) *Handler {
+func NewHandler(service Service) *Handler {
 	return &Handler{service: service}
 }
 
 func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 	var team models.Team
-	if err := json.NewDecoder(r.Body).Decode(&team); err != nil {
-		h.writeError(w, http.StatusBadRequest, models.ErrNotFound, "invalid request body")
+	if !h.decode(w, r, &team) {
 		return
 	}
 
@@ -34,9 +35,9 @@ func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
-	teamName := r.URL.Query().Get("team_name")
+	teamName := param(r, "name", "team_name")
 	if teamName == "" {
-		h.writeError(w, http.StatusBadRequest, models.ErrNotFound, "team_name is required")
+		h.writeError(w, models.ErrMissingParam, "team_name is required")
 		return
 	}
 
@@ -49,10 +50,28 @@ func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, http.StatusOK, team)
 }
 
+func (h *Handler) ListTeams(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.TeamFilter{Prefix: query.Get("prefix")}
+	var err error
+	filter.Limit, filter.Offset, err = pageParams(query)
+	if err != nil {
+		h.writeError(w, models.ErrValidation, err.Error())
+		return
+	}
+
+	teams, err := h.service.ListTeams(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.TeamListResponse{Teams: teams})
+}
+
 func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 	var req dto.SetUserActiveRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, models.ErrNotFound, "invalid request body")
+	if !h.decode(w, r, &req) {
 		return
 	}
 
@@ -65,10 +84,162 @@ func (h *Handler) SetUserActive(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, http.StatusOK, dto.UserResponse{User: *user})
 }
 
+// EraseUser anonymizes the user's personal data.
+func (h *Handler) EraseUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.EraseUserRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	user, err := h.service.EraseUser(r.Context(), req.UserID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserResponse{User: *user})
+}
+
+// DeleteUser soft-deletes the user; RestoreUser brings them back.
+func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.UserIDRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	if err := h.service.DeleteUser(r.Context(), req.UserID); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.UserIDRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	user, err := h.service.RestoreUser(r.Context(), req.UserID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserResponse{User: *user})
+}
+
+// DeleteTeam soft-deletes the team with its members; RestoreTeam brings
+// them back.
+func (h *Handler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	var req dto.TeamNameRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	if err := h.service.DeleteTeam(r.Context(), req.TeamName); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) RestoreTeam(w http.ResponseWriter, r *http.Request) {
+	var req dto.TeamNameRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	team, err := h.service.RestoreTeam(r.Context(), req.TeamName)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.TeamResponse{Team: *team})
+}
+
+func (h *Handler) SetUserMentee(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetUserMenteeRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	user, err := h.service.SetUserMentee(r.Context(), req.UserID, req.IsMentee)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserResponse{User: *user})
+}
+
+func (h *Handler) SetUserReviewWeight(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetUserReviewWeightRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	user, err := h.service.SetUserReviewWeight(r.Context(), req.UserID, req.ReviewWeight)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserResponse{User: *user})
+}
+
+func (h *Handler) SetUserWorkingHours(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetUserWorkingHoursRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	user, err := h.service.SetUserWorkingHours(r.Context(), req.UserID, req.Timezone, req.WorkingHours)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserResponse{User: *user})
+}
+
+func (h *Handler) SetUserSeniority(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetUserSeniorityRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	user, err := h.service.SetUserSeniority(r.Context(), req.UserID, req.Seniority)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserResponse{User: *user})
+}
+
+func (h *Handler) SetUserManager(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetUserManagerRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	user, err := h.service.SetUserManager(r.Context(), req.UserID, req.ManagerID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserResponse{User: *user})
+}
+
 func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
-	userID := r.URL.Query().Get("user_id")
+	userID := param(r, "id", "user_id")
 	if userID == "" {
-		h.writeError(w, http.StatusBadRequest, models.ErrNotFound, "user_id is required")
+		h.writeError(w, models.ErrMissingParam, "user_id is required")
 		return
 	}
 
@@ -84,14 +255,75 @@ func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *Handler) GetUserHistory(w http.ResponseWriter, r *http.Request) {
+	userID := param(r, "id", "user_id")
+	if userID == "" {
+		h.writeError(w, models.ErrMissingParam, "user_id is required")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := models.ReviewHistoryFilter{UserID: userID}
+	var err error
+	if filter.Since, filter.Until, err = periodParams(query); err != nil {
+		h.writeError(w, models.ErrValidation, err.Error())
+		return
+	}
+	if filter.Limit, filter.Offset, err = pageParams(query); err != nil {
+		h.writeError(w, models.ErrValidation, err.Error())
+		return
+	}
+
+	history, err := h.service.GetUserHistory(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserHistoryResponse{UserID: userID, History: history})
+}
+
+func (h *Handler) GetUserStatistics(w http.ResponseWriter, r *http.Request) {
+	userID := param(r, "id", "user_id")
+	if userID == "" {
+		h.writeError(w, models.ErrMissingParam, "user_id is required")
+		return
+	}
+
+	since, until, err := periodParams(r.URL.Query())
+	if err != nil {
+		h.writeError(w, models.ErrValidation, err.Error())
+		return
+	}
+
+	stats, err := h.service.GetUserStatistics(r.Context(), userID, since, until)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, stats)
+}
+
 func (h *Handler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
 	var req dto.CreatePullRequestRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, models.ErrNotFound, "invalid request body")
+	if !h.decode(w, r, &req) {
 		return
 	}
 
-	pr, err := h.service.CreatePullRequest(r.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID)
+	pr, err := h.service.CreatePullRequest(r.Context(), service.CreatePullRequestParams{
+		PullRequestID:      req.PullRequestID,
+		PullRequestName:    req.PullRequestName,
+		AuthorID:           req.AuthorID,
+		ChangedPaths:       req.ChangedPaths,
+		Labels:             req.Labels,
+		Repository:         req.Repository,
+		Priority:           req.Priority,
+		IsDraft:            req.IsDraft,
+		LinesChanged:       req.LinesChanged,
+		FilesChanged:       req.FilesChanged,
+		PreferredReviewers: req.PreferredReviewers,
+	})
 	if err != nil {
 		h.handleServiceError(w, err)
 		return
@@ -100,10 +332,82 @@ func (h *Handler) CreatePullRequest(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, http.StatusCreated, dto.PullRequestResponse{PR: *pr})
 }
 
+func (h *Handler) UpdatePullRequest(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdatePullRequestRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	pr, err := h.service.UpdatePullRequest(r.Context(), service.UpdatePullRequestParams{
+		PullRequestID:   req.PullRequestID,
+		PullRequestName: req.PullRequestName,
+		Labels:          req.Labels,
+		ChangedPaths:    req.ChangedPaths,
+		Repository:      req.Repository,
+		Priority:        req.Priority,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.PullRequestResponse{PR: *pr})
+}
+
+func (h *Handler) SimulateAssignment(w http.ResponseWriter, r *http.Request) {
+	var req dto.SimulateAssignmentRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	simulation, err := h.service.SimulateAssignment(r.Context(), service.SimulateAssignmentParams{
+		AuthorID:           req.AuthorID,
+		ChangedPaths:       req.ChangedPaths,
+		Labels:             req.Labels,
+		Repository:         req.Repository,
+		Priority:           req.Priority,
+		LinesChanged:       req.LinesChanged,
+		PreferredReviewers: req.PreferredReviewers,
+		ReviewerCount:      req.ReviewerCount,
+		AssignmentStrategy: req.AssignmentStrategy,
+		MaxOpenReviews:     req.MaxOpenReviews,
+		PreferWorkingHours: req.PreferWorkingHours,
+		RequireSenior:      req.RequireSenior,
+		PeerReviewOnly:     req.PeerReviewOnly,
+		Excluded:           req.Excluded,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.SimulateAssignmentResponse{Simulation: *simulation})
+}
+
+func (h *Handler) SuggestReviewers(w http.ResponseWriter, r *http.Request) {
+	var req dto.SuggestReviewersRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	suggestions, err := h.service.SuggestReviewers(r.Context(), service.SuggestReviewersParams{
+		PullRequestID: req.PullRequestID,
+		AuthorID:      req.AuthorID,
+		ChangedPaths:  req.ChangedPaths,
+		Repository:    req.Repository,
+		Count:         req.Count,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.SuggestReviewersResponse{Suggestions: *suggestions})
+}
+
 func (h *Handler) MergePullRequest(w http.ResponseWriter, r *http.Request) {
 	var req dto.MergePullRequestRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, models.ErrNotFound, "invalid request body")
+	if !h.decode(w, r, &req) {
 		return
 	}
 
@@ -116,10 +420,138 @@ func (h *Handler) MergePullRequest(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, http.StatusOK, dto.PullRequestResponse{PR: *pr})
 }
 
+func (h *Handler) MarkPullRequestReady(w http.ResponseWriter, r *http.Request) {
+	var req dto.MarkReadyRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	pr, err := h.service.MarkPullRequestReady(r.Context(), req.PullRequestID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.PullRequestResponse{PR: *pr})
+}
+
+func (h *Handler) ListPullRequests(w http.ResponseWriter, r *http.Request) {
+	filter, msg := pullRequestFilter(r.URL.Query())
+	if msg != "" {
+		h.writeError(w, models.ErrValidation, msg)
+		return
+	}
+
+	prs, err := h.service.ListPullRequests(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.PullRequestListResponse{PullRequests: prs})
+}
+
+// SearchPullRequests is ListPullRequests with a full-text query q over PR
+// names.
+func (h *Handler) SearchPullRequests(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter, msg := pullRequestFilter(query)
+	if msg != "" {
+		h.writeError(w, models.ErrValidation, msg)
+		return
+	}
+	filter.Query = query.Get("q")
+
+	prs, err := h.service.SearchPullRequests(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.PullRequestListResponse{PullRequests: prs})
+}
+
+// pullRequestFilter reads the PR list filters from the query, returning the
+// validation message of the first invalid one.
+func pullRequestFilter(query url.Values) (models.PullRequestFilter, string) {
+	filter := models.PullRequestFilter{
+		Status:     models.PullRequestStatus(query.Get("status")),
+		Priority:   models.PullRequestPriority(query.Get("priority")),
+		AuthorID:   query.Get("author_id"),
+		TeamName:   query.Get("team_name"),
+		Repository: query.Get("repository"),
+	}
+
+	if filter.Status != "" && !filter.Status.Valid() {
+		return filter, "status must be OPEN, MERGED or CLOSED"
+	}
+	if filter.Priority != "" && !filter.Priority.Valid() {
+		return filter, "priority must be LOW, NORMAL or URGENT"
+	}
+	if v := query.Get("include_archived"); v != "" {
+		includeArchived, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, "include_archived must be a boolean"
+		}
+		filter.IncludeArchived = includeArchived
+	}
+	var err error
+	filter.Limit, filter.Offset, err = pageParams(query)
+	if err != nil {
+		return filter, err.Error()
+	}
+	return filter, ""
+}
+
+func (h *Handler) GetStalePullRequests(w http.ResponseWriter, r *http.Request) {
+	prs, err := h.service.GetStalePullRequests(r.Context())
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.StalePullRequestsResponse{PullRequests: prs})
+}
+
+func (h *Handler) GetPullRequest(w http.ResponseWriter, r *http.Request) {
+	prID := param(r, "id", "pull_request_id")
+	if prID == "" {
+		h.writeError(w, models.ErrMissingParam, "pull_request_id is required")
+		return
+	}
+
+	details, err := h.service.GetPullRequestDetails(r.Context(), prID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, details)
+}
+
+func (h *Handler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	var req dto.SubmitReviewRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	review, err := h.service.SubmitReview(r.Context(), &models.Review{
+		PullRequestID: req.PullRequestID,
+		ReviewerID:    req.ReviewerID,
+		State:         req.State,
+		Comment:       req.Comment,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, dto.ReviewResponse{Review: *review})
+}
+
 func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	var req dto.ReassignReviewerRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, models.ErrNotFound, "invalid request body")
+	if !h.decode(w, r, &req) {
 		return
 	}
 
@@ -135,12 +567,161 @@ func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *Handler) UndoReassign(w http.ResponseWriter, r *http.Request) {
+	var req dto.UndoReassignRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	pr, undo, err := h.service.UndoReassign(r.Context(), req.PullRequestID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UndoReassignResponse{PR: *pr, Undo: *undo})
+}
+
+func (h *Handler) SwapReviewers(w http.ResponseWriter, r *http.Request) {
+	var req dto.SwapReviewersRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	pr, other, err := h.service.SwapReviewers(r.Context(), service.SwapReviewersParams{
+		PullRequestID:      req.PullRequestID,
+		UserID:             req.UserID,
+		OtherPullRequestID: req.OtherPullRequestID,
+		OtherUserID:        req.OtherUserID,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.SwapReviewersResponse{PR: *pr, OtherPR: *other})
+}
+
+func (h *Handler) AddReviewer(w http.ResponseWriter, r *http.Request) {
+	var req dto.AddReviewerRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	pr, err := h.service.AddReviewer(r.Context(), req.PullRequestID, req.UserID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.PullRequestResponse{PR: *pr})
+}
+
+func (h *Handler) RemoveReviewer(w http.ResponseWriter, r *http.Request) {
+	var req dto.RemoveReviewerRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	pr, err := h.service.RemoveReviewer(r.Context(), req.PullRequestID, req.UserID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.PullRequestResponse{PR: *pr})
+}
+
 func (h *Handler) GetStatistics(w http.ResponseWriter, r *http.Request) {
+	format, ok := h.exportFormat(w, r)
+	if !ok {
+		return
+	}
+
 	stats, err := h.service.GetStatistics(r.Context())
 	if err != nil {
 		h.handleServiceError(w, err)
 		return
 	}
 
+	if format != "" {
+		h.writeExport(w, format, "statistics", statisticsRows(stats))
+		return
+	}
 	h.writeJSON(w, http.StatusOK, stats)
 }
+
+// RefreshStatistics recomputes the statistics without waiting for the
+// scheduled refresh.
+func (h *Handler) RefreshStatistics(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.service.RefreshStatistics(r.Context())
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, stats)
+}
+
+func (h *Handler) GetWorkload(w http.ResponseWriter, r *http.Request) {
+	format, ok := h.exportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	workload, err := h.service.GetWorkload(r.Context())
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	if format != "" {
+		h.writeExport(w, format, "workload", workloadRows(workload))
+		return
+	}
+	h.writeJSON(w, http.StatusOK, workload)
+}
+
+func (h *Handler) GetForecast(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	weeks := 0
+	if v := query.Get("weeks"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			h.writeError(w, models.ErrValidation, "weeks must be an integer")
+			return
+		}
+		weeks = n
+	}
+
+	forecast, err := h.service.ForecastReviewLoad(r.Context(), models.ForecastMethod(query.Get("method")), weeks)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, forecast)
+}
+
+func (h *Handler) GetAnomalies(w http.ResponseWriter, r *http.Request) {
+	anomalies, err := h.service.GetAnomalies(r.Context())
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, dto.AnomaliesResponse{Anomalies: anomalies})
+}
+
+// exportFormat reads the optional format query parameter. An empty format
+// means the regular JSON response.
+func (h *Handler) exportFormat(w http.ResponseWriter, r *http.Request) (exporter.Format, bool) {
+	value := r.URL.Query().Get("format")
+	if value == "" || value == "json" {
+		return "", true
+	}
+
+	format, err := exporter.ParseFormat(value)
+	if err != nil {
+		h.writeError(w, models.ErrValidation, "format must be one of json, csv, xlsx")
+		return "", false
+	}
+	return format, true
+}
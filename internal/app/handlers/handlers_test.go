@@ -0,0 +1,773 @@
+package handlers_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/app/auth"
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/app/handlers"
+	"github.com/Thorlik/avito_internship/internal/app/handlers/handlersmock"
+	"github.com/Thorlik/avito_internship/internal/app/reqlog"
+	"github.com/Thorlik/avito_internship/internal/app/router"
+	"github.com/Thorlik/avito_internship/internal/app/webhook"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+	"github.com/Thorlik/avito_internship/internal/domain/service"
+)
+
+func serve(t *testing.T, svc handlers.Service, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.New(handlers.NewHandler(svc), router.Options{}).ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) models.ErrorDetail {
+	t.Helper()
+	var resp models.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	return resp.Error
+}
+
+func TestCreatePullRequest(t *testing.T) {
+	svc := &handlersmock.ServiceMock{
+		CreatePullRequestFunc: func(ctx context.Context, params service.CreatePullRequestParams) (*models.PullRequest, error) {
+			return &models.PullRequest{
+				PullRequestID:     params.PullRequestID,
+				PullRequestName:   params.PullRequestName,
+				AuthorID:          params.AuthorID,
+				Status:            models.StatusOpen,
+				AssignedReviewers: []string{"u2"},
+			}, nil
+		},
+	}
+
+	rec := serve(t, svc, http.MethodPost, "/api/v2/pull-requests",
+		`{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1","labels":["backend"]}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+	}
+
+	calls := svc.CreatePullRequestCalls()
+	if len(calls) != 1 {
+		t.Fatalf("CreatePullRequest called %d times, want 1", len(calls))
+	}
+	if p := calls[0].Params; p.PullRequestID != "pr-1" || p.AuthorID != "u1" || len(p.Labels) != 1 {
+		t.Errorf("params = %+v", p)
+	}
+
+	var resp struct {
+		PR models.PullRequest `json:"pr"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.PR.PullRequestID != "pr-1" || len(resp.PR.AssignedReviewers) != 1 {
+		t.Errorf("pr = %+v", resp.PR)
+	}
+}
+
+func TestCreatePullRequestValidation(t *testing.T) {
+	svc := &handlersmock.ServiceMock{}
+
+	rec := serve(t, svc, http.MethodPost, "/api/v2/pull-requests", `{"pull_request_id":"pr-1"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if detail := decodeError(t, rec); detail.Code != models.ErrValidation || len(detail.Fields) == 0 {
+		t.Errorf("error = %+v, want %s with fields", detail, models.ErrValidation)
+	}
+	if len(svc.CreatePullRequestCalls()) != 0 {
+		t.Error("service called for an invalid request")
+	}
+}
+
+func TestUpdatePullRequest(t *testing.T) {
+	svc := &handlersmock.ServiceMock{
+		UpdatePullRequestFunc: func(ctx context.Context, params service.UpdatePullRequestParams) (*models.PullRequest, error) {
+			return &models.PullRequest{PullRequestID: params.PullRequestID, Status: models.StatusOpen}, nil
+		},
+	}
+
+	rec := serve(t, svc, http.MethodPatch, "/api/v2/pull-requests/pr-1", `{"pull_request_name":"Add search","labels":[]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	rec = serve(t, svc, http.MethodPost, "/pullRequest/update", `{"pull_request_id":"pr-2","priority":"URGENT"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("legacy status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	calls := svc.UpdatePullRequestCalls()
+	if len(calls) != 2 {
+		t.Fatalf("UpdatePullRequest called %d times, want 2", len(calls))
+	}
+	// Absent fields stay nil, an empty list is passed on to clear them.
+	if p := calls[0].Params; p.PullRequestID != "pr-1" || p.PullRequestName == nil || *p.PullRequestName != "Add search" ||
+		p.Labels == nil || p.ChangedPaths != nil || p.Repository != nil || p.Priority != "" {
+		t.Errorf("params = %+v", p)
+	}
+	if p := calls[1].Params; p.PullRequestID != "pr-2" || p.PullRequestName != nil || p.Labels != nil || p.Priority != models.PriorityUrgent {
+		t.Errorf("legacy params = %+v", p)
+	}
+
+	rec = serve(t, svc, http.MethodPatch, "/api/v2/pull-requests/pr-1", `{"pull_request_name":"`+strings.Repeat("a", 256)+`"}`)
+	if detail := decodeError(t, rec); rec.Code != http.StatusBadRequest || detail.Code != models.ErrValidation {
+		t.Errorf("long name: status %d, error %+v; want %s", rec.Code, detail, models.ErrValidation)
+	}
+}
+
+func TestMergePullRequestErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   models.ErrorCode
+	}{
+		{"service error", &service.ServiceError{Code: models.ErrNotFound, Message: "PR not found"}, http.StatusNotFound, models.ErrNotFound},
+		{"unavailable", fmt.Errorf("merge: %w", repository.ErrUnavailable), http.StatusServiceUnavailable, models.ErrServiceUnavailable},
+		{"timeout", repository.ErrTimeout, http.StatusGatewayTimeout, models.ErrTimeout},
+		{"unexpected", fmt.Errorf("boom"), http.StatusInternalServerError, models.ErrInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &handlersmock.ServiceMock{
+				MergePullRequestFunc: func(ctx context.Context, prID string) (*models.PullRequest, error) {
+					return nil, tt.err
+				},
+			}
+
+			rec := serve(t, svc, http.MethodPost, "/api/v2/pull-requests/pr-1/merge", "")
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			detail := decodeError(t, rec)
+			if detail.Code != tt.wantCode {
+				t.Errorf("code = %s, want %s", detail.Code, tt.wantCode)
+			}
+			if detail.RequestID == "" {
+				t.Error("error response has no request_id")
+			}
+			if calls := svc.MergePullRequestCalls(); len(calls) != 1 || calls[0].PrID != "pr-1" {
+				t.Errorf("MergePullRequest calls = %+v, want one for pr-1", calls)
+			}
+		})
+	}
+}
+
+// TestClientDisconnectCancelsRequest checks that a client giving up cancels
+// the context the service, and the storage queries below it, run under.
+func TestClientDisconnectCancelsRequest(t *testing.T) {
+	started := make(chan struct{})
+	observed := make(chan error, 1)
+	svc := &handlersmock.ServiceMock{
+		GetStatisticsFunc: func(ctx context.Context) (*models.Statistics, error) {
+			close(started)
+			select {
+			case <-ctx.Done():
+				observed <- ctx.Err()
+				return nil, ctx.Err()
+			case <-time.After(5 * time.Second):
+				observed <- nil
+				return &models.Statistics{}, nil
+			}
+		},
+	}
+	srv := httptest.NewServer(router.New(handlers.NewHandler(svc), router.Options{}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v2/statistics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if resp, err := srv.Client().Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	<-started
+	cancel()
+	if err := <-observed; !errors.Is(err, context.Canceled) {
+		t.Errorf("service context error = %v, want %v", err, context.Canceled)
+	}
+	<-done
+}
+
+func TestCancelledRequestStatus(t *testing.T) {
+	svc := &handlersmock.ServiceMock{
+		GetStatisticsFunc: func(ctx context.Context) (*models.Statistics, error) {
+			return nil, fmt.Errorf("statistics: %w", context.Canceled)
+		},
+	}
+
+	rec := serve(t, svc, http.MethodGet, "/api/v2/statistics", "")
+	if rec.Code != 499 {
+		t.Errorf("status = %d, want 499", rec.Code)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("body = %q, want empty", rec.Body)
+	}
+}
+
+func TestRestoreDryRun(t *testing.T) {
+	svc := &handlersmock.ServiceMock{
+		RestoreFunc: func(ctx context.Context, backup *models.Backup, dryRun bool) (*models.RestoreResult, error) {
+			return &models.RestoreResult{DryRun: dryRun}, nil
+		},
+	}
+
+	rec := serve(t, svc, http.MethodPost, "/admin/restore?dry_run=maybe", `{"format":1}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	rec = serve(t, svc, http.MethodPost, "/admin/restore?dry_run=true", `{"format":1}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if calls := svc.RestoreCalls(); len(calls) != 1 || !calls[0].DryRun {
+		t.Errorf("Restore calls = %+v, want one dry run", calls)
+	}
+}
+
+func TestGrafanaQuery(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	svc := &handlersmock.ServiceMock{
+		GetSeriesFunc: func(ctx context.Context, metric models.SeriesMetric, start, end time.Time, step time.Duration) ([]models.SeriesPoint, error) {
+			return []models.SeriesPoint{{Time: start, Value: 2}, {Time: start.Add(step), Value: 0}}, nil
+		},
+		GetWorkloadFunc: func(ctx context.Context) (*models.Workload, error) {
+			return &models.Workload{Users: []models.UserWorkload{{UserID: "u1", Username: "Alice", TeamName: "backend", OpenReviews: 3}}}, nil
+		},
+	}
+
+	rec := serve(t, svc, http.MethodPost, "/grafana/query", `{
+		"range": {"from": "2024-03-01T00:00:00Z", "to": "2024-03-01T02:00:00Z"},
+		"intervalMs": 3600000,
+		"targets": [{"target": "prs_merged", "refId": "A"}, {"target": "reviewer_load", "refId": "B", "type": "table"}]
+	}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	want := fmt.Sprintf(`[{"target":"prs_merged","datapoints":[[2,%d],[0,%d]]},`+
+		`{"type":"table","columns":[{"text":"user_id","type":"string"},{"text":"username","type":"string"},{"text":"team_name","type":"string"},`+
+		`{"text":"open_reviews","type":"number"},{"text":"assigned_last_7d","type":"number"},{"text":"assigned_last_30d","type":"number"},`+
+		`{"text":"fairness_index","type":"number"}],"rows":[["u1","Alice","backend",3,0,0,0]]}]`,
+		from.UnixMilli(), from.Add(time.Hour).UnixMilli())
+	if got := strings.TrimSpace(rec.Body.String()); got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+	if calls := svc.GetSeriesCalls(); len(calls) != 1 || calls[0].Metric != models.SeriesPullRequestsMerged || calls[0].Step != time.Hour {
+		t.Errorf("GetSeries calls = %+v, want prs_merged by the hour", calls)
+	}
+}
+
+func TestErrorMessagesAreLocalized(t *testing.T) {
+	svc := &handlersmock.ServiceMock{}
+
+	for _, tc := range []struct {
+		acceptLanguage, wantLanguage, wantMessage string
+	}{
+		{"", "en", "request validation failed"},
+		{"ru-RU,ru;q=0.9,en;q=0.8", "ru", "запрос не прошёл проверку"},
+		{"de, en;q=0.5", "en", "request validation failed"},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/pull-requests", strings.NewReader(`{"pull_request_id":"pr-1"}`))
+		req.Header.Set("Accept-Language", tc.acceptLanguage)
+		rec := httptest.NewRecorder()
+		router.New(handlers.NewHandler(svc), router.Options{}).ServeHTTP(rec, req)
+
+		if lang := rec.Header().Get("Content-Language"); lang != tc.wantLanguage {
+			t.Errorf("Accept-Language %q: Content-Language = %q, want %q", tc.acceptLanguage, lang, tc.wantLanguage)
+		}
+		detail := decodeError(t, rec)
+		if detail.Code != models.ErrValidation || detail.Message != tc.wantMessage {
+			t.Errorf("Accept-Language %q: error = %s %q, want %s %q", tc.acceptLanguage, detail.Code, detail.Message, models.ErrValidation, tc.wantMessage)
+		}
+		if tc.wantLanguage == "ru" && (len(detail.Fields) == 0 || detail.Fields[0].Message != "обязательное поле") {
+			t.Errorf("Accept-Language %q: fields = %+v, want translated messages", tc.acceptLanguage, detail.Fields)
+		}
+	}
+}
+
+func TestDebugRequestsRedactsBodies(t *testing.T) {
+	capture := reqlog.New(reqlog.Config{
+		BufferSize:  10,
+		MaxBodySize: 1024,
+		Redact:      []string{"notification_channel"},
+	}, nil)
+	r := router.New(handlers.NewHandler(&handlersmock.ServiceMock{}), router.Options{
+		Capture:  capture.Middleware,
+		Requests: capture.Entries,
+	})
+
+	// Malformed on purpose: the payload is what capturing is for.
+	body := `{"team_name":"backend","notification_channel":"https://hooks.example.com/secret",`
+	req := httptest.NewRequest(http.MethodPost, "/team/settings", strings.NewReader(body))
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/requests", nil))
+	var resp dto.RequestsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode requests: %v", err)
+	}
+	if len(resp.Requests) != 1 {
+		t.Fatalf("requests = %+v, want the settings update", resp.Requests)
+	}
+	got := resp.Requests[0]
+	if got.Status != http.StatusBadRequest || got.RequestID == "" {
+		t.Errorf("status = %d, request ID = %q, want 400 with an ID", got.Status, got.RequestID)
+	}
+	if strings.Contains(got.RequestBody, "secret") || !strings.Contains(got.RequestBody, `"team_name":"backend"`) {
+		t.Errorf("request body = %s, want the channel redacted only", got.RequestBody)
+	}
+	if !strings.Contains(got.ResponseBody, string(models.ErrInvalidBody)) {
+		t.Errorf("response body = %s, want the error", got.ResponseBody)
+	}
+}
+
+type panicRecorder struct {
+	requestID string
+	value     interface{}
+}
+
+func (p *panicRecorder) ReportPanic(_ *http.Request, requestID string, value interface{}, _ []byte) {
+	p.requestID, p.value = requestID, value
+}
+
+func TestPanicBecomesInternalError(t *testing.T) {
+	svc := &handlersmock.ServiceMock{
+		GetStatisticsFunc: func(ctx context.Context) (*models.Statistics, error) {
+			panic("statistics exploded")
+		},
+	}
+	reporter := &panicRecorder{}
+	rec := httptest.NewRecorder()
+	router.New(handlers.NewHandler(svc), router.Options{Panics: reporter}).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/statistics", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	detail := decodeError(t, rec)
+	if detail.Code != models.ErrInternal || detail.RequestID == "" {
+		t.Errorf("error = %+v, want INTERNAL with a request ID", detail)
+	}
+	if reporter.value != "statistics exploded" || reporter.requestID != detail.RequestID {
+		t.Errorf("reported %v for request %q, want the panic of %q", reporter.value, reporter.requestID, detail.RequestID)
+	}
+}
+
+func TestMaintenanceRefusesWrites(t *testing.T) {
+	svc := &handlersmock.ServiceMock{
+		CurrentMaintenanceFunc: func(ctx context.Context) models.Maintenance {
+			return models.Maintenance{Enabled: true, Message: "migrating"}
+		},
+		GetStatisticsFunc: func(ctx context.Context) (*models.Statistics, error) {
+			return &models.Statistics{}, nil
+		},
+		SimulateAssignmentFunc: func(ctx context.Context, params service.SimulateAssignmentParams) (*models.AssignmentSimulation, error) {
+			return &models.AssignmentSimulation{}, nil
+		},
+		SetMaintenanceFunc: func(ctx context.Context, m *models.Maintenance) (*models.Maintenance, error) {
+			return m, nil
+		},
+	}
+	r := router.New(handlers.NewHandler(svc), router.Options{Maintenance: true})
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/pull-requests", `{"pull_request_id":"pr-1","pull_request_name":"Fix","author_id":"u1"}`)
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("create: status = %d, Retry-After = %q, want 503 with Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if detail := decodeError(t, rec); detail.Code != models.ErrRetryLater || !strings.Contains(detail.Message, "migrating") {
+		t.Errorf("create: error = %+v, want RETRY_LATER with the reason", detail)
+	}
+
+	for _, tc := range []struct{ method, target, body string }{
+		{http.MethodGet, "/statistics", ""},
+		{http.MethodPost, "/api/v2/pull-requests/simulate", `{"pull_request_id":"pr-1","pull_request_name":"Fix","author_id":"u1"}`},
+		{http.MethodPut, "/admin/maintenance", `{"enabled":false}`},
+	} {
+		if rec := do(tc.method, tc.target, tc.body); rec.Code != http.StatusOK {
+			t.Errorf("%s %s: status = %d, want 200 during maintenance", tc.method, tc.target, rec.Code)
+		}
+	}
+}
+
+func TestDeleteAndRestoreTeam(t *testing.T) {
+	svc := &handlersmock.ServiceMock{
+		DeleteTeamFunc: func(ctx context.Context, teamName string) error {
+			return nil
+		},
+		RestoreTeamFunc: func(ctx context.Context, teamName string) (*models.Team, error) {
+			return &models.Team{TeamName: teamName, Members: []models.TeamMember{}}, nil
+		},
+	}
+
+	rec := serve(t, svc, http.MethodDelete, "/api/v2/teams/backend", "")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d, want %d: %s", rec.Code, http.StatusNoContent, rec.Body)
+	}
+	if calls := svc.DeleteTeamCalls(); len(calls) != 1 || calls[0].TeamName != "backend" {
+		t.Errorf("DeleteTeam calls = %+v, want one for backend", calls)
+	}
+
+	rec = serve(t, svc, http.MethodPost, "/team/restore", `{"team_name":"backend"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("restore status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var resp dto.TeamResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Team.TeamName != "backend" {
+		t.Errorf("team = %+v, want backend", resp.Team)
+	}
+}
+
+func TestGitHubWebhook(t *testing.T) {
+	svc := &handlersmock.ServiceMock{
+		ApplyGitEventFunc: func(ctx context.Context, params service.GitEventParams) (*models.PullRequest, error) {
+			return &models.PullRequest{PullRequestID: params.PullRequestID, Status: models.StatusOpen}, nil
+		},
+	}
+	r := router.New(handlers.NewHandler(svc), router.Options{
+		Webhooks: webhook.NewGuard(webhook.NewMemoryNonces(), time.Hour, webhook.GitHub("s3cret")),
+	})
+	delivery := 0
+	send := func(event, secret, body string) *httptest.ResponseRecorder {
+		delivery++
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(body))
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		req.Header.Set("X-GitHub-Delivery", strconv.Itoa(delivery))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	opened := `{"action": "opened", "pull_request": {"number": 7, "title": "Add search", "draft": true,
+		"additions": 120, "deletions": 30, "user": {"login": "alice"}},
+		"repository": {"name": "API", "full_name": "Acme/API"}}`
+	if rec := send("pull_request", "other", opened); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("wrong secret status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := send("pull_request", "s3cret", opened); rec.Code != http.StatusOK {
+		t.Fatalf("opened status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	merged := `{"action": "closed", "pull_request": {"number": 7, "title": "Add search", "merged": true,
+		"user": {"login": "alice"}}, "repository": {"name": "API", "full_name": "Acme/API"}}`
+	if rec := send("pull_request", "s3cret", merged); rec.Code != http.StatusOK {
+		t.Fatalf("merged status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	requested := `{"action": "review_requested", "pull_request": {"number": 7, "title": "Add search",
+		"user": {"login": "alice"}}, "requested_reviewer": {"login": "bob"},
+		"repository": {"name": "API", "full_name": "Acme/API"}}`
+	if rec := send("pull_request", "s3cret", requested); rec.Code != http.StatusOK {
+		t.Fatalf("review requested status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	calls := svc.ApplyGitEventCalls()
+	want := []service.GitEventParams{
+		{Provider: models.ProviderGitHub, Action: service.GitOpened, PullRequestID: "acme/api#7",
+			PullRequestName: "Add search", Repository: "API", Author: "alice", IsDraft: true, LinesChanged: 150},
+		{Provider: models.ProviderGitHub, Action: service.GitMerged, PullRequestID: "acme/api#7",
+			PullRequestName: "Add search", Repository: "API", Author: "alice"},
+		{Provider: models.ProviderGitHub, Action: service.GitReviewerAdded, PullRequestID: "acme/api#7",
+			PullRequestName: "Add search", Repository: "API", Author: "alice", Reviewer: "bob"},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("ApplyGitEvent calls = %+v, want %d", calls, len(want))
+	}
+	for i, call := range calls {
+		if call.Params != want[i] {
+			t.Errorf("ApplyGitEvent call %d = %+v, want %+v", i, call.Params, want[i])
+		}
+	}
+
+	if rec := send("ping", "s3cret", `{"zen": "Keep it simple."}`); rec.Code != http.StatusNoContent {
+		t.Errorf("ping status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	labeled := `{"action": "labeled", "pull_request": {"number": 7}, "repository": {"full_name": "Acme/API"}}`
+	if rec := send("pull_request", "s3cret", labeled); rec.Code != http.StatusNoContent {
+		t.Errorf("labeled status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+}
+
+func TestGitLabWebhook(t *testing.T) {
+	svc := &handlersmock.ServiceMock{
+		ApplyGitEventFunc: func(ctx context.Context, params service.GitEventParams) (*models.PullRequest, error) {
+			return &models.PullRequest{PullRequestID: params.PullRequestID, Status: models.StatusOpen}, nil
+		},
+	}
+	r := router.New(handlers.NewHandler(svc), router.Options{
+		Webhooks: webhook.NewGuard(webhook.NewMemoryNonces(), time.Hour, webhook.GitLab("t0ken")),
+	})
+	delivery := 0
+	send := func(event, token, body string) *httptest.ResponseRecorder {
+		delivery++
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/gitlab", strings.NewReader(body))
+		req.Header.Set("X-Gitlab-Event", event)
+		req.Header.Set("X-Gitlab-Token", token)
+		req.Header.Set("X-Gitlab-Event-UUID", strconv.Itoa(delivery))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	opened := `{"object_kind": "merge_request", "user": {"username": "alice", "email": "alice@example.com"},
+		"project": {"path_with_namespace": "Shop/Backend/API"},
+		"object_attributes": {"iid": 12, "title": "Fix login", "action": "open", "draft": true}}`
+	if rec := send("Merge Request Hook", "wrong", opened); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("wrong token status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := send("Merge Request Hook", "t0ken", opened); rec.Code != http.StatusOK {
+		t.Fatalf("open status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	calls := svc.ApplyGitEventCalls()
+	want := service.GitEventParams{Provider: models.ProviderGitLab, Action: service.GitOpened,
+		PullRequestID: "gitlab:shop/backend/api!12", PullRequestName: "Fix login", Repository: "API",
+		Author: "alice", AuthorEmail: "alice@example.com", IsDraft: true}
+	if len(calls) != 1 || calls[0].Params != want {
+		t.Fatalf("ApplyGitEvent calls = %+v, want [%+v]", calls, want)
+	}
+
+	approved := `{"object_kind": "merge_request", "project": {"path_with_namespace": "shop/api"},
+		"object_attributes": {"iid": 12, "action": "approved"}}`
+	if rec := send("Merge Request Hook", "t0ken", approved); rec.Code != http.StatusNoContent {
+		t.Errorf("approved status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if rec := send("Push Hook", "t0ken", `{"object_kind": "push"}`); rec.Code != http.StatusNoContent {
+		t.Errorf("push status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+}
+
+func TestBitbucketWebhook(t *testing.T) {
+	svc := &handlersmock.ServiceMock{
+		ApplyGitEventFunc: func(ctx context.Context, params service.GitEventParams) (*models.PullRequest, error) {
+			return &models.PullRequest{PullRequestID: params.PullRequestID, Status: models.StatusOpen}, nil
+		},
+	}
+	r := router.New(handlers.NewHandler(svc), router.Options{
+		Webhooks: webhook.NewGuard(webhook.NewMemoryNonces(), time.Hour, webhook.Bitbucket("s3cret")),
+	})
+	send := func(eventKey, secret, body string) *httptest.ResponseRecorder {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/bitbucket", strings.NewReader(body))
+		req.Header.Set("X-Event-Key", eventKey)
+		req.Header.Set("X-Hub-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		req.Header.Set("X-Request-UUID", eventKey)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	cloud := `{"pullrequest": {"id": 7, "title": "Add search", "draft": true,
+		"author": {"account_id": "557058:abc"},
+		"destination": {"repository": {"full_name": "Acme/API"}}}}`
+	if rec := send("pullrequest:created", "other", cloud); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("wrong secret status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := send("pullrequest:created", "s3cret", cloud); rec.Code != http.StatusOK {
+		t.Fatalf("cloud status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	server := `{"eventKey": "pr:declined", "pullRequest": {"id": 12, "title": "Fix login",
+		"author": {"user": {"name": "bob", "emailAddress": "bob@example.com"}},
+		"toRef": {"repository": {"slug": "web", "project": {"key": "SHOP"}}}}}`
+	if rec := send("pr:declined", "s3cret", server); rec.Code != http.StatusOK {
+		t.Fatalf("server status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	calls := svc.ApplyGitEventCalls()
+	want := []service.GitEventParams{
+		{Provider: models.ProviderBitbucket, Action: service.GitOpened, PullRequestID: "bitbucket:acme:api:7",
+			PullRequestName: "Add search", Repository: "API", Author: "557058:abc", IsDraft: true},
+		{Provider: models.ProviderBitbucket, Action: service.GitDeclined, PullRequestID: "bitbucket:shop:web:12",
+			PullRequestName: "Fix login", Repository: "web", Author: "bob", AuthorEmail: "bob@example.com"},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("ApplyGitEvent calls = %+v, want %d", calls, len(want))
+	}
+	for i, call := range calls {
+		if call.Params != want[i] {
+			t.Errorf("ApplyGitEvent call %d = %+v, want %+v", i, call.Params, want[i])
+		}
+	}
+
+	if rec := send("diagnostics:ping", "s3cret", `{"test": true}`); rec.Code != http.StatusNoContent {
+		t.Errorf("ping status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+}
+
+func TestGerritWebhook(t *testing.T) {
+	svc := &handlersmock.ServiceMock{
+		ApplyGitEventFunc: func(ctx context.Context, params service.GitEventParams) (*models.PullRequest, error) {
+			return &models.PullRequest{PullRequestID: params.PullRequestID, Status: models.StatusOpen}, nil
+		},
+	}
+	r := router.New(handlers.NewHandler(svc), router.Options{
+		Webhooks: webhook.NewGuard(webhook.NewMemoryNonces(), time.Hour, webhook.Gerrit("t0ken")),
+	})
+	send := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/gerrit", strings.NewReader(body))
+		req.Header.Set("X-Gerrit-Token", token)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	createdOn := `"eventCreatedOn": ` + strconv.FormatInt(time.Now().Unix(), 10)
+	change := createdOn + `, "change": {"project": "platform/api", "number": 4711, "subject": "Add search", "wip": true,
+		"owner": {"username": "alice", "email": "alice@example.com"}}`
+	events := []string{
+		`{"type": "patchset-created", ` + change + `, "patchSet": {"number": 1, "sizeInsertions": 30, "sizeDeletions": -12}}`,
+		`{"type": "patchset-created", ` + change + `, "patchSet": {"number": 2, "sizeInsertions": 40, "sizeDeletions": -12}}`,
+		`{"type": "reviewer-added", ` + change + `, "reviewer": {"username": "bob"}}`,
+	}
+	if rec := send("nope", events[0]); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("wrong token status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	for _, body := range events {
+		if rec := send("t0ken", body); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+	}
+
+	base := service.GitEventParams{Provider: models.ProviderGerrit, PullRequestID: "gerrit:4711",
+		PullRequestName: "Add search", Repository: "api", Author: "alice", AuthorEmail: "alice@example.com", IsDraft: true}
+	want := []service.GitEventParams{base, base, base}
+	want[0].Action, want[0].LinesChanged = service.GitOpened, 42
+	want[1].Action, want[1].LinesChanged = service.GitUpdated, 52
+	want[2].Action, want[2].Reviewer = service.GitReviewerAdded, "bob"
+	calls := svc.ApplyGitEventCalls()
+	if len(calls) != len(want) {
+		t.Fatalf("ApplyGitEvent calls = %+v, want %d", calls, len(want))
+	}
+	for i, call := range calls {
+		if call.Params != want[i] {
+			t.Errorf("ApplyGitEvent call %d = %+v, want %+v", i, call.Params, want[i])
+		}
+	}
+
+	if rec := send("t0ken", `{"type": "ref-updated", `+createdOn+`, "refUpdate": {"refName": "refs/heads/main"}}`); rec.Code != http.StatusNoContent {
+		t.Errorf("ref-updated status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if rec := send("t0ken", `{"type": "change-merged", `+createdOn+`, "change": {}}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("no change number status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestBotCommandActor(t *testing.T) {
+	svc := &handlersmock.ServiceMock{
+		RunBotCommandFunc: func(ctx context.Context, params service.BotCommandParams) (*models.BotReply, error) {
+			return &models.BotReply{Command: "/approve", PR: models.PullRequest{PullRequestID: params.PullRequestID}}, nil
+		},
+		UserLocaleFunc: func(ctx context.Context, userID string) (models.Locale, error) {
+			return "", nil
+		},
+	}
+	r := router.New(handlers.NewHandler(svc), router.Options{
+		Webhooks: webhook.NewGuard(webhook.NewMemoryNonces(), time.Hour, webhook.Bot("t0ken")),
+	})
+	send := func(target, token, body, userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("X-Bot-Token", token)
+		}
+		if userID != "" {
+			req = req.WithContext(auth.WithUserID(req.Context(), userID))
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+	body := `{"pull_request_id":"pr-1","command":"/approve","actor":"u1"}`
+
+	// Naming an actor takes a verified bot; an anonymous caller is not one.
+	if rec := send("/api/v2/bot/command", "", body, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous actor status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if rec := send("/api/v2/bot/command", "", body, "u2"); rec.Code != http.StatusForbidden {
+		t.Errorf("other user's actor status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if rec := send("/webhooks/bot", "nope", body, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("wrong bot token status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if len(svc.RunBotCommandCalls()) != 0 {
+		t.Fatalf("RunBotCommand called for rejected requests: %+v", svc.RunBotCommandCalls())
+	}
+
+	if rec := send("/api/v2/bot/command", "", `{"pull_request_id":"pr-1","command":"/approve"}`, "u2"); rec.Code != http.StatusOK {
+		t.Fatalf("own command status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if rec := send("/webhooks/bot", "t0ken", body, ""); rec.Code != http.StatusOK {
+		t.Fatalf("bot status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	calls := svc.RunBotCommandCalls()
+	if len(calls) != 2 || calls[0].Params.Actor != "u2" || calls[1].Params.Actor != "u1" {
+		t.Errorf("RunBotCommand calls = %+v, want u2 then u1", calls)
+	}
+}
+
+// orderedSubscriber records subscriptions and cancellations in steps.
+type orderedSubscriber struct {
+	steps *[]string
+}
+
+func (s orderedSubscriber) Subscribe() (<-chan models.Event, func()) {
+	*s.steps = append(*s.steps, "subscribe")
+	return make(chan models.Event), func() { *s.steps = append(*s.steps, "cancel") }
+}
+
+func TestReviewerQueueSubscribesFirst(t *testing.T) {
+	var steps []string
+	svc := &handlersmock.ServiceMock{
+		GetUserReviewsFunc: func(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+			steps = append(steps, "snapshot")
+			return nil, &service.ServiceError{Code: models.ErrNotFound, Message: "user not found"}
+		},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/queue?user_id=u1", nil)
+	rec := httptest.NewRecorder()
+	handlers.NewHandler(svc).ReviewerQueue(orderedSubscriber{&steps})(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	// A change made while the snapshot is read must reach the queue.
+	if want := []string{"subscribe", "snapshot", "cancel"}; strings.Join(steps, ",") != strings.Join(want, ",") {
+		t.Errorf("steps = %v, want %v", steps, want)
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (h *Handler) GetUserIdentities(w http.ResponseWriter, r *http.Request) {
+	userID := param(r, "id", "user_id")
+	identities, err := h.service.GetUserIdentities(r.Context(), userID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserIdentitiesResponse{UserID: userID, Identities: identities})
+}
+
+func (h *Handler) SetUserIdentity(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetUserIdentityRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	identity, err := h.service.SetUserIdentity(r.Context(), &models.UserIdentity{
+		UserID:     req.UserID,
+		Provider:   req.Provider,
+		ExternalID: req.ExternalID,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserIdentityResponse{Identity: *identity})
+}
+
+func (h *Handler) DeleteUserIdentity(w http.ResponseWriter, r *http.Request) {
+	err := h.service.DeleteUserIdentity(r.Context(), param(r, "id", "user_id"), models.IdentityProvider(param(r, "provider", "provider")))
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ResolveIdentity(w http.ResponseWriter, r *http.Request) {
+	user, err := h.service.ResolveIdentity(r.Context(), models.IdentityProvider(param(r, "provider", "provider")), param(r, "external_id", "external_id"))
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserResponse{User: *user})
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/app/reqlog"
+	"github.com/Thorlik/avito_internship/internal/app/scheduler"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/persistence"
+)
+
+// Jobs reports the background job stats returned by stats and, when leader
+// is not nil, whether this instance is the one running the jobs.
+func (h *Handler) Jobs(stats func() []scheduler.JobStats, leader func() bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := dto.JobsResponse{Jobs: stats()}
+		if leader != nil {
+			isLeader := leader()
+			resp.Leader = &isLeader
+		}
+		h.writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+// Queries reports the storage call stats returned by stats.
+func (h *Handler) Queries(stats func() []persistence.QueryStats) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.writeJSON(w, http.StatusOK, dto.QueriesResponse{Queries: stats()})
+	}
+}
+
+// Requests lists the latest captured requests, newest first.
+func (h *Handler) Requests(entries func() []reqlog.Entry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.writeJSON(w, http.StatusOK, dto.RequestsResponse{Requests: entries()})
+	}
+}
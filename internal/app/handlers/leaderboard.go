@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (h *Handler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	teamName := param(r, "name", "team_name")
+	if teamName == "" {
+		h.writeError(w, models.ErrMissingParam, "team_name is required")
+		return
+	}
+
+	var days int
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			h.writeError(w, models.ErrValidation, "days must be an integer")
+			return
+		}
+		days = n
+	}
+
+	leaderboard, err := h.service.GetLeaderboard(r.Context(), teamName, days)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, leaderboard)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/Thorlik/avito_internship/internal/app/auth"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/i18n"
+)
+
+// ContentLanguageHeader names the language error messages are written in.
+const ContentLanguageHeader = "Content-Language"
+
+// Localize picks the language of error messages for every request: the one
+// Accept-Language prefers, else the locale of the authenticated caller's
+// team, else defaultLocale, else English. It is announced in the
+// Content-Language header, which is where writeError looks it up. Error
+// codes are the same in every language.
+func (h *Handler) Localize(defaultLocale models.Locale) func(http.Handler) http.Handler {
+	if defaultLocale == "" {
+		defaultLocale = models.LocaleEnglish
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			locale := i18n.Negotiate(r.Header.Get("Accept-Language"))
+			if userID, ok := auth.UserID(r.Context()); ok && locale == "" {
+				var err error
+				if locale, err = h.service.UserLocale(r.Context(), userID); err != nil {
+					log.Printf("Cannot look up locale of %s: %v", userID, err)
+				}
+			}
+			if locale == "" {
+				locale = defaultLocale
+			}
+			w.Header().Set(ContentLanguageHeader, string(locale))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
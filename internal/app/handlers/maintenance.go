@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// maintenanceRetryAfter is the Retry-After, in seconds, of requests refused
+// during maintenance.
+const maintenanceRetryAfter = "60"
+
+func (h *Handler) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+	m, err := h.service.GetMaintenance(r.Context())
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.MaintenanceResponse{Maintenance: *m})
+}
+
+func (h *Handler) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var m models.Maintenance
+	if !h.decode(w, r, &m) {
+		return
+	}
+
+	updated, err := h.service.SetMaintenance(r.Context(), &m)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.MaintenanceResponse{Maintenance: *updated})
+}
+
+func (h *Handler) GetSchema(w http.ResponseWriter, r *http.Request) {
+	status, err := h.service.GetSchema(r.Context())
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.SchemaResponse{Schema: *status})
+}
+
+// Maintenance refuses the requests writes reports as changing data with
+// 503 RETRY_LATER while the service is in maintenance mode. Reads are
+// served as usual.
+func (h *Handler) Maintenance(writes func(r *http.Request) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !writes(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			m := h.service.CurrentMaintenance(r.Context())
+			if !m.Enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			msg := "service is in read-only maintenance mode"
+			if m.Message != "" {
+				msg += ": " + m.Message
+			}
+			w.Header().Set("Retry-After", maintenanceRetryAfter)
+			h.writeError(w, models.ErrRetryLater, msg)
+		})
+	}
+}
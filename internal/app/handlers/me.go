@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Thorlik/avito_internship/internal/app/auth"
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// Unauthorized rejects a request whose credentials could not be verified.
+func (h *Handler) Unauthorized(w http.ResponseWriter, err error) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	h.writeError(w, models.ErrUnauthorized, err.Error())
+}
+
+func (h *Handler) MyReviews(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	prs, err := h.service.GetUserReviews(r.Context(), userID)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserReviewsResponse{
+		UserID:            userID,
+		PullRequestsShort: prs,
+	})
+}
+
+func (h *Handler) MyPullRequests(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	status := models.PullRequestStatus(r.URL.Query().Get("status"))
+	prs, err := h.service.ListPullRequests(r.Context(), models.PullRequestFilter{
+		Status:   status,
+		AuthorID: userID,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.PullRequestListResponse{PullRequests: prs})
+}
+
+func (h *Handler) MySetActive(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.currentUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.SetMyActiveRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	user, err := h.service.SetUserActive(r.Context(), userID, req.IsActive)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserResponse{User: *user})
+}
+
+// currentUser returns the user authenticated by the request's bearer token
+// and writes a 401 when there is none.
+func (h *Handler) currentUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID, ok := auth.UserID(r.Context())
+	if !ok {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		h.writeError(w, models.ErrUnauthorized, "bearer token required")
+	}
+	return userID, ok
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// MethodNotAllowed answers a request whose path exists but not for its
+// method, listing the allowed methods in the Allow header.
+func (h *Handler) MethodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	h.writeError(w, models.ErrMethodNotAllowed, "method not allowed")
+}
+
+func (h *Handler) NotFound(w http.ResponseWriter, r *http.Request) {
+	h.writeError(w, models.ErrNotFound, "route not found")
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// param returns the named path parameter of the matched route, falling back
+// to the query parameter the legacy endpoints use for the same value.
+func param(r *http.Request, name, legacyQuery string) string {
+	if v := chi.URLParam(r, name); v != "" {
+		return v
+	}
+	return r.URL.Query().Get(legacyQuery)
+}
+
+// bindPath copies path parameters into the string fields of dst tagged
+// `path:"<name>"`. RESTful routes carry identifiers in the URL that the
+// legacy routes send in the request body; a path value wins over the body.
+func bindPath(r *http.Request, dst interface{}) {
+	v := reflect.Indirect(reflect.ValueOf(dst))
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("path")
+		if name == "" || v.Field(i).Kind() != reflect.String {
+			continue
+		}
+		if value := chi.URLParam(r, name); value != "" {
+			v.Field(i).SetString(value)
+		}
+	}
+}
+
+// pageParams reads the limit and offset query parameters; absent ones are
+// left at zero for the service to default.
+func pageParams(query url.Values) (limit, offset int, err error) {
+	for name, dest := range map[string]*int{"limit": &limit, "offset": &offset} {
+		if v := query.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return 0, 0, errors.New(name + " must be a non-negative integer")
+			}
+			*dest = n
+		}
+	}
+	return limit, offset, nil
+}
+
+// periodParams reads the since and until query parameters as RFC 3339
+// timestamps; absent ones are returned as zero times.
+func periodParams(query url.Values) (since, until time.Time, err error) {
+	for name, dest := range map[string]*time.Time{"since": &since, "until": &until} {
+		if v := query.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return time.Time{}, time.Time{}, errors.New(name + " must be an RFC 3339 timestamp")
+			}
+			*dest = t
+		}
+	}
+	return since, until, nil
+}
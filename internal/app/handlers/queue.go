@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Thorlik/avito_internship/internal/app/auth"
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+const (
+	wsWriteTimeout = 10 * time.Second
+	wsPingInterval = 30 * time.Second
+	// wsPongTimeout closes connections that stop answering pings.
+	wsPongTimeout = 2 * wsPingInterval
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// ReviewerQueue upgrades to a WebSocket that pushes a reviewer's queue
+// changes: a snapshot first, then an update whenever they are assigned to a
+// PR, reassigned away from one, or one of their PRs is merged. An
+// authenticated caller may only watch their own queue.
+func (h *Handler) ReviewerQueue(events EventSubscriber) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := r.URL.Query().Get("user_id")
+		if authUser, ok := auth.UserID(r.Context()); ok {
+			if userID != "" && userID != authUser {
+				h.writeError(w, models.ErrForbidden, "cannot watch another user's queue")
+				return
+			}
+			userID = authUser
+		}
+		if userID == "" {
+			h.writeError(w, models.ErrMissingParam, "user_id is required")
+			return
+		}
+
+		// Subscribe before the snapshot is read so no change falls between.
+		ch, cancel := events.Subscribe()
+		defer cancel()
+
+		queue, err := h.service.GetUserReviews(r.Context(), userID)
+		if err != nil {
+			h.handleServiceError(w, err)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// Clients only send control frames; reading handles pongs and
+		// notices when the client goes away.
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		})
+		gone := make(chan struct{})
+		go func() {
+			defer close(gone)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		send := func(msg interface{}) bool {
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			return conn.WriteJSON(msg) == nil
+		}
+		if !send(dto.QueueSnapshotMessage{Type: dto.QueueSnapshot, Queue: queue}) {
+			return
+		}
+
+		ping := time.NewTicker(wsPingInterval)
+		defer ping.Stop()
+
+		for {
+			select {
+			case <-gone:
+				return
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+					return
+				}
+			case event, ok := <-ch:
+				if !ok {
+					msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+					conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteTimeout))
+					return
+				}
+				if update, ok := queueUpdate(event, userID); ok && !send(update) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// queueUpdate translates a lifecycle event into an update for userID's
+// queue. It reports false when the event does not concern the user.
+func queueUpdate(event models.Event, userID string) (dto.QueueUpdate, bool) {
+	update := dto.QueueUpdate{
+		PullRequestID:  event.PullRequestID,
+		PullRequest:    event.PullRequest,
+		ReplacedUserID: event.ReplacedUserID,
+		NewUserID:      event.NewUserID,
+	}
+
+	pr := event.PullRequest
+	reviews := pr != nil && (contains(pr.AssignedReviewers, userID) || contains(pr.ShadowReviewers, userID))
+
+	switch event.Type {
+	case models.EventPRCreated, models.EventPRReady, models.EventPRReopened:
+		update.Type = dto.QueueAssigned
+		return update, reviews
+	case models.EventReviewerReassigned, models.EventReviewerAdded, models.EventReviewerRemoved:
+		switch userID {
+		case event.NewUserID:
+			update.Type = dto.QueueAssigned
+			return update, true
+		case event.ReplacedUserID:
+			update.Type = dto.QueueUnassigned
+			return update, true
+		}
+	case models.EventPRUpdated:
+		update.Type = dto.QueueUpdated
+		return update, reviews
+	case models.EventPRMerged:
+		update.Type = dto.QueueMerged
+		return update, reviews || pr != nil && pr.AuthorID == userID
+	case models.EventPRClosed:
+		update.Type = dto.QueueClosed
+		return update, reviews || pr != nil && pr.AuthorID == userID
+	}
+	return update, false
+}
+
+func contains(items []string, item string) bool {
+	for _, it := range items {
+		if it == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+)
+
+func (h *Handler) RebalanceTeam(w http.ResponseWriter, r *http.Request) {
+	var req dto.RebalanceTeamRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	result, err := h.service.RebalanceTeam(r.Context(), req.TeamName, req.DryRun)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// PanicReporter forwards panics recovered from handlers to an error
+// tracker.
+type PanicReporter interface {
+	ReportPanic(r *http.Request, requestID string, value interface{}, stack []byte)
+}
+
+// Recover turns a handler panic into a 500 INTERNAL response carrying the
+// request ID, logs it with its stack trace and passes it on to reporter
+// when that is not nil. A panic after the response has begun can no longer
+// change it, so the connection is dropped instead.
+func (h *Handler) Recover(reporter PanicReporter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				// Handlers abort on purpose with this; it is not a bug.
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+
+				stack := debug.Stack()
+				requestID := w.Header().Get(RequestIDHeader)
+				log.Printf("request %s panicked: %v\n%s", requestID, p, stack)
+				if reporter != nil {
+					reporter.ReportPanic(r, requestID, p, stack)
+				}
+				if ww.Status() != 0 {
+					panic(http.ErrAbortHandler)
+				}
+				h.writeError(w, models.ErrInternal, "internal server error")
+			}()
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// ReplayEvents rebuilds derived data from the event log. Progress is
+// streamed as JSON lines, one per batch, the last one with done set; an
+// error after the stream started ends it with an error line instead.
+func (h *Handler) ReplayEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	opts := models.ReplayOptions{PullRequestID: query.Get("pull_request_id")}
+	if v := query.Get("dry_run"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			h.writeError(w, models.ErrValidation, "dry_run must be a boolean")
+			return
+		}
+		opts.DryRun = parsed
+	}
+
+	// A replay of a large log is meant to outlive the server's write
+	// timeout.
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Time{})
+
+	enc := json.NewEncoder(w)
+	started := false
+	progress := func(p models.ReplayProgress) {
+		if !started {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("X-Accel-Buffering", "no")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		enc.Encode(p)
+		rc.Flush()
+	}
+
+	_, err := h.service.ReplayEvents(r.Context(), opts, progress)
+	if err == nil {
+		return
+	}
+	if !started {
+		h.handleServiceError(w, err)
+		return
+	}
+	enc.Encode(models.ErrorResponse{Error: models.ErrorDetail{Code: models.ErrInternal, Message: "replay failed"}})
+	rc.Flush()
+}
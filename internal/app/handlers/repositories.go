@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (h *Handler) ListRepositories(w http.ResponseWriter, r *http.Request) {
+	repos, err := h.service.ListRepositories(r.Context(), r.URL.Query().Get("team"))
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.RepositoriesResponse{Repositories: repos})
+}
+
+func (h *Handler) CreateRepository(w http.ResponseWriter, r *http.Request) {
+	var repo models.Repository
+	if !h.decode(w, r, &repo) {
+		return
+	}
+
+	created, err := h.service.CreateRepository(r.Context(), &repo)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, dto.RepositoryResponse{Repository: *created})
+}
+
+func (h *Handler) GetRepository(w http.ResponseWriter, r *http.Request) {
+	repo, err := h.service.GetRepository(r.Context(), param(r, "name", "name"))
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.RepositoryResponse{Repository: *repo})
+}
+
+func (h *Handler) UpdateRepository(w http.ResponseWriter, r *http.Request) {
+	var repo models.Repository
+	if !h.decode(w, r, &repo) {
+		return
+	}
+
+	updated, err := h.service.UpdateRepository(r.Context(), &repo)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.RepositoryResponse{Repository: *updated})
+}
+
+func (h *Handler) DeleteRepository(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteRepository(r.Context(), param(r, "name", "name")); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) GetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	owners, err := h.service.GetCodeOwners(r.Context(), param(r, "name", "name"))
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.CodeOwnersResponse{CodeOwners: *owners})
+}
+
+func (h *Handler) SetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetCodeOwnersRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	owners, err := h.service.SetCodeOwners(r.Context(), req.Repository, req.Content)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.CodeOwnersResponse{CodeOwners: *owners})
+}
+
+func (h *Handler) PreviewCodeOwners(w http.ResponseWriter, r *http.Request) {
+	var req dto.PreviewCodeOwnersRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	preview, err := h.service.PreviewCodeOwners(r.Context(), req.Repository, req.Content, req.ChangedPaths)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.CodeOwnersPreviewResponse{Preview: *preview})
+}
@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"log"
 	"net/http"
 
+	"github.com/Thorlik/avito_internship/internal/app/validation"
 	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
 	"github.com/Thorlik/avito_internship/internal/domain/service"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/i18n"
 )
 
 func (h *Handler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -14,30 +21,109 @@ func (h *Handler) writeJSON(w http.ResponseWriter, status int, data interface{})
 	json.NewEncoder(w).Encode(data)
 }
 
-func (h *Handler) writeError(w http.ResponseWriter, status int, code models.ErrorCode, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(models.ErrorResponse{
-		Error: models.ErrorDetail{
-			Code:    code,
-			Message: message,
-		},
-	})
+// RequestIDHeader carries the request ID set by the router. Error responses
+// repeat it as request_id so clients can quote it when reporting problems.
+const RequestIDHeader = "X-Request-Id"
+
+// statusClientClosedRequest is the non-standard status nginx logs for
+// requests the client abandoned before the response.
+const statusClientClosedRequest = 499
+
+// errorStatus maps error codes to HTTP statuses. Codes missing here are
+// answered with 500.
+var errorStatus = map[models.ErrorCode]int{
+	models.ErrInvalidBody:        http.StatusBadRequest,
+	models.ErrMissingParam:       http.StatusBadRequest,
+	models.ErrValidation:         http.StatusBadRequest,
+	models.ErrTeamExists:         http.StatusBadRequest,
+	models.ErrInvalidReference:   http.StatusBadRequest,
+	models.ErrUnauthorized:       http.StatusUnauthorized,
+	models.ErrForbidden:          http.StatusForbidden,
+	models.ErrNotFound:           http.StatusNotFound,
+	models.ErrMethodNotAllowed:   http.StatusMethodNotAllowed,
+	models.ErrAlreadyExists:      http.StatusConflict,
+	models.ErrPRExists:           http.StatusConflict,
+	models.ErrPRMerged:           http.StatusConflict,
+	models.ErrPRClosed:           http.StatusConflict,
+	models.ErrNotAssigned:        http.StatusConflict,
+	models.ErrNoCandidate:        http.StatusConflict,
+	models.ErrNotApproved:        http.StatusConflict,
+	models.ErrNotEmpty:           http.StatusConflict,
+	models.ErrUndoExpired:        http.StatusConflict,
+	models.ErrNoSenior:           http.StatusConflict,
+	models.ErrOnHold:             http.StatusConflict,
+	models.ErrDeliveryNotDead:    http.StatusConflict,
+	models.ErrServiceUnavailable: http.StatusServiceUnavailable,
+	models.ErrRetryLater:         http.StatusServiceUnavailable,
+	models.ErrTimeout:            http.StatusGatewayTimeout,
+}
+
+func statusFor(code models.ErrorCode) int {
+	if status, ok := errorStatus[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, code models.ErrorCode, message string) {
+	h.writeErrorDetail(w, models.ErrorDetail{Code: code, Message: message})
+}
+
+func (h *Handler) writeErrorDetail(w http.ResponseWriter, detail models.ErrorDetail) {
+	detail.RequestID = w.Header().Get(RequestIDHeader)
+	locale := models.Locale(w.Header().Get(ContentLanguageHeader))
+	detail.Message = i18n.Translate(locale, detail.Message)
+	for i, field := range detail.Fields {
+		detail.Fields[i].Message = i18n.Translate(locale, field.Message)
+	}
+	h.writeJSON(w, statusFor(detail.Code), models.ErrorResponse{Error: detail})
+}
+
+// decode reads the JSON request body into dst, fills in path parameters and
+// validates the result, writing a 400 response and returning false when
+// either step fails. An empty body decodes as an empty object.
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil && !errors.Is(err, io.EOF) {
+		h.writeError(w, models.ErrInvalidBody, "invalid request body")
+		return false
+	}
+	bindPath(r, dst)
+
+	if err := validation.Validate(dst); err != nil {
+		var fields validation.Errors
+		errors.As(err, &fields)
+		h.writeErrorDetail(w, models.ErrorDetail{
+			Code:    models.ErrValidation,
+			Message: "request validation failed",
+			Fields:  fields,
+		})
+		return false
+	}
+	return true
 }
 
 func (h *Handler) handleServiceError(w http.ResponseWriter, err error) {
-	if serviceErr, ok := err.(*service.ServiceError); ok {
-		status := http.StatusInternalServerError
-		switch serviceErr.Code {
-		case models.ErrTeamExists:
-			status = http.StatusBadRequest
-		case models.ErrPRExists, models.ErrPRMerged, models.ErrNotAssigned, models.ErrNoCandidate:
-			status = http.StatusConflict
-		case models.ErrNotFound:
-			status = http.StatusNotFound
-		}
-		h.writeError(w, status, serviceErr.Code, serviceErr.Message)
-		return
+	var serviceErr *service.ServiceError
+	switch {
+	case errors.As(err, &serviceErr):
+		h.writeError(w, serviceErr.Code, serviceErr.Message)
+	case errors.Is(err, repository.ErrAlreadyExists):
+		h.writeError(w, models.ErrAlreadyExists, "resource already exists")
+	case errors.Is(err, repository.ErrUsernameTaken):
+		h.writeError(w, models.ErrAlreadyExists, "username is already taken")
+	case errors.Is(err, repository.ErrForeignKeyMissing):
+		h.writeError(w, models.ErrInvalidReference, "referenced resource does not exist")
+	case errors.Is(err, repository.ErrUnavailable):
+		w.Header().Set("Retry-After", "5")
+		h.writeError(w, models.ErrServiceUnavailable, "storage is temporarily unavailable")
+	case errors.Is(err, repository.ErrTimeout) || errors.Is(err, context.DeadlineExceeded):
+		h.writeError(w, models.ErrTimeout, "request deadline exceeded")
+	case errors.Is(err, context.Canceled):
+		// The client disconnected, which cancelled the request's queries.
+		// Nobody is left to read an error body.
+		w.WriteHeader(statusClientClosedRequest)
+	default:
+		log.Printf("request %s failed: %v", w.Header().Get(RequestIDHeader), err)
+		h.writeError(w, models.ErrInternal, "internal server error")
 	}
-	h.writeError(w, http.StatusInternalServerError, models.ErrNotFound, "internal server error")
 }
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (h *Handler) ListRoutingRules(w http.ResponseWriter, r *http.Request) {
+	teamName := param(r, "name", "team_name")
+	if teamName == "" {
+		h.writeError(w, models.ErrMissingParam, "team_name is required")
+		return
+	}
+
+	rules, err := h.service.GetRoutingRules(r.Context(), teamName)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.RoutingRulesResponse{TeamName: teamName, Rules: rules})
+}
+
+func (h *Handler) CreateRoutingRule(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateRoutingRuleRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	rule, err := h.service.CreateRoutingRule(r.Context(), &models.RoutingRule{
+		TeamName: req.TeamName,
+		Label:    req.Label,
+		UserID:   req.UserID,
+	})
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, dto.RoutingRuleResponse{Rule: *rule})
+}
+
+func (h *Handler) DeleteRoutingRule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(param(r, "id", "id"), 10, 64)
+	if err != nil {
+		h.writeError(w, models.ErrValidation, "id must be an integer")
+		return
+	}
+
+	if err := h.service.DeleteRoutingRule(r.Context(), id); err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) SetPullRequestLabels(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetLabelsRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+
+	pr, err := h.service.SetPullRequestLabels(r.Context(), req.PullRequestID, req.Labels)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.PullRequestResponse{PR: *pr})
+}
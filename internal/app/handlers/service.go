@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/service"
+)
+
+//go:generate moq -out handlersmock/service.go -pkg handlersmock . Service
+
+// Service is the business logic the handlers call. *service.Service
+// implements it; tests can substitute handlersmock.ServiceMock.
+type Service interface {
+	CreateTeam(ctx context.Context, team *models.Team) (*models.Team, error)
+	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error)
+	UpdateTeamSettings(ctx context.Context, settings *models.TeamSettings) (*models.TeamSettings, error)
+	RebalanceTeam(ctx context.Context, teamName string, dryRun bool) (*models.RebalanceResult, error)
+	GetLeaderboard(ctx context.Context, teamName string, days int) (*models.Leaderboard, error)
+
+	CreateRoutingRule(ctx context.Context, rule *models.RoutingRule) (*models.RoutingRule, error)
+	GetRoutingRules(ctx context.Context, teamName string) ([]models.RoutingRule, error)
+	DeleteRoutingRule(ctx context.Context, id int64) error
+	CreateRepositoryRule(ctx context.Context, rule *models.RepositoryRule) (*models.RepositoryRule, error)
+	GetRepositoryRules(ctx context.Context, teamName string) ([]models.RepositoryRule, error)
+	DeleteRepositoryRule(ctx context.Context, id int64) error
+	CreateExclusionRule(ctx context.Context, rule *models.ExclusionRule) (*models.ExclusionRule, error)
+	GetExclusionRules(ctx context.Context, teamName string) ([]models.ExclusionRule, error)
+	DeleteExclusionRule(ctx context.Context, id int64) error
+
+	SetUserActive(ctx context.Context, userID string, isActive bool) (*models.User, error)
+	SetUserMentee(ctx context.Context, userID string, isMentee bool) (*models.User, error)
+	SetUserReviewWeight(ctx context.Context, userID string, weight float64) (*models.User, error)
+	SetUserWorkingHours(ctx context.Context, userID, timezone, hours string) (*models.User, error)
+	SetUserSeniority(ctx context.Context, userID string, seniority models.Seniority) (*models.User, error)
+	SetUserManager(ctx context.Context, userID, managerID string) (*models.User, error)
+	SetUserIdentity(ctx context.Context, identity *models.UserIdentity) (*models.UserIdentity, error)
+	GetUserIdentities(ctx context.Context, userID string) ([]models.UserIdentity, error)
+	DeleteUserIdentity(ctx context.Context, userID string, provider models.IdentityProvider) error
+	ResolveIdentity(ctx context.Context, provider models.IdentityProvider, externalID string) (*models.User, error)
+	UserLocale(ctx context.Context, userID string) (models.Locale, error)
+	GetDigestPreferences(ctx context.Context, userID string) (*models.DigestPreferences, error)
+	SetDigestPreferences(ctx context.Context, prefs *models.DigestPreferences) (*models.DigestPreferences, error)
+	GetEscalations(ctx context.Context, teamName string, limit, offset int) ([]models.Escalation, error)
+	EraseUser(ctx context.Context, userID string) (*models.User, error)
+	LookupUser(ctx context.Context, username, email string) (*models.User, error)
+	SearchUsers(ctx context.Context, query string, limit, offset int) ([]models.User, error)
+	ListUsers(ctx context.Context, filter models.UserFilter) ([]models.UserSummary, error)
+	DeleteUser(ctx context.Context, userID string) error
+	RestoreUser(ctx context.Context, userID string) (*models.User, error)
+	ListTeams(ctx context.Context, filter models.TeamFilter) ([]models.TeamSummary, error)
+	DeleteTeam(ctx context.Context, teamName string) error
+	RestoreTeam(ctx context.Context, teamName string) (*models.Team, error)
+	GetUserReviews(ctx context.Context, userID string) ([]models.PullRequestShort, error)
+	GetUserHistory(ctx context.Context, filter models.ReviewHistoryFilter) ([]models.ReviewHistoryEntry, error)
+	GetUserStatistics(ctx context.Context, userID string, since, until time.Time) (*models.UserStatistics, error)
+
+	CreatePullRequest(ctx context.Context, params service.CreatePullRequestParams) (*models.PullRequest, error)
+	UpdatePullRequest(ctx context.Context, params service.UpdatePullRequestParams) (*models.PullRequest, error)
+	SimulateAssignment(ctx context.Context, params service.SimulateAssignmentParams) (*models.AssignmentSimulation, error)
+	RunBotCommand(ctx context.Context, params service.BotCommandParams) (*models.BotReply, error)
+	ApplyGitEvent(ctx context.Context, params service.GitEventParams) (*models.PullRequest, error)
+	SuggestReviewers(ctx context.Context, params service.SuggestReviewersParams) (*models.ReviewerSuggestions, error)
+	GetPullRequestDetails(ctx context.Context, prID string) (*models.PullRequestDetails, error)
+	ListPullRequests(ctx context.Context, filter models.PullRequestFilter) ([]models.PullRequestShort, error)
+	SearchPullRequests(ctx context.Context, filter models.PullRequestFilter) ([]models.PullRequestShort, error)
+	GetStalePullRequests(ctx context.Context) ([]models.StalePullRequest, error)
+	MarkPullRequestReady(ctx context.Context, prID string) (*models.PullRequest, error)
+	MergePullRequest(ctx context.Context, prID string) (*models.PullRequest, error)
+	ReassignReviewer(ctx context.Context, prID, oldReviewerID string) (*models.PullRequest, string, error)
+	UndoReassign(ctx context.Context, prID string) (*models.PullRequest, *models.AssignmentEvent, error)
+	SwapReviewers(ctx context.Context, params service.SwapReviewersParams) (*models.PullRequest, *models.PullRequest, error)
+	AddReviewer(ctx context.Context, prID, userID string) (*models.PullRequest, error)
+	RemoveReviewer(ctx context.Context, prID, userID string) (*models.PullRequest, error)
+	SetPullRequestLabels(ctx context.Context, prID string, labels []string) (*models.PullRequest, error)
+	SubmitReview(ctx context.Context, review *models.Review) (*models.Review, error)
+
+	GetStatistics(ctx context.Context) (*models.Statistics, error)
+	RefreshStatistics(ctx context.Context) (*models.Statistics, error)
+	GetWorkload(ctx context.Context) (*models.Workload, error)
+	ForecastReviewLoad(ctx context.Context, method models.ForecastMethod, weeks int) (*models.Forecast, error)
+	GetAnomalies(ctx context.Context) ([]models.Anomaly, error)
+	GetSeries(ctx context.Context, metric models.SeriesMetric, from, to time.Time, step time.Duration) ([]models.SeriesPoint, error)
+	GetAnnotations(ctx context.Context, kind models.AnnotationKind, from, to time.Time) ([]models.Annotation, error)
+
+	CreateRepository(ctx context.Context, repo *models.Repository) (*models.Repository, error)
+	GetRepository(ctx context.Context, name string) (*models.Repository, error)
+	ListRepositories(ctx context.Context, teamName string) ([]models.Repository, error)
+	UpdateRepository(ctx context.Context, repo *models.Repository) (*models.Repository, error)
+	DeleteRepository(ctx context.Context, name string) error
+	GetCodeOwners(ctx context.Context, repoName string) (*models.CodeOwners, error)
+	SetCodeOwners(ctx context.Context, repoName, content string) (*models.CodeOwners, error)
+	PreviewCodeOwners(ctx context.Context, repoName, content string, paths []string) (*models.CodeOwnersPreview, error)
+
+	ListFeatureFlags(ctx context.Context) ([]models.FeatureFlag, error)
+	SetFeatureFlag(ctx context.Context, flag *models.FeatureFlag) (*models.FeatureFlag, error)
+	DeleteFeatureFlag(ctx context.Context, name string) error
+	GetMaintenance(ctx context.Context) (*models.Maintenance, error)
+	SetMaintenance(ctx context.Context, m *models.Maintenance) (*models.Maintenance, error)
+	CurrentMaintenance(ctx context.Context) models.Maintenance
+	GetSchema(ctx context.Context) (*models.SchemaStatus, error)
+	SyncDirectory(ctx context.Context, dryRun bool) (*models.DirectorySyncReport, error)
+	ListWebhookDeliveries(ctx context.Context, filter models.DeliveryFilter) ([]models.WebhookDelivery, error)
+	RetryWebhookDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error)
+
+	Backup(ctx context.Context) (*models.Backup, error)
+	Restore(ctx context.Context, backup *models.Backup, dryRun bool) (*models.RestoreResult, error)
+	ReplayEvents(ctx context.Context, opts models.ReplayOptions, progress func(models.ReplayProgress)) (*models.ReplayProgress, error)
+}
+
+var _ Service = (*service.Service)(nil)
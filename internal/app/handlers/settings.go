@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (h *Handler) GetTeamSettings(w http.ResponseWriter, r *http.Request) {
+	teamName := param(r, "name", "team_name")
+	if teamName == "" {
+		h.writeError(w, models.ErrMissingParam, "team_name is required")
+		return
+	}
+
+	settings, err := h.service.GetTeamSettings(r.Context(), teamName)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.TeamSettingsResponse{Settings: *settings})
+}
+
+func (h *Handler) UpdateTeamSettings(w http.ResponseWriter, r *http.Request) {
+	var settings models.TeamSettings
+	if !h.decode(w, r, &settings) {
+		return
+	}
+
+	updated, err := h.service.UpdateTeamSettings(r.Context(), &settings)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.TeamSettingsResponse{Settings: *updated})
+}
+
+func (h *Handler) GetEscalations(w http.ResponseWriter, r *http.Request) {
+	teamName := param(r, "name", "team_name")
+	if teamName == "" {
+		h.writeError(w, models.ErrMissingParam, "team_name is required")
+		return
+	}
+	limit, offset, err := pageParams(r.URL.Query())
+	if err != nil {
+		h.writeError(w, models.ErrValidation, err.Error())
+		return
+	}
+
+	escalations, err := h.service.GetEscalations(r.Context(), teamName, limit, offset)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.EscalationsResponse{TeamName: teamName, Escalations: escalations})
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// LookupUser finds a user by username or email address.
+func (h *Handler) LookupUser(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	user, err := h.service.LookupUser(r.Context(), query.Get("username"), query.Get("email"))
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserResponse{User: *user})
+}
+
+func (h *Handler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, offset, err := pageParams(query)
+	if err != nil {
+		h.writeError(w, models.ErrValidation, err.Error())
+		return
+	}
+
+	users, err := h.service.SearchUsers(r.Context(), query.Get("q"), limit, offset)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UsersResponse{Users: users})
+}
+
+func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.UserFilter{
+		TeamName: query.Get("team_name"),
+		Role:     models.ReviewerRole(query.Get("role")),
+	}
+
+	if filter.Role != "" && filter.Role != models.RoleReviewer && filter.Role != models.RoleShadow {
+		h.writeError(w, models.ErrValidation, "role must be reviewer or shadow")
+		return
+	}
+	if v := query.Get("is_active"); v != "" {
+		isActive, err := strconv.ParseBool(v)
+		if err != nil {
+			h.writeError(w, models.ErrValidation, "is_active must be a boolean")
+			return
+		}
+		filter.IsActive = &isActive
+	}
+	var err error
+	filter.Limit, filter.Offset, err = pageParams(query)
+	if err != nil {
+		h.writeError(w, models.ErrValidation, err.Error())
+		return
+	}
+
+	users, err := h.service.ListUsers(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.UserListResponse{Users: users})
+}
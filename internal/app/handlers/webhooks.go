@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/app/webhook"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// WebhookRejected answers inbound webhooks that failed verification.
+func (h *Handler) WebhookRejected(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, webhook.ErrReplayed):
+		h.writeError(w, models.ErrAlreadyExists, err.Error())
+	case errors.Is(err, webhook.ErrTooLarge):
+		h.writeError(w, models.ErrInvalidBody, err.Error())
+	case errors.Is(err, webhook.ErrMissingSignature), errors.Is(err, webhook.ErrInvalidSignature), errors.Is(err, webhook.ErrStale),
+		errors.Is(err, webhook.ErrMissingDelivery):
+		h.writeError(w, models.ErrUnauthorized, err.Error())
+	default:
+		h.handleServiceError(w, err)
+	}
+}
+
+func (h *Handler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.DeliveryFilter{Status: models.DeliveryStatus(query.Get("status"))}
+	var err error
+	filter.Limit, filter.Offset, err = pageParams(query)
+	if err != nil {
+		h.writeError(w, models.ErrValidation, err.Error())
+		return
+	}
+
+	deliveries, err := h.service.ListWebhookDeliveries(r.Context(), filter)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.WebhookDeliveryListResponse{Deliveries: deliveries})
+}
+
+func (h *Handler) RetryWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(param(r, "id", "id"), 10, 64)
+	if err != nil {
+		h.writeError(w, models.ErrValidation, "id must be an integer")
+		return
+	}
+
+	delivery, err := h.service.RetryWebhookDelivery(r.Context(), id)
+	if err != nil {
+		h.handleServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, dto.WebhookDeliveryResponse{Delivery: *delivery})
+}
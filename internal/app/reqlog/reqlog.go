@@ -0,0 +1,239 @@
+// Package reqlog captures the bodies of HTTP requests and responses for
+// troubleshooting malformed client payloads. Captured exchanges go to the
+// log as JSON lines, to a ring buffer of the latest ones, or both.
+package reqlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redacted replaces the values of sensitive fields.
+const redacted = "[REDACTED]"
+
+// Config describes what is captured. Bodies are cut off after MaxBodySize
+// bytes. The string values of JSON fields and query parameters named in
+// Redact, in any case, are replaced before anything is kept. Log writes
+// every exchange to the log; BufferSize keeps the latest exchanges for
+// Entries, zero keeping none.
+type Config struct {
+	Log         bool
+	BufferSize  int
+	MaxBodySize int
+	Redact      []string
+}
+
+// Entry is one captured exchange. Bodies are kept as they were sent, only
+// redacted, so malformed JSON stays visible.
+type Entry struct {
+	Time         time.Time `json:"time"`
+	RequestID    string    `json:"request_id,omitempty"`
+	Method       string    `json:"method"`
+	URI          string    `json:"uri"`
+	Status       int       `json:"status"`
+	Duration     string    `json:"duration"`
+	RequestBody  string    `json:"request_body,omitempty"`
+	ResponseBody string    `json:"response_body,omitempty"`
+	// Truncated reports that a body was longer than MaxBodySize.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Recorder captures exchanges through Middleware.
+type Recorder struct {
+	cfg    Config
+	fields *regexp.Regexp
+	params map[string]bool
+	logger *log.Logger
+
+	mu     sync.Mutex
+	ring   []Entry
+	next   int
+	filled bool
+}
+
+func New(cfg Config, logger *log.Logger) *Recorder {
+	rec := &Recorder{cfg: cfg, params: make(map[string]bool), logger: logger}
+	var names []string
+	for _, name := range cfg.Redact {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, regexp.QuoteMeta(name))
+			rec.params[strings.ToLower(name)] = true
+		}
+	}
+	if len(names) > 0 {
+		// Matches on the raw text rather than decoded JSON, so truncated
+		// and malformed bodies are redacted too.
+		rec.fields = regexp.MustCompile(`("(?i:` + strings.Join(names, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*("|$)`)
+	}
+	if cfg.BufferSize > 0 {
+		rec.ring = make([]Entry, cfg.BufferSize)
+	}
+	return rec
+}
+
+// Middleware captures every exchange passing through it. Streams and
+// WebSockets are captured up to their upgrade only.
+func (rec *Recorder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		reqBody, truncated := rec.readBody(r)
+
+		cw := &captureWriter{ResponseWriter: w, status: http.StatusOK, max: rec.cfg.MaxBodySize}
+		next.ServeHTTP(cw, r)
+
+		rec.add(Entry{
+			Time:         start,
+			RequestID:    w.Header().Get("X-Request-Id"),
+			Method:       r.Method,
+			URI:          rec.redactURI(r.URL),
+			Status:       cw.status,
+			Duration:     time.Since(start).String(),
+			RequestBody:  rec.redact(reqBody),
+			ResponseBody: rec.redact(cw.body.Bytes()),
+			Truncated:    truncated || cw.truncated,
+		})
+	})
+}
+
+// Entries returns the buffered exchanges, newest first.
+func (rec *Recorder) Entries() []Entry {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	n := rec.next
+	if rec.filled {
+		n = len(rec.ring)
+	}
+	entries := make([]Entry, 0, n)
+	for i := 1; i <= n; i++ {
+		entries = append(entries, rec.ring[(rec.next-i+len(rec.ring))%len(rec.ring)])
+	}
+	return entries
+}
+
+func (rec *Recorder) add(e Entry) {
+	if rec.cfg.Log {
+		if line, err := json.Marshal(e); err == nil {
+			rec.logger.Printf("request %s", line)
+		}
+	}
+	if len(rec.ring) == 0 {
+		return
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.ring[rec.next] = e
+	rec.next++
+	if rec.next == len(rec.ring) {
+		rec.next = 0
+		rec.filled = true
+	}
+}
+
+// readBody reads up to MaxBodySize bytes of the request body and puts them
+// back in front of the rest for the handler.
+func (rec *Recorder) readBody(r *http.Request) ([]byte, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, false
+	}
+	head, err := io.ReadAll(io.LimitReader(r.Body, int64(rec.cfg.MaxBodySize)+1))
+	r.Body = readCloser{io.MultiReader(bytes.NewReader(head), r.Body), r.Body}
+	if err != nil {
+		return head, false
+	}
+	if len(head) > rec.cfg.MaxBodySize {
+		return head[:rec.cfg.MaxBodySize], true
+	}
+	return head, false
+}
+
+func (rec *Recorder) redact(body []byte) string {
+	if rec.fields == nil {
+		return string(body)
+	}
+	return rec.fields.ReplaceAllString(string(body), `${1}"`+redacted+`"`)
+}
+
+// redactURI hides the values of sensitive query parameters, such as the
+// access_token of WebSocket upgrades.
+func (rec *Recorder) redactURI(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.RequestURI()
+	}
+	query := u.Query()
+	for name := range query {
+		if rec.params[strings.ToLower(name)] {
+			query.Set(name, redacted)
+		}
+	}
+	c := *u
+	c.RawQuery = query.Encode()
+	return c.RequestURI()
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// captureWriter keeps the status and the first max bytes of the body on
+// their way to the client.
+type captureWriter struct {
+	http.ResponseWriter
+	status    int
+	max       int
+	body      bytes.Buffer
+	truncated bool
+	wrote     bool
+}
+
+func (c *captureWriter) WriteHeader(status int) {
+	if !c.wrote {
+		c.status = status
+		c.wrote = true
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *captureWriter) Write(p []byte) (int, error) {
+	c.wrote = true
+	if room := c.max - c.body.Len(); room < len(p) {
+		c.body.Write(p[:room])
+		c.truncated = true
+	} else {
+		c.body.Write(p)
+	}
+	return c.ResponseWriter.Write(p)
+}
+
+func (c *captureWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+func (c *captureWriter) Flush() {
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hands the connection over for a WebSocket upgrade, after which
+// nothing more is captured.
+func (c *captureWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := c.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	c.status = http.StatusSwitchingProtocols
+	return hj.Hijack()
+}
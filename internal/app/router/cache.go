@@ -0,0 +1,177 @@
+package router
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// cacheName identifies this service in Cache-Status headers.
+const cacheName = "pr-reviewer"
+
+// CacheOptions enables caching of successful GET responses on the routes
+// that opt in. A response is fresh for TTL; for StaleWhileRevalidate after
+// that it is still served while a single background request refreshes it.
+// A zero TTL disables the cache.
+type CacheOptions struct {
+	TTL                  time.Duration
+	StaleWhileRevalidate time.Duration
+}
+
+// responseCache holds the latest successful response of a route per query
+// string. Handlers behind it must answer the same to every caller, as the
+// cache runs after authentication but ignores who is asking.
+type responseCache struct {
+	opts CacheOptions
+
+	mu         sync.Mutex
+	entries    map[string]*cachedResponse
+	refreshing map[string]bool
+	// fill serializes misses, so callers arriving together while nothing
+	// usable is cached wait for one request instead of each making their own.
+	fill sync.Mutex
+}
+
+type cachedResponse struct {
+	status   int
+	header   http.Header
+	body     []byte
+	storedAt time.Time
+}
+
+func cacheResponses(opts CacheOptions) func(http.Handler) http.Handler {
+	if opts.TTL <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	c := &responseCache{
+		opts:       opts,
+		entries:    make(map[string]*cachedResponse),
+		refreshing: make(map[string]bool),
+	}
+	return c.middleware
+}
+
+func (c *responseCache) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := r.URL.RawQuery
+
+		if entry, age := c.lookup(key); entry != nil {
+			if age > c.opts.TTL {
+				c.revalidate(next, r, key)
+			}
+			// Stale responses have a negative ttl, as RFC 9211 has it.
+			ttl := math.Floor((c.opts.TTL - age).Seconds())
+			c.write(w, entry, "hit; ttl="+strconv.Itoa(int(ttl)))
+			return
+		}
+
+		c.fill.Lock()
+		defer c.fill.Unlock()
+		if entry, _ := c.lookup(key); entry != nil {
+			c.write(w, entry, "fwd=miss; collapsed")
+			return
+		}
+		if entry := c.fetch(next, r, key); entry.status != http.StatusOK {
+			c.write(w, entry, "fwd=miss")
+		} else {
+			c.write(w, entry, "fwd=miss; stored")
+		}
+	})
+}
+
+// lookup returns the entry of key with its age, or nil when there is none
+// or it is too stale to serve.
+func (c *responseCache) lookup(key string) (*cachedResponse, time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := c.entries[key]
+	if entry == nil {
+		return nil, 0
+	}
+	age := time.Since(entry.storedAt)
+	if age > c.opts.TTL+c.opts.StaleWhileRevalidate {
+		return nil, 0
+	}
+	return entry, age
+}
+
+// revalidate refreshes the entry of key in the background unless a refresh
+// is running already. The request is detached from the caller, who gets the
+// stale response without waiting.
+func (c *responseCache) revalidate(next http.Handler, r *http.Request, key string) {
+	c.mu.Lock()
+	if c.refreshing[key] {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[key] = true
+	c.mu.Unlock()
+
+	r = r.Clone(context.WithoutCancel(r.Context()))
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, key)
+			c.mu.Unlock()
+		}()
+		c.fetch(next, r, key)
+	}()
+}
+
+// fetch serves r with next and stores the response when it succeeded. The
+// response is returned either way.
+func (c *responseCache) fetch(next http.Handler, r *http.Request, key string) *cachedResponse {
+	if r.Method == http.MethodHead {
+		r = r.Clone(r.Context())
+		r.Method = http.MethodGet
+	}
+	rec := &recordingWriter{header: make(http.Header), status: http.StatusOK}
+	next.ServeHTTP(rec, r)
+
+	entry := &cachedResponse{status: rec.status, header: rec.header, body: rec.buf, storedAt: time.Now()}
+	if entry.status == http.StatusOK {
+		c.mu.Lock()
+		c.entries[key] = entry
+		c.mu.Unlock()
+	}
+	return entry
+}
+
+// write replays entry to w with cacheStatus, the parameters of its
+// Cache-Status header.
+func (c *responseCache) write(w http.ResponseWriter, entry *cachedResponse, cacheStatus string) {
+	for name, values := range entry.header {
+		w.Header()[name] = append([]string(nil), values...)
+	}
+	w.Header().Set("Cache-Status", cacheName+"; "+cacheStatus)
+	w.WriteHeader(entry.status)
+	w.Write(entry.body)
+}
+
+// recordingWriter captures a response without sending it, so it can be
+// stored and replayed.
+type recordingWriter struct {
+	header http.Header
+	status int
+	buf    []byte
+}
+
+func (rw *recordingWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *recordingWriter) WriteHeader(status int) {
+	rw.status = status
+}
+
+func (rw *recordingWriter) Write(p []byte) (int, error) {
+	rw.buf = append(rw.buf, p...)
+	return len(p), nil
+}
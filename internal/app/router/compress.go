@@ -0,0 +1,195 @@
+package router
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CompressOptions enables gzip/deflate response compression for the listed
+// media types. Bodies shorter than MinSize are sent uncompressed, as the
+// encoding overhead would outweigh the savings.
+type CompressOptions struct {
+	MinSize int
+	Types   []string
+}
+
+func compress(opts CompressOptions) func(http.Handler) http.Handler {
+	types := make(map[string]bool, len(opts.Types))
+	for _, t := range opts.Types {
+		types[strings.ToLower(strings.TrimSpace(t))] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoding := acceptedEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				encoding:       encoding,
+				minSize:        opts.MinSize,
+				types:          types,
+				status:         http.StatusOK,
+			}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// acceptedEncoding picks gzip over deflate among the encodings the client
+// accepts. It returns "" when neither is acceptable.
+func acceptedEncoding(header string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+				continue
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	for _, encoding := range []string{"gzip", "deflate"} {
+		if accepted[encoding] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+type flushWriteCloser interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// compressWriter holds back the start of the body until it reaches minSize,
+// then starts compressing. A response that ends or flushes before that is
+// sent as is.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+	types    map[string]bool
+
+	status  int
+	buf     []byte
+	decided bool
+	enc     flushWriteCloser
+}
+
+func (c *compressWriter) WriteHeader(status int) {
+	if !c.decided {
+		c.status = status
+	}
+}
+
+func (c *compressWriter) Write(p []byte) (int, error) {
+	if !c.decided {
+		c.buf = append(c.buf, p...)
+		if len(c.buf) < c.minSize {
+			return len(p), nil
+		}
+		if err := c.decide(true); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if c.enc != nil {
+		return c.enc.Write(p)
+	}
+	return c.ResponseWriter.Write(p)
+}
+
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// Hijack hands the connection over, e.g. for a WebSocket upgrade. Nothing
+// is compressed afterwards.
+func (c *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := c.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	c.decided = true
+	return hj.Hijack()
+}
+
+func (c *compressWriter) Flush() {
+	if !c.decided {
+		c.decide(false)
+	}
+	if c.enc != nil {
+		c.enc.Flush()
+	}
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// decide writes the header and the buffered body, compressing them when the
+// body is big enough and its type is on the allowlist.
+func (c *compressWriter) decide(bigEnough bool) error {
+	c.decided = true
+
+	h := c.Header()
+	eligible := c.eligible()
+	if eligible {
+		h.Add("Vary", "Accept-Encoding")
+	}
+	if eligible && bigEnough {
+		h.Set("Content-Encoding", c.encoding)
+		h.Del("Content-Length")
+		if c.encoding == "gzip" {
+			c.enc = gzip.NewWriter(c.ResponseWriter)
+		} else {
+			c.enc, _ = flate.NewWriter(c.ResponseWriter, flate.DefaultCompression)
+		}
+	}
+	c.ResponseWriter.WriteHeader(c.status)
+
+	if len(c.buf) == 0 {
+		return nil
+	}
+	var err error
+	if c.enc != nil {
+		_, err = c.enc.Write(c.buf)
+	} else {
+		_, err = c.ResponseWriter.Write(c.buf)
+	}
+	c.buf = nil
+	return err
+}
+
+func (c *compressWriter) eligible() bool {
+	switch c.status {
+	case http.StatusNoContent, http.StatusNotModified:
+		return false
+	}
+	if c.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(c.Header().Get("Content-Type"))
+	return err == nil && c.types[mediaType]
+}
+
+func (c *compressWriter) close() {
+	if !c.decided {
+		c.decide(false)
+	}
+	if c.enc != nil {
+		c.enc.Close()
+	}
+}
@@ -0,0 +1,51 @@
+package router
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// etag tags successful GET responses with a hash of their body and answers
+// 304 Not Modified when If-None-Match already names that version, so polling
+// clients skip unchanged payloads. The tag is weak because compression may
+// re-encode the body on the way out.
+func etag(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		bw := &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(bw, r)
+
+		body := bw.buf.Bytes()
+		if bw.status == http.StatusOK {
+			sum := sha256.Sum256(body)
+			tag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+			w.Header().Set("ETag", tag)
+
+			if etagMatches(r.Header.Get("If-None-Match"), tag) {
+				w.Header().Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		w.WriteHeader(bw.status)
+		w.Write(body)
+	})
+}
+
+// etagMatches applies the weak comparison If-None-Match calls for.
+func etagMatches(header, tag string) bool {
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,371 @@
+// Package router maps URLs to handlers: the RESTful routes under each API
+// version prefix, the unprefixed v1 routes and the legacy flat endpoints kept
+// as deprecated aliases.
+package router
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/Thorlik/avito_internship/internal/app/handlers"
+	"github.com/Thorlik/avito_internship/internal/app/reqlog"
+	"github.com/Thorlik/avito_internship/internal/app/scheduler"
+	"github.com/Thorlik/avito_internship/internal/app/ui"
+	"github.com/Thorlik/avito_internship/internal/app/webhook"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/persistence"
+)
+
+var methods = []string{
+	http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+type Options struct {
+	// Jobs reports background job stats for GET /jobs.
+	Jobs func() []scheduler.JobStats
+	// Leader reports for GET /jobs whether this instance runs the jobs.
+	Leader func() bool
+	// Queries reports storage call stats for GET /queries.
+	Queries func() []persistence.QueryStats
+	// V1Sunset deprecates the v1 format when set; see Version.
+	V1Sunset time.Time
+	// Auth, when set, wraps every route. It runs after the request ID is
+	// assigned so its error responses carry one, and before error messages
+	// are localized so the caller's team can choose their language.
+	Auth func(http.Handler) http.Handler
+	// DefaultLocale is the language of error messages for callers who do
+	// not ask for one and whose team has none; English when empty.
+	DefaultLocale models.Locale
+	// Panics, when set, receives the panics recovered from handlers besides
+	// the log.
+	Panics handlers.PanicReporter
+	// Maintenance refuses requests that change data while the service is
+	// in maintenance mode.
+	Maintenance bool
+	// Compress enables response compression when it lists any types.
+	Compress CompressOptions
+	// StatisticsCache caches GET /statistics, which dashboards poll.
+	StatisticsCache CacheOptions
+	// Capture, when set, wraps every route inside compression so it sees
+	// bodies as the handlers read and write them.
+	Capture func(http.Handler) http.Handler
+	// Requests reports captured exchanges for GET /debug/requests.
+	Requests func() []reqlog.Entry
+	// Events serves the SSE stream at /events/stream and reviewer queue
+	// WebSockets at /ws/queue when set.
+	Events handlers.EventSubscriber
+	// Webhooks verifies inbound webhooks; a Git host's webhook route is
+	// served only when it has a secret.
+	Webhooks *webhook.Guard
+}
+
+func New(h *handlers.Handler, opts Options) http.Handler {
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID, echoRequestID, h.Recover(opts.Panics))
+	if len(opts.Compress.Types) > 0 {
+		r.Use(compress(opts.Compress))
+	}
+	if opts.Capture != nil {
+		r.Use(opts.Capture)
+	}
+	if opts.Auth != nil {
+		r.Use(opts.Auth)
+	}
+	r.Use(h.Localize(opts.DefaultLocale))
+	if opts.Maintenance {
+		r.Use(h.Maintenance(changesData))
+	}
+	r.Use(middleware.GetHead)
+	r.NotFound(h.NotFound)
+	r.MethodNotAllowed(func(w http.ResponseWriter, req *http.Request) {
+		h.MethodNotAllowed(w, allowedMethods(r, req)...)
+	})
+
+	v1 := V1
+	v1.Sunset = opts.V1Sunset
+	// One cache serves every prefix: it sits inside the v1 translation.
+	statistics := cacheResponses(opts.StatisticsCache)
+
+	r.Route(V2.Prefix, func(r chi.Router) {
+		routes(r, h, opts, statistics)
+	})
+	r.Route(v1.Prefix, func(r chi.Router) {
+		r.Use(v1.Middleware)
+		routes(r, h, opts, statistics)
+	})
+
+	// Unprefixed routes predate versioning and keep answering in v1.
+	r.Group(func(r chi.Router) {
+		r.Use(v1.Middleware)
+		routes(r, h, opts, statistics)
+
+		r.Group(func(r chi.Router) {
+			r.Use(deprecated)
+			r.Post("/team/add", h.CreateTeam)
+			r.With(etag).Get("/team/get", h.GetTeam)
+			r.Get("/team/list", h.ListTeams)
+			r.Post("/team/delete", h.DeleteTeam)
+			r.Post("/team/restore", h.RestoreTeam)
+			r.Get("/team/routingRules", h.ListRoutingRules)
+			r.Post("/team/routingRules", h.CreateRoutingRule)
+			r.Delete("/team/routingRules", h.DeleteRoutingRule)
+			r.Get("/team/repositoryRules", h.ListRepositoryRules)
+			r.Post("/team/repositoryRules", h.CreateRepositoryRule)
+			r.Delete("/team/repositoryRules", h.DeleteRepositoryRule)
+			r.Get("/team/exclusions", h.ListExclusionRules)
+			r.Post("/team/exclusions", h.CreateExclusionRule)
+			r.Delete("/team/exclusions", h.DeleteExclusionRule)
+			r.Get("/team/settings", h.GetTeamSettings)
+			r.Post("/team/settings", h.UpdateTeamSettings)
+			r.Put("/team/settings", h.UpdateTeamSettings)
+			r.Post("/team/rebalance", h.RebalanceTeam)
+			r.Get("/team/leaderboard", h.GetLeaderboard)
+			r.Post("/users/setIsActive", h.SetUserActive)
+			r.Post("/users/setMentee", h.SetUserMentee)
+			r.Post("/users/setReviewWeight", h.SetUserReviewWeight)
+			r.Get("/users/getReview", h.GetUserReviews)
+			r.Get("/users/getHistory", h.GetUserHistory)
+			r.Get("/users/statistics", h.GetUserStatistics)
+			r.Post("/users/erase", h.EraseUser)
+			r.Post("/users/delete", h.DeleteUser)
+			r.Get("/users/get", h.LookupUser)
+			r.Get("/users/list", h.ListUsers)
+			r.Post("/users/restore", h.RestoreUser)
+			r.Post("/pullRequest/create", h.CreatePullRequest)
+			r.Post("/pullRequest/update", h.UpdatePullRequest)
+			r.With(etag).Get("/pullRequest/get", h.GetPullRequest)
+			r.Get("/pullRequest/list", h.ListPullRequests)
+			r.Get("/pullRequest/search", h.SearchPullRequests)
+			r.Post("/pullRequest/merge", h.MergePullRequest)
+			r.Post("/pullRequest/markReady", h.MarkPullRequestReady)
+			r.Post("/pullRequest/reassign", h.ReassignReviewer)
+			r.Post("/pullRequest/undoReassign", h.UndoReassign)
+			r.Post("/pullRequest/addReviewer", h.AddReviewer)
+			r.Post("/pullRequest/removeReviewer", h.RemoveReviewer)
+			r.Post("/pullRequest/review", h.SubmitReview)
+			r.Post("/pullRequest/setLabels", h.SetPullRequestLabels)
+			r.Get("/pullRequest/stale", h.GetStalePullRequests)
+			r.Post("/pullRequest/simulateAssignment", h.SimulateAssignment)
+			r.Post("/pullRequest/suggestReviewers", h.SuggestReviewers)
+		})
+	})
+
+	// Streams are not versioned: v1 translation buffers whole responses,
+	// so events always use the current field names.
+	if opts.Events != nil {
+		r.Get("/events/stream", h.EventStream(opts.Events))
+		r.Get("/ws/queue", h.ReviewerQueue(opts.Events))
+	}
+
+	// Backups mirror the database schema rather than the API format, so they
+	// are not versioned either: v1 field renaming would corrupt them.
+	r.Get("/admin/backup", h.Backup)
+	r.Post("/admin/restore", h.Restore)
+
+	// Replay progress is streamed, which v1 translation would hold back
+	// until the end.
+	r.Post("/admin/events/replay", h.ReplayEvents)
+
+	// Grafana's JSON datasource expects its own paths and field names.
+	r.Get("/grafana", h.GrafanaTest)
+	r.Post("/grafana/search", h.GrafanaSearch)
+	r.Post("/grafana/query", h.GrafanaQuery)
+	r.Post("/grafana/annotations", h.GrafanaAnnotations)
+
+	// Git hosts post their own payloads, which API versions do not describe.
+	if opts.Webhooks.Enabled("github") {
+		r.With(opts.Webhooks.Middleware("github", h.WebhookRejected)).Post("/webhooks/github", h.GitHubWebhook)
+	}
+	if opts.Webhooks.Enabled("gitlab") {
+		r.With(opts.Webhooks.Middleware("gitlab", h.WebhookRejected)).Post("/webhooks/gitlab", h.GitLabWebhook)
+	}
+	if opts.Webhooks.Enabled("bitbucket") {
+		r.With(opts.Webhooks.Middleware("bitbucket", h.WebhookRejected)).Post("/webhooks/bitbucket", h.BitbucketWebhook)
+	}
+	if opts.Webhooks.Enabled("gerrit") {
+		r.With(opts.Webhooks.Middleware("gerrit", h.WebhookRejected)).Post("/webhooks/gerrit", h.GerritWebhook)
+	}
+	if opts.Webhooks.Enabled("bot") {
+		r.With(opts.Webhooks.Middleware("bot", h.WebhookRejected)).Post("/webhooks/bot", h.BotWebhook)
+	}
+
+	// Captured bodies are shown as sent, which v1 translation would hide.
+	if opts.Requests != nil {
+		r.Get("/debug/requests", h.Requests(opts.Requests))
+	}
+
+	r.Get("/ui", http.RedirectHandler("/ui/", http.StatusMovedPermanently).ServeHTTP)
+	r.Handle("/ui/*", ui.Handler("/ui/"))
+
+	return r
+}
+
+func routes(r chi.Router, h *handlers.Handler, opts Options, statistics func(http.Handler) http.Handler) {
+	// Routes are registered with full paths rather than nested Route calls:
+	// chi matches a mount point itself for every method, which would defeat
+	// allowedMethods.
+	r.Get("/teams", h.ListTeams)
+	r.Post("/teams", h.CreateTeam)
+	r.With(etag).Get("/teams/{name}", h.GetTeam)
+	r.Delete("/teams/{name}", h.DeleteTeam)
+	r.Post("/teams/{name}/restore", h.RestoreTeam)
+	r.Get("/teams/{name}/settings", h.GetTeamSettings)
+	r.Put("/teams/{name}/settings", h.UpdateTeamSettings)
+	r.Get("/teams/{name}/escalations", h.GetEscalations)
+	r.Get("/teams/{name}/routing-rules", h.ListRoutingRules)
+	r.Post("/teams/{name}/routing-rules", h.CreateRoutingRule)
+	r.Delete("/teams/{name}/routing-rules/{id}", h.DeleteRoutingRule)
+	r.Get("/teams/{name}/repository-rules", h.ListRepositoryRules)
+	r.Post("/teams/{name}/repository-rules", h.CreateRepositoryRule)
+	r.Delete("/teams/{name}/repository-rules/{id}", h.DeleteRepositoryRule)
+	r.Get("/teams/{name}/exclusions", h.ListExclusionRules)
+	r.Post("/teams/{name}/exclusions", h.CreateExclusionRule)
+	r.Delete("/teams/{name}/exclusions/{id}", h.DeleteExclusionRule)
+	r.Post("/teams/{name}/rebalance", h.RebalanceTeam)
+	r.Get("/teams/{name}/leaderboard", h.GetLeaderboard)
+
+	r.Get("/users", h.ListUsers)
+	r.Get("/users/lookup", h.LookupUser)
+	r.Get("/users/search", h.SearchUsers)
+	r.Get("/users/{id}/reviews", h.GetUserReviews)
+	r.Get("/users/{id}/history", h.GetUserHistory)
+	r.Get("/users/{id}/statistics", h.GetUserStatistics)
+	r.Put("/users/{id}/active", h.SetUserActive)
+	r.Put("/users/{id}/mentee", h.SetUserMentee)
+	r.Put("/users/{id}/review-weight", h.SetUserReviewWeight)
+	r.Put("/users/{id}/working-hours", h.SetUserWorkingHours)
+	r.Put("/users/{id}/seniority", h.SetUserSeniority)
+	r.Put("/users/{id}/manager", h.SetUserManager)
+	r.Get("/users/{id}/identities", h.GetUserIdentities)
+	r.Put("/users/{id}/identities/{provider}", h.SetUserIdentity)
+	r.Delete("/users/{id}/identities/{provider}", h.DeleteUserIdentity)
+	r.Get("/identities/{provider}/{external_id}", h.ResolveIdentity)
+	r.Get("/users/{id}/digest", h.GetDigestPreferences)
+	r.Put("/users/{id}/digest", h.SetDigestPreferences)
+	r.Post("/users/{id}/erase", h.EraseUser)
+	r.Delete("/users/{id}", h.DeleteUser)
+	r.Post("/users/{id}/restore", h.RestoreUser)
+
+	r.Get("/pull-requests", h.ListPullRequests)
+	r.Post("/pull-requests", h.CreatePullRequest)
+	r.Get("/pull-requests/search", h.SearchPullRequests)
+	r.Get("/pull-requests/stale", h.GetStalePullRequests)
+	r.Post("/pull-requests/simulate", h.SimulateAssignment)
+	r.Post("/pull-requests/suggest-reviewers", h.SuggestReviewers)
+	r.Post("/pull-requests/swap-reviewers", h.SwapReviewers)
+	r.With(etag).Get("/pull-requests/{id}", h.GetPullRequest)
+	r.Patch("/pull-requests/{id}", h.UpdatePullRequest)
+	r.Post("/pull-requests/{id}/merge", h.MergePullRequest)
+	r.Post("/pull-requests/{id}/ready", h.MarkPullRequestReady)
+	r.Post("/pull-requests/{id}/reassign", h.ReassignReviewer)
+	r.Post("/pull-requests/{id}/undo-reassign", h.UndoReassign)
+	r.Post("/pull-requests/{id}/reviewers", h.AddReviewer)
+	r.Delete("/pull-requests/{id}/reviewers/{user_id}", h.RemoveReviewer)
+	r.Post("/pull-requests/{id}/reviews", h.SubmitReview)
+	r.Put("/pull-requests/{id}/labels", h.SetPullRequestLabels)
+
+	r.Get("/repositories", h.ListRepositories)
+	r.Post("/repositories", h.CreateRepository)
+	r.Get("/repositories/{name}", h.GetRepository)
+	r.Put("/repositories/{name}", h.UpdateRepository)
+	r.Delete("/repositories/{name}", h.DeleteRepository)
+	r.Get("/repositories/{name}/code-owners", h.GetCodeOwners)
+	r.Put("/repositories/{name}/code-owners", h.SetCodeOwners)
+	r.Post("/repositories/{name}/code-owners/preview", h.PreviewCodeOwners)
+
+	r.Post("/bot/command", h.BotCommand)
+
+	r.Get("/me/reviews", h.MyReviews)
+	r.Get("/me/prs", h.MyPullRequests)
+	r.Post("/me/setActive", h.MySetActive)
+
+	r.With(etag, statistics).Get("/statistics", h.GetStatistics)
+	r.Post("/statistics/refresh", h.RefreshStatistics)
+	r.Get("/statistics/workload", h.GetWorkload)
+	r.Get("/statistics/forecast", h.GetForecast)
+	r.Get("/statistics/anomalies", h.GetAnomalies)
+
+	r.Get("/admin/flags", h.ListFeatureFlags)
+	r.Put("/admin/flags/{name}", h.SetFeatureFlag)
+	r.Delete("/admin/flags/{name}", h.DeleteFeatureFlag)
+	r.Post("/admin/directory/sync", h.SyncDirectory)
+	r.Get("/admin/webhooks/deliveries", h.ListWebhookDeliveries)
+	r.Post("/admin/webhooks/deliveries/{id}/retry", h.RetryWebhookDelivery)
+	r.Get("/admin/maintenance", h.GetMaintenance)
+	r.Put("/admin/maintenance", h.SetMaintenance)
+	r.Get("/admin/schema", h.GetSchema)
+	if opts.Jobs != nil {
+		r.Get("/jobs", h.Jobs(opts.Jobs, opts.Leader))
+	}
+	if opts.Queries != nil {
+		r.Get("/queries", h.Queries(opts.Queries))
+	}
+}
+
+// readOnlyPosts are the paths, under any version prefix, of POST routes
+// that only compute an answer and keep working in maintenance mode.
+var readOnlyPosts = []string{
+	"/pull-requests/simulate",
+	"/pull-requests/suggest-reviewers",
+	"/code-owners/preview",
+	"/pullRequest/simulateAssignment",
+	"/pullRequest/suggestReviewers",
+	"/grafana/search",
+	"/grafana/query",
+	"/grafana/annotations",
+}
+
+// changesData reports whether the request may change data. The maintenance
+// mode itself can always be changed, or it could never be left.
+func changesData(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	if strings.HasSuffix(r.URL.Path, "/admin/maintenance") {
+		return false
+	}
+	if r.Method == http.MethodPost {
+		for _, path := range readOnlyPosts {
+			if strings.HasSuffix(r.URL.Path, path) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// echoRequestID returns the request ID to the client, which also makes it
+// available to handlers writing error responses.
+func echoRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(handlers.RequestIDHeader, middleware.GetReqID(r.Context()))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// deprecated marks responses of the legacy flat endpoints so clients can
+// detect that they should move to the RESTful routes.
+func deprecated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		next.ServeHTTP(w, r)
+	})
+}
+
+func allowedMethods(r chi.Routes, req *http.Request) []string {
+	var allowed []string
+	for _, m := range methods {
+		if r.Match(chi.NewRouteContext(), m, req.URL.Path) {
+			allowed = append(allowed, m)
+			if m == http.MethodGet {
+				allowed = append(allowed, http.MethodHead)
+			}
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,114 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Version describes how an API version's responses differ from the current
+// format. Handlers always produce the current format; older versions are
+// translated on the way out.
+type Version struct {
+	Prefix string
+	// Fields maps current JSON field names to the names this version uses.
+	Fields map[string]string
+	// Sunset marks the version deprecated when set: responses carry
+	// Deprecation, Sunset and a Link to the Successor prefix.
+	Sunset    time.Time
+	Successor string
+}
+
+// V1 is the original response format with camelCase timestamps.
+var V1 = Version{
+	Prefix: "/api/v1",
+	Fields: map[string]string{
+		"created_at": "createdAt",
+		"merged_at":  "mergedAt",
+	},
+	Successor: "/api/v2",
+}
+
+// V2 is the current response format.
+var V2 = Version{Prefix: "/api/v2"}
+
+func (v Version) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !v.Sunset.IsZero() {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Sunset", v.Sunset.UTC().Format(http.TimeFormat))
+			if v.Successor != "" {
+				w.Header().Add("Link", "<"+v.Successor+">; rel=\"successor-version\"")
+			}
+		}
+		if len(v.Fields) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		bw := &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(bw, r)
+
+		body := bw.buf.Bytes()
+		if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+			body = v.translate(body)
+			w.Header().Del("Content-Length")
+		}
+		w.WriteHeader(bw.status)
+		w.Write(body)
+	})
+}
+
+// translate renames fields in a JSON body. Bodies that fail to parse are
+// returned unchanged.
+func (v Version) translate(body []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return body
+	}
+
+	var out bytes.Buffer
+	if err := json.NewEncoder(&out).Encode(v.rename(doc)); err != nil {
+		return body
+	}
+	return out.Bytes()
+}
+
+func (v Version) rename(doc interface{}) interface{} {
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(doc))
+		for key, value := range doc {
+			if name, ok := v.Fields[key]; ok {
+				key = name
+			}
+			renamed[key] = v.rename(value)
+		}
+		return renamed
+	case []interface{}:
+		for i, value := range doc {
+			doc[i] = v.rename(value)
+		}
+		return doc
+	default:
+		return doc
+	}
+}
+
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func (b *bufferedWriter) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	return b.buf.Write(p)
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Leases stores named leases shared by every instance of the service. A
+// lease belongs to one holder until it expires or is released.
+type Leases interface {
+	// AcquireLease takes the lease for holder, or renews it when holder
+	// already has it, for ttl. It reports false while another holder's
+	// lease has not expired.
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
+type election struct {
+	leases Leases
+	name   string
+	holder string
+	ttl    time.Duration
+}
+
+// WithLeaderElection runs the jobs only on the instance holding the lease
+// name, so that several replicas sharing a database do not run them twice.
+// holder identifies this instance. The leader renews the lease every ttl/3
+// and stops its jobs as soon as a renewal fails; when the leader dies,
+// another instance takes over once the lease expires.
+func WithLeaderElection(leases Leases, name, holder string, ttl time.Duration) Option {
+	return func(s *Scheduler) {
+		s.election = &election{leases: leases, name: name, holder: holder, ttl: ttl}
+	}
+}
+
+// campaign tries to take the lease every ttl/3 and runs the jobs while it is
+// held. When ctx is done it stops them and releases the lease, so a
+// restarting leader hands over at once.
+func (s *Scheduler) campaign(ctx context.Context) {
+	e := s.election
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	// stepDown ends the current term and waits for its jobs to return; it
+	// is nil while this instance is not the leader.
+	var stepDown func()
+	for {
+		acquired, err := e.leases.AcquireLease(ctx, e.name, e.holder, e.ttl)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Scheduler lease %s: %v", e.name, err)
+		}
+		switch {
+		case acquired && stepDown == nil:
+			log.Printf("Scheduler lease %s acquired by %s, running jobs", e.name, e.holder)
+			stepDown = s.term(ctx)
+		case !acquired && stepDown != nil && ctx.Err() == nil:
+			log.Printf("Scheduler lease %s lost by %s, stopping jobs", e.name, e.holder)
+			stepDown()
+			stepDown = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if stepDown != nil {
+				stepDown()
+				releaseCtx, cancel := context.WithTimeout(context.Background(), e.ttl/3)
+				if err := e.leases.ReleaseLease(releaseCtx, e.name, e.holder); err != nil {
+					log.Printf("Scheduler lease %s: release: %v", e.name, err)
+				}
+				cancel()
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// term runs the jobs in the background until the returned function is
+// called, which stops them and waits for them to return.
+func (s *Scheduler) term(ctx context.Context) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.lead(ctx)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type lease struct {
+	holder  string
+	expires time.Time
+}
+
+// memoryLeases is a Leases shared by the schedulers of one test.
+type memoryLeases struct {
+	mu     sync.Mutex
+	leases map[string]lease
+}
+
+func newMemoryLeases() *memoryLeases {
+	return &memoryLeases{leases: make(map[string]lease)}
+}
+
+func (m *memoryLeases) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if l, ok := m.leases[name]; ok && l.holder != holder && now.Before(l.expires) {
+		return false, nil
+	}
+	m.leases[name] = lease{holder: holder, expires: now.Add(ttl)}
+	return true, nil
+}
+
+func (m *memoryLeases) ReleaseLease(ctx context.Context, name, holder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.leases[name].holder == holder {
+		delete(m.leases, name)
+	}
+	return nil
+}
+
+func (m *memoryLeases) holder(name string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.leases[name].holder
+}
+
+// waitFor polls cond until it holds or a second has passed.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestLeaderElection(t *testing.T) {
+	leases := newMemoryLeases()
+	var runs [2]atomic.Int64
+	schedulers := make([]*Scheduler, 2)
+	for i := range schedulers {
+		i := i
+		s := New(WithLeaderElection(leases, "scheduler", []string{"a", "b"}[i], 60*time.Millisecond))
+		s.Add("job", 10*time.Millisecond, func(ctx context.Context) error {
+			runs[i].Add(1)
+			return nil
+		})
+		schedulers[i] = s
+	}
+
+	schedulers[0].Start()
+	waitFor(t, "a to lead", schedulers[0].Leader)
+	schedulers[1].Start()
+	defer schedulers[1].Stop(context.Background())
+
+	waitFor(t, "a to run the job", func() bool { return runs[0].Load() >= 3 })
+	if schedulers[1].Leader() || runs[1].Load() != 0 {
+		t.Fatalf("b ran %d jobs while a held the lease", runs[1].Load())
+	}
+
+	if err := schedulers[0].Stop(context.Background()); err != nil {
+		t.Fatalf("stop a: %v", err)
+	}
+	if schedulers[0].Leader() {
+		t.Error("a still reports leading after Stop")
+	}
+	waitFor(t, "b to take over", func() bool { return leases.holder("scheduler") == "b" && runs[1].Load() > 0 })
+}
+
+func TestLeaderStepsDownWhenLeaseIsLost(t *testing.T) {
+	leases := newMemoryLeases()
+	var running atomic.Bool
+	s := New(WithLeaderElection(leases, "scheduler", "a", 60*time.Millisecond))
+	s.Add("job", time.Hour, func(ctx context.Context) error {
+		running.Store(true)
+		<-ctx.Done()
+		running.Store(false)
+		return ctx.Err()
+	})
+	s.Start()
+	defer s.Stop(context.Background())
+
+	waitFor(t, "the job to start", running.Load)
+
+	// Another instance takes the lease, e.g. after a network partition let
+	// a's lease expire.
+	leases.mu.Lock()
+	leases.leases["scheduler"] = lease{holder: "b", expires: time.Now().Add(time.Hour)}
+	leases.mu.Unlock()
+
+	waitFor(t, "a to step down", func() bool { return !s.Leader() && !running.Load() })
+}
@@ -0,0 +1,237 @@
+// Package scheduler runs the service's periodic background jobs.
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// JobFunc is one run of a job. It should return promptly once ctx is done.
+type JobFunc func(ctx context.Context) error
+
+// JobStats describes the runs of a job so far.
+type JobStats struct {
+	Name         string     `json:"name"`
+	Interval     string     `json:"interval"`
+	Running      bool       `json:"running"`
+	Runs         int64      `json:"runs"`
+	Failures     int64      `json:"failures"`
+	Skipped      int64      `json:"skipped"`
+	LastRun      *time.Time `json:"last_run,omitempty"`
+	LastDuration string     `json:"last_duration,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	NextRun      *time.Time `json:"next_run,omitempty"`
+}
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       JobFunc
+
+	mu    sync.Mutex
+	stats JobStats
+}
+
+// Scheduler runs every registered job on its own interval. A job never
+// overlaps with itself: the next run is scheduled after the previous one
+// finishes.
+type Scheduler struct {
+	election *election
+	paused   func(ctx context.Context) bool
+
+	mu      sync.Mutex
+	jobs    []*job
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+	leader  bool
+}
+
+// Option configures a Scheduler.
+type Option func(*Scheduler)
+
+// WithPause skips the runs that fall while paused reports true, such as
+// while the service is in read-only maintenance. A skipped run is counted in
+// Skipped and the job is tried again after its interval.
+func WithPause(paused func(ctx context.Context) bool) Option {
+	return func(s *Scheduler) {
+		s.paused = paused
+	}
+}
+
+func New(opts ...Option) *Scheduler {
+	s := &Scheduler{}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Add registers a job that runs once right after Start and then every
+// interval. Jobs with a non-positive interval are ignored, which lets callers
+// disable a job through configuration. Add must be called before Start.
+func (s *Scheduler) Add(name string, interval time.Duration, fn JobFunc) {
+	if interval <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, &job{
+		name:     name,
+		interval: interval,
+		fn:       fn,
+		stats:    JobStats{Name: name, Interval: interval.String()},
+	})
+}
+
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if s.election != nil {
+			s.campaign(ctx)
+		} else {
+			s.lead(ctx)
+		}
+	}()
+}
+
+// Stop cancels running jobs and waits for them to return or for ctx to be
+// done, whichever comes first.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Leader reports whether this instance is running the jobs: always once
+// started without leader election, otherwise while it holds the lease.
+func (s *Scheduler) Leader() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.leader
+}
+
+// Stats returns a snapshot of every job's stats ordered by name.
+func (s *Scheduler) Stats() []JobStats {
+	s.mu.Lock()
+	jobs := append([]*job(nil), s.jobs...)
+	s.mu.Unlock()
+
+	stats := make([]JobStats, 0, len(jobs))
+	for _, j := range jobs {
+		j.mu.Lock()
+		stats = append(stats, j.stats)
+		j.mu.Unlock()
+	}
+	sort.Slice(stats, func(i, k int) bool { return stats[i].Name < stats[k].Name })
+	return stats
+}
+
+// lead runs every job until ctx is done and waits for them to return.
+func (s *Scheduler) lead(ctx context.Context) {
+	s.setLeader(true)
+	defer s.setLeader(false)
+
+	var wg sync.WaitGroup
+	for _, j := range s.jobs {
+		wg.Add(1)
+		go func(j *job) {
+			defer wg.Done()
+			s.loop(ctx, j)
+		}(j)
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) setLeader(leader bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leader = leader
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.mu.Lock()
+			j.stats.NextRun = nil
+			j.mu.Unlock()
+			return
+		case <-timer.C:
+		}
+
+		s.run(ctx, j)
+
+		next := time.Now().Add(j.interval)
+		j.mu.Lock()
+		j.stats.NextRun = &next
+		j.mu.Unlock()
+		timer.Reset(j.interval)
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, j *job) {
+	if s.paused != nil && s.paused(ctx) {
+		j.mu.Lock()
+		j.stats.Skipped++
+		j.mu.Unlock()
+		return
+	}
+
+	start := time.Now()
+	j.mu.Lock()
+	j.stats.Running = true
+	j.stats.NextRun = nil
+	j.mu.Unlock()
+
+	err := j.fn(ctx)
+	if err != nil && ctx.Err() == nil {
+		log.Printf("Job %s failed: %v", j.name, err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.stats.Running = false
+	j.stats.Runs++
+	j.stats.LastRun = &start
+	j.stats.LastDuration = time.Since(start).String()
+	j.stats.LastError = ""
+	if err != nil {
+		j.stats.Failures++
+		j.stats.LastError = err.Error()
+	}
+}
@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPause(t *testing.T) {
+	var paused atomic.Bool
+	paused.Store(true)
+	var runs atomic.Int64
+	s := New(WithPause(func(ctx context.Context) bool { return paused.Load() }))
+	s.Add("job", 10*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	s.Start()
+	defer s.Stop(context.Background())
+
+	waitFor(t, "runs to be skipped", func() bool { return s.Stats()[0].Skipped >= 2 })
+	if n := runs.Load(); n != 0 {
+		t.Fatalf("job ran %d times while paused", n)
+	}
+
+	paused.Store(false)
+	waitFor(t, "the job to run once resumed", func() bool { return runs.Load() > 0 })
+	if stats := s.Stats()[0]; stats.Runs == 0 {
+		t.Errorf("stats after resuming = %+v, want the runs counted", stats)
+	}
+}
@@ -0,0 +1,129 @@
+'use strict';
+
+const state = { teams: new Set() };
+
+async function api(method, path, body) {
+  const resp = await fetch(path, {
+    method,
+    headers: body ? { 'Content-Type': 'application/json' } : {},
+    body: body ? JSON.stringify(body) : undefined,
+  });
+  const data = await resp.json().catch(() => ({}));
+  if (!resp.ok) {
+    const message = data.error ? `${data.error.code}: ${data.error.message}` : resp.statusText;
+    throw new Error(message);
+  }
+  return data;
+}
+
+function toast(message, isError) {
+  const el = document.getElementById('toast');
+  el.textContent = message;
+  el.className = isError ? 'error' : '';
+  el.hidden = false;
+  clearTimeout(toast.timer);
+  toast.timer = setTimeout(() => { el.hidden = true; }, 4000);
+}
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  Object.entries(attrs || {}).forEach(([k, v]) => {
+    if (k.startsWith('on')) node.addEventListener(k.slice(2), v);
+    else node.setAttribute(k, v);
+  });
+  children.flat().forEach((c) => node.append(c instanceof Node ? c : String(c)));
+  return node;
+}
+
+async function loadStats() {
+  const stats = await api('GET', '/statistics');
+  const fields = ['total_teams', 'total_users', 'active_users', 'total_prs', 'open_prs', 'merged_prs'];
+  document.getElementById('stats').replaceChildren(
+    ...fields.map((f) => el('div', { class: 'card' }, el('b', {}, stats[f]), f)),
+  );
+}
+
+async function discoverTeams() {
+  const workload = await api('GET', '/statistics/workload');
+  workload.users.forEach((u) => state.teams.add(u.team_name));
+}
+
+async function setActive(userId, isActive) {
+  try {
+    await api('POST', '/users/setIsActive', { user_id: userId, is_active: isActive });
+    toast(`${userId}: is_active=${isActive}`);
+    await refresh();
+  } catch (e) {
+    toast(e.message, true);
+  }
+}
+
+async function reassign(prId, userId) {
+  try {
+    const result = await api('POST', '/pullRequest/reassign', { pull_request_id: prId, old_user_id: userId });
+    toast(`${prId}: ${userId} → ${result.replaced_by}`);
+    await refresh();
+  } catch (e) {
+    toast(e.message, true);
+  }
+}
+
+async function renderTeams() {
+  const container = document.getElementById('teams');
+  const teams = await Promise.all([...state.teams].sort().map((name) => api('GET', `/team/get?team_name=${encodeURIComponent(name)}`)));
+  container.replaceChildren(...teams.map((team) => el('div', {},
+    el('h3', {}, team.team_name),
+    el('table', {},
+      el('thead', {}, el('tr', {}, el('th', {}, 'user_id'), el('th', {}, 'username'), el('th', {}, 'активен'), el('th', {}, ''))),
+      el('tbody', {}, team.members.map((m) => el('tr', { class: m.is_active ? '' : 'inactive' },
+        el('td', {}, m.user_id),
+        el('td', {}, m.username),
+        el('td', {}, m.is_active ? 'да' : 'нет'),
+        el('td', {}, el('button', { class: 'small', onclick: () => setActive(m.user_id, !m.is_active) },
+          m.is_active ? 'Деактивировать' : 'Активировать')),
+      ))),
+    ),
+  )));
+  return teams;
+}
+
+async function renderOpenPRs(teams) {
+  const members = teams.flatMap((t) => t.members.map((m) => m.user_id));
+  const reviews = await Promise.all(members.map((id) => api('GET', `/users/getReview?user_id=${encodeURIComponent(id)}`)));
+
+  const prs = new Map();
+  reviews.forEach((r) => r.pull_requests
+    .filter((pr) => pr.status === 'OPEN')
+    .forEach((pr) => {
+      const entry = prs.get(pr.pull_request_id) || { ...pr, reviewers: [] };
+      entry.reviewers.push(r.user_id);
+      prs.set(pr.pull_request_id, entry);
+    }));
+
+  document.querySelector('#prs tbody').replaceChildren(...[...prs.values()].map((pr) => el('tr', {},
+    el('td', {}, pr.pull_request_id),
+    el('td', {}, pr.pull_request_name),
+    el('td', {}, pr.author_id),
+    el('td', {}, pr.reviewers.map((id) => el('span', { class: 'reviewer' }, id,
+      el('button', { class: 'small', title: 'Переназначить', onclick: () => reassign(pr.pull_request_id, id) }, '↻')))),
+  )));
+}
+
+async function refresh() {
+  try {
+    await Promise.all([loadStats(), discoverTeams()]);
+    const teams = await renderTeams();
+    await renderOpenPRs(teams);
+  } catch (e) {
+    toast(e.message, true);
+  }
+}
+
+document.getElementById('refresh').addEventListener('click', refresh);
+document.getElementById('team-lookup').addEventListener('submit', async (event) => {
+  event.preventDefault();
+  state.teams.add(new FormData(event.target).get('team_name'));
+  await refresh();
+});
+
+refresh();
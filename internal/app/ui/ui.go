@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// Handler serves the embedded admin UI. It must be mounted under the given
+// prefix, e.g. mux.Handle("/ui/", ui.Handler("/ui/")).
+func Handler(prefix string) http.Handler {
+	root, err := fs.Sub(staticFiles, "static")
+	if err != nil {
+		panic(err)
+	}
+	return http.StripPrefix(prefix, http.FileServer(http.FS(root)))
+}
@@ -0,0 +1,179 @@
+// Package validation checks request structs against their `validate` tags.
+//
+// Supported rules, separated by commas:
+//
+//	required   strings must be non-blank, slices non-empty
+//	max=N      maximum string length, slice length or integer value
+//	min=N      minimum string length, slice length or integer value
+//	oneof=a b  the string must be one of the space separated values
+//	dive       apply validation to every element of a slice
+//
+// Rules other than required apply to the value a pointer field points to,
+// and not at all to nil ones, so optional fields can be pointers. Fields
+// are reported by their JSON name.
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+type Errors []models.FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate returns Errors listing every failed rule of v, which must be a
+// struct or a pointer to one, or nil when v is valid.
+func Validate(v interface{}) error {
+	var errs Errors
+	validateStruct(reflect.Indirect(reflect.ValueOf(v)), "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func validateStruct(v reflect.Value, prefix string, errs *Errors) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := prefix + jsonName(f)
+		tag := f.Tag.Get("validate")
+		field := v.Field(i)
+
+		if tag == "" {
+			if f.Anonymous || field.Kind() == reflect.Struct {
+				validateStruct(field, nestedPrefix(prefix, f), errs)
+			}
+			continue
+		}
+		validateField(field, name, tag, errs)
+	}
+}
+
+func validateField(v reflect.Value, name, tag string, errs *Errors) {
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		key, param, _ := strings.Cut(rule, "=")
+		if key == "dive" {
+			if v.Kind() == reflect.Slice {
+				for j := 0; j < v.Len(); j++ {
+					elemName := fmt.Sprintf("%s[%d]", name, j)
+					elem := reflect.Indirect(v.Index(j))
+					if elem.Kind() == reflect.Struct {
+						validateStruct(elem, elemName+".", errs)
+					} else if rest := strings.Join(rules[i+1:], ","); rest != "" {
+						validateField(elem, elemName, rest, errs)
+					}
+				}
+			}
+			return
+		}
+		if msg := check(v, key, param); msg != "" {
+			*errs = append(*errs, models.FieldError{Field: name, Rule: key, Message: msg})
+			if key == "required" {
+				return
+			}
+		}
+	}
+}
+
+func check(v reflect.Value, rule, param string) string {
+	if rule != "required" && v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch rule {
+	case "required":
+		if isEmpty(v) {
+			return "is required"
+		}
+	case "max", "min":
+		limit, err := strconv.Atoi(param)
+		if err != nil {
+			panic(fmt.Sprintf("validation: bad %s parameter %q", rule, param))
+		}
+		n, unit, ok := size(v)
+		if !ok || isEmpty(v) && v.Kind() != reflect.Int {
+			return ""
+		}
+		if rule == "max" && n > limit {
+			return fmt.Sprintf("must be at most %d%s", limit, unit)
+		}
+		if rule == "min" && n < limit {
+			return fmt.Sprintf("must be at least %d%s", limit, unit)
+		}
+	case "oneof":
+		if v.Kind() != reflect.String || v.String() == "" {
+			return ""
+		}
+		allowed := strings.Fields(param)
+		for _, a := range allowed {
+			if v.String() == a {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(allowed, ", ")
+	default:
+		panic("validation: unknown rule " + rule)
+	}
+	return ""
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
+
+func size(v reflect.Value) (int, string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(v.String()), " characters", true
+	case reflect.Slice:
+		return v.Len(), " items", true
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return int(v.Int()), "", true
+	}
+	return 0, "", false
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
+
+func nestedPrefix(prefix string, f reflect.StructField) string {
+	if f.Anonymous {
+		return prefix
+	}
+	return prefix + jsonName(f) + "."
+}
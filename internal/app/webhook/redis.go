@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisNoncePrefix = "pr-reviewer:webhook:"
+
+// RedisNonces shares delivery IDs between instances. When Redis cannot be
+// reached it falls back to the given store, which still catches replays
+// reaching this instance.
+type RedisNonces struct {
+	client   redis.UniversalClient
+	fallback NonceStore
+}
+
+func NewRedisNonces(client redis.UniversalClient, fallback NonceStore) *RedisNonces {
+	return &RedisNonces{client: client, fallback: fallback}
+}
+
+func (n *RedisNonces) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	fresh, err := n.client.SetNX(ctx, redisNoncePrefix+key, 1, ttl).Result()
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		log.Printf("Redis nonce %s unavailable, falling back to local nonces: %v", key, err)
+		return n.fallback.Claim(ctx, key, ttl)
+	}
+	return fresh, nil
+}
+
+func (n *RedisNonces) Release(ctx context.Context, key string) error {
+	if err := n.client.Del(ctx, redisNoncePrefix+key).Err(); err != nil {
+		log.Printf("Failed to release Redis nonce %s: %v", key, err)
+	}
+	return n.fallback.Release(ctx, key)
+}
@@ -0,0 +1,356 @@
+// Package webhook verifies that inbound webhooks come from the Git host they
+// claim to and are not replays of earlier deliveries.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+var (
+	ErrMissingSignature = errors.New("webhook signature is missing")
+	ErrInvalidSignature = errors.New("webhook signature is invalid")
+	// ErrStale rejects deliveries whose timestamp is outside the replay
+	// window.
+	ErrStale = errors.New("webhook timestamp is outside the replay window")
+	// ErrReplayed rejects a delivery that was accepted before.
+	ErrReplayed = errors.New("webhook delivery was received already")
+	// ErrMissingDelivery rejects a delivery without the ID its source
+	// always sends.
+	ErrMissingDelivery = errors.New("webhook delivery ID is missing")
+	ErrTooLarge        = errors.New("webhook body is too large")
+)
+
+// maxBodySize bounds the bodies read for verification.
+const maxBodySize = 5 << 20
+
+// Verifier checks that a webhook request was sent by the holder of a
+// secret.
+type Verifier interface {
+	Verify(header http.Header, body []byte) error
+}
+
+// HMACSHA256 verifies the hex HMAC-SHA256 of the body sent in Header after
+// Prefix, as GitHub and Bitbucket sign their webhooks.
+type HMACSHA256 struct {
+	Header string
+	Prefix string
+	Secret string
+}
+
+func (v HMACSHA256) Verify(header http.Header, body []byte) error {
+	value := header.Get(v.Header)
+	if value == "" {
+		return ErrMissingSignature
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(value, v.Prefix))
+	if err != nil || !strings.HasPrefix(value, v.Prefix) {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(v.Secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Token verifies a static token sent in Header, as GitLab authenticates its
+// webhooks.
+type Token struct {
+	Header string
+	Secret string
+}
+
+func (v Token) Verify(header http.Header, _ []byte) error {
+	value := header.Get(v.Header)
+	if value == "" {
+		return ErrMissingSignature
+	}
+	if subtle.ConstantTimeCompare([]byte(value), []byte(v.Secret)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Source is a sender of webhooks: how its requests are verified and which
+// headers identify and date its deliveries.
+type Source struct {
+	Name     string
+	Verifier Verifier
+	// DeliveryHeaders carry the sender's ID of a delivery, the first one
+	// present counting, which a delivery must have. The ID is not signed,
+	// so a delivery is told apart by its body instead: the same body is
+	// accepted once per replay window.
+	DeliveryHeaders []string
+	// TimestampHeader, when set, carries the Unix time a delivery was
+	// sent, which must be within the replay window of now.
+	TimestampHeader string
+	// Identify, when set, reads the ID and send time of a delivery from its
+	// body, for senders that put them nowhere else. The delivery must have
+	// both, is accepted once per replay window by that ID and must be sent
+	// within the window of now.
+	Identify func(body []byte) (id string, sent time.Time, ok bool)
+}
+
+// GitHub verifies the X-Hub-Signature-256 of GitHub webhooks.
+func GitHub(secret string) Source {
+	return Source{
+		Name:            "github",
+		Verifier:        HMACSHA256{Header: "X-Hub-Signature-256", Prefix: "sha256=", Secret: secret},
+		DeliveryHeaders: []string{"X-GitHub-Delivery"},
+	}
+}
+
+// GitLab verifies the secret token of GitLab webhooks.
+func GitLab(token string) Source {
+	return Source{
+		Name:            "gitlab",
+		Verifier:        Token{Header: "X-Gitlab-Token", Secret: token},
+		DeliveryHeaders: []string{"X-Gitlab-Event-UUID"},
+	}
+}
+
+// Bitbucket verifies the X-Hub-Signature of Bitbucket Cloud and Server
+// webhooks, which identify deliveries with different headers.
+func Bitbucket(secret string) Source {
+	return Source{
+		Name:            "bitbucket",
+		Verifier:        HMACSHA256{Header: "X-Hub-Signature", Prefix: "sha256=", Secret: secret},
+		DeliveryHeaders: []string{"X-Request-UUID", "X-Request-Id"},
+	}
+}
+
+// Gerrit verifies the token a bridge forwarding Gerrit's stream-events
+// sends. The events carry no delivery ID, and replaying an old one would
+// undo what happened since, so they are identified by what they report and
+// when Gerrit created them.
+func Gerrit(token string) Source {
+	return Source{
+		Name:     "gerrit",
+		Verifier: Token{Header: "X-Gerrit-Token", Secret: token},
+		Identify: identifyGerrit,
+	}
+}
+
+func identifyGerrit(body []byte) (string, time.Time, bool) {
+	var event struct {
+		Type           string `json:"type"`
+		EventCreatedOn int64  `json:"eventCreatedOn"`
+		Change         struct {
+			Number int64 `json:"number"`
+		} `json:"change"`
+		PatchSet struct {
+			Number int64 `json:"number"`
+		} `json:"patchSet"`
+		Reviewer struct {
+			Username string `json:"username"`
+			Email    string `json:"email"`
+		} `json:"reviewer"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil || event.Type == "" || event.EventCreatedOn == 0 {
+		return "", time.Time{}, false
+	}
+	// Reviewers of a change may be added in the same second.
+	id := fmt.Sprintf("%s:%d:%d:%d:%s:%s", event.Type, event.Change.Number, event.PatchSet.Number,
+		event.EventCreatedOn, event.Reviewer.Username, event.Reviewer.Email)
+	return id, time.Unix(event.EventCreatedOn, 0), true
+}
+
+// Bot verifies the token of a chat or PR comment bot forwarding slash
+// commands, which it runs for the users it names.
+func Bot(token string) Source {
+	return Source{
+		Name:     "bot",
+		Verifier: Token{Header: "X-Bot-Token", Secret: token},
+	}
+}
+
+// NonceStore remembers the delivery IDs a Guard accepted.
+type NonceStore interface {
+	// Claim records key for ttl and reports whether it was new.
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Release forgets key, so its delivery can be sent again.
+	Release(ctx context.Context, key string) error
+}
+
+// Guard verifies the webhooks of the configured sources.
+type Guard struct {
+	sources map[string]Source
+	nonces  NonceStore
+	window  time.Duration
+	now     func() time.Time
+}
+
+// NewGuard verifies webhooks of sources, accepting each delivery ID once
+// per window as recorded in nonces.
+func NewGuard(nonces NonceStore, window time.Duration, sources ...Source) *Guard {
+	g := &Guard{
+		sources: make(map[string]Source),
+		nonces:  nonces,
+		window:  window,
+		now:     time.Now,
+	}
+	for _, s := range sources {
+		g.sources[s.Name] = s
+	}
+	return g
+}
+
+// Enabled reports whether the named source is configured.
+func (g *Guard) Enabled(source string) bool {
+	if g == nil {
+		return false
+	}
+	_, ok := g.sources[source]
+	return ok
+}
+
+// Middleware verifies requests from the named source before next sees
+// them, and rejects them with onError otherwise. The body is read to verify
+// it and handed on unchanged. A delivery next does not answer with 2xx is
+// forgotten, so the sender's retry is accepted.
+func (g *Guard) Middleware(source string, onError func(w http.ResponseWriter, err error)) func(http.Handler) http.Handler {
+	s, ok := g.sources[source]
+	if !ok {
+		panic(fmt.Sprintf("webhook source %q is not configured", source))
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+			if err != nil {
+				onError(w, fmt.Errorf("read webhook body: %w", err))
+				return
+			}
+			if len(body) > maxBodySize {
+				onError(w, ErrTooLarge)
+				return
+			}
+			if err := s.Verifier.Verify(r.Header, body); err != nil {
+				onError(w, err)
+				return
+			}
+			key, err := g.deliveryKey(s, r.Header, body)
+			if err != nil {
+				onError(w, err)
+				return
+			}
+			if key != "" {
+				fresh, err := g.nonces.Claim(r.Context(), key, g.window)
+				if err != nil {
+					onError(w, err)
+					return
+				}
+				if !fresh {
+					onError(w, ErrReplayed)
+					return
+				}
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+			if status := ww.Status(); key != "" && (status < 200 || status >= 300) {
+				g.nonces.Release(context.WithoutCancel(r.Context()), key)
+			}
+		})
+	}
+}
+
+func (g *Guard) checkTimestamp(s Source, header http.Header) error {
+	if s.TimestampHeader == "" {
+		return nil
+	}
+	sec, err := strconv.ParseInt(header.Get(s.TimestampHeader), 10, 64)
+	if err != nil || !g.recent(time.Unix(sec, 0)) {
+		return ErrStale
+	}
+	return nil
+}
+
+// recent reports whether sent is within the replay window of now.
+func (g *Guard) recent(sent time.Time) bool {
+	age := g.now().Sub(sent)
+	return age <= g.window && age >= -g.window
+}
+
+// deliveryKey checks when the delivery was sent and returns its nonce: the
+// ID Identify reads, or else the hash of the body, which unlike the delivery
+// ID in a header the signature covers. It is "" for sources that identify
+// no deliveries.
+func (g *Guard) deliveryKey(s Source, header http.Header, body []byte) (string, error) {
+	if s.Identify != nil {
+		id, sent, ok := s.Identify(body)
+		if !ok {
+			return "", ErrMissingDelivery
+		}
+		if !g.recent(sent) {
+			return "", ErrStale
+		}
+		return s.Name + ":" + id, nil
+	}
+	if err := g.checkTimestamp(s, header); err != nil {
+		return "", err
+	}
+	if len(s.DeliveryHeaders) == 0 {
+		return "", nil
+	}
+	for _, name := range s.DeliveryHeaders {
+		if header.Get(name) != "" {
+			sum := sha256.Sum256(body)
+			return s.Name + ":" + hex.EncodeToString(sum[:]), nil
+		}
+	}
+	return "", ErrMissingDelivery
+}
+
+// MemoryNonces keeps delivery IDs in process. Behind several instances a
+// replay may reach one that has not seen the delivery; RedisNonces shares
+// them.
+type MemoryNonces struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+}
+
+func NewMemoryNonces() *MemoryNonces {
+	return &MemoryNonces{expires: make(map[string]time.Time)}
+}
+
+func (m *MemoryNonces) Claim(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for k, at := range m.expires {
+		if now.After(at) {
+			delete(m.expires, k)
+		}
+	}
+	if _, ok := m.expires[key]; ok {
+		return false, nil
+	}
+	m.expires[key] = now.Add(ttl)
+	return true, nil
+}
+
+func (m *MemoryNonces) Release(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.expires, key)
+	return nil
+}
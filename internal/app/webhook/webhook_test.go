@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// serve sends a request through the guard's middleware for source and
+// returns the status and the error it was rejected with, if any.
+func serve(g *Guard, source string, status int, header http.Header, body string) (int, error) {
+	var rejected error
+	onError := func(w http.ResponseWriter, err error) {
+		rejected = err
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ := io.ReadAll(r.Body)
+		if string(got) != body {
+			w.WriteHeader(http.StatusTeapot)
+			return
+		}
+		w.WriteHeader(status)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/"+source, strings.NewReader(body))
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	g.Middleware(source, onError)(next).ServeHTTP(rec, req)
+	return rec.Code, rejected
+}
+
+func TestGuardSignatures(t *testing.T) {
+	g := NewGuard(NewMemoryNonces(), time.Hour, GitHub("s3cret"), GitLab("token"))
+	body := `{"action":"opened"}`
+
+	tests := []struct {
+		name   string
+		source string
+		header http.Header
+		want   error
+	}{
+		{"valid hmac", "github", http.Header{"X-Hub-Signature-256": {sign("s3cret", body)}, "X-Github-Delivery": {"d-1"}}, nil},
+		{"missing hmac", "github", http.Header{}, ErrMissingSignature},
+		{"wrong secret", "github", http.Header{"X-Hub-Signature-256": {sign("other", body)}}, ErrInvalidSignature},
+		{"no prefix", "github", http.Header{"X-Hub-Signature-256": {strings.TrimPrefix(sign("s3cret", body), "sha256=")}}, ErrInvalidSignature},
+		{"valid token", "gitlab", http.Header{"X-Gitlab-Token": {"token"}, "X-Gitlab-Event-Uuid": {"e-1"}}, nil},
+		{"wrong token", "gitlab", http.Header{"X-Gitlab-Token": {"nope"}}, ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := serve(g, tt.source, http.StatusOK, tt.header, body)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && status != http.StatusOK {
+				t.Fatalf("status = %d, want body handed on unchanged", status)
+			}
+		})
+	}
+
+	if g.Enabled("bitbucket") || !g.Enabled("github") {
+		t.Fatal("only configured sources are enabled")
+	}
+	if (*Guard)(nil).Enabled("github") {
+		t.Fatal("a nil guard enables no source")
+	}
+}
+
+func TestGuardReplays(t *testing.T) {
+	g := NewGuard(NewMemoryNonces(), time.Hour, GitHub("s3cret"))
+	body := `{}`
+	header := http.Header{
+		"X-Hub-Signature-256": {sign("s3cret", body)},
+		"X-Github-Delivery":   {"d-1"},
+	}
+
+	// A delivery that failed is forgotten so the sender can retry it.
+	if status, err := serve(g, "github", http.StatusInternalServerError, header, body); err != nil || status != http.StatusInternalServerError {
+		t.Fatalf("first attempt: status %d, error %v", status, err)
+	}
+	if _, err := serve(g, "github", http.StatusOK, header, body); err != nil {
+		t.Fatalf("retry after failure: %v", err)
+	}
+	if _, err := serve(g, "github", http.StatusOK, header, body); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("replay: error = %v, want %v", err, ErrReplayed)
+	}
+}
+
+func TestGuardRequiresDelivery(t *testing.T) {
+	g := NewGuard(NewMemoryNonces(), time.Hour, GitHub("s3cret"))
+	body := `{"action":"closed"}`
+	signed := http.Header{"X-Hub-Signature-256": {sign("s3cret", body)}}
+
+	// A signed body without its delivery ID could be replayed forever.
+	if _, err := serve(g, "github", http.StatusOK, signed, body); !errors.Is(err, ErrMissingDelivery) {
+		t.Fatalf("missing delivery: error = %v, want %v", err, ErrMissingDelivery)
+	}
+
+	// The ID is not signed: a new one does not make a replay new.
+	for i, id := range []string{"d-1", "d-2"} {
+		header := http.Header{"X-Hub-Signature-256": signed["X-Hub-Signature-256"], "X-Github-Delivery": {id}}
+		_, err := serve(g, "github", http.StatusOK, header, body)
+		if want := []error{nil, ErrReplayed}[i]; !errors.Is(err, want) {
+			t.Errorf("delivery %s: error = %v, want %v", id, err, want)
+		}
+	}
+}
+
+func TestGuardGerritEvents(t *testing.T) {
+	g := NewGuard(NewMemoryNonces(), time.Hour, Gerrit("t0ken"))
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	g.now = func() time.Time { return now }
+	header := http.Header{"X-Gerrit-Token": {"t0ken"}}
+	event := func(typ string, createdOn time.Time, reviewer string) string {
+		return fmt.Sprintf(`{"type": %q, "eventCreatedOn": %d, "change": {"number": 4711}, "patchSet": {"number": 2}, "reviewer": {"username": %q}}`,
+			typ, createdOn.Unix(), reviewer)
+	}
+
+	for _, tt := range []struct {
+		name string
+		body string
+		want error
+	}{
+		{"event", event("reviewer-deleted", now, "bob"), nil},
+		{"replay", event("reviewer-deleted", now, "bob"), ErrReplayed},
+		{"replay reformatted", strings.ReplaceAll(event("reviewer-deleted", now, "bob"), ": ", ":"), ErrReplayed},
+		{"other reviewer", event("reviewer-deleted", now, "carol"), nil},
+		{"old event", event("change-abandoned", now.Add(-2*time.Hour), ""), ErrStale},
+		{"no creation time", `{"type": "change-abandoned", "change": {"number": 4711}}`, ErrMissingDelivery},
+	} {
+		if _, err := serve(g, "gerrit", http.StatusOK, header, tt.body); !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestGuardTimestamp(t *testing.T) {
+	source := GitHub("s3cret")
+	source.TimestampHeader = "X-Timestamp"
+	g := NewGuard(NewMemoryNonces(), time.Hour, source)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	g.now = func() time.Time { return now }
+
+	body := `{}`
+	for _, tt := range []struct {
+		sent time.Time
+		want error
+	}{
+		{now.Add(-time.Minute), nil},
+		{now.Add(-2 * time.Hour), ErrStale},
+		{now.Add(2 * time.Hour), ErrStale},
+	} {
+		header := http.Header{
+			"X-Hub-Signature-256": {sign("s3cret", body)},
+			"X-Timestamp":         {strconv.FormatInt(tt.sent.Unix(), 10)},
+			"X-Github-Delivery":   {"d-1"},
+		}
+		if _, err := serve(g, "github", http.StatusOK, header, body); !errors.Is(err, tt.want) {
+			t.Errorf("sent at %s: error = %v, want %v", tt.sent, err, tt.want)
+		}
+	}
+}
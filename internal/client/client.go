@@ -0,0 +1,183 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/app/dto"
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// APIError is returned when the service answers with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Code       models.ErrorCode
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("http %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("http %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// apiPrefix selects the API version whose response format matches models.
+const apiPrefix = "/api/v2"
+
+type Client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sends requests through hc instead of a default client with
+// a 30 second timeout.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+func New(baseURL, apiKey string, opts ...Option) *Client {
+	c := &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		http: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) CreateTeam(ctx context.Context, team models.Team) (*models.Team, error) {
+	var resp dto.TeamResponse
+	if err := c.do(ctx, http.MethodPost, "/teams", team, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Team, nil
+}
+
+func (c *Client) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	var team models.Team
+	if err := c.do(ctx, http.MethodGet, "/teams/"+url.PathEscape(teamName), nil, &team); err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (c *Client) SetUserActive(ctx context.Context, userID string, isActive bool) (*models.User, error) {
+	var resp dto.UserResponse
+	req := dto.SetUserActiveRequest{UserID: userID, IsActive: isActive}
+	if err := c.do(ctx, http.MethodPut, "/users/"+url.PathEscape(userID)+"/active", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.User, nil
+}
+
+func (c *Client) GetUserReviews(ctx context.Context, userID string) (*dto.UserReviewsResponse, error) {
+	var resp dto.UserReviewsResponse
+	if err := c.do(ctx, http.MethodGet, "/users/"+url.PathEscape(userID)+"/reviews", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) CreatePullRequest(ctx context.Context, req dto.CreatePullRequestRequest) (*models.PullRequest, error) {
+	var resp dto.PullRequestResponse
+	if err := c.do(ctx, http.MethodPost, "/pull-requests", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.PR, nil
+}
+
+func (c *Client) MarkPullRequestReady(ctx context.Context, prID string) (*models.PullRequest, error) {
+	var resp dto.PullRequestResponse
+	req := dto.MarkReadyRequest{PullRequestID: prID}
+	if err := c.do(ctx, http.MethodPost, "/pull-requests/"+url.PathEscape(prID)+"/ready", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.PR, nil
+}
+
+func (c *Client) MergePullRequest(ctx context.Context, prID string) (*models.PullRequest, error) {
+	var resp dto.PullRequestResponse
+	req := dto.MergePullRequestRequest{PullRequestID: prID}
+	if err := c.do(ctx, http.MethodPost, "/pull-requests/"+url.PathEscape(prID)+"/merge", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.PR, nil
+}
+
+func (c *Client) ReassignReviewer(ctx context.Context, prID, oldUserID string) (*dto.ReassignResponse, error) {
+	var resp dto.ReassignResponse
+	req := dto.ReassignReviewerRequest{PullRequestID: prID, OldUserID: oldUserID}
+	if err := c.do(ctx, http.MethodPost, "/pull-requests/"+url.PathEscape(prID)+"/reassign", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) GetStatistics(ctx context.Context) (*models.Statistics, error) {
+	var stats models.Statistics
+	if err := c.do(ctx, http.MethodGet, "/statistics", nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+apiPrefix+path, reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		var errResp models.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil && errResp.Error.Code != "" {
+			apiErr.Code = errResp.Error.Code
+			apiErr.Message = errResp.Error.Message
+		}
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
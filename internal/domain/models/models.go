@@ -1,23 +1,240 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type User struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	TeamName string `json:"team_name"`
 	IsActive bool   `json:"is_active"`
+	IsMentee bool   `json:"is_mentee"`
+	// ReviewWeight scales the share of open reviews the user is expected to
+	// carry: load-based selection compares open reviews divided by it.
+	ReviewWeight float64 `json:"review_weight"`
+	// Timezone is an IANA zone name and WorkingHours a local "HH:MM-HH:MM"
+	// range; see TeamSettings.PreferWorkingHours.
+	Timezone     string `json:"timezone,omitempty"`
+	WorkingHours string `json:"working_hours,omitempty"`
+	// Seniority is empty until set; see TeamSettings.RequireSenior.
+	Seniority Seniority `json:"seniority,omitempty"`
+	// ManagerID is the user's direct manager, if known; see
+	// TeamSettings.PeerReviewOnly.
+	ManagerID string `json:"manager_id,omitempty"`
+	// DirectoryManaged marks users the directory sync created or took over:
+	// it keeps their team, name and activity in line with the directory.
+	DirectoryManaged bool `json:"directory_managed,omitempty"`
+}
+
+// Seniority is a user's experience level.
+type Seniority string
+
+const (
+	SeniorityJunior Seniority = "junior"
+	SeniorityMiddle Seniority = "middle"
+	SenioritySenior Seniority = "senior"
+)
+
+func (s Seniority) Valid() bool {
+	switch s {
+	case SeniorityJunior, SeniorityMiddle, SenioritySenior:
+		return true
+	}
+	return false
+}
+
+// IdentityProvider is an external system users have accounts in.
+type IdentityProvider string
+
+const (
+	ProviderGitHub IdentityProvider = "github"
+	ProviderGitLab IdentityProvider = "gitlab"
+	ProviderSlack  IdentityProvider = "slack"
+	ProviderEmail  IdentityProvider = "email"
+	ProviderJira   IdentityProvider = "jira"
+	// ProviderBitbucket accounts are Bitbucket Cloud account IDs or
+	// Bitbucket Server usernames.
+	ProviderBitbucket IdentityProvider = "bitbucket"
+	ProviderGerrit    IdentityProvider = "gerrit"
+)
+
+func (p IdentityProvider) Valid() bool {
+	switch p {
+	case ProviderGitHub, ProviderGitLab, ProviderSlack, ProviderEmail, ProviderJira, ProviderBitbucket, ProviderGerrit:
+		return true
+	}
+	return false
+}
+
+// UserIdentity links a user to their account in an external system. A user
+// has at most one identity per provider, and an external ID belongs to at
+// most one user.
+type UserIdentity struct {
+	UserID     string           `json:"user_id"`
+	Provider   IdentityProvider `json:"provider"`
+	ExternalID string           `json:"external_id"`
+	UpdatedAt  *time.Time       `json:"updated_at,omitempty"`
+}
+
+// OutboxKind names the call an outbox message stands for.
+type OutboxKind string
+
+const (
+	// OutboxReviewTaskCreate files the review task of UserID.
+	OutboxReviewTaskCreate OutboxKind = "review_task.create"
+	// OutboxReviewTaskApprove moves UserID's task on after their approval.
+	OutboxReviewTaskApprove OutboxKind = "review_task.approve"
+	// OutboxReviewTaskMerge moves every task of the PR on after the merge.
+	OutboxReviewTaskMerge OutboxKind = "review_task.merge"
+	// OutboxReviewRequestAdd requests UserID's review on the Git host PR.
+	OutboxReviewRequestAdd OutboxKind = "review_request.add"
+	// OutboxReviewRequestRemove withdraws the request for UserID's review.
+	OutboxReviewRequestRemove OutboxKind = "review_request.remove"
+)
+
+// OutboxMessage is a call to an external system, stored with the change
+// that causes it and retried until delivered. Messages of a PR are
+// delivered in order.
+type OutboxMessage struct {
+	ID            int64      `json:"id"`
+	Kind          OutboxKind `json:"kind"`
+	PullRequestID string     `json:"pull_request_id"`
+	UserID        string     `json:"user_id,omitempty"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	// NextAttemptAt is nil once the message ran out of attempts.
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// ReviewTask links a reviewer of a PR to their task in the issue tracker.
+type ReviewTask struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+	IssueKey      string `json:"issue_key"`
+}
+
+// ReviewTaskEvent is what moves a review task on in the issue tracker.
+type ReviewTaskEvent string
+
+const (
+	ReviewTaskApproved ReviewTaskEvent = "approved"
+	ReviewTaskMerged   ReviewTaskEvent = "merged"
+)
+
+// DirectoryUser is a user as an external directory (LDAP, SCIM) lists
+// them. TeamName is empty when none of their groups maps to a team.
+type DirectoryUser struct {
+	UserID   string
+	Username string
+	TeamName string
+	Active   bool
+}
+
+// DirectoryConflictPolicy decides what the directory sync does with users
+// that exist locally but are not managed by it yet.
+type DirectoryConflictPolicy string
+
+const (
+	// ConflictDirectoryWins takes such users over and applies the
+	// directory's data to them.
+	ConflictDirectoryWins DirectoryConflictPolicy = "directory"
+	// ConflictKeepLocal leaves them as they are and reports the conflict.
+	ConflictKeepLocal DirectoryConflictPolicy = "local"
+)
+
+func (p DirectoryConflictPolicy) Valid() bool {
+	return p == ConflictDirectoryWins || p == ConflictKeepLocal
+}
+
+type DirectoryAction string
+
+const (
+	DirectoryCreateTeam     DirectoryAction = "create_team"
+	DirectoryCreateUser     DirectoryAction = "create_user"
+	DirectoryAdoptUser      DirectoryAction = "adopt_user"
+	DirectoryMoveUser       DirectoryAction = "move_user"
+	DirectoryRenameUser     DirectoryAction = "rename_user"
+	DirectoryReactivateUser DirectoryAction = "reactivate_user"
+	DirectoryDeactivateUser DirectoryAction = "deactivate_user"
+)
+
+// DirectoryChange is one step of reconciling the local users with the
+// directory. TeamName is the team the step applies to: the new team of a
+// moved user and the current one otherwise. Conflict marks steps on users
+// not managed by the sync yet; under ConflictKeepLocal they are Skipped.
+// Steps on users or into teams deleted locally are Deleted conflicts and
+// always Skipped: deleted users and teams come back only when restored.
+type DirectoryChange struct {
+	Action           DirectoryAction `json:"action"`
+	TeamName         string          `json:"team_name"`
+	UserID           string          `json:"user_id,omitempty"`
+	Username         string          `json:"username,omitempty"`
+	PreviousTeam     string          `json:"previous_team,omitempty"`
+	PreviousUsername string          `json:"previous_username,omitempty"`
+	Conflict         bool            `json:"conflict,omitempty"`
+	Skipped          bool            `json:"skipped,omitempty"`
+	Deleted          bool            `json:"deleted,omitempty"`
+}
+
+// DirectorySyncReport describes a directory sync. A dry run plans the same
+// changes without applying any; Applied then stays zero.
+type DirectorySyncReport struct {
+	DryRun         bool                    `json:"dry_run"`
+	ConflictPolicy DirectoryConflictPolicy `json:"conflict_policy"`
+	DirectoryUsers int                     `json:"directory_users"`
+	Changes        []DirectoryChange       `json:"changes"`
+	Applied        int                     `json:"applied"`
+	Conflicts      int                     `json:"conflicts"`
+	Skipped        int                     `json:"skipped"`
+	SyncedAt       time.Time               `json:"synced_at"`
 }
 
+// ErasedUsername replaces the username of an erased user.
+const ErasedUsername = "erased user"
+
 type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
+	UserID   string `json:"user_id" validate:"required,max=255"`
+	Username string `json:"username" validate:"required,max=255"`
 	IsActive bool   `json:"is_active"`
+	IsMentee bool   `json:"is_mentee"`
+	// ReviewWeight defaults to 1 when zero.
+	ReviewWeight float64 `json:"review_weight"`
 }
 
 type Team struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName string       `json:"team_name" validate:"required,max=255"`
+	Members  []TeamMember `json:"members" validate:"dive"`
+}
+
+// TeamSummary is a team in the team list: how big it is, how many of its
+// members' PRs await review and the settings that shape its assignment.
+type TeamSummary struct {
+	TeamName         string              `json:"team_name"`
+	Members          int                 `json:"members"`
+	ActiveMembers    int                 `json:"active_members"`
+	OpenPullRequests int                 `json:"open_pull_requests"`
+	Settings         TeamSettingsSummary `json:"settings"`
+}
+
+// UserSummary is a user in the user list, with the reviews of open PRs they
+// are assigned to.
+type UserSummary struct {
+	User
+	OpenReviews int `json:"open_reviews"`
+}
+
+// TeamSettingsSummary is the part of TeamSettings the team list shows.
+// Zero values mean the defaults, as in TeamSettings, except ReviewerCount,
+// which is the one in effect.
+type TeamSettingsSummary struct {
+	ReviewerCount       int    `json:"reviewer_count"`
+	AssignmentStrategy  string `json:"assignment_strategy"`
+	MaxOpenReviews      int    `json:"max_open_reviews"`
+	StaleThresholdHours int    `json:"stale_threshold_hours"`
+	Locale              Locale `json:"locale"`
 }
 
 type PullRequestStatus string
@@ -25,23 +242,718 @@ type PullRequestStatus string
 const (
 	StatusOpen   PullRequestStatus = "OPEN"
 	StatusMerged PullRequestStatus = "MERGED"
+	// StatusClosed PRs were declined on their Git host. They keep their
+	// reviewers but no longer count towards anyone's reviews.
+	StatusClosed PullRequestStatus = "CLOSED"
+)
+
+func (s PullRequestStatus) Valid() bool {
+	switch s {
+	case StatusOpen, StatusMerged, StatusClosed:
+		return true
+	}
+	return false
+}
+
+// PullRequestPriority ranks PRs. URGENT PRs may be assigned to reviewers at
+// their team's open review limit, come first in review queues and go stale
+// sooner.
+type PullRequestPriority string
+
+const (
+	PriorityLow    PullRequestPriority = "LOW"
+	PriorityNormal PullRequestPriority = "NORMAL"
+	PriorityUrgent PullRequestPriority = "URGENT"
 )
 
+func (p PullRequestPriority) Valid() bool {
+	switch p {
+	case PriorityLow, PriorityNormal, PriorityUrgent:
+		return true
+	}
+	return false
+}
+
 type PullRequest struct {
-	PullRequestID     string            `json:"pull_request_id"`
-	PullRequestName   string            `json:"pull_request_name"`
-	AuthorID          string            `json:"author_id"`
-	Status            PullRequestStatus `json:"status"`
-	AssignedReviewers []string          `json:"assigned_reviewers"`
-	CreatedAt         *time.Time        `json:"createdAt,omitempty"`
-	MergedAt          *time.Time        `json:"mergedAt,omitempty"`
+	PullRequestID   string              `json:"pull_request_id"`
+	PullRequestName string              `json:"pull_request_name"`
+	AuthorID        string              `json:"author_id"`
+	Status          PullRequestStatus   `json:"status"`
+	Priority        PullRequestPriority `json:"priority"`
+	// IsDraft PRs get no reviewers until they are marked ready.
+	IsDraft           bool     `json:"is_draft"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+	ShadowReviewers   []string `json:"shadow_reviewers"`
+	ChangedPaths      []string `json:"changed_paths,omitempty"`
+	Labels            []string `json:"labels"`
+	Repository        string   `json:"repository,omitempty"`
+	LinesChanged      int      `json:"lines_changed,omitempty"`
+	FilesChanged      int      `json:"files_changed,omitempty"`
+	// RequestedReviewers are the reviewers the author asked for; the rest
+	// of AssignedReviewers were picked automatically.
+	RequestedReviewers []string `json:"requested_reviewers,omitempty"`
+	// AssignmentReasons maps each current reviewer, regular or shadow, to
+	// why they were picked.
+	AssignmentReasons map[string]AssignmentReason `json:"assignment_reasons,omitempty"`
+	Reviews           []Review                    `json:"reviews,omitempty"`
+	Archived          bool                        `json:"archived,omitempty"`
+	CreatedAt         *time.Time                  `json:"created_at,omitempty"`
+	MergedAt          *time.Time                  `json:"merged_at,omitempty"`
 }
 
-type PullRequestShort struct {
+type ReviewState string
+
+const (
+	ReviewApproved         ReviewState = "APPROVED"
+	ReviewChangesRequested ReviewState = "CHANGES_REQUESTED"
+	ReviewCommented        ReviewState = "COMMENTED"
+)
+
+func (s ReviewState) Valid() bool {
+	switch s {
+	case ReviewApproved, ReviewChangesRequested, ReviewCommented:
+		return true
+	}
+	return false
+}
+
+type Review struct {
+	ID            int64       `json:"id"`
+	PullRequestID string      `json:"pull_request_id"`
+	ReviewerID    string      `json:"reviewer_id"`
+	State         ReviewState `json:"state"`
+	Comment       string      `json:"comment,omitempty"`
+	CreatedAt     *time.Time  `json:"created_at,omitempty"`
+}
+
+// TeamSettings overrides service defaults for one team. Zero values mean the
+// default applies: two reviewers, automatic strategy choice, no review limit,
+// the global stale threshold and the global notification webhook.
+type TeamSettings struct {
+	TeamName            string `json:"team_name" path:"name" validate:"required,max=255"`
+	ReviewerCount       int    `json:"reviewer_count" validate:"min=0,max=10"`
+	AssignmentStrategy  string `json:"assignment_strategy" validate:"max=64"`
+	MaxOpenReviews      int    `json:"max_open_reviews" validate:"min=0"`
+	StaleThresholdHours int    `json:"stale_threshold_hours" validate:"min=0"`
+	NotificationChannel string `json:"notification_channel" validate:"max=2048"`
+	// NotificationProvider is the chat NotificationChannel belongs to, which
+	// decides the message format; empty keeps the global default.
+	NotificationProvider NotificationProvider `json:"notification_provider" validate:"oneof=webhook slack msteams mattermost"`
+	// NotificationTemplate is a text/template rendering the message text
+	// from a NotificationView; empty keeps the standard text.
+	NotificationTemplate string `json:"notification_template" validate:"max=4096"`
+	// PreferWorkingHours makes the assignment strategy pick reviewers who
+	// are within their working hours, or about to start, before others.
+	PreferWorkingHours bool `json:"prefer_working_hours"`
+	// RequireSenior makes every PR of the team get at least one senior
+	// regular reviewer, on assignment and on reassignment.
+	RequireSenior bool `json:"require_senior"`
+	// PeerReviewOnly keeps the author's direct manager and direct reports
+	// out of automatic assignment, reassignment and rebalancing.
+	PeerReviewOnly bool `json:"peer_review_only"`
+	// Locale is the language of the team's notifications, and of error
+	// messages for its members who do not ask for one; empty keeps the
+	// service default.
+	Locale Locale `json:"locale" validate:"oneof=en ru"`
+	// RemindAfterHours, EscalateAfterHours and ReassignAfterHours are the
+	// escalation policy for reviewers who have not acted on a PR since
+	// being assigned: after so many hours they are reminded, their team
+	// lead is notified, and they are replaced. Zero skips a step.
+	RemindAfterHours   int        `json:"remind_after_hours" validate:"min=0"`
+	EscalateAfterHours int        `json:"escalate_after_hours" validate:"min=0"`
+	ReassignAfterHours int        `json:"reassign_after_hours" validate:"min=0"`
+	UpdatedAt          *time.Time `json:"updated_at,omitempty"`
+}
+
+// Locale is a language human-readable texts are translated to.
+type Locale string
+
+const (
+	LocaleEnglish Locale = "en"
+	LocaleRussian Locale = "ru"
+)
+
+func (l Locale) Valid() bool {
+	return l == LocaleEnglish || l == LocaleRussian
+}
+
+// Repository is a code repository PRs are opened in. A PR names it, or one
+// of its components as "name/component", in its repository field.
+// DefaultTeam is the team owning the repository, if any.
+type Repository struct {
+	Name        string             `json:"name" path:"name" validate:"required,max=255"`
+	DefaultTeam string             `json:"default_team,omitempty" validate:"max=255"`
+	Settings    RepositorySettings `json:"settings"`
+	CreatedAt   *time.Time         `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time         `json:"updated_at,omitempty"`
+}
+
+// RepositorySettings override, for the repository's PRs, the settings that
+// otherwise apply. Zero keeps the team's reviewer_count and the global
+// required approvals.
+type RepositorySettings struct {
+	ReviewerCount     int `json:"reviewer_count" validate:"min=0,max=10"`
+	RequiredApprovals int `json:"required_approvals" validate:"min=0,max=10"`
+}
+
+// CodeOwnersRule is one line of a repository's CODEOWNERS mapping: Owners
+// own the paths Pattern matches. As in CODEOWNERS files the last matching
+// rule wins, and a rule without owners leaves its paths unowned.
+type CodeOwnersRule struct {
+	Line    int      `json:"line"`
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+type CodeOwners struct {
+	Repository string           `json:"repository"`
+	Rules      []CodeOwnersRule `json:"rules"`
+}
+
+// PathOwners tells which rule owns Path; Pattern and Line are empty when no
+// rule matches it.
+type PathOwners struct {
+	Path    string   `json:"path"`
+	Pattern string   `json:"pattern,omitempty"`
+	Line    int      `json:"line,omitempty"`
+	Owners  []string `json:"owners"`
+}
+
+// CodeOwnersPreview resolves changed paths against a CODEOWNERS mapping.
+// Owners lists the owners of any of the paths, in order of first appearance.
+type CodeOwnersPreview struct {
+	Repository string       `json:"repository"`
+	Paths      []PathOwners `json:"paths"`
+	Owners     []string     `json:"owners"`
+}
+
+// FeatureFlag gates a risky behavior so it can be rolled out gradually. A
+// disabled flag is off for every team; an enabled one is on for the listed
+// Teams and for Percentage percent of the others, picked by a stable hash of
+// the flag and team names.
+type FeatureFlag struct {
+	Name       string     `json:"name" path:"name" validate:"required,max=64"`
+	Enabled    bool       `json:"enabled"`
+	Teams      []string   `json:"teams"`
+	Percentage int        `json:"percentage" validate:"min=0,max=100"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// Maintenance is the read-only mode of the service: while it is Enabled
+// requests that would change data are refused, with Message as the reason
+// when set.
+type Maintenance struct {
+	Enabled   bool       `json:"enabled"`
+	Message   string     `json:"message,omitempty" validate:"max=1000"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// SchemaMigration is a migration applied to the database.
+type SchemaMigration struct {
+	Version   int       `json:"version"`
+	Name      string    `json:"name"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// SchemaStatus compares the database schema with the version this instance
+// requires. A newer schema makes the instance read-only, since its writes
+// could corrupt data migrated for newer code.
+type SchemaStatus struct {
+	RequiredVersion int               `json:"required_version"`
+	CurrentVersion  int               `json:"current_version"`
+	ReadOnly        bool              `json:"read_only"`
+	Migrations      []SchemaMigration `json:"migrations"`
+}
+
+type RoutingRule struct {
+	ID        int64      `json:"id"`
+	TeamName  string     `json:"team_name"`
+	Label     string     `json:"label"`
+	UserID    string     `json:"user_id"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// RepositoryRule requires one of UserIDs to review every PR of the team
+// whose repository is Repository or a component under it ("repo/component").
+// A single user makes that user a required reviewer, e.g. a code owner.
+type RepositoryRule struct {
+	ID         int64      `json:"id"`
+	TeamName   string     `json:"team_name"`
+	Repository string     `json:"repository"`
+	UserIDs    []string   `json:"user_ids"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+}
+
+// ExclusionRule forbids ReviewerID from being assigned to PRs authored by
+// AuthorID, e.g. because of a conflict of interest.
+type ExclusionRule struct {
+	ID         int64      `json:"id"`
+	TeamName   string     `json:"team_name"`
+	ReviewerID string     `json:"reviewer_id"`
+	AuthorID   string     `json:"author_id"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+}
+
+type ReviewerRole string
+
+const (
+	RoleReviewer ReviewerRole = "reviewer"
+	RoleShadow   ReviewerRole = "shadow"
+)
+
+type AssignmentAction string
+
+const (
+	ActionAssigned   AssignmentAction = "assigned"
+	ActionReassigned AssignmentAction = "reassigned"
+	// ActionAdded and ActionRemoved record reviewers put on or taken off a
+	// PR by hand. A removal names the removed user in UserID.
+	ActionAdded   AssignmentAction = "added"
+	ActionRemoved AssignmentAction = "removed"
+)
+
+// AssignmentReason tells why the assignment pipeline picked a reviewer.
+type AssignmentReason string
+
+const (
+	ReasonPreferred   AssignmentReason = "preferred"
+	ReasonCodeOwner   AssignmentReason = "code_owner"
+	ReasonLabelRule   AssignmentReason = "label_rule"
+	ReasonLeastLoaded AssignmentReason = "least_loaded"
+	ReasonExpertise   AssignmentReason = "expertise"
+	ReasonRoundRobin  AssignmentReason = "round_robin"
+	ReasonRandom      AssignmentReason = "random"
+	ReasonReplacement AssignmentReason = "replacement"
+	ReasonRebalance   AssignmentReason = "rebalance"
+	// ReasonUndo restores the reviewer a reassignment replaced.
+	ReasonUndo AssignmentReason = "undo"
+	// ReasonManual marks reviewers added or removed through the API.
+	ReasonManual AssignmentReason = "manual"
+	// ReasonSwap marks a reviewer traded with one of another PR.
+	ReasonSwap AssignmentReason = "swap"
+)
+
+// AssignmentEvent records a reviewer being put on a PR, or taken off it by
+// hand. For reassignments ReplacedUserID is the reviewer that was taken off.
+type AssignmentEvent struct {
+	ID             int64            `json:"id"`
+	PullRequestID  string           `json:"pull_request_id"`
+	UserID         string           `json:"user_id"`
+	Role           ReviewerRole     `json:"role"`
+	Action         AssignmentAction `json:"action"`
+	Reason         AssignmentReason `json:"reason,omitempty"`
+	ReplacedUserID string           `json:"replaced_user_id,omitempty"`
+	CreatedAt      *time.Time       `json:"created_at,omitempty"`
+}
+
+// ReviewOutcome tells how a user's assignment to a PR ended.
+type ReviewOutcome string
+
+const (
+	// OutcomeOpen means the user is still on the PR and it is not merged.
+	OutcomeOpen       ReviewOutcome = "open"
+	OutcomeMerged     ReviewOutcome = "merged"
+	OutcomeClosed     ReviewOutcome = "closed"
+	OutcomeReassigned ReviewOutcome = "reassigned"
+	OutcomeRemoved    ReviewOutcome = "removed"
+)
+
+// ReviewHistoryEntry is one assignment of a user to a PR, taken from the
+// assignment history. ReplacedBy and ReassignedAt are set when the user was
+// later reassigned away; LastReview is the user's latest review of the PR.
+type ReviewHistoryEntry struct {
 	PullRequestID   string            `json:"pull_request_id"`
 	PullRequestName string            `json:"pull_request_name"`
 	AuthorID        string            `json:"author_id"`
+	Role            ReviewerRole      `json:"role"`
+	Reason          AssignmentReason  `json:"reason,omitempty"`
+	AssignedAt      *time.Time        `json:"assigned_at,omitempty"`
 	Status          PullRequestStatus `json:"status"`
+	Outcome         ReviewOutcome     `json:"outcome"`
+	LastReview      ReviewState       `json:"last_review,omitempty"`
+	ReplacedBy      string            `json:"replaced_by,omitempty"`
+	ReassignedAt    *time.Time        `json:"reassigned_at,omitempty"`
+	RemovedAt       *time.Time        `json:"removed_at,omitempty"`
+	MergedAt        *time.Time        `json:"merged_at,omitempty"`
+}
+
+// ReviewHistoryFilter selects a user's assignments made in [Since, Until);
+// zero times leave that end open.
+type ReviewHistoryFilter struct {
+	UserID string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+	Offset int
+}
+
+type StalePullRequest struct {
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	TeamName          string     `json:"team_name"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	StaleSince        *time.Time `json:"stale_since,omitempty"`
+}
+
+// EscalationAction is a step of a team's escalation policy.
+type EscalationAction string
+
+const (
+	EscalationReminded     EscalationAction = "reminded"
+	EscalationLeadNotified EscalationAction = "lead_notified"
+	EscalationReassigned   EscalationAction = "reassigned"
+)
+
+// IdleReview is a reviewer of an open PR who has not reviewed it since they
+// were assigned at AssignedAt. Taken lists the escalation steps taken on
+// that assignment so far; TeamName is the author's team, whose policy
+// applies.
+type IdleReview struct {
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	TeamName        string
+	UserID          string
+	AssignedAt      time.Time
+	Taken           []EscalationAction
+}
+
+// Escalation is the audit record of a step taken on reviewer UserID of a
+// PR. TargetID is the team lead notified or the reviewer who took over; it
+// is empty when the reviewer has no manager to notify.
+type Escalation struct {
+	ID            int64            `json:"id"`
+	PullRequestID string           `json:"pull_request_id"`
+	UserID        string           `json:"user_id"`
+	TeamName      string           `json:"team_name"`
+	Action        EscalationAction `json:"action"`
+	TargetID      string           `json:"target_id,omitempty"`
+	AssignedAt    time.Time        `json:"assigned_at"`
+	CreatedAt     *time.Time       `json:"created_at,omitempty"`
+}
+
+type NotificationEvent string
+
+const (
+	EventPullRequestStale NotificationEvent = "pull_request.stale"
+	EventReviewDigest     NotificationEvent = "review.digest"
+	// EventReviewReminder reminds a reviewer of a PR they have not acted on.
+	EventReviewReminder NotificationEvent = "review.reminder"
+	// EventReviewEscalated tells a reviewer's team lead about a PR the
+	// reviewer has not acted on.
+	EventReviewEscalated NotificationEvent = "review.escalated"
+	// EventAnomalyDetected reports a new finding of the anomaly check.
+	EventAnomalyDetected NotificationEvent = "statistics.anomaly"
+)
+
+// NotificationProvider is the kind of chat a notification channel posts to.
+type NotificationProvider string
+
+const (
+	// NotifyWebhook posts the notification itself as JSON.
+	NotifyWebhook    NotificationProvider = "webhook"
+	NotifySlack      NotificationProvider = "slack"
+	NotifyMSTeams    NotificationProvider = "msteams"
+	NotifyMattermost NotificationProvider = "mattermost"
+)
+
+func (p NotificationProvider) Valid() bool {
+	switch p {
+	case NotifyWebhook, NotifySlack, NotifyMSTeams, NotifyMattermost:
+		return true
+	}
+	return false
+}
+
+type Notification struct {
+	Event         NotificationEvent `json:"event"`
+	TeamName      string            `json:"team_name"`
+	PullRequestID string            `json:"pull_request_id"`
+	// Channel, Provider, Template and Locale come from the team's settings
+	// and say where the notification goes and how it reads there.
+	Channel    string               `json:"-"`
+	Provider   NotificationProvider `json:"-"`
+	Template   string               `json:"-"`
+	Locale     Locale               `json:"locale,omitempty"`
+	Recipients []string             `json:"recipients"`
+	Message    string               `json:"message"`
+	CreatedAt  time.Time            `json:"created_at"`
+	// Identities are the recipients' external identities, so that the
+	// receiving side can mention them in its own system.
+	Identities []UserIdentity `json:"identities,omitempty"`
+	// Digest is the summary a review.digest notification is about.
+	Digest *ReviewDigest `json:"digest,omitempty"`
+}
+
+// DigestPreferences opt a user in to the daily review digest, sent at
+// SendAt ("HH:MM") in their time zone, UTC without one. Channel and
+// Provider default to those of the user's team.
+type DigestPreferences struct {
+	UserID     string               `json:"user_id" path:"id" validate:"required,max=255"`
+	Enabled    bool                 `json:"enabled"`
+	SendAt     string               `json:"send_at" validate:"max=5"`
+	Channel    string               `json:"channel" validate:"max=2048"`
+	Provider   NotificationProvider `json:"provider" validate:"oneof=webhook slack msteams mattermost"`
+	LastSentAt *time.Time           `json:"last_sent_at,omitempty"`
+}
+
+// ReviewDigest sums up a reviewer's queue: their open reviews, those of
+// stale PRs, and the assignments made since the previous digest.
+type ReviewDigest struct {
+	UserID        string               `json:"user_id"`
+	OpenReviews   []PullRequestShort   `json:"open_reviews"`
+	Overdue       []PullRequestShort   `json:"overdue"`
+	NewlyAssigned []ReviewHistoryEntry `json:"newly_assigned"`
+	Since         time.Time            `json:"since"`
+}
+
+// NotificationView is what a team's notification template renders: the
+// notification, with its recipients mentioned the way the channel's chat
+// mentions people.
+type NotificationView struct {
+	Notification
+	Mentions []string
+}
+
+// DeliveryStatus is where a webhook delivery stands.
+type DeliveryStatus string
+
+const (
+	// DeliveryPending is waiting for its next attempt.
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliveryDelivered was accepted by the receiver.
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDead ran out of attempts and is only retried by hand.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+func (s DeliveryStatus) Valid() bool {
+	switch s {
+	case DeliveryPending, DeliveryDelivered, DeliveryDead:
+		return true
+	}
+	return false
+}
+
+// WebhookDelivery is a notification rendered for its webhook, kept until
+// the receiver accepted it. Every attempt posts the same Payload with the
+// delivery's ID, so receivers can drop duplicates.
+type WebhookDelivery struct {
+	ID            int64             `json:"id"`
+	Event         NotificationEvent `json:"event"`
+	TeamName      string            `json:"team_name,omitempty"`
+	PullRequestID string            `json:"pull_request_id,omitempty"`
+	URL           string            `json:"url"`
+	Payload       json.RawMessage   `json:"payload"`
+	Status        DeliveryStatus    `json:"status"`
+	Attempts      int               `json:"attempts"`
+	// LastStatus is the HTTP status of the last attempt, zero when it got
+	// no response.
+	LastStatus    int        `json:"last_status,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
+
+// DeliveryFilter selects webhook deliveries, newest first. An empty Status
+// matches all of them.
+type DeliveryFilter struct {
+	Status DeliveryStatus
+	Limit  int
+	Offset int
+}
+
+type EventType string
+
+const (
+	EventPRCreated          EventType = "pr.created"
+	EventPRMerged           EventType = "pr.merged"
+	EventPRClosed           EventType = "pr.closed"
+	EventPRReopened         EventType = "pr.reopened"
+	EventPRReady            EventType = "pr.ready"
+	EventPRUpdated          EventType = "pr.updated"
+	EventReviewerReassigned EventType = "reviewer.reassigned"
+	EventReviewerAdded      EventType = "reviewer.added"
+	EventReviewerRemoved    EventType = "reviewer.removed"
+)
+
+// Event describes a PR lifecycle change published on the internal event
+// bus. For reassignments NewUserID replaced ReplacedUserID; a reviewer added
+// by hand is NewUserID and one removed by hand ReplacedUserID. Assignments
+// are the history entries the change recorded, which the event log replays.
+// Changes are the fields an update of the PR changed.
+type Event struct {
+	ID             uint64            `json:"id"`
+	Type           EventType         `json:"type"`
+	PullRequestID  string            `json:"pull_request_id"`
+	PullRequest    *PullRequest      `json:"pull_request,omitempty"`
+	ReplacedUserID string            `json:"replaced_user_id,omitempty"`
+	NewUserID      string            `json:"new_user_id,omitempty"`
+	Assignments    []AssignmentEvent `json:"assignments,omitempty"`
+	Changes        []FieldChange     `json:"changes,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+// FieldChange is a field of a PR with its value before and after a change,
+// named as in the API.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// ReplayOptions limits an event replay to one PR when PullRequestID is set.
+// With DryRun set the replay only checks the event log and writes nothing.
+type ReplayOptions struct {
+	PullRequestID string
+	DryRun        bool
+}
+
+// ReplayProgress reports how far an event replay got: of the PRs with
+// events, how many were replayed and how many were skipped because their
+// events do not lead to the PR as stored.
+type ReplayProgress struct {
+	DryRun       bool  `json:"dry_run"`
+	PullRequests int64 `json:"pull_requests"`
+	Processed    int64 `json:"processed"`
+	Replayed     int64 `json:"replayed"`
+	Skipped      int64 `json:"skipped"`
+	Events       int64 `json:"events"`
+	Done         bool  `json:"done"`
+}
+
+// RebalanceMove hands one review of a PR from FromUserID to ToUserID.
+type RebalanceMove struct {
+	PullRequestID string `json:"pull_request_id"`
+	FromUserID    string `json:"from_user_id"`
+	ToUserID      string `json:"to_user_id"`
+}
+
+// RebalanceResult reports open review counts of the team's active reviewers
+// before and after a rebalance. With DryRun set the moves were only planned.
+type RebalanceResult struct {
+	TeamName string          `json:"team_name"`
+	DryRun   bool            `json:"dry_run"`
+	Before   map[string]int  `json:"before"`
+	After    map[string]int  `json:"after"`
+	Moves    []RebalanceMove `json:"moves"`
+}
+
+// AssignmentSimulation is the outcome of assigning reviewers to a
+// hypothetical PR: who would be picked and why, and the settings and open
+// review counts the choice was based on. Nothing of it is persisted.
+type AssignmentSimulation struct {
+	TeamName          string                      `json:"team_name"`
+	Settings          TeamSettings                `json:"settings"`
+	AssignedReviewers []string                    `json:"assigned_reviewers"`
+	ShadowReviewers   []string                    `json:"shadow_reviewers"`
+	AssignmentReasons map[string]AssignmentReason `json:"assignment_reasons"`
+	OpenReviews       map[string]int              `json:"open_reviews"`
+}
+
+// BotReply is the outcome of a chat or PR comment command: the PR after
+// the command and a reply for the bot to post.
+type BotReply struct {
+	Command string      `json:"command"`
+	Reply   string      `json:"reply"`
+	PR      PullRequest `json:"pr"`
+}
+
+// ReviewerSuggestion is one ranked reviewer candidate. Score adds up the
+// candidate's ownership of the changed paths, expertise in them and spare
+// capacity; Reasons names the parts that set the candidate apart.
+type ReviewerSuggestion struct {
+	UserID      string             `json:"user_id"`
+	Username    string             `json:"username"`
+	Score       float64            `json:"score"`
+	CodeOwner   bool               `json:"code_owner"`
+	Expertise   int                `json:"expertise"`
+	Load        float64            `json:"load"`
+	OpenReviews int                `json:"open_reviews"`
+	Reasons     []AssignmentReason `json:"reasons"`
+}
+
+// ReviewerSuggestions ranks the reviewers the team could take for a PR,
+// best first. Nothing is assigned.
+type ReviewerSuggestions struct {
+	PullRequestID string               `json:"pull_request_id,omitempty"`
+	TeamName      string               `json:"team_name"`
+	Suggestions   []ReviewerSuggestion `json:"suggestions"`
+}
+
+type PullRequestDetails struct {
+	PR                PullRequest       `json:"pr"`
+	History           []AssignmentEvent `json:"history"`
+	Approvals         int               `json:"approvals"`
+	RequiredApprovals int               `json:"required_approvals"`
+}
+
+type PullRequestShort struct {
+	PullRequestID   string              `json:"pull_request_id"`
+	PullRequestName string              `json:"pull_request_name"`
+	AuthorID        string              `json:"author_id"`
+	Status          PullRequestStatus   `json:"status"`
+	Priority        PullRequestPriority `json:"priority"`
+	IsDraft         bool                `json:"is_draft,omitempty"`
+	Role            ReviewerRole        `json:"role,omitempty"`
+	Archived        bool                `json:"archived,omitempty"`
+}
+
+// PullRequestFilter selects PRs for listing. Archived PRs are skipped unless
+// IncludeArchived is set.
+type PullRequestFilter struct {
+	Status   PullRequestStatus
+	Priority PullRequestPriority
+	AuthorID string
+	// TeamName matches PRs whose author is in the team.
+	TeamName string
+	// Repository matches PRs in the repository and in its components.
+	Repository string
+	// Query is a web search style query over PR names; matching PRs come
+	// most relevant first.
+	Query           string
+	IncludeArchived bool
+	Limit           int
+	Offset          int
+}
+
+// TeamFilter selects teams for listing by the start of their name.
+type TeamFilter struct {
+	Prefix string
+	Limit  int
+	Offset int
+}
+
+// UserFilter selects users for listing. A nil IsActive matches either;
+// Role is RoleShadow for mentees and RoleReviewer for the others.
+type UserFilter struct {
+	TeamName string
+	IsActive *bool
+	Role     ReviewerRole
+	Limit    int
+	Offset   int
+}
+
+// BackupFormat is the Backup layout this version writes and restores.
+const BackupFormat = 1
+
+// Backup is a consistent snapshot of the database. Tables maps each table to
+// its rows, keyed by column name, so a backup restores into the schema
+// version it was taken from.
+type Backup struct {
+	Format    int                          `json:"format"`
+	CreatedAt time.Time                    `json:"created_at"`
+	Tables    map[string][]json.RawMessage `json:"tables"`
+}
+
+// RestoreResult counts the rows restored per table. With DryRun set the
+// restore was validated and rolled back.
+type RestoreResult struct {
+	DryRun bool             `json:"dry_run"`
+	Rows   map[string]int64 `json:"rows"`
 }
 
 type ErrorCode string
@@ -50,9 +962,33 @@ const (
 	ErrTeamExists  ErrorCode = "TEAM_EXISTS"
 	ErrPRExists    ErrorCode = "PR_EXISTS"
 	ErrPRMerged    ErrorCode = "PR_MERGED"
+	ErrPRClosed    ErrorCode = "PR_CLOSED"
 	ErrNotAssigned ErrorCode = "NOT_ASSIGNED"
 	ErrNoCandidate ErrorCode = "NO_CANDIDATE"
+	ErrNotApproved ErrorCode = "NOT_APPROVED"
 	ErrNotFound    ErrorCode = "NOT_FOUND"
+	ErrNotEmpty    ErrorCode = "NOT_EMPTY"
+	ErrUndoExpired ErrorCode = "UNDO_EXPIRED"
+	ErrNoSenior    ErrorCode = "NO_SENIOR_REVIEWER"
+	ErrOnHold      ErrorCode = "PR_ON_HOLD"
+
+	ErrDeliveryNotDead ErrorCode = "DELIVERY_NOT_DEAD"
+
+	ErrAlreadyExists    ErrorCode = "ALREADY_EXISTS"
+	ErrInvalidReference ErrorCode = "INVALID_REFERENCE"
+
+	ErrUnauthorized ErrorCode = "UNAUTHORIZED"
+	ErrForbidden    ErrorCode = "FORBIDDEN"
+	ErrValidation   ErrorCode = "VALIDATION_ERROR"
+	ErrInvalidBody  ErrorCode = "INVALID_BODY"
+	ErrMissingParam ErrorCode = "MISSING_PARAM"
+
+	ErrMethodNotAllowed ErrorCode = "METHOD_NOT_ALLOWED"
+
+	ErrServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
+	ErrRetryLater         ErrorCode = "RETRY_LATER"
+	ErrTimeout            ErrorCode = "TIMEOUT"
+	ErrInternal           ErrorCode = "INTERNAL"
 )
 
 type ErrorResponse struct {
@@ -60,18 +996,47 @@ type ErrorResponse struct {
 }
 
 type ErrorDetail struct {
-	Code    ErrorCode `json:"code"`
-	Message string    `json:"message"`
+	Code      ErrorCode    `json:"code"`
+	Message   string       `json:"message"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// FieldError describes one request field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
 }
 
 type Statistics struct {
-	TotalTeams   int             `json:"total_teams"`
-	TotalUsers   int             `json:"total_users"`
-	ActiveUsers  int             `json:"active_users"`
-	TotalPRs     int             `json:"total_prs"`
-	OpenPRs      int             `json:"open_prs"`
-	MergedPRs    int             `json:"merged_prs"`
-	TopReviewers []ReviewerStats `json:"top_reviewers"`
+	TotalTeams  int `json:"total_teams"`
+	TotalUsers  int `json:"total_users"`
+	ActiveUsers int `json:"active_users"`
+	TotalPRs    int `json:"total_prs"`
+	OpenPRs     int `json:"open_prs"`
+	MergedPRs   int `json:"merged_prs"`
+	// DraftPRs counts open drafts, which OpenPRs and OpenByPriority leave
+	// out.
+	DraftPRs int `json:"draft_prs"`
+	// OpenByPriority counts open PRs per priority.
+	OpenByPriority map[PullRequestPriority]int `json:"open_by_priority"`
+	TopReviewers   []ReviewerStats             `json:"top_reviewers"`
+	// ByRepository breaks the PR counts down by repository, leaving out PRs
+	// without one.
+	ByRepository []RepositoryStatistics `json:"by_repository"`
+	// ComputedAt is when the figures were last recomputed. Stale means that
+	// was longer ago than the refresh schedule allows.
+	ComputedAt *time.Time `json:"computed_at,omitempty"`
+	Stale      bool       `json:"stale"`
+}
+
+type RepositoryStatistics struct {
+	Repository string `json:"repository"`
+	TotalPRs   int    `json:"total_prs"`
+	OpenPRs    int    `json:"open_prs"`
+	MergedPRs  int    `json:"merged_prs"`
+	DraftPRs   int    `json:"draft_prs"`
 }
 
 type ReviewerStats struct {
@@ -81,3 +1046,184 @@ type ReviewerStats struct {
 	CompletedReviews int    `json:"completed_reviews"`
 	TotalReviews     int    `json:"total_reviews"`
 }
+
+// UserStatistics summarizes a user's reviewing in [Since, Until); nil ends
+// are open. OpenReviews is the current count regardless of the period.
+// Declines counts CHANGES_REQUESTED reviews and ReassignedAway the times the
+// user was replaced on a PR.
+type UserStatistics struct {
+	UserID                        string     `json:"user_id"`
+	Since                         *time.Time `json:"since,omitempty"`
+	Until                         *time.Time `json:"until,omitempty"`
+	OpenReviews                   int        `json:"open_reviews"`
+	CompletedReviews              int        `json:"completed_reviews"`
+	Approvals                     int        `json:"approvals"`
+	AvgTimeToFirstApprovalSeconds float64    `json:"avg_time_to_first_approval_seconds"`
+	Declines                      int        `json:"declines"`
+	ReassignedAway                int        `json:"reassigned_away"`
+}
+
+// LeaderboardEntry ranks one team member over the leaderboard window.
+// MedianTurnaroundSeconds is the median time from assignment to the
+// member's first review of a PR, nil without reviews in the window.
+type LeaderboardEntry struct {
+	Rank                    int      `json:"rank"`
+	UserID                  string   `json:"user_id"`
+	Username                string   `json:"username"`
+	CompletedReviews        int      `json:"completed_reviews"`
+	MedianTurnaroundSeconds *float64 `json:"median_turnaround_seconds"`
+	OpenReviews             int      `json:"open_reviews"`
+}
+
+type Leaderboard struct {
+	TeamName string             `json:"team_name"`
+	Since    time.Time          `json:"since"`
+	Entries  []LeaderboardEntry `json:"entries"`
+}
+
+type UserWorkload struct {
+	UserID        string  `json:"user_id"`
+	Username      string  `json:"username"`
+	TeamName      string  `json:"team_name"`
+	OpenReviews   int     `json:"open_reviews"`
+	Assigned7d    int     `json:"assigned_last_7d"`
+	Assigned30d   int     `json:"assigned_last_30d"`
+	FairnessIndex float64 `json:"fairness_index"`
+}
+
+type Workload struct {
+	Users []UserWorkload `json:"users"`
+}
+
+// TeamActivity is what forecasts and anomaly checks of a team are based
+// on: the PRs its members opened and merged in each of the past weeks,
+// oldest first, and its current reviewer pool, the active members who are
+// not mentees, with their open reviews.
+type TeamActivity struct {
+	TeamName           string
+	WeeklyPullRequests []int
+	WeeklyMerges       []int
+	EligibleReviewers  int
+	OpenReviews        int
+}
+
+// AssignmentCount is how many times a user was put on PRs of a team as a
+// reviewer.
+type AssignmentCount struct {
+	TeamName    string
+	UserID      string
+	Assignments int
+}
+
+type AnomalyKind string
+
+const (
+	// AnomalyAssignmentConcentration: one member got an outsized share of
+	// the team's reviewer assignments.
+	AnomalyAssignmentConcentration AnomalyKind = "assignment_concentration"
+	// AnomalyMergeDrop: the team merged far fewer PRs last week than usual.
+	AnomalyMergeDrop AnomalyKind = "merge_drop"
+)
+
+// Anomaly is a finding of the anomaly check. Value is the share of
+// assignments or the relative drop in merges that exceeded Threshold;
+// UserID is set for findings about a member. DetectedAt is when the finding
+// was first made.
+type Anomaly struct {
+	Kind       AnomalyKind `json:"kind"`
+	TeamName   string      `json:"team_name"`
+	UserID     string      `json:"user_id,omitempty"`
+	Value      float64     `json:"value"`
+	Threshold  float64     `json:"threshold"`
+	Message    string      `json:"message"`
+	DetectedAt *time.Time  `json:"detected_at,omitempty"`
+}
+
+// ForecastMethod is how a forecast projects weekly PR counts.
+type ForecastMethod string
+
+const (
+	ForecastMovingAverage        ForecastMethod = "moving_average"
+	ForecastExponentialSmoothing ForecastMethod = "exponential_smoothing"
+)
+
+// Forecast risks name the conditions under which assignment runs out of
+// candidates and fails with NO_CANDIDATE.
+const (
+	// RiskTooFewReviewers: the pool leaves nobody to replace a reviewer on
+	// a PR by one of its members.
+	RiskTooFewReviewers = "too_few_reviewers"
+	// RiskOverCapacity: the expected reviews would take every reviewer to
+	// the team's max_open_reviews.
+	RiskOverCapacity = "over_capacity"
+)
+
+// TeamForecast is the expected review load of a team for the forecast
+// week. ProjectedOpenReviews adds the expected reviews to the open ones
+// without counting those finished meanwhile, so it is an upper bound;
+// Capacity is max_open_reviews for every eligible reviewer, zero when the
+// team sets no limit.
+type TeamForecast struct {
+	TeamName                 string   `json:"team_name"`
+	WeeklyPullRequests       []int    `json:"weekly_pull_requests"`
+	ExpectedPullRequests     float64  `json:"expected_pull_requests"`
+	ReviewerCount            int      `json:"reviewer_count"`
+	ExpectedReviews          float64  `json:"expected_reviews"`
+	EligibleReviewers        int      `json:"eligible_reviewers"`
+	ExpectedReviewsPerMember float64  `json:"expected_reviews_per_member"`
+	OpenReviews              int      `json:"open_reviews"`
+	ProjectedOpenReviews     float64  `json:"projected_open_reviews"`
+	Capacity                 int      `json:"capacity"`
+	AtRisk                   bool     `json:"at_risk"`
+	Risks                    []string `json:"risks"`
+}
+
+// Forecast projects the review load of every team for the week starting
+// at From from the HistoryWeeks weeks before it.
+type Forecast struct {
+	Method       ForecastMethod `json:"method"`
+	HistoryWeeks int            `json:"history_weeks"`
+	From         time.Time      `json:"from"`
+	To           time.Time      `json:"to"`
+	Teams        []TeamForecast `json:"teams"`
+}
+
+// SeriesMetric is a count of events that can be charted over time.
+type SeriesMetric string
+
+const (
+	SeriesPullRequestsOpened  SeriesMetric = "prs_opened"
+	SeriesPullRequestsMerged  SeriesMetric = "prs_merged"
+	SeriesReviewsSubmitted    SeriesMetric = "reviews_submitted"
+	SeriesReviewerAssignments SeriesMetric = "reviewer_assignments"
+)
+
+func (m SeriesMetric) Valid() bool {
+	switch m {
+	case SeriesPullRequestsOpened, SeriesPullRequestsMerged, SeriesReviewsSubmitted, SeriesReviewerAssignments:
+		return true
+	}
+	return false
+}
+
+// SeriesPoint is the number of events in the bucket starting at Time.
+type SeriesPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// AnnotationKind selects the events marked on charts.
+type AnnotationKind string
+
+const (
+	AnnotationMerges      AnnotationKind = "merges"
+	AnnotationEscalations AnnotationKind = "escalations"
+)
+
+// Annotation is an event marked on charts at Time.
+type Annotation struct {
+	Time  time.Time
+	Title string
+	Text  string
+	Tags  []string
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// DirectorySource lists the users of an external directory, e.g. LDAP or
+// SCIM, with the team each of them belongs to.
+type DirectorySource interface {
+	Users(ctx context.Context) ([]models.DirectoryUser, error)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrUnavailable is returned by Storage implementations when the backing
+	// database cannot be reached.
+	ErrUnavailable = errors.New("storage unavailable")
+
+	// ErrTimeout is returned when a storage operation exceeds its deadline.
+	ErrTimeout = errors.New("storage operation timed out")
+
+	// ErrAlreadyExists is returned when a write conflicts with an existing
+	// record, e.g. a duplicate key.
+	ErrAlreadyExists = errors.New("record already exists")
+
+	// ErrUsernameTaken is returned when a write gives a user the username
+	// of another one.
+	ErrUsernameTaken = errors.New("username already taken")
+
+	// ErrForeignKeyMissing is returned when a write references a record that
+	// does not exist.
+	ErrForeignKeyMissing = errors.New("referenced record does not exist")
+
+	// ErrNotEmpty is returned when restoring into a database that already
+	// holds data.
+	ErrNotEmpty = errors.New("database is not empty")
+
+	// ErrInvalidBackup is returned when a backup's rows do not fit the
+	// schema.
+	ErrInvalidBackup = errors.New("invalid backup")
+
+	// ErrRejected is returned when an external system refuses a call for
+	// good, e.g. a review request of someone who cannot review there.
+	// Retrying the call does not help.
+	ErrRejected = errors.New("rejected by external system")
+)
+
+// RateLimitError is returned when an external system refuses calls until
+// Reset because too many were made.
+type RateLimitError struct {
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited until %s", e.Reset.Format(time.RFC3339))
+}
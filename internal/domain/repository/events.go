@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// EventPublisher broadcasts PR lifecycle events to in-process subscribers.
+// Publish must not block on slow subscribers.
+type EventPublisher interface {
+	Publish(ctx context.Context, event models.Event)
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "context"
+
+// Locker serializes work on a named resource, possibly across service
+// replicas. Lock blocks until the lock is held or ctx is done; the returned
+// function releases it.
+type Locker interface {
+	Lock(ctx context.Context, key string) (unlock func(), err error)
+}
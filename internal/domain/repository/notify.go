@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// Notifier delivers notifications about PRs to people outside the service,
+// e.g. through a chat webhook.
+type Notifier interface {
+	Notify(ctx context.Context, n models.Notification) error
+}
+
+// WebhookSender posts notifications to chat webhooks in two steps, so that
+// a post can be recorded and retried exactly as it was first rendered.
+type WebhookSender interface {
+	// Render returns the delivery of n to its webhook, or nil when n has no
+	// webhook to go to.
+	Render(n models.Notification) (*models.WebhookDelivery, error)
+	// Send posts d and returns the HTTP status of the response, zero when
+	// there was none. A status other than 2xx is an error.
+	Send(ctx context.Context, d models.WebhookDelivery) (int, error)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// ReviewRequester requests reviews on the Git host PRs local PRs stand for,
+// such as the requested reviewers of a GitHub PR. Reviewers are given as
+// their logins with the host.
+type ReviewRequester interface {
+	// Hosts reports whether pr stands for a PR on the host. Review
+	// requests of other PRs are not made.
+	Hosts(pr models.PullRequest) bool
+	// RequestedReviewers returns the logins the host PR awaits a review
+	// from.
+	RequestedReviewers(ctx context.Context, pr models.PullRequest) ([]string, error)
+	RequestReviewers(ctx context.Context, pr models.PullRequest, logins []string) error
+	RemoveReviewRequests(ctx context.Context, pr models.PullRequest, logins []string) error
+}
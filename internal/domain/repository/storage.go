@@ -2,29 +2,258 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/Thorlik/avito_internship/internal/domain/models"
 )
 
+//go:generate moq -out repositorymock/storage.go -pkg repositorymock . Storage
+
 type Storage interface {
 	CreateTeam(ctx context.Context, team *models.Team) error
+	// GetTeam may read from a replica and miss a change made just before;
+	// GetTeamFromPrimary reads the team as just written.
 	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	GetTeamFromPrimary(ctx context.Context, teamName string) (*models.Team, error)
 	TeamExists(ctx context.Context, teamName string) (bool, error)
+	// ListTeams returns the teams ordered by name. The reviewer count of
+	// their settings is left zero when the team has not set one.
+	ListTeams(ctx context.Context, filter models.TeamFilter) ([]models.TeamSummary, error)
 
 	CreateUser(ctx context.Context, user *models.User) error
 	UpdateUser(ctx context.Context, user *models.User) error
 	GetUser(ctx context.Context, userID string) (*models.User, error)
 	GetUsersByTeam(ctx context.Context, teamName string) ([]models.User, error)
+	// GetUsersByIDs returns the users with the given IDs, ordered by user ID,
+	// in a single query. IDs without a user are left out.
+	GetUsersByIDs(ctx context.Context, userIDs []string) ([]models.User, error)
+	// GetUserByUsername returns the user with the username, ignoring case,
+	// or nil.
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	// SearchUsers returns the users whose username or user ID starts with
+	// query, ignoring case, followed by those whose username is merely
+	// similar to it, most similar first. Erased users are left out.
+	SearchUsers(ctx context.Context, query string, limit, offset int) ([]models.User, error)
+	// ListUsers returns the users ordered by team and user ID.
+	ListUsers(ctx context.Context, filter models.UserFilter) ([]models.UserSummary, error)
+	// GetDirectoryManagedUsers returns the live users the directory sync
+	// manages, ordered by user ID.
+	GetDirectoryManagedUsers(ctx context.Context) ([]models.User, error)
+	// GetDeletedUserIDs returns which of the given IDs belong to deleted
+	// users, and TeamDeleted whether the team is deleted, so the sync can
+	// tell them from users and teams that do not exist.
+	GetDeletedUserIDs(ctx context.Context, userIDs []string) ([]string, error)
+	TeamDeleted(ctx context.Context, teamName string) (bool, error)
+	// SetUserIdentity links the user to the external ID, replacing their
+	// identity with the same provider.
+	SetUserIdentity(ctx context.Context, identity *models.UserIdentity) error
+	// GetUserIdentities returns the identities of the given users, ordered
+	// by user ID and provider.
+	GetUserIdentities(ctx context.Context, userIDs []string) ([]models.UserIdentity, error)
+	DeleteUserIdentity(ctx context.Context, userID string, provider models.IdentityProvider) (bool, error)
+	// FindUserByIdentity returns the user linked to the external ID, or nil.
+	FindUserByIdentity(ctx context.Context, provider models.IdentityProvider, externalID string) (*models.User, error)
+	// EraseUser anonymizes the user's personal data, removes their external
+	// identities and clears their review comments; EraseInactiveUsers does
+	// so for every user deactivated before deactivatedBefore and not erased
+	// yet.
+	EraseUser(ctx context.Context, userID string) error
+	EraseInactiveUsers(ctx context.Context, deactivatedBefore time.Time) (int64, error)
+	// DeleteUser and DeleteTeam mark the user, or the team and its members,
+	// deleted, which hides them from every other read. They report false
+	// when there is nothing live to delete, and the Restore methods when
+	// there is nothing deleted to restore. A team is restored with the
+	// members deleted along with it.
+	DeleteUser(ctx context.Context, userID string) (bool, error)
+	RestoreUser(ctx context.Context, userID string) (bool, error)
+	DeleteTeam(ctx context.Context, teamName string) (bool, error)
+	RestoreTeam(ctx context.Context, teamName string) (bool, error)
+	// PurgeDeletedUsers and PurgeDeletedTeams remove what was deleted before
+	// deletedBefore for good, as far as nothing refers to it; users that
+	// are still referred to are erased instead.
+	PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error)
+	PurgeDeletedTeams(ctx context.Context, deletedBefore time.Time) (int64, error)
 
 	CreatePullRequest(ctx context.Context, pr *models.PullRequest) error
 	GetPullRequest(ctx context.Context, prID string) (*models.PullRequest, error)
-	UpdatePullRequest(ctx context.Context, pr *models.PullRequest) error
+	// UpdatePullRequest saves the reviewers and draft flag of pr, and
+	// UpdatePullRequestDetails its name, labels, priority, repository, size
+	// and changed paths. Both report false without saving when the PR is no
+	// longer open. UpdatePullRequestStatus saves the status and merge time
+	// of pr, and reports false without saving when the PR's status is no
+	// longer from. None of them saves what the others do, so a stale copy
+	// saved by one does not undo a change saved by another.
+	UpdatePullRequest(ctx context.Context, pr *models.PullRequest) (bool, error)
+	UpdatePullRequestDetails(ctx context.Context, pr *models.PullRequest) (bool, error)
+	UpdatePullRequestStatus(ctx context.Context, pr *models.PullRequest, from models.PullRequestStatus) (bool, error)
 	PullRequestExists(ctx context.Context, prID string) (bool, error)
 	GetPullRequestsByReviewer(ctx context.Context, userID string) ([]models.PullRequestShort, error)
+	ListPullRequests(ctx context.Context, filter models.PullRequestFilter) ([]models.PullRequestShort, error)
+	ArchivePullRequests(ctx context.Context, mergedBefore time.Time) (int64, error)
+	PurgeArchivedPullRequests(ctx context.Context, archivedBefore time.Time) (int64, error)
+	MarkStalePullRequests(ctx context.Context, now time.Time, defaultThreshold time.Duration) ([]string, error)
+	GetStalePullRequests(ctx context.Context) ([]models.StalePullRequest, error)
+	GetOpenPullRequestsByTeam(ctx context.Context, teamName string) ([]models.PullRequest, error)
+
+	CreateReview(ctx context.Context, review *models.Review) error
+	AddAssignmentEvents(ctx context.Context, events []models.AssignmentEvent) error
+	GetAssignmentHistory(ctx context.Context, prID string) ([]models.AssignmentEvent, error)
+	// ReplaceAssignmentHistory makes events the whole history of the PR.
+	ReplaceAssignmentHistory(ctx context.Context, prID string, events []models.AssignmentEvent) error
+	// GetUserHistory returns the user's assignments, newest first. Outcome
+	// is left for the caller to fill in.
+	GetUserHistory(ctx context.Context, filter models.ReviewHistoryFilter) ([]models.ReviewHistoryEntry, error)
+
+	GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error)
+	UpsertTeamSettings(ctx context.Context, settings *models.TeamSettings) error
+	// GetFeatureFlags returns the stored flags ordered by name.
+	GetFeatureFlags(ctx context.Context) ([]models.FeatureFlag, error)
+	UpsertFeatureFlag(ctx context.Context, flag *models.FeatureFlag) error
+	DeleteFeatureFlag(ctx context.Context, name string) (bool, error)
+	// GetMaintenance returns the stored maintenance mode, nil when it was
+	// never set.
+	GetMaintenance(ctx context.Context) (*models.Maintenance, error)
+	SetMaintenance(ctx context.Context, m *models.Maintenance) error
+	// GetSchemaMigrations returns the applied migrations ordered by
+	// version.
+	GetSchemaMigrations(ctx context.Context) ([]models.SchemaMigration, error)
+	GetRotationCursor(ctx context.Context, teamName string) (string, error)
+	SetRotationCursor(ctx context.Context, teamName, userID string) error
+
+	CreateRepository(ctx context.Context, repo *models.Repository) error
+	GetRepository(ctx context.Context, name string) (*models.Repository, error)
+	// ListRepositories returns the repositories ordered by name, only those
+	// whose default team is teamName unless it is empty.
+	ListRepositories(ctx context.Context, teamName string) ([]models.Repository, error)
+	UpdateRepository(ctx context.Context, repo *models.Repository) (bool, error)
+	DeleteRepository(ctx context.Context, name string) (bool, error)
+	// GetCodeOwners returns the repository's CODEOWNERS rules in line order.
+	GetCodeOwners(ctx context.Context, repository string) ([]models.CodeOwnersRule, error)
+	// SetCodeOwners replaces the repository's CODEOWNERS rules.
+	SetCodeOwners(ctx context.Context, repository string, rules []models.CodeOwnersRule) error
+
+	CreateRoutingRule(ctx context.Context, rule *models.RoutingRule) error
+	GetRoutingRules(ctx context.Context, teamName string) ([]models.RoutingRule, error)
+	DeleteRoutingRule(ctx context.Context, id int64) (bool, error)
+
+	CreateRepositoryRule(ctx context.Context, rule *models.RepositoryRule) error
+	GetRepositoryRules(ctx context.Context, teamName string) ([]models.RepositoryRule, error)
+	DeleteRepositoryRule(ctx context.Context, id int64) (bool, error)
+
+	CreateExclusionRule(ctx context.Context, rule *models.ExclusionRule) error
+	GetExclusionRules(ctx context.Context, teamName string) ([]models.ExclusionRule, error)
+	DeleteExclusionRule(ctx context.Context, id int64) (bool, error)
 
 	GetReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error)
+	// GetReviewSizes returns the lines changed by each of the users' open
+	// reviews.
+	GetReviewSizes(ctx context.Context, userIDs []string) (map[string][]int, error)
+	GetPathExpertise(ctx context.Context, paths []string, userIDs []string) (map[string]int, error)
+
+	// AddOutboxMessages stores messages for delivery.
+	AddOutboxMessages(ctx context.Context, messages []models.OutboxMessage) error
+	// ClaimOutboxMessages returns up to limit messages due at now, oldest
+	// first, leaving out those behind an undelivered message of the same PR
+	// that has attempts left. Claimed messages are not due again before
+	// leaseUntil, so other replicas skip them.
+	ClaimOutboxMessages(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.OutboxMessage, error)
+	DeleteOutboxMessage(ctx context.Context, id int64) error
+	// FailOutboxMessage counts a failed attempt and schedules the next one,
+	// or none when nextAttemptAt is nil.
+	FailOutboxMessage(ctx context.Context, id int64, lastError string, nextAttemptAt *time.Time) error
+	// GetReviewIssue returns the key of the issue the PR's review tasks are
+	// filed under, or "".
+	GetReviewIssue(ctx context.Context, prID string) (string, error)
+	SetReviewIssue(ctx context.Context, prID, issueKey string) error
+	// GetReviewTasks returns the PR's review tasks ordered by user ID.
+	GetReviewTasks(ctx context.Context, prID string) ([]models.ReviewTask, error)
+	AddReviewTask(ctx context.Context, task models.ReviewTask) error
+
+	// AddWebhookDelivery stores d and fills in its ID and CreatedAt.
+	AddWebhookDelivery(ctx context.Context, d *models.WebhookDelivery) error
+	GetWebhookDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error)
+	// ClaimWebhookDeliveries returns up to limit pending deliveries due at
+	// now, oldest first. Claimed deliveries are not due again before
+	// leaseUntil, so other replicas skip them.
+	ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error)
+	// RecordWebhookAttempt saves the outcome of an attempt to deliver d: its
+	// status, attempts, last status and error, next attempt and delivery
+	// time.
+	RecordWebhookAttempt(ctx context.Context, d models.WebhookDelivery) error
+	ListWebhookDeliveries(ctx context.Context, filter models.DeliveryFilter) ([]models.WebhookDelivery, error)
+	// RetryWebhookDelivery makes a dead delivery pending again, due at
+	// nextAttemptAt, and reports whether it was dead.
+	RetryWebhookDelivery(ctx context.Context, id int64, nextAttemptAt time.Time) (bool, error)
+	// PurgeWebhookDeliveries deletes deliveries delivered before cutoff.
+	PurgeWebhookDeliveries(ctx context.Context, cutoff time.Time) (int64, error)
+
+	// GetDigestPreferences returns the user's digest preferences, or nil when
+	// they have never set any.
+	GetDigestPreferences(ctx context.Context, userID string) (*models.DigestPreferences, error)
+	SetDigestPreferences(ctx context.Context, prefs *models.DigestPreferences) error
+	// GetEnabledDigests returns the preferences of every user who opted in,
+	// ordered by user ID.
+	GetEnabledDigests(ctx context.Context) ([]models.DigestPreferences, error)
+	MarkDigestSent(ctx context.Context, userID string, at time.Time) error
+
+	// GetIdleReviews returns the reviewers of open, ready PRs who have not
+	// reviewed them since being assigned, for the teams with an escalation
+	// policy, longest idle first.
+	GetIdleReviews(ctx context.Context) ([]models.IdleReview, error)
+	AddEscalation(ctx context.Context, e *models.Escalation) error
+	// ListEscalations returns the team's escalations, newest first.
+	ListEscalations(ctx context.Context, teamName string, limit, offset int) ([]models.Escalation, error)
+
+	// WithTeamLock serializes fn with every other WithTeamLock call for the
+	// same team, across all service replicas. fn must use the Storage it is
+	// given so its reads and writes happen under the lock.
+	WithTeamLock(ctx context.Context, teamName string, fn func(repo Storage) error) error
+
+	// AppendEvent adds a published event to the event log, without the
+	// reviews of its PR.
+	AppendEvent(ctx context.Context, event models.Event) error
+	// GetEventPullRequests returns up to limit IDs of PRs with logged events
+	// that sort after afterID, in order.
+	GetEventPullRequests(ctx context.Context, afterID string, limit int) ([]string, error)
+	CountEventPullRequests(ctx context.Context) (int64, error)
+	// GetPullRequestEvents returns the logged events of the PR, oldest
+	// first, with their log IDs.
+	GetPullRequestEvents(ctx context.Context, prID string) ([]models.Event, error)
 
 	GetStatistics(ctx context.Context) (*models.Statistics, error)
+	// RefreshStatistics recomputes the figures GetStatistics returns.
+	RefreshStatistics(ctx context.Context) error
+
+	// Backup snapshots every table in one transaction. Restore loads a
+	// backup into an empty database and returns the rows restored per
+	// table; with dryRun it rolls back instead of committing.
+	Backup(ctx context.Context) (*models.Backup, error)
+	Restore(ctx context.Context, backup *models.Backup, dryRun bool) (map[string]int64, error)
+	GetWorkload(ctx context.Context, since7d, since30d time.Time) ([]models.UserWorkload, error)
+	// GetTeamActivity returns the activity of every team ordered by name,
+	// counting the PRs opened and merged in each of the weeks before now.
+	GetTeamActivity(ctx context.Context, now time.Time, weeks int) ([]models.TeamActivity, error)
+	// GetAssignmentCounts returns how often every user was assigned as a
+	// reviewer since then, per team of the PR authors.
+	GetAssignmentCounts(ctx context.Context, since time.Time) ([]models.AssignmentCount, error)
+	// ReplaceAnomalies makes anomalies the current findings, stamped with at
+	// unless they were current already, and returns those that were not.
+	ReplaceAnomalies(ctx context.Context, anomalies []models.Anomaly, at time.Time) ([]models.Anomaly, error)
+	// GetAnomalies returns the current findings ordered by team, kind and
+	// user.
+	GetAnomalies(ctx context.Context) ([]models.Anomaly, error)
+	// GetSeries counts the events of metric in [from, to) in buckets of
+	// step starting at from, oldest first. Empty buckets are left out.
+	GetSeries(ctx context.Context, metric models.SeriesMetric, from, to time.Time, step time.Duration) ([]models.SeriesPoint, error)
+	// GetAnnotations returns up to limit events of kind in [from, to),
+	// oldest first.
+	GetAnnotations(ctx context.Context, kind models.AnnotationKind, from, to time.Time, limit int) ([]models.Annotation, error)
+	// GetUserStatistics aggregates the user's reviewing between since and
+	// until; zero times leave that end open.
+	GetUserStatistics(ctx context.Context, userID string, since, until time.Time) (*models.UserStatistics, error)
+	// GetLeaderboard returns unranked entries for every member of the team,
+	// counting completed reviews and turnaround since the given time.
+	GetLeaderboard(ctx context.Context, teamName string, since time.Time) ([]models.LeaderboardEntry, error)
 
 	Close() error
 }
@@ -0,0 +1,1544 @@
+// Package storagetest is a conformance suite for repository.Storage. Every
+// backend runs it from its own tests, so a new implementation is checked
+// against the same behaviour the service relies on:
+//
+//	func TestStorage(t *testing.T) {
+//		storagetest.Run(t, func(t *testing.T) repository.Storage {
+//			return newEmptyStorage(t)
+//		})
+//	}
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// Factory returns an empty storage. It is called once per test; the suite
+// does not close the storage, so the factory registers any cleanup with t.
+type Factory func(t *testing.T) repository.Storage
+
+// Run runs every conformance test against storages made by newStorage. The
+// tests run one at a time, so factories may hand out the same database
+// after emptying it.
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s repository.Storage)
+	}{
+		{"TeamRoundTrip", testTeamRoundTrip},
+		{"DuplicateTeam", testDuplicateTeam},
+		{"MissingRecords", testMissingRecords},
+		{"UpdateUser", testUpdateUser},
+		{"GetUsersByIDs", testGetUsersByIDs},
+		{"UsernameLookup", testUsernameLookup},
+		{"ListTeams", testListTeams},
+		{"ListUsers", testListUsers},
+		{"SearchPullRequests", testSearchPullRequests},
+		{"PullRequestRoundTrip", testPullRequestRoundTrip},
+		{"DuplicatePullRequest", testDuplicatePullRequest},
+		{"PullRequestUnknownAuthor", testPullRequestUnknownAuthor},
+		{"ReviewCounts", testReviewCounts},
+		{"ReviewerQueueOrder", testReviewerQueueOrder},
+		{"ArchiveIdempotent", testArchiveIdempotent},
+		{"StaleIdempotent", testStaleIdempotent},
+		{"EraseUserIdempotent", testEraseUserIdempotent},
+		{"SoftDelete", testSoftDelete},
+		{"PurgeDeleted", testPurgeDeleted},
+		{"FeatureFlags", testFeatureFlags},
+		{"Maintenance", testMaintenance},
+		{"SchemaMigrations", testSchemaMigrations},
+		{"Repositories", testRepositories},
+		{"CodeOwners", testCodeOwners},
+		{"UserIdentities", testUserIdentities},
+		{"DirectoryManagedUsers", testDirectoryManagedUsers},
+		{"Outbox", testOutbox},
+		{"EventLog", testEventLog},
+		{"EventLogOmitsReviews", testEventLogOmitsReviews},
+		{"WebhookDeliveries", testWebhookDeliveries},
+		{"ReviewTasks", testReviewTasks},
+		{"DigestPreferences", testDigestPreferences},
+		{"Escalations", testEscalations},
+		{"TeamActivity", testTeamActivity},
+		{"Anomalies", testAnomalies},
+		{"Series", testSeries},
+		{"TeamLockRollback", testTeamLockRollback},
+		{"TeamLockSerializes", testTeamLockSerializes},
+		{"BackupRestore", func(t *testing.T, s repository.Storage) { testBackupRestore(t, s, newStorage) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStorage(t))
+		})
+	}
+}
+
+var epoch = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// seedTeam creates team "backend" with active members u1, u2 and u3.
+func seedTeam(t *testing.T, s repository.Storage) {
+	t.Helper()
+	team := &models.Team{
+		TeamName: "backend",
+		Members: []models.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true, ReviewWeight: 1},
+			{UserID: "u2", Username: "Bob", IsActive: true, ReviewWeight: 1},
+			{UserID: "u3", Username: "Carol", IsActive: true, ReviewWeight: 1},
+		},
+	}
+	if err := s.CreateTeam(context.Background(), team); err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+}
+
+func newPullRequest(id, authorID string, reviewers ...string) *models.PullRequest {
+	createdAt := epoch
+	return &models.PullRequest{
+		PullRequestID:     id,
+		PullRequestName:   "PR " + id,
+		AuthorID:          authorID,
+		Status:            models.StatusOpen,
+		Priority:          models.PriorityNormal,
+		AssignedReviewers: append([]string{}, reviewers...),
+		ShadowReviewers:   []string{},
+		Labels:            []string{},
+		CreatedAt:         &createdAt,
+	}
+}
+
+func createPullRequest(t *testing.T, s repository.Storage, pr *models.PullRequest) {
+	t.Helper()
+	if err := s.CreatePullRequest(context.Background(), pr); err != nil {
+		t.Fatalf("CreatePullRequest(%s): %v", pr.PullRequestID, err)
+	}
+}
+
+func testTeamRoundTrip(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	team, err := s.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetTeam: %v", err)
+	}
+	if team == nil || len(team.Members) != 3 {
+		t.Fatalf("GetTeam = %+v, want 3 members", team)
+	}
+	for i, want := range []string{"u1", "u2", "u3"} {
+		if team.Members[i].UserID != want {
+			t.Errorf("member %d = %s, want %s (ordered by user_id)", i, team.Members[i].UserID, want)
+		}
+	}
+
+	if primary, err := s.GetTeamFromPrimary(ctx, "backend"); err != nil || primary == nil || len(primary.Members) != 3 {
+		t.Errorf("GetTeamFromPrimary = %+v, %v, want 3 members", primary, err)
+	}
+
+	exists, err := s.TeamExists(ctx, "backend")
+	if err != nil || !exists {
+		t.Errorf("TeamExists = %v, %v, want true", exists, err)
+	}
+	users, err := s.GetUsersByTeam(ctx, "backend")
+	if err != nil || len(users) != 3 {
+		t.Errorf("GetUsersByTeam = %d users, %v, want 3", len(users), err)
+	}
+}
+
+func testDuplicateTeam(t *testing.T, s repository.Storage) {
+	seedTeam(t, s)
+	err := s.CreateTeam(context.Background(), &models.Team{TeamName: "backend"})
+	if !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Errorf("CreateTeam duplicate = %v, want ErrAlreadyExists", err)
+	}
+}
+
+func testMissingRecords(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+
+	if team, err := s.GetTeam(ctx, "nope"); team != nil || err != nil {
+		t.Errorf("GetTeam missing = %+v, %v, want nil, nil", team, err)
+	}
+	if exists, err := s.TeamExists(ctx, "nope"); exists || err != nil {
+		t.Errorf("TeamExists missing = %v, %v, want false, nil", exists, err)
+	}
+	if user, err := s.GetUser(ctx, "nope"); user != nil || err != nil {
+		t.Errorf("GetUser missing = %+v, %v, want nil, nil", user, err)
+	}
+	if pr, err := s.GetPullRequest(ctx, "nope"); pr != nil || err != nil {
+		t.Errorf("GetPullRequest missing = %+v, %v, want nil, nil", pr, err)
+	}
+	if exists, err := s.PullRequestExists(ctx, "nope"); exists || err != nil {
+		t.Errorf("PullRequestExists missing = %v, %v, want false, nil", exists, err)
+	}
+}
+
+func testUpdateUser(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	user, err := s.GetUser(ctx, "u2")
+	if err != nil || user == nil {
+		t.Fatalf("GetUser = %+v, %v", user, err)
+	}
+	user.IsActive = false
+	user.ReviewWeight = 0.5
+	user.ManagerID = "u1"
+	if err := s.UpdateUser(ctx, user); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+
+	got, err := s.GetUser(ctx, "u2")
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if got.IsActive || got.ReviewWeight != 0.5 || got.TeamName != "backend" || got.ManagerID != "u1" {
+		t.Errorf("GetUser after update = %+v", got)
+	}
+
+	user.ManagerID = "ghost"
+	if err := s.UpdateUser(ctx, user); !errors.Is(err, repository.ErrForeignKeyMissing) {
+		t.Errorf("UpdateUser unknown manager error = %v, want ErrForeignKeyMissing", err)
+	}
+}
+
+func testGetUsersByIDs(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	users, err := s.GetUsersByIDs(ctx, []string{"u3", "nope", "u1"})
+	if err != nil {
+		t.Fatalf("GetUsersByIDs: %v", err)
+	}
+	if len(users) != 2 || users[0].UserID != "u1" || users[1].UserID != "u3" {
+		t.Errorf("GetUsersByIDs = %+v, want u1 and u3 (ordered by user_id)", users)
+	}
+	if users[0].Username != "Alice" || users[0].TeamName != "backend" {
+		t.Errorf("GetUsersByIDs u1 = %+v", users[0])
+	}
+
+	users, err = s.GetUsersByIDs(ctx, nil)
+	if err != nil || users == nil || len(users) != 0 {
+		t.Errorf("GetUsersByIDs(nil) = %#v, %v, want an empty slice", users, err)
+	}
+}
+
+func testUsernameLookup(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	user, err := s.GetUserByUsername(ctx, "alice")
+	if err != nil || user == nil || user.UserID != "u1" {
+		t.Errorf("GetUserByUsername(alice) = %+v, %v, want u1", user, err)
+	}
+	if user, err := s.GetUserByUsername(ctx, "nobody"); user != nil || err != nil {
+		t.Errorf("GetUserByUsername(nobody) = %+v, %v, want nil", user, err)
+	}
+
+	err = s.CreateUser(ctx, &models.User{UserID: "u4", Username: "ALICE", TeamName: "backend", IsActive: true, ReviewWeight: 1})
+	if !errors.Is(err, repository.ErrUsernameTaken) {
+		t.Errorf("CreateUser with a taken username = %v, want ErrUsernameTaken", err)
+	}
+
+	users, err := s.SearchUsers(ctx, "BO", 10, 0)
+	if err != nil || len(users) != 1 || users[0].UserID != "u2" {
+		t.Errorf("SearchUsers(BO) = %+v, %v, want u2", users, err)
+	}
+	users, err = s.SearchUsers(ctx, "Alise", 10, 0)
+	if err != nil || len(users) != 1 || users[0].UserID != "u1" {
+		t.Errorf("SearchUsers(Alise) = %+v, %v, want the similar u1", users, err)
+	}
+	if users, err := s.SearchUsers(ctx, "u", 2, 1); err != nil || len(users) != 2 || users[0].UserID != "u2" {
+		t.Errorf("SearchUsers(u) second page = %+v, %v, want u2 and u3", users, err)
+	}
+	if users, err := s.SearchUsers(ctx, "%", 10, 0); err != nil || len(users) != 0 {
+		t.Errorf("SearchUsers(%%) = %+v, %v, want the wildcard matched literally", users, err)
+	}
+}
+
+func testListTeams(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+	if err := s.CreateTeam(ctx, &models.Team{TeamName: "frontend"}); err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+	if err := s.CreateTeam(ctx, &models.Team{TeamName: "b_side"}); err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+	if err := s.UpsertTeamSettings(ctx, &models.TeamSettings{TeamName: "backend", ReviewerCount: 3, NotificationProvider: models.NotifyWebhook, Locale: models.LocaleRussian}); err != nil {
+		t.Fatalf("UpsertTeamSettings: %v", err)
+	}
+	createPullRequest(t, s, newPullRequest("pr-1", "u1", "u2"))
+	if _, err := s.DeleteUser(ctx, "u3"); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+
+	teams, err := s.ListTeams(ctx, models.TeamFilter{Limit: 10})
+	if err != nil || len(teams) != 3 {
+		t.Fatalf("ListTeams = %+v, %v, want 3 teams", teams, err)
+	}
+	var backend models.TeamSummary
+	for _, team := range teams {
+		if team.TeamName == "backend" {
+			backend = team
+		}
+	}
+	if backend.TeamName != "backend" || backend.Members != 2 || backend.OpenPullRequests != 1 ||
+		backend.Settings.ReviewerCount != 3 || backend.Settings.Locale != models.LocaleRussian {
+		t.Errorf("backend summary = %+v", backend)
+	}
+
+	// The underscore matches itself only, not any character.
+	teams, err = s.ListTeams(ctx, models.TeamFilter{Prefix: "b_", Limit: 10})
+	if err != nil || len(teams) != 1 || teams[0].TeamName != "b_side" {
+		t.Errorf("ListTeams(b_) = %+v, %v, want b_side", teams, err)
+	}
+}
+
+func testListUsers(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+	if err := s.CreateUser(ctx, &models.User{UserID: "u4", Username: "Dave", TeamName: "backend", IsMentee: true, ReviewWeight: 1}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	createPullRequest(t, s, newPullRequest("pr-1", "u1", "u2", "u3"))
+	createPullRequest(t, s, newPullRequest("pr-2", "u3", "u2"))
+
+	users, err := s.ListUsers(ctx, models.UserFilter{TeamName: "backend", Limit: 10})
+	if err != nil || len(users) != 4 {
+		t.Fatalf("ListUsers = %+v, %v, want 4 users", users, err)
+	}
+	open := map[string]int{}
+	for _, u := range users {
+		open[u.UserID] = u.OpenReviews
+	}
+	if open["u1"] != 0 || open["u2"] != 2 || open["u3"] != 1 {
+		t.Errorf("open reviews = %v", open)
+	}
+
+	active := true
+	users, err = s.ListUsers(ctx, models.UserFilter{IsActive: &active, Role: models.RoleShadow, Limit: 10})
+	if err != nil || len(users) != 0 {
+		t.Errorf("ListUsers(active mentees) = %+v, %v, want none", users, err)
+	}
+	users, err = s.ListUsers(ctx, models.UserFilter{Role: models.RoleShadow, Limit: 10})
+	if err != nil || len(users) != 1 || users[0].UserID != "u4" {
+		t.Errorf("ListUsers(mentees) = %+v, %v, want u4", users, err)
+	}
+}
+
+func testSearchPullRequests(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+	names := map[string]string{
+		"pr-1": "Fix login redirect",
+		"pr-2": "Login page: fix fix fix",
+		"pr-3": "Add billing export",
+	}
+	for id, name := range names {
+		pr := newPullRequest(id, "u1")
+		pr.PullRequestName = name
+		createPullRequest(t, s, pr)
+	}
+
+	prs, err := s.ListPullRequests(ctx, models.PullRequestFilter{Query: "FIX login", Limit: 10})
+	if err != nil || len(prs) != 2 || prs[0].PullRequestID != "pr-2" {
+		t.Errorf("search = %+v, %v, want pr-2 then pr-1", prs, err)
+	}
+	prs, err = s.ListPullRequests(ctx, models.PullRequestFilter{Query: "login -redirect", Limit: 10})
+	if err != nil || len(prs) != 1 || prs[0].PullRequestID != "pr-2" {
+		t.Errorf("search excluding a word = %+v, %v, want pr-2", prs, err)
+	}
+	prs, err = s.ListPullRequests(ctx, models.PullRequestFilter{Query: "billing", TeamName: "frontend", Limit: 10})
+	if err != nil || len(prs) != 0 {
+		t.Errorf("search in another team = %+v, %v, want none", prs, err)
+	}
+}
+
+func testPullRequestRoundTrip(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	pr := newPullRequest("pr-1", "u1", "u2", "u3")
+	pr.Priority = models.PriorityUrgent
+	pr.Labels = []string{"backend"}
+	createPullRequest(t, s, pr)
+
+	got, err := s.GetPullRequest(ctx, "pr-1")
+	if err != nil || got == nil {
+		t.Fatalf("GetPullRequest = %+v, %v", got, err)
+	}
+	if got.AuthorID != "u1" || got.Status != models.StatusOpen || got.Priority != models.PriorityUrgent {
+		t.Errorf("GetPullRequest = %+v", got)
+	}
+	if len(got.AssignedReviewers) != 2 || got.AssignedReviewers[0] != "u2" || got.AssignedReviewers[1] != "u3" {
+		t.Errorf("AssignedReviewers = %v, want [u2 u3] in order", got.AssignedReviewers)
+	}
+
+	got.PullRequestName = "Add search"
+	got.Priority = models.PriorityLow
+	got.Labels = []string{}
+	got.Repository = "api"
+	got.ChangedPaths = []string{"api/search.go"}
+	got.LinesChanged = 120
+	if updated, err := s.UpdatePullRequestDetails(ctx, got); err != nil || !updated {
+		t.Fatalf("UpdatePullRequestDetails = %v, %v", updated, err)
+	}
+	updated, err := s.GetPullRequest(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetPullRequest: %v", err)
+	}
+	if updated.PullRequestName != "Add search" || updated.Priority != models.PriorityLow || len(updated.Labels) != 0 ||
+		updated.Repository != "api" || len(updated.ChangedPaths) != 1 || updated.ChangedPaths[0] != "api/search.go" ||
+		updated.LinesChanged != 120 {
+		t.Errorf("after details update = %+v", updated)
+	}
+
+	// A stale copy saving reviewers keeps the details.
+	pr.AssignedReviewers = []string{"u3", "u2"}
+	if updated, err := s.UpdatePullRequest(ctx, pr); err != nil || !updated {
+		t.Fatalf("UpdatePullRequest = %v, %v", updated, err)
+	}
+	// So does one saving the status, which also keeps the reviewers.
+	pr.Status = models.StatusClosed
+	pr.AssignedReviewers = []string{"u2"}
+	if updated, err := s.UpdatePullRequestStatus(ctx, pr, models.StatusOpen); err != nil || !updated {
+		t.Fatalf("UpdatePullRequestStatus = %v, %v", updated, err)
+	}
+	closed, err := s.GetPullRequest(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetPullRequest: %v", err)
+	}
+	if closed.Status != models.StatusClosed || closed.PullRequestName != "Add search" || len(closed.Labels) != 0 || closed.LinesChanged != 120 ||
+		!reflect.DeepEqual(closed.AssignedReviewers, []string{"u3", "u2"}) {
+		t.Errorf("after close = %+v", closed)
+	}
+	if updated, err := s.UpdatePullRequestDetails(ctx, got); err != nil || updated {
+		t.Errorf("UpdatePullRequestDetails of closed PR = %v, %v; want false", updated, err)
+	}
+	if updated, err := s.UpdatePullRequest(ctx, got); err != nil || updated {
+		t.Errorf("UpdatePullRequest of closed PR = %v, %v; want false", updated, err)
+	}
+
+	// Status changes only apply to the status they were decided on.
+	mergedAt := epoch.Add(time.Hour)
+	got.Status = models.StatusMerged
+	got.MergedAt = &mergedAt
+	if updated, err := s.UpdatePullRequestStatus(ctx, got, models.StatusOpen); err != nil || updated {
+		t.Errorf("UpdatePullRequestStatus from OPEN of closed PR = %v, %v; want false", updated, err)
+	}
+	if updated, err := s.UpdatePullRequestStatus(ctx, got, models.StatusClosed); err != nil || !updated {
+		t.Fatalf("UpdatePullRequestStatus = %v, %v", updated, err)
+	}
+	merged, err := s.GetPullRequest(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetPullRequest: %v", err)
+	}
+	if merged.Status != models.StatusMerged || merged.MergedAt == nil {
+		t.Errorf("after merge = %+v", merged)
+	}
+}
+
+func testDuplicatePullRequest(t *testing.T, s repository.Storage) {
+	seedTeam(t, s)
+	createPullRequest(t, s, newPullRequest("pr-1", "u1"))
+
+	err := s.CreatePullRequest(context.Background(), newPullRequest("pr-1", "u2"))
+	if !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Errorf("CreatePullRequest duplicate = %v, want ErrAlreadyExists", err)
+	}
+}
+
+func testPullRequestUnknownAuthor(t *testing.T, s repository.Storage) {
+	err := s.CreatePullRequest(context.Background(), newPullRequest("pr-1", "ghost"))
+	if !errors.Is(err, repository.ErrForeignKeyMissing) {
+		t.Errorf("CreatePullRequest unknown author = %v, want ErrForeignKeyMissing", err)
+	}
+}
+
+func testReviewCounts(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	createPullRequest(t, s, newPullRequest("open", "u1", "u2"))
+	draft := newPullRequest("draft", "u1", "u2")
+	draft.IsDraft = true
+	createPullRequest(t, s, draft)
+	merged := newPullRequest("merged", "u1", "u2")
+	mergedAt := epoch
+	merged.Status = models.StatusMerged
+	merged.MergedAt = &mergedAt
+	createPullRequest(t, s, merged)
+
+	counts, err := s.GetReviewCounts(ctx, []string{"u2", "u3"})
+	if err != nil {
+		t.Fatalf("GetReviewCounts: %v", err)
+	}
+	if counts["u2"] != 1 {
+		t.Errorf("u2 count = %d, want 1 (open, not draft or merged)", counts["u2"])
+	}
+	if count, ok := counts["u3"]; !ok || count != 0 {
+		t.Errorf("u3 count = %d, %v, want 0 present", count, ok)
+	}
+
+	empty, err := s.GetReviewCounts(ctx, nil)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("GetReviewCounts(nil) = %v, %v, want empty", empty, err)
+	}
+}
+
+func testReviewerQueueOrder(t *testing.T, s repository.Storage) {
+	seedTeam(t, s)
+
+	older := newPullRequest("normal", "u1", "u2")
+	createPullRequest(t, s, older)
+	urgent := newPullRequest("urgent", "u1", "u2")
+	urgent.Priority = models.PriorityUrgent
+	createdAt := epoch.Add(-time.Hour)
+	urgent.CreatedAt = &createdAt
+	createPullRequest(t, s, urgent)
+
+	prs, err := s.GetPullRequestsByReviewer(context.Background(), "u2")
+	if err != nil {
+		t.Fatalf("GetPullRequestsByReviewer: %v", err)
+	}
+	if len(prs) != 2 || prs[0].PullRequestID != "urgent" {
+		t.Errorf("GetPullRequestsByReviewer = %+v, want urgent first", prs)
+	}
+}
+
+func testArchiveIdempotent(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	pr := newPullRequest("pr-1", "u1", "u2")
+	mergedAt := epoch
+	pr.Status = models.StatusMerged
+	pr.MergedAt = &mergedAt
+	createPullRequest(t, s, pr)
+	createPullRequest(t, s, newPullRequest("pr-2", "u1", "u2"))
+
+	for i, want := range []int64{1, 0} {
+		n, err := s.ArchivePullRequests(ctx, epoch.Add(time.Hour))
+		if err != nil || n != want {
+			t.Errorf("ArchivePullRequests call %d = %d, %v, want %d", i+1, n, err, want)
+		}
+	}
+
+	visible, err := s.ListPullRequests(ctx, models.PullRequestFilter{Limit: 10})
+	if err != nil || len(visible) != 1 || visible[0].PullRequestID != "pr-2" {
+		t.Errorf("ListPullRequests = %+v, %v, want only pr-2", visible, err)
+	}
+	all, err := s.ListPullRequests(ctx, models.PullRequestFilter{IncludeArchived: true, Limit: 10})
+	if err != nil || len(all) != 2 {
+		t.Errorf("ListPullRequests including archived = %d, %v, want 2", len(all), err)
+	}
+}
+
+func testStaleIdempotent(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+	createPullRequest(t, s, newPullRequest("pr-1", "u1", "u2"))
+
+	now := epoch.Add(48 * time.Hour)
+	ids, err := s.MarkStalePullRequests(ctx, now, 24*time.Hour)
+	if err != nil || len(ids) != 1 || ids[0] != "pr-1" {
+		t.Fatalf("MarkStalePullRequests = %v, %v, want [pr-1]", ids, err)
+	}
+	ids, err = s.MarkStalePullRequests(ctx, now, 24*time.Hour)
+	if err != nil || len(ids) != 0 {
+		t.Errorf("MarkStalePullRequests again = %v, %v, want none", ids, err)
+	}
+}
+
+func testEraseUserIdempotent(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	for i := 0; i < 2; i++ {
+		if err := s.EraseUser(ctx, "u2"); err != nil {
+			t.Fatalf("EraseUser call %d: %v", i+1, err)
+		}
+	}
+	user, err := s.GetUser(ctx, "u2")
+	if err != nil || user == nil {
+		t.Fatalf("GetUser after erase = %+v, %v, want the user kept", user, err)
+	}
+	if user.Username != models.ErasedUsername || user.IsActive {
+		t.Errorf("erased user = %+v", user)
+	}
+}
+
+func testSoftDelete(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	if ok, err := s.DeleteUser(ctx, "u1"); !ok || err != nil {
+		t.Fatalf("DeleteUser = %v, %v, want true", ok, err)
+	}
+	if ok, err := s.DeleteUser(ctx, "u1"); ok || err != nil {
+		t.Errorf("DeleteUser twice = %v, %v, want false", ok, err)
+	}
+	if user, err := s.GetUser(ctx, "u1"); user != nil || err != nil {
+		t.Errorf("GetUser of deleted user = %+v, %v, want nil", user, err)
+	}
+	users, err := s.GetUsersByTeam(ctx, "backend")
+	if err != nil || len(users) != 2 {
+		t.Errorf("GetUsersByTeam = %+v, %v, want the two live members", users, err)
+	}
+
+	if ok, err := s.DeleteTeam(ctx, "backend"); !ok || err != nil {
+		t.Fatalf("DeleteTeam = %v, %v, want true", ok, err)
+	}
+	if team, err := s.GetTeam(ctx, "backend"); team != nil || err != nil {
+		t.Errorf("GetTeam of deleted team = %+v, %v, want nil", team, err)
+	}
+	if exists, err := s.TeamExists(ctx, "backend"); exists || err != nil {
+		t.Errorf("TeamExists of deleted team = %v, %v, want false", exists, err)
+	}
+	if ok, err := s.RestoreUser(ctx, "u2"); ok || err != nil {
+		t.Errorf("RestoreUser of deleted team = %v, %v, want false", ok, err)
+	}
+
+	// u1 was deleted on its own and stays deleted.
+	if ok, err := s.RestoreTeam(ctx, "backend"); !ok || err != nil {
+		t.Fatalf("RestoreTeam = %v, %v, want true", ok, err)
+	}
+	team, err := s.GetTeam(ctx, "backend")
+	if err != nil || team == nil || len(team.Members) != 2 {
+		t.Fatalf("GetTeam after restore = %+v, %v, want u2 and u3", team, err)
+	}
+	if ok, err := s.RestoreUser(ctx, "u1"); !ok || err != nil {
+		t.Errorf("RestoreUser = %v, %v, want true", ok, err)
+	}
+	if user, err := s.GetUser(ctx, "u1"); user == nil || err != nil {
+		t.Errorf("GetUser after restore = %+v, %v, want the user", user, err)
+	}
+}
+
+func testPurgeDeleted(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+	createPullRequest(t, s, newPullRequest("pr-1", "u1", "u2"))
+
+	if _, err := s.DeleteTeam(ctx, "backend"); err != nil {
+		t.Fatalf("DeleteTeam: %v", err)
+	}
+	if n, err := s.PurgeDeletedUsers(ctx, time.Now().Add(-24*time.Hour)); n != 0 || err != nil {
+		t.Errorf("PurgeDeletedUsers of recent deletions = %d, %v, want 0", n, err)
+	}
+
+	// u1 and u2 are on a PR and are erased, u3 is removed.
+	if n, err := s.PurgeDeletedUsers(ctx, time.Now().Add(24*time.Hour)); n != 3 || err != nil {
+		t.Errorf("PurgeDeletedUsers = %d, %v, want 3", n, err)
+	}
+	if pr, err := s.GetPullRequest(ctx, "pr-1"); pr == nil || err != nil {
+		t.Errorf("GetPullRequest after purge = %+v, %v, want the PR kept", pr, err)
+	}
+	if ok, err := s.RestoreTeam(ctx, "backend"); !ok || err != nil {
+		t.Fatalf("RestoreTeam = %v, %v, want true", ok, err)
+	}
+	if team, err := s.GetTeam(ctx, "backend"); err != nil || team == nil || len(team.Members) != 0 {
+		t.Errorf("GetTeam after purge and restore = %+v, %v, want no members", team, err)
+	}
+}
+
+func testFeatureFlags(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+
+	flag := &models.FeatureFlag{Name: "beta", Enabled: true, Teams: []string{"backend"}, Percentage: 10}
+	if err := s.UpsertFeatureFlag(ctx, flag); err != nil {
+		t.Fatalf("UpsertFeatureFlag: %v", err)
+	}
+	if flag.UpdatedAt == nil {
+		t.Error("UpsertFeatureFlag left UpdatedAt unset")
+	}
+	flag.Teams = []string{}
+	flag.Percentage = 50
+	if err := s.UpsertFeatureFlag(ctx, flag); err != nil {
+		t.Fatalf("UpsertFeatureFlag update: %v", err)
+	}
+	if err := s.UpsertFeatureFlag(ctx, &models.FeatureFlag{Name: "alpha", Teams: []string{}}); err != nil {
+		t.Fatalf("UpsertFeatureFlag alpha: %v", err)
+	}
+
+	flags, err := s.GetFeatureFlags(ctx)
+	if err != nil {
+		t.Fatalf("GetFeatureFlags: %v", err)
+	}
+	if len(flags) != 2 || flags[0].Name != "alpha" || flags[1].Name != "beta" {
+		t.Fatalf("GetFeatureFlags = %+v, want alpha and beta (ordered by name)", flags)
+	}
+	if got := flags[1]; !got.Enabled || got.Percentage != 50 || len(got.Teams) != 0 {
+		t.Errorf("GetFeatureFlags beta = %+v, want the updated flag", got)
+	}
+
+	deleted, err := s.DeleteFeatureFlag(ctx, "beta")
+	if err != nil || !deleted {
+		t.Errorf("DeleteFeatureFlag = %v, %v, want true, nil", deleted, err)
+	}
+	deleted, err = s.DeleteFeatureFlag(ctx, "beta")
+	if err != nil || deleted {
+		t.Errorf("DeleteFeatureFlag again = %v, %v, want false, nil", deleted, err)
+	}
+}
+
+func testMaintenance(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+
+	if m, err := s.GetMaintenance(ctx); err != nil || m != nil {
+		t.Fatalf("GetMaintenance before setting = %+v, %v, want nil, nil", m, err)
+	}
+	on := &models.Maintenance{Enabled: true, Message: "migrating"}
+	if err := s.SetMaintenance(ctx, on); err != nil {
+		t.Fatalf("SetMaintenance: %v", err)
+	}
+	if on.UpdatedAt == nil {
+		t.Error("SetMaintenance left UpdatedAt unset")
+	}
+	if err := s.SetMaintenance(ctx, &models.Maintenance{}); err != nil {
+		t.Fatalf("SetMaintenance off: %v", err)
+	}
+
+	m, err := s.GetMaintenance(ctx)
+	if err != nil {
+		t.Fatalf("GetMaintenance: %v", err)
+	}
+	if m == nil || m.Enabled || m.Message != "" || m.UpdatedAt == nil {
+		t.Errorf("GetMaintenance = %+v, want the mode switched off", m)
+	}
+}
+
+// testSchemaMigrations expects a migrated database, which records every
+// migration from the first on.
+func testSchemaMigrations(t *testing.T, s repository.Storage) {
+	migrations, err := s.GetSchemaMigrations(context.Background())
+	if err != nil {
+		t.Fatalf("GetSchemaMigrations: %v", err)
+	}
+	for i, m := range migrations {
+		if m.Version != i+1 || m.Name == "" {
+			t.Fatalf("GetSchemaMigrations[%d] = %+v, want version %d with a name", i, m, i+1)
+		}
+	}
+	if len(migrations) == 0 {
+		t.Error("GetSchemaMigrations returned nothing, want the applied migrations")
+	}
+}
+
+func testRepositories(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	repo := &models.Repository{Name: "api", DefaultTeam: "backend", Settings: models.RepositorySettings{ReviewerCount: 3}}
+	if err := s.CreateRepository(ctx, repo); err != nil {
+		t.Fatalf("CreateRepository: %v", err)
+	}
+	if repo.CreatedAt == nil {
+		t.Error("CreateRepository left CreatedAt unset")
+	}
+	if err := s.CreateRepository(ctx, &models.Repository{Name: "api"}); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Errorf("CreateRepository duplicate error = %v, want ErrAlreadyExists", err)
+	}
+	if err := s.CreateRepository(ctx, &models.Repository{Name: "web", DefaultTeam: "ghosts"}); !errors.Is(err, repository.ErrForeignKeyMissing) {
+		t.Errorf("CreateRepository unknown team error = %v, want ErrForeignKeyMissing", err)
+	}
+	if err := s.CreateRepository(ctx, &models.Repository{Name: "docs"}); err != nil {
+		t.Fatalf("CreateRepository docs: %v", err)
+	}
+
+	repo.DefaultTeam = ""
+	repo.Settings.RequiredApprovals = 2
+	if updated, err := s.UpdateRepository(ctx, repo); err != nil || !updated {
+		t.Fatalf("UpdateRepository = %v, %v, want true, nil", updated, err)
+	}
+	got, err := s.GetRepository(ctx, "api")
+	if err != nil {
+		t.Fatalf("GetRepository: %v", err)
+	}
+	if got == nil || got.DefaultTeam != "" || got.Settings != (models.RepositorySettings{ReviewerCount: 3, RequiredApprovals: 2}) {
+		t.Errorf("GetRepository = %+v, want the updated repository", got)
+	}
+	if updated, err := s.UpdateRepository(ctx, &models.Repository{Name: "ghost"}); err != nil || updated {
+		t.Errorf("UpdateRepository unknown = %v, %v, want false, nil", updated, err)
+	}
+
+	repos, err := s.ListRepositories(ctx, "")
+	if err != nil {
+		t.Fatalf("ListRepositories: %v", err)
+	}
+	if len(repos) != 2 || repos[0].Name != "api" || repos[1].Name != "docs" {
+		t.Errorf("ListRepositories = %+v, want api and docs (ordered by name)", repos)
+	}
+	if repos, err := s.ListRepositories(ctx, "backend"); err != nil || len(repos) != 0 {
+		t.Errorf("ListRepositories backend = %+v, %v, want none", repos, err)
+	}
+
+	deleted, err := s.DeleteRepository(ctx, "api")
+	if err != nil || !deleted {
+		t.Errorf("DeleteRepository = %v, %v, want true, nil", deleted, err)
+	}
+	if got, err := s.GetRepository(ctx, "api"); err != nil || got != nil {
+		t.Errorf("GetRepository after delete = %+v, %v, want nil, nil", got, err)
+	}
+}
+
+func testCodeOwners(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	if err := s.CreateRepository(ctx, &models.Repository{Name: "api"}); err != nil {
+		t.Fatalf("CreateRepository: %v", err)
+	}
+
+	rules := []models.CodeOwnersRule{
+		{Line: 1, Pattern: "*", Owners: []string{"u1"}},
+		{Line: 3, Pattern: "/docs/", Owners: []string{"u2", "u3"}},
+		{Line: 4, Pattern: "/vendor/", Owners: []string{}},
+	}
+	if err := s.SetCodeOwners(ctx, "api", rules); err != nil {
+		t.Fatalf("SetCodeOwners: %v", err)
+	}
+	if err := s.SetCodeOwners(ctx, "api", rules[1:]); err != nil {
+		t.Fatalf("SetCodeOwners again: %v", err)
+	}
+	got, err := s.GetCodeOwners(ctx, "api")
+	if err != nil {
+		t.Fatalf("GetCodeOwners: %v", err)
+	}
+	if !reflect.DeepEqual(got, rules[1:]) {
+		t.Errorf("GetCodeOwners = %+v, want the replacing rules %+v", got, rules[1:])
+	}
+
+	if err := s.SetCodeOwners(ctx, "ghost", rules); !errors.Is(err, repository.ErrForeignKeyMissing) {
+		t.Errorf("SetCodeOwners unknown repository error = %v, want ErrForeignKeyMissing", err)
+	}
+
+	if _, err := s.DeleteRepository(ctx, "api"); err != nil {
+		t.Fatalf("DeleteRepository: %v", err)
+	}
+	if got, err := s.GetCodeOwners(ctx, "api"); err != nil || len(got) != 0 {
+		t.Errorf("GetCodeOwners after delete = %+v, %v, want none", got, err)
+	}
+}
+
+func testDirectoryManagedUsers(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	adopted := &models.User{UserID: "u2", Username: "Bob", TeamName: "backend", IsActive: true, ReviewWeight: 1, DirectoryManaged: true}
+	if err := s.UpdateUser(ctx, adopted); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	created := &models.User{UserID: "u4", Username: "Dave", TeamName: "backend", IsActive: true, ReviewWeight: 1, DirectoryManaged: true}
+	if err := s.CreateUser(ctx, created); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	users, err := s.GetDirectoryManagedUsers(ctx)
+	if err != nil {
+		t.Fatalf("GetDirectoryManagedUsers: %v", err)
+	}
+	got := []string{}
+	for _, u := range users {
+		if !u.DirectoryManaged {
+			t.Errorf("GetDirectoryManagedUsers returned %s without DirectoryManaged", u.UserID)
+		}
+		got = append(got, u.UserID)
+	}
+	if want := []string{"u2", "u4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDirectoryManagedUsers = %v, want %v", got, want)
+	}
+
+	if ok, err := s.DeleteUser(ctx, "u4"); !ok || err != nil {
+		t.Fatalf("DeleteUser = %v, %v", ok, err)
+	}
+	if users, err := s.GetDirectoryManagedUsers(ctx); err != nil || len(users) != 1 || users[0].UserID != "u2" {
+		t.Errorf("GetDirectoryManagedUsers after delete = %+v, %v, want only u2", users, err)
+	}
+	if ids, err := s.GetDeletedUserIDs(ctx, []string{"u2", "u4", "ghost"}); err != nil || !reflect.DeepEqual(ids, []string{"u4"}) {
+		t.Errorf("GetDeletedUserIDs = %v, %v, want [u4]", ids, err)
+	}
+	if deleted, err := s.TeamDeleted(ctx, "backend"); err != nil || deleted {
+		t.Errorf("TeamDeleted of a live team = %v, %v", deleted, err)
+	}
+	if ok, err := s.DeleteTeam(ctx, "backend"); !ok || err != nil {
+		t.Fatalf("DeleteTeam = %v, %v", ok, err)
+	}
+	if deleted, err := s.TeamDeleted(ctx, "backend"); err != nil || !deleted {
+		t.Errorf("TeamDeleted of a deleted team = %v, %v", deleted, err)
+	}
+}
+
+func testEventLog(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+	createPullRequest(t, s, newPullRequest("pr-1", "u1", "u2"))
+	createPullRequest(t, s, newPullRequest("pr-2", "u1", "u3"))
+
+	for _, event := range []models.Event{
+		{Type: models.EventPRCreated, PullRequestID: "pr-2", CreatedAt: epoch},
+		{Type: models.EventPRCreated, PullRequestID: "pr-1", CreatedAt: epoch,
+			Assignments: []models.AssignmentEvent{{UserID: "u2", Role: models.RoleReviewer, Action: models.ActionAssigned}}},
+		{Type: models.EventPRMerged, PullRequestID: "pr-1", CreatedAt: epoch.Add(time.Hour)},
+	} {
+		if err := s.AppendEvent(ctx, event); err != nil {
+			t.Fatalf("AppendEvent: %v", err)
+		}
+	}
+
+	if n, err := s.CountEventPullRequests(ctx); err != nil || n != 2 {
+		t.Errorf("CountEventPullRequests = %d, %v, want 2", n, err)
+	}
+	first, err := s.GetEventPullRequests(ctx, "", 1)
+	if err != nil || !reflect.DeepEqual(first, []string{"pr-1"}) {
+		t.Errorf("GetEventPullRequests = %v, %v, want [pr-1]", first, err)
+	}
+	rest, err := s.GetEventPullRequests(ctx, "pr-1", 10)
+	if err != nil || !reflect.DeepEqual(rest, []string{"pr-2"}) {
+		t.Errorf("GetEventPullRequests after pr-1 = %v, %v, want [pr-2]", rest, err)
+	}
+
+	events, err := s.GetPullRequestEvents(ctx, "pr-1")
+	if err != nil || len(events) != 2 {
+		t.Fatalf("GetPullRequestEvents = %+v, %v, want 2 events", events, err)
+	}
+	if events[0].Type != models.EventPRCreated || events[1].Type != models.EventPRMerged ||
+		events[0].ID >= events[1].ID || len(events[0].Assignments) != 1 || events[0].Assignments[0].UserID != "u2" {
+		t.Errorf("events = %+v", events)
+	}
+
+	at := epoch.Add(time.Minute)
+	err = s.ReplaceAssignmentHistory(ctx, "pr-1", []models.AssignmentEvent{
+		{PullRequestID: "pr-1", UserID: "u3", Role: models.RoleReviewer, Action: models.ActionAssigned, CreatedAt: &at},
+	})
+	if err != nil {
+		t.Fatalf("ReplaceAssignmentHistory: %v", err)
+	}
+	history, err := s.GetAssignmentHistory(ctx, "pr-1")
+	if err != nil || len(history) != 1 || history[0].UserID != "u3" || !history[0].CreatedAt.Equal(at) {
+		t.Errorf("history after replace = %+v, %v", history, err)
+	}
+}
+
+func testEventLogOmitsReviews(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+	pr := newPullRequest("pr-1", "u1", "u2")
+	createPullRequest(t, s, pr)
+
+	pr.Reviews = []models.Review{{PullRequestID: "pr-1", ReviewerID: "u2", State: models.ReviewCommented, Comment: "see line 42"}}
+	if err := s.AppendEvent(ctx, models.Event{Type: models.EventPRUpdated, PullRequestID: "pr-1", PullRequest: pr, CreatedAt: epoch}); err != nil {
+		t.Fatalf("AppendEvent: %v", err)
+	}
+	if len(pr.Reviews) != 1 {
+		t.Errorf("AppendEvent changed the event's PR: reviews = %+v", pr.Reviews)
+	}
+	if err := s.EraseUser(ctx, "u2"); err != nil {
+		t.Fatalf("EraseUser: %v", err)
+	}
+
+	events, err := s.GetPullRequestEvents(ctx, "pr-1")
+	if err != nil || len(events) != 1 || events[0].PullRequest == nil {
+		t.Fatalf("GetPullRequestEvents = %+v, %v, want the event with its PR", events, err)
+	}
+	if reviews := events[0].PullRequest.Reviews; len(reviews) != 0 {
+		t.Errorf("logged reviews = %+v, want none kept after erasure", reviews)
+	}
+}
+
+func testWebhookDeliveries(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	due := epoch
+	for _, event := range []models.NotificationEvent{models.EventPullRequestStale, models.EventReviewDigest} {
+		d := &models.WebhookDelivery{Event: event, URL: "http://chat", Payload: []byte(`{"text":"hi"}`),
+			Status: models.DeliveryPending, NextAttemptAt: &due}
+		if err := s.AddWebhookDelivery(ctx, d); err != nil || d.ID == 0 {
+			t.Fatalf("AddWebhookDelivery = %d, %v", d.ID, err)
+		}
+	}
+
+	claimed, err := s.ClaimWebhookDeliveries(ctx, epoch, epoch.Add(time.Minute), 10)
+	if err != nil || len(claimed) != 2 || claimed[0].Event != models.EventPullRequestStale || string(claimed[0].Payload) != `{"text": "hi"}` {
+		t.Fatalf("ClaimWebhookDeliveries = %+v, %v, want both in order", claimed, err)
+	}
+	if again, err := s.ClaimWebhookDeliveries(ctx, epoch, epoch.Add(time.Minute), 10); err != nil || len(again) != 0 {
+		t.Errorf("claimed %+v again while leased (%v)", again, err)
+	}
+
+	delivered, dead := claimed[0], claimed[1]
+	at := epoch.Add(time.Second)
+	delivered.Status, delivered.Attempts, delivered.LastStatus, delivered.NextAttemptAt, delivered.DeliveredAt = models.DeliveryDelivered, 1, 200, nil, &at
+	dead.Status, dead.Attempts, dead.LastStatus, dead.LastError, dead.NextAttemptAt = models.DeliveryDead, 1, 502, "bad gateway", nil
+	for _, d := range []models.WebhookDelivery{delivered, dead} {
+		if err := s.RecordWebhookAttempt(ctx, d); err != nil {
+			t.Fatalf("RecordWebhookAttempt: %v", err)
+		}
+	}
+
+	list, err := s.ListWebhookDeliveries(ctx, models.DeliveryFilter{Status: models.DeliveryDead, Limit: 10})
+	if err != nil || len(list) != 1 || list[0].ID != dead.ID || list[0].LastStatus != 502 || list[0].LastError != "bad gateway" {
+		t.Fatalf("dead deliveries = %+v, %v", list, err)
+	}
+	if all, err := s.ListWebhookDeliveries(ctx, models.DeliveryFilter{Limit: 10}); err != nil || len(all) != 2 || all[0].ID != dead.ID {
+		t.Errorf("all deliveries = %+v, %v, want both, newest first", all, err)
+	}
+
+	if ok, err := s.RetryWebhookDelivery(ctx, delivered.ID, epoch); err != nil || ok {
+		t.Errorf("RetryWebhookDelivery of a delivered one = %v, %v, want false", ok, err)
+	}
+	if ok, err := s.RetryWebhookDelivery(ctx, dead.ID, epoch); err != nil || !ok {
+		t.Fatalf("RetryWebhookDelivery = %v, %v, want true", ok, err)
+	}
+	if got, err := s.GetWebhookDelivery(ctx, dead.ID); err != nil || got.Status != models.DeliveryPending || !got.NextAttemptAt.Equal(epoch) {
+		t.Errorf("retried delivery = %+v, %v, want pending and due", got, err)
+	}
+
+	if n, err := s.PurgeWebhookDeliveries(ctx, epoch.Add(time.Hour)); err != nil || n != 1 {
+		t.Errorf("PurgeWebhookDeliveries = %d, %v, want the delivered one", n, err)
+	}
+	if got, err := s.GetWebhookDelivery(ctx, delivered.ID); err != nil || got != nil {
+		t.Errorf("purged delivery = %+v, %v, want nil", got, err)
+	}
+}
+
+func testOutbox(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	due := epoch
+	err := s.AddOutboxMessages(ctx, []models.OutboxMessage{
+		{Kind: models.OutboxReviewTaskCreate, PullRequestID: "pr-1", UserID: "u2", NextAttemptAt: &due},
+		{Kind: models.OutboxReviewTaskApprove, PullRequestID: "pr-1", UserID: "u2", NextAttemptAt: &due},
+		{Kind: models.OutboxReviewTaskMerge, PullRequestID: "pr-2", NextAttemptAt: &due},
+	})
+	if err != nil {
+		t.Fatalf("AddOutboxMessages: %v", err)
+	}
+
+	claim := func(now time.Time) []models.OutboxMessage {
+		t.Helper()
+		messages, err := s.ClaimOutboxMessages(ctx, now, now.Add(time.Minute), 10)
+		if err != nil {
+			t.Fatalf("ClaimOutboxMessages: %v", err)
+		}
+		return messages
+	}
+	kinds := func(messages []models.OutboxMessage) []models.OutboxKind {
+		got := []models.OutboxKind{}
+		for _, m := range messages {
+			got = append(got, m.Kind)
+		}
+		return got
+	}
+
+	if got := claim(epoch.Add(-time.Second)); len(got) != 0 {
+		t.Errorf("claimed %v before they were due", kinds(got))
+	}
+	// The approval waits for the creation of the same PR's task.
+	first := claim(epoch)
+	if want := []models.OutboxKind{models.OutboxReviewTaskCreate, models.OutboxReviewTaskMerge}; !reflect.DeepEqual(kinds(first), want) {
+		t.Fatalf("claimed %v, want %v", kinds(first), want)
+	}
+	if got := claim(epoch); len(got) != 0 {
+		t.Errorf("claimed %v again while leased", kinds(got))
+	}
+
+	retry := epoch.Add(time.Hour)
+	if err := s.FailOutboxMessage(ctx, first[0].ID, "timeout", &retry); err != nil {
+		t.Fatalf("FailOutboxMessage: %v", err)
+	}
+	if err := s.FailOutboxMessage(ctx, first[1].ID, "gone", nil); err != nil {
+		t.Fatalf("FailOutboxMessage without retry: %v", err)
+	}
+	again := claim(retry)
+	if len(again) != 1 || again[0].ID != first[0].ID || again[0].Attempts != 1 || again[0].LastError != "timeout" {
+		t.Fatalf("claimed %+v after the retry time, want the failed creation with 1 attempt", again)
+	}
+
+	if err := s.DeleteOutboxMessage(ctx, first[0].ID); err != nil {
+		t.Fatalf("DeleteOutboxMessage: %v", err)
+	}
+	if got := claim(retry); !reflect.DeepEqual(kinds(got), []models.OutboxKind{models.OutboxReviewTaskApprove}) {
+		t.Errorf("claimed %v after the creation was delivered, want the approval", kinds(got))
+	}
+}
+
+func testReviewTasks(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+	createPullRequest(t, s, newPullRequest("pr-1", "u1", "u2", "u3"))
+
+	if key, err := s.GetReviewIssue(ctx, "pr-1"); err != nil || key != "" {
+		t.Errorf("GetReviewIssue before set = %q, %v, want none", key, err)
+	}
+	if err := s.SetReviewIssue(ctx, "pr-1", "REV-1"); err != nil {
+		t.Fatalf("SetReviewIssue: %v", err)
+	}
+	if key, err := s.GetReviewIssue(ctx, "pr-1"); err != nil || key != "REV-1" {
+		t.Errorf("GetReviewIssue = %q, %v, want REV-1", key, err)
+	}
+
+	for _, task := range []models.ReviewTask{
+		{PullRequestID: "pr-1", UserID: "u3", IssueKey: "REV-3"},
+		{PullRequestID: "pr-1", UserID: "u2", IssueKey: "REV-2"},
+	} {
+		if err := s.AddReviewTask(ctx, task); err != nil {
+			t.Fatalf("AddReviewTask %+v: %v", task, err)
+		}
+	}
+	tasks, err := s.GetReviewTasks(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetReviewTasks: %v", err)
+	}
+	want := []models.ReviewTask{
+		{PullRequestID: "pr-1", UserID: "u2", IssueKey: "REV-2"},
+		{PullRequestID: "pr-1", UserID: "u3", IssueKey: "REV-3"},
+	}
+	if !reflect.DeepEqual(tasks, want) {
+		t.Errorf("GetReviewTasks = %+v, want %+v", tasks, want)
+	}
+
+	if err := s.SetReviewIssue(ctx, "ghost", "REV-9"); !errors.Is(err, repository.ErrForeignKeyMissing) {
+		t.Errorf("SetReviewIssue unknown PR error = %v, want ErrForeignKeyMissing", err)
+	}
+}
+
+func testDigestPreferences(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	if prefs, err := s.GetDigestPreferences(ctx, "u1"); err != nil || prefs != nil {
+		t.Fatalf("GetDigestPreferences before set = %+v, %v, want nil", prefs, err)
+	}
+	for _, prefs := range []*models.DigestPreferences{
+		{UserID: "u2", Enabled: true, SendAt: "09:30", Provider: models.NotifySlack},
+		{UserID: "u1", Enabled: true, SendAt: "08:00", Channel: "https://chat.example.com/hooks/u1"},
+		{UserID: "u3", Enabled: false, SendAt: "09:00"},
+	} {
+		if err := s.SetDigestPreferences(ctx, prefs); err != nil {
+			t.Fatalf("SetDigestPreferences %s: %v", prefs.UserID, err)
+		}
+	}
+	if err := s.MarkDigestSent(ctx, "u1", epoch); err != nil {
+		t.Fatalf("MarkDigestSent: %v", err)
+	}
+
+	enabled, err := s.GetEnabledDigests(ctx)
+	if err != nil {
+		t.Fatalf("GetEnabledDigests: %v", err)
+	}
+	if len(enabled) != 2 || enabled[0].UserID != "u1" || enabled[1].UserID != "u2" {
+		t.Fatalf("GetEnabledDigests = %+v, want u1 and u2", enabled)
+	}
+	if got := enabled[0]; got.SendAt != "08:00" || got.Channel != "https://chat.example.com/hooks/u1" || got.LastSentAt == nil || !got.LastSentAt.Equal(epoch) {
+		t.Errorf("u1 preferences = %+v", got)
+	}
+	if got := enabled[1]; got.Provider != models.NotifySlack || got.LastSentAt != nil {
+		t.Errorf("u2 preferences = %+v", got)
+	}
+
+	// Changing preferences keeps the last send time.
+	update := &models.DigestPreferences{UserID: "u1", Enabled: false, SendAt: "10:00"}
+	if err := s.SetDigestPreferences(ctx, update); err != nil {
+		t.Fatalf("SetDigestPreferences update: %v", err)
+	}
+	if update.LastSentAt == nil || !update.LastSentAt.Equal(epoch) {
+		t.Errorf("LastSentAt after update = %v, want %v", update.LastSentAt, epoch)
+	}
+
+	if err := s.SetDigestPreferences(ctx, &models.DigestPreferences{UserID: "ghost", SendAt: "09:00"}); !errors.Is(err, repository.ErrForeignKeyMissing) {
+		t.Errorf("SetDigestPreferences unknown user error = %v, want ErrForeignKeyMissing", err)
+	}
+}
+
+func testEscalations(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	idle := func() []models.IdleReview {
+		t.Helper()
+		reviews, err := s.GetIdleReviews(ctx)
+		if err != nil {
+			t.Fatalf("GetIdleReviews: %v", err)
+		}
+		return reviews
+	}
+
+	createPullRequest(t, s, newPullRequest("pr-1", "u1", "u2", "u3"))
+	draft := newPullRequest("pr-2", "u1", "u2")
+	draft.IsDraft = true
+	createPullRequest(t, s, draft)
+	assignedAt := epoch.Add(time.Hour)
+	err := s.AddAssignmentEvents(ctx, []models.AssignmentEvent{{
+		PullRequestID: "pr-1", UserID: "u2", Role: models.RoleReviewer, Action: models.ActionAssigned, CreatedAt: &assignedAt,
+	}})
+	if err != nil {
+		t.Fatalf("AddAssignmentEvents: %v", err)
+	}
+	if err := s.CreateReview(ctx, &models.Review{PullRequestID: "pr-1", ReviewerID: "u3", State: models.ReviewCommented}); err != nil {
+		t.Fatalf("CreateReview: %v", err)
+	}
+
+	if got := idle(); len(got) != 0 {
+		t.Fatalf("GetIdleReviews without a policy = %+v, want none", got)
+	}
+	if err := s.UpsertTeamSettings(ctx, &models.TeamSettings{TeamName: "backend", RemindAfterHours: 4}); err != nil {
+		t.Fatalf("UpsertTeamSettings: %v", err)
+	}
+
+	// u3 reviewed and the draft is not up for review yet.
+	got := idle()
+	if len(got) != 1 || got[0].PullRequestID != "pr-1" || got[0].UserID != "u2" || got[0].TeamName != "backend" ||
+		!got[0].AssignedAt.Equal(assignedAt) || len(got[0].Taken) != 0 {
+		t.Fatalf("GetIdleReviews = %+v, want u2 on pr-1 assigned at %v", got, assignedAt)
+	}
+
+	e := &models.Escalation{
+		PullRequestID: "pr-1",
+		UserID:        "u2",
+		TeamName:      "backend",
+		Action:        models.EscalationReminded,
+		AssignedAt:    got[0].AssignedAt,
+	}
+	if err := s.AddEscalation(ctx, e); err != nil {
+		t.Fatalf("AddEscalation: %v", err)
+	}
+	if e.ID == 0 || e.CreatedAt == nil {
+		t.Errorf("AddEscalation left ID %d, created_at %v unset", e.ID, e.CreatedAt)
+	}
+	if got := idle(); len(got) != 1 || len(got[0].Taken) != 1 || got[0].Taken[0] != models.EscalationReminded {
+		t.Errorf("GetIdleReviews after reminder = %+v, want the reminder taken", got)
+	}
+
+	// Assigning the reviewer again starts their escalation afresh.
+	reassignedAt := epoch.Add(2 * time.Hour)
+	err = s.AddAssignmentEvents(ctx, []models.AssignmentEvent{{
+		PullRequestID: "pr-1", UserID: "u2", Role: models.RoleReviewer, Action: models.ActionAdded, CreatedAt: &reassignedAt,
+	}})
+	if err != nil {
+		t.Fatalf("AddAssignmentEvents: %v", err)
+	}
+	if got := idle(); len(got) != 1 || !got[0].AssignedAt.Equal(reassignedAt) || len(got[0].Taken) != 0 {
+		t.Errorf("GetIdleReviews after new assignment = %+v, want nothing taken", got)
+	}
+
+	escalations, err := s.ListEscalations(ctx, "backend", 10, 0)
+	if err != nil {
+		t.Fatalf("ListEscalations: %v", err)
+	}
+	if len(escalations) != 1 || escalations[0].ID != e.ID || escalations[0].Action != models.EscalationReminded ||
+		!escalations[0].AssignedAt.Equal(assignedAt) {
+		t.Errorf("ListEscalations = %+v, want the reminder", escalations)
+	}
+	if escalations, err := s.ListEscalations(ctx, "frontend", 10, 0); err != nil || len(escalations) != 0 {
+		t.Errorf("ListEscalations of another team = %+v, %v, want none", escalations, err)
+	}
+}
+
+func testTeamActivity(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	now := epoch.Add(24 * time.Hour)
+	for _, pr := range []struct {
+		id        string
+		daysAgo   int
+		reviewers []string
+	}{
+		{"pr-1", 1, []string{"u2", "u3"}},
+		{"pr-2", 8, []string{"u2"}},
+		{"pr-3", 9, []string{"u3"}},
+		// Before the two weeks counted, but still open.
+		{"pr-4", 30, []string{"u3"}},
+	} {
+		p := newPullRequest(pr.id, "u1", pr.reviewers...)
+		createdAt := now.AddDate(0, 0, -pr.daysAgo)
+		p.CreatedAt = &createdAt
+		createPullRequest(t, s, p)
+	}
+	merged, err := s.GetPullRequest(ctx, "pr-3")
+	if err != nil {
+		t.Fatalf("GetPullRequest: %v", err)
+	}
+	mergedAt := now.AddDate(0, 0, -2)
+	merged.Status = models.StatusMerged
+	merged.MergedAt = &mergedAt
+	if updated, err := s.UpdatePullRequestStatus(ctx, merged, models.StatusOpen); err != nil || !updated {
+		t.Fatalf("UpdatePullRequestStatus = %v, %v", updated, err)
+	}
+
+	activity, err := s.GetTeamActivity(ctx, now, 2)
+	if err != nil {
+		t.Fatalf("GetTeamActivity: %v", err)
+	}
+	if len(activity) != 1 {
+		t.Fatalf("GetTeamActivity = %+v, want backend only", activity)
+	}
+	a := activity[0]
+	if a.TeamName != "backend" || !reflect.DeepEqual(a.WeeklyPullRequests, []int{2, 1}) || !reflect.DeepEqual(a.WeeklyMerges, []int{0, 1}) ||
+		a.EligibleReviewers != 3 || a.OpenReviews != 4 {
+		t.Errorf("backend activity = %+v, want opened [2 1], merged [0 1], 3 reviewers and 4 open reviews", a)
+	}
+}
+
+func testAnomalies(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	createPullRequest(t, s, newPullRequest("pr-1", "u1", "u2"))
+	createPullRequest(t, s, newPullRequest("pr-2", "u1", "u2", "u3"))
+	before := epoch.Add(-2 * time.Hour)
+	event := func(prID, userID string, action models.AssignmentAction, at time.Time) models.AssignmentEvent {
+		return models.AssignmentEvent{PullRequestID: prID, UserID: userID, Role: models.RoleReviewer, Action: action, CreatedAt: &at}
+	}
+	err := s.AddAssignmentEvents(ctx, []models.AssignmentEvent{
+		event("pr-1", "u2", models.ActionAssigned, epoch),
+		event("pr-2", "u2", models.ActionAssigned, epoch),
+		event("pr-2", "u3", models.ActionAdded, epoch),
+		event("pr-2", "u3", models.ActionRemoved, epoch),
+		event("pr-1", "u3", models.ActionAssigned, before),
+	})
+	if err != nil {
+		t.Fatalf("AddAssignmentEvents: %v", err)
+	}
+
+	counts, err := s.GetAssignmentCounts(ctx, epoch.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetAssignmentCounts: %v", err)
+	}
+	want := []models.AssignmentCount{{TeamName: "backend", UserID: "u2", Assignments: 2}, {TeamName: "backend", UserID: "u3", Assignments: 1}}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("GetAssignmentCounts = %+v, want %+v", counts, want)
+	}
+
+	concentration := models.Anomaly{Kind: models.AnomalyAssignmentConcentration, TeamName: "backend", UserID: "u2", Value: 0.67, Threshold: 0.5, Message: "u2"}
+	drop := models.Anomaly{Kind: models.AnomalyMergeDrop, TeamName: "backend", Value: 0.8, Threshold: 0.5, Message: "drop"}
+	added, err := s.ReplaceAnomalies(ctx, []models.Anomaly{concentration, drop}, epoch)
+	if err != nil {
+		t.Fatalf("ReplaceAnomalies: %v", err)
+	}
+	if len(added) != 2 {
+		t.Fatalf("ReplaceAnomalies added %+v, want both", added)
+	}
+
+	// A finding made again keeps its detection time; one not made again is
+	// dropped.
+	concentration.Value = 0.7
+	added, err = s.ReplaceAnomalies(ctx, []models.Anomaly{concentration}, epoch.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ReplaceAnomalies again: %v", err)
+	}
+	if len(added) != 0 {
+		t.Errorf("ReplaceAnomalies again added %+v, want none", added)
+	}
+	current, err := s.GetAnomalies(ctx)
+	if err != nil {
+		t.Fatalf("GetAnomalies: %v", err)
+	}
+	if len(current) != 1 || current[0].UserID != "u2" || current[0].Value != 0.7 || current[0].DetectedAt == nil || !current[0].DetectedAt.Equal(epoch) {
+		t.Errorf("GetAnomalies = %+v, want u2's finding detected at %v", current, epoch)
+	}
+}
+
+func testSeries(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	for i, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		pr := newPullRequest(id, "u1", "u2")
+		createdAt := epoch.Add(time.Duration(i*i) * time.Hour)
+		pr.CreatedAt = &createdAt
+		createPullRequest(t, s, pr)
+	}
+	merged, err := s.GetPullRequest(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("GetPullRequest: %v", err)
+	}
+	mergedAt := epoch.Add(90 * time.Minute)
+	merged.Status = models.StatusMerged
+	merged.MergedAt = &mergedAt
+	if updated, err := s.UpdatePullRequestStatus(ctx, merged, models.StatusOpen); err != nil || !updated {
+		t.Fatalf("UpdatePullRequestStatus = %v, %v", updated, err)
+	}
+
+	// PRs were opened at 0h, 1h and 4h; the one at 4h is out of range and
+	// the bucket at 2h is empty.
+	points, err := s.GetSeries(ctx, models.SeriesPullRequestsOpened, epoch, epoch.Add(4*time.Hour), 2*time.Hour)
+	if err != nil {
+		t.Fatalf("GetSeries: %v", err)
+	}
+	if len(points) != 1 || !points[0].Time.Equal(epoch) || points[0].Value != 2 {
+		t.Errorf("GetSeries = %+v, want 2 PRs at %v", points, epoch)
+	}
+	points, err = s.GetSeries(ctx, models.SeriesPullRequestsMerged, epoch, epoch.Add(4*time.Hour), time.Hour)
+	if err != nil {
+		t.Fatalf("GetSeries merged: %v", err)
+	}
+	if len(points) != 1 || !points[0].Time.Equal(epoch.Add(time.Hour)) || points[0].Value != 1 {
+		t.Errorf("GetSeries merged = %+v, want 1 merge at %v", points, epoch.Add(time.Hour))
+	}
+
+	annotations, err := s.GetAnnotations(ctx, models.AnnotationMerges, epoch, epoch.Add(4*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("GetAnnotations: %v", err)
+	}
+	if len(annotations) != 1 || !annotations[0].Time.Equal(mergedAt) || annotations[0].Title != "Merged PR pr-1" ||
+		!reflect.DeepEqual(annotations[0].Tags, []string{"backend", string(models.PriorityNormal)}) {
+		t.Errorf("GetAnnotations = %+v, want the merge of pr-1", annotations)
+	}
+	if annotations, err := s.GetAnnotations(ctx, models.AnnotationEscalations, epoch, epoch.Add(4*time.Hour), 10); err != nil || len(annotations) != 0 {
+		t.Errorf("GetAnnotations escalations = %+v, %v, want none", annotations, err)
+	}
+}
+
+func testUserIdentities(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	for _, identity := range []*models.UserIdentity{
+		{UserID: "u1", Provider: models.ProviderGitHub, ExternalID: "alice-old"},
+		{UserID: "u1", Provider: models.ProviderGitHub, ExternalID: "alice"},
+		{UserID: "u1", Provider: models.ProviderSlack, ExternalID: "U01ALICE"},
+		{UserID: "u2", Provider: models.ProviderGitHub, ExternalID: "alice-old"},
+	} {
+		if err := s.SetUserIdentity(ctx, identity); err != nil {
+			t.Fatalf("SetUserIdentity %+v: %v", identity, err)
+		}
+		if identity.UpdatedAt == nil {
+			t.Errorf("SetUserIdentity %+v left UpdatedAt unset", identity)
+		}
+	}
+	taken := &models.UserIdentity{UserID: "u3", Provider: models.ProviderGitHub, ExternalID: "alice"}
+	if err := s.SetUserIdentity(ctx, taken); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Errorf("SetUserIdentity taken external ID error = %v, want ErrAlreadyExists", err)
+	}
+	ghost := &models.UserIdentity{UserID: "ghost", Provider: models.ProviderGitHub, ExternalID: "ghost"}
+	if err := s.SetUserIdentity(ctx, ghost); !errors.Is(err, repository.ErrForeignKeyMissing) {
+		t.Errorf("SetUserIdentity unknown user error = %v, want ErrForeignKeyMissing", err)
+	}
+
+	identities, err := s.GetUserIdentities(ctx, []string{"u1", "u2", "u3"})
+	if err != nil {
+		t.Fatalf("GetUserIdentities: %v", err)
+	}
+	got := []string{}
+	for _, identity := range identities {
+		got = append(got, identity.UserID+":"+string(identity.Provider)+":"+identity.ExternalID)
+	}
+	if want := []string{"u1:github:alice", "u1:slack:U01ALICE", "u2:github:alice-old"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetUserIdentities = %v, want %v", got, want)
+	}
+
+	user, err := s.FindUserByIdentity(ctx, models.ProviderGitHub, "alice")
+	if err != nil || user == nil || user.UserID != "u1" {
+		t.Errorf("FindUserByIdentity = %+v, %v, want u1", user, err)
+	}
+	if user, err := s.FindUserByIdentity(ctx, models.ProviderGitLab, "alice"); err != nil || user != nil {
+		t.Errorf("FindUserByIdentity other provider = %+v, %v, want nil, nil", user, err)
+	}
+
+	if deleted, err := s.DeleteUserIdentity(ctx, "u1", models.ProviderSlack); err != nil || !deleted {
+		t.Errorf("DeleteUserIdentity = %v, %v, want true, nil", deleted, err)
+	}
+	if deleted, err := s.DeleteUserIdentity(ctx, "u1", models.ProviderSlack); err != nil || deleted {
+		t.Errorf("DeleteUserIdentity again = %v, %v, want false, nil", deleted, err)
+	}
+
+	if err := s.EraseUser(ctx, "u1"); err != nil {
+		t.Fatalf("EraseUser: %v", err)
+	}
+	if identities, err := s.GetUserIdentities(ctx, []string{"u1"}); err != nil || len(identities) != 0 {
+		t.Errorf("GetUserIdentities after erase = %+v, %v, want none", identities, err)
+	}
+}
+
+func testTeamLockRollback(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	errAbort := errors.New("abort")
+	err := s.WithTeamLock(ctx, "backend", func(repo repository.Storage) error {
+		if err := repo.CreatePullRequest(ctx, newPullRequest("pr-1", "u1")); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithTeamLock = %v, want the error from fn", err)
+	}
+	if exists, err := s.PullRequestExists(ctx, "pr-1"); exists || err != nil {
+		t.Errorf("PR written before the error = %v, %v, want rolled back", exists, err)
+	}
+}
+
+func testTeamLockSerializes(t *testing.T, s repository.Storage) {
+	ctx := context.Background()
+	seedTeam(t, s)
+
+	// Every writer reads the weight and writes it back incremented; only a
+	// lock that serializes them keeps an update from being lost.
+	const writers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- s.WithTeamLock(ctx, "backend", func(repo repository.Storage) error {
+				user, err := repo.GetUser(ctx, "u1")
+				if err != nil {
+					return err
+				}
+				user.ReviewWeight++
+				return repo.UpdateUser(ctx, user)
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("WithTeamLock: %v", err)
+		}
+	}
+
+	user, err := s.GetUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.ReviewWeight != 1+writers {
+		t.Errorf("ReviewWeight = %v, want %d", user.ReviewWeight, 1+writers)
+	}
+}
+
+func testBackupRestore(t *testing.T, s repository.Storage, newStorage Factory) {
+	ctx := context.Background()
+	seedTeam(t, s)
+	createPullRequest(t, s, newPullRequest("pr-1", "u1", "u2"))
+
+	backup, err := s.Backup(ctx)
+	if err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	if _, err := s.Restore(ctx, backup, true); !errors.Is(err, repository.ErrNotEmpty) {
+		t.Errorf("Restore into non-empty storage = %v, want ErrNotEmpty", err)
+	}
+
+	target := newStorage(t)
+	if _, err := target.Restore(ctx, backup, true); err != nil {
+		t.Fatalf("Restore dry run: %v", err)
+	}
+	if exists, _ := target.TeamExists(ctx, "backend"); exists {
+		t.Fatal("dry run restore kept the data")
+	}
+	if _, err := target.Restore(ctx, backup, false); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	pr, err := target.GetPullRequest(ctx, "pr-1")
+	if err != nil || pr == nil || len(pr.AssignedReviewers) != 1 || pr.AssignedReviewers[0] != "u2" {
+		t.Errorf("restored PR = %+v, %v", pr, err)
+	}
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// IssueTracker files a task for every reviewer of a PR in an issue tracker
+// such as Jira, under an issue for the PR.
+type IssueTracker interface {
+	// ReferencedIssue returns the key of the issue the PR refers to, e.g. in
+	// its name, or "" when it refers to none.
+	ReferencedIssue(pr models.PullRequest) string
+	// CreateIssue files an issue for reviewing the PR in the project of the
+	// author's team and returns its key.
+	CreateIssue(ctx context.Context, pr models.PullRequest, teamName string) (string, error)
+	// CreateTask files the reviewer's task under parentKey, assigned to
+	// their tracker account unless assignee is empty, and returns its key.
+	CreateTask(ctx context.Context, parentKey string, pr models.PullRequest, reviewer models.User, assignee string) (string, error)
+	// TransitionTask moves the task on after event. Tasks that cannot take
+	// the transition, e.g. because they are done already, are left alone.
+	TransitionTask(ctx context.Context, key string, event models.ReviewTaskEvent) error
+}
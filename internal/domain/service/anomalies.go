@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// mergeBaselineWeeks is how many weeks before the last one a team's usual
+// merge rate is averaged over.
+const mergeBaselineWeeks = 4
+
+// AnomalyThresholds tune DetectAnomalies. A member is flagged when they got
+// more than MaxAssignmentShare of their team's reviewer assignments in the
+// last Window, once the team had MinAssignments; a team is flagged when it
+// merged more than MaxMergeDrop fewer PRs last week than its weekly average
+// of the weeks before, once that average is MinWeeklyMerges. With Notify
+// new findings are sent to the team's notification channel.
+type AnomalyThresholds struct {
+	Window             time.Duration
+	MaxAssignmentShare float64
+	MinAssignments     int
+	MaxMergeDrop       float64
+	MinWeeklyMerges    int
+	Notify             bool
+}
+
+var defaultAnomalyThresholds = AnomalyThresholds{
+	Window:             14 * 24 * time.Hour,
+	MaxAssignmentShare: 0.5,
+	MinAssignments:     10,
+	MaxMergeDrop:       0.5,
+	MinWeeklyMerges:    3,
+}
+
+// WithAnomalyThresholds sets the thresholds of DetectAnomalies; zero fields
+// keep their defaults.
+func WithAnomalyThresholds(t AnomalyThresholds) Option {
+	return func(s *Service) {
+		d := defaultAnomalyThresholds
+		if t.Window > 0 {
+			d.Window = t.Window
+		}
+		if t.MaxAssignmentShare > 0 {
+			d.MaxAssignmentShare = t.MaxAssignmentShare
+		}
+		if t.MinAssignments > 0 {
+			d.MinAssignments = t.MinAssignments
+		}
+		if t.MaxMergeDrop > 0 {
+			d.MaxMergeDrop = t.MaxMergeDrop
+		}
+		if t.MinWeeklyMerges > 0 {
+			d.MinWeeklyMerges = t.MinWeeklyMerges
+		}
+		d.Notify = t.Notify
+		s.anomalyThresholds = d
+	}
+}
+
+// GetAnomalies returns the findings of the last anomaly check.
+func (s *Service) GetAnomalies(ctx context.Context) ([]models.Anomaly, error) {
+	return s.repo.GetAnomalies(ctx)
+}
+
+// DetectAnomalies looks for unusual assignment and merge patterns, makes
+// them the current findings and returns how many are new. Findings that no
+// longer hold are dropped.
+func (s *Service) DetectAnomalies(ctx context.Context) (int, error) {
+	t := s.anomalyThresholds
+	now := s.clock.Now()
+
+	counts, err := s.repo.GetAssignmentCounts(ctx, now.Add(-t.Window))
+	if err != nil {
+		return 0, err
+	}
+	activity, err := s.repo.GetTeamActivity(ctx, now, mergeBaselineWeeks+1)
+	if err != nil {
+		return 0, err
+	}
+
+	anomalies := append(assignmentConcentration(counts, t), mergeDrops(activity, t)...)
+	added, err := s.repo.ReplaceAnomalies(ctx, anomalies, now)
+	if err != nil || !t.Notify {
+		return len(added), err
+	}
+
+	var errs []error
+	channels := make(map[string]models.TeamSettings)
+	for _, a := range added {
+		channel, ok := channels[a.TeamName]
+		if !ok {
+			settings, err := s.repo.GetTeamSettings(ctx, a.TeamName)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if settings != nil {
+				channel = *settings
+			}
+			channels[a.TeamName] = channel
+		}
+
+		err := s.notify(ctx, models.Notification{
+			Event:      models.EventAnomalyDetected,
+			TeamName:   a.TeamName,
+			Channel:    channel.NotificationChannel,
+			Provider:   channel.NotificationProvider,
+			Template:   channel.NotificationTemplate,
+			Locale:     s.teamLocale(channel),
+			Recipients: []string{},
+			Message:    a.Message,
+			CreatedAt:  now,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notify about %s of %s: %w", a.Kind, a.TeamName, err))
+		}
+	}
+	return len(added), errors.Join(errs...)
+}
+
+// assignmentConcentration flags the members who got more than their share
+// of their team's assignments.
+func assignmentConcentration(counts []models.AssignmentCount, t AnomalyThresholds) []models.Anomaly {
+	totals := make(map[string]int)
+	for _, c := range counts {
+		totals[c.TeamName] += c.Assignments
+	}
+
+	days := int(t.Window / (24 * time.Hour))
+	anomalies := []models.Anomaly{}
+	for _, c := range counts {
+		total := totals[c.TeamName]
+		if total < t.MinAssignments {
+			continue
+		}
+		share := float64(c.Assignments) / float64(total)
+		if share <= t.MaxAssignmentShare {
+			continue
+		}
+		anomalies = append(anomalies, models.Anomaly{
+			Kind:      models.AnomalyAssignmentConcentration,
+			TeamName:  c.TeamName,
+			UserID:    c.UserID,
+			Value:     round2(share),
+			Threshold: t.MaxAssignmentShare,
+			Message: fmt.Sprintf("%s got %d of %d review assignments of team %s in the last %d days (%.0f%%)",
+				c.UserID, c.Assignments, total, c.TeamName, days, share*100),
+		})
+	}
+	return anomalies
+}
+
+// mergeDrops flags the teams that merged far fewer PRs in the last week of
+// their activity than in the weeks before on average.
+func mergeDrops(activity []models.TeamActivity, t AnomalyThresholds) []models.Anomaly {
+	anomalies := []models.Anomaly{}
+	for _, a := range activity {
+		if len(a.WeeklyMerges) < 2 {
+			continue
+		}
+		last := a.WeeklyMerges[len(a.WeeklyMerges)-1]
+		baseline := 0.0
+		for _, n := range a.WeeklyMerges[:len(a.WeeklyMerges)-1] {
+			baseline += float64(n)
+		}
+		baseline /= float64(len(a.WeeklyMerges) - 1)
+		if baseline < float64(t.MinWeeklyMerges) {
+			continue
+		}
+		drop := 1 - float64(last)/baseline
+		if drop <= t.MaxMergeDrop {
+			continue
+		}
+		anomalies = append(anomalies, models.Anomaly{
+			Kind:      models.AnomalyMergeDrop,
+			TeamName:  a.TeamName,
+			Value:     round2(drop),
+			Threshold: t.MaxMergeDrop,
+			Message: fmt.Sprintf("Team %s merged %d PRs in the last week, %.0f%% below its weekly average of %s",
+				a.TeamName, last, drop*100, formatRate(baseline)),
+		})
+	}
+	return anomalies
+}
+
+func formatRate(x float64) string {
+	if x == math.Trunc(x) {
+		return fmt.Sprintf("%.0f", x)
+	}
+	return fmt.Sprintf("%.1f", x)
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+// ArchiveMergedPullRequests archives PRs merged more than olderThan ago and
+// returns how many were archived. Archived PRs no longer count towards review
+// load and are hidden from user review lists.
+func (s *Service) ArchiveMergedPullRequests(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return s.repo.ArchivePullRequests(ctx, s.clock.Now().Add(-olderThan))
+}
+
+func (s *Service) ListPullRequests(ctx context.Context, filter models.PullRequestFilter) ([]models.PullRequestShort, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "status must be OPEN, MERGED or CLOSED",
+		}
+	}
+	if filter.Priority != "" && !filter.Priority.Valid() {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "priority must be LOW, NORMAL or URGENT",
+		}
+	}
+	filter.Repository = normalizeRepository(filter.Repository)
+	filter.Limit, filter.Offset = pageBounds(filter.Limit, filter.Offset)
+	return s.repo.ListPullRequests(ctx, filter)
+}
+
+// SearchPullRequests is ListPullRequests for a search query over PR names,
+// which it requires.
+func (s *Service) SearchPullRequests(ctx context.Context, filter models.PullRequestFilter) ([]models.PullRequestShort, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Query == "" {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "q is required",
+		}
+	}
+	return s.ListPullRequests(ctx, filter)
+}
+
+// pageBounds applies the default and maximum page size to a requested page.
+func pageBounds(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
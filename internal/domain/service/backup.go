@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// Backup snapshots teams, users and PRs with everything attached to them.
+func (s *Service) Backup(ctx context.Context) (*models.Backup, error) {
+	return s.repo.Backup(ctx)
+}
+
+// Restore loads a backup into an empty database. With dryRun the backup is
+// loaded and checked against the schema, then rolled back.
+func (s *Service) Restore(ctx context.Context, backup *models.Backup, dryRun bool) (*models.RestoreResult, error) {
+	if backup.Format != models.BackupFormat {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "unsupported backup format, want " + strconv.Itoa(models.BackupFormat),
+		}
+	}
+
+	rows, err := s.repo.Restore(ctx, backup, dryRun)
+	switch {
+	case errors.Is(err, repository.ErrNotEmpty):
+		return nil, &ServiceError{
+			Code:    models.ErrNotEmpty,
+			Message: "restore needs an empty database",
+		}
+	case errors.Is(err, repository.ErrInvalidBackup):
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: err.Error(),
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	if !dryRun {
+		if err := s.repo.RefreshStatistics(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return &models.RestoreResult{DryRun: dryRun, Rows: rows}, nil
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// HoldLabel keeps a PR from being merged while it is set. The /hold and
+// /unhold bot commands add and remove it.
+const HoldLabel = "hold"
+
+// botCommands lists the commands RunBotCommand understands, for its error
+// messages.
+const botCommands = "/approve [comment], /reassign [@user], /hold, /unhold"
+
+// BotCommandParams is a command as a chat or PR comment bot forwards it.
+// The actor and any @mentions are user IDs, or external IDs of Provider
+// when it is set.
+type BotCommandParams struct {
+	PullRequestID string
+	Provider      models.IdentityProvider
+	Actor         string
+	Text          string
+}
+
+// botCommand is a parsed command: its name and the words after it.
+type botCommand struct {
+	name string
+	args []string
+}
+
+// RunBotCommand parses the first line of params.Text that starts with a
+// slash and runs it on the PR for the actor:
+//
+//   - /approve [comment] approves the PR as the actor, who must review it;
+//   - /reassign [@user] replaces reviewer @user, or the actor without a
+//     mention. Only the PR author and the reviewer themselves may;
+//   - /hold and /unhold set and clear HoldLabel. Only the PR author and its
+//     reviewers may.
+//
+// The reply mentions users by their external IDs of params.Provider, when
+// they have one.
+func (s *Service) RunBotCommand(ctx context.Context, params BotCommandParams) (*models.BotReply, error) {
+	cmd, ok := parseBotCommand(params.Text)
+	if !ok {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "no command found; supported: " + botCommands,
+		}
+	}
+
+	actor, err := s.botUser(ctx, params.Provider, params.Actor)
+	if err != nil {
+		return nil, err
+	}
+	pr, err := s.repo.GetPullRequest(ctx, params.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "PR not found",
+		}
+	}
+
+	mention := s.mentioner(ctx, params.Provider)
+	var reply string
+	switch cmd.name {
+	case "/approve":
+		pr, reply, err = s.botApprove(ctx, mention, pr, actor, strings.Join(cmd.args, " "))
+	case "/reassign":
+		pr, reply, err = s.botReassign(ctx, mention, params.Provider, pr, actor, cmd.args)
+	case "/hold", "/unhold":
+		pr, reply, err = s.botHold(ctx, mention, pr, actor, cmd.name == "/hold")
+	default:
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "unknown command " + cmd.name + "; supported: " + botCommands,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &models.BotReply{Command: cmd.name, Reply: reply, PR: *pr}, nil
+}
+
+func (s *Service) botApprove(ctx context.Context, mention func(string) string, pr *models.PullRequest, actor, comment string) (*models.PullRequest, string, error) {
+	_, err := s.SubmitReview(ctx, &models.Review{
+		PullRequestID: pr.PullRequestID,
+		ReviewerID:    actor,
+		State:         models.ReviewApproved,
+		Comment:       comment,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	pr, err = s.repo.GetPullRequest(ctx, pr.PullRequestID)
+	if err != nil {
+		return nil, "", err
+	}
+	required, err := s.requiredApprovalsFor(ctx, pr)
+	if err != nil {
+		return nil, "", err
+	}
+	if required > 0 {
+		return pr, fmt.Sprintf("%s approved PR %s (%d of %d required approvals).", mention(actor), pr.PullRequestID, countApprovals(pr), required), nil
+	}
+	return pr, fmt.Sprintf("%s approved PR %s.", mention(actor), pr.PullRequestID), nil
+}
+
+func (s *Service) botReassign(ctx context.Context, mention func(string) string, provider models.IdentityProvider, pr *models.PullRequest, actor string, args []string) (*models.PullRequest, string, error) {
+	reviewer := actor
+	if len(args) > 0 {
+		var err error
+		reviewer, err = s.botUser(ctx, provider, args[0])
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	if actor != pr.AuthorID && actor != reviewer {
+		return nil, "", &ServiceError{
+			Code:    models.ErrForbidden,
+			Message: "only the PR author or the reviewer can reassign them",
+		}
+	}
+
+	pr, replacedBy, err := s.ReassignReviewer(ctx, pr.PullRequestID, reviewer)
+	if err != nil {
+		return nil, "", err
+	}
+	return pr, fmt.Sprintf("%s was replaced by %s on PR %s.", mention(reviewer), mention(replacedBy), pr.PullRequestID), nil
+}
+
+func (s *Service) botHold(ctx context.Context, mention func(string) string, pr *models.PullRequest, actor string, hold bool) (*models.PullRequest, string, error) {
+	if actor != pr.AuthorID && !contains(pr.AssignedReviewers, actor) && !contains(pr.ShadowReviewers, actor) {
+		return nil, "", &ServiceError{
+			Code:    models.ErrForbidden,
+			Message: "only the PR author and reviewers can hold it",
+		}
+	}
+
+	if contains(pr.Labels, HoldLabel) == hold {
+		if hold {
+			return pr, fmt.Sprintf("PR %s is already on hold.", pr.PullRequestID), nil
+		}
+		return pr, fmt.Sprintf("PR %s is not on hold.", pr.PullRequestID), nil
+	}
+
+	labels := without(pr.Labels, HoldLabel)
+	if hold {
+		labels = append(labels, HoldLabel)
+	}
+	pr, err := s.SetPullRequestLabels(ctx, pr.PullRequestID, labels)
+	if err != nil {
+		return nil, "", err
+	}
+	if hold {
+		return pr, fmt.Sprintf("%s put PR %s on hold; it cannot be merged until /unhold.", mention(actor), pr.PullRequestID), nil
+	}
+	return pr, fmt.Sprintf("%s took PR %s off hold.", mention(actor), pr.PullRequestID), nil
+}
+
+// botUser returns the user_id of a command's actor or mention: name itself,
+// without a leading @, or the user linked to it with provider.
+func (s *Service) botUser(ctx context.Context, provider models.IdentityProvider, name string) (string, error) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "@")
+	if name == "" {
+		return "", &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "actor is required",
+		}
+	}
+	if provider != "" {
+		user, err := s.ResolveIdentity(ctx, provider, name)
+		if err != nil {
+			return "", err
+		}
+		return user.UserID, nil
+	}
+	if err := s.requireUser(ctx, name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// parseBotCommand returns the command on the first line of text starting
+// with a slash. Command names are case-insensitive.
+func parseBotCommand(text string) (botCommand, bool) {
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+			continue
+		}
+		return botCommand{name: strings.ToLower(fields[0]), args: fields[1:]}, true
+	}
+	return botCommand{}, false
+}
+
+// mentioner returns a function writing @ and a user's external ID of
+// provider, or their user_id when they have none or provider is empty.
+func (s *Service) mentioner(ctx context.Context, provider models.IdentityProvider) func(userID string) string {
+	return func(userID string) string {
+		if provider == "" {
+			return "@" + userID
+		}
+		identities, err := s.repo.GetUserIdentities(ctx, []string{userID})
+		if err != nil {
+			// The user_id still identifies them.
+			return "@" + userID
+		}
+		for _, identity := range identities {
+			if identity.Provider == provider {
+				return "@" + identity.ExternalID
+			}
+		}
+		return "@" + userID
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Clock tells the service the current time. Injecting one with WithClock
+// makes time-dependent logic such as working hours and staleness
+// reproducible.
+type Clock interface {
+	Now() time.Time
+}
+
+// Rand is the randomness reviewer selection draws on. Implementations must
+// be safe for concurrent use.
+type Rand interface {
+	Intn(n int) int
+	Shuffle(n int, swap func(i, j int))
+}
+
+// WithClock replaces the wall clock.
+func WithClock(clock Clock) Option {
+	return func(s *Service) {
+		s.clock = clock
+	}
+}
+
+// WithRand replaces the randomly seeded source used to pick reviewers.
+func WithRand(rng Rand) Option {
+	return func(s *Service) {
+		s.rng = rng
+	}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// FixedClock is a Clock that stands still until it is moved, for tests.
+type FixedClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewFixedClock(now time.Time) *FixedClock {
+	return &FixedClock{now: now}
+}
+
+func (c *FixedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to now.
+func (c *FixedClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the clock forward by d.
+func (c *FixedClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// lockedRand serializes access to a *rand.Rand, which is not safe for
+// concurrent use on its own.
+type lockedRand struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewSeededRand returns a Rand that yields the same choices for the same
+// seed and sequence of calls, for tests.
+func NewSeededRand(seed int64) Rand {
+	return &lockedRand{rng: rand.New(rand.NewSource(seed))}
+}
+
+func (r *lockedRand) Intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Intn(n)
+}
+
+func (r *lockedRand) Shuffle(n int, swap func(i, j int)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rng.Shuffle(n, swap)
+}
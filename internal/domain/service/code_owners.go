@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// maxCodeOwnersErrors caps the parse errors reported for one file.
+const maxCodeOwnersErrors = 10
+
+func (s *Service) GetCodeOwners(ctx context.Context, repoName string) (*models.CodeOwners, error) {
+	r, err := s.GetRepository(ctx, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.repo.GetCodeOwners(ctx, r.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &models.CodeOwners{Repository: r.Name, Rules: rules}, nil
+}
+
+// SetCodeOwners parses content as a CODEOWNERS file and replaces the
+// repository's mapping with it; empty content clears the mapping. Owners
+// are user IDs, optionally written as @user_id, and must exist.
+func (s *Service) SetCodeOwners(ctx context.Context, repoName, content string) (*models.CodeOwners, error) {
+	r, err := s.GetRepository(ctx, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.parseCodeOwners(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetCodeOwners(ctx, r.Name, rules); err != nil {
+		return nil, err
+	}
+	return &models.CodeOwners{Repository: r.Name, Rules: rules}, nil
+}
+
+// PreviewCodeOwners resolves paths against content, parsed as by
+// SetCodeOwners but not stored, or against the repository's stored mapping
+// when content is empty.
+func (s *Service) PreviewCodeOwners(ctx context.Context, repoName, content string, paths []string) (*models.CodeOwnersPreview, error) {
+	r, err := s.GetRepository(ctx, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []models.CodeOwnersRule
+	if strings.TrimSpace(content) != "" {
+		rules, err = s.parseCodeOwners(ctx, content)
+	} else {
+		rules, err = s.repo.GetCodeOwners(ctx, r.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &models.CodeOwnersPreview{
+		Repository: r.Name,
+		Paths:      []models.PathOwners{},
+		Owners:     []string{},
+	}
+	matchers := compileCodeOwners(rules)
+	for _, path := range normalizePaths(paths) {
+		owned := models.PathOwners{Path: path, Owners: []string{}}
+		if rule := matchers.owner(path); rule != nil {
+			owned.Pattern = rule.Pattern
+			owned.Line = rule.Line
+			owned.Owners = rule.Owners
+		}
+		preview.Paths = append(preview.Paths, owned)
+		preview.Owners = uniqueStrings(append(preview.Owners, owned.Owners...))
+	}
+	return preview, nil
+}
+
+// parseCodeOwners parses content and checks that every owner is a known
+// user. Parse errors are reported together, with their line numbers.
+func (s *Service) parseCodeOwners(ctx context.Context, content string) ([]models.CodeOwnersRule, error) {
+	rules, problems := parseCodeOwners(content)
+
+	owners := []string{}
+	for _, rule := range rules {
+		owners = append(owners, rule.Owners...)
+	}
+	owners = uniqueStrings(owners)
+	if len(owners) > 0 {
+		users, err := s.repo.GetUsersByIDs(ctx, owners)
+		if err != nil {
+			return nil, err
+		}
+		known := make(map[string]bool, len(users))
+		for _, u := range users {
+			known[u.UserID] = true
+		}
+		for _, rule := range rules {
+			for _, owner := range rule.Owners {
+				if !known[owner] {
+					problems = append(problems, fmt.Sprintf("line %d: unknown owner %s", rule.Line, owner))
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		if len(problems) > maxCodeOwnersErrors {
+			problems = append(problems[:maxCodeOwnersErrors], fmt.Sprintf("and %d more", len(problems)-maxCodeOwnersErrors))
+		}
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "invalid CODEOWNERS: " + strings.Join(problems, "; "),
+		}
+	}
+	return rules, nil
+}
+
+// parseCodeOwners splits content into rules, one per non-blank line that
+// is not a comment, and describes the lines it cannot use. A line holds a
+// path pattern followed by its owners; # starts a comment.
+func parseCodeOwners(content string) ([]models.CodeOwnersRule, []string) {
+	rules := []models.CodeOwnersRule{}
+	problems := []string{}
+	for i, line := range strings.Split(content, "\n") {
+		if comment := strings.Index(line, "#"); comment >= 0 {
+			line = line[:comment]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		rule := models.CodeOwnersRule{Line: i + 1, Pattern: fields[0], Owners: []string{}}
+		if problem := checkCodeOwnersPattern(rule.Pattern); problem != "" {
+			problems = append(problems, fmt.Sprintf("line %d: %s", rule.Line, problem))
+			continue
+		}
+		for _, owner := range fields[1:] {
+			owner = strings.TrimPrefix(owner, "@")
+			if owner == "" || strings.ContainsAny(owner, "/@") {
+				problems = append(problems, fmt.Sprintf("line %d: owner %s is not a user ID", rule.Line, owner))
+				continue
+			}
+			rule.Owners = append(rule.Owners, owner)
+		}
+		rule.Owners = uniqueStrings(rule.Owners)
+		rules = append(rules, rule)
+	}
+	return rules, problems
+}
+
+func checkCodeOwnersPattern(pattern string) string {
+	switch {
+	case strings.HasPrefix(pattern, "!"):
+		return "negated patterns are not supported"
+	case strings.ContainsAny(pattern, "[]\\"):
+		return "character classes and escapes are not supported"
+	case strings.Trim(pattern, "/") == "" && pattern != "/":
+		return "empty pattern"
+	}
+	return ""
+}
+
+type codeOwnersMatcher struct {
+	rule models.CodeOwnersRule
+	re   *regexp.Regexp
+}
+
+type codeOwnersMatchers []codeOwnersMatcher
+
+func compileCodeOwners(rules []models.CodeOwnersRule) codeOwnersMatchers {
+	matchers := make(codeOwnersMatchers, 0, len(rules))
+	for _, rule := range rules {
+		matchers = append(matchers, codeOwnersMatcher{rule: rule, re: codeOwnersRegexp(rule.Pattern)})
+	}
+	return matchers
+}
+
+// owner returns the last rule matching path, or nil.
+func (m codeOwnersMatchers) owner(path string) *models.CodeOwnersRule {
+	for i := len(m) - 1; i >= 0; i-- {
+		if m[i].re.MatchString(path) {
+			return &m[i].rule
+		}
+	}
+	return nil
+}
+
+// codeOwnersRegexp translates a CODEOWNERS pattern to a regexp over paths
+// relative to the repository root. A pattern starting with or containing a
+// slash is anchored at the root, otherwise it matches at any depth; a
+// pattern ending in a slash only matches directories. A match on a
+// directory covers everything under it, except for "dir/*", which only
+// matches the files directly in dir. * and ? do not cross slashes, **
+// does.
+func codeOwnersRegexp(pattern string) *regexp.Regexp {
+	trimmed := strings.Trim(pattern, "/")
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case trimmed[i] == '*':
+			b.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(trimmed[i : i+1]))
+		}
+	}
+	switch {
+	case trimmed == "":
+		// "/" owns the whole repository.
+		b.WriteString(".*")
+	case dirOnly:
+		b.WriteString("/.*")
+	case strings.HasSuffix(trimmed, "/*"):
+		// Files directly in the directory only.
+	default:
+		b.WriteString("(?:/.*)?")
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// codeOwnerReviewers picks a code owner of paths when the CODEOWNERS
+// mapping of the PR's repository owns any of them and no owner is among
+// chosen yet: the least loaded owner among the candidates. Owners who are
+// not candidates, e.g. members of other teams, cannot be picked; when none
+// is a candidate nothing is.
+func (s *Service) codeOwnerReviewers(ctx context.Context, repo repository.Storage, candidates []models.User, repoName string, paths []string, chosen []string) ([]Decision, error) {
+	owners, err := pathOwners(ctx, repo, repoName, paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(owners) == 0 || containsAny(chosen, owners) {
+		return []Decision{}, nil
+	}
+
+	group := []models.User{}
+	for _, c := range excludeUsers(candidates, chosen) {
+		if contains(owners, c.UserID) {
+			group = append(group, c)
+		}
+	}
+	if len(group) == 0 {
+		return []Decision{}, nil
+	}
+
+	picked, err := s.strategies[StrategyLeastLoaded].SelectReviewers(ctx, repo, AssignmentRequest{
+		Candidates: group,
+		Count:      1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	decisions := []Decision{}
+	for _, d := range picked {
+		decisions = append(decisions, Decision{UserID: d.UserID, Reason: models.ReasonCodeOwner})
+	}
+	return decisions, nil
+}
+
+// pathOwners returns the owners the CODEOWNERS mapping of repoName gives
+// any of paths.
+func pathOwners(ctx context.Context, repo repository.Storage, repoName string, paths []string) ([]string, error) {
+	if repoName == "" || len(paths) == 0 {
+		return []string{}, nil
+	}
+
+	rules, err := repo.GetCodeOwners(ctx, repositoryName(repoName))
+	if err != nil {
+		return nil, err
+	}
+	matchers := compileCodeOwners(rules)
+	owners := []string{}
+	for _, path := range paths {
+		if rule := matchers.owner(path); rule != nil {
+			owners = append(owners, rule.Owners...)
+		}
+	}
+	return uniqueStrings(owners), nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// DeleteUser soft-deletes the user: they disappear from teams, lookups and
+// assignment, while their PRs, reviews and history are kept, until
+// RestoreUser brings them back or PurgeDeleted removes them.
+func (s *Service) DeleteUser(ctx context.Context, userID string) error {
+	deleted, err := s.repo.DeleteUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "user not found",
+		}
+	}
+	return s.repo.RefreshStatistics(ctx)
+}
+
+// RestoreUser undoes DeleteUser. Restoring a user who is not deleted
+// returns them unchanged; users of a deleted team are restored with the
+// team.
+func (s *Service) RestoreUser(ctx context.Context, userID string) (*models.User, error) {
+	restored, err := s.repo.RestoreUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if restored {
+		if err := s.repo.RefreshStatistics(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "deleted user not found",
+		}
+	}
+	return user, nil
+}
+
+// DeleteTeam soft-deletes the team together with its members.
+func (s *Service) DeleteTeam(ctx context.Context, teamName string) error {
+	deleted, err := s.repo.DeleteTeam(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "team not found",
+		}
+	}
+	return s.repo.RefreshStatistics(ctx)
+}
+
+// RestoreTeam undoes DeleteTeam, bringing back the members deleted with
+// the team. Restoring a team that is not deleted returns it unchanged.
+func (s *Service) RestoreTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	restored, err := s.repo.RestoreTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if restored {
+		if err := s.repo.RefreshStatistics(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	team, err := s.repo.GetTeamFromPrimary(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "deleted team not found",
+		}
+	}
+	return team, nil
+}
+
+// PurgeDeleted removes users and teams deleted more than olderThan ago for
+// good and returns how many of each were purged. Users still referred to
+// by PRs or assignment history are erased rather than removed.
+func (s *Service) PurgeDeleted(ctx context.Context, olderThan time.Duration) (users, teams int64, err error) {
+	before := s.clock.Now().Add(-olderThan)
+	users, err = s.repo.PurgeDeletedUsers(ctx, before)
+	if err != nil {
+		return 0, 0, err
+	}
+	teams, err = s.repo.PurgeDeletedTeams(ctx, before)
+	if err != nil {
+		return users, 0, err
+	}
+	if users == 0 && teams == 0 {
+		return 0, 0, nil
+	}
+	return users, teams, s.repo.RefreshStatistics(ctx)
+}
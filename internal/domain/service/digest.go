@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+const (
+	// defaultDigestTime is when digests go out for users who opt in without
+	// choosing a time.
+	defaultDigestTime = "09:00"
+	// digestPeriod is how far back a digest lists new assignments.
+	digestPeriod = 24 * time.Hour
+	// digestItems caps the PRs a digest lists in each section.
+	digestItems = 100
+)
+
+// GetDigestPreferences returns the user's digest preferences, disabled with
+// the default time when they have not set any.
+func (s *Service) GetDigestPreferences(ctx context.Context, userID string) (*models.DigestPreferences, error) {
+	if err := s.requireUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	prefs, err := s.repo.GetDigestPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs == nil {
+		prefs = &models.DigestPreferences{UserID: userID, SendAt: defaultDigestTime}
+	}
+	return prefs, nil
+}
+
+// SetDigestPreferences replaces the user's digest preferences. An empty
+// send time means defaultDigestTime.
+func (s *Service) SetDigestPreferences(ctx context.Context, prefs *models.DigestPreferences) (*models.DigestPreferences, error) {
+	prefs.SendAt = strings.TrimSpace(prefs.SendAt)
+	prefs.Channel = strings.TrimSpace(prefs.Channel)
+	if prefs.SendAt == "" {
+		prefs.SendAt = defaultDigestTime
+	}
+
+	var msg string
+	switch {
+	case !validClock(prefs.SendAt):
+		msg = "send_at must look like 09:00"
+	case prefs.Channel != "" && !validChannel(prefs.Channel):
+		msg = "channel must be an http(s) URL"
+	case prefs.Provider != "" && !prefs.Provider.Valid():
+		msg = "provider must be webhook, slack, msteams or mattermost"
+	}
+	if msg != "" {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: msg,
+		}
+	}
+
+	if err := s.requireUser(ctx, prefs.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetDigestPreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// SendDigests sends the daily digest to every active user who opted in and
+// whose send time has passed today in their time zone without a digest
+// going out. Users with nothing to review get none. It returns the number
+// of digests sent.
+func (s *Service) SendDigests(ctx context.Context) (int, error) {
+	subscribed, err := s.repo.GetEnabledDigests(ctx)
+	if err != nil || len(subscribed) == 0 {
+		return 0, err
+	}
+	userIDs := make([]string, len(subscribed))
+	for i, p := range subscribed {
+		userIDs[i] = p.UserID
+	}
+	users, err := s.repo.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return 0, err
+	}
+	byID := make(map[string]models.User, len(users))
+	for _, u := range users {
+		byID[u.UserID] = u
+	}
+	stale, err := s.repo.GetStalePullRequests(ctx)
+	if err != nil {
+		return 0, err
+	}
+	overdue := make(map[string]bool, len(stale))
+	for _, pr := range stale {
+		overdue[pr.PullRequestID] = true
+	}
+
+	now := s.clock.Now()
+	sent := 0
+	var errs []error
+	channels := make(map[string]models.TeamSettings)
+	for _, prefs := range subscribed {
+		user, ok := byID[prefs.UserID]
+		if !ok || !user.IsActive || !digestDue(prefs, user, now) {
+			continue
+		}
+
+		digest, err := s.reviewDigest(ctx, user.UserID, now, overdue)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("digest of %s: %w", user.UserID, err))
+			continue
+		}
+		if len(digest.OpenReviews) > 0 || len(digest.NewlyAssigned) > 0 {
+			channel, ok := channels[user.TeamName]
+			if !ok {
+				settings, err := s.repo.GetTeamSettings(ctx, user.TeamName)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				if settings != nil {
+					channel = *settings
+				}
+				channels[user.TeamName] = channel
+			}
+			if prefs.Channel != "" {
+				channel.NotificationChannel = prefs.Channel
+			}
+			if prefs.Provider != "" {
+				channel.NotificationProvider = prefs.Provider
+			}
+
+			err = s.notify(ctx, models.Notification{
+				Event:      models.EventReviewDigest,
+				TeamName:   user.TeamName,
+				Channel:    channel.NotificationChannel,
+				Provider:   channel.NotificationProvider,
+				Template:   channel.NotificationTemplate,
+				Locale:     s.teamLocale(channel),
+				Recipients: []string{user.UserID},
+				Message:    digestMessage(digest),
+				CreatedAt:  now,
+				Identities: s.recipientIdentities(ctx, []string{user.UserID}),
+				Digest:     digest,
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("send digest to %s: %w", user.UserID, err))
+				continue
+			}
+			sent++
+		}
+		if err := s.repo.MarkDigestSent(ctx, user.UserID, now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+// digestDue reports whether today's digest of the user is due at now: their
+// send time has passed in their time zone and no digest went out since.
+func digestDue(prefs models.DigestPreferences, user models.User, now time.Time) bool {
+	loc := time.UTC
+	if user.Timezone != "" {
+		if l, err := time.LoadLocation(user.Timezone); err == nil {
+			loc = l
+		}
+	}
+	sendAt, err := parseClock(prefs.SendAt)
+	if err != nil {
+		return false
+	}
+
+	local := now.In(loc)
+	due := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc).Add(sendAt)
+	if local.Before(due) {
+		return false
+	}
+	return prefs.LastSentAt == nil || prefs.LastSentAt.Before(due)
+}
+
+// reviewDigest collects the user's open reviews, those of PRs in overdue,
+// and the assignments of the last digestPeriod they still hold.
+func (s *Service) reviewDigest(ctx context.Context, userID string, now time.Time, overdue map[string]bool) (*models.ReviewDigest, error) {
+	digest := &models.ReviewDigest{
+		UserID:        userID,
+		OpenReviews:   []models.PullRequestShort{},
+		Overdue:       []models.PullRequestShort{},
+		NewlyAssigned: []models.ReviewHistoryEntry{},
+		Since:         now.Add(-digestPeriod),
+	}
+
+	prs, err := s.repo.GetPullRequestsByReviewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pr := range prs {
+		if pr.Status != models.StatusOpen {
+			continue
+		}
+		digest.OpenReviews = append(digest.OpenReviews, pr)
+		if overdue[pr.PullRequestID] {
+			digest.Overdue = append(digest.Overdue, pr)
+		}
+	}
+
+	entries, err := s.repo.GetUserHistory(ctx, models.ReviewHistoryFilter{
+		UserID: userID,
+		Since:  digest.Since,
+		Until:  now,
+		Limit:  digestItems,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.Status == models.StatusOpen && e.ReplacedBy == "" && e.RemovedAt == nil {
+			e.Outcome = models.OutcomeOpen
+			digest.NewlyAssigned = append(digest.NewlyAssigned, e)
+		}
+	}
+
+	if len(digest.OpenReviews) > digestItems {
+		digest.OpenReviews = digest.OpenReviews[:digestItems]
+	}
+	return digest, nil
+}
+
+// digestMessage writes a digest as chat text: the counts, then the PRs.
+func digestMessage(d *models.ReviewDigest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Review digest: %d open, %d overdue, %d new since yesterday", len(d.OpenReviews), len(d.Overdue), len(d.NewlyAssigned))
+
+	isOverdue := make(map[string]bool, len(d.Overdue))
+	for _, pr := range d.Overdue {
+		isOverdue[pr.PullRequestID] = true
+	}
+	for _, pr := range d.OpenReviews {
+		fmt.Fprintf(&b, "\n- %s %s", pr.PullRequestID, pr.PullRequestName)
+		if isOverdue[pr.PullRequestID] {
+			b.WriteString(" (overdue)")
+		}
+	}
+	return b.String()
+}
+
+func validClock(value string) bool {
+	_, err := parseClock(value)
+	return err == nil
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// WithDirectory enables SyncDirectory: users and team membership are pulled
+// from source, and policy decides about local users the sync does not
+// manage yet.
+func WithDirectory(source repository.DirectorySource, policy models.DirectoryConflictPolicy) Option {
+	return func(s *Service) {
+		s.directory = source
+		s.conflictPolicy = policy
+	}
+}
+
+// SyncDirectory reconciles the local users with the directory. Active
+// directory users missing locally are created, with their team when it does
+// not exist yet; managed users get the directory's team, name and activity;
+// and managed users the directory no longer lists, or lists without a team,
+// are deactivated. Users are never deleted, so their PRs and history stay
+// intact. Local users the sync does not manage yet are taken over or left
+// alone by the conflict policy. With dryRun the changes are only planned.
+//
+// Changes are applied one by one, like the user endpoints do; a sync that
+// fails midway leaves the applied ones in place, and the next run picks up
+// the rest.
+func (s *Service) SyncDirectory(ctx context.Context, dryRun bool) (*models.DirectorySyncReport, error) {
+	if s.directory == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "directory sync is not configured",
+		}
+	}
+
+	unlock, err := s.locker.Lock(ctx, "directory:sync")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	entries, err := s.directory.Users(ctx)
+	if err != nil {
+		return nil, &ServiceError{
+			Code:    models.ErrServiceUnavailable,
+			Message: "directory source: " + err.Error(),
+		}
+	}
+	// An empty answer far more likely means a broken query than an empty
+	// company; applying it would deactivate every managed user.
+	if len(entries) == 0 {
+		return nil, &ServiceError{
+			Code:    models.ErrServiceUnavailable,
+			Message: "directory source returned no users",
+		}
+	}
+
+	plan, err := s.planDirectorySync(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.DirectorySyncReport{
+		DryRun:         dryRun,
+		ConflictPolicy: s.conflictPolicy,
+		DirectoryUsers: len(entries),
+		Changes:        plan,
+		SyncedAt:       s.clock.Now(),
+	}
+	for _, change := range plan {
+		if change.Conflict {
+			report.Conflicts++
+		}
+		if change.Skipped {
+			report.Skipped++
+		}
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	for _, change := range plan {
+		if change.Skipped {
+			continue
+		}
+		if err := s.applyDirectoryChange(ctx, change); err != nil {
+			subject := change.UserID
+			if subject == "" {
+				subject = change.TeamName
+			}
+			return report, fmt.Errorf("directory sync: %s %s: %w", change.Action, subject, err)
+		}
+		report.Applied++
+	}
+	return report, nil
+}
+
+// planDirectorySync compares the directory entries with the local users and
+// returns the changes reconciling them: teams first, then users in ID order.
+func (s *Service) planDirectorySync(ctx context.Context, entries []models.DirectoryUser) ([]models.DirectoryChange, error) {
+	listed := make(map[string]models.DirectoryUser, len(entries))
+	ids := []string{}
+	for _, e := range entries {
+		if _, ok := listed[e.UserID]; e.UserID == "" || ok {
+			continue
+		}
+		listed[e.UserID] = e
+		ids = append(ids, e.UserID)
+	}
+
+	managed, err := s.repo.GetDirectoryManagedUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	known, err := s.repo.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	local := make(map[string]models.User, len(known)+len(managed))
+	for _, u := range append(known, managed...) {
+		local[u.UserID] = u
+	}
+	deletedIDs, err := s.repo.GetDeletedUserIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	deleted := make(map[string]bool, len(deletedIDs))
+	for _, id := range deletedIDs {
+		deleted[id] = true
+	}
+	for _, u := range managed {
+		if _, ok := listed[u.UserID]; !ok {
+			ids = append(ids, u.UserID)
+		}
+	}
+	sort.Strings(ids)
+
+	// needTeam plans creating the team unless it exists, and reports
+	// whether it is deleted instead, in which case the steps needing it are
+	// skipped.
+	teams := []models.DirectoryChange{}
+	newTeams := make(map[string]bool)
+	deletedTeams := make(map[string]bool)
+	needTeam := func(teamName string) (bool, error) {
+		if newTeams[teamName] || deletedTeams[teamName] {
+			return deletedTeams[teamName], nil
+		}
+		exists, err := s.repo.TeamExists(ctx, teamName)
+		if err != nil || exists {
+			return false, err
+		}
+		teamDeleted, err := s.repo.TeamDeleted(ctx, teamName)
+		if err != nil {
+			return false, err
+		}
+		change := models.DirectoryChange{Action: models.DirectoryCreateTeam, TeamName: teamName}
+		if teamDeleted {
+			deletedTeams[teamName] = true
+			skipDeleted(&change)
+		} else {
+			newTeams[teamName] = true
+		}
+		teams = append(teams, change)
+		return teamDeleted, nil
+	}
+
+	changes := []models.DirectoryChange{}
+	for _, id := range ids {
+		entry, isListed := listed[id]
+		user, isLocal := local[id]
+		if isListed && entry.TeamName == "" {
+			// Outside every synced team: treated as no longer listed.
+			isListed = false
+		}
+
+		switch {
+		case !isLocal:
+			if !isListed || !entry.Active {
+				continue
+			}
+			change := models.DirectoryChange{
+				Action:   models.DirectoryCreateUser,
+				TeamName: entry.TeamName,
+				UserID:   id,
+				Username: entry.Username,
+			}
+			teamDeleted := false
+			if !deleted[id] {
+				if teamDeleted, err = needTeam(entry.TeamName); err != nil {
+					return nil, err
+				}
+			}
+			if deleted[id] || teamDeleted {
+				skipDeleted(&change)
+			}
+			changes = append(changes, change)
+
+		case !isListed:
+			if user.DirectoryManaged && user.IsActive {
+				changes = append(changes, models.DirectoryChange{
+					Action:   models.DirectoryDeactivateUser,
+					TeamName: user.TeamName,
+					UserID:   id,
+					Username: user.Username,
+				})
+			}
+
+		default:
+			userChanges := directoryUserChanges(user, entry)
+			if len(userChanges) == 0 {
+				if !user.DirectoryManaged && s.conflictPolicy == models.ConflictDirectoryWins {
+					changes = append(changes, models.DirectoryChange{
+						Action:   models.DirectoryAdoptUser,
+						TeamName: user.TeamName,
+						UserID:   id,
+						Username: user.Username,
+						Conflict: true,
+					})
+				}
+				continue
+			}
+			for i := range userChanges {
+				if !user.DirectoryManaged {
+					userChanges[i].Conflict = true
+					userChanges[i].Skipped = s.conflictPolicy == models.ConflictKeepLocal
+				}
+				if userChanges[i].Action == models.DirectoryMoveUser && !userChanges[i].Skipped {
+					teamDeleted, err := needTeam(userChanges[i].TeamName)
+					if err != nil {
+						return nil, err
+					}
+					if teamDeleted {
+						skipDeleted(&userChanges[i])
+					}
+				}
+			}
+			changes = append(changes, userChanges...)
+		}
+	}
+	return append(teams, changes...), nil
+}
+
+// skipDeleted marks a step on a deleted user, or into a deleted team, as a
+// conflict the sync leaves alone.
+func skipDeleted(change *models.DirectoryChange) {
+	change.Conflict = true
+	change.Skipped = true
+	change.Deleted = true
+}
+
+// directoryUserChanges lists what differs between a local user and their
+// directory entry. Only deactivation is carried over from entries of
+// inactive users.
+func directoryUserChanges(user models.User, entry models.DirectoryUser) []models.DirectoryChange {
+	changes := []models.DirectoryChange{}
+	if !entry.Active {
+		if user.IsActive {
+			changes = append(changes, models.DirectoryChange{
+				Action:   models.DirectoryDeactivateUser,
+				TeamName: user.TeamName,
+				UserID:   user.UserID,
+				Username: user.Username,
+			})
+		}
+		return changes
+	}
+
+	if entry.TeamName != user.TeamName {
+		changes = append(changes, models.DirectoryChange{
+			Action:       models.DirectoryMoveUser,
+			TeamName:     entry.TeamName,
+			UserID:       user.UserID,
+			Username:     user.Username,
+			PreviousTeam: user.TeamName,
+		})
+	}
+	if entry.Username != "" && entry.Username != user.Username {
+		changes = append(changes, models.DirectoryChange{
+			Action:           models.DirectoryRenameUser,
+			TeamName:         entry.TeamName,
+			UserID:           user.UserID,
+			Username:         entry.Username,
+			PreviousUsername: user.Username,
+		})
+	}
+	if !user.IsActive {
+		changes = append(changes, models.DirectoryChange{
+			Action:   models.DirectoryReactivateUser,
+			TeamName: entry.TeamName,
+			UserID:   user.UserID,
+			Username: entry.Username,
+		})
+	}
+	return changes
+}
+
+// applyDirectoryChange applies one planned change; every change but
+// creating a team leaves the user managed by the sync.
+func (s *Service) applyDirectoryChange(ctx context.Context, change models.DirectoryChange) error {
+	switch change.Action {
+	case models.DirectoryCreateTeam:
+		return s.repo.CreateTeam(ctx, &models.Team{TeamName: change.TeamName, Members: []models.TeamMember{}})
+	case models.DirectoryCreateUser:
+		username := change.Username
+		if username == "" {
+			username = change.UserID
+		}
+		return s.repo.CreateUser(ctx, &models.User{
+			UserID:           change.UserID,
+			Username:         username,
+			TeamName:         change.TeamName,
+			IsActive:         true,
+			ReviewWeight:     defaultReviewWeight,
+			DirectoryManaged: true,
+		})
+	}
+
+	user, err := s.repo.GetUser(ctx, change.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("user %s disappeared", change.UserID)
+	}
+	switch change.Action {
+	case models.DirectoryMoveUser:
+		user.TeamName = change.TeamName
+	case models.DirectoryRenameUser:
+		user.Username = change.Username
+	case models.DirectoryReactivateUser:
+		user.IsActive = true
+	case models.DirectoryDeactivateUser:
+		user.IsActive = false
+	}
+	user.DirectoryManaged = true
+	return s.repo.UpdateUser(ctx, user)
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// MarkPullRequestReady takes a PR out of draft and assigns its reviewers the
+// way CreatePullRequest does for PRs that are ready from the start. Preferred
+// reviewers that are no longer eligible are dropped. Marking a PR that is
+// already ready is a no-op.
+func (s *Service) MarkPullRequestReady(ctx context.Context, prID string) (*models.PullRequest, error) {
+	unlock, err := s.locker.Lock(ctx, "pr:"+prID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	pr, err := s.repo.GetPullRequest(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "PR not found",
+		}
+	}
+	if pr.Status != models.StatusOpen {
+		return nil, notOpen(pr, "cannot mark %s PR as ready")
+	}
+	if !pr.IsDraft {
+		return pr, nil
+	}
+
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "author not found",
+		}
+	}
+
+	var assigned []models.AssignmentEvent
+	err = s.repo.WithTeamLock(ctx, author.TeamName, func(repo repository.Storage) error {
+		teamMembers, err := repo.GetUsersByTeam(ctx, author.TeamName)
+		if err != nil {
+			return err
+		}
+		settings, err := pullRequestSettings(ctx, repo, author.TeamName, pr.Repository)
+		if err != nil {
+			return err
+		}
+
+		requested := []string{}
+		for _, userID := range pr.RequestedReviewers {
+			err := validatePreferred(ctx, repo, settings, teamMembers, pr.AuthorID, []string{userID})
+			var svcErr *ServiceError
+			if errors.As(err, &svcErr) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			requested = append(requested, userID)
+		}
+		pr.RequestedReviewers = requested
+
+		if err := s.assignReviewers(ctx, repo, settings, teamMembers, pr); err != nil {
+			return err
+		}
+		pr.IsDraft = false
+
+		if err := saveReviewers(ctx, repo, pr, "cannot mark %s PR as ready"); err != nil {
+			return err
+		}
+		now := s.clock.Now()
+		assigned = initialAssignments(pr, &now)
+		return s.recordAssignments(ctx, repo, assigned)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, models.EventPRReady, pr, "", "", assigned...)
+	return pr, nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// escalationOrder ranks the steps of an escalation policy.
+var escalationOrder = map[models.EscalationAction]int{
+	models.EscalationReminded:     1,
+	models.EscalationLeadNotified: 2,
+	models.EscalationReassigned:   3,
+}
+
+// GetEscalations lists the escalation steps taken on the team's PRs, newest
+// first.
+func (s *Service) GetEscalations(ctx context.Context, teamName string, limit, offset int) ([]models.Escalation, error) {
+	if err := s.requireTeam(ctx, teamName); err != nil {
+		return nil, err
+	}
+	limit, offset = pageBounds(limit, offset)
+	return s.repo.ListEscalations(ctx, teamName, limit, offset)
+}
+
+// EscalateIdleReviews applies the escalation policy of the author's team to
+// every reviewer who has not reviewed a PR since being assigned to it. Only
+// the furthest step due is taken, once per assignment, so a reviewer idle
+// past the reassignment threshold is replaced without being reminded first.
+// Every step taken is recorded; it returns how many were.
+func (s *Service) EscalateIdleReviews(ctx context.Context) (int, error) {
+	idle, err := s.repo.GetIdleReviews(ctx)
+	if err != nil || len(idle) == 0 {
+		return 0, err
+	}
+
+	now := s.clock.Now()
+	taken := 0
+	var errs []error
+	policies := make(map[string]models.TeamSettings)
+	for _, r := range idle {
+		policy, ok := policies[r.TeamName]
+		if !ok {
+			settings, err := s.repo.GetTeamSettings(ctx, r.TeamName)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if settings != nil {
+				policy = *settings
+			}
+			policies[r.TeamName] = policy
+		}
+
+		action := escalationDue(policy, now.Sub(r.AssignedAt))
+		if action == "" || escalatedTo(r.Taken, action) {
+			continue
+		}
+		e := models.Escalation{
+			PullRequestID: r.PullRequestID,
+			UserID:        r.UserID,
+			TeamName:      r.TeamName,
+			Action:        action,
+			AssignedAt:    r.AssignedAt,
+		}
+		if e.TargetID, err = s.escalate(ctx, policy, r, action, now); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s on %s: %w", action, r.UserID, r.PullRequestID, err))
+			continue
+		}
+		if err := s.repo.AddEscalation(ctx, &e); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		taken++
+	}
+	return taken, errors.Join(errs...)
+}
+
+// escalate takes the step on an idle reviewer and returns the user it
+// involved besides them: the lead notified or the new reviewer.
+func (s *Service) escalate(ctx context.Context, policy models.TeamSettings, r models.IdleReview, action models.EscalationAction, now time.Time) (string, error) {
+	hours := int(now.Sub(r.AssignedAt) / time.Hour)
+	switch action {
+	case models.EscalationReminded:
+		return "", s.notifyEscalation(ctx, policy, r, models.EventReviewReminder, r.UserID,
+			fmt.Sprintf("PR %q has been waiting for your review for %d hours", r.PullRequestName, hours), now)
+
+	case models.EscalationLeadNotified:
+		reviewer, err := s.repo.GetUser(ctx, r.UserID)
+		if err != nil || reviewer == nil || reviewer.ManagerID == "" {
+			return "", err
+		}
+		return reviewer.ManagerID, s.notifyEscalation(ctx, policy, r, models.EventReviewEscalated, reviewer.ManagerID,
+			fmt.Sprintf("%s has not reviewed PR %q for %d hours", r.UserID, r.PullRequestName, hours), now)
+
+	case models.EscalationReassigned:
+		_, newReviewerID, err := s.reassignReviewer(ctx, r.PullRequestID, r.UserID, nil)
+		return newReviewerID, err
+	}
+	return "", fmt.Errorf("unknown escalation action %q", action)
+}
+
+func (s *Service) notifyEscalation(ctx context.Context, policy models.TeamSettings, r models.IdleReview, event models.NotificationEvent, recipient, message string, now time.Time) error {
+	return s.notify(ctx, models.Notification{
+		Event:         event,
+		TeamName:      r.TeamName,
+		PullRequestID: r.PullRequestID,
+		Channel:       policy.NotificationChannel,
+		Provider:      policy.NotificationProvider,
+		Template:      policy.NotificationTemplate,
+		Locale:        s.teamLocale(policy),
+		Recipients:    []string{recipient},
+		Message:       message,
+		CreatedAt:     now,
+		Identities:    s.recipientIdentities(ctx, []string{recipient}),
+	})
+}
+
+// escalationDue returns the furthest step of the policy due for a reviewer
+// idle for so long, or "" when none is.
+func escalationDue(policy models.TeamSettings, idle time.Duration) models.EscalationAction {
+	due := func(hours int) bool {
+		return hours > 0 && idle >= time.Duration(hours)*time.Hour
+	}
+	switch {
+	case due(policy.ReassignAfterHours):
+		return models.EscalationReassigned
+	case due(policy.EscalateAfterHours):
+		return models.EscalationLeadNotified
+	case due(policy.RemindAfterHours):
+		return models.EscalationReminded
+	}
+	return ""
+}
+
+// escalatedTo reports whether action, or a step beyond it, was taken.
+func escalatedTo(taken []models.EscalationAction, action models.EscalationAction) bool {
+	for _, t := range taken {
+		if escalationOrder[t] >= escalationOrder[action] {
+			return true
+		}
+	}
+	return false
+}
+
+// escalationOrdered reports whether the steps the policy sets come in their
+// natural order.
+func escalationOrdered(settings *models.TeamSettings) bool {
+	last := 0
+	for _, hours := range []int{settings.RemindAfterHours, settings.EscalateAfterHours, settings.ReassignAfterHours} {
+		if hours == 0 {
+			continue
+		}
+		if hours <= last {
+			return false
+		}
+		last = hours
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// WithEventPublisher sets where PR lifecycle events are published. Without
+// it events are dropped.
+func WithEventPublisher(events repository.EventPublisher) Option {
+	return func(s *Service) {
+		s.events = events
+	}
+}
+
+type noopPublisher struct{}
+
+func (noopPublisher) Publish(context.Context, models.Event) {}
+
+// publish sends an event carrying a snapshot of pr, so later changes to pr
+// do not leak into events already sent, and the assignment history entries
+// the change recorded.
+func (s *Service) publish(ctx context.Context, eventType models.EventType, pr *models.PullRequest, replacedUserID, newUserID string, assignments ...models.AssignmentEvent) {
+	s.events.Publish(ctx, s.event(eventType, pr, replacedUserID, newUserID, assignments))
+}
+
+func (s *Service) event(eventType models.EventType, pr *models.PullRequest, replacedUserID, newUserID string, assignments []models.AssignmentEvent) models.Event {
+	snapshot := *pr
+	snapshot.AssignedReviewers = append([]string(nil), pr.AssignedReviewers...)
+	snapshot.ShadowReviewers = append([]string(nil), pr.ShadowReviewers...)
+
+	return models.Event{
+		Type:           eventType,
+		PullRequestID:  pr.PullRequestID,
+		PullRequest:    &snapshot,
+		ReplacedUserID: replacedUserID,
+		NewUserID:      newUserID,
+		Assignments:    assignments,
+		CreatedAt:      s.clock.Now(),
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+func (s *Service) CreateExclusionRule(ctx context.Context, rule *models.ExclusionRule) (*models.ExclusionRule, error) {
+	if rule.TeamName == "" || rule.ReviewerID == "" || rule.AuthorID == "" {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "team_name, reviewer_id and author_id are required",
+		}
+	}
+	if rule.ReviewerID == rule.AuthorID {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "reviewer_id and author_id must differ",
+		}
+	}
+
+	if err := s.requireTeamMembers(ctx, rule.TeamName, []string{rule.ReviewerID, rule.AuthorID}); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateExclusionRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *Service) GetExclusionRules(ctx context.Context, teamName string) ([]models.ExclusionRule, error) {
+	if err := s.requireTeam(ctx, teamName); err != nil {
+		return nil, err
+	}
+	return s.repo.GetExclusionRules(ctx, teamName)
+}
+
+func (s *Service) DeleteExclusionRule(ctx context.Context, id int64) error {
+	deleted, err := s.repo.DeleteExclusionRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "exclusion rule not found",
+		}
+	}
+	return nil
+}
+
+// excludedReviewers returns the team members that the team's exclusion
+// rules forbid from reviewing PRs of authorID.
+func excludedReviewers(ctx context.Context, repo repository.Storage, teamName, authorID string) (map[string]bool, error) {
+	rules, err := repo.GetExclusionRules(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.AuthorID == authorID {
+			excluded[rule.ReviewerID] = true
+		}
+	}
+	return excluded, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"hash/fnv"
+	"regexp"
+	"sort"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// Feature flags gating behaviors that are being rolled out. Both are on for
+// every team unless a stored flag or WithFeatureFlags says otherwise.
+const (
+	// FlagApprovalGating makes MergePullRequest enforce the required
+	// approvals for the PR author's team.
+	FlagApprovalGating = "approval_gating"
+	// FlagExpertiseStrategy picks reviewers by path expertise for PRs with
+	// changed paths when the team has not chosen a strategy.
+	FlagExpertiseStrategy = "expertise_strategy"
+)
+
+var flagNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+func defaultFeatureFlags() map[string]models.FeatureFlag {
+	flags := make(map[string]models.FeatureFlag)
+	for _, name := range []string{FlagApprovalGating, FlagExpertiseStrategy} {
+		flags[name] = models.FeatureFlag{Name: name, Enabled: true, Teams: []string{}, Percentage: 100}
+	}
+	return flags
+}
+
+// WithFeatureFlags sets the flags that apply while no flag of the same name
+// is stored, replacing the built-in defaults.
+func WithFeatureFlags(flags []models.FeatureFlag) Option {
+	return func(s *Service) {
+		for _, f := range flags {
+			if f.Teams == nil {
+				f.Teams = []string{}
+			}
+			s.flagDefaults[f.Name] = f
+		}
+	}
+}
+
+// ListFeatureFlags returns every flag in effect, stored ones taking
+// precedence over the defaults, ordered by name.
+func (s *Service) ListFeatureFlags(ctx context.Context) ([]models.FeatureFlag, error) {
+	flags, err := s.featureFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.FeatureFlag, 0, len(flags))
+	for _, f := range flags {
+		result = append(result, f)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+func (s *Service) SetFeatureFlag(ctx context.Context, flag *models.FeatureFlag) (*models.FeatureFlag, error) {
+	switch {
+	case !flagNamePattern.MatchString(flag.Name) || len(flag.Name) > 64:
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "name must be 1-64 lowercase letters, digits or underscores",
+		}
+	case flag.Percentage < 0 || flag.Percentage > 100:
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "percentage must be between 0 and 100",
+		}
+	}
+
+	flag.Teams = uniqueStrings(flag.Teams)
+	sort.Strings(flag.Teams)
+	if err := s.repo.UpsertFeatureFlag(ctx, flag); err != nil {
+		return nil, err
+	}
+	return flag, nil
+}
+
+// DeleteFeatureFlag removes a stored flag, so its default applies again.
+func (s *Service) DeleteFeatureFlag(ctx context.Context, name string) error {
+	deleted, err := s.repo.DeleteFeatureFlag(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "feature flag not found",
+		}
+	}
+	return nil
+}
+
+// featureEnabled reports whether the named flag is on for the team. Flags
+// that are neither stored nor defaulted are off.
+func (s *Service) featureEnabled(ctx context.Context, name, teamName string) (bool, error) {
+	flags, err := s.featureFlags(ctx)
+	if err != nil {
+		return false, err
+	}
+	flag, ok := flags[name]
+	return ok && flagEnabledFor(flag, teamName), nil
+}
+
+func (s *Service) featureFlags(ctx context.Context) (map[string]models.FeatureFlag, error) {
+	stored, err := s.repo.GetFeatureFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make(map[string]models.FeatureFlag, len(s.flagDefaults)+len(stored))
+	for name, f := range s.flagDefaults {
+		flags[name] = f
+	}
+	for _, f := range stored {
+		flags[f.Name] = f
+	}
+	return flags, nil
+}
+
+// flagEnabledFor evaluates flag for a team. Percentage rollouts hash the
+// flag name with the team name, so a team stays in or out as the percentage
+// grows and different flags reach different teams first.
+func flagEnabledFor(flag models.FeatureFlag, teamName string) bool {
+	if !flag.Enabled {
+		return false
+	}
+	if contains(flag.Teams, teamName) {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(flag.Name + "/" + teamName))
+	return int(h.Sum32()%100) < flag.Percentage
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+const (
+	// defaultForecastWeeks is how many past weeks a forecast is based on
+	// unless asked otherwise, and maxForecastWeeks the most it may be.
+	defaultForecastWeeks = 8
+	maxForecastWeeks     = 52
+	// forecastSmoothing is the weight exponential smoothing gives the most
+	// recent week.
+	forecastSmoothing = 0.5
+)
+
+// ForecastReviewLoad projects the review load of every team for the coming
+// week from its PR creation rate over the past weeks, moving_average by
+// default, and flags the teams likely to run out of reviewer candidates.
+func (s *Service) ForecastReviewLoad(ctx context.Context, method models.ForecastMethod, weeks int) (*models.Forecast, error) {
+	if method == "" {
+		method = models.ForecastMovingAverage
+	}
+	if weeks == 0 {
+		weeks = defaultForecastWeeks
+	}
+	var msg string
+	switch {
+	case method != models.ForecastMovingAverage && method != models.ForecastExponentialSmoothing:
+		msg = "method must be moving_average or exponential_smoothing"
+	case weeks < 1 || weeks > maxForecastWeeks:
+		msg = fmt.Sprintf("weeks must be between 1 and %d", maxForecastWeeks)
+	}
+	if msg != "" {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: msg,
+		}
+	}
+
+	now := s.clock.Now()
+	activity, err := s.repo.GetTeamActivity(ctx, now, weeks)
+	if err != nil {
+		return nil, err
+	}
+
+	forecast := &models.Forecast{
+		Method:       method,
+		HistoryWeeks: weeks,
+		From:         now,
+		To:           now.Add(7 * 24 * time.Hour),
+		Teams:        make([]models.TeamForecast, 0, len(activity)),
+	}
+	for _, a := range activity {
+		settings, err := effectiveSettings(ctx, s.repo, a.TeamName)
+		if err != nil {
+			return nil, err
+		}
+		forecast.Teams = append(forecast.Teams, teamForecast(a, settings, method))
+	}
+	return forecast, nil
+}
+
+// teamForecast projects a team's activity with method under its settings.
+func teamForecast(a models.TeamActivity, settings models.TeamSettings, method models.ForecastMethod) models.TeamForecast {
+	var expected float64
+	switch method {
+	case models.ForecastExponentialSmoothing:
+		for i, prs := range a.WeeklyPullRequests {
+			if i == 0 {
+				expected = float64(prs)
+				continue
+			}
+			expected = forecastSmoothing*float64(prs) + (1-forecastSmoothing)*expected
+		}
+	default:
+		for _, prs := range a.WeeklyPullRequests {
+			expected += float64(prs)
+		}
+		if len(a.WeeklyPullRequests) > 0 {
+			expected /= float64(len(a.WeeklyPullRequests))
+		}
+	}
+
+	f := models.TeamForecast{
+		TeamName:             a.TeamName,
+		WeeklyPullRequests:   a.WeeklyPullRequests,
+		ExpectedPullRequests: round2(expected),
+		ReviewerCount:        settings.ReviewerCount,
+		ExpectedReviews:      round2(expected * float64(settings.ReviewerCount)),
+		EligibleReviewers:    a.EligibleReviewers,
+		OpenReviews:          a.OpenReviews,
+		Capacity:             settings.MaxOpenReviews * a.EligibleReviewers,
+		Risks:                []string{},
+	}
+	f.ProjectedOpenReviews = round2(float64(f.OpenReviews) + f.ExpectedReviews)
+	if f.EligibleReviewers > 0 {
+		f.ExpectedReviewsPerMember = round2(f.ExpectedReviews / float64(f.EligibleReviewers))
+	}
+
+	// Replacing a reviewer of a PR by a member of the pool needs somebody
+	// who is neither the author nor a reviewer already.
+	if f.ExpectedPullRequests > 0 && f.EligibleReviewers <= f.ReviewerCount+1 {
+		f.Risks = append(f.Risks, models.RiskTooFewReviewers)
+	}
+	if f.Capacity > 0 && f.ProjectedOpenReviews > float64(f.Capacity) {
+		f.Risks = append(f.Risks, models.RiskOverCapacity)
+	}
+	f.AtRisk = len(f.Risks) > 0
+	return f
+}
+
+func round2(x float64) float64 {
+	return math.Round(x*100) / 100
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// GitAction is what a Git host reports happened to a PR.
+type GitAction string
+
+const (
+	GitOpened   GitAction = "opened"
+	GitUpdated  GitAction = "updated"
+	GitMerged   GitAction = "merged"
+	GitDeclined GitAction = "declined"
+	// GitReviewerAdded and GitReviewerRemoved report a reviewer added on
+	// the host or taken off there.
+	GitReviewerAdded   GitAction = "reviewer_added"
+	GitReviewerRemoved GitAction = "reviewer_removed"
+)
+
+// GitEventParams is a PR event as a Git host webhook reports it. Author and
+// Reviewer are external IDs of Provider; AuthorEmail and ReviewerEmail, when
+// the host sends them, find users linked by their email instead.
+type GitEventParams struct {
+	Provider        models.IdentityProvider
+	Action          GitAction
+	PullRequestID   string
+	PullRequestName string
+	Repository      string
+	Author          string
+	AuthorEmail     string
+	IsDraft         bool
+	// LinesChanged is left as recorded when zero.
+	LinesChanged  int
+	Reviewer      string
+	ReviewerEmail string
+}
+
+// ApplyGitEvent brings the PR in line with what happened to it on its Git
+// host: an opened PR is created and gets reviewers as any other, an updated
+// one takes the new name and size, a merged one is merged and a declined one
+// closed. Opening a closed PR reopens it with the reviewers it had, and
+// opening any other known PR changes nothing. Merges are recorded even when
+// they would be refused here: they have happened already.
+//
+// Reviewers added or removed on the host are added or removed here too,
+// unless they are not linked to a user, as bots and CI accounts are not.
+func (s *Service) ApplyGitEvent(ctx context.Context, params GitEventParams) (*models.PullRequest, error) {
+	switch params.Action {
+	case GitOpened:
+		return s.openGitPullRequest(ctx, params)
+	case GitUpdated:
+		return s.updateGitPullRequest(ctx, params)
+	case GitMerged:
+		return s.mergePullRequest(ctx, params.PullRequestID, false)
+	case GitDeclined:
+		return s.ClosePullRequest(ctx, params.PullRequestID)
+	case GitReviewerAdded, GitReviewerRemoved:
+		return s.changeGitReviewer(ctx, params)
+	}
+	return nil, &ServiceError{
+		Code:    models.ErrValidation,
+		Message: "action must be opened, updated, merged, declined, reviewer_added or reviewer_removed",
+	}
+}
+
+func (s *Service) openGitPullRequest(ctx context.Context, params GitEventParams) (*models.PullRequest, error) {
+	pr, err := s.repo.GetPullRequest(ctx, params.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if pr != nil {
+		if pr.Status != models.StatusClosed {
+			return pr, nil
+		}
+		pr.Status = models.StatusOpen
+		updated, err := s.repo.UpdatePullRequestStatus(ctx, pr, models.StatusClosed)
+		if err != nil {
+			return nil, err
+		}
+		if !updated {
+			return s.openGitPullRequest(ctx, params)
+		}
+		s.publish(ctx, models.EventPRReopened, pr, "", "")
+		return pr, nil
+	}
+
+	author, err := s.gitUser(ctx, params.Provider, params.Author, params.AuthorEmail)
+	if err != nil {
+		return nil, err
+	}
+	pr, err = s.CreatePullRequest(ctx, CreatePullRequestParams{
+		PullRequestID:   params.PullRequestID,
+		PullRequestName: params.PullRequestName,
+		AuthorID:        author.UserID,
+		Repository:      params.Repository,
+		LinesChanged:    params.LinesChanged,
+		IsDraft:         params.IsDraft,
+	})
+	var serviceErr *ServiceError
+	if errors.As(err, &serviceErr) && serviceErr.Code == models.ErrPRExists {
+		// A concurrent delivery of the same event created it first.
+		return s.repo.GetPullRequest(ctx, params.PullRequestID)
+	}
+	return pr, err
+}
+
+// updateGitPullRequest records a new name or size of an open PR in a
+// pr.updated event, and that it is ready once the host no longer marks it a
+// draft. A PR the host turns back into a draft keeps its reviewers. A PR not
+// seen before is opened.
+func (s *Service) updateGitPullRequest(ctx context.Context, params GitEventParams) (*models.PullRequest, error) {
+	pr, err := s.repo.GetPullRequest(ctx, params.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return s.openGitPullRequest(ctx, params)
+	}
+	if pr.Status != models.StatusOpen {
+		return pr, nil
+	}
+
+	pr, changes, err := s.changeDetails(ctx, pr.PullRequestID, "cannot update %s PR", func(pr *models.PullRequest) ([]models.FieldChange, error) {
+		var changes []models.FieldChange
+		if params.PullRequestName != "" && params.PullRequestName != pr.PullRequestName {
+			changes = append(changes, models.FieldChange{Field: "pull_request_name", Old: pr.PullRequestName, New: params.PullRequestName})
+			pr.PullRequestName = params.PullRequestName
+		}
+		if params.LinesChanged > 0 && params.LinesChanged != pr.LinesChanged {
+			changes = append(changes, models.FieldChange{Field: "lines_changed", Old: pr.LinesChanged, New: params.LinesChanged})
+			pr.LinesChanged = params.LinesChanged
+		}
+		return changes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publishChanges(ctx, pr, changes)
+	if pr.IsDraft && !params.IsDraft {
+		return s.MarkPullRequestReady(ctx, pr.PullRequestID)
+	}
+	return pr, nil
+}
+
+// changeGitReviewer adds or removes the reviewer of the event. A reviewer
+// who is not linked to a user, or who is on the PR already, or not on it,
+// leaves the PR as it is.
+func (s *Service) changeGitReviewer(ctx context.Context, params GitEventParams) (*models.PullRequest, error) {
+	pr, err := s.repo.GetPullRequest(ctx, params.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "PR not found",
+		}
+	}
+
+	reviewer, err := s.gitUser(ctx, params.Provider, params.Reviewer, params.ReviewerEmail)
+	var serviceErr *ServiceError
+	if errors.As(err, &serviceErr) && serviceErr.Code == models.ErrNotFound {
+		return pr, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	onPR := contains(pr.AssignedReviewers, reviewer.UserID) || contains(pr.ShadowReviewers, reviewer.UserID)
+	if params.Action == GitReviewerAdded {
+		if onPR {
+			return pr, nil
+		}
+		return s.AddReviewer(ctx, pr.PullRequestID, reviewer.UserID)
+	}
+	if !onPR {
+		return pr, nil
+	}
+	return s.RemoveReviewer(ctx, pr.PullRequestID, reviewer.UserID)
+}
+
+// gitUser returns the user linked to an account with a Git host, or the one
+// linked to email when the account is not linked or the host sent no
+// account, as Gerrit does for accounts without a username.
+func (s *Service) gitUser(ctx context.Context, provider models.IdentityProvider, externalID, email string) (*models.User, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	var serviceErr *ServiceError
+	if strings.TrimSpace(externalID) == "" && email != "" {
+		serviceErr = &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "no user is linked to email identity " + email,
+		}
+	} else {
+		user, err := s.ResolveIdentity(ctx, provider, externalID)
+		if email == "" || !errors.As(err, &serviceErr) || serviceErr.Code != models.ErrNotFound {
+			return user, err
+		}
+	}
+
+	byEmail, err := s.repo.FindUserByIdentity(ctx, models.ProviderEmail, email)
+	if err != nil {
+		return nil, err
+	}
+	if byEmail == nil {
+		// Linking the host account is the fix to suggest.
+		return nil, serviceErr
+	}
+	return byEmail, nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// GetUserHistory lists every PR the user was assigned to, newest assignment
+// first, including the ones they were later reassigned away or removed from.
+func (s *Service) GetUserHistory(ctx context.Context, filter models.ReviewHistoryFilter) ([]models.ReviewHistoryEntry, error) {
+	if err := s.requireUserInPeriod(ctx, filter.UserID, filter.Since, filter.Until); err != nil {
+		return nil, err
+	}
+
+	filter.Limit, filter.Offset = pageBounds(filter.Limit, filter.Offset)
+	entries, err := s.repo.GetUserHistory(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		switch {
+		case entries[i].ReplacedBy != "":
+			entries[i].Outcome = models.OutcomeReassigned
+		case entries[i].RemovedAt != nil:
+			entries[i].Outcome = models.OutcomeRemoved
+		case entries[i].Status == models.StatusMerged:
+			entries[i].Outcome = models.OutcomeMerged
+		case entries[i].Status == models.StatusClosed:
+			entries[i].Outcome = models.OutcomeClosed
+		default:
+			entries[i].Outcome = models.OutcomeOpen
+		}
+	}
+	return entries, nil
+}
+
+// GetUserStatistics summarizes the user's reviewing between since and until;
+// zero times leave that end of the period open.
+func (s *Service) GetUserStatistics(ctx context.Context, userID string, since, until time.Time) (*models.UserStatistics, error) {
+	if err := s.requireUserInPeriod(ctx, userID, since, until); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.GetUserStatistics(ctx, userID, since, until)
+	if err != nil {
+		return nil, err
+	}
+	if !since.IsZero() {
+		stats.Since = &since
+	}
+	if !until.IsZero() {
+		stats.Until = &until
+	}
+	return stats, nil
+}
+
+// requireUserInPeriod checks that the user exists and the period is not
+// empty.
+func (s *Service) requireUserInPeriod(ctx context.Context, userID string, since, until time.Time) error {
+	if !since.IsZero() && !until.IsZero() && !since.Before(until) {
+		return &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "since must be before until",
+		}
+	}
+
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "user not found",
+		}
+	}
+	return nil
+}
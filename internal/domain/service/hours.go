@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// workdayLead is how long before their working hours start a reviewer
+// already counts as available.
+const workdayLead = time.Hour
+
+func (s *Service) SetUserWorkingHours(ctx context.Context, userID, timezone, hours string) (*models.User, error) {
+	timezone, hours = strings.TrimSpace(timezone), strings.TrimSpace(hours)
+	if msg := validateWorkingHours(timezone, hours); msg != "" {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: msg,
+		}
+	}
+
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "user not found",
+		}
+	}
+
+	user.Timezone = timezone
+	user.WorkingHours = hours
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// validateWorkingHours accepts either both values empty, which clears the
+// schedule, or a known time zone with an "HH:MM-HH:MM" range.
+func validateWorkingHours(timezone, hours string) string {
+	if timezone == "" && hours == "" {
+		return ""
+	}
+	if _, err := time.LoadLocation(timezone); err != nil || timezone == "" {
+		return "timezone must be an IANA time zone name, e.g. Europe/Moscow"
+	}
+	if _, _, err := parseWorkingHours(hours); err != nil {
+		return "working_hours must look like 09:00-18:00"
+	}
+	return ""
+}
+
+// parseWorkingHours returns the start and end of an "HH:MM-HH:MM" range as
+// offsets from midnight. An end before the start means the range crosses
+// midnight.
+func parseWorkingHours(hours string) (start, end time.Duration, err error) {
+	from, to, ok := strings.Cut(hours, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid working hours %q", hours)
+	}
+	if start, err = parseClock(from); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parseClock(to); err != nil {
+		return 0, 0, err
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("empty working hours %q", hours)
+	}
+	return start, end, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// availableAt reports whether the user is within their working hours at
+// now, or starts within workdayLead. Users without a schedule are always
+// available.
+func availableAt(user models.User, now time.Time) bool {
+	if user.Timezone == "" || user.WorkingHours == "" {
+		return true
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return true
+	}
+	start, end, err := parseWorkingHours(user.WorkingHours)
+	if err != nil {
+		return true
+	}
+
+	local := now.In(loc)
+	clock := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	return withinRange(clock, start-workdayLead, end)
+}
+
+// withinRange reports whether clock lies in [start, end) on a 24-hour
+// dial, wrapping around midnight when needed.
+func withinRange(clock, start, end time.Duration) bool {
+	const day = 24 * time.Hour
+	start = (start%day + day) % day
+	if start < end {
+		return clock >= start && clock < end
+	}
+	return clock >= start || clock < end
+}
+
+// splitByAvailability separates users available at now from the rest,
+// keeping their order.
+func splitByAvailability(users []models.User, now time.Time) (available, others []models.User) {
+	for _, u := range users {
+		if availableAt(u, now) {
+			available = append(available, u)
+		} else {
+			others = append(others, u)
+		}
+	}
+	return available, others
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/mail"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// SetUserIdentity links the user to their account with the identity's
+// provider, replacing any earlier link to that provider. External IDs are
+// normalized first: GitHub and GitLab logins and emails are
+// case-insensitive and stored lowercase, Slack member IDs uppercase.
+func (s *Service) SetUserIdentity(ctx context.Context, identity *models.UserIdentity) (*models.UserIdentity, error) {
+	externalID, err := normalizeExternalID(identity.Provider, identity.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+	identity.ExternalID = externalID
+
+	if err := s.requireUser(ctx, identity.UserID); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.SetUserIdentity(ctx, identity)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return nil, &ServiceError{
+			Code:    models.ErrAlreadyExists,
+			Message: string(identity.Provider) + " identity " + externalID + " is linked to another user",
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (s *Service) GetUserIdentities(ctx context.Context, userID string) ([]models.UserIdentity, error) {
+	if err := s.requireUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserIdentities(ctx, []string{userID})
+}
+
+func (s *Service) DeleteUserIdentity(ctx context.Context, userID string, provider models.IdentityProvider) error {
+	deleted, err := s.repo.DeleteUserIdentity(ctx, userID, provider)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "identity not found",
+		}
+	}
+	return nil
+}
+
+// ResolveIdentity returns the user linked to an external identity, for
+// integrations that only know who acted in the external system.
+func (s *Service) ResolveIdentity(ctx context.Context, provider models.IdentityProvider, externalID string) (*models.User, error) {
+	externalID, err := normalizeExternalID(provider, externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.FindUserByIdentity(ctx, provider, externalID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "no user is linked to " + string(provider) + " identity " + externalID,
+		}
+	}
+	return user, nil
+}
+
+func (s *Service) requireUser(ctx context.Context, userID string) error {
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "user not found",
+		}
+	}
+	return nil
+}
+
+func normalizeExternalID(provider models.IdentityProvider, externalID string) (string, error) {
+	externalID = strings.TrimSpace(externalID)
+	var msg string
+	switch provider {
+	case models.ProviderGitHub, models.ProviderGitLab:
+		externalID = strings.ToLower(strings.TrimPrefix(externalID, "@"))
+		if strings.ContainsAny(externalID, " /@") {
+			msg = "external_id must be a " + string(provider) + " login"
+		}
+	case models.ProviderSlack:
+		externalID = strings.ToUpper(externalID)
+		if strings.ContainsAny(externalID, " @") {
+			msg = "external_id must be a Slack member ID"
+		}
+	case models.ProviderEmail:
+		externalID = strings.ToLower(externalID)
+		if addr, err := mail.ParseAddress(externalID); err != nil || addr.Address != externalID {
+			msg = "external_id must be an email address"
+		}
+	case models.ProviderJira:
+		// Jira Cloud account IDs and Server usernames are both kept as given.
+		if strings.ContainsAny(externalID, " ") {
+			msg = "external_id must be a Jira account ID or username"
+		}
+	case models.ProviderBitbucket:
+		// So are Bitbucket Cloud account IDs and Server usernames.
+		if strings.ContainsAny(externalID, " ") {
+			msg = "external_id must be a Bitbucket account ID or username"
+		}
+	case models.ProviderGerrit:
+		if strings.ContainsAny(externalID, " ") {
+			msg = "external_id must be a Gerrit username"
+		}
+	default:
+		msg = "provider must be github, gitlab, slack, email, jira, bitbucket or gerrit"
+	}
+	if msg == "" && externalID == "" {
+		msg = "external_id is required"
+	}
+	if msg != "" {
+		return "", &ServiceError{
+			Code:    models.ErrValidation,
+			Message: msg,
+		}
+	}
+	return externalID, nil
+}
+
+// recipientIdentities returns the external identities of a notification's
+// recipients. Notifications go out without them when they cannot be loaded.
+func (s *Service) recipientIdentities(ctx context.Context, userIDs []string) []models.UserIdentity {
+	identities, err := s.repo.GetUserIdentities(ctx, userIDs)
+	if err != nil {
+		log.Printf("Cannot load identities of %v: %v", userIDs, err)
+		return nil
+	}
+	return identities
+}
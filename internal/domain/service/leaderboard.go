@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+const (
+	defaultLeaderboardDays = 30
+	maxLeaderboardDays     = 365
+)
+
+// GetLeaderboard ranks the team's members over the last days days (30 when
+// zero): most completed reviews first, then the fastest median turnaround,
+// members without reviews last, then the lowest open load, then user_id.
+func (s *Service) GetLeaderboard(ctx context.Context, teamName string, days int) (*models.Leaderboard, error) {
+	if days == 0 {
+		days = defaultLeaderboardDays
+	}
+	if days < 0 || days > maxLeaderboardDays {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "days must be between 1 and 365",
+		}
+	}
+	if err := s.requireTeam(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	since := s.clock.Now().AddDate(0, 0, -days)
+	entries, err := s.repo.GetLeaderboard(ctx, teamName, since)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.CompletedReviews != b.CompletedReviews {
+			return a.CompletedReviews > b.CompletedReviews
+		}
+		if (a.MedianTurnaroundSeconds == nil) != (b.MedianTurnaroundSeconds == nil) {
+			return b.MedianTurnaroundSeconds == nil
+		}
+		if a.MedianTurnaroundSeconds != nil && *a.MedianTurnaroundSeconds != *b.MedianTurnaroundSeconds {
+			return *a.MedianTurnaroundSeconds < *b.MedianTurnaroundSeconds
+		}
+		if a.OpenReviews != b.OpenReviews {
+			return a.OpenReviews < b.OpenReviews
+		}
+		return a.UserID < b.UserID
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+
+	return &models.Leaderboard{TeamName: teamName, Since: since, Entries: entries}, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// WithDefaultLocale sets the language of notifications to teams without a
+// locale of their own. Without it they are in English.
+func WithDefaultLocale(locale models.Locale) Option {
+	return func(s *Service) {
+		if locale.Valid() {
+			s.locale = locale
+		}
+	}
+}
+
+// UserLocale returns the locale of the user's team, or "" when the team has
+// none or the user does not exist.
+func (s *Service) UserLocale(ctx context.Context, userID string) (models.Locale, error) {
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil || user == nil {
+		return "", err
+	}
+	settings, err := s.repo.GetTeamSettings(ctx, user.TeamName)
+	if err != nil || settings == nil {
+		return "", err
+	}
+	return settings.Locale, nil
+}
+
+// teamLocale returns the language notifications to a team are sent in.
+func (s *Service) teamLocale(settings models.TeamSettings) models.Locale {
+	if settings.Locale != "" {
+		return settings.Locale
+	}
+	return s.locale
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// maintenanceRefresh is how long the stored maintenance mode is trusted
+// before it is read again, which bounds how soon other instances follow a
+// change.
+const maintenanceRefresh = 5 * time.Second
+
+// maintenanceCache holds the maintenance mode last read from storage.
+type maintenanceCache struct {
+	mu     sync.Mutex
+	mode   models.Maintenance
+	readAt time.Time
+}
+
+// GetMaintenance returns the stored maintenance mode, off when it was never
+// set.
+func (s *Service) GetMaintenance(ctx context.Context) (*models.Maintenance, error) {
+	m, err := s.repo.GetMaintenance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		m = &models.Maintenance{}
+	}
+	return m, nil
+}
+
+// SetMaintenance switches the read-only maintenance mode on or off for every
+// instance. The message is kept only while the mode is on.
+func (s *Service) SetMaintenance(ctx context.Context, m *models.Maintenance) (*models.Maintenance, error) {
+	m.Message = strings.TrimSpace(m.Message)
+	if !m.Enabled {
+		m.Message = ""
+	}
+	if err := s.repo.SetMaintenance(ctx, m); err != nil {
+		return nil, err
+	}
+
+	s.maintenance.mu.Lock()
+	s.maintenance.readAt = time.Time{}
+	s.maintenance.mu.Unlock()
+	return m, nil
+}
+
+// CurrentMaintenance returns the maintenance mode in effect as last read,
+// reading it again once it is maintenanceRefresh old. Besides the stored
+// mode, a database schema newer than this instance supports puts it in
+// maintenance. When storage cannot be read the last known mode stays in
+// effect.
+func (s *Service) CurrentMaintenance(ctx context.Context) models.Maintenance {
+	s.maintenance.mu.Lock()
+	defer s.maintenance.mu.Unlock()
+
+	now := s.clock.Now()
+	if !s.maintenance.readAt.IsZero() && now.Sub(s.maintenance.readAt) < maintenanceRefresh {
+		return s.maintenance.mode
+	}
+	mode, err := s.GetMaintenance(ctx)
+	if err == nil && !mode.Enabled && s.schemaVersion > 0 {
+		mode, err = s.schemaMaintenance(ctx)
+	}
+	if err != nil {
+		log.Printf("Cannot read maintenance mode, keeping the last known: %v", err)
+		return s.maintenance.mode
+	}
+	s.maintenance.mode = *mode
+	s.maintenance.readAt = now
+	return s.maintenance.mode
+}
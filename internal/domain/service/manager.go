@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// maxManagementChain bounds the walk up a user's managers when checking a
+// new manager for cycles.
+const maxManagementChain = 100
+
+// SetUserManager sets the user's direct manager; an empty managerID clears
+// it. The manager may be on any team, but must not be managed by the user,
+// directly or through others.
+func (s *Service) SetUserManager(ctx context.Context, userID, managerID string) (*models.User, error) {
+	if managerID == userID {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "a user cannot be their own manager",
+		}
+	}
+
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "user not found",
+		}
+	}
+
+	for id, depth := managerID, 0; id != ""; depth++ {
+		manager, err := s.repo.GetUser(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if manager == nil {
+			if id == managerID {
+				return nil, &ServiceError{
+					Code:    models.ErrNotFound,
+					Message: "manager not found",
+				}
+			}
+			break
+		}
+		if manager.ManagerID == userID || depth >= maxManagementChain {
+			return nil, &ServiceError{
+				Code:    models.ErrValidation,
+				Message: "manager_id would make the user their own manager",
+			}
+		}
+		id = manager.ManagerID
+	}
+
+	user.ManagerID = managerID
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// automaticExclusions returns the team members that may not be picked
+// automatically for PRs of authorID: those excluded by the team's exclusion
+// rules and, when the team reviews among peers only, the author's direct
+// manager and direct reports. teamMembers is the team reviewers are picked
+// from, which need not be the author's.
+func automaticExclusions(ctx context.Context, repo repository.Storage, settings models.TeamSettings, teamMembers []models.User, authorID string) (map[string]bool, error) {
+	excluded, err := excludedReviewers(ctx, repo, settings.TeamName, authorID)
+	if err != nil || !settings.PeerReviewOnly {
+		return excluded, err
+	}
+
+	var author *models.User
+	for i := range teamMembers {
+		if teamMembers[i].UserID == authorID {
+			author = &teamMembers[i]
+			break
+		}
+	}
+	if author == nil {
+		author, err = repo.GetUser(ctx, authorID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if author != nil && author.ManagerID != "" {
+		excluded[author.ManagerID] = true
+	}
+	for _, member := range teamMembers {
+		if member.ManagerID == authorID {
+			excluded[member.UserID] = true
+		}
+	}
+	return excluded, nil
+}
+
+// managementPairs returns, for every user of users with a manager, both
+// the user reviewing their manager's PRs and the manager reviewing theirs.
+func managementPairs(users []models.User) map[exclusionKey]bool {
+	pairs := make(map[exclusionKey]bool)
+	for _, u := range users {
+		if u.ManagerID != "" {
+			pairs[exclusionKey{author: u.UserID, reviewer: u.ManagerID}] = true
+			pairs[exclusionKey{author: u.ManagerID, reviewer: u.UserID}] = true
+		}
+	}
+	return pairs
+}
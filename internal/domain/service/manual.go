@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// AddReviewer puts userID on an open PR as a regular reviewer by hand. The
+// user must be eligible as a preferred reviewer would be: an active,
+// non-mentee member of the author's team, not the author and not excluded
+// from the author's PRs. The team's open review limit does not apply, but
+// a PR has at most 10 reviewers.
+func (s *Service) AddReviewer(ctx context.Context, prID, userID string) (*models.PullRequest, error) {
+	return s.changeReviewers(ctx, prID, func(repo repository.Storage, pr *models.PullRequest, teamName string) (*models.AssignmentEvent, error) {
+		if contains(pr.AssignedReviewers, userID) || contains(pr.ShadowReviewers, userID) {
+			return nil, &ServiceError{
+				Code:    models.ErrAlreadyExists,
+				Message: "reviewer " + userID + " is already on this PR",
+			}
+		}
+		if len(pr.AssignedReviewers) >= maxReviewersPerPR {
+			return nil, &ServiceError{
+				Code:    models.ErrValidation,
+				Message: fmt.Sprintf("PR already has %d reviewers", maxReviewersPerPR),
+			}
+		}
+
+		teamMembers, err := repo.GetUsersByTeam(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		excluded, err := excludedReviewers(ctx, repo, teamName, pr.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		if reason := ineligibleReviewer(teamMembers, excluded, pr.AuthorID, userID); reason != "" {
+			return nil, &ServiceError{
+				Code:    models.ErrValidation,
+				Message: fmt.Sprintf("reviewer %s %s", userID, reason),
+			}
+		}
+
+		pr.AssignedReviewers = append(pr.AssignedReviewers, userID)
+		return &models.AssignmentEvent{
+			PullRequestID: pr.PullRequestID,
+			UserID:        userID,
+			Role:          models.RoleReviewer,
+			Action:        models.ActionAdded,
+			Reason:        models.ReasonManual,
+		}, nil
+	})
+}
+
+// RemoveReviewer takes userID, a regular or shadow reviewer, off an open PR
+// by hand without picking a replacement.
+func (s *Service) RemoveReviewer(ctx context.Context, prID, userID string) (*models.PullRequest, error) {
+	return s.changeReviewers(ctx, prID, func(repo repository.Storage, pr *models.PullRequest, teamName string) (*models.AssignmentEvent, error) {
+		role := models.RoleReviewer
+		switch {
+		case contains(pr.AssignedReviewers, userID):
+			pr.AssignedReviewers = without(pr.AssignedReviewers, userID)
+		case contains(pr.ShadowReviewers, userID):
+			role = models.RoleShadow
+			pr.ShadowReviewers = without(pr.ShadowReviewers, userID)
+		default:
+			return nil, &ServiceError{
+				Code:    models.ErrNotAssigned,
+				Message: "reviewer is not assigned to this PR",
+			}
+		}
+
+		return &models.AssignmentEvent{
+			PullRequestID: pr.PullRequestID,
+			UserID:        userID,
+			Role:          role,
+			Action:        models.ActionRemoved,
+			Reason:        models.ReasonManual,
+		}, nil
+	})
+}
+
+// changeReviewers loads an open PR under its author's team lock, lets change
+// edit its reviewers and saves the PR with the history event change
+// returns. The event is published as reviewer.added or reviewer.removed.
+func (s *Service) changeReviewers(ctx context.Context, prID string, change func(repo repository.Storage, pr *models.PullRequest, teamName string) (*models.AssignmentEvent, error)) (*models.PullRequest, error) {
+	pr, err := s.repo.GetPullRequest(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "PR not found",
+		}
+	}
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "author not found",
+		}
+	}
+
+	var event *models.AssignmentEvent
+	err = s.repo.WithTeamLock(ctx, author.TeamName, func(repo repository.Storage) error {
+		pr, err = repo.GetPullRequest(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if pr == nil {
+			return &ServiceError{
+				Code:    models.ErrNotFound,
+				Message: "PR not found",
+			}
+		}
+		if pr.Status != models.StatusOpen {
+			return notOpen(pr, "cannot change reviewers on %s PR")
+		}
+
+		event, err = change(repo, pr, author.TeamName)
+		if err != nil {
+			return err
+		}
+		if err := saveReviewers(ctx, repo, pr, "cannot change reviewers on %s PR"); err != nil {
+			return err
+		}
+		recorded := []models.AssignmentEvent{*event}
+		if err := s.recordAssignments(ctx, repo, recorded); err != nil {
+			return err
+		}
+		event = &recorded[0]
+
+		history, err := repo.GetAssignmentHistory(ctx, pr.PullRequestID)
+		if err != nil {
+			return err
+		}
+		pr.AssignmentReasons = assignmentReasons(pr, history)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if event.Action == models.ActionAdded {
+		s.publish(ctx, models.EventReviewerAdded, pr, "", event.UserID, *event)
+	} else {
+		s.publish(ctx, models.EventReviewerRemoved, pr, event.UserID, "", *event)
+	}
+	return pr, nil
+}
+
+func without(values []string, value string) []string {
+	result := []string{}
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
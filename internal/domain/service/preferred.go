@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// validatePreferred checks the reviewers the author asked for: each must be
+// an active, non-mentee member of the author's team other than the author,
+// not excluded from the author's PRs, and there may be no more of them than
+// the team's reviewer count. The team's open review limit does not apply to
+// them.
+func validatePreferred(ctx context.Context, repo repository.Storage, settings models.TeamSettings, teamMembers []models.User, authorID string, preferred []string) error {
+	if len(preferred) == 0 {
+		return nil
+	}
+	if len(preferred) > settings.ReviewerCount {
+		return &ServiceError{
+			Code:    models.ErrValidation,
+			Message: fmt.Sprintf("at most %d preferred_reviewers allowed", settings.ReviewerCount),
+		}
+	}
+
+	excluded, err := excludedReviewers(ctx, repo, settings.TeamName, authorID)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range preferred {
+		if reason := ineligibleReviewer(teamMembers, excluded, authorID, userID); reason != "" {
+			return &ServiceError{
+				Code:    models.ErrValidation,
+				Message: fmt.Sprintf("preferred reviewer %s %s", userID, reason),
+			}
+		}
+	}
+	return nil
+}
+
+// ineligibleReviewer tells why userID may not be picked by hand as a
+// regular reviewer of a PR by authorID, or returns "" if they may.
+// teamMembers is the author's team and excluded the users excluded from
+// the author's PRs.
+func ineligibleReviewer(teamMembers []models.User, excluded map[string]bool, authorID, userID string) string {
+	var member *models.User
+	for i := range teamMembers {
+		if teamMembers[i].UserID == userID {
+			member = &teamMembers[i]
+		}
+	}
+
+	switch {
+	case userID == authorID:
+		return "is the author"
+	case member == nil:
+		return "is not in the author's team"
+	case !member.IsActive:
+		return "is not active"
+	case member.IsMentee:
+		return "is a mentee"
+	case excluded[userID]:
+		return "is excluded from the author's PRs"
+	}
+	return ""
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// RebalanceTeam evens out the weighted open review load of the team's
+// active, non-mentee members by moving reviews of the team's open PRs from
+// the most loaded reviewers to the least loaded ones. Each review weighs as
+// much as its PR's size bucket, see WithSizeBuckets. With dryRun the moves
+// are planned and reported but not applied.
+func (s *Service) RebalanceTeam(ctx context.Context, teamName string, dryRun bool) (*models.RebalanceResult, error) {
+	if err := s.requireTeam(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	result := &models.RebalanceResult{TeamName: teamName, DryRun: dryRun}
+	var changed []*models.PullRequest
+	var events []models.AssignmentEvent
+	err := s.repo.WithTeamLock(ctx, teamName, func(repo repository.Storage) error {
+		teamMembers, err := repo.GetUsersByTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
+		settings, err := effectiveSettings(ctx, repo, teamName)
+		if err != nil {
+			return err
+		}
+
+		reviewers := []models.User{}
+		for _, member := range teamMembers {
+			if member.IsActive && !member.IsMentee {
+				reviewers = append(reviewers, member)
+			}
+		}
+		loads, counts, err := reviewLoads(ctx, repo, userIDs(reviewers), s.reviewSize)
+		if err != nil {
+			return err
+		}
+		prs, err := repo.GetOpenPullRequestsByTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
+		rules, err := repo.GetExclusionRules(ctx, teamName)
+		if err != nil {
+			return err
+		}
+		excluded := make(map[exclusionKey]bool, len(rules))
+		for _, rule := range rules {
+			excluded[exclusionKey{author: rule.AuthorID, reviewer: rule.ReviewerID}] = true
+		}
+		if settings.PeerReviewOnly {
+			authors, err := outsideAuthors(ctx, repo, prs, teamMembers)
+			if err != nil {
+				return err
+			}
+			for key := range managementPairs(append(authors, teamMembers...)) {
+				excluded[key] = true
+			}
+		}
+
+		result.Before = make(map[string]int, len(reviewers))
+		result.After = make(map[string]int, len(reviewers))
+		for _, r := range reviewers {
+			result.Before[r.UserID] = counts[r.UserID]
+			result.After[r.UserID] = counts[r.UserID]
+		}
+
+		var senior map[string]bool
+		if settings.RequireSenior {
+			senior = seniors(teamMembers)
+		}
+		plan := rebalancePlan{loads: loads, counts: result.After, size: s.reviewSize, excluded: excluded, senior: senior, max: settings.MaxOpenReviews}
+		result.Moves, changed = plan.run(prs, reviewers)
+		if dryRun || len(result.Moves) == 0 {
+			return nil
+		}
+
+		for _, pr := range changed {
+			if err := saveReviewers(ctx, repo, pr, "cannot rebalance %s PR"); err != nil {
+				return err
+			}
+		}
+		events = make([]models.AssignmentEvent, 0, len(result.Moves))
+		for _, move := range result.Moves {
+			events = append(events, models.AssignmentEvent{
+				PullRequestID:  move.PullRequestID,
+				UserID:         move.ToUserID,
+				Role:           models.RoleReviewer,
+				Action:         models.ActionReassigned,
+				Reason:         models.ReasonRebalance,
+				ReplacedUserID: move.FromUserID,
+			})
+		}
+		return s.recordAssignments(ctx, repo, events)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !dryRun {
+		byID := make(map[string]*models.PullRequest, len(changed))
+		for _, pr := range changed {
+			byID[pr.PullRequestID] = pr
+		}
+		for i, move := range result.Moves {
+			s.publish(ctx, models.EventReviewerReassigned, byID[move.PullRequestID], move.FromUserID, move.ToUserID, events[i])
+		}
+	}
+	return result, nil
+}
+
+// outsideAuthors returns the authors of prs who are not among teamMembers.
+func outsideAuthors(ctx context.Context, repo repository.Storage, prs []models.PullRequest, teamMembers []models.User) ([]models.User, error) {
+	members := make(map[string]bool, len(teamMembers))
+	for _, m := range teamMembers {
+		members[m.UserID] = true
+	}
+	ids := []string{}
+	for _, pr := range prs {
+		if !members[pr.AuthorID] {
+			ids = append(ids, pr.AuthorID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return repo.GetUsersByIDs(ctx, uniqueStrings(ids))
+}
+
+type exclusionKey struct {
+	author   string
+	reviewer string
+}
+
+// rebalancePlan is what planning a rebalance works with. loads, the summed
+// sizes of each reviewer's open reviews, and counts, their number, are
+// updated in place as reviews move.
+type rebalancePlan struct {
+	loads    map[string]float64
+	counts   map[string]int
+	size     func(lines int) float64
+	excluded map[exclusionKey]bool
+	senior   map[string]bool
+	// max is the open review count a reviewer may reach; zero means no
+	// limit.
+	max int
+}
+
+// run repeatedly moves one review between two reviewers while that lowers
+// the sum of load²/weight over the team, i.e. while the receiving reviewer
+// stays less loaded, relative to their weight, than the giving one. The
+// reviewers of prs are updated in place. A review is never handed to the
+// PR's author, to someone already on the PR or excluded from the author's
+// PRs, or to a reviewer at the max, nor is a PR's last senior reviewer moved
+// off it for a non-senior when senior is set. The sum strictly decreases
+// with every move, so the loop terminates.
+func (p *rebalancePlan) run(prs []models.PullRequest, reviewers []models.User) ([]models.RebalanceMove, []*models.PullRequest) {
+	users := append([]models.User(nil), reviewers...)
+
+	moves := []models.RebalanceMove{}
+	changed := []*models.PullRequest{}
+	touched := make(map[string]bool)
+	for {
+		sort.Slice(users, func(i, j int) bool {
+			loadI := weightedLoad(p.loads[users[i].UserID], users[i])
+			loadJ := weightedLoad(p.loads[users[j].UserID], users[j])
+			if loadI != loadJ {
+				return loadI > loadJ
+			}
+			return users[i].UserID < users[j].UserID
+		})
+
+		move, pr := p.next(prs, users)
+		if pr == nil {
+			return moves, changed
+		}
+
+		for i, reviewerID := range pr.AssignedReviewers {
+			if reviewerID == move.FromUserID {
+				pr.AssignedReviewers[i] = move.ToUserID
+				break
+			}
+		}
+		size := p.size(pr.LinesChanged)
+		p.loads[move.FromUserID] -= size
+		p.loads[move.ToUserID] += size
+		p.counts[move.FromUserID]--
+		p.counts[move.ToUserID]++
+		moves = append(moves, move)
+		if !touched[pr.PullRequestID] {
+			touched[pr.PullRequestID] = true
+			changed = append(changed, pr)
+		}
+	}
+}
+
+// next finds a review to move from the most loaded reviewer possible to the
+// least loaded one possible. users must be sorted by descending weighted
+// load.
+func (p *rebalancePlan) next(prs []models.PullRequest, users []models.User) (models.RebalanceMove, *models.PullRequest) {
+	for _, from := range users {
+		for i := len(users) - 1; i >= 0; i-- {
+			to := users[i]
+			if from.UserID == to.UserID || p.max > 0 && p.counts[to.UserID] >= p.max {
+				continue
+			}
+			for j := range prs {
+				pr := &prs[j]
+				if pr.AuthorID == to.UserID || !contains(pr.AssignedReviewers, from.UserID) ||
+					contains(pr.AssignedReviewers, to.UserID) || contains(pr.ShadowReviewers, to.UserID) ||
+					p.excluded[exclusionKey{author: pr.AuthorID, reviewer: to.UserID}] {
+					continue
+				}
+				if p.senior[from.UserID] && !p.senior[to.UserID] && !hasSenior(pr.AssignedReviewers, p.senior, from.UserID) {
+					continue
+				}
+				if !improves(p.loads[from.UserID], from, p.loads[to.UserID], to, p.size(pr.LinesChanged)) {
+					continue
+				}
+				return models.RebalanceMove{PullRequestID: pr.PullRequestID, FromUserID: from.UserID, ToUserID: to.UserID}, pr
+			}
+		}
+	}
+	return models.RebalanceMove{}, nil
+}
+
+// improves reports whether moving a review of the given size from a
+// reviewer with fromLoad to one with toLoad lowers the sum of load²/weight.
+// With equal weights that means the loads differ by more than the size.
+func improves(fromLoad float64, from models.User, toLoad float64, to models.User, size float64) bool {
+	return weightedLoad(2*toLoad+size, to) < weightedLoad(2*fromLoad-size, from)
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// replayBatch is how many PRs an event replay loads at a time, and reports
+// its progress after.
+const replayBatch = 100
+
+// ReplayEvents rebuilds the assignment history of PRs from the event log,
+// then the statistics derived from it, to repair them after a bug or a
+// schema change. Only PRs whose logged events start with their creation
+// and end with the reviewers they have now are replayed; the others, such
+// as PRs created before the log was kept, are skipped and keep their
+// history. Open review counts are read from the PRs themselves and need no
+// rebuilding. progress, when not nil, is called after every batch of PRs.
+func (s *Service) ReplayEvents(ctx context.Context, opts models.ReplayOptions, progress func(models.ReplayProgress)) (*models.ReplayProgress, error) {
+	result := &models.ReplayProgress{DryRun: opts.DryRun}
+	report := func() {
+		if progress != nil {
+			progress(*result)
+		}
+	}
+
+	if opts.PullRequestID != "" {
+		exists, err := s.repo.PullRequestExists(ctx, opts.PullRequestID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, &ServiceError{
+				Code:    models.ErrNotFound,
+				Message: "PR not found",
+			}
+		}
+		result.PullRequests = 1
+		if err := s.replayPullRequest(ctx, opts.PullRequestID, opts.DryRun, result); err != nil {
+			return nil, err
+		}
+	} else {
+		total, err := s.repo.CountEventPullRequests(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result.PullRequests = total
+
+		after := ""
+		for {
+			prIDs, err := s.repo.GetEventPullRequests(ctx, after, replayBatch)
+			if err != nil {
+				return nil, err
+			}
+			if len(prIDs) == 0 {
+				break
+			}
+			for _, prID := range prIDs {
+				if err := s.replayPullRequest(ctx, prID, opts.DryRun, result); err != nil {
+					return nil, err
+				}
+			}
+			after = prIDs[len(prIDs)-1]
+			report()
+		}
+	}
+
+	if !opts.DryRun && result.Replayed > 0 {
+		if err := s.repo.RefreshStatistics(ctx); err != nil {
+			return nil, err
+		}
+	}
+	result.Done = true
+	report()
+	return result, nil
+}
+
+// replayPullRequest replays the logged events of one PR under its author's
+// team lock, so no assignment is recorded between reading the events and
+// replacing the history.
+func (s *Service) replayPullRequest(ctx context.Context, prID string, dryRun bool, result *models.ReplayProgress) error {
+	result.Processed++
+	pr, err := s.repo.GetPullRequest(ctx, prID)
+	if err != nil {
+		return err
+	}
+	if pr == nil {
+		result.Skipped++
+		return nil
+	}
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		return err
+	}
+	teamName := ""
+	if author != nil {
+		teamName = author.TeamName
+	}
+
+	return s.repo.WithTeamLock(ctx, teamName, func(repo repository.Storage) error {
+		pr, err := repo.GetPullRequest(ctx, prID)
+		if err != nil {
+			return err
+		}
+		events, err := repo.GetPullRequestEvents(ctx, prID)
+		if err != nil {
+			return err
+		}
+		result.Events += int64(len(events))
+
+		history, ok := replayHistory(pr, events)
+		if !ok {
+			result.Skipped++
+			return nil
+		}
+		result.Replayed++
+		if dryRun {
+			return nil
+		}
+		return repo.ReplaceAssignmentHistory(ctx, prID, history)
+	})
+}
+
+// replayHistory returns the assignment history events of pr lead to, and
+// false when they do not lead to pr as it is.
+func replayHistory(pr *models.PullRequest, events []models.Event) ([]models.AssignmentEvent, bool) {
+	if pr == nil || len(events) == 0 || events[0].Type != models.EventPRCreated {
+		return nil, false
+	}
+	last := events[len(events)-1].PullRequest
+	if last == nil || !sameMembers(last.AssignedReviewers, pr.AssignedReviewers) ||
+		!sameMembers(last.ShadowReviewers, pr.ShadowReviewers) {
+		return nil, false
+	}
+
+	history := []models.AssignmentEvent{}
+	for _, event := range events {
+		for _, a := range event.Assignments {
+			a.PullRequestID = pr.PullRequestID
+			if a.CreatedAt == nil {
+				at := event.CreatedAt
+				a.CreatedAt = &at
+			}
+			history = append(history, a)
+		}
+	}
+	return history, true
+}
+
+func sameMembers(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// WithRequireRepository makes CreatePullRequest and UpdatePullRequest reject
+// PRs that do not name a registered repository or one of its components.
+func WithRequireRepository(require bool) Option {
+	return func(s *Service) {
+		s.requireRepository = require
+	}
+}
+
+func (s *Service) CreateRepository(ctx context.Context, r *models.Repository) (*models.Repository, error) {
+	if err := s.validateRepository(ctx, r); err != nil {
+		return nil, err
+	}
+
+	err := s.repo.CreateRepository(ctx, r)
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return nil, &ServiceError{
+			Code:    models.ErrAlreadyExists,
+			Message: "repository already exists",
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *Service) GetRepository(ctx context.Context, name string) (*models.Repository, error) {
+	r, err := s.repo.GetRepository(ctx, normalizeRepository(name))
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, repositoryNotFound()
+	}
+	return r, nil
+}
+
+// ListRepositories returns the registered repositories, only those owned
+// by teamName unless it is empty.
+func (s *Service) ListRepositories(ctx context.Context, teamName string) ([]models.Repository, error) {
+	if teamName != "" {
+		if err := s.requireTeam(ctx, teamName); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.ListRepositories(ctx, teamName)
+}
+
+func (s *Service) UpdateRepository(ctx context.Context, r *models.Repository) (*models.Repository, error) {
+	if err := s.validateRepository(ctx, r); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateRepository(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, repositoryNotFound()
+	}
+	return r, nil
+}
+
+// DeleteRepository unregisters the repository. Its PRs keep naming it.
+func (s *Service) DeleteRepository(ctx context.Context, name string) error {
+	deleted, err := s.repo.DeleteRepository(ctx, normalizeRepository(name))
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return repositoryNotFound()
+	}
+	return nil
+}
+
+func (s *Service) validateRepository(ctx context.Context, r *models.Repository) error {
+	r.Name = normalizeRepository(r.Name)
+	r.DefaultTeam = strings.TrimSpace(r.DefaultTeam)
+	var msg string
+	switch {
+	case r.Name == "" || strings.Contains(r.Name, "/"):
+		msg = "name must be a repository name without components"
+	case r.Settings.ReviewerCount < 0 || r.Settings.ReviewerCount > maxReviewersPerPR:
+		msg = "reviewer_count must be between 0 and 10"
+	case r.Settings.RequiredApprovals < 0 || r.Settings.RequiredApprovals > maxReviewersPerPR:
+		msg = "required_approvals must be between 0 and 10"
+	}
+	if msg != "" {
+		return &ServiceError{
+			Code:    models.ErrValidation,
+			Message: msg,
+		}
+	}
+
+	if r.DefaultTeam != "" {
+		return s.requireTeam(ctx, r.DefaultTeam)
+	}
+	return nil
+}
+
+// checkRepository rejects a new PR's repository when registration is
+// required and repoName, already normalized, is empty or not registered.
+func (s *Service) checkRepository(ctx context.Context, repoName string) error {
+	if !s.requireRepository {
+		return nil
+	}
+	if repoName == "" {
+		return &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "repository is required",
+		}
+	}
+
+	r, err := s.repo.GetRepository(ctx, repositoryName(repoName))
+	if err != nil {
+		return err
+	}
+	if r == nil {
+		return &ServiceError{
+			Code:    models.ErrInvalidReference,
+			Message: "repository " + repositoryName(repoName) + " is not registered",
+		}
+	}
+	return nil
+}
+
+// pullRequestSettings returns the team's effective settings for a PR in
+// repoName, with the repository's reviewer_count in place of the team's
+// when it sets one.
+func pullRequestSettings(ctx context.Context, repo repository.Storage, teamName, repoName string) (models.TeamSettings, error) {
+	settings, err := effectiveSettings(ctx, repo, teamName)
+	if err != nil || repoName == "" {
+		return settings, err
+	}
+
+	r, err := repo.GetRepository(ctx, repositoryName(repoName))
+	if err != nil {
+		return models.TeamSettings{}, err
+	}
+	if r != nil && r.Settings.ReviewerCount > 0 {
+		settings.ReviewerCount = r.Settings.ReviewerCount
+	}
+	return settings, nil
+}
+
+// repositoryName returns the repository a PR's repository field names,
+// dropping any component.
+func repositoryName(repoName string) string {
+	name, _, _ := strings.Cut(repoName, "/")
+	return name
+}
+
+func repositoryNotFound() error {
+	return &ServiceError{
+		Code:    models.ErrNotFound,
+		Message: "repository not found",
+	}
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+func (s *Service) CreateRepositoryRule(ctx context.Context, rule *models.RepositoryRule) (*models.RepositoryRule, error) {
+	rule.Repository = normalizeRepository(rule.Repository)
+	rule.UserIDs = uniqueStrings(rule.UserIDs)
+	if rule.TeamName == "" || rule.Repository == "" || len(rule.UserIDs) == 0 {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "team_name, repository and user_ids are required",
+		}
+	}
+
+	if err := s.requireTeamMembers(ctx, rule.TeamName, rule.UserIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateRepositoryRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *Service) GetRepositoryRules(ctx context.Context, teamName string) ([]models.RepositoryRule, error) {
+	if err := s.requireTeam(ctx, teamName); err != nil {
+		return nil, err
+	}
+	return s.repo.GetRepositoryRules(ctx, teamName)
+}
+
+func (s *Service) DeleteRepositoryRule(ctx context.Context, id int64) error {
+	deleted, err := s.repo.DeleteRepositoryRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "repository rule not found",
+		}
+	}
+	return nil
+}
+
+// requiredReviewers picks reviewers satisfying the team's repository rules
+// matching repoName, in rule order. A rule already satisfied by a chosen or
+// earlier picked reviewer is skipped; otherwise the least loaded of its
+// users among the candidates is picked. Rules none of whose users are
+// candidates are skipped.
+func (s *Service) requiredReviewers(ctx context.Context, repo repository.Storage, teamName string, candidates []models.User, repoName string, chosen []string) ([]Decision, error) {
+	decisions := []Decision{}
+	if repoName == "" {
+		return decisions, nil
+	}
+
+	rules, err := repo.GetRepositoryRules(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	taken := append([]string{}, chosen...)
+	for _, rule := range rules {
+		if !matchesRepository(rule.Repository, repoName) || containsAny(taken, rule.UserIDs) {
+			continue
+		}
+
+		group := []models.User{}
+		for _, c := range excludeUsers(candidates, taken) {
+			if contains(rule.UserIDs, c.UserID) {
+				group = append(group, c)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+
+		picked, err := s.strategies[StrategyLeastLoaded].SelectReviewers(ctx, repo, AssignmentRequest{
+			Candidates: group,
+			Count:      1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range picked {
+			taken = append(taken, d.UserID)
+			decisions = append(decisions, Decision{UserID: d.UserID, Reason: models.ReasonCodeOwner})
+		}
+	}
+	return decisions, nil
+}
+
+// matchesRepository reports whether a rule for ruleRepo applies to a PR in
+// repoName: the same repository or one of its components.
+func matchesRepository(ruleRepo, repoName string) bool {
+	return repoName == ruleRepo || strings.HasPrefix(repoName, ruleRepo+"/")
+}
+
+func normalizeRepository(name string) string {
+	return strings.Trim(strings.ToLower(strings.TrimSpace(name)), "/")
+}
+
+func containsAny(values, wanted []string) bool {
+	for _, w := range wanted {
+		if contains(values, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := []string{}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// EraseUser anonymizes the user's personal data: the username is replaced,
+// working hours and review comments are cleared and the user is deactivated.
+// The user ID stays, so the PRs, reviews and assignments referring to it are
+// kept. Statistics are recomputed right away so the old username does not
+// linger there.
+func (s *Service) EraseUser(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "user not found",
+		}
+	}
+
+	if err := s.repo.EraseUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.RefreshStatistics(ctx); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUser(ctx, userID)
+}
+
+// EraseInactiveUsers erases users deactivated more than olderThan ago and
+// returns how many were erased.
+func (s *Service) EraseInactiveUsers(ctx context.Context, olderThan time.Duration) (int64, error) {
+	n, err := s.repo.EraseInactiveUsers(ctx, s.clock.Now().Add(-olderThan))
+	if err != nil || n == 0 {
+		return n, err
+	}
+	return n, s.repo.RefreshStatistics(ctx)
+}
+
+// PurgeArchivedPullRequests deletes PRs archived more than olderThan ago,
+// with their reviews and assignment history, and returns how many were
+// deleted.
+func (s *Service) PurgeArchivedPullRequests(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return s.repo.PurgeArchivedPullRequests(ctx, s.clock.Now().Add(-olderThan))
+}
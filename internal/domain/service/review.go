@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// WithRequiredApprovals makes MergePullRequest refuse PRs approved by fewer
+// than n of their assigned reviewers. Zero disables the check.
+func WithRequiredApprovals(n int) Option {
+	return func(s *Service) {
+		s.SetRequiredApprovals(n)
+	}
+}
+
+// SetRequiredApprovals changes the WithRequiredApprovals setting at runtime.
+func (s *Service) SetRequiredApprovals(n int) {
+	s.requiredApprovals.Store(int64(n))
+}
+
+func (s *Service) SubmitReview(ctx context.Context, review *models.Review) (*models.Review, error) {
+	review.Comment = strings.TrimSpace(review.Comment)
+	if !review.State.Valid() {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "state must be one of APPROVED, CHANGES_REQUESTED, COMMENTED",
+		}
+	}
+
+	pr, err := s.repo.GetPullRequest(ctx, review.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "PR not found",
+		}
+	}
+	if pr.Status != models.StatusOpen {
+		return nil, notOpen(pr, "cannot review %s PR")
+	}
+	if !contains(pr.AssignedReviewers, review.ReviewerID) && !contains(pr.ShadowReviewers, review.ReviewerID) {
+		return nil, &ServiceError{
+			Code:    models.ErrNotAssigned,
+			Message: "reviewer is not assigned to this PR",
+		}
+	}
+
+	if err := s.repo.CreateReview(ctx, review); err != nil {
+		return nil, err
+	}
+	if review.State == models.ReviewApproved && contains(pr.AssignedReviewers, review.ReviewerID) {
+		s.queueReviewTaskEvent(ctx, models.OutboxReviewTaskApprove, pr.PullRequestID, review.ReviewerID)
+	}
+	return review, nil
+}
+
+func (s *Service) GetPullRequestDetails(ctx context.Context, prID string) (*models.PullRequestDetails, error) {
+	pr, err := s.repo.GetPullRequest(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "PR not found",
+		}
+	}
+
+	history, err := s.repo.GetAssignmentHistory(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Reviews == nil {
+		pr.Reviews = []models.Review{}
+	}
+	pr.AssignmentReasons = assignmentReasons(pr, history)
+
+	required, err := s.requiredApprovalsFor(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PullRequestDetails{
+		PR:                *pr,
+		History:           history,
+		Approvals:         countApprovals(pr),
+		RequiredApprovals: required,
+	}, nil
+}
+
+// assignmentReasons returns why each current reviewer of pr was picked,
+// taken from their latest assignment event. Reviewers whose events predate
+// recorded reasons are left out.
+func assignmentReasons(pr *models.PullRequest, history []models.AssignmentEvent) map[string]models.AssignmentReason {
+	latest := make(map[string]models.AssignmentReason)
+	for _, e := range history {
+		latest[e.UserID] = e.Reason
+	}
+
+	reasons := make(map[string]models.AssignmentReason)
+	for _, userID := range append(append([]string{}, pr.AssignedReviewers...), pr.ShadowReviewers...) {
+		if reason := latest[userID]; reason != "" {
+			reasons[userID] = reason
+		}
+	}
+	return reasons
+}
+
+// countApprovals returns how many assigned reviewers have APPROVED as their
+// latest review state. Shadow reviewers and reviewers that were replaced do
+// not count.
+func countApprovals(pr *models.PullRequest) int {
+	latest := make(map[string]models.ReviewState)
+	for _, r := range pr.Reviews {
+		latest[r.ReviewerID] = r.State
+	}
+
+	approvals := 0
+	for _, reviewerID := range pr.AssignedReviewers {
+		if latest[reviewerID] == models.ReviewApproved {
+			approvals++
+		}
+	}
+	return approvals
+}
+
+// requiredApprovalsFor returns the approvals pr needs before merging: the
+// required_approvals of its repository, or else the WithRequiredApprovals
+// setting, while FlagApprovalGating is on for the author's team, zero
+// otherwise.
+func (s *Service) requiredApprovalsFor(ctx context.Context, pr *models.PullRequest) (int, error) {
+	required := int(s.requiredApprovals.Load())
+	if pr.Repository != "" {
+		r, err := s.repo.GetRepository(ctx, repositoryName(pr.Repository))
+		if err != nil {
+			return 0, err
+		}
+		if r != nil && r.Settings.RequiredApprovals > 0 {
+			required = r.Settings.RequiredApprovals
+		}
+	}
+	if required <= 0 {
+		return 0, nil
+	}
+
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		return 0, err
+	}
+	teamName := ""
+	if author != nil {
+		teamName = author.TeamName
+	}
+	gated, err := s.featureEnabled(ctx, FlagApprovalGating, teamName)
+	if err != nil || !gated {
+		return 0, err
+	}
+	return required, nil
+}
+
+func (s *Service) checkApprovals(ctx context.Context, pr *models.PullRequest) error {
+	required, err := s.requiredApprovalsFor(ctx, pr)
+	if err != nil || required <= 0 {
+		return err
+	}
+
+	if len(pr.AssignedReviewers) < required {
+		required = len(pr.AssignedReviewers)
+	}
+	if approvals := countApprovals(pr); approvals < required {
+		return &ServiceError{
+			Code:    models.ErrNotApproved,
+			Message: fmt.Sprintf("PR has %d of %d required approvals", approvals, required),
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// WithReviewRequests requests the review of every regular reviewer put on
+// a PR on the Git host PR it stands for, and withdraws the request when
+// they are taken off, so the host shows who is to review. The calls go
+// through the outbox like those of WithIssueTracker and are given up on
+// after maxAttempts failures, or defaultOutboxAttempts when it is zero.
+func WithReviewRequests(requester repository.ReviewRequester, maxAttempts int) Option {
+	return func(s *Service) {
+		s.reviewRequests = requester
+		s.outboxAttempts = maxAttempts
+		if s.outboxAttempts <= 0 {
+			s.outboxAttempts = defaultOutboxAttempts
+		}
+	}
+}
+
+// syncReviewRequest brings the host's request for userID's review in line
+// with the PR as it is when the message is delivered, not as it was when
+// it was queued: the review is requested while they are a reviewer of the
+// open PR and withdrawn otherwise. Requests the host has already, or lacks
+// already, are left alone, and so are reviewers without a linked GitHub
+// account. A request the host refuses for good, e.g. of the PR's author
+// there, is given up on right away.
+func (s *Service) syncReviewRequest(ctx context.Context, prID, userID string) error {
+	if s.reviewRequests == nil {
+		return nil
+	}
+	pr, err := s.repo.GetPullRequest(ctx, prID)
+	if err != nil {
+		return err
+	}
+	if pr == nil || pr.Status != models.StatusOpen || !s.reviewRequests.Hosts(*pr) {
+		return nil
+	}
+
+	identities, err := s.repo.GetUserIdentities(ctx, []string{userID})
+	if err != nil {
+		return err
+	}
+	login := ""
+	for _, identity := range identities {
+		if identity.Provider == models.ProviderGitHub {
+			login = identity.ExternalID
+		}
+	}
+	if login == "" {
+		return nil
+	}
+
+	err = s.reconcileReviewRequest(ctx, pr, contains(pr.AssignedReviewers, userID), login)
+	if errors.Is(err, repository.ErrRejected) {
+		log.Printf("Review request of %s on PR %s was rejected, giving up: %v", login, prID, err)
+		return nil
+	}
+	return err
+}
+
+// reconcileReviewRequest requests login's review of pr when wanted and
+// withdraws it otherwise, unless the host has it that way already.
+func (s *Service) reconcileReviewRequest(ctx context.Context, pr *models.PullRequest, wanted bool, login string) error {
+	requested, err := s.reviewRequests.RequestedReviewers(ctx, *pr)
+	if err != nil {
+		return err
+	}
+	// Logins are stored lowercase, the host keeps their case.
+	isRequested := false
+	for _, r := range requested {
+		isRequested = isRequested || strings.EqualFold(r, login)
+	}
+	switch {
+	case wanted && !isRequested:
+		return s.reviewRequests.RequestReviewers(ctx, *pr, []string{login})
+	case !wanted && isRequested:
+		return s.reviewRequests.RemoveReviewRequests(ctx, *pr, []string{login})
+	}
+	return nil
+}
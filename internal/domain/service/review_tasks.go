@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+const (
+	// defaultOutboxAttempts is how often an outbox message is tried before
+	// it is given up on, unless WithIssueTracker says otherwise.
+	defaultOutboxAttempts = 10
+	// outboxBatch is how many messages one DeliverOutbox call claims.
+	outboxBatch = 100
+	// outboxLease keeps a claimed message from being delivered by another
+	// instance while this one is at it.
+	outboxLease = 5 * time.Minute
+	// outboxBackoff is the delay before the first retry of an outbox
+	// message or webhook delivery; it doubles with every failure up to
+	// outboxMaxBackoff.
+	outboxBackoff    = 30 * time.Second
+	outboxMaxBackoff = time.Hour
+)
+
+// WithIssueTracker files a task in tracker for every regular reviewer put
+// on a PR and moves it on when they approve and when the PR is merged. The
+// calls go through the outbox, which DeliverOutbox works off; a call is
+// given up on after maxAttempts failures, or defaultOutboxAttempts when it
+// is zero.
+func WithIssueTracker(tracker repository.IssueTracker, maxAttempts int) Option {
+	return func(s *Service) {
+		s.tracker = tracker
+		s.outboxAttempts = maxAttempts
+		if s.outboxAttempts <= 0 {
+			s.outboxAttempts = defaultOutboxAttempts
+		}
+	}
+}
+
+// recordAssignments saves assignment events and queues a review task and a
+// review request on the Git host for every regular reviewer they put on a
+// PR, in the same transaction. Reviewers they take off have their review
+// request withdrawn.
+func (s *Service) recordAssignments(ctx context.Context, repo repository.Storage, events []models.AssignmentEvent) error {
+	if err := repo.AddAssignmentEvents(ctx, events); err != nil {
+		return err
+	}
+	if s.tracker == nil && s.reviewRequests == nil {
+		return nil
+	}
+
+	now := s.clock.Now()
+	messages := []models.OutboxMessage{}
+	queue := func(kind models.OutboxKind, prID, userID string) {
+		messages = append(messages, models.OutboxMessage{
+			Kind:          kind,
+			PullRequestID: prID,
+			UserID:        userID,
+			NextAttemptAt: &now,
+		})
+	}
+	for _, e := range events {
+		if e.Role != models.RoleReviewer {
+			continue
+		}
+		if s.reviewRequests != nil {
+			switch {
+			case e.Action == models.ActionRemoved:
+				queue(models.OutboxReviewRequestRemove, e.PullRequestID, e.UserID)
+			case e.ReplacedUserID != "":
+				queue(models.OutboxReviewRequestRemove, e.PullRequestID, e.ReplacedUserID)
+				fallthrough
+			default:
+				queue(models.OutboxReviewRequestAdd, e.PullRequestID, e.UserID)
+			}
+		}
+		if s.tracker != nil && e.Action != models.ActionRemoved {
+			queue(models.OutboxReviewTaskCreate, e.PullRequestID, e.UserID)
+		}
+	}
+	return repo.AddOutboxMessages(ctx, messages)
+}
+
+// queueReviewTaskEvent queues moving the review tasks of a PR on: userID's
+// task, or every task when userID is empty. The change causing it is saved
+// already, so a failure is only logged.
+func (s *Service) queueReviewTaskEvent(ctx context.Context, kind models.OutboxKind, prID, userID string) {
+	if s.tracker == nil {
+		return
+	}
+	now := s.clock.Now()
+	err := s.repo.AddOutboxMessages(ctx, []models.OutboxMessage{{
+		Kind:          kind,
+		PullRequestID: prID,
+		UserID:        userID,
+		NextAttemptAt: &now,
+	}})
+	if err != nil {
+		log.Printf("Cannot queue %s of PR %s: %v", kind, prID, err)
+	}
+}
+
+// DeliverOutbox makes the due calls of the outbox and returns how many
+// succeeded. Failed calls are retried with exponential backoff until they
+// run out of attempts.
+func (s *Service) DeliverOutbox(ctx context.Context) (int, error) {
+	if s.tracker == nil && s.reviewRequests == nil {
+		return 0, nil
+	}
+
+	now := s.clock.Now()
+	messages, err := s.repo.ClaimOutboxMessages(ctx, now, now.Add(outboxLease), outboxBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, m := range messages {
+		if err := s.deliver(ctx, m); err != nil {
+			s.failDelivery(ctx, m, err)
+			continue
+		}
+		if err := s.repo.DeleteOutboxMessage(ctx, m.ID); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+// failDelivery schedules the retry of m, or gives up on it when it has run
+// out of attempts. A rate limited call is not retried before the limit
+// resets.
+func (s *Service) failDelivery(ctx context.Context, m models.OutboxMessage, cause error) {
+	attempts := m.Attempts + 1
+	var next *time.Time
+	if attempts < s.outboxAttempts {
+		at := s.clock.Now().Add(retryDelay(attempts))
+		var limited *repository.RateLimitError
+		if errors.As(cause, &limited) && limited.Reset.After(at) {
+			at = limited.Reset
+		}
+		next = &at
+		log.Printf("Outbox message %d (%s of PR %s) failed, retrying at %s: %v", m.ID, m.Kind, m.PullRequestID, at.Format(time.RFC3339), cause)
+	} else {
+		log.Printf("Outbox message %d (%s of PR %s) failed %d times, giving up: %v", m.ID, m.Kind, m.PullRequestID, attempts, cause)
+	}
+
+	if err := s.repo.FailOutboxMessage(ctx, m.ID, cause.Error(), next); err != nil {
+		// The lease runs out and the message is retried anyway.
+		log.Printf("Cannot record failure of outbox message %d: %v", m.ID, err)
+	}
+}
+
+// retryDelay is how long to wait after the given number of failed attempts.
+func retryDelay(attempts int) time.Duration {
+	delay := outboxBackoff
+	for i := 1; i < attempts && delay < outboxMaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > outboxMaxBackoff {
+		delay = outboxMaxBackoff
+	}
+	return delay
+}
+
+func (s *Service) deliver(ctx context.Context, m models.OutboxMessage) error {
+	switch m.Kind {
+	case models.OutboxReviewTaskCreate:
+		return s.createReviewTask(ctx, m.PullRequestID, m.UserID)
+	case models.OutboxReviewTaskApprove:
+		return s.transitionReviewTasks(ctx, m.PullRequestID, m.UserID, models.ReviewTaskApproved)
+	case models.OutboxReviewTaskMerge:
+		return s.transitionReviewTasks(ctx, m.PullRequestID, "", models.ReviewTaskMerged)
+	case models.OutboxReviewRequestAdd, models.OutboxReviewRequestRemove:
+		return s.syncReviewRequest(ctx, m.PullRequestID, m.UserID)
+	}
+	return fmt.Errorf("unknown outbox message kind %q", m.Kind)
+}
+
+// createReviewTask files userID's task for reviewing the PR. Nothing is
+// filed when they are no longer its reviewer by the time the message is
+// delivered, or have a task already.
+func (s *Service) createReviewTask(ctx context.Context, prID, userID string) error {
+	pr, err := s.repo.GetPullRequest(ctx, prID)
+	if err != nil {
+		return err
+	}
+	if pr == nil || pr.Status != models.StatusOpen || !contains(pr.AssignedReviewers, userID) {
+		return nil
+	}
+	tasks, err := s.repo.GetReviewTasks(ctx, prID)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if task.UserID == userID {
+			return nil
+		}
+	}
+	reviewer, err := s.repo.GetUser(ctx, userID)
+	if err != nil || reviewer == nil {
+		return err
+	}
+
+	parent, err := s.reviewIssue(ctx, pr)
+	if err != nil {
+		return err
+	}
+	assignee := ""
+	identities, err := s.repo.GetUserIdentities(ctx, []string{userID})
+	if err != nil {
+		return err
+	}
+	for _, identity := range identities {
+		if identity.Provider == models.ProviderJira {
+			assignee = identity.ExternalID
+		}
+	}
+
+	key, err := s.tracker.CreateTask(ctx, parent, *pr, *reviewer, assignee)
+	if err != nil {
+		return err
+	}
+	return s.repo.AddReviewTask(ctx, models.ReviewTask{PullRequestID: prID, UserID: userID, IssueKey: key})
+}
+
+// reviewIssue returns the key of the issue review tasks of pr are filed
+// under: the one used before, the one the PR refers to, or a new one in the
+// project of the author's team.
+func (s *Service) reviewIssue(ctx context.Context, pr *models.PullRequest) (string, error) {
+	key, err := s.repo.GetReviewIssue(ctx, pr.PullRequestID)
+	if err != nil || key != "" {
+		return key, err
+	}
+
+	key = s.tracker.ReferencedIssue(*pr)
+	if key == "" {
+		author, err := s.repo.GetUser(ctx, pr.AuthorID)
+		if err != nil {
+			return "", err
+		}
+		if author == nil {
+			return "", fmt.Errorf("author %s of PR %s not found", pr.AuthorID, pr.PullRequestID)
+		}
+		key, err = s.tracker.CreateIssue(ctx, *pr, author.TeamName)
+		if err != nil {
+			return "", err
+		}
+	}
+	return key, s.repo.SetReviewIssue(ctx, pr.PullRequestID, key)
+}
+
+// transitionReviewTasks moves userID's task of the PR on after event, or
+// every task of the PR when userID is empty.
+func (s *Service) transitionReviewTasks(ctx context.Context, prID, userID string, event models.ReviewTaskEvent) error {
+	tasks, err := s.repo.GetReviewTasks(ctx, prID)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if userID != "" && task.UserID != userID {
+			continue
+		}
+		if err := s.tracker.TransitionTask(ctx, task.IssueKey, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
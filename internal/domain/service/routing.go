@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+func (s *Service) CreateRoutingRule(ctx context.Context, rule *models.RoutingRule) (*models.RoutingRule, error) {
+	rule.Label = normalizeLabel(rule.Label)
+	if rule.Label == "" || rule.TeamName == "" || rule.UserID == "" {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "team_name, label and user_id are required",
+		}
+	}
+
+	user, err := s.repo.GetUser(ctx, rule.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.TeamName != rule.TeamName {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "user not found in team",
+		}
+	}
+
+	if err := s.repo.CreateRoutingRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *Service) GetRoutingRules(ctx context.Context, teamName string) ([]models.RoutingRule, error) {
+	exists, err := s.repo.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "team not found",
+		}
+	}
+	return s.repo.GetRoutingRules(ctx, teamName)
+}
+
+func (s *Service) DeleteRoutingRule(ctx context.Context, id int64) error {
+	deleted, err := s.repo.DeleteRoutingRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "routing rule not found",
+		}
+	}
+	return nil
+}
+
+// SetPullRequestLabels replaces the PR's labels, publishing pr.updated when
+// they change. Order does not count as a change.
+func (s *Service) SetPullRequestLabels(ctx context.Context, prID string, labels []string) (*models.PullRequest, error) {
+	pr, changes, err := s.changeDetails(ctx, prID, "cannot change labels on %s PR", func(pr *models.PullRequest) ([]models.FieldChange, error) {
+		labels := normalizeLabels(labels)
+		if sameMembers(pr.Labels, labels) {
+			return nil, nil
+		}
+		changes := []models.FieldChange{{Field: "labels", Old: pr.Labels, New: labels}}
+		pr.Labels = labels
+		return changes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publishChanges(ctx, pr, changes)
+	return pr, nil
+}
+
+// routedReviewers returns the candidates that the team's routing rules
+// require for the given labels, in user_id order. Rules pointing at users
+// who are not candidates (inactive, or the author) are skipped.
+func (s *Service) routedReviewers(ctx context.Context, repo repository.Storage, teamName string, candidates []models.User, labels []string) ([]string, error) {
+	if len(labels) == 0 {
+		return []string{}, nil
+	}
+
+	rules, err := repo.GetRoutingRules(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	hasLabel := make(map[string]bool, len(labels))
+	for _, l := range labels {
+		hasLabel[l] = true
+	}
+	isCandidate := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		isCandidate[c.UserID] = true
+	}
+
+	seen := make(map[string]bool)
+	reviewers := []string{}
+	for _, rule := range rules {
+		if hasLabel[rule.Label] && isCandidate[rule.UserID] && !seen[rule.UserID] {
+			seen[rule.UserID] = true
+			reviewers = append(reviewers, rule.UserID)
+		}
+	}
+	sort.Strings(reviewers)
+	return reviewers, nil
+}
+
+func normalizeLabel(label string) string {
+	return strings.ToLower(strings.TrimSpace(label))
+}
+
+func normalizeLabels(labels []string) []string {
+	seen := make(map[string]bool, len(labels))
+	result := []string{}
+	for _, l := range labels {
+		l = normalizeLabel(l)
+		if l == "" || seen[l] {
+			continue
+		}
+		seen[l] = true
+		result = append(result, l)
+	}
+	return result
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// WithSchemaVersion sets the database schema version the service is
+// written against. CheckSchema refuses an older schema, and a newer one
+// puts the service in maintenance mode. Without it the schema is not
+// checked.
+func WithSchemaVersion(version int) Option {
+	return func(s *Service) {
+		s.schemaVersion = version
+	}
+}
+
+// GetSchema returns the applied migrations and how they compare with the
+// version the service requires.
+func (s *Service) GetSchema(ctx context.Context) (*models.SchemaStatus, error) {
+	migrations, err := s.repo.GetSchemaMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := &models.SchemaStatus{RequiredVersion: s.schemaVersion, Migrations: migrations}
+	if n := len(migrations); n > 0 {
+		status.CurrentVersion = migrations[n-1].Version
+	}
+	status.ReadOnly = s.schemaVersion > 0 && status.CurrentVersion > s.schemaVersion
+	return status, nil
+}
+
+// CheckSchema fails when the database schema is older than the service
+// requires, as the service would fail on the missing migrations. A newer
+// schema, migrated for the next release, is only logged: the service keeps
+// serving reads until it is replaced.
+func (s *Service) CheckSchema(ctx context.Context) error {
+	if s.schemaVersion == 0 {
+		return nil
+	}
+	status, err := s.GetSchema(ctx)
+	if err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if status.CurrentVersion < s.schemaVersion {
+		return fmt.Errorf("database schema version %d is older than the required %d, apply the migrations first", status.CurrentVersion, s.schemaVersion)
+	}
+	if status.ReadOnly {
+		log.Printf("Database schema version %d is newer than the supported %d, serving reads only", status.CurrentVersion, s.schemaVersion)
+	}
+	return nil
+}
+
+// schemaMaintenance returns the maintenance mode the schema calls for: on
+// while it is newer than the service supports.
+func (s *Service) schemaMaintenance(ctx context.Context) (*models.Maintenance, error) {
+	status, err := s.GetSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !status.ReadOnly {
+		return &models.Maintenance{}, nil
+	}
+	return &models.Maintenance{
+		Enabled: true,
+		Message: fmt.Sprintf("database schema %d is newer than %d this instance supports", status.CurrentVersion, s.schemaVersion),
+	}, nil
+}
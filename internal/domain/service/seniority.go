@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// SetUserSeniority sets the user's seniority level; an empty one clears it.
+func (s *Service) SetUserSeniority(ctx context.Context, userID string, seniority models.Seniority) (*models.User, error) {
+	if seniority != "" && !seniority.Valid() {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "seniority must be junior, middle or senior",
+		}
+	}
+
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "user not found",
+		}
+	}
+
+	user.Seniority = seniority
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// seniors returns the IDs of the senior users among users.
+func seniors(users []models.User) map[string]bool {
+	result := make(map[string]bool)
+	for _, u := range users {
+		if u.Seniority == models.SenioritySenior {
+			result[u.UserID] = true
+		}
+	}
+	return result
+}
+
+// seniorUsers keeps the senior users of users.
+func seniorUsers(users []models.User) []models.User {
+	result := []models.User{}
+	for _, u := range users {
+		if u.Seniority == models.SenioritySenior {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+// hasSenior reports whether any of reviewerIDs other than except is in
+// senior.
+func hasSenior(reviewerIDs []string, senior map[string]bool, except string) bool {
+	for _, id := range reviewerIDs {
+		if id != except && senior[id] {
+			return true
+		}
+	}
+	return false
+}
+
+func noSeniorError() error {
+	return &ServiceError{
+		Code:    models.ErrNoSenior,
+		Message: "team requires a senior reviewer, but no senior candidate is available",
+	}
+}
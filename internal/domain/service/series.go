@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+const (
+	// minSeriesStep and maxSeriesPoints bound the resolution of a series:
+	// steps are widened until the range fits into maxSeriesPoints buckets.
+	minSeriesStep   = time.Minute
+	maxSeriesPoints = 10000
+	// maxAnnotations caps the events one annotation query returns.
+	maxAnnotations = 1000
+)
+
+// GetSeries counts the events of metric in [from, to) in buckets of step,
+// the empty ones included. Steps shorter than a minute, or making more than
+// maxSeriesPoints buckets, are widened.
+func (s *Service) GetSeries(ctx context.Context, metric models.SeriesMetric, from, to time.Time, step time.Duration) ([]models.SeriesPoint, error) {
+	var msg string
+	switch {
+	case !metric.Valid():
+		msg = "unknown metric " + string(metric)
+	case !to.After(from):
+		msg = "range must end after it starts"
+	}
+	if msg != "" {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: msg,
+		}
+	}
+
+	if step < minSeriesStep {
+		step = minSeriesStep
+	}
+	if span := to.Sub(from); span/step >= maxSeriesPoints {
+		step = span/maxSeriesPoints + 1
+	}
+
+	counted, err := s.repo.GetSeries(ctx, metric, from, to, step)
+	if err != nil {
+		return nil, err
+	}
+	points := []models.SeriesPoint{}
+	next := 0
+	for t := from; t.Before(to); t = t.Add(step) {
+		p := models.SeriesPoint{Time: t}
+		if next < len(counted) && !counted[next].Time.After(t) {
+			p.Value = counted[next].Value
+			next++
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// GetAnnotations returns the events of kind in [from, to), merges by
+// default, at most maxAnnotations of them.
+func (s *Service) GetAnnotations(ctx context.Context, kind models.AnnotationKind, from, to time.Time) ([]models.Annotation, error) {
+	if kind == "" {
+		kind = models.AnnotationMerges
+	}
+	var msg string
+	switch {
+	case kind != models.AnnotationMerges && kind != models.AnnotationEscalations:
+		msg = "annotations must be merges or escalations"
+	case !to.After(from):
+		msg = "range must end after it starts"
+	}
+	if msg != "" {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: msg,
+		}
+	}
+	return s.repo.GetAnnotations(ctx, kind, from, to, maxAnnotations)
+}
@@ -2,9 +2,12 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"math/rand"
-	"sort"
+	"math"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/Thorlik/avito_internship/internal/domain/models"
@@ -12,18 +15,116 @@ import (
 )
 
 type Service struct {
-	repo repository.Storage
-	rng  *rand.Rand
+	repo       repository.Storage
+	locker     repository.Locker
+	notifier   repository.Notifier
+	events     repository.EventPublisher
+	clock      Clock
+	rng        Rand
+	strategies map[string]AssignmentStrategy
+	// sizeBuckets weigh open reviews by PR size; see WithSizeBuckets.
+	sizeBuckets []SizeBucket
+	// statisticsMaxAge is how old statistics may be before they are
+	// reported stale; see WithStatisticsMaxAge.
+	statisticsMaxAge time.Duration
+	// flagDefaults apply to feature flags that are not stored; see
+	// WithFeatureFlags.
+	flagDefaults map[string]models.FeatureFlag
+	// undoWindow limits how old a reassignment UndoReassign reverts; see
+	// WithUndoWindow.
+	undoWindow time.Duration
+	// requireRepository makes new PRs name a registered repository; see
+	// WithRequireRepository.
+	requireRepository bool
+	// directory is the source SyncDirectory pulls users from, and
+	// conflictPolicy its treatment of unmanaged users; see WithDirectory.
+	directory      repository.DirectorySource
+	conflictPolicy models.DirectoryConflictPolicy
+	// tracker gets a task for every reviewer through the outbox, given up
+	// on after outboxAttempts failures; see WithIssueTracker.
+	tracker        repository.IssueTracker
+	outboxAttempts int
+	// reviewRequests mirrors the reviewers of PRs on their Git host through
+	// the outbox; see WithReviewRequests.
+	reviewRequests repository.ReviewRequester
+	// webhooks posts recorded webhook deliveries, dead after
+	// webhookAttempts failures; see WithWebhookDeliveries.
+	webhooks        repository.WebhookSender
+	webhookAttempts int
+	// anomalyThresholds tune DetectAnomalies; see WithAnomalyThresholds.
+	anomalyThresholds AnomalyThresholds
+	// locale is the language of teams without one; see WithDefaultLocale.
+	locale models.Locale
+	// schemaVersion is the database schema the service requires; see
+	// WithSchemaVersion.
+	schemaVersion int
+
+	// Runtime-tunable settings, changed through their setters while the
+	// service is in use.
+	requiredApprovals atomic.Int64
+	staleAutoReassign atomic.Bool
+
+	// maintenance caches the maintenance mode; see CurrentMaintenance.
+	maintenance maintenanceCache
 }
 
-func NewService(repo repository.Storage) *Service {
-	return &Service{
-		repo: repo,
-		rng:  rand.New(rand.NewSource(time.Now().UnixNano())),
+type Option func(*Service)
+
+// WithLocker sets the lock used to serialize concurrent changes to the same
+// pull request. Without it no locking is performed.
+func WithLocker(locker repository.Locker) Option {
+	return func(s *Service) {
+		s.locker = locker
 	}
 }
 
+func NewService(repo repository.Storage, opts ...Option) *Service {
+	s := &Service{
+		repo:     repo,
+		locker:   noopLocker{},
+		notifier: noopNotifier{},
+		events:   noopPublisher{},
+		clock:    systemClock{},
+		rng:      NewSeededRand(time.Now().UnixNano()),
+
+		flagDefaults:      defaultFeatureFlags(),
+		undoWindow:        defaultUndoWindow,
+		anomalyThresholds: defaultAnomalyThresholds,
+		locale:            models.LocaleEnglish,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.strategies = defaultStrategies(s.rng, s.reviewSize)
+	return s
+}
+
+type noopLocker struct{}
+
+func (noopLocker) Lock(context.Context, string) (func(), error) {
+	return func() {}, nil
+}
+
+type noopNotifier struct{}
+
+func (noopNotifier) Notify(context.Context, models.Notification) error {
+	return nil
+}
+
 func (s *Service) CreateTeam(ctx context.Context, team *models.Team) (*models.Team, error) {
+	for i := range team.Members {
+		member := &team.Members[i]
+		if member.ReviewWeight == 0 {
+			member.ReviewWeight = defaultReviewWeight
+		}
+		if msg := validateReviewWeight(member.ReviewWeight); msg != "" {
+			return nil, &ServiceError{
+				Code:    models.ErrValidation,
+				Message: fmt.Sprintf("members[%d]: %s", i, msg),
+			}
+		}
+	}
+
 	exists, err := s.repo.TeamExists(ctx, team.TeamName)
 	if err != nil {
 		return nil, err
@@ -36,10 +137,17 @@ func (s *Service) CreateTeam(ctx context.Context, team *models.Team) (*models.Te
 	}
 
 	if err := s.repo.CreateTeam(ctx, team); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			// Lost a race with a concurrent request creating the same team.
+			return nil, &ServiceError{
+				Code:    models.ErrTeamExists,
+				Message: "team_name already exists",
+			}
+		}
 		return nil, err
 	}
 
-	return s.repo.GetTeam(ctx, team.TeamName)
+	return s.repo.GetTeamFromPrimary(ctx, team.TeamName)
 }
 
 func (s *Service) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
@@ -56,6 +164,22 @@ func (s *Service) GetTeam(ctx context.Context, teamName string) (*models.Team, e
 	return team, nil
 }
 
+// ListTeams returns a page of the teams whose name starts with the filter's
+// prefix, with their size, open PRs and main settings.
+func (s *Service) ListTeams(ctx context.Context, filter models.TeamFilter) ([]models.TeamSummary, error) {
+	filter.Limit, filter.Offset = pageBounds(filter.Limit, filter.Offset)
+	teams, err := s.repo.ListTeams(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range teams {
+		if teams[i].Settings.ReviewerCount == 0 {
+			teams[i].Settings.ReviewerCount = reviewersPerPR
+		}
+	}
+	return teams, nil
+}
+
 func (s *Service) SetUserActive(ctx context.Context, userID string, isActive bool) (*models.User, error) {
 	user, err := s.repo.GetUser(ctx, userID)
 	if err != nil {
@@ -76,6 +200,53 @@ func (s *Service) SetUserActive(ctx context.Context, userID string, isActive boo
 	return user, nil
 }
 
+func (s *Service) SetUserMentee(ctx context.Context, userID string, isMentee bool) (*models.User, error) {
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "user not found",
+		}
+	}
+
+	user.IsMentee = isMentee
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *Service) SetUserReviewWeight(ctx context.Context, userID string, weight float64) (*models.User, error) {
+	if msg := validateReviewWeight(weight); msg != "" {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: msg,
+		}
+	}
+
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "user not found",
+		}
+	}
+
+	user.ReviewWeight = weight
+	if err := s.repo.UpdateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
 func (s *Service) GetUserReviews(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
 	user, err := s.repo.GetUser(ctx, userID)
 	if err != nil {
@@ -88,7 +259,44 @@ func (s *Service) GetUserReviews(ctx context.Context, userID string) ([]models.P
 	return s.repo.GetPullRequestsByReviewer(ctx, userID)
 }
 
-func (s *Service) CreatePullRequest(ctx context.Context, prID, prName, authorID string) (*models.PullRequest, error) {
+type CreatePullRequestParams struct {
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	ChangedPaths    []string
+	Labels          []string
+	Repository      string
+	// Priority defaults to NORMAL.
+	Priority models.PullRequestPriority
+	// IsDraft defers reviewer assignment until MarkPullRequestReady.
+	IsDraft bool
+	// LinesChanged weighs the PR in its reviewers' load; see
+	// WithSizeBuckets. Zero means unknown.
+	LinesChanged int
+	FilesChanged int
+	// PreferredReviewers are assigned first; see validatePreferred.
+	PreferredReviewers []string
+}
+
+func (s *Service) CreatePullRequest(ctx context.Context, params CreatePullRequestParams) (*models.PullRequest, error) {
+	prID, authorID := params.PullRequestID, params.AuthorID
+	priority := params.Priority
+	if priority == "" {
+		priority = models.PriorityNormal
+	}
+	if !priority.Valid() {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "priority must be LOW, NORMAL or URGENT",
+		}
+	}
+	if params.LinesChanged < 0 || params.FilesChanged < 0 {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "lines_changed and files_changed must not be negative",
+		}
+	}
+
 	exists, err := s.repo.PullRequestExists(ctx, prID)
 	if err != nil {
 		return nil, err
@@ -110,32 +318,80 @@ func (s *Service) CreatePullRequest(ctx context.Context, prID, prName, authorID
 			Message: "author not found",
 		}
 	}
-
-	teamMembers, err := s.repo.GetUsersByTeam(ctx, author.TeamName)
-	if err != nil {
+	repoName := normalizeRepository(params.Repository)
+	if err := s.checkRepository(ctx, repoName); err != nil {
 		return nil, err
 	}
 
-	reviewers := s.assignReviewers(teamMembers, authorID)
+	var pr *models.PullRequest
+	var assigned []models.AssignmentEvent
+	err = s.repo.WithTeamLock(ctx, author.TeamName, func(repo repository.Storage) error {
+		teamMembers, err := repo.GetUsersByTeam(ctx, author.TeamName)
+		if err != nil {
+			return err
+		}
+		settings, err := pullRequestSettings(ctx, repo, author.TeamName, repoName)
+		if err != nil {
+			return err
+		}
 
-	now := time.Now()
-	pr := &models.PullRequest{
-		PullRequestID:     prID,
-		PullRequestName:   prName,
-		AuthorID:          authorID,
-		Status:            models.StatusOpen,
-		AssignedReviewers: reviewers,
-		CreatedAt:         &now,
-	}
+		now := s.clock.Now()
+		pr = &models.PullRequest{
+			PullRequestID:      prID,
+			PullRequestName:    params.PullRequestName,
+			AuthorID:           authorID,
+			Status:             models.StatusOpen,
+			Priority:           priority,
+			IsDraft:            params.IsDraft,
+			LinesChanged:       params.LinesChanged,
+			FilesChanged:       params.FilesChanged,
+			ChangedPaths:       normalizePaths(params.ChangedPaths),
+			Labels:             normalizeLabels(params.Labels),
+			Repository:         repoName,
+			CreatedAt:          &now,
+			RequestedReviewers: uniqueStrings(params.PreferredReviewers),
+		}
+		if err := validatePreferred(ctx, repo, settings, teamMembers, authorID, pr.RequestedReviewers); err != nil {
+			return err
+		}
+
+		if pr.IsDraft {
+			pr.AssignedReviewers = []string{}
+			pr.ShadowReviewers = []string{}
+		} else if err := s.assignReviewers(ctx, repo, settings, teamMembers, pr); err != nil {
+			return err
+		}
 
-	if err := s.repo.CreatePullRequest(ctx, pr); err != nil {
+		if err := repo.CreatePullRequest(ctx, pr); err != nil {
+			return err
+		}
+		assigned = initialAssignments(pr, pr.CreatedAt)
+		return s.recordAssignments(ctx, repo, assigned)
+	})
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return nil, &ServiceError{
+			Code:    models.ErrPRExists,
+			Message: "PR id already exists",
+		}
+	}
+	if err != nil {
 		return nil, err
 	}
 
+	s.publish(ctx, models.EventPRCreated, pr, "", "", assigned...)
 	return pr, nil
 }
 
+// MergePullRequest marks the PR merged. Closed PRs, PRs on hold, labelled
+// HoldLabel, and PRs short of their required approvals are refused.
 func (s *Service) MergePullRequest(ctx context.Context, prID string) (*models.PullRequest, error) {
+	return s.mergePullRequest(ctx, prID, true)
+}
+
+// mergePullRequest marks the PR merged, refusing it as MergePullRequest
+// does when enforce is set. Merges made on the PR's Git host are recorded
+// without the checks: they have happened already.
+func (s *Service) mergePullRequest(ctx context.Context, prID string, enforce bool) (*models.PullRequest, error) {
 	pr, err := s.repo.GetPullRequest(ctx, prID)
 	if err != nil {
 		return nil, err
@@ -150,118 +406,454 @@ func (s *Service) MergePullRequest(ctx context.Context, prID string) (*models.Pu
 	if pr.Status == models.StatusMerged {
 		return pr, nil
 	}
+	if enforce {
+		if pr.Status == models.StatusClosed {
+			return nil, notOpen(pr, "cannot merge %s PR")
+		}
+		if contains(pr.Labels, HoldLabel) {
+			return nil, &ServiceError{
+				Code:    models.ErrOnHold,
+				Message: "PR is on hold",
+			}
+		}
+		if err := s.checkApprovals(ctx, pr); err != nil {
+			return nil, err
+		}
+	}
 
-	now := time.Now()
+	from := pr.Status
+	now := s.clock.Now()
 	pr.Status = models.StatusMerged
 	pr.MergedAt = &now
 
-	if err := s.repo.UpdatePullRequest(ctx, pr); err != nil {
+	updated, err := s.repo.UpdatePullRequestStatus(ctx, pr, from)
+	if err != nil {
 		return nil, err
 	}
+	if !updated {
+		// Merged, closed or reopened since it was read: decide again.
+		return s.mergePullRequest(ctx, prID, enforce)
+	}
 
+	s.queueReviewTaskEvent(ctx, models.OutboxReviewTaskMerge, pr.PullRequestID, "")
+	s.publish(ctx, models.EventPRMerged, pr, "", "")
 	return pr, nil
 }
 
-func (s *Service) ReassignReviewer(ctx context.Context, prID, oldReviewerID string) (*models.PullRequest, string, error) {
+// ClosePullRequest marks the PR closed without a merge, as when it is
+// declined on its Git host. Merged PRs cannot be closed.
+func (s *Service) ClosePullRequest(ctx context.Context, prID string) (*models.PullRequest, error) {
 	pr, err := s.repo.GetPullRequest(ctx, prID)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 	if pr == nil {
-		return nil, "", &ServiceError{
+		return nil, &ServiceError{
 			Code:    models.ErrNotFound,
 			Message: "PR not found",
 		}
 	}
 
-	if pr.Status == models.StatusMerged {
-		return nil, "", &ServiceError{
-			Code:    models.ErrPRMerged,
-			Message: "cannot reassign on merged PR",
-		}
+	switch pr.Status {
+	case models.StatusClosed:
+		return pr, nil
+	case models.StatusMerged:
+		return nil, notOpen(pr, "cannot close %s PR")
+	}
+
+	from := pr.Status
+	pr.Status = models.StatusClosed
+	updated, err := s.repo.UpdatePullRequestStatus(ctx, pr, from)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return s.ClosePullRequest(ctx, prID)
+	}
+
+	s.publish(ctx, models.EventPRClosed, pr, "", "")
+	return pr, nil
+}
+
+// saveReviewers saves the reviewers of pr, refusing with format, as notOpen
+// does, when the PR was merged or closed since it was read: merges and
+// closes do not take the team lock.
+func saveReviewers(ctx context.Context, repo repository.Storage, pr *models.PullRequest, format string) error {
+	updated, err := repo.UpdatePullRequest(ctx, pr)
+	if err != nil || updated {
+		return err
 	}
+	return noLongerOpen(ctx, repo, pr.PullRequestID, format)
+}
 
-	reviewerIndex := -1
-	for i, reviewerID := range pr.AssignedReviewers {
-		if reviewerID == oldReviewerID {
-			reviewerIndex = i
-			break
+// noLongerOpen refuses with format a change of the PR prID that found it
+// closed or merged when saving.
+func noLongerOpen(ctx context.Context, repo repository.Storage, prID, format string) error {
+	current, err := repo.GetPullRequest(ctx, prID)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "PR not found",
 		}
 	}
-	if reviewerIndex == -1 {
-		return nil, "", &ServiceError{
-			Code:    models.ErrNotAssigned,
-			Message: "reviewer is not assigned to this PR",
+	return notOpen(current, format)
+}
+
+// notOpen refuses to change pr, which is merged or closed. format describes
+// the change with %s for the PR's status, as in "cannot review %s PR".
+func notOpen(pr *models.PullRequest, format string) *ServiceError {
+	if pr.Status == models.StatusClosed {
+		return &ServiceError{
+			Code:    models.ErrPRClosed,
+			Message: fmt.Sprintf(format, "closed"),
 		}
 	}
+	return &ServiceError{
+		Code:    models.ErrPRMerged,
+		Message: fmt.Sprintf(format, "merged"),
+	}
+}
 
-	oldReviewer, err := s.repo.GetUser(ctx, oldReviewerID)
+func (s *Service) ReassignReviewer(ctx context.Context, prID, oldReviewerID string) (*models.PullRequest, string, error) {
+	return s.reassignReviewer(ctx, prID, oldReviewerID, nil)
+}
+
+// reassignReviewer is ReassignReviewer taking the old reviewer from known
+// when present there, so callers replacing several reviewers can load them
+// all in one query. The PR is read and saved under its author's team lock,
+// as other reviewer changes are, so none of them is lost.
+func (s *Service) reassignReviewer(ctx context.Context, prID, oldReviewerID string, known map[string]models.User) (*models.PullRequest, string, error) {
+	unlock, err := s.locker.Lock(ctx, "pr:"+prID)
 	if err != nil {
 		return nil, "", err
 	}
-	if oldReviewer == nil {
+	defer unlock()
+
+	pr, err := s.repo.GetPullRequest(ctx, prID)
+	if err != nil {
+		return nil, "", err
+	}
+	if pr == nil {
 		return nil, "", &ServiceError{
 			Code:    models.ErrNotFound,
-			Message: "old reviewer not found",
+			Message: "PR not found",
 		}
 	}
-
-	teamMembers, err := s.repo.GetUsersByTeam(ctx, oldReviewer.TeamName)
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
 	if err != nil {
 		return nil, "", err
 	}
+	if author == nil {
+		return nil, "", &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "author not found",
+		}
+	}
 
-	newReviewerID, err := s.findReplacement(ctx, teamMembers, pr.AuthorID, pr.AssignedReviewers)
+	oldReviewer, err := s.knownUser(ctx, known, oldReviewerID)
 	if err != nil {
 		return nil, "", err
 	}
+	if oldReviewer == nil {
+		// Deleted reviewers are replaced from the author's team.
+		oldReviewer = author
+	}
+
+	var newReviewerID string
+	var assigned []models.AssignmentEvent
+	err = s.repo.WithTeamLock(ctx, author.TeamName, func(repo repository.Storage) error {
+		pr, err = repo.GetPullRequest(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if pr == nil {
+			return &ServiceError{
+				Code:    models.ErrNotFound,
+				Message: "PR not found",
+			}
+		}
+		if pr.Status != models.StatusOpen {
+			return notOpen(pr, "cannot reassign on %s PR")
+		}
+		reviewerIndex := slices.Index(pr.AssignedReviewers, oldReviewerID)
+		if reviewerIndex == -1 {
+			return &ServiceError{
+				Code:    models.ErrNotAssigned,
+				Message: "reviewer is not assigned to this PR",
+			}
+		}
 
-	pr.AssignedReviewers[reviewerIndex] = newReviewerID
-	if err := s.repo.UpdatePullRequest(ctx, pr); err != nil {
+		teamMembers, err := repo.GetUsersByTeam(ctx, oldReviewer.TeamName)
+		if err != nil {
+			return err
+		}
+		settings, err := effectiveSettings(ctx, repo, oldReviewer.TeamName)
+		if err != nil {
+			return err
+		}
+
+		settings.MaxOpenReviews = reviewLimit(settings, pr)
+		seniorOnly := settings.RequireSenior && !hasSenior(pr.AssignedReviewers, seniors(teamMembers), oldReviewerID)
+		newReviewerID, err = s.findReplacement(ctx, repo, settings, teamMembers, pr.AuthorID, append(pr.AssignedReviewers, pr.ShadowReviewers...), seniorOnly)
+		if err != nil {
+			return err
+		}
+
+		pr.AssignedReviewers[reviewerIndex] = newReviewerID
+		if err := saveReviewers(ctx, repo, pr, "cannot reassign on %s PR"); err != nil {
+			return err
+		}
+		assigned = []models.AssignmentEvent{{
+			PullRequestID:  pr.PullRequestID,
+			UserID:         newReviewerID,
+			Role:           models.RoleReviewer,
+			Action:         models.ActionReassigned,
+			Reason:         models.ReasonReplacement,
+			ReplacedUserID: oldReviewerID,
+		}}
+		if err := s.recordAssignments(ctx, repo, assigned); err != nil {
+			return err
+		}
+
+		history, err := repo.GetAssignmentHistory(ctx, pr.PullRequestID)
+		if err != nil {
+			return err
+		}
+		pr.AssignmentReasons = assignmentReasons(pr, history)
+		return nil
+	})
+	if err != nil {
 		return nil, "", err
 	}
 
+	s.publish(ctx, models.EventReviewerReassigned, pr, oldReviewerID, newReviewerID, assigned...)
 	return pr, newReviewerID, nil
 }
 
-func (s *Service) assignReviewers(teamMembers []models.User, authorID string) []string {
+func (s *Service) knownUser(ctx context.Context, known map[string]models.User, userID string) (*models.User, error) {
+	if user, ok := known[userID]; ok {
+		return &user, nil
+	}
+	return s.repo.GetUser(ctx, userID)
+}
+
+func initialAssignments(pr *models.PullRequest, at *time.Time) []models.AssignmentEvent {
+	events := []models.AssignmentEvent{}
+	for _, userID := range pr.AssignedReviewers {
+		events = append(events, models.AssignmentEvent{
+			PullRequestID: pr.PullRequestID,
+			UserID:        userID,
+			Role:          models.RoleReviewer,
+			Action:        models.ActionAssigned,
+			Reason:        pr.AssignmentReasons[userID],
+			CreatedAt:     at,
+		})
+	}
+	for _, userID := range pr.ShadowReviewers {
+		events = append(events, models.AssignmentEvent{
+			PullRequestID: pr.PullRequestID,
+			UserID:        userID,
+			Role:          models.RoleShadow,
+			Action:        models.ActionAssigned,
+			Reason:        pr.AssignmentReasons[userID],
+			CreatedAt:     at,
+		})
+	}
+	return events
+}
+
+// assignReviewers fills the reviewers of a new PR: the reviewers the author
+// requested come first, then users required by the team's repository rules,
+// a code owner of the changed paths when the repository's CODEOWNERS
+// mapping owns them and users required by the team's label routing rules,
+// and the remaining slots are filled by the assignment strategy. Mentees
+// never take a regular slot; one of them is added as a shadow reviewer when
+// the PR got at least one regular reviewer. Users excluded from the author's
+// PRs by an exclusion rule, or as the author's manager or direct report when
+// the team reviews among peers only, are not considered, nor are users at
+// the team's open review limit unless the PR is URGENT. When the team
+// requires a senior reviewer and none was picked before the strategy runs,
+// the strategy first fills one slot among seniors.
+func (s *Service) assignReviewers(ctx context.Context, repo repository.Storage, settings models.TeamSettings, teamMembers []models.User, pr *models.PullRequest) error {
+	pr.AssignedReviewers = []string{}
+	pr.ShadowReviewers = []string{}
+
+	excluded, err := automaticExclusions(ctx, repo, settings, teamMembers, pr.AuthorID)
+	if err != nil {
+		return err
+	}
+
 	candidates := []models.User{}
-	candidateIDs := []string{}
+	mentees := []models.User{}
 	for _, member := range teamMembers {
-		if member.IsActive && member.UserID != authorID {
+		if !member.IsActive || member.UserID == pr.AuthorID || excluded[member.UserID] {
+			continue
+		}
+		if member.IsMentee {
+			mentees = append(mentees, member)
+		} else {
 			candidates = append(candidates, member)
-			candidateIDs = append(candidateIDs, member.UserID)
 		}
 	}
 
-	if len(candidates) == 0 {
-		return []string{}
+	candidates, err = withinCapacity(ctx, repo, candidates, reviewLimit(settings, pr))
+	if err != nil {
+		return err
 	}
+	if len(candidates) == 0 && len(pr.RequestedReviewers) == 0 {
+		if settings.RequireSenior {
+			return noSeniorError()
+		}
+		return nil
+	}
+
+	reasons := make(map[string]models.AssignmentReason)
+	reviewers := []string{}
+	add := func(decisions []Decision) {
+		for _, d := range decisions {
+			if _, ok := reasons[d.UserID]; !ok {
+				reasons[d.UserID] = d.Reason
+				reviewers = append(reviewers, d.UserID)
+			}
+		}
+	}
+	add(decide(pr.RequestedReviewers, models.ReasonPreferred))
+
+	required, err := s.requiredReviewers(ctx, repo, settings.TeamName, candidates, pr.Repository, reviewers)
+	if err != nil {
+		return err
+	}
+	add(required)
 
-	counts, err := s.repo.GetReviewCounts(context.Background(), candidateIDs)
+	owners, err := s.codeOwnerReviewers(ctx, repo, candidates, pr.Repository, pr.ChangedPaths, reviewers)
 	if err != nil {
-		return s.randomSelection(candidates, 2)
+		return err
 	}
+	add(owners)
 
-	sort.Slice(candidates, func(i, j int) bool {
-		countI := counts[candidates[i].UserID]
-		countJ := counts[candidates[j].UserID]
-		if countI != countJ {
-			return countI < countJ
+	routed, err := s.routedReviewers(ctx, repo, settings.TeamName, candidates, pr.Labels)
+	if err != nil {
+		return err
+	}
+	add(decide(routed, models.ReasonLabelRule))
+
+	if settings.RequireSenior && !hasSenior(reviewers, seniors(teamMembers), "") {
+		picked, err := s.pickReviewers(ctx, repo, settings, seniorUsers(excludeUsers(candidates, reviewers)), 1, pr)
+		if err != nil {
+			return err
 		}
-		return candidates[i].UserID < candidates[j].UserID
-	})
+		if len(picked) == 0 {
+			return noSeniorError()
+		}
+		add(picked)
+	}
 
-	reviewers := []string{}
-	for i := 0; i < len(candidates) && i < 2; i++ {
-		reviewers = append(reviewers, candidates[i].UserID)
+	if len(reviewers) < settings.ReviewerCount {
+		picked, err := s.pickReviewers(ctx, repo, settings, excludeUsers(candidates, reviewers), settings.ReviewerCount-len(reviewers), pr)
+		if err != nil {
+			return err
+		}
+		add(picked)
 	}
+	pr.AssignedReviewers = reviewers
+	pr.AssignmentReasons = reasons
 
-	return reviewers
+	mentees, err = withinCapacity(ctx, repo, mentees, reviewLimit(settings, pr))
+	if err != nil {
+		return err
+	}
+	if len(reviewers) > 0 && len(mentees) > 0 {
+		shadows, err := s.strategies[StrategyLeastLoaded].SelectReviewers(ctx, repo, AssignmentRequest{
+			Candidates: mentees,
+			Count:      1,
+		})
+		if err != nil {
+			return err
+		}
+		for _, d := range shadows {
+			pr.ShadowReviewers = append(pr.ShadowReviewers, d.UserID)
+			reasons[d.UserID] = d.Reason
+		}
+	}
+
+	return nil
 }
 
-func (s *Service) findReplacement(ctx context.Context, teamMembers []models.User, authorID string, currentReviewers []string) (string, error) {
-	excluded := make(map[string]bool)
+// pickReviewers selects count reviewers with the team's strategy. Without
+// one the expertise strategy is used for PRs with changed paths, while
+// FlagExpertiseStrategy is on for the team, and the least loaded strategy
+// otherwise. When the team prefers working hours the
+// strategy first picks among candidates available now and only fills the
+// remaining slots from the others.
+func (s *Service) pickReviewers(ctx context.Context, repo repository.Storage, settings models.TeamSettings, candidates []models.User, count int, pr *models.PullRequest) ([]Decision, error) {
+	if len(candidates) == 0 {
+		return []Decision{}, nil
+	}
+
+	strategy := s.strategies[settings.AssignmentStrategy]
+	if strategy == nil {
+		strategy = s.strategies[StrategyLeastLoaded]
+		if len(pr.ChangedPaths) > 0 {
+			expertise, err := s.featureEnabled(ctx, FlagExpertiseStrategy, settings.TeamName)
+			if err != nil {
+				return nil, err
+			}
+			if expertise {
+				strategy = s.strategies[StrategyExpertise]
+			}
+		}
+	}
+
+	groups := [][]models.User{candidates}
+	if settings.PreferWorkingHours {
+		available, others := splitByAvailability(candidates, s.clock.Now())
+		groups = [][]models.User{available, others}
+	}
+
+	reviewers := []Decision{}
+	for _, group := range groups {
+		if len(group) == 0 || len(reviewers) >= count {
+			continue
+		}
+		picked, err := strategy.SelectReviewers(ctx, repo, AssignmentRequest{
+			TeamName:     settings.TeamName,
+			Candidates:   group,
+			Count:        count - len(reviewers),
+			ChangedPaths: pr.ChangedPaths,
+		})
+		if err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, picked...)
+	}
+	return reviewers, nil
+}
+
+func excludeUsers(users []models.User, excludedIDs []string) []models.User {
+	excluded := make(map[string]bool, len(excludedIDs))
+	for _, id := range excludedIDs {
+		excluded[id] = true
+	}
+	result := []models.User{}
+	for _, u := range users {
+		if !excluded[u.UserID] {
+			result = append(result, u)
+		}
+	}
+	return result
+}
+
+// findReplacement picks the least loaded eligible team member not on the PR
+// yet. With seniorOnly only seniors are eligible, and their absence is
+// reported as ErrNoSenior.
+func (s *Service) findReplacement(ctx context.Context, repo repository.Storage, settings models.TeamSettings, teamMembers []models.User, authorID string, currentReviewers []string, seniorOnly bool) (string, error) {
+	excluded, err := automaticExclusions(ctx, repo, settings, teamMembers, authorID)
+	if err != nil {
+		return "", err
+	}
 	excluded[authorID] = true
 	for _, reviewerID := range currentReviewers {
 		excluded[reviewerID] = true
@@ -269,35 +861,50 @@ func (s *Service) findReplacement(ctx context.Context, teamMembers []models.User
 
 	candidates := []models.User{}
 	for _, member := range teamMembers {
-		if member.IsActive && !excluded[member.UserID] {
+		if member.IsActive && !member.IsMentee && !excluded[member.UserID] {
 			candidates = append(candidates, member)
 		}
 	}
+	if seniorOnly {
+		candidates = seniorUsers(candidates)
+	}
 
 	if len(candidates) == 0 {
+		if seniorOnly {
+			return "", noSeniorError()
+		}
 		return "", &ServiceError{
 			Code:    models.ErrNoCandidate,
 			Message: "no active replacement candidate in team",
 		}
 	}
 
-	candidateIDs := []string{}
-	for _, c := range candidates {
-		candidateIDs = append(candidateIDs, c.UserID)
-	}
-	counts, err := s.repo.GetReviewCounts(ctx, candidateIDs)
+	loads, counts, err := reviewLoads(ctx, repo, userIDs(candidates), s.reviewSize)
 	if err != nil {
+		if ctx.Err() != nil {
+			return "", err
+		}
 		return candidates[s.rng.Intn(len(candidates))].UserID, nil
 	}
 
-	minCount := -1
+	// Candidates available now beat any load when the team prefers working
+	// hours; among equally available ones the least loaded win.
+	now := s.clock.Now()
+	minLoad := -1.0
+	minAvailable := false
 	var selected []models.User
 	for _, candidate := range candidates {
 		count := counts[candidate.UserID]
-		if minCount == -1 || count < minCount {
-			minCount = count
+		if settings.MaxOpenReviews > 0 && count >= settings.MaxOpenReviews {
+			continue
+		}
+		load := weightedLoad(loads[candidate.UserID], candidate)
+		available := !settings.PreferWorkingHours || availableAt(candidate, now)
+		switch {
+		case minLoad < 0 || available && !minAvailable || available == minAvailable && load < minLoad:
+			minLoad, minAvailable = load, available
 			selected = []models.User{candidate}
-		} else if count == minCount {
+		case available == minAvailable && load == minLoad:
 			selected = append(selected, candidate)
 		}
 	}
@@ -306,34 +913,58 @@ func (s *Service) findReplacement(ctx context.Context, teamMembers []models.User
 		return selected[s.rng.Intn(len(selected))].UserID, nil
 	}
 
+	if seniorOnly {
+		return "", noSeniorError()
+	}
 	return "", &ServiceError{
 		Code:    models.ErrNoCandidate,
 		Message: "no active replacement candidate in team",
 	}
 }
 
-func (s *Service) randomSelection(candidates []models.User, maxCount int) []string {
-	if len(candidates) <= maxCount {
-		result := make([]string, len(candidates))
-		for i, c := range candidates {
-			result[i] = c.UserID
-		}
-		return result
+// GetWorkload returns review load for every active user. FairnessIndex is the
+// relative deviation of the user's open reviews from their team average:
+// 0 means an even share, 1 means twice the average, -1 means no open reviews.
+func (s *Service) GetWorkload(ctx context.Context) (*models.Workload, error) {
+	now := s.clock.Now()
+	users, err := s.repo.GetWorkload(ctx, now.AddDate(0, 0, -7), now.AddDate(0, 0, -30))
+	if err != nil {
+		return nil, err
 	}
 
-	s.rng.Shuffle(len(candidates), func(i, j int) {
-		candidates[i], candidates[j] = candidates[j], candidates[i]
-	})
+	openByTeam := make(map[string]int)
+	membersByTeam := make(map[string]int)
+	for _, u := range users {
+		openByTeam[u.TeamName] += u.OpenReviews
+		membersByTeam[u.TeamName]++
+	}
 
-	result := make([]string, maxCount)
-	for i := 0; i < maxCount; i++ {
-		result[i] = candidates[i].UserID
+	for i := range users {
+		avg := float64(openByTeam[users[i].TeamName]) / float64(membersByTeam[users[i].TeamName])
+		if avg > 0 {
+			users[i].FairnessIndex = math.Round((float64(users[i].OpenReviews)-avg)/avg*100) / 100
+		}
 	}
-	return result
+
+	return &models.Workload{Users: users}, nil
 }
 
-func (s *Service) GetStatistics(ctx context.Context) (*models.Statistics, error) {
-	return s.repo.GetStatistics(ctx)
+// normalizePaths trims, strips leading "./" and "/" and deduplicates the
+// changed paths so that expertise lookups compare like with like.
+func normalizePaths(paths []string) []string {
+	seen := make(map[string]bool, len(paths))
+	result := []string{}
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		p = strings.TrimPrefix(p, "./")
+		p = strings.TrimLeft(p, "/")
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		result = append(result, p)
+	}
+	return result
 }
 
 type ServiceError struct {
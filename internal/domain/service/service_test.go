@@ -0,0 +1,2688 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+	"github.com/Thorlik/avito_internship/internal/domain/repository/repositorymock"
+	"github.com/Thorlik/avito_internship/internal/domain/service"
+)
+
+var now = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+func newService(repo repository.Storage, opts ...service.Option) *service.Service {
+	opts = append([]service.Option{
+		service.WithClock(service.NewFixedClock(now)),
+		service.WithRand(service.NewSeededRand(1)),
+	}, opts...)
+	return service.NewService(repo, opts...)
+}
+
+func errorCode(err error) models.ErrorCode {
+	var svcErr *service.ServiceError
+	if errors.As(err, &svcErr) {
+		return svcErr.Code
+	}
+	return ""
+}
+
+// teamStorage serves team "backend" with author u1 and reviewers u2 to u4,
+// whose open reviews have the given sizes in lines.
+func teamStorage(sizes map[string][]int) *repositorymock.StorageMock {
+	users := []models.User{
+		{UserID: "u1", Username: "Alice", TeamName: "backend", IsActive: true, ReviewWeight: 1},
+		{UserID: "u2", Username: "Bob", TeamName: "backend", IsActive: true, ReviewWeight: 1},
+		{UserID: "u3", Username: "Carol", TeamName: "backend", IsActive: true, ReviewWeight: 1},
+		{UserID: "u4", Username: "Dave", TeamName: "backend", IsActive: true, ReviewWeight: 1},
+	}
+	repo := &repositorymock.StorageMock{}
+	repo.PullRequestExistsFunc = func(ctx context.Context, prID string) (bool, error) {
+		return false, nil
+	}
+	repo.GetUserFunc = func(ctx context.Context, userID string) (*models.User, error) {
+		for _, u := range users {
+			if u.UserID == userID {
+				return &u, nil
+			}
+		}
+		return nil, nil
+	}
+	repo.GetUsersByTeamFunc = func(ctx context.Context, teamName string) ([]models.User, error) {
+		return users, nil
+	}
+	repo.WithTeamLockFunc = func(ctx context.Context, teamName string, fn func(repo repository.Storage) error) error {
+		return fn(repo)
+	}
+	repo.GetTeamSettingsFunc = func(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+		return nil, nil
+	}
+	repo.GetRoutingRulesFunc = func(ctx context.Context, teamName string) ([]models.RoutingRule, error) {
+		return nil, nil
+	}
+	repo.GetRepositoryRulesFunc = func(ctx context.Context, teamName string) ([]models.RepositoryRule, error) {
+		return nil, nil
+	}
+	repo.GetCodeOwnersFunc = func(ctx context.Context, repository string) ([]models.CodeOwnersRule, error) {
+		return nil, nil
+	}
+	repo.GetExclusionRulesFunc = func(ctx context.Context, teamName string) ([]models.ExclusionRule, error) {
+		return nil, nil
+	}
+	repo.GetReviewCountsFunc = func(ctx context.Context, userIDs []string) (map[string]int, error) {
+		counts := make(map[string]int)
+		for _, id := range userIDs {
+			counts[id] = len(sizes[id])
+		}
+		return counts, nil
+	}
+	repo.GetReviewSizesFunc = func(ctx context.Context, userIDs []string) (map[string][]int, error) {
+		return sizes, nil
+	}
+	repo.CreatePullRequestFunc = func(ctx context.Context, pr *models.PullRequest) error {
+		return nil
+	}
+	repo.AddAssignmentEventsFunc = func(ctx context.Context, events []models.AssignmentEvent) error {
+		return nil
+	}
+	return repo
+}
+
+func TestCreatePullRequestAssignsLeastLoaded(t *testing.T) {
+	repo := teamStorage(map[string][]int{
+		"u2": {10, 10, 10},
+		"u4": {10},
+	})
+	svc := newService(repo)
+
+	pr, err := svc.CreatePullRequest(context.Background(), service.CreatePullRequestParams{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Add search",
+		AuthorID:        "u1",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+
+	if len(pr.AssignedReviewers) != 2 || pr.AssignedReviewers[0] != "u3" || pr.AssignedReviewers[1] != "u4" {
+		t.Errorf("AssignedReviewers = %v, want [u3 u4]", pr.AssignedReviewers)
+	}
+	if pr.CreatedAt == nil || !pr.CreatedAt.Equal(now) {
+		t.Errorf("CreatedAt = %v, want the injected clock's %v", pr.CreatedAt, now)
+	}
+	if calls := repo.CreatePullRequestCalls(); len(calls) != 1 || calls[0].Pr.PullRequestID != "pr-1" {
+		t.Errorf("CreatePullRequest calls = %+v", calls)
+	}
+	if calls := repo.AddAssignmentEventsCalls(); len(calls) != 1 || len(calls[0].Events) != 2 {
+		t.Errorf("AddAssignmentEvents calls = %+v, want one with 2 events", calls)
+	}
+}
+
+func TestCreatePullRequestWeighsReviewSize(t *testing.T) {
+	// u2 has fewer open reviews than u4, but they are large enough to weigh
+	// more.
+	repo := teamStorage(map[string][]int{
+		"u2": {3000},
+		"u4": {10, 10},
+	})
+	svc := newService(repo, service.WithSizeBuckets([]service.SizeBucket{
+		{MaxLines: 100, Weight: 1},
+		{MaxLines: 2000, Weight: 3},
+		{MaxLines: 5000, Weight: 5},
+	}))
+
+	pr, err := svc.CreatePullRequest(context.Background(), service.CreatePullRequestParams{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Add search",
+		AuthorID:        "u1",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 2 || pr.AssignedReviewers[0] != "u3" || pr.AssignedReviewers[1] != "u4" {
+		t.Errorf("AssignedReviewers = %v, want [u3 u4]", pr.AssignedReviewers)
+	}
+}
+
+func TestCreatePullRequestDraftDefersAssignment(t *testing.T) {
+	repo := teamStorage(nil)
+	svc := newService(repo)
+
+	pr, err := svc.CreatePullRequest(context.Background(), service.CreatePullRequestParams{
+		PullRequestID:   "pr-1",
+		PullRequestName: "WIP",
+		AuthorID:        "u1",
+		IsDraft:         true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	if len(pr.AssignedReviewers) != 0 {
+		t.Errorf("draft AssignedReviewers = %v, want none", pr.AssignedReviewers)
+	}
+	if len(repo.GetReviewSizesCalls()) != 0 {
+		t.Error("draft creation looked up reviewer load")
+	}
+}
+
+func TestCreatePullRequestRepository(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository string
+		wantCount  int
+		wantErr    models.ErrorCode
+	}{
+		{"component of registered", "API/handlers", 3, ""},
+		{"unregistered", "web", 0, models.ErrInvalidReference},
+		{"missing", "", 0, models.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := teamStorage(nil)
+			repo.GetRepositoryFunc = func(ctx context.Context, name string) (*models.Repository, error) {
+				if name != "api" {
+					return nil, nil
+				}
+				return &models.Repository{Name: "api", Settings: models.RepositorySettings{ReviewerCount: 3}}, nil
+			}
+			svc := newService(repo, service.WithRequireRepository(true))
+
+			pr, err := svc.CreatePullRequest(context.Background(), service.CreatePullRequestParams{
+				PullRequestID:   "pr-1",
+				PullRequestName: "Add search",
+				AuthorID:        "u1",
+				Repository:      tt.repository,
+			})
+			if code := errorCode(err); code != tt.wantErr {
+				t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantErr)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if pr.Repository != "api/handlers" || len(pr.AssignedReviewers) != tt.wantCount {
+				t.Errorf("repository = %q with reviewers %v, want api/handlers with %d", pr.Repository, pr.AssignedReviewers, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestCreatePullRequestCodeOwners(t *testing.T) {
+	// u4 is the most loaded, so only the CODEOWNERS mapping gets them on
+	// the PR.
+	repo := teamStorage(map[string][]int{
+		"u4": {10, 10, 10},
+	})
+	repo.GetCodeOwnersFunc = func(ctx context.Context, repository string) ([]models.CodeOwnersRule, error) {
+		if repository != "api" {
+			return nil, nil
+		}
+		return []models.CodeOwnersRule{
+			{Line: 1, Pattern: "*", Owners: []string{"u1"}},
+			{Line: 2, Pattern: "/docs/", Owners: []string{"u4"}},
+		}, nil
+	}
+	repo.GetRepositoryFunc = func(ctx context.Context, name string) (*models.Repository, error) {
+		return nil, nil
+	}
+	repo.GetFeatureFlagsFunc = func(ctx context.Context) ([]models.FeatureFlag, error) {
+		return nil, nil
+	}
+	repo.GetPathExpertiseFunc = func(ctx context.Context, paths []string, userIDs []string) (map[string]int, error) {
+		return nil, nil
+	}
+	svc := newService(repo)
+
+	pr, err := svc.CreatePullRequest(context.Background(), service.CreatePullRequestParams{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Document search",
+		AuthorID:        "u1",
+		Repository:      "api/handlers",
+		ChangedPaths:    []string{"docs/search.md"},
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+
+	if len(pr.AssignedReviewers) != 2 || pr.AssignedReviewers[0] != "u4" {
+		t.Errorf("AssignedReviewers = %v, want u4 first", pr.AssignedReviewers)
+	}
+	if reason := pr.AssignmentReasons["u4"]; reason != models.ReasonCodeOwner {
+		t.Errorf("u4 reason = %q, want %q", reason, models.ReasonCodeOwner)
+	}
+}
+
+func TestPreviewCodeOwners(t *testing.T) {
+	content := `# Default owners
+*            @u2
+*.go         u3
+/docs/       @u4 # docs team
+internal/**/testdata/  u2 u3
+cmd/*        u4
+/vendor/
+`
+	tests := []struct {
+		path    string
+		wantPat string
+		want    []string
+	}{
+		{"README.md", "*", []string{"u2"}},
+		{"internal/app/main.go", "*.go", []string{"u3"}},
+		{"docs/guide/intro.md", "/docs/", []string{"u4"}},
+		{"api/docs/intro.md", "*", []string{"u2"}},
+		{"internal/app/testdata/case.json", "internal/**/testdata/", []string{"u2", "u3"}},
+		{"cmd/README", "cmd/*", []string{"u4"}},
+		{"cmd/server/main.go", "*.go", []string{"u3"}},
+		{"vendor/lib/lib.go", "/vendor/", []string{}},
+	}
+
+	repo := teamStorage(nil)
+	repo.GetRepositoryFunc = func(ctx context.Context, name string) (*models.Repository, error) {
+		return &models.Repository{Name: name}, nil
+	}
+	repo.GetUsersByIDsFunc = func(ctx context.Context, userIDs []string) ([]models.User, error) {
+		users := []models.User{}
+		for _, id := range userIDs {
+			users = append(users, models.User{UserID: id})
+		}
+		return users, nil
+	}
+	svc := newService(repo)
+
+	paths := []string{}
+	for _, tt := range tests {
+		paths = append(paths, tt.path)
+	}
+	preview, err := svc.PreviewCodeOwners(context.Background(), "api", content, paths)
+	if err != nil {
+		t.Fatalf("PreviewCodeOwners: %v", err)
+	}
+	if len(preview.Paths) != len(tests) {
+		t.Fatalf("got %d paths, want %d", len(preview.Paths), len(tests))
+	}
+	for i, tt := range tests {
+		got := preview.Paths[i]
+		if got.Pattern != tt.wantPat || !reflect.DeepEqual(got.Owners, tt.want) {
+			t.Errorf("%s: owned by %q %v, want %q %v", tt.path, got.Pattern, got.Owners, tt.wantPat, tt.want)
+		}
+	}
+	if want := []string{"u2", "u3", "u4"}; !reflect.DeepEqual(preview.Owners, want) {
+		t.Errorf("Owners = %v, want %v", preview.Owners, want)
+	}
+}
+
+func TestSetCodeOwnersValidation(t *testing.T) {
+	repo := teamStorage(nil)
+	repo.GetRepositoryFunc = func(ctx context.Context, name string) (*models.Repository, error) {
+		return &models.Repository{Name: name}, nil
+	}
+	repo.GetUsersByIDsFunc = func(ctx context.Context, userIDs []string) ([]models.User, error) {
+		return []models.User{{UserID: "u2"}}, nil
+	}
+	svc := newService(repo)
+
+	_, err := svc.SetCodeOwners(context.Background(), "api", "*.go @u2\n!vendor/ u2\n/docs/ @u9\n")
+	if code := errorCode(err); code != models.ErrValidation {
+		t.Fatalf("error code = %q (%v), want %q", code, err, models.ErrValidation)
+	}
+	for _, want := range []string{"line 2: negated", "line 3: unknown owner u9"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	if calls := repo.SetCodeOwnersCalls(); len(calls) != 0 {
+		t.Errorf("SetCodeOwners calls = %+v, want none", calls)
+	}
+}
+
+func TestCreatePullRequestRequiresSenior(t *testing.T) {
+	tests := []struct {
+		name    string
+		senior  string
+		want    []string
+		wantErr models.ErrorCode
+	}{
+		// u4 is the most loaded, but the only senior.
+		{"senior picked first", "u4", []string{"u4", "u2"}, ""},
+		{"no senior", "", nil, models.ErrNoSenior},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := teamStorage(map[string][]int{
+				"u3": {10},
+				"u4": {10, 10},
+			})
+			members := repo.GetUsersByTeamFunc
+			repo.GetUsersByTeamFunc = func(ctx context.Context, teamName string) ([]models.User, error) {
+				users, err := members(ctx, teamName)
+				for i := range users {
+					if users[i].UserID == tt.senior {
+						users[i].Seniority = models.SenioritySenior
+					}
+				}
+				return users, err
+			}
+			repo.GetTeamSettingsFunc = func(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+				return &models.TeamSettings{TeamName: teamName, RequireSenior: true}, nil
+			}
+
+			pr, err := newService(repo).CreatePullRequest(context.Background(), service.CreatePullRequestParams{
+				PullRequestID:   "pr-1",
+				PullRequestName: "Add search",
+				AuthorID:        "u1",
+			})
+			if code := errorCode(err); code != tt.wantErr {
+				t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantErr)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if !reflect.DeepEqual(pr.AssignedReviewers, tt.want) {
+				t.Errorf("AssignedReviewers = %v, want %v", pr.AssignedReviewers, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreatePullRequestPeerReviewOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		peerOnly bool
+		want     []string
+	}{
+		{"any reviewer", false, []string{"u2", "u3"}},
+		// u2 manages the author and u3 reports to them; only u4 is left.
+		{"peers only", true, []string{"u4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := teamStorage(map[string][]int{
+				"u4": {10, 10},
+			})
+			members := repo.GetUsersByTeamFunc
+			repo.GetUsersByTeamFunc = func(ctx context.Context, teamName string) ([]models.User, error) {
+				users, err := members(ctx, teamName)
+				managers := map[string]string{"u1": "u2", "u3": "u1"}
+				for i := range users {
+					users[i].ManagerID = managers[users[i].UserID]
+				}
+				return users, err
+			}
+			repo.GetTeamSettingsFunc = func(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+				return &models.TeamSettings{TeamName: teamName, PeerReviewOnly: tt.peerOnly}, nil
+			}
+
+			pr, err := newService(repo).CreatePullRequest(context.Background(), service.CreatePullRequestParams{
+				PullRequestID:   "pr-1",
+				PullRequestName: "Add search",
+				AuthorID:        "u1",
+			})
+			if err != nil {
+				t.Fatalf("CreatePullRequest: %v", err)
+			}
+			if !reflect.DeepEqual(pr.AssignedReviewers, tt.want) {
+				t.Errorf("AssignedReviewers = %v, want %v", pr.AssignedReviewers, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetUserManagerRejectsCycle(t *testing.T) {
+	repo := teamStorage(nil)
+	users := repo.GetUserFunc
+	repo.GetUserFunc = func(ctx context.Context, userID string) (*models.User, error) {
+		user, err := users(ctx, userID)
+		// u1 manages u2, who manages u3.
+		if user != nil && user.UserID == "u2" {
+			user.ManagerID = "u1"
+		}
+		if user != nil && user.UserID == "u3" {
+			user.ManagerID = "u2"
+		}
+		return user, err
+	}
+	svc := newService(repo)
+
+	for managerID, want := range map[string]models.ErrorCode{
+		"u1":    models.ErrValidation,
+		"u3":    models.ErrValidation,
+		"ghost": models.ErrNotFound,
+	} {
+		if _, err := svc.SetUserManager(context.Background(), "u1", managerID); errorCode(err) != want {
+			t.Errorf("SetUserManager(u1, %s) error = %v, want %s", managerID, err, want)
+		}
+	}
+}
+
+func TestSimulateAssignmentAppliesOverridesWithoutWriting(t *testing.T) {
+	repo := teamStorage(map[string][]int{
+		"u2": {10, 10},
+	})
+	svc := newService(repo)
+
+	count := 1
+	sim, err := svc.SimulateAssignment(context.Background(), service.SimulateAssignmentParams{
+		AuthorID:      "u1",
+		ReviewerCount: &count,
+		Excluded:      []string{"u3"},
+	})
+	if err != nil {
+		t.Fatalf("SimulateAssignment: %v", err)
+	}
+	if len(sim.AssignedReviewers) != 1 || sim.AssignedReviewers[0] != "u4" {
+		t.Errorf("AssignedReviewers = %v, want [u4]", sim.AssignedReviewers)
+	}
+	if sim.AssignmentReasons["u4"] != models.ReasonLeastLoaded {
+		t.Errorf("AssignmentReasons = %v, want u4 least_loaded", sim.AssignmentReasons)
+	}
+	if sim.Settings.ReviewerCount != 1 || sim.OpenReviews["u2"] != 2 {
+		t.Errorf("simulation = %+v, want reviewer_count 1 and u2 with 2 open reviews", sim)
+	}
+	if len(repo.CreatePullRequestCalls()) != 0 || len(repo.AddAssignmentEventsCalls()) != 0 {
+		t.Error("simulation persisted the PR")
+	}
+
+	strategy := "nope"
+	_, err = svc.SimulateAssignment(context.Background(), service.SimulateAssignmentParams{
+		AuthorID:           "u1",
+		AssignmentStrategy: &strategy,
+	})
+	if code := errorCode(err); code != models.ErrValidation {
+		t.Errorf("unknown strategy error code = %q (%v), want %q", code, err, models.ErrValidation)
+	}
+}
+
+func TestSuggestReviewersRanksWithoutAssigning(t *testing.T) {
+	repo := teamStorage(map[string][]int{
+		"u3": {10},
+		"u4": {10, 10},
+	})
+	repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+		return &models.PullRequest{
+			PullRequestID:     "pr-1",
+			AuthorID:          "u1",
+			Status:            models.StatusOpen,
+			Priority:          models.PriorityNormal,
+			Repository:        "svc",
+			ChangedPaths:      []string{"api/search.go"},
+			AssignedReviewers: []string{"u2"},
+		}, nil
+	}
+	repo.GetRepositoryFunc = func(ctx context.Context, name string) (*models.Repository, error) {
+		return nil, nil
+	}
+	repo.GetCodeOwnersFunc = func(ctx context.Context, repository string) ([]models.CodeOwnersRule, error) {
+		return []models.CodeOwnersRule{{Line: 1, Pattern: "/api/", Owners: []string{"u4"}}}, nil
+	}
+	repo.GetPathExpertiseFunc = func(ctx context.Context, paths []string, userIDs []string) (map[string]int, error) {
+		return map[string]int{"u3": 5}, nil
+	}
+	svc := newService(repo)
+
+	got, err := svc.SuggestReviewers(context.Background(), service.SuggestReviewersParams{PullRequestID: "pr-1"})
+	if err != nil {
+		t.Fatalf("SuggestReviewers: %v", err)
+	}
+	want := []models.ReviewerSuggestion{
+		{UserID: "u4", Username: "Dave", Score: 3.333, CodeOwner: true, Load: 2, OpenReviews: 2, Reasons: []models.AssignmentReason{models.ReasonCodeOwner}},
+		{UserID: "u3", Username: "Carol", Score: 2.5, Expertise: 5, Load: 1, OpenReviews: 1, Reasons: []models.AssignmentReason{models.ReasonExpertise, models.ReasonLeastLoaded}},
+	}
+	if !reflect.DeepEqual(got.Suggestions, want) {
+		t.Errorf("Suggestions = %+v, want %+v", got.Suggestions, want)
+	}
+	if len(repo.UpdatePullRequestCalls()) != 0 || len(repo.AddAssignmentEventsCalls()) != 0 {
+		t.Error("suggestions changed the PR")
+	}
+
+	_, err = svc.SuggestReviewers(context.Background(), service.SuggestReviewersParams{PullRequestID: "pr-1", AuthorID: "u1"})
+	if code := errorCode(err); code != models.ErrValidation {
+		t.Errorf("both PR and author error code = %q (%v), want %q", code, err, models.ErrValidation)
+	}
+}
+
+func TestCreatePullRequestUnknownAuthor(t *testing.T) {
+	svc := newService(teamStorage(nil))
+
+	_, err := svc.CreatePullRequest(context.Background(), service.CreatePullRequestParams{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Add search",
+		AuthorID:        "ghost",
+	})
+	if code := errorCode(err); code != models.ErrNotFound {
+		t.Errorf("error code = %q (%v), want %q", code, err, models.ErrNotFound)
+	}
+}
+
+func TestRunBotCommand(t *testing.T) {
+	pr := &models.PullRequest{
+		PullRequestID:     "pr-1",
+		AuthorID:          "u1",
+		Status:            models.StatusOpen,
+		AssignedReviewers: []string{"u2"},
+		Labels:            []string{},
+	}
+	logins := map[string]string{"alice": "u1", "bob": "u2", "carol": "u3"}
+	repo := &repositorymock.StorageMock{
+		GetUserFunc: func(ctx context.Context, userID string) (*models.User, error) {
+			return &models.User{UserID: userID, IsActive: true}, nil
+		},
+		FindUserByIdentityFunc: func(ctx context.Context, provider models.IdentityProvider, externalID string) (*models.User, error) {
+			if id, ok := logins[externalID]; ok {
+				return &models.User{UserID: id}, nil
+			}
+			return nil, nil
+		},
+		GetUserIdentitiesFunc: func(ctx context.Context, userIDs []string) ([]models.UserIdentity, error) {
+			identities := []models.UserIdentity{}
+			for login, id := range logins {
+				if id == userIDs[0] {
+					identities = append(identities, models.UserIdentity{UserID: id, Provider: models.ProviderGitHub, ExternalID: login})
+				}
+			}
+			return identities, nil
+		},
+		GetPullRequestFunc: func(ctx context.Context, prID string) (*models.PullRequest, error) {
+			copied := *pr
+			return &copied, nil
+		},
+		UpdatePullRequestFunc: func(ctx context.Context, updated *models.PullRequest) (bool, error) {
+			pr = updated
+			return true, nil
+		},
+		UpdatePullRequestDetailsFunc: func(ctx context.Context, updated *models.PullRequest) (bool, error) {
+			pr = updated
+			return true, nil
+		},
+		CreateReviewFunc: func(ctx context.Context, review *models.Review) error {
+			pr.Reviews = append(pr.Reviews, *review)
+			return nil
+		},
+	}
+	repo.WithTeamLockFunc = func(ctx context.Context, teamName string, fn func(repo repository.Storage) error) error {
+		return fn(repo)
+	}
+	svc := newService(repo)
+	run := func(actor, command string) (*models.BotReply, error) {
+		return svc.RunBotCommand(context.Background(), service.BotCommandParams{
+			PullRequestID: "pr-1",
+			Provider:      models.ProviderGitHub,
+			Actor:         actor,
+			Text:          command,
+		})
+	}
+
+	reply, err := run("@alice", "Not ready yet.\n/HOLD")
+	if err != nil {
+		t.Fatalf("/hold: %v", err)
+	}
+	if want := "@alice put PR pr-1 on hold; it cannot be merged until /unhold."; reply.Reply != want {
+		t.Errorf("/hold reply = %q, want %q", reply.Reply, want)
+	}
+	if _, err := svc.MergePullRequest(context.Background(), "pr-1"); errorCode(err) != models.ErrOnHold {
+		t.Errorf("merge on hold error = %v, want %q", err, models.ErrOnHold)
+	}
+
+	if _, err := run("carol", "/unhold"); errorCode(err) != models.ErrForbidden {
+		t.Errorf("/unhold by outsider error = %v, want %q", err, models.ErrForbidden)
+	}
+	if _, err := run("carol", "/reassign @bob"); errorCode(err) != models.ErrForbidden {
+		t.Errorf("/reassign by outsider error = %v, want %q", err, models.ErrForbidden)
+	}
+	if _, err := run("alice", "/lgtm"); errorCode(err) != models.ErrValidation {
+		t.Errorf("unknown command error = %v, want %q", err, models.ErrValidation)
+	}
+
+	reply, err = run("bob", "/approve looks good")
+	if err != nil {
+		t.Fatalf("/approve: %v", err)
+	}
+	if want := "@bob approved PR pr-1."; reply.Reply != want {
+		t.Errorf("/approve reply = %q, want %q", reply.Reply, want)
+	}
+	if len(pr.Reviews) != 1 || pr.Reviews[0].ReviewerID != "u2" || pr.Reviews[0].Comment != "looks good" {
+		t.Errorf("reviews = %+v, want an approval by u2", pr.Reviews)
+	}
+}
+
+func TestMergePullRequest(t *testing.T) {
+	pr := &models.PullRequest{PullRequestID: "pr-1", Status: models.StatusOpen}
+	repo := &repositorymock.StorageMock{
+		GetPullRequestFunc: func(ctx context.Context, prID string) (*models.PullRequest, error) {
+			return pr, nil
+		},
+		UpdatePullRequestStatusFunc: func(ctx context.Context, pr *models.PullRequest, from models.PullRequestStatus) (bool, error) {
+			return from == models.StatusOpen, nil
+		},
+	}
+	svc := newService(repo)
+
+	merged, err := svc.MergePullRequest(context.Background(), "pr-1")
+	if err != nil {
+		t.Fatalf("MergePullRequest: %v", err)
+	}
+	if merged.Status != models.StatusMerged || merged.MergedAt == nil || !merged.MergedAt.Equal(now) {
+		t.Errorf("merged PR = %+v, want MERGED at %v", merged, now)
+	}
+
+	// Merging again is a no-op.
+	if _, err := svc.MergePullRequest(context.Background(), "pr-1"); err != nil {
+		t.Fatalf("MergePullRequest again: %v", err)
+	}
+	if calls := len(repo.UpdatePullRequestStatusCalls()); calls != 1 {
+		t.Errorf("UpdatePullRequestStatus called %d times, want 1", calls)
+	}
+}
+
+func TestMergeAfterConcurrentClose(t *testing.T) {
+	status := models.StatusOpen
+	repo := &repositorymock.StorageMock{
+		GetPullRequestFunc: func(ctx context.Context, prID string) (*models.PullRequest, error) {
+			return &models.PullRequest{PullRequestID: "pr-1", Status: status, AssignedReviewers: []string{"u2"}}, nil
+		},
+		UpdatePullRequestStatusFunc: func(ctx context.Context, pr *models.PullRequest, from models.PullRequestStatus) (bool, error) {
+			// Closed between the merge's read and its save.
+			status = models.StatusClosed
+			return from == status, nil
+		},
+	}
+
+	_, err := newService(repo).MergePullRequest(context.Background(), "pr-1")
+	if errorCode(err) != models.ErrPRClosed {
+		t.Errorf("MergePullRequest = %v, want PR_CLOSED", err)
+	}
+	if status != models.StatusClosed {
+		t.Errorf("status = %s, want the close kept", status)
+	}
+}
+
+func TestReviewerSaveOfClosedPR(t *testing.T) {
+	// The PR is closed after it is read under the lock; the reviewer save
+	// finds it no longer open.
+	repo := teamStorage(nil)
+	status := models.StatusOpen
+	repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+		return &models.PullRequest{PullRequestID: "pr-1", AuthorID: "u1", Status: status,
+			AssignedReviewers: []string{"u2"}, ShadowReviewers: []string{}}, nil
+	}
+	repo.UpdatePullRequestFunc = func(ctx context.Context, pr *models.PullRequest) (bool, error) {
+		status = models.StatusClosed
+		return false, nil
+	}
+
+	_, err := newService(repo).AddReviewer(context.Background(), "pr-1", "u3")
+	if errorCode(err) != models.ErrPRClosed {
+		t.Errorf("AddReviewer = %v, want PR_CLOSED", err)
+	}
+	if n := len(repo.AddAssignmentEventsCalls()); n != 0 {
+		t.Errorf("history recorded %d times for an unsaved change", n)
+	}
+}
+
+func TestMergePullRequestApprovalGatingFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   []models.FeatureFlag
+		wantErr models.ErrorCode
+	}{
+		{"default", nil, models.ErrNotApproved},
+		{"other team", []models.FeatureFlag{{Name: service.FlagApprovalGating, Enabled: true, Teams: []string{"frontend"}}}, ""},
+		{"listed team", []models.FeatureFlag{{Name: service.FlagApprovalGating, Enabled: true, Teams: []string{"backend"}}}, models.ErrNotApproved},
+		{"disabled", []models.FeatureFlag{{Name: service.FlagApprovalGating, Teams: []string{"backend"}, Percentage: 100}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := teamStorage(nil)
+			repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+				return &models.PullRequest{PullRequestID: "pr-1", AuthorID: "u1", Status: models.StatusOpen, AssignedReviewers: []string{"u2"}}, nil
+			}
+			repo.UpdatePullRequestStatusFunc = func(ctx context.Context, pr *models.PullRequest, from models.PullRequestStatus) (bool, error) {
+				return true, nil
+			}
+			repo.GetFeatureFlagsFunc = func(ctx context.Context) ([]models.FeatureFlag, error) {
+				return tt.flags, nil
+			}
+			svc := newService(repo, service.WithRequiredApprovals(1))
+
+			_, err := svc.MergePullRequest(context.Background(), "pr-1")
+			if code := errorCode(err); code != tt.wantErr {
+				t.Errorf("error code = %q (%v), want %q", code, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUndoReassign(t *testing.T) {
+	recent, old := now.Add(-10*time.Minute), now.Add(-2*time.Hour)
+	reassigned := func(at time.Time, reason models.AssignmentReason, userID, replaced string) models.AssignmentEvent {
+		return models.AssignmentEvent{PullRequestID: "pr-1", UserID: userID, Role: models.RoleReviewer,
+			Action: models.ActionReassigned, Reason: reason, ReplacedUserID: replaced, CreatedAt: &at}
+	}
+	tests := []struct {
+		name    string
+		history []models.AssignmentEvent
+		wantErr models.ErrorCode
+	}{
+		{"recent", []models.AssignmentEvent{reassigned(recent, models.ReasonReplacement, "u4", "u2")}, ""},
+		{"expired", []models.AssignmentEvent{reassigned(old, models.ReasonReplacement, "u4", "u2")}, models.ErrUndoExpired},
+		{"already undone", []models.AssignmentEvent{
+			reassigned(recent, models.ReasonReplacement, "u2", "u4"),
+			reassigned(recent, models.ReasonUndo, "u4", "u2"),
+		}, models.ErrNotFound},
+		{"none", nil, models.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := teamStorage(nil)
+			repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+				return &models.PullRequest{PullRequestID: "pr-1", AuthorID: "u1", Status: models.StatusOpen,
+					AssignedReviewers: []string{"u3", "u4"}, ShadowReviewers: []string{}}, nil
+			}
+			repo.GetAssignmentHistoryFunc = func(ctx context.Context, prID string) ([]models.AssignmentEvent, error) {
+				return tt.history, nil
+			}
+			repo.UpdatePullRequestFunc = func(ctx context.Context, pr *models.PullRequest) (bool, error) {
+				return true, nil
+			}
+
+			pr, undo, err := newService(repo).UndoReassign(context.Background(), "pr-1")
+			if code := errorCode(err); code != tt.wantErr {
+				t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantErr)
+			}
+			if tt.wantErr != "" {
+				if len(repo.UpdatePullRequestCalls()) != 0 {
+					t.Error("failed undo updated the PR")
+				}
+				return
+			}
+			if pr.AssignedReviewers[1] != "u2" {
+				t.Errorf("AssignedReviewers = %v, want u2 back in place of u4", pr.AssignedReviewers)
+			}
+			if undo.UserID != "u2" || undo.ReplacedUserID != "u4" || undo.Reason != models.ReasonUndo {
+				t.Errorf("undo event = %+v", undo)
+			}
+		})
+	}
+}
+
+func TestManualReviewers(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(svc *service.Service) (*models.PullRequest, error)
+		want    []string
+		action  models.AssignmentAction
+		wantErr models.ErrorCode
+	}{
+		{"add", func(svc *service.Service) (*models.PullRequest, error) {
+			return svc.AddReviewer(context.Background(), "pr-1", "u4")
+		}, []string{"u2", "u4"}, models.ActionAdded, ""},
+		{"add author", func(svc *service.Service) (*models.PullRequest, error) {
+			return svc.AddReviewer(context.Background(), "pr-1", "u1")
+		}, nil, "", models.ErrValidation},
+		{"add assigned", func(svc *service.Service) (*models.PullRequest, error) {
+			return svc.AddReviewer(context.Background(), "pr-1", "u2")
+		}, nil, "", models.ErrAlreadyExists},
+		{"add unknown", func(svc *service.Service) (*models.PullRequest, error) {
+			return svc.AddReviewer(context.Background(), "pr-1", "u9")
+		}, nil, "", models.ErrValidation},
+		{"remove", func(svc *service.Service) (*models.PullRequest, error) {
+			return svc.RemoveReviewer(context.Background(), "pr-1", "u2")
+		}, []string{}, models.ActionRemoved, ""},
+		{"remove unassigned", func(svc *service.Service) (*models.PullRequest, error) {
+			return svc.RemoveReviewer(context.Background(), "pr-1", "u3")
+		}, nil, "", models.ErrNotAssigned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := teamStorage(nil)
+			repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+				return &models.PullRequest{PullRequestID: "pr-1", AuthorID: "u1", Status: models.StatusOpen,
+					AssignedReviewers: []string{"u2"}, ShadowReviewers: []string{}}, nil
+			}
+			repo.GetAssignmentHistoryFunc = func(ctx context.Context, prID string) ([]models.AssignmentEvent, error) {
+				return nil, nil
+			}
+			repo.UpdatePullRequestFunc = func(ctx context.Context, pr *models.PullRequest) (bool, error) {
+				return true, nil
+			}
+
+			pr, err := tt.change(newService(repo))
+			if code := errorCode(err); code != tt.wantErr {
+				t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantErr)
+			}
+			if tt.wantErr != "" {
+				if len(repo.UpdatePullRequestCalls()) != 0 {
+					t.Error("rejected change updated the PR")
+				}
+				return
+			}
+			if !reflect.DeepEqual(pr.AssignedReviewers, tt.want) {
+				t.Errorf("AssignedReviewers = %v, want %v", pr.AssignedReviewers, tt.want)
+			}
+			events := repo.AddAssignmentEventsCalls()[0].Events
+			if events[0].Action != tt.action || events[0].Reason != models.ReasonManual {
+				t.Errorf("history event = %+v", events[0])
+			}
+		})
+	}
+}
+
+func TestSwapReviewers(t *testing.T) {
+	tests := []struct {
+		name      string
+		otherUser string
+		wantErr   models.ErrorCode
+	}{
+		{"swap", "u3", ""},
+		{"not assigned", "u4", models.ErrNotAssigned},
+		{"onto own PR", "u1", models.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := teamStorage(nil)
+			repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+				if prID == "pr-1" {
+					return &models.PullRequest{PullRequestID: "pr-1", AuthorID: "u1", Status: models.StatusOpen,
+						AssignedReviewers: []string{"u2"}, ShadowReviewers: []string{}}, nil
+				}
+				return &models.PullRequest{PullRequestID: "pr-2", AuthorID: "u4", Status: models.StatusOpen,
+					AssignedReviewers: []string{"u3", "u1"}, ShadowReviewers: []string{}}, nil
+			}
+			repo.GetAssignmentHistoryFunc = func(ctx context.Context, prID string) ([]models.AssignmentEvent, error) {
+				return nil, nil
+			}
+			repo.UpdatePullRequestFunc = func(ctx context.Context, pr *models.PullRequest) (bool, error) {
+				return true, nil
+			}
+
+			pr, other, err := newService(repo).SwapReviewers(context.Background(), service.SwapReviewersParams{
+				PullRequestID:      "pr-1",
+				UserID:             "u2",
+				OtherPullRequestID: "pr-2",
+				OtherUserID:        tt.otherUser,
+			})
+			if code := errorCode(err); code != tt.wantErr {
+				t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantErr)
+			}
+			if tt.wantErr != "" {
+				if len(repo.UpdatePullRequestCalls()) != 0 {
+					t.Error("failed swap updated a PR")
+				}
+				return
+			}
+			if pr.AssignedReviewers[0] != "u3" || other.AssignedReviewers[0] != "u2" {
+				t.Errorf("reviewers = %v and %v, want u3 on pr-1 and u2 on pr-2", pr.AssignedReviewers, other.AssignedReviewers)
+			}
+			events := repo.AddAssignmentEventsCalls()[0].Events
+			if len(events) != 2 || events[0].Reason != models.ReasonSwap || events[1].ReplacedUserID != "u3" {
+				t.Errorf("history events = %+v", events)
+			}
+		})
+	}
+}
+
+func TestReplayEvents(t *testing.T) {
+	prs := map[string]*models.PullRequest{
+		"pr-1": {PullRequestID: "pr-1", AuthorID: "u1", AssignedReviewers: []string{"u3"}, ShadowReviewers: []string{}},
+		"pr-2": {PullRequestID: "pr-2", AuthorID: "u1", AssignedReviewers: []string{"u2"}, ShadowReviewers: []string{}},
+	}
+	logged := map[string][]models.Event{
+		"pr-1": {
+			{Type: models.EventPRCreated, CreatedAt: now, PullRequest: &models.PullRequest{AssignedReviewers: []string{"u2"}},
+				Assignments: []models.AssignmentEvent{{UserID: "u2", Action: models.ActionAssigned}}},
+			{Type: models.EventReviewerReassigned, CreatedAt: now.Add(time.Hour), PullRequest: &models.PullRequest{AssignedReviewers: []string{"u3"}},
+				Assignments: []models.AssignmentEvent{{UserID: "u3", Action: models.ActionAssigned, ReplacedUserID: "u2"}}},
+		},
+		// Logging started after pr-2 was created.
+		"pr-2": {
+			{Type: models.EventReviewerReassigned, CreatedAt: now, PullRequest: &models.PullRequest{AssignedReviewers: []string{"u2"}},
+				Assignments: []models.AssignmentEvent{{UserID: "u2", Action: models.ActionAssigned, ReplacedUserID: "u3"}}},
+		},
+	}
+
+	repo := teamStorage(nil)
+	repo.CountEventPullRequestsFunc = func(ctx context.Context) (int64, error) {
+		return int64(len(logged)), nil
+	}
+	repo.GetEventPullRequestsFunc = func(ctx context.Context, afterID string, limit int) ([]string, error) {
+		if afterID != "" {
+			return nil, nil
+		}
+		return []string{"pr-1", "pr-2"}, nil
+	}
+	repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+		return prs[prID], nil
+	}
+	repo.GetPullRequestEventsFunc = func(ctx context.Context, prID string) ([]models.Event, error) {
+		return logged[prID], nil
+	}
+	repo.ReplaceAssignmentHistoryFunc = func(ctx context.Context, prID string, events []models.AssignmentEvent) error {
+		return nil
+	}
+	repo.RefreshStatisticsFunc = func(ctx context.Context) error {
+		return nil
+	}
+
+	var reports []models.ReplayProgress
+	result, err := newService(repo).ReplayEvents(context.Background(), models.ReplayOptions{}, func(p models.ReplayProgress) {
+		reports = append(reports, p)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Replayed != 1 || result.Skipped != 1 || result.Events != 3 || !result.Done {
+		t.Errorf("result = %+v, want pr-1 replayed and pr-2 skipped", result)
+	}
+	if len(reports) != 2 || reports[0].Done || !reports[1].Done {
+		t.Errorf("progress reports = %+v, want one per batch and a final one", reports)
+	}
+
+	calls := repo.ReplaceAssignmentHistoryCalls()
+	if len(calls) != 1 || calls[0].PrID != "pr-1" {
+		t.Fatalf("replaced history = %+v, want pr-1 only", calls)
+	}
+	history := calls[0].Events
+	if len(history) != 2 || history[1].ReplacedUserID != "u2" || !history[1].CreatedAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("history = %+v", history)
+	}
+	if len(repo.RefreshStatisticsCalls()) != 1 {
+		t.Error("statistics were not refreshed")
+	}
+}
+
+func TestMergePullRequestNotFound(t *testing.T) {
+	repo := &repositorymock.StorageMock{
+		GetPullRequestFunc: func(ctx context.Context, prID string) (*models.PullRequest, error) {
+			return nil, nil
+		},
+	}
+
+	_, err := newService(repo).MergePullRequest(context.Background(), "pr-1")
+	if code := errorCode(err); code != models.ErrNotFound {
+		t.Errorf("error code = %q (%v), want %q", code, err, models.ErrNotFound)
+	}
+}
+
+func TestApplyGitEvent(t *testing.T) {
+	repo := teamStorage(nil)
+	prs := map[string]*models.PullRequest{}
+	repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+		return prs[prID], nil
+	}
+	repo.CreatePullRequestFunc = func(ctx context.Context, pr *models.PullRequest) error {
+		prs[pr.PullRequestID] = pr
+		return nil
+	}
+	repo.UpdatePullRequestStatusFunc = func(ctx context.Context, pr *models.PullRequest, from models.PullRequestStatus) (bool, error) {
+		return true, nil
+	}
+	repo.GetRepositoryFunc = func(ctx context.Context, name string) (*models.Repository, error) {
+		return nil, nil
+	}
+	repo.FindUserByIdentityFunc = func(ctx context.Context, provider models.IdentityProvider, externalID string) (*models.User, error) {
+		if provider == models.ProviderEmail && externalID == "alice@example.com" {
+			return &models.User{UserID: "u1"}, nil
+		}
+		return nil, nil
+	}
+	svc := newService(repo, service.WithRequiredApprovals(1))
+	ctx := context.Background()
+	event := service.GitEventParams{
+		Provider:        models.ProviderBitbucket,
+		Action:          service.GitOpened,
+		PullRequestID:   "bitbucket:proj:api:1",
+		PullRequestName: "Add search",
+		Repository:      "api",
+		Author:          "alice",
+	}
+
+	// The author is found by email only when the host sends it.
+	if _, err := svc.ApplyGitEvent(ctx, event); errorCode(err) != models.ErrNotFound {
+		t.Fatalf("unlinked author error = %v, want %q", err, models.ErrNotFound)
+	}
+	event.AuthorEmail = " Alice@example.com"
+	pr, err := svc.ApplyGitEvent(ctx, event)
+	if err != nil {
+		t.Fatalf("opened: %v", err)
+	}
+	if pr.AuthorID != "u1" || pr.Repository != "api" || len(pr.AssignedReviewers) != 2 {
+		t.Errorf("opened PR = %+v, want u1's PR in api with 2 reviewers", pr)
+	}
+
+	// The same event again changes nothing.
+	if again, err := svc.ApplyGitEvent(ctx, event); err != nil || again != pr || len(repo.CreatePullRequestCalls()) != 1 {
+		t.Errorf("opened again = %+v, %v; want the PR unchanged", again, err)
+	}
+
+	event.Action = service.GitDeclined
+	if closed, err := svc.ApplyGitEvent(ctx, event); err != nil || closed.Status != models.StatusClosed {
+		t.Fatalf("declined = %+v, %v; want CLOSED", closed, err)
+	}
+	if _, _, err := svc.ReassignReviewer(ctx, pr.PullRequestID, pr.AssignedReviewers[0]); errorCode(err) != models.ErrPRClosed {
+		t.Errorf("reassign on closed PR error = %v, want %q", err, models.ErrPRClosed)
+	}
+	if _, err := svc.MergePullRequest(ctx, pr.PullRequestID); errorCode(err) != models.ErrPRClosed {
+		t.Errorf("merge of closed PR error = %v, want %q", err, models.ErrPRClosed)
+	}
+
+	event.Action = service.GitOpened
+	if reopened, err := svc.ApplyGitEvent(ctx, event); err != nil || reopened.Status != models.StatusOpen {
+		t.Fatalf("reopened = %+v, %v; want OPEN", reopened, err)
+	}
+
+	// Merges on the host are recorded without the approvals required here.
+	event.Action = service.GitMerged
+	if merged, err := svc.ApplyGitEvent(ctx, event); err != nil || merged.Status != models.StatusMerged {
+		t.Fatalf("merged = %+v, %v; want MERGED", merged, err)
+	}
+	event.Action = service.GitDeclined
+	if _, err := svc.ApplyGitEvent(ctx, event); errorCode(err) != models.ErrPRMerged {
+		t.Errorf("declining a merged PR error = %v, want %q", err, models.ErrPRMerged)
+	}
+}
+
+func TestApplyGitEventUpdatesAndReviewers(t *testing.T) {
+	repo := teamStorage(nil)
+	prs := map[string]*models.PullRequest{
+		"gerrit:1": {PullRequestID: "gerrit:1", PullRequestName: "WIP", AuthorID: "u1", Status: models.StatusOpen,
+			AssignedReviewers: []string{"u2"}, LinesChanged: 10},
+	}
+	repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+		return prs[prID], nil
+	}
+	repo.UpdatePullRequestFunc = func(ctx context.Context, pr *models.PullRequest) (bool, error) {
+		prs[pr.PullRequestID] = pr
+		return true, nil
+	}
+	repo.UpdatePullRequestDetailsFunc = func(ctx context.Context, pr *models.PullRequest) (bool, error) {
+		prs[pr.PullRequestID] = pr
+		return true, nil
+	}
+	repo.GetAssignmentHistoryFunc = func(ctx context.Context, prID string) ([]models.AssignmentEvent, error) {
+		return nil, nil
+	}
+	repo.FindUserByIdentityFunc = func(ctx context.Context, provider models.IdentityProvider, externalID string) (*models.User, error) {
+		if provider == models.ProviderGerrit && externalID == "carol" {
+			return &models.User{UserID: "u3"}, nil
+		}
+		return nil, nil
+	}
+	events := &recordingPublisher{}
+	svc := newService(repo, service.WithEventPublisher(events))
+	ctx := context.Background()
+	event := service.GitEventParams{Provider: models.ProviderGerrit, PullRequestID: "gerrit:1"}
+
+	event.Action, event.PullRequestName, event.LinesChanged = service.GitUpdated, "Add search", 80
+	if pr, err := svc.ApplyGitEvent(ctx, event); err != nil || pr.PullRequestName != "Add search" || pr.LinesChanged != 80 {
+		t.Fatalf("updated = %+v, %v; want the new name and size", pr, err)
+	}
+	if len(events.events) != 1 || events.events[0].Type != models.EventPRUpdated || len(events.events[0].Changes) != 2 {
+		t.Errorf("events = %+v, want one pr.updated with the name and size", events.events)
+	}
+	if _, err := svc.ApplyGitEvent(ctx, event); err != nil || len(events.events) != 1 {
+		t.Errorf("unchanged update: %v, events = %+v; want no new event", err, events.events)
+	}
+
+	// Reviewers the host reports are added once; unlinked ones are skipped.
+	event.Action, event.Reviewer = service.GitReviewerAdded, "carol"
+	for i := 0; i < 2; i++ {
+		if pr, err := svc.ApplyGitEvent(ctx, event); err != nil || len(pr.AssignedReviewers) != 2 {
+			t.Fatalf("reviewer added = %+v, %v; want u2 and u3", pr, err)
+		}
+	}
+	event.Reviewer = "ci-bot"
+	if pr, err := svc.ApplyGitEvent(ctx, event); err != nil || len(pr.AssignedReviewers) != 2 {
+		t.Errorf("unlinked reviewer added = %+v, %v; want the PR unchanged", pr, err)
+	}
+	event.Action, event.Reviewer = service.GitReviewerRemoved, "carol"
+	if pr, err := svc.ApplyGitEvent(ctx, event); err != nil || len(pr.AssignedReviewers) != 1 {
+		t.Errorf("reviewer removed = %+v, %v; want only u2", pr, err)
+	}
+
+	event.PullRequestID = "gerrit:2"
+	if _, err := svc.ApplyGitEvent(ctx, event); errorCode(err) != models.ErrNotFound {
+		t.Errorf("reviewer of unknown PR error = %v, want %q", err, models.ErrNotFound)
+	}
+}
+
+func TestGetStatisticsStale(t *testing.T) {
+	tests := []struct {
+		name       string
+		computedAt time.Time
+		want       bool
+	}{
+		{"fresh", now.Add(-time.Minute), false},
+		{"stale", now.Add(-time.Hour), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repositorymock.StorageMock{
+				GetStatisticsFunc: func(ctx context.Context) (*models.Statistics, error) {
+					return &models.Statistics{ComputedAt: &tt.computedAt}, nil
+				},
+			}
+			svc := newService(repo, service.WithStatisticsMaxAge(2*time.Minute))
+
+			stats, err := svc.GetStatistics(context.Background())
+			if err != nil {
+				t.Fatalf("GetStatistics: %v", err)
+			}
+			if stats.Stale != tt.want {
+				t.Errorf("Stale = %v, want %v", stats.Stale, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetUserStatisticsRejectsEmptyPeriod(t *testing.T) {
+	svc := newService(&repositorymock.StorageMock{})
+
+	_, err := svc.GetUserStatistics(context.Background(), "u1", now, now.Add(-time.Hour))
+	if code := errorCode(err); code != models.ErrValidation {
+		t.Errorf("error code = %q (%v), want %q", code, err, models.ErrValidation)
+	}
+}
+
+func TestCreateRepositoryRuleLooksUpUsersOnce(t *testing.T) {
+	repo := teamStorage(nil)
+	repo.GetUsersByIDsFunc = func(ctx context.Context, userIDs []string) ([]models.User, error) {
+		users, _ := repo.GetUsersByTeam(ctx, "backend")
+		return users[1:3], nil
+	}
+	repo.CreateRepositoryRuleFunc = func(ctx context.Context, rule *models.RepositoryRule) error {
+		return nil
+	}
+	svc := newService(repo)
+
+	_, err := svc.CreateRepositoryRule(context.Background(), &models.RepositoryRule{
+		TeamName:   "backend",
+		Repository: "org/api",
+		UserIDs:    []string{"u2", "u3"},
+	})
+	if err != nil {
+		t.Fatalf("CreateRepositoryRule: %v", err)
+	}
+	if calls := len(repo.GetUsersByIDsCalls()); calls != 1 {
+		t.Errorf("GetUsersByIDs called %d times, want 1", calls)
+	}
+	if calls := len(repo.GetUserCalls()); calls != 0 {
+		t.Errorf("GetUser called %d times, want 0", calls)
+	}
+
+	_, err = svc.CreateRepositoryRule(context.Background(), &models.RepositoryRule{
+		TeamName:   "backend",
+		Repository: "org/api",
+		UserIDs:    []string{"u2", "ghost"},
+	})
+	if code := errorCode(err); code != models.ErrNotFound {
+		t.Errorf("unknown user error code = %q (%v), want %q", code, err, models.ErrNotFound)
+	}
+}
+
+func TestSetUserIdentityNormalizes(t *testing.T) {
+	tests := []struct {
+		provider   models.IdentityProvider
+		externalID string
+		want       string
+		wantErr    models.ErrorCode
+	}{
+		{models.ProviderGitHub, " @Alice-Dev ", "alice-dev", ""},
+		{models.ProviderGitLab, "org/alice", "", models.ErrValidation},
+		{models.ProviderSlack, "u01alice", "U01ALICE", ""},
+		{models.ProviderEmail, "Alice@Example.com", "alice@example.com", ""},
+		{models.ProviderEmail, "Alice <alice@example.com>", "", models.ErrValidation},
+		{models.ProviderJira, " 5b10ac8d82e05b22cc7d4ef5 ", "5b10ac8d82e05b22cc7d4ef5", ""},
+		{models.ProviderBitbucket, "557058:alice", "557058:alice", ""},
+		{"phabricator", "alice", "", models.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.provider)+" "+tt.externalID, func(t *testing.T) {
+			repo := teamStorage(nil)
+			repo.SetUserIdentityFunc = func(ctx context.Context, identity *models.UserIdentity) error {
+				return nil
+			}
+			svc := newService(repo)
+
+			identity, err := svc.SetUserIdentity(context.Background(), &models.UserIdentity{
+				UserID:     "u1",
+				Provider:   tt.provider,
+				ExternalID: tt.externalID,
+			})
+			if code := errorCode(err); code != tt.wantErr {
+				t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantErr)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			if identity.ExternalID != tt.want {
+				t.Errorf("ExternalID = %q, want %q", identity.ExternalID, tt.want)
+			}
+		})
+	}
+}
+
+type directorySource []models.DirectoryUser
+
+func (d directorySource) Users(ctx context.Context) ([]models.DirectoryUser, error) {
+	return d, nil
+}
+
+func TestSyncDirectory(t *testing.T) {
+	source := directorySource{
+		{UserID: "u1", Username: "Alice", TeamName: "frontend", Active: true},
+		{UserID: "u2", Username: "Bobby", TeamName: "backend", Active: true},
+		{UserID: "u5", Username: "Eve", TeamName: "backend", Active: true},
+	}
+	tests := []struct {
+		name   string
+		policy models.DirectoryConflictPolicy
+		dryRun bool
+		want   []string
+	}{
+		{
+			name:   "directory wins",
+			policy: models.ConflictDirectoryWins,
+			want:   []string{"create_team frontend", "move_user u1", "rename_user u2 conflict", "deactivate_user u3", "create_user u5"},
+		},
+		{
+			name:   "keep local",
+			policy: models.ConflictKeepLocal,
+			want:   []string{"create_team frontend", "move_user u1", "rename_user u2 conflict skipped", "deactivate_user u3", "create_user u5"},
+		},
+		{
+			name:   "dry run",
+			policy: models.ConflictDirectoryWins,
+			dryRun: true,
+			want:   []string{"create_team frontend", "move_user u1", "rename_user u2 conflict", "deactivate_user u3", "create_user u5"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// u1 and u3 are managed by the sync, u2 and u4 are local.
+			users := map[string]models.User{
+				"u1": {UserID: "u1", Username: "Alice", TeamName: "backend", IsActive: true, DirectoryManaged: true},
+				"u2": {UserID: "u2", Username: "Bob", TeamName: "backend", IsActive: true},
+				"u3": {UserID: "u3", Username: "Carol", TeamName: "backend", IsActive: true, DirectoryManaged: true},
+				"u4": {UserID: "u4", Username: "Dave", TeamName: "backend", IsActive: true},
+			}
+			applied := []string{}
+			repo := &repositorymock.StorageMock{}
+			repo.GetDirectoryManagedUsersFunc = func(ctx context.Context) ([]models.User, error) {
+				return []models.User{users["u1"], users["u3"]}, nil
+			}
+			repo.GetUsersByIDsFunc = func(ctx context.Context, userIDs []string) ([]models.User, error) {
+				result := []models.User{}
+				for _, id := range userIDs {
+					if u, ok := users[id]; ok {
+						result = append(result, u)
+					}
+				}
+				return result, nil
+			}
+			repo.GetDeletedUserIDsFunc = func(ctx context.Context, userIDs []string) ([]string, error) {
+				return nil, nil
+			}
+			repo.TeamExistsFunc = func(ctx context.Context, teamName string) (bool, error) {
+				return teamName == "backend", nil
+			}
+			repo.TeamDeletedFunc = func(ctx context.Context, teamName string) (bool, error) {
+				return false, nil
+			}
+			repo.GetUserFunc = func(ctx context.Context, userID string) (*models.User, error) {
+				u := users[userID]
+				return &u, nil
+			}
+			repo.CreateTeamFunc = func(ctx context.Context, team *models.Team) error {
+				applied = append(applied, "team "+team.TeamName)
+				return nil
+			}
+			repo.CreateUserFunc = func(ctx context.Context, user *models.User) error {
+				applied = append(applied, fmt.Sprintf("create %s %s %s %v", user.UserID, user.Username, user.TeamName, user.DirectoryManaged))
+				return nil
+			}
+			repo.UpdateUserFunc = func(ctx context.Context, user *models.User) error {
+				applied = append(applied, fmt.Sprintf("update %s %s %s %v %v", user.UserID, user.Username, user.TeamName, user.IsActive, user.DirectoryManaged))
+				return nil
+			}
+			svc := newService(repo, service.WithDirectory(source, tt.policy))
+
+			report, err := svc.SyncDirectory(context.Background(), tt.dryRun)
+			if err != nil {
+				t.Fatalf("SyncDirectory: %v", err)
+			}
+			got := []string{}
+			for _, c := range report.Changes {
+				s := string(c.Action) + " " + c.UserID
+				if c.UserID == "" {
+					s = string(c.Action) + " " + c.TeamName
+				}
+				if c.Conflict {
+					s += " conflict"
+				}
+				if c.Skipped {
+					s += " skipped"
+				}
+				got = append(got, s)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes = %v, want %v", got, tt.want)
+			}
+			if report.Conflicts != 1 {
+				t.Errorf("Conflicts = %d, want 1", report.Conflicts)
+			}
+
+			wantApplied := []string{
+				"team frontend",
+				"update u1 Alice frontend true true",
+				"update u2 Bobby backend true true",
+				"update u3 Carol backend false true",
+				"create u5 Eve backend true",
+			}
+			switch {
+			case tt.dryRun:
+				wantApplied = []string{}
+			case tt.policy == models.ConflictKeepLocal:
+				wantApplied = append(wantApplied[:2], wantApplied[3:]...)
+			}
+			if !reflect.DeepEqual(applied, wantApplied) {
+				t.Errorf("applied = %v, want %v", applied, wantApplied)
+			}
+			if report.Applied != len(wantApplied) {
+				t.Errorf("Applied = %d, want %d", report.Applied, len(wantApplied))
+			}
+		})
+	}
+}
+
+func TestSyncDirectorySkipsDeleted(t *testing.T) {
+	// u1 (managed) and u6 (local) are deleted, and so is team ops.
+	source := directorySource{
+		{UserID: "u1", Username: "Alicia", TeamName: "backend", Active: true},
+		{UserID: "u6", Username: "Frank", TeamName: "backend", Active: true},
+		{UserID: "u7", Username: "Grace", TeamName: "ops", Active: true},
+		{UserID: "u8", Username: "Heidi", TeamName: "ops", Active: true},
+	}
+	live := map[string]models.User{
+		"u8": {UserID: "u8", Username: "Heidi", TeamName: "backend", IsActive: true, DirectoryManaged: true},
+	}
+	repo := &repositorymock.StorageMock{
+		GetDirectoryManagedUsersFunc: func(ctx context.Context) ([]models.User, error) {
+			return []models.User{live["u8"]}, nil
+		},
+		GetUsersByIDsFunc: func(ctx context.Context, userIDs []string) ([]models.User, error) {
+			result := []models.User{}
+			for _, id := range userIDs {
+				if u, ok := live[id]; ok {
+					result = append(result, u)
+				}
+			}
+			return result, nil
+		},
+		GetDeletedUserIDsFunc: func(ctx context.Context, userIDs []string) ([]string, error) {
+			return []string{"u1", "u6"}, nil
+		},
+		TeamExistsFunc: func(ctx context.Context, teamName string) (bool, error) {
+			return teamName == "backend", nil
+		},
+		TeamDeletedFunc: func(ctx context.Context, teamName string) (bool, error) {
+			return teamName == "ops", nil
+		},
+	}
+	svc := newService(repo, service.WithDirectory(source, models.ConflictDirectoryWins))
+
+	report, err := svc.SyncDirectory(context.Background(), false)
+	if err != nil {
+		t.Fatalf("SyncDirectory: %v", err)
+	}
+	got := []string{}
+	for _, c := range report.Changes {
+		if !c.Conflict || !c.Skipped || !c.Deleted {
+			t.Errorf("change %+v not skipped as deleted", c)
+		}
+		got = append(got, string(c.Action)+" "+c.UserID)
+	}
+	want := []string{"create_team ", "create_user u1", "create_user u6", "create_user u7", "move_user u8"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("changes = %v, want %v", got, want)
+	}
+	if report.Applied != 0 || report.Skipped != len(want) {
+		t.Errorf("Applied = %d, Skipped = %d; want 0 and %d", report.Applied, report.Skipped, len(want))
+	}
+}
+
+func TestSyncDirectoryRefusesEmptyDirectory(t *testing.T) {
+	svc := newService(&repositorymock.StorageMock{}, service.WithDirectory(directorySource{}, models.ConflictDirectoryWins))
+
+	_, err := svc.SyncDirectory(context.Background(), false)
+	if code := errorCode(err); code != models.ErrServiceUnavailable {
+		t.Fatalf("error code = %q (%v), want %q", code, err, models.ErrServiceUnavailable)
+	}
+}
+
+// fakeTracker files issues with sequential keys and fails transitions
+// while failing is set.
+type fakeTracker struct {
+	issues      []string
+	tasks       []string
+	transitions []string
+	failing     bool
+}
+
+func (f *fakeTracker) ReferencedIssue(pr models.PullRequest) string {
+	return ""
+}
+
+func (f *fakeTracker) CreateIssue(ctx context.Context, pr models.PullRequest, teamName string) (string, error) {
+	f.issues = append(f.issues, teamName)
+	return fmt.Sprintf("REV-%d", len(f.issues)), nil
+}
+
+func (f *fakeTracker) CreateTask(ctx context.Context, parentKey string, pr models.PullRequest, reviewer models.User, assignee string) (string, error) {
+	f.tasks = append(f.tasks, parentKey+" "+reviewer.UserID+" "+assignee)
+	return fmt.Sprintf("REV-%d", 100+len(f.tasks)), nil
+}
+
+func (f *fakeTracker) TransitionTask(ctx context.Context, key string, event models.ReviewTaskEvent) error {
+	if f.failing {
+		return errors.New("jira is down")
+	}
+	f.transitions = append(f.transitions, key+" "+string(event))
+	return nil
+}
+
+func TestDeliverOutboxFilesReviewTasks(t *testing.T) {
+	repo := teamStorage(nil)
+	var pr *models.PullRequest
+	repo.CreatePullRequestFunc = func(ctx context.Context, created *models.PullRequest) error {
+		pr = created
+		return nil
+	}
+	repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+		copied := *pr
+		return &copied, nil
+	}
+	repo.UpdatePullRequestFunc = func(ctx context.Context, updated *models.PullRequest) (bool, error) {
+		pr = updated
+		return true, nil
+	}
+	repo.UpdatePullRequestStatusFunc = func(ctx context.Context, updated *models.PullRequest, from models.PullRequestStatus) (bool, error) {
+		pr = updated
+		return true, nil
+	}
+	repo.CreateReviewFunc = func(ctx context.Context, review *models.Review) error {
+		pr.Reviews = append(pr.Reviews, *review)
+		return nil
+	}
+	repo.GetUserIdentitiesFunc = func(ctx context.Context, userIDs []string) ([]models.UserIdentity, error) {
+		if userIDs[0] == "u2" {
+			return []models.UserIdentity{{UserID: "u2", Provider: models.ProviderJira, ExternalID: "bob-jira"}}, nil
+		}
+		return nil, nil
+	}
+
+	var outbox []models.OutboxMessage
+	var failures []string
+	repo.AddOutboxMessagesFunc = func(ctx context.Context, messages []models.OutboxMessage) error {
+		for _, m := range messages {
+			m.ID = int64(len(outbox) + len(failures) + 1)
+			outbox = append(outbox, m)
+		}
+		return nil
+	}
+	repo.ClaimOutboxMessagesFunc = func(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.OutboxMessage, error) {
+		return append([]models.OutboxMessage(nil), outbox...), nil
+	}
+	repo.DeleteOutboxMessageFunc = func(ctx context.Context, id int64) error {
+		for i, m := range outbox {
+			if m.ID == id {
+				outbox = append(outbox[:i], outbox[i+1:]...)
+				break
+			}
+		}
+		return nil
+	}
+	repo.FailOutboxMessageFunc = func(ctx context.Context, id int64, lastError string, nextAttemptAt *time.Time) error {
+		failures = append(failures, fmt.Sprintf("%d %s %s", id, lastError, nextAttemptAt.Sub(now)))
+		return nil
+	}
+
+	issue := ""
+	var tasks []models.ReviewTask
+	repo.GetReviewIssueFunc = func(ctx context.Context, prID string) (string, error) {
+		return issue, nil
+	}
+	repo.SetReviewIssueFunc = func(ctx context.Context, prID, key string) error {
+		issue = key
+		return nil
+	}
+	repo.GetReviewTasksFunc = func(ctx context.Context, prID string) ([]models.ReviewTask, error) {
+		return tasks, nil
+	}
+	repo.AddReviewTaskFunc = func(ctx context.Context, task models.ReviewTask) error {
+		tasks = append(tasks, task)
+		return nil
+	}
+
+	tracker := &fakeTracker{}
+	svc := newService(repo, service.WithIssueTracker(tracker, 3))
+	ctx := context.Background()
+
+	created, err := svc.CreatePullRequest(ctx, service.CreatePullRequestParams{PullRequestID: "pr-1", PullRequestName: "Fix login", AuthorID: "u1"})
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	if len(outbox) != len(created.AssignedReviewers) {
+		t.Fatalf("queued %d messages for %d reviewers", len(outbox), len(created.AssignedReviewers))
+	}
+	delivered, err := svc.DeliverOutbox(ctx)
+	if err != nil || delivered != 2 {
+		t.Fatalf("DeliverOutbox = %d, %v, want 2 delivered", delivered, err)
+	}
+	// A second delivery of the same creation must not file another task.
+	outbox = append(outbox, models.OutboxMessage{ID: 99, Kind: models.OutboxReviewTaskCreate, PullRequestID: "pr-1", UserID: created.AssignedReviewers[0]})
+	if _, err := svc.DeliverOutbox(ctx); err != nil {
+		t.Fatalf("DeliverOutbox again: %v", err)
+	}
+	if !reflect.DeepEqual(tracker.issues, []string{"backend"}) || len(tracker.tasks) != 2 {
+		t.Fatalf("filed issues %v and tasks %v, want one issue and two tasks", tracker.issues, tracker.tasks)
+	}
+	for _, task := range tracker.tasks {
+		if strings.Contains(task, " u2 ") && !strings.HasSuffix(task, " bob-jira") {
+			t.Errorf("task %q is not assigned to Bob's Jira account", task)
+		}
+	}
+
+	reviewer := created.AssignedReviewers[0]
+	if _, err := svc.SubmitReview(ctx, &models.Review{PullRequestID: "pr-1", ReviewerID: reviewer, State: models.ReviewApproved}); err != nil {
+		t.Fatalf("SubmitReview: %v", err)
+	}
+	tracker.failing = true
+	if _, err := svc.DeliverOutbox(ctx); err != nil {
+		t.Fatalf("DeliverOutbox while failing: %v", err)
+	}
+	if len(outbox) != 1 || len(failures) != 1 || !strings.HasSuffix(failures[0], "jira is down 30s") {
+		t.Fatalf("outbox %v, failures %v, want the approval kept and retried in 30s", outbox, failures)
+	}
+
+	tracker.failing = false
+	if _, err := svc.DeliverOutbox(ctx); err != nil {
+		t.Fatalf("DeliverOutbox: %v", err)
+	}
+	if _, err := svc.MergePullRequest(ctx, "pr-1"); err != nil {
+		t.Fatalf("MergePullRequest: %v", err)
+	}
+	if _, err := svc.DeliverOutbox(ctx); err != nil {
+		t.Fatalf("DeliverOutbox: %v", err)
+	}
+	want := []string{tasks[0].IssueKey + " approved", tasks[0].IssueKey + " merged", tasks[1].IssueKey + " merged"}
+	if tasks[0].UserID != reviewer || !reflect.DeepEqual(tracker.transitions, want) {
+		t.Errorf("transitions = %v, want %v", tracker.transitions, want)
+	}
+	if len(outbox) != 0 {
+		t.Errorf("outbox still holds %v", outbox)
+	}
+}
+
+// fakeRequester hosts the PRs of repository api, whose requested reviewers
+// it keeps with GitHub's case, and fails calls with err while it is set.
+type fakeRequester struct {
+	requested map[string][]string
+	calls     []string
+	err       error
+}
+
+func (f *fakeRequester) Hosts(pr models.PullRequest) bool {
+	return pr.Repository == "api"
+}
+
+func (f *fakeRequester) RequestedReviewers(ctx context.Context, pr models.PullRequest) ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.requested[pr.PullRequestID], nil
+}
+
+func (f *fakeRequester) RequestReviewers(ctx context.Context, pr models.PullRequest, logins []string) error {
+	f.calls = append(f.calls, "request "+strings.Join(logins, ","))
+	f.requested[pr.PullRequestID] = append(f.requested[pr.PullRequestID], logins...)
+	return nil
+}
+
+func (f *fakeRequester) RemoveReviewRequests(ctx context.Context, pr models.PullRequest, logins []string) error {
+	f.calls = append(f.calls, "remove "+strings.Join(logins, ","))
+	kept := []string{}
+	for _, login := range f.requested[pr.PullRequestID] {
+		if !strings.EqualFold(login, logins[0]) {
+			kept = append(kept, login)
+		}
+	}
+	f.requested[pr.PullRequestID] = kept
+	return nil
+}
+
+func TestDeliverOutboxRequestsReviews(t *testing.T) {
+	repo := teamStorage(nil)
+	var pr *models.PullRequest
+	repo.CreatePullRequestFunc = func(ctx context.Context, created *models.PullRequest) error {
+		pr = created
+		return nil
+	}
+	repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+		copied := *pr
+		copied.AssignedReviewers = append([]string(nil), pr.AssignedReviewers...)
+		return &copied, nil
+	}
+	repo.UpdatePullRequestFunc = func(ctx context.Context, updated *models.PullRequest) (bool, error) {
+		pr = updated
+		return true, nil
+	}
+	repo.GetRepositoryFunc = func(ctx context.Context, name string) (*models.Repository, error) {
+		return nil, nil
+	}
+	repo.GetAssignmentHistoryFunc = func(ctx context.Context, prID string) ([]models.AssignmentEvent, error) {
+		return nil, nil
+	}
+	logins := map[string]string{"u2": "bob", "u3": "carol", "u4": "dave"}
+	repo.GetUserIdentitiesFunc = func(ctx context.Context, userIDs []string) ([]models.UserIdentity, error) {
+		return []models.UserIdentity{{UserID: userIDs[0], Provider: models.ProviderGitHub, ExternalID: logins[userIDs[0]]}}, nil
+	}
+
+	var outbox []models.OutboxMessage
+	var failures []string
+	repo.AddOutboxMessagesFunc = func(ctx context.Context, messages []models.OutboxMessage) error {
+		for _, m := range messages {
+			m.ID = int64(len(outbox) + len(failures) + 1)
+			outbox = append(outbox, m)
+		}
+		return nil
+	}
+	repo.ClaimOutboxMessagesFunc = func(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.OutboxMessage, error) {
+		return append([]models.OutboxMessage(nil), outbox...), nil
+	}
+	repo.DeleteOutboxMessageFunc = func(ctx context.Context, id int64) error {
+		for i, m := range outbox {
+			if m.ID == id {
+				outbox = append(outbox[:i], outbox[i+1:]...)
+				break
+			}
+		}
+		return nil
+	}
+	repo.FailOutboxMessageFunc = func(ctx context.Context, id int64, lastError string, nextAttemptAt *time.Time) error {
+		failures = append(failures, fmt.Sprintf("%d %s", id, nextAttemptAt.Sub(now)))
+		return nil
+	}
+
+	requester := &fakeRequester{requested: map[string][]string{}}
+	svc := newService(repo, service.WithReviewRequests(requester, 3))
+	ctx := context.Background()
+
+	created, err := svc.CreatePullRequest(ctx, service.CreatePullRequestParams{PullRequestID: "pr-7", PullRequestName: "Fix login", AuthorID: "u1", Repository: "api"})
+	if err != nil {
+		t.Fatalf("CreatePullRequest: %v", err)
+	}
+	first, second := created.AssignedReviewers[0], created.AssignedReviewers[1]
+	// Someone already asked the first reviewer on GitHub.
+	requester.requested["pr-7"] = []string{strings.ToUpper(logins[first])}
+
+	// Until the rate limit resets nothing is delivered, nor retried.
+	reset := now.Add(2 * time.Hour)
+	requester.err = &repository.RateLimitError{Reset: reset}
+	if delivered, err := svc.DeliverOutbox(ctx); err != nil || delivered != 0 {
+		t.Fatalf("DeliverOutbox while rate limited = %d, %v", delivered, err)
+	}
+	if want := []string{"1 2h0m0s", "2 2h0m0s"}; !reflect.DeepEqual(failures, want) {
+		t.Fatalf("failures = %v, want retries once the limit resets %v", failures, want)
+	}
+
+	requester.err = nil
+	if delivered, err := svc.DeliverOutbox(ctx); err != nil || delivered != 2 {
+		t.Fatalf("DeliverOutbox = %d, %v, want 2 delivered", delivered, err)
+	}
+	if want := []string{"request " + logins[second]}; !reflect.DeepEqual(requester.calls, want) {
+		t.Fatalf("calls = %v, want only the reviewer not requested yet %v", requester.calls, want)
+	}
+
+	// Reassigning withdraws the request of the reviewer taken off.
+	_, replacement, err := svc.ReassignReviewer(ctx, "pr-7", first)
+	if err != nil {
+		t.Fatalf("ReassignReviewer: %v", err)
+	}
+	if _, err := svc.DeliverOutbox(ctx); err != nil {
+		t.Fatalf("DeliverOutbox: %v", err)
+	}
+	want := []string{"request " + logins[second], "remove " + logins[first], "request " + logins[replacement]}
+	if !reflect.DeepEqual(requester.calls, want) || len(outbox) != 0 {
+		t.Errorf("calls = %v with %v left, want %v", requester.calls, outbox, want)
+	}
+
+	// A request GitHub refuses for good is not retried.
+	requester.err = fmt.Errorf("github: %w", repository.ErrRejected)
+	outbox = append(outbox, models.OutboxMessage{ID: 99, Kind: models.OutboxReviewRequestAdd, PullRequestID: "pr-7", UserID: second})
+	if _, err := svc.DeliverOutbox(ctx); err != nil {
+		t.Fatalf("DeliverOutbox: %v", err)
+	}
+	if len(outbox) != 0 || len(failures) != 2 {
+		t.Errorf("outbox %v, failures %v, want the rejected request dropped", outbox, failures)
+	}
+}
+
+func TestUpdateTeamSettingsChecksNotificationTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider models.NotificationProvider
+		template string
+		wantErr  models.ErrorCode
+	}{
+		{"default", "", "", ""},
+		{"mentions", models.NotifyMSTeams, "{{.Message}}: {{range .Mentions}}{{.}} {{end}}", ""},
+		{"unknown provider", "discord", "", models.ErrValidation},
+		{"syntax error", models.NotifySlack, "{{.Message", models.ErrValidation},
+		{"unknown field", models.NotifySlack, "{{.Author}}", models.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &repositorymock.StorageMock{
+				TeamExistsFunc: func(ctx context.Context, teamName string) (bool, error) {
+					return true, nil
+				},
+				UpsertTeamSettingsFunc: func(ctx context.Context, settings *models.TeamSettings) error {
+					return nil
+				},
+			}
+			_, err := newService(repo).UpdateTeamSettings(context.Background(), &models.TeamSettings{
+				TeamName:             "backend",
+				NotificationProvider: tt.provider,
+				NotificationTemplate: tt.template,
+			})
+			if code := errorCode(err); code != tt.wantErr {
+				t.Errorf("error code = %q (%v), want %q", code, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// recordingNotifier keeps the notifications it is asked to send.
+type recordingNotifier struct {
+	sent []models.Notification
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, n models.Notification) error {
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+// recordingPublisher keeps the events published.
+type recordingPublisher struct {
+	events []models.Event
+}
+
+func (r *recordingPublisher) Publish(ctx context.Context, event models.Event) {
+	r.events = append(r.events, event)
+}
+
+func TestUpdatePullRequest(t *testing.T) {
+	stored := &models.PullRequest{PullRequestID: "pr-1", PullRequestName: "WIP", AuthorID: "u1", Status: models.StatusOpen,
+		Priority: models.PriorityNormal, Labels: []string{"backend"}, ChangedPaths: []string{"api/handler.go"}, AssignedReviewers: []string{"u2"}}
+	repo := teamStorage(nil)
+	repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+		if prID != stored.PullRequestID {
+			return nil, nil
+		}
+		pr := *stored
+		return &pr, nil
+	}
+	repo.UpdatePullRequestDetailsFunc = func(ctx context.Context, pr *models.PullRequest) (bool, error) {
+		if stored.Status != models.StatusOpen {
+			return false, nil
+		}
+		stored = pr
+		return true, nil
+	}
+	repo.GetRepositoryFunc = func(ctx context.Context, name string) (*models.Repository, error) {
+		if name != "api" {
+			return nil, nil
+		}
+		return &models.Repository{Name: name}, nil
+	}
+	events := &recordingPublisher{}
+	svc := newService(repo, service.WithEventPublisher(events), service.WithRequireRepository(true))
+	ctx := context.Background()
+	name, repoName := "Add search", " API/Search "
+
+	pr, err := svc.UpdatePullRequest(ctx, service.UpdatePullRequestParams{
+		PullRequestID:   "pr-1",
+		PullRequestName: &name,
+		Labels:          []string{"Backend"},
+		ChangedPaths:    []string{"./web/app.ts", "api/handler.go"},
+		Repository:      &repoName,
+		Priority:        models.PriorityUrgent,
+	})
+	if err != nil {
+		t.Fatalf("UpdatePullRequest: %v", err)
+	}
+	if pr.PullRequestName != name || pr.Repository != "api/search" || pr.Priority != models.PriorityUrgent ||
+		len(pr.ChangedPaths) != 2 || len(pr.AssignedReviewers) != 1 {
+		t.Errorf("pr = %+v", pr)
+	}
+	// The labels are the same once normalized, so only four fields changed.
+	if len(events.events) != 1 || events.events[0].Type != models.EventPRUpdated || len(events.events[0].Changes) != 4 {
+		t.Fatalf("events = %+v, want one pr.updated with 4 changes", events.events)
+	}
+	if c := events.events[0].Changes[0]; c.Field != "pull_request_name" || c.Old != "WIP" || c.New != name {
+		t.Errorf("first change = %+v", c)
+	}
+
+	// Nothing left to change: no write and no event.
+	if _, err := svc.UpdatePullRequest(ctx, service.UpdatePullRequestParams{PullRequestID: "pr-1", PullRequestName: &name}); err != nil {
+		t.Fatalf("unchanged update: %v", err)
+	}
+	if len(repo.UpdatePullRequestDetailsCalls()) != 1 || len(events.events) != 1 {
+		t.Errorf("unchanged update saved or published")
+	}
+
+	unknown, blank := "web", " "
+	tests := []struct {
+		name   string
+		params service.UpdatePullRequestParams
+		want   models.ErrorCode
+	}{
+		{"blank name", service.UpdatePullRequestParams{PullRequestID: "pr-1", PullRequestName: &blank}, models.ErrValidation},
+		{"bad priority", service.UpdatePullRequestParams{PullRequestID: "pr-1", Priority: "HIGH"}, models.ErrValidation},
+		{"unregistered repository", service.UpdatePullRequestParams{PullRequestID: "pr-1", Repository: &unknown}, models.ErrInvalidReference},
+		{"unknown PR", service.UpdatePullRequestParams{PullRequestID: "pr-2", Labels: []string{}}, models.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.UpdatePullRequest(ctx, tt.params); errorCode(err) != tt.want {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	stored.Status = models.StatusMerged
+	if _, err := svc.UpdatePullRequest(ctx, service.UpdatePullRequestParams{PullRequestID: "pr-1", Labels: []string{}}); errorCode(err) != models.ErrPRMerged {
+		t.Errorf("merged PR error = %v, want %q", err, models.ErrPRMerged)
+	}
+}
+
+func TestSendDigests(t *testing.T) {
+	sentToday := now.Add(-30 * time.Minute)
+	users := []models.User{
+		{UserID: "u2", TeamName: "backend", IsActive: true},
+		{UserID: "u3", TeamName: "backend", IsActive: true, Timezone: "America/New_York"},
+		{UserID: "u4", TeamName: "backend", IsActive: true},
+	}
+	marked := []string{}
+	repo := &repositorymock.StorageMock{
+		GetEnabledDigestsFunc: func(ctx context.Context) ([]models.DigestPreferences, error) {
+			return []models.DigestPreferences{
+				{UserID: "u2", Enabled: true, SendAt: "09:00", Provider: models.NotifySlack},
+				// 05:00 in New York, before the send time.
+				{UserID: "u3", Enabled: true, SendAt: "09:00"},
+				{UserID: "u4", Enabled: true, SendAt: "09:00", LastSentAt: &sentToday},
+			}, nil
+		},
+		GetUsersByIDsFunc: func(ctx context.Context, userIDs []string) ([]models.User, error) {
+			return users, nil
+		},
+		GetStalePullRequestsFunc: func(ctx context.Context) ([]models.StalePullRequest, error) {
+			return []models.StalePullRequest{{PullRequestID: "pr-1", AssignedReviewers: []string{"u2"}}}, nil
+		},
+		GetPullRequestsByReviewerFunc: func(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+			return []models.PullRequestShort{
+				{PullRequestID: "pr-1", PullRequestName: "Old fix", Status: models.StatusOpen},
+				{PullRequestID: "pr-2", PullRequestName: "New feature", Status: models.StatusOpen},
+				{PullRequestID: "pr-3", Status: models.StatusMerged},
+			}, nil
+		},
+		GetUserHistoryFunc: func(ctx context.Context, filter models.ReviewHistoryFilter) ([]models.ReviewHistoryEntry, error) {
+			if !filter.Since.Equal(now.Add(-24 * time.Hour)) {
+				t.Errorf("history since %v, want a day before %v", filter.Since, now)
+			}
+			return []models.ReviewHistoryEntry{
+				{PullRequestID: "pr-2", Status: models.StatusOpen},
+				{PullRequestID: "pr-4", Status: models.StatusOpen, ReplacedBy: "u3"},
+			}, nil
+		},
+		GetTeamSettingsFunc: func(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+			return &models.TeamSettings{TeamName: teamName, NotificationChannel: "https://chat.example.com/backend"}, nil
+		},
+		GetUserIdentitiesFunc: func(ctx context.Context, userIDs []string) ([]models.UserIdentity, error) {
+			return nil, nil
+		},
+		MarkDigestSentFunc: func(ctx context.Context, userID string, at time.Time) error {
+			marked = append(marked, userID)
+			return nil
+		},
+	}
+	notifier := &recordingNotifier{}
+	svc := newService(repo, service.WithNotifier(notifier))
+
+	sent, err := svc.SendDigests(context.Background())
+	if err != nil {
+		t.Fatalf("SendDigests: %v", err)
+	}
+	if sent != 1 || len(notifier.sent) != 1 || !reflect.DeepEqual(marked, []string{"u2"}) {
+		t.Fatalf("sent %d (%d notifications), marked %v; want one digest to u2", sent, len(notifier.sent), marked)
+	}
+
+	n := notifier.sent[0]
+	if n.Event != models.EventReviewDigest || n.Channel != "https://chat.example.com/backend" || n.Provider != models.NotifySlack {
+		t.Errorf("notification = %+v, want a Slack digest in the team channel", n)
+	}
+	d := n.Digest
+	if d == nil || len(d.OpenReviews) != 2 || len(d.Overdue) != 1 || d.Overdue[0].PullRequestID != "pr-1" ||
+		len(d.NewlyAssigned) != 1 || d.NewlyAssigned[0].PullRequestID != "pr-2" {
+		t.Errorf("digest = %+v, want 2 open, pr-1 overdue and pr-2 new", d)
+	}
+	if !strings.HasPrefix(n.Message, "Review digest: 2 open, 1 overdue, 1 new") || !strings.Contains(n.Message, "pr-1 Old fix (overdue)") {
+		t.Errorf("message = %q", n.Message)
+	}
+}
+
+func TestEscalateIdleReviews(t *testing.T) {
+	repo := teamStorage(nil)
+	repo.GetTeamSettingsFunc = func(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+		return &models.TeamSettings{TeamName: teamName, RemindAfterHours: 4, EscalateAfterHours: 24, ReassignAfterHours: 48}, nil
+	}
+	getUser := repo.GetUserFunc
+	repo.GetUserFunc = func(ctx context.Context, userID string) (*models.User, error) {
+		u, err := getUser(ctx, userID)
+		if u != nil && userID == "u3" {
+			u.ManagerID = "u1"
+		}
+		return u, err
+	}
+	repo.GetIdleReviewsFunc = func(ctx context.Context) ([]models.IdleReview, error) {
+		idle := func(prID, userID string, hours int, taken ...models.EscalationAction) models.IdleReview {
+			return models.IdleReview{PullRequestID: prID, PullRequestName: "PR " + prID, AuthorID: "u1", TeamName: "backend",
+				UserID: userID, AssignedAt: now.Add(-time.Duration(hours) * time.Hour), Taken: taken}
+		}
+		return []models.IdleReview{
+			idle("pr-1", "u4", 50, models.EscalationReminded),
+			idle("pr-2", "u3", 30, models.EscalationReminded),
+			idle("pr-2", "u2", 30, models.EscalationLeadNotified),
+			idle("pr-3", "u2", 5),
+			idle("pr-3", "u3", 2),
+		}, nil
+	}
+	repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+		return &models.PullRequest{PullRequestID: prID, AuthorID: "u1", Status: models.StatusOpen,
+			AssignedReviewers: []string{"u4"}, ShadowReviewers: []string{}}, nil
+	}
+	repo.UpdatePullRequestFunc = func(ctx context.Context, pr *models.PullRequest) (bool, error) {
+		return true, nil
+	}
+	repo.GetAssignmentHistoryFunc = func(ctx context.Context, prID string) ([]models.AssignmentEvent, error) {
+		return nil, nil
+	}
+	repo.GetUserIdentitiesFunc = func(ctx context.Context, userIDs []string) ([]models.UserIdentity, error) {
+		return nil, nil
+	}
+	var recorded []models.Escalation
+	repo.AddEscalationFunc = func(ctx context.Context, e *models.Escalation) error {
+		recorded = append(recorded, *e)
+		return nil
+	}
+	notifier := &recordingNotifier{}
+	svc := newService(repo, service.WithNotifier(notifier))
+
+	taken, err := svc.EscalateIdleReviews(context.Background())
+	if err != nil {
+		t.Fatalf("EscalateIdleReviews: %v", err)
+	}
+	if taken != 3 || len(recorded) != 3 {
+		t.Fatalf("took %d steps, recorded %+v; want 3", taken, recorded)
+	}
+
+	// Past 48 hours u4 is replaced without further notice.
+	if e := recorded[0]; e.PullRequestID != "pr-1" || e.UserID != "u4" || e.Action != models.EscalationReassigned ||
+		e.TargetID == "" || e.TargetID == "u4" || e.TargetID == "u1" {
+		t.Errorf("first escalation = %+v, want u4 reassigned", e)
+	}
+	if e := recorded[1]; e.UserID != "u3" || e.Action != models.EscalationLeadNotified || e.TargetID != "u1" {
+		t.Errorf("second escalation = %+v, want u3's manager u1 notified", e)
+	}
+	if e := recorded[2]; e.PullRequestID != "pr-3" || e.UserID != "u2" || e.Action != models.EscalationReminded ||
+		!e.AssignedAt.Equal(now.Add(-5*time.Hour)) {
+		t.Errorf("third escalation = %+v, want u2 reminded", e)
+	}
+
+	if len(notifier.sent) != 2 {
+		t.Fatalf("sent %+v, want the lead notice and the reminder", notifier.sent)
+	}
+	if n := notifier.sent[0]; n.Event != models.EventReviewEscalated || !reflect.DeepEqual(n.Recipients, []string{"u1"}) {
+		t.Errorf("lead notice = %+v", n)
+	}
+	if n := notifier.sent[1]; n.Event != models.EventReviewReminder || !reflect.DeepEqual(n.Recipients, []string{"u2"}) ||
+		n.Message != `PR "PR pr-3" has been waiting for your review for 5 hours` {
+		t.Errorf("reminder = %+v", n)
+	}
+}
+
+func TestUpdateTeamSettingsChecksEscalationPolicy(t *testing.T) {
+	tests := []struct {
+		name                       string
+		remind, escalate, reassign int
+		wantErr                    models.ErrorCode
+	}{
+		{"full", 4, 24, 48, ""},
+		{"reassign only", 0, 0, 48, ""},
+		{"out of order", 24, 4, 0, models.ErrValidation},
+		{"equal", 0, 24, 24, models.ErrValidation},
+		{"negative", -1, 0, 0, models.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := teamStorage(nil)
+			repo.TeamExistsFunc = func(ctx context.Context, teamName string) (bool, error) {
+				return true, nil
+			}
+			repo.UpsertTeamSettingsFunc = func(ctx context.Context, ts *models.TeamSettings) error {
+				return nil
+			}
+			_, err := newService(repo).UpdateTeamSettings(context.Background(), &models.TeamSettings{
+				TeamName:           "backend",
+				RemindAfterHours:   tt.remind,
+				EscalateAfterHours: tt.escalate,
+				ReassignAfterHours: tt.reassign,
+			})
+			if code := errorCode(err); code != tt.wantErr {
+				t.Errorf("error code = %q (%v), want %q", code, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestForecastReviewLoad(t *testing.T) {
+	repo := &repositorymock.StorageMock{
+		GetTeamActivityFunc: func(ctx context.Context, at time.Time, weeks int) ([]models.TeamActivity, error) {
+			if !at.Equal(now) || weeks != 4 {
+				t.Errorf("activity at %v over %d weeks, want %v over 4", at, weeks, now)
+			}
+			return []models.TeamActivity{
+				{TeamName: "backend", WeeklyPullRequests: []int{2, 4, 6, 8}, EligibleReviewers: 3, OpenReviews: 10},
+				{TeamName: "frontend", WeeklyPullRequests: []int{1, 0, 1, 0}, EligibleReviewers: 5, OpenReviews: 1},
+				{TeamName: "idle", WeeklyPullRequests: []int{0, 0, 0, 0}},
+			}, nil
+		},
+		GetTeamSettingsFunc: func(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+			if teamName == "frontend" {
+				return &models.TeamSettings{TeamName: teamName, MaxOpenReviews: 2}, nil
+			}
+			return nil, nil
+		},
+	}
+	svc := newService(repo)
+
+	forecast, err := svc.ForecastReviewLoad(context.Background(), "", 4)
+	if err != nil {
+		t.Fatalf("ForecastReviewLoad: %v", err)
+	}
+	if forecast.Method != models.ForecastMovingAverage || !forecast.From.Equal(now) || len(forecast.Teams) != 3 {
+		t.Fatalf("forecast = %+v", forecast)
+	}
+	backend, frontend, idle := forecast.Teams[0], forecast.Teams[1], forecast.Teams[2]
+	// Three reviewers leave nobody to replace either of two reviewers.
+	if backend.ExpectedPullRequests != 5 || backend.ExpectedReviews != 10 || backend.ProjectedOpenReviews != 20 ||
+		!backend.AtRisk || !reflect.DeepEqual(backend.Risks, []string{models.RiskTooFewReviewers}) {
+		t.Errorf("backend = %+v, want 5 PRs and too few reviewers", backend)
+	}
+	if frontend.ExpectedPullRequests != 0.5 || frontend.Capacity != 10 || frontend.ProjectedOpenReviews != 2 || frontend.AtRisk {
+		t.Errorf("frontend = %+v, want 0.5 PRs within a capacity of 10", frontend)
+	}
+	if idle.AtRisk {
+		t.Errorf("idle = %+v, want no risk without PRs", idle)
+	}
+
+	smoothed, err := svc.ForecastReviewLoad(context.Background(), models.ForecastExponentialSmoothing, 4)
+	if err != nil {
+		t.Fatalf("ForecastReviewLoad smoothing: %v", err)
+	}
+	// 2, then 3, 4.5 and 6.25 with half the weight on each new week.
+	if got := smoothed.Teams[0].ExpectedPullRequests; got != 6.25 {
+		t.Errorf("smoothed backend PRs = %v, want 6.25", got)
+	}
+
+	for _, bad := range []struct {
+		method models.ForecastMethod
+		weeks  int
+	}{{"median", 4}, {"", -1}, {"", 53}} {
+		if _, err := svc.ForecastReviewLoad(context.Background(), bad.method, bad.weeks); errorCode(err) != models.ErrValidation {
+			t.Errorf("ForecastReviewLoad(%q, %d) error = %v, want validation", bad.method, bad.weeks, err)
+		}
+	}
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	var replaced []models.Anomaly
+	repo := &repositorymock.StorageMock{
+		GetAssignmentCountsFunc: func(ctx context.Context, since time.Time) ([]models.AssignmentCount, error) {
+			if !since.Equal(now.Add(-14 * 24 * time.Hour)) {
+				t.Errorf("assignments since %v, want two weeks before %v", since, now)
+			}
+			return []models.AssignmentCount{
+				{TeamName: "backend", UserID: "u2", Assignments: 8},
+				{TeamName: "backend", UserID: "u3", Assignments: 2},
+				// Too few assignments to tell.
+				{TeamName: "frontend", UserID: "u5", Assignments: 3},
+			}, nil
+		},
+		GetTeamActivityFunc: func(ctx context.Context, at time.Time, weeks int) ([]models.TeamActivity, error) {
+			return []models.TeamActivity{
+				{TeamName: "backend", WeeklyMerges: []int{6, 4, 5, 5, 1}},
+				{TeamName: "frontend", WeeklyMerges: []int{1, 2, 1, 0, 0}},
+			}, nil
+		},
+		ReplaceAnomaliesFunc: func(ctx context.Context, anomalies []models.Anomaly, at time.Time) ([]models.Anomaly, error) {
+			replaced = anomalies
+			return anomalies[:1], nil
+		},
+		GetTeamSettingsFunc: func(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+			return nil, nil
+		},
+	}
+	notifier := &recordingNotifier{}
+	svc := newService(repo, service.WithNotifier(notifier), service.WithAnomalyThresholds(service.AnomalyThresholds{Notify: true}))
+
+	n, err := svc.DetectAnomalies(context.Background())
+	if err != nil {
+		t.Fatalf("DetectAnomalies: %v", err)
+	}
+	if n != 1 || len(replaced) != 2 {
+		t.Fatalf("DetectAnomalies = %d with findings %+v, want 1 new of 2", n, replaced)
+	}
+	if a := replaced[0]; a.Kind != models.AnomalyAssignmentConcentration || a.UserID != "u2" || a.Value != 0.8 ||
+		a.Message != "u2 got 8 of 10 review assignments of team backend in the last 14 days (80%)" {
+		t.Errorf("first finding = %+v, want u2's share", a)
+	}
+	if a := replaced[1]; a.Kind != models.AnomalyMergeDrop || a.TeamName != "backend" || a.Value != 0.8 ||
+		a.Message != "Team backend merged 1 PRs in the last week, 80% below its weekly average of 5" {
+		t.Errorf("second finding = %+v, want backend's merge drop", a)
+	}
+	if len(notifier.sent) != 1 || notifier.sent[0].Event != models.EventAnomalyDetected || notifier.sent[0].Message != replaced[0].Message {
+		t.Errorf("sent %+v, want the new finding only", notifier.sent)
+	}
+}
+
+func TestGetSeriesFillsEmptyBuckets(t *testing.T) {
+	var gotStep time.Duration
+	repo := &repositorymock.StorageMock{
+		GetSeriesFunc: func(ctx context.Context, metric models.SeriesMetric, from, to time.Time, step time.Duration) ([]models.SeriesPoint, error) {
+			gotStep = step
+			return []models.SeriesPoint{{Time: from.Add(step), Value: 3}}, nil
+		},
+	}
+	svc := newService(repo)
+
+	// Steps under a minute are widened.
+	points, err := svc.GetSeries(context.Background(), models.SeriesPullRequestsOpened, now, now.Add(3*time.Minute), time.Second)
+	if err != nil {
+		t.Fatalf("GetSeries: %v", err)
+	}
+	if gotStep != time.Minute {
+		t.Errorf("step = %v, want a minute", gotStep)
+	}
+	want := []models.SeriesPoint{
+		{Time: now, Value: 0},
+		{Time: now.Add(time.Minute), Value: 3},
+		{Time: now.Add(2 * time.Minute), Value: 0},
+	}
+	if !reflect.DeepEqual(points, want) {
+		t.Errorf("GetSeries = %+v, want %+v", points, want)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		metric   models.SeriesMetric
+		from, to time.Time
+	}{
+		{"unknown metric", "coffee", now, now.Add(time.Hour)},
+		{"empty range", models.SeriesPullRequestsMerged, now, now},
+	} {
+		if _, err := svc.GetSeries(context.Background(), tc.metric, tc.from, tc.to, time.Minute); errorCode(err) != models.ErrValidation {
+			t.Errorf("%s: error = %v, want %s", tc.name, err, models.ErrValidation)
+		}
+	}
+}
+
+func TestNotificationLocale(t *testing.T) {
+	locales := map[string]models.Locale{"backend": models.LocaleRussian, "frontend": ""}
+	repo := &repositorymock.StorageMock{
+		GetAssignmentCountsFunc: func(ctx context.Context, since time.Time) ([]models.AssignmentCount, error) {
+			return []models.AssignmentCount{
+				{TeamName: "backend", UserID: "u2", Assignments: 10},
+				{TeamName: "frontend", UserID: "u5", Assignments: 10},
+			}, nil
+		},
+		GetTeamActivityFunc: func(ctx context.Context, at time.Time, weeks int) ([]models.TeamActivity, error) {
+			return nil, nil
+		},
+		ReplaceAnomaliesFunc: func(ctx context.Context, anomalies []models.Anomaly, at time.Time) ([]models.Anomaly, error) {
+			return anomalies, nil
+		},
+		GetTeamSettingsFunc: func(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+			return &models.TeamSettings{TeamName: teamName, Locale: locales[teamName]}, nil
+		},
+		GetUserFunc: func(ctx context.Context, userID string) (*models.User, error) {
+			return &models.User{UserID: userID, TeamName: "backend"}, nil
+		},
+	}
+	notifier := &recordingNotifier{}
+	svc := newService(repo, service.WithNotifier(notifier), service.WithDefaultLocale(models.LocaleEnglish),
+		service.WithAnomalyThresholds(service.AnomalyThresholds{Notify: true}))
+
+	if _, err := svc.DetectAnomalies(context.Background()); err != nil {
+		t.Fatalf("DetectAnomalies: %v", err)
+	}
+	got := map[string]models.Locale{}
+	for _, n := range notifier.sent {
+		got[n.TeamName] = n.Locale
+	}
+	if want := map[string]models.Locale{"backend": models.LocaleRussian, "frontend": models.LocaleEnglish}; !reflect.DeepEqual(got, want) {
+		t.Errorf("notification locales = %v, want %v", got, want)
+	}
+
+	if locale, err := svc.UserLocale(context.Background(), "u2"); err != nil || locale != models.LocaleRussian {
+		t.Errorf("UserLocale = %q, %v, want ru", locale, err)
+	}
+}
+
+func TestCurrentMaintenanceIsCached(t *testing.T) {
+	stored := &models.Maintenance{Enabled: true, Message: "migrating"}
+	reads := 0
+	repo := &repositorymock.StorageMock{
+		GetMaintenanceFunc: func(ctx context.Context) (*models.Maintenance, error) {
+			reads++
+			return stored, nil
+		},
+	}
+	clock := service.NewFixedClock(now)
+	svc := newService(repo, service.WithClock(clock))
+	ctx := context.Background()
+
+	if m := svc.CurrentMaintenance(ctx); !m.Enabled || m.Message != "migrating" {
+		t.Fatalf("CurrentMaintenance = %+v, want the stored mode", m)
+	}
+	stored = nil
+	if m := svc.CurrentMaintenance(ctx); !m.Enabled || reads != 1 {
+		t.Errorf("CurrentMaintenance within the refresh = %+v after %d reads, want the cached mode", m, reads)
+	}
+	clock.Set(now.Add(time.Minute))
+	if m := svc.CurrentMaintenance(ctx); m.Enabled || reads != 2 {
+		t.Errorf("CurrentMaintenance after the refresh = %+v after %d reads, want it read again and off", m, reads)
+	}
+}
+
+func TestSchemaVersionGate(t *testing.T) {
+	version := 40
+	repo := &repositorymock.StorageMock{
+		GetMaintenanceFunc: func(ctx context.Context) (*models.Maintenance, error) {
+			return nil, nil
+		},
+		GetSchemaMigrationsFunc: func(ctx context.Context) ([]models.SchemaMigration, error) {
+			return []models.SchemaMigration{{Version: version - 1}, {Version: version}}, nil
+		},
+	}
+	svc := newService(repo, service.WithSchemaVersion(40))
+	ctx := context.Background()
+
+	if err := svc.CheckSchema(ctx); err != nil {
+		t.Errorf("CheckSchema on the required version: %v", err)
+	}
+	if m := svc.CurrentMaintenance(ctx); m.Enabled {
+		t.Errorf("CurrentMaintenance on the required version = %+v, want off", m)
+	}
+
+	version = 39
+	if err := svc.CheckSchema(ctx); err == nil {
+		t.Error("CheckSchema on an older version succeeded, want an error")
+	}
+
+	version = 41
+	if err := svc.CheckSchema(ctx); err != nil {
+		t.Errorf("CheckSchema on a newer version: %v", err)
+	}
+	svc = newService(repo, service.WithSchemaVersion(40))
+	if m := svc.CurrentMaintenance(ctx); !m.Enabled || !strings.Contains(m.Message, "41") {
+		t.Errorf("CurrentMaintenance on a newer version = %+v, want read-only", m)
+	}
+}
+
+func TestReassignDeletedReviewer(t *testing.T) {
+	// u5 was deleted and is no longer found; the replacement comes from the
+	// author's team.
+	repo := teamStorage(nil)
+	repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+		return &models.PullRequest{PullRequestID: "pr-1", AuthorID: "u1", Status: models.StatusOpen,
+			AssignedReviewers: []string{"u5", "u3"}, ShadowReviewers: []string{}}, nil
+	}
+	repo.GetAssignmentHistoryFunc = func(ctx context.Context, prID string) ([]models.AssignmentEvent, error) {
+		return nil, nil
+	}
+	repo.UpdatePullRequestFunc = func(ctx context.Context, pr *models.PullRequest) (bool, error) {
+		return true, nil
+	}
+
+	pr, newReviewer, err := newService(repo).ReassignReviewer(context.Background(), "pr-1", "u5")
+	if err != nil {
+		t.Fatalf("ReassignReviewer: %v", err)
+	}
+	if newReviewer != "u2" && newReviewer != "u4" {
+		t.Errorf("new reviewer = %q, want u2 or u4", newReviewer)
+	}
+	if pr.AssignedReviewers[0] != newReviewer {
+		t.Errorf("AssignedReviewers = %v, want %s in place of u5", pr.AssignedReviewers, newReviewer)
+	}
+}
+
+func TestReassignRereadsUnderAuthorLock(t *testing.T) {
+	// The old reviewer is from another team; the PR is merged between the
+	// first read and the one under the lock.
+	repo := teamStorage(nil)
+	teamUser := repo.GetUserFunc
+	repo.GetUserFunc = func(ctx context.Context, userID string) (*models.User, error) {
+		if userID == "u5" {
+			return &models.User{UserID: "u5", TeamName: "frontend", IsActive: true}, nil
+		}
+		return teamUser(ctx, userID)
+	}
+	var locked []string
+	repo.WithTeamLockFunc = func(ctx context.Context, teamName string, fn func(repo repository.Storage) error) error {
+		locked = append(locked, teamName)
+		return fn(repo)
+	}
+	repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+		status := models.StatusOpen
+		if len(locked) > 0 {
+			status = models.StatusMerged
+		}
+		return &models.PullRequest{PullRequestID: "pr-1", AuthorID: "u1", Status: status,
+			AssignedReviewers: []string{"u5", "u3"}, ShadowReviewers: []string{}}, nil
+	}
+
+	_, _, err := newService(repo).ReassignReviewer(context.Background(), "pr-1", "u5")
+	if errorCode(err) != models.ErrPRMerged {
+		t.Errorf("ReassignReviewer = %v, want PR_MERGED", err)
+	}
+	if !reflect.DeepEqual(locked, []string{"backend"}) {
+		t.Errorf("locked teams = %v, want the author's team", locked)
+	}
+	if n := len(repo.UpdatePullRequestCalls()); n != 0 {
+		t.Errorf("UpdatePullRequest called %d times for a merged PR", n)
+	}
+}
+
+func TestRestoreUser(t *testing.T) {
+	var live map[string]bool
+	repo := &repositorymock.StorageMock{
+		RestoreUserFunc: func(ctx context.Context, userID string) (bool, error) {
+			if live[userID] {
+				return false, nil
+			}
+			live[userID] = userID == "u1"
+			return live[userID], nil
+		},
+		GetUserFunc: func(ctx context.Context, userID string) (*models.User, error) {
+			if !live[userID] {
+				return nil, nil
+			}
+			return &models.User{UserID: userID, TeamName: "backend"}, nil
+		},
+		RefreshStatisticsFunc: func(ctx context.Context) error {
+			return nil
+		},
+	}
+	svc := newService(repo)
+	ctx := context.Background()
+
+	live = map[string]bool{}
+	if _, err := svc.RestoreUser(ctx, "u2"); errorCode(err) != models.ErrNotFound {
+		t.Errorf("RestoreUser of an unknown user = %v, want NOT_FOUND", err)
+	}
+	for i := 0; i < 2; i++ {
+		user, err := svc.RestoreUser(ctx, "u1")
+		if err != nil || user == nil || user.UserID != "u1" {
+			t.Fatalf("RestoreUser call %d = %+v, %v, want u1", i+1, user, err)
+		}
+	}
+	if n := len(repo.RefreshStatisticsCalls()); n != 1 {
+		t.Errorf("statistics refreshed %d times, want once for the one restore", n)
+	}
+}
+
+func TestLookupUser(t *testing.T) {
+	alice := &models.User{UserID: "u1", Username: "Alice", TeamName: "backend"}
+	repo := &repositorymock.StorageMock{
+		GetUserByUsernameFunc: func(ctx context.Context, username string) (*models.User, error) {
+			if strings.EqualFold(username, "alice") {
+				return alice, nil
+			}
+			return nil, nil
+		},
+		FindUserByIdentityFunc: func(ctx context.Context, provider models.IdentityProvider, externalID string) (*models.User, error) {
+			if provider == models.ProviderEmail && externalID == "alice@example.com" {
+				return alice, nil
+			}
+			return nil, nil
+		},
+	}
+	svc := newService(repo)
+	ctx := context.Background()
+
+	tests := []struct {
+		name, username, email string
+		wantErr               models.ErrorCode
+	}{
+		{"username", " alice ", "", ""},
+		{"email", "", "Alice@Example.com", ""},
+		{"unknown", "bob", "", models.ErrNotFound},
+		{"neither", "", "", models.ErrValidation},
+		{"both", "alice", "alice@example.com", models.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := svc.LookupUser(ctx, tt.username, tt.email)
+			if code := errorCode(err); code != tt.wantErr {
+				t.Fatalf("error code = %q (%v), want %q", code, err, tt.wantErr)
+			}
+			if tt.wantErr == "" && user.UserID != "u1" {
+				t.Errorf("LookupUser = %+v, want u1", user)
+			}
+		})
+	}
+}
+
+// fakeSender renders every notification to the same URL and fails posts
+// with status until failures run out.
+type fakeSender struct {
+	status   int
+	failures int
+	sent     []int64
+}
+
+func (f *fakeSender) Render(n models.Notification) (*models.WebhookDelivery, error) {
+	return &models.WebhookDelivery{Event: n.Event, TeamName: n.TeamName, URL: "http://chat", Payload: []byte(`{}`)}, nil
+}
+
+func (f *fakeSender) Send(ctx context.Context, d models.WebhookDelivery) (int, error) {
+	f.sent = append(f.sent, d.ID)
+	if f.failures > 0 {
+		f.failures--
+		return f.status, fmt.Errorf("unexpected status %d", f.status)
+	}
+	return http.StatusOK, nil
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	var recorded []models.WebhookDelivery
+	repo := &repositorymock.StorageMock{
+		AddWebhookDeliveryFunc: func(ctx context.Context, d *models.WebhookDelivery) error {
+			d.ID = 1
+			return nil
+		},
+		RecordWebhookAttemptFunc: func(ctx context.Context, d models.WebhookDelivery) error {
+			recorded = append(recorded, d)
+			return nil
+		},
+		ClaimWebhookDeliveriesFunc: func(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+			return []models.WebhookDelivery{recorded[len(recorded)-1]}, nil
+		},
+		PurgeWebhookDeliveriesFunc: func(ctx context.Context, cutoff time.Time) (int64, error) {
+			return 0, nil
+		},
+		// One reviewer due a reminder.
+		GetIdleReviewsFunc: func(ctx context.Context) ([]models.IdleReview, error) {
+			return []models.IdleReview{{PullRequestID: "pr-1", TeamName: "backend", UserID: "u2", AssignedAt: now.Add(-5 * time.Hour)}}, nil
+		},
+		GetTeamSettingsFunc: func(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+			return &models.TeamSettings{TeamName: teamName, RemindAfterHours: 4}, nil
+		},
+		GetUserIdentitiesFunc: func(ctx context.Context, userIDs []string) ([]models.UserIdentity, error) {
+			return nil, nil
+		},
+		AddEscalationFunc: func(ctx context.Context, e *models.Escalation) error {
+			return nil
+		},
+	}
+	sender := &fakeSender{status: http.StatusBadGateway, failures: 2}
+	svc := newService(repo, service.WithWebhookDeliveries(sender, 2))
+	ctx := context.Background()
+
+	// A failed first attempt is the delivery's to retry, not the caller's.
+	if n, err := svc.EscalateIdleReviews(ctx); err != nil || n != 1 {
+		t.Fatalf("EscalateIdleReviews = %d, %v, want the reminder sent", n, err)
+	}
+	if d := recorded[0]; d.Status != models.DeliveryPending || d.Attempts != 1 || d.LastStatus != http.StatusBadGateway ||
+		!d.NextAttemptAt.Equal(now.Add(30*time.Second)) {
+		t.Errorf("after first attempt = %+v, want pending retry in 30s", d)
+	}
+
+	if n, err := svc.DeliverWebhooks(ctx); err != nil || n != 0 {
+		t.Fatalf("DeliverWebhooks = %d, %v, want 0", n, err)
+	}
+	if d := recorded[1]; d.Status != models.DeliveryDead || d.Attempts != 2 || d.NextAttemptAt != nil {
+		t.Errorf("after second attempt = %+v, want dead", d)
+	}
+
+	repo.RetryWebhookDeliveryFunc = func(ctx context.Context, id int64, nextAttemptAt time.Time) (bool, error) {
+		return true, nil
+	}
+	repo.GetWebhookDeliveryFunc = func(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+		d := recorded[len(recorded)-1]
+		d.Status = models.DeliveryPending
+		return &d, nil
+	}
+	d, err := svc.RetryWebhookDelivery(ctx, 1)
+	if err != nil {
+		t.Fatalf("RetryWebhookDelivery: %v", err)
+	}
+	if d.Status != models.DeliveryDelivered || d.Attempts != 3 || d.DeliveredAt == nil || d.LastError != "" {
+		t.Errorf("after retry = %+v, want delivered", d)
+	}
+	if len(sender.sent) != 3 || sender.sent[2] != 1 {
+		t.Errorf("sent = %v, want delivery 1 three times", sender.sent)
+	}
+}
+
+func TestRetryWebhookDeliveryNotDead(t *testing.T) {
+	repo := &repositorymock.StorageMock{
+		RetryWebhookDeliveryFunc: func(ctx context.Context, id int64, nextAttemptAt time.Time) (bool, error) {
+			return false, nil
+		},
+		GetWebhookDeliveryFunc: func(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+			if id == 1 {
+				return &models.WebhookDelivery{ID: 1, Status: models.DeliveryDelivered}, nil
+			}
+			return nil, nil
+		},
+	}
+	svc := newService(repo, service.WithWebhookDeliveries(&fakeSender{}, 0))
+
+	for id, want := range map[int64]models.ErrorCode{1: models.ErrDeliveryNotDead, 2: models.ErrNotFound} {
+		if _, err := svc.RetryWebhookDelivery(context.Background(), id); errorCode(err) != want {
+			t.Errorf("retry of %d: error code = %q (%v), want %q", id, errorCode(err), err, want)
+		}
+	}
+}
+
+func TestRebalanceWeighsReviewSize(t *testing.T) {
+	// By count u2 is only one review ahead of the others, but the size of
+	// their large PR makes moving their small one worth it.
+	repo := teamStorage(map[string][]int{
+		"u1": {10},
+		"u2": {3000, 10},
+		"u3": {10},
+		"u4": {10},
+	})
+	repo.TeamExistsFunc = func(ctx context.Context, teamName string) (bool, error) {
+		return true, nil
+	}
+	repo.GetOpenPullRequestsByTeamFunc = func(ctx context.Context, teamName string) ([]models.PullRequest, error) {
+		return []models.PullRequest{
+			{PullRequestID: "pr-big", AuthorID: "u9", Status: models.StatusOpen, AssignedReviewers: []string{"u2"}, LinesChanged: 3000},
+			{PullRequestID: "pr-a", AuthorID: "u9", Status: models.StatusOpen, AssignedReviewers: []string{"u2"}, LinesChanged: 10},
+			{PullRequestID: "pr-b", AuthorID: "u9", Status: models.StatusOpen, AssignedReviewers: []string{"u1"}, LinesChanged: 10},
+			{PullRequestID: "pr-c", AuthorID: "u9", Status: models.StatusOpen, AssignedReviewers: []string{"u3"}, LinesChanged: 10},
+			{PullRequestID: "pr-d", AuthorID: "u9", Status: models.StatusOpen, AssignedReviewers: []string{"u4"}, LinesChanged: 10},
+		}, nil
+	}
+	svc := newService(repo, service.WithSizeBuckets([]service.SizeBucket{
+		{MaxLines: 100, Weight: 1},
+		{MaxLines: 5000, Weight: 5},
+	}))
+
+	result, err := svc.RebalanceTeam(context.Background(), "backend", true)
+	if err != nil {
+		t.Fatalf("RebalanceTeam: %v", err)
+	}
+	want := models.RebalanceMove{PullRequestID: "pr-a", FromUserID: "u2", ToUserID: "u4"}
+	if len(result.Moves) != 1 || result.Moves[0] != want {
+		t.Fatalf("Moves = %+v, want [%+v]", result.Moves, want)
+	}
+	if result.Before["u2"] != 2 || result.After["u2"] != 1 || result.After["u4"] != 2 {
+		t.Errorf("Before = %v, After = %v, want u2 2 -> 1 and u4 -> 2", result.Before, result.After)
+	}
+}
+
+func TestSetPullRequestLabels(t *testing.T) {
+	stored := &models.PullRequest{PullRequestID: "pr-1", AuthorID: "u1", Status: models.StatusOpen, Labels: []string{"backend", "hold"}}
+	repo := teamStorage(nil)
+	repo.GetPullRequestFunc = func(ctx context.Context, prID string) (*models.PullRequest, error) {
+		pr := *stored
+		return &pr, nil
+	}
+	repo.UpdatePullRequestDetailsFunc = func(ctx context.Context, pr *models.PullRequest) (bool, error) {
+		stored = pr
+		return true, nil
+	}
+	events := &recordingPublisher{}
+	svc := newService(repo, service.WithEventPublisher(events))
+	ctx := context.Background()
+
+	// The same labels in another order change nothing.
+	if _, err := svc.SetPullRequestLabels(ctx, "pr-1", []string{"Hold", "backend"}); err != nil {
+		t.Fatalf("SetPullRequestLabels unchanged: %v", err)
+	}
+	if len(repo.UpdatePullRequestDetailsCalls()) != 0 || len(events.events) != 0 {
+		t.Fatalf("unchanged labels saved or published")
+	}
+
+	pr, err := svc.SetPullRequestLabels(ctx, "pr-1", []string{"backend"})
+	if err != nil {
+		t.Fatalf("SetPullRequestLabels: %v", err)
+	}
+	if len(pr.Labels) != 1 || pr.Labels[0] != "backend" {
+		t.Errorf("Labels = %v, want [backend]", pr.Labels)
+	}
+	if len(events.events) != 1 || events.events[0].Type != models.EventPRUpdated ||
+		len(events.events[0].Changes) != 1 || events.events[0].Changes[0].Field != "labels" {
+		t.Errorf("events = %+v, want one pr.updated with the labels change", events.events)
+	}
+}
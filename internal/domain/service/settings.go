@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"text/template"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+const maxReviewersPerPR = 10
+
+func (s *Service) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	if err := s.requireTeam(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.repo.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = &models.TeamSettings{TeamName: teamName}
+	}
+	return settings, nil
+}
+
+func (s *Service) UpdateTeamSettings(ctx context.Context, settings *models.TeamSettings) (*models.TeamSettings, error) {
+	if msg := s.validateSettings(settings); msg != "" {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: msg,
+		}
+	}
+	if err := s.requireTeam(ctx, settings.TeamName); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpsertTeamSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (s *Service) validateSettings(settings *models.TeamSettings) string {
+	switch {
+	case settings.TeamName == "":
+		return "team_name is required"
+	case settings.ReviewerCount < 0 || settings.ReviewerCount > maxReviewersPerPR:
+		return "reviewer_count must be between 0 and 10"
+	case settings.AssignmentStrategy != "" && s.strategies[settings.AssignmentStrategy] == nil:
+		return "unknown assignment_strategy"
+	case settings.MaxOpenReviews < 0:
+		return "max_open_reviews must not be negative"
+	case settings.StaleThresholdHours < 0:
+		return "stale_threshold_hours must not be negative"
+	case settings.RemindAfterHours < 0 || settings.EscalateAfterHours < 0 || settings.ReassignAfterHours < 0:
+		return "escalation hours must not be negative"
+	case !escalationOrdered(settings):
+		return "remind_after_hours, escalate_after_hours and reassign_after_hours must increase"
+	}
+	if settings.NotificationChannel != "" && !validChannel(settings.NotificationChannel) {
+		return "notification_channel must be an http(s) URL"
+	}
+	if settings.NotificationProvider != "" && !settings.NotificationProvider.Valid() {
+		return "notification_provider must be webhook, slack, msteams or mattermost"
+	}
+	if settings.Locale != "" && !settings.Locale.Valid() {
+		return "locale must be en or ru"
+	}
+	if settings.NotificationTemplate != "" {
+		if err := checkNotificationTemplate(settings.NotificationTemplate); err != nil {
+			return "notification_template: " + err.Error()
+		}
+	}
+	return ""
+}
+
+// validChannel reports whether channel is a webhook URL notifications can
+// be posted to.
+func validChannel(channel string) bool {
+	u, err := url.Parse(channel)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// checkNotificationTemplate parses a notification template and renders it
+// for a sample notification, which also catches unknown fields.
+func checkNotificationTemplate(text string) error {
+	tmpl, err := template.New("notification").Parse(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(io.Discard, models.NotificationView{
+		Notification: models.Notification{
+			Event:         models.EventPullRequestStale,
+			TeamName:      "backend",
+			PullRequestID: "pr-1",
+			Recipients:    []string{"u1"},
+			Message:       "PR is stale",
+			CreatedAt:     time.Now(),
+		},
+		Mentions: []string{"@u1"},
+	})
+}
+
+func (s *Service) requireTeam(ctx context.Context, teamName string) error {
+	exists, err := s.repo.TeamExists(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "team not found",
+		}
+	}
+	return nil
+}
+
+// requireTeamMembers checks that every user in userIDs belongs to the team,
+// looking them all up at once.
+func (s *Service) requireTeamMembers(ctx context.Context, teamName string, userIDs []string) error {
+	users, err := s.repo.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	inTeam := make(map[string]bool, len(users))
+	for _, u := range users {
+		inTeam[u.UserID] = u.TeamName == teamName
+	}
+
+	for _, userID := range userIDs {
+		if !inTeam[userID] {
+			return &ServiceError{
+				Code:    models.ErrNotFound,
+				Message: "user " + userID + " not found in team",
+			}
+		}
+	}
+	return nil
+}
+
+// effectiveSettings returns the team's settings with defaults filled in for
+// everything the team did not override.
+func effectiveSettings(ctx context.Context, repo repository.Storage, teamName string) (models.TeamSettings, error) {
+	settings, err := repo.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		return models.TeamSettings{}, err
+	}
+	if settings == nil {
+		settings = &models.TeamSettings{TeamName: teamName}
+	}
+	if settings.ReviewerCount == 0 {
+		settings.ReviewerCount = reviewersPerPR
+	}
+	return *settings, nil
+}
+
+// reviewLimit is the open review limit that applies when assigning reviewers
+// to pr: URGENT PRs are not held back by it.
+func reviewLimit(settings models.TeamSettings, pr *models.PullRequest) int {
+	if pr.Priority == models.PriorityUrgent {
+		return 0
+	}
+	return settings.MaxOpenReviews
+}
+
+// withinCapacity drops candidates that already have max open reviews. A
+// zero max means no limit.
+func withinCapacity(ctx context.Context, repo repository.Storage, candidates []models.User, max int) ([]models.User, error) {
+	if max <= 0 || len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	counts, err := repo.GetReviewCounts(ctx, userIDs(candidates))
+	if err != nil {
+		return nil, err
+	}
+	result := []models.User{}
+	for _, c := range candidates {
+		if counts[c.UserID] < max {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
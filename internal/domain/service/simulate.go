@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// SimulateAssignmentParams describe a hypothetical PR like
+// CreatePullRequestParams do. The non-nil settings fields replace the
+// team's for this simulation only, and Excluded users are left out as if an
+// exclusion rule kept them off the author's PRs.
+type SimulateAssignmentParams struct {
+	AuthorID           string
+	ChangedPaths       []string
+	Labels             []string
+	Repository         string
+	Priority           models.PullRequestPriority
+	LinesChanged       int
+	PreferredReviewers []string
+
+	ReviewerCount      *int
+	AssignmentStrategy *string
+	MaxOpenReviews     *int
+	PreferWorkingHours *bool
+	RequireSenior      *bool
+	PeerReviewOnly     *bool
+	Excluded           []string
+}
+
+// SimulateAssignment runs the assignment pipeline of CreatePullRequest for
+// a PR that is not created, so team leads can preview the effect of
+// settings changes. It takes no team lock and writes nothing: the round
+// robin cursor is read but not moved.
+func (s *Service) SimulateAssignment(ctx context.Context, params SimulateAssignmentParams) (*models.AssignmentSimulation, error) {
+	priority := params.Priority
+	if priority == "" {
+		priority = models.PriorityNormal
+	}
+	if !priority.Valid() {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "priority must be LOW, NORMAL or URGENT",
+		}
+	}
+
+	author, err := s.repo.GetUser(ctx, params.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "author not found",
+		}
+	}
+
+	repo := &simulationStorage{Storage: s.repo, authorID: author.UserID, excluded: uniqueStrings(params.Excluded)}
+	teamMembers, err := repo.GetUsersByTeam(ctx, author.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := pullRequestSettings(ctx, repo, author.TeamName, normalizeRepository(params.Repository))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.overrideSettings(&settings, params); err != nil {
+		return nil, err
+	}
+
+	pr := &models.PullRequest{
+		AuthorID:           author.UserID,
+		Status:             models.StatusOpen,
+		Priority:           priority,
+		LinesChanged:       params.LinesChanged,
+		ChangedPaths:       normalizePaths(params.ChangedPaths),
+		Labels:             normalizeLabels(params.Labels),
+		Repository:         normalizeRepository(params.Repository),
+		RequestedReviewers: uniqueStrings(params.PreferredReviewers),
+	}
+	if err := validatePreferred(ctx, repo, settings, teamMembers, author.UserID, pr.RequestedReviewers); err != nil {
+		return nil, err
+	}
+	if err := s.assignReviewers(ctx, repo, settings, teamMembers, pr); err != nil {
+		return nil, err
+	}
+
+	reviewers := []models.User{}
+	for _, member := range teamMembers {
+		if member.IsActive && member.UserID != author.UserID {
+			reviewers = append(reviewers, member)
+		}
+	}
+	counts, err := repo.GetReviewCounts(ctx, userIDs(reviewers))
+	if err != nil {
+		return nil, err
+	}
+	openReviews := make(map[string]int, len(reviewers))
+	for _, r := range reviewers {
+		openReviews[r.UserID] = counts[r.UserID]
+	}
+
+	reasons := pr.AssignmentReasons
+	if reasons == nil {
+		reasons = map[string]models.AssignmentReason{}
+	}
+	return &models.AssignmentSimulation{
+		TeamName:          author.TeamName,
+		Settings:          settings,
+		AssignedReviewers: pr.AssignedReviewers,
+		ShadowReviewers:   pr.ShadowReviewers,
+		AssignmentReasons: reasons,
+		OpenReviews:       openReviews,
+	}, nil
+}
+
+// overrideSettings applies the settings overrides of params, validating
+// them as UpdateTeamSettings would.
+func (s *Service) overrideSettings(settings *models.TeamSettings, params SimulateAssignmentParams) error {
+	if params.ReviewerCount != nil {
+		settings.ReviewerCount = *params.ReviewerCount
+	}
+	if params.AssignmentStrategy != nil {
+		settings.AssignmentStrategy = *params.AssignmentStrategy
+	}
+	if params.MaxOpenReviews != nil {
+		settings.MaxOpenReviews = *params.MaxOpenReviews
+	}
+	if params.PreferWorkingHours != nil {
+		settings.PreferWorkingHours = *params.PreferWorkingHours
+	}
+	if params.RequireSenior != nil {
+		settings.RequireSenior = *params.RequireSenior
+	}
+	if params.PeerReviewOnly != nil {
+		settings.PeerReviewOnly = *params.PeerReviewOnly
+	}
+
+	if msg := s.validateSettings(settings); msg != "" {
+		return &ServiceError{
+			Code:    models.ErrValidation,
+			Message: msg,
+		}
+	}
+	if settings.ReviewerCount == 0 {
+		settings.ReviewerCount = reviewersPerPR
+	}
+	return nil
+}
+
+// simulationStorage keeps SimulateAssignment from writing: it drops the
+// round robin cursor update and adds the simulated exclusions to the
+// team's rules.
+type simulationStorage struct {
+	repository.Storage
+
+	authorID string
+	excluded []string
+}
+
+func (s *simulationStorage) SetRotationCursor(context.Context, string, string) error {
+	return nil
+}
+
+func (s *simulationStorage) GetExclusionRules(ctx context.Context, teamName string) ([]models.ExclusionRule, error) {
+	rules, err := s.Storage.GetExclusionRules(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range s.excluded {
+		rules = append(rules, models.ExclusionRule{TeamName: teamName, ReviewerID: userID, AuthorID: s.authorID})
+	}
+	return rules, nil
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// SizeBucket is how much an open review of a PR with at most MaxLines
+// changed lines adds to the reviewer's load.
+type SizeBucket struct {
+	MaxLines int
+	Weight   float64
+}
+
+// WithSizeBuckets makes load-based selection weigh open reviews by PR size.
+// Buckets must be sorted by MaxLines; PRs larger than the last bucket weigh
+// as much as it does. Without buckets every open review counts 1.
+func WithSizeBuckets(buckets []SizeBucket) Option {
+	return func(s *Service) {
+		s.sizeBuckets = append([]SizeBucket(nil), buckets...)
+	}
+}
+
+// reviewSize is the load an open review of a PR with the given number of
+// changed lines adds. PRs of unknown size count 1.
+func (s *Service) reviewSize(lines int) float64 {
+	if lines <= 0 || len(s.sizeBuckets) == 0 {
+		return 1
+	}
+	for _, bucket := range s.sizeBuckets {
+		if lines <= bucket.MaxLines {
+			return bucket.Weight
+		}
+	}
+	return s.sizeBuckets[len(s.sizeBuckets)-1].Weight
+}
+
+// reviewLoads sums the sizes of each user's open reviews and also returns
+// how many open reviews each user has.
+func reviewLoads(ctx context.Context, repo repository.Storage, ids []string, size func(lines int) float64) (map[string]float64, map[string]int, error) {
+	sizes, err := repo.GetReviewSizes(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	loads := make(map[string]float64, len(ids))
+	counts := make(map[string]int, len(ids))
+	for _, id := range ids {
+		for _, lines := range sizes[id] {
+			loads[id] += size(lines)
+		}
+		counts[id] = len(sizes[id])
+	}
+	return loads, counts, nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// WithNotifier sets where escalations such as stale PR alerts are sent.
+// Without it notifications are dropped.
+func WithNotifier(notifier repository.Notifier) Option {
+	return func(s *Service) {
+		s.notifier = notifier
+	}
+}
+
+// WithStaleAutoReassign makes DetectStalePullRequests replace the reviewers
+// of newly stale PRs before notifying about them.
+func WithStaleAutoReassign(enabled bool) Option {
+	return func(s *Service) {
+		s.SetStaleAutoReassign(enabled)
+	}
+}
+
+// SetStaleAutoReassign changes the WithStaleAutoReassign setting at runtime.
+func (s *Service) SetStaleAutoReassign(enabled bool) {
+	s.staleAutoReassign.Store(enabled)
+}
+
+func (s *Service) GetStalePullRequests(ctx context.Context) ([]models.StalePullRequest, error) {
+	return s.repo.GetStalePullRequests(ctx)
+}
+
+// DetectStalePullRequests flags open PRs created more than the team's stale
+// threshold ago, olderThan for teams without one.
+// Every PR is escalated once, when it is first flagged: its reviewers are
+// optionally reassigned and a notification is sent to the reviewers. It
+// returns the number of newly flagged PRs.
+func (s *Service) DetectStalePullRequests(ctx context.Context, olderThan time.Duration) (int, error) {
+	ids, err := s.repo.MarkStalePullRequests(ctx, s.clock.Now(), olderThan)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	flagged := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		flagged[id] = true
+	}
+
+	stale, err := s.repo.GetStalePullRequests(ctx)
+	if err != nil {
+		return len(ids), err
+	}
+
+	var errs []error
+	channels := make(map[string]models.TeamSettings)
+	for _, pr := range stale {
+		if !flagged[pr.PullRequestID] {
+			continue
+		}
+		if s.staleAutoReassign.Load() {
+			pr.AssignedReviewers = s.reassignStale(ctx, pr)
+		}
+
+		channel, ok := channels[pr.TeamName]
+		if !ok {
+			settings, err := s.repo.GetTeamSettings(ctx, pr.TeamName)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if settings != nil {
+				channel = *settings
+			}
+			channels[pr.TeamName] = channel
+		}
+
+		err := s.notify(ctx, models.Notification{
+			Event:         models.EventPullRequestStale,
+			TeamName:      pr.TeamName,
+			PullRequestID: pr.PullRequestID,
+			Channel:       channel.NotificationChannel,
+			Provider:      channel.NotificationProvider,
+			Template:      channel.NotificationTemplate,
+			Locale:        s.teamLocale(channel),
+			Recipients:    pr.AssignedReviewers,
+			Message:       fmt.Sprintf("PR %q is stale and still waiting for review", pr.PullRequestName),
+			CreatedAt:     s.clock.Now(),
+			Identities:    s.recipientIdentities(ctx, pr.AssignedReviewers),
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notify about %s: %w", pr.PullRequestID, err))
+		}
+	}
+
+	return len(ids), errors.Join(errs...)
+}
+
+// reassignStale replaces every reviewer of a stale PR it can find a
+// replacement for and returns the resulting reviewer list.
+func (s *Service) reassignStale(ctx context.Context, pr models.StalePullRequest) []string {
+	reviewers := pr.AssignedReviewers
+	users, err := s.repo.GetUsersByIDs(ctx, pr.AssignedReviewers)
+	if err != nil {
+		log.Printf("Stale PR %s: cannot load reviewers: %v", pr.PullRequestID, err)
+		return reviewers
+	}
+	known := make(map[string]models.User, len(users))
+	for _, u := range users {
+		known[u.UserID] = u
+	}
+
+	for _, reviewerID := range pr.AssignedReviewers {
+		updated, _, err := s.reassignReviewer(ctx, pr.PullRequestID, reviewerID, known)
+		if err != nil {
+			log.Printf("Stale PR %s: cannot reassign %s: %v", pr.PullRequestID, reviewerID, err)
+			continue
+		}
+		reviewers = updated.AssignedReviewers
+	}
+	return reviewers
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// WithStatisticsMaxAge marks statistics computed more than maxAge ago as
+// stale. Zero never marks them stale.
+func WithStatisticsMaxAge(maxAge time.Duration) Option {
+	return func(s *Service) {
+		s.statisticsMaxAge = maxAge
+	}
+}
+
+// GetStatistics returns the statistics as of their last refresh.
+func (s *Service) GetStatistics(ctx context.Context) (*models.Statistics, error) {
+	stats, err := s.repo.GetStatistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.statisticsMaxAge > 0 {
+		stats.Stale = stats.ComputedAt == nil || s.clock.Now().Sub(*stats.ComputedAt) > s.statisticsMaxAge
+	}
+	return stats, nil
+}
+
+// RefreshStatistics recomputes the statistics and returns the fresh figures.
+func (s *Service) RefreshStatistics(ctx context.Context) (*models.Statistics, error) {
+	if err := s.repo.RefreshStatistics(ctx); err != nil {
+		return nil, err
+	}
+	return s.GetStatistics(ctx)
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+const reviewersPerPR = 2
+
+const (
+	defaultReviewWeight = 1
+	maxReviewWeight     = 10
+)
+
+const (
+	StrategyLeastLoaded = "least_loaded"
+	StrategyExpertise   = "expertise"
+	StrategyRoundRobin  = "round_robin"
+)
+
+// AssignmentRequest is the input of an AssignmentStrategy. Candidates are
+// already filtered down to users allowed to review the PR.
+type AssignmentRequest struct {
+	TeamName     string
+	Candidates   []models.User
+	Count        int
+	ChangedPaths []string
+}
+
+// Decision is one reviewer picked by the assignment pipeline and why.
+type Decision struct {
+	UserID string
+	Reason models.AssignmentReason
+}
+
+// AssignmentStrategy picks up to req.Count reviewers out of req.Candidates.
+// repo is the storage the caller is currently working with (it may be bound
+// to a locked transaction) and must be used for any lookups.
+type AssignmentStrategy interface {
+	Name() string
+	SelectReviewers(ctx context.Context, repo repository.Storage, req AssignmentRequest) ([]Decision, error)
+}
+
+// defaultStrategies builds the strategies; size weighs an open review by the
+// lines its PR changed.
+func defaultStrategies(rng Rand, size func(lines int) float64) map[string]AssignmentStrategy {
+	leastLoaded := &leastLoadedStrategy{rng: rng, size: size}
+	return map[string]AssignmentStrategy{
+		StrategyLeastLoaded: leastLoaded,
+		StrategyExpertise:   &expertiseStrategy{fallback: leastLoaded, size: size},
+		StrategyRoundRobin:  roundRobinStrategy{},
+	}
+}
+
+// leastLoadedStrategy prefers candidates with the lowest weighted load,
+// breaking ties by user_id so results are stable.
+type leastLoadedStrategy struct {
+	rng  Rand
+	size func(lines int) float64
+}
+
+func (st *leastLoadedStrategy) Name() string {
+	return StrategyLeastLoaded
+}
+
+func (st *leastLoadedStrategy) SelectReviewers(ctx context.Context, repo repository.Storage, req AssignmentRequest) ([]Decision, error) {
+	candidates := append([]models.User(nil), req.Candidates...)
+
+	loads, _, err := reviewLoads(ctx, repo, userIDs(candidates), st.size)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return randomSelection(st.rng, candidates, req.Count), nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		loadI := weightedLoad(loads[candidates[i].UserID], candidates[i])
+		loadJ := weightedLoad(loads[candidates[j].UserID], candidates[j])
+		if loadI != loadJ {
+			return loadI < loadJ
+		}
+		return candidates[i].UserID < candidates[j].UserID
+	})
+
+	reviewers := []Decision{}
+	for i := 0; i < len(candidates) && i < req.Count; i++ {
+		reviewers = append(reviewers, Decision{UserID: candidates[i].UserID, Reason: models.ReasonLeastLoaded})
+	}
+
+	return reviewers, nil
+}
+
+// expertiseStrategy prefers candidates who authored or reviewed earlier PRs
+// touching the same files or directories, ordered by expertise score and
+// then by weighted load. Slots no expert can fill go to the fallback strategy.
+type expertiseStrategy struct {
+	fallback AssignmentStrategy
+	size     func(lines int) float64
+}
+
+func (st *expertiseStrategy) Name() string {
+	return StrategyExpertise
+}
+
+func (st *expertiseStrategy) SelectReviewers(ctx context.Context, repo repository.Storage, req AssignmentRequest) ([]Decision, error) {
+	if len(req.ChangedPaths) == 0 {
+		return st.fallback.SelectReviewers(ctx, repo, req)
+	}
+
+	ids := userIDs(req.Candidates)
+	scores, err := repo.GetPathExpertise(ctx, req.ChangedPaths, ids)
+	if err != nil {
+		return nil, err
+	}
+	loads, _, err := reviewLoads(ctx, repo, ids, st.size)
+	if err != nil {
+		return nil, err
+	}
+
+	experts := []models.User{}
+	others := []models.User{}
+	for _, c := range req.Candidates {
+		if scores[c.UserID] > 0 {
+			experts = append(experts, c)
+		} else {
+			others = append(others, c)
+		}
+	}
+
+	sort.Slice(experts, func(i, j int) bool {
+		a, b := experts[i].UserID, experts[j].UserID
+		if scores[a] != scores[b] {
+			return scores[a] > scores[b]
+		}
+		loadA := weightedLoad(loads[a], experts[i])
+		loadB := weightedLoad(loads[b], experts[j])
+		if loadA != loadB {
+			return loadA < loadB
+		}
+		return a < b
+	})
+
+	reviewers := []Decision{}
+	for i := 0; i < len(experts) && i < req.Count; i++ {
+		reviewers = append(reviewers, Decision{UserID: experts[i].UserID, Reason: models.ReasonExpertise})
+	}
+
+	if remaining := req.Count - len(reviewers); remaining > 0 && len(others) > 0 {
+		rest, err := st.fallback.SelectReviewers(ctx, repo, AssignmentRequest{
+			Candidates: others,
+			Count:      remaining,
+		})
+		if err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, rest...)
+	}
+
+	return reviewers, nil
+}
+
+// roundRobinStrategy takes candidates in user_id order, continuing after
+// the last user the team's rotation assigned. The cursor is stored with the
+// team and advanced in the caller's transaction, which runs under the team
+// lock, so concurrent PRs never get the same turn.
+type roundRobinStrategy struct{}
+
+func (roundRobinStrategy) Name() string {
+	return StrategyRoundRobin
+}
+
+func (roundRobinStrategy) SelectReviewers(ctx context.Context, repo repository.Storage, req AssignmentRequest) ([]Decision, error) {
+	if len(req.Candidates) == 0 || req.Count <= 0 {
+		return []Decision{}, nil
+	}
+
+	cursor, err := repo.GetRotationCursor(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := userIDs(req.Candidates)
+	sort.Strings(ids)
+	start := sort.SearchStrings(ids, cursor)
+	if start < len(ids) && ids[start] == cursor {
+		start++
+	}
+
+	reviewers := []Decision{}
+	for i := 0; i < len(ids) && i < req.Count; i++ {
+		reviewers = append(reviewers, Decision{UserID: ids[(start+i)%len(ids)], Reason: models.ReasonRoundRobin})
+	}
+
+	last := reviewers[len(reviewers)-1].UserID
+	if err := repo.SetRotationCursor(ctx, req.TeamName, last); err != nil {
+		return nil, err
+	}
+	return reviewers, nil
+}
+
+func randomSelection(rng Rand, candidates []models.User, maxCount int) []Decision {
+	if len(candidates) > maxCount {
+		rng.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+		candidates = candidates[:maxCount]
+	}
+
+	result := make([]Decision, len(candidates))
+	for i, c := range candidates {
+		result[i] = Decision{UserID: c.UserID, Reason: models.ReasonRandom}
+	}
+	return result
+}
+
+// weightedLoad is the user's open review load relative to their review
+// weight, so a user with weight 2 counts as half as loaded.
+func weightedLoad(load float64, user models.User) float64 {
+	if user.ReviewWeight <= 0 {
+		return load
+	}
+	return load / user.ReviewWeight
+}
+
+func validateReviewWeight(weight float64) string {
+	if weight <= 0 || weight > maxReviewWeight {
+		return "review_weight must be greater than 0 and at most 10"
+	}
+	return ""
+}
+
+// decide records the same reason for every user in ids.
+func decide(ids []string, reason models.AssignmentReason) []Decision {
+	decisions := make([]Decision, len(ids))
+	for i, id := range ids {
+		decisions[i] = Decision{UserID: id, Reason: reason}
+	}
+	return decisions
+}
+
+func userIDs(users []models.User) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.UserID
+	}
+	return ids
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// defaultSuggestions is how many reviewers SuggestReviewers ranks when the
+// caller does not say.
+const defaultSuggestions = 5
+
+// Weights of the parts of a suggestion's score. Owning a changed path
+// outweighs everything else; the most expertise among the candidates counts
+// twice as much as having no open reviews.
+const (
+	ownerScore     = 3
+	expertiseScore = 2
+	capacityScore  = 1
+)
+
+// SuggestReviewersParams name either an existing PR or, like
+// SimulateAssignmentParams, a hypothetical one by its author, changed paths
+// and repository.
+type SuggestReviewersParams struct {
+	PullRequestID string
+	AuthorID      string
+	ChangedPaths  []string
+	Repository    string
+	Count         int
+}
+
+// SuggestReviewers ranks up to params.Count reviewers from the author's
+// team who could be assigned automatically, so a bot or IDE plugin can let
+// the author choose. Reviewers already on the PR are left out. It writes
+// nothing.
+func (s *Service) SuggestReviewers(ctx context.Context, params SuggestReviewersParams) (*models.ReviewerSuggestions, error) {
+	if (params.PullRequestID == "") == (params.AuthorID == "") {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "either pull_request_id or author_id is required",
+		}
+	}
+	count := params.Count
+	if count < 0 {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "count must not be negative",
+		}
+	}
+	if count == 0 {
+		count = defaultSuggestions
+	}
+
+	pr := &models.PullRequest{
+		AuthorID:     params.AuthorID,
+		Priority:     models.PriorityNormal,
+		ChangedPaths: normalizePaths(params.ChangedPaths),
+		Repository:   normalizeRepository(params.Repository),
+	}
+	if params.PullRequestID != "" {
+		var err error
+		pr, err = s.repo.GetPullRequest(ctx, params.PullRequestID)
+		if err != nil {
+			return nil, err
+		}
+		if pr == nil {
+			return nil, &ServiceError{
+				Code:    models.ErrNotFound,
+				Message: "PR not found",
+			}
+		}
+		if pr.Status != models.StatusOpen {
+			return nil, notOpen(pr, "cannot suggest reviewers for %s PR")
+		}
+	}
+
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "author not found",
+		}
+	}
+	teamMembers, err := s.repo.GetUsersByTeam(ctx, author.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := pullRequestSettings(ctx, s.repo, author.TeamName, pr.Repository)
+	if err != nil {
+		return nil, err
+	}
+	excluded, err := automaticExclusions(ctx, s.repo, settings, teamMembers, author.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []models.User{}
+	for _, member := range teamMembers {
+		if !member.IsActive || member.IsMentee || member.UserID == author.UserID || excluded[member.UserID] {
+			continue
+		}
+		if contains(pr.AssignedReviewers, member.UserID) || contains(pr.ShadowReviewers, member.UserID) {
+			continue
+		}
+		candidates = append(candidates, member)
+	}
+	candidates, err = withinCapacity(ctx, s.repo, candidates, reviewLimit(settings, pr))
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions, err := s.rankReviewers(ctx, candidates, pr)
+	if err != nil {
+		return nil, err
+	}
+	if len(suggestions) > count {
+		suggestions = suggestions[:count]
+	}
+	return &models.ReviewerSuggestions{
+		PullRequestID: pr.PullRequestID,
+		TeamName:      author.TeamName,
+		Suggestions:   suggestions,
+	}, nil
+}
+
+// rankReviewers scores every candidate for pr and sorts them by score,
+// breaking ties by user_id.
+func (s *Service) rankReviewers(ctx context.Context, candidates []models.User, pr *models.PullRequest) ([]models.ReviewerSuggestion, error) {
+	suggestions := []models.ReviewerSuggestion{}
+	if len(candidates) == 0 {
+		return suggestions, nil
+	}
+
+	ids := userIDs(candidates)
+	owners, err := pathOwners(ctx, s.repo, pr.Repository, pr.ChangedPaths)
+	if err != nil {
+		return nil, err
+	}
+	expertise := map[string]int{}
+	if len(pr.ChangedPaths) > 0 {
+		expertise, err = s.repo.GetPathExpertise(ctx, pr.ChangedPaths, ids)
+		if err != nil {
+			return nil, err
+		}
+	}
+	loads, counts, err := reviewLoads(ctx, s.repo, ids, s.reviewSize)
+	if err != nil {
+		return nil, err
+	}
+
+	maxExpertise := 0
+	minLoad := math.Inf(1)
+	for _, c := range candidates {
+		if expertise[c.UserID] > maxExpertise {
+			maxExpertise = expertise[c.UserID]
+		}
+		if load := weightedLoad(loads[c.UserID], c); load < minLoad {
+			minLoad = load
+		}
+	}
+
+	for _, c := range candidates {
+		suggestion := models.ReviewerSuggestion{
+			UserID:      c.UserID,
+			Username:    c.Username,
+			CodeOwner:   contains(owners, c.UserID),
+			Expertise:   expertise[c.UserID],
+			Load:        weightedLoad(loads[c.UserID], c),
+			OpenReviews: counts[c.UserID],
+			Reasons:     []models.AssignmentReason{},
+		}
+		score := capacityScore / (1 + suggestion.Load)
+		if suggestion.CodeOwner {
+			score += ownerScore
+			suggestion.Reasons = append(suggestion.Reasons, models.ReasonCodeOwner)
+		}
+		if suggestion.Expertise > 0 {
+			score += expertiseScore * float64(suggestion.Expertise) / float64(maxExpertise)
+			suggestion.Reasons = append(suggestion.Reasons, models.ReasonExpertise)
+		}
+		if suggestion.Load == minLoad {
+			suggestion.Reasons = append(suggestion.Reasons, models.ReasonLeastLoaded)
+		}
+		suggestion.Score = math.Round(score*1000) / 1000
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].UserID < suggestions[j].UserID
+	})
+	return suggestions, nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// SwapReviewersParams names a reviewer on each of two PRs to trade places.
+type SwapReviewersParams struct {
+	PullRequestID      string
+	UserID             string
+	OtherPullRequestID string
+	OtherUserID        string
+}
+
+// SwapReviewers moves UserID from PullRequestID to OtherPullRequestID and
+// OtherUserID the other way, in one transaction. Both PRs must be open and
+// authored within the same team, both users regular reviewers of their PR,
+// and each must be eligible for the PR they move to. Each PR records the
+// swap as a reassignment with reason swap.
+func (s *Service) SwapReviewers(ctx context.Context, params SwapReviewersParams) (*models.PullRequest, *models.PullRequest, error) {
+	if params.PullRequestID == params.OtherPullRequestID {
+		return nil, nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "cannot swap reviewers within one PR",
+		}
+	}
+	if params.UserID == params.OtherUserID {
+		return nil, nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "cannot swap a reviewer with themselves",
+		}
+	}
+
+	teamName, err := s.swapTeam(ctx, params.PullRequestID, params.OtherPullRequestID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var pr, other *models.PullRequest
+	var events []models.AssignmentEvent
+	err = s.repo.WithTeamLock(ctx, teamName, func(repo repository.Storage) error {
+		pr, err = openPullRequest(ctx, repo, params.PullRequestID)
+		if err != nil {
+			return err
+		}
+		other, err = openPullRequest(ctx, repo, params.OtherPullRequestID)
+		if err != nil {
+			return err
+		}
+
+		teamMembers, err := repo.GetUsersByTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
+		if err := swapIn(ctx, repo, teamName, teamMembers, pr, params.UserID, params.OtherUserID); err != nil {
+			return err
+		}
+		if err := swapIn(ctx, repo, teamName, teamMembers, other, params.OtherUserID, params.UserID); err != nil {
+			return err
+		}
+
+		for _, p := range []*models.PullRequest{pr, other} {
+			if err := saveReviewers(ctx, repo, p, "cannot swap reviewers on %s PR"); err != nil {
+				return err
+			}
+		}
+		events = []models.AssignmentEvent{
+			{
+				PullRequestID:  pr.PullRequestID,
+				UserID:         params.OtherUserID,
+				Role:           models.RoleReviewer,
+				Action:         models.ActionReassigned,
+				Reason:         models.ReasonSwap,
+				ReplacedUserID: params.UserID,
+			},
+			{
+				PullRequestID:  other.PullRequestID,
+				UserID:         params.UserID,
+				Role:           models.RoleReviewer,
+				Action:         models.ActionReassigned,
+				Reason:         models.ReasonSwap,
+				ReplacedUserID: params.OtherUserID,
+			},
+		}
+		if err := s.recordAssignments(ctx, repo, events); err != nil {
+			return err
+		}
+
+		for _, p := range []*models.PullRequest{pr, other} {
+			history, err := repo.GetAssignmentHistory(ctx, p.PullRequestID)
+			if err != nil {
+				return err
+			}
+			p.AssignmentReasons = assignmentReasons(p, history)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.publish(ctx, models.EventReviewerReassigned, pr, params.UserID, params.OtherUserID, events[0])
+	s.publish(ctx, models.EventReviewerReassigned, other, params.OtherUserID, params.UserID, events[1])
+	return pr, other, nil
+}
+
+// swapTeam returns the team both PRs' authors belong to.
+func (s *Service) swapTeam(ctx context.Context, prIDs ...string) (string, error) {
+	teamName := ""
+	for _, prID := range prIDs {
+		pr, err := s.repo.GetPullRequest(ctx, prID)
+		if err != nil {
+			return "", err
+		}
+		if pr == nil {
+			return "", &ServiceError{
+				Code:    models.ErrNotFound,
+				Message: "PR " + prID + " not found",
+			}
+		}
+		author, err := s.repo.GetUser(ctx, pr.AuthorID)
+		if err != nil {
+			return "", err
+		}
+		if author == nil {
+			return "", &ServiceError{
+				Code:    models.ErrNotFound,
+				Message: "author of PR " + prID + " not found",
+			}
+		}
+		if teamName != "" && author.TeamName != teamName {
+			return "", &ServiceError{
+				Code:    models.ErrValidation,
+				Message: "PRs belong to different teams",
+			}
+		}
+		teamName = author.TeamName
+	}
+	return teamName, nil
+}
+
+// openPullRequest loads a PR that reviewers may still be changed on.
+func openPullRequest(ctx context.Context, repo repository.Storage, prID string) (*models.PullRequest, error) {
+	pr, err := repo.GetPullRequest(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "PR " + prID + " not found",
+		}
+	}
+	if pr.Status != models.StatusOpen {
+		err := notOpen(pr, "cannot swap reviewers on %s PR")
+		err.Message += " " + prID
+		return nil, err
+	}
+	return pr, nil
+}
+
+// swapIn replaces the regular reviewer outgoing of pr with incoming, who
+// must be eligible for pr and not on it yet.
+func swapIn(ctx context.Context, repo repository.Storage, teamName string, teamMembers []models.User, pr *models.PullRequest, outgoing, incoming string) error {
+	index := -1
+	for i, reviewerID := range pr.AssignedReviewers {
+		if reviewerID == outgoing {
+			index = i
+		}
+	}
+	if index == -1 {
+		return &ServiceError{
+			Code:    models.ErrNotAssigned,
+			Message: fmt.Sprintf("reviewer %s is not assigned to PR %s", outgoing, pr.PullRequestID),
+		}
+	}
+	if contains(pr.AssignedReviewers, incoming) || contains(pr.ShadowReviewers, incoming) {
+		return &ServiceError{
+			Code:    models.ErrAlreadyExists,
+			Message: fmt.Sprintf("reviewer %s is already on PR %s", incoming, pr.PullRequestID),
+		}
+	}
+
+	excluded, err := excludedReviewers(ctx, repo, teamName, pr.AuthorID)
+	if err != nil {
+		return err
+	}
+	if reason := ineligibleReviewer(teamMembers, excluded, pr.AuthorID, incoming); reason != "" {
+		return &ServiceError{
+			Code:    models.ErrValidation,
+			Message: fmt.Sprintf("reviewer %s %s (PR %s)", incoming, reason, pr.PullRequestID),
+		}
+	}
+
+	pr.AssignedReviewers[index] = incoming
+	return nil
+}
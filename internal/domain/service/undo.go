@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+const defaultUndoWindow = time.Hour
+
+// WithUndoWindow sets how long after a reassignment UndoReassign may still
+// revert it.
+func WithUndoWindow(window time.Duration) Option {
+	return func(s *Service) {
+		s.undoWindow = window
+	}
+}
+
+// UndoReassign reverts the most recent reassignment of an open PR, putting
+// the replaced reviewer back in place of their replacement. The revert is
+// itself recorded as a reassignment with reason undo, so it cannot be undone
+// in turn. It returns that history entry along with the PR.
+func (s *Service) UndoReassign(ctx context.Context, prID string) (*models.PullRequest, *models.AssignmentEvent, error) {
+	pr, err := s.repo.GetPullRequest(ctx, prID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pr == nil {
+		return nil, nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "PR not found",
+		}
+	}
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, nil, err
+	}
+	teamName := ""
+	if author != nil {
+		teamName = author.TeamName
+	}
+
+	var undo models.AssignmentEvent
+	err = s.repo.WithTeamLock(ctx, teamName, func(repo repository.Storage) error {
+		// Re-read under the lock so a concurrent reassignment is either
+		// fully visible or not at all.
+		pr, err = repo.GetPullRequest(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if pr == nil {
+			return &ServiceError{
+				Code:    models.ErrNotFound,
+				Message: "PR not found",
+			}
+		}
+		if pr.Status != models.StatusOpen {
+			return notOpen(pr, "cannot undo reassignment on %s PR")
+		}
+
+		history, err := repo.GetAssignmentHistory(ctx, prID)
+		if err != nil {
+			return err
+		}
+		last, err := s.undoableReassignment(history)
+		if err != nil {
+			return err
+		}
+
+		index := -1
+		for i, reviewerID := range pr.AssignedReviewers {
+			if reviewerID == last.UserID {
+				index = i
+			}
+		}
+		if index == -1 {
+			return &ServiceError{
+				Code:    models.ErrNotAssigned,
+				Message: "reviewer " + last.UserID + " is no longer assigned to this PR",
+			}
+		}
+		if contains(pr.AssignedReviewers, last.ReplacedUserID) || contains(pr.ShadowReviewers, last.ReplacedUserID) {
+			return &ServiceError{
+				Code:    models.ErrAlreadyExists,
+				Message: "reviewer " + last.ReplacedUserID + " is already on this PR",
+			}
+		}
+		previous, err := repo.GetUser(ctx, last.ReplacedUserID)
+		if err != nil {
+			return err
+		}
+		if previous == nil || !previous.IsActive {
+			return &ServiceError{
+				Code:    models.ErrNoCandidate,
+				Message: "previous reviewer " + last.ReplacedUserID + " is no longer active",
+			}
+		}
+
+		pr.AssignedReviewers[index] = last.ReplacedUserID
+		if err := saveReviewers(ctx, repo, pr, "cannot undo reassignment on %s PR"); err != nil {
+			return err
+		}
+		events := []models.AssignmentEvent{{
+			PullRequestID:  pr.PullRequestID,
+			UserID:         last.ReplacedUserID,
+			Role:           models.RoleReviewer,
+			Action:         models.ActionReassigned,
+			Reason:         models.ReasonUndo,
+			ReplacedUserID: last.UserID,
+		}}
+		if err := s.recordAssignments(ctx, repo, events); err != nil {
+			return err
+		}
+		undo = events[0]
+
+		pr.AssignmentReasons = assignmentReasons(pr, append(history, undo))
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.publish(ctx, models.EventReviewerReassigned, pr, undo.ReplacedUserID, undo.UserID, undo)
+	return pr, &undo, nil
+}
+
+// undoableReassignment returns the latest reassignment in history, provided
+// it is not an undo itself and happened within the undo window.
+func (s *Service) undoableReassignment(history []models.AssignmentEvent) (models.AssignmentEvent, error) {
+	var last *models.AssignmentEvent
+	for i := range history {
+		if history[i].Action == models.ActionReassigned {
+			last = &history[i]
+		}
+	}
+	if last == nil || last.Reason == models.ReasonUndo {
+		return models.AssignmentEvent{}, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "PR has no reassignment to undo",
+		}
+	}
+	if last.CreatedAt != nil && s.clock.Now().Sub(*last.CreatedAt) > s.undoWindow {
+		return models.AssignmentEvent{}, &ServiceError{
+			Code:    models.ErrUndoExpired,
+			Message: fmt.Sprintf("reassignment is older than %s and can no longer be undone", s.undoWindow),
+		}
+	}
+	return *last, nil
+}
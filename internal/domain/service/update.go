@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// UpdatePullRequestParams are the fields of a PR to change. Nil fields, and
+// an empty priority, are left as they are; empty slices clear labels or
+// changed paths.
+type UpdatePullRequestParams struct {
+	PullRequestID   string
+	PullRequestName *string
+	Labels          []string
+	ChangedPaths    []string
+	Repository      *string
+	Priority        models.PullRequestPriority
+}
+
+// UpdatePullRequest changes the name, labels, changed paths, repository or
+// priority of an open PR and records what changed in a pr.updated event.
+// Reviewers stay as they are: they were picked for the PR as it was opened.
+// An update that changes nothing publishes no event.
+func (s *Service) UpdatePullRequest(ctx context.Context, params UpdatePullRequestParams) (*models.PullRequest, error) {
+	if params.Priority != "" && !params.Priority.Valid() {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "priority must be LOW, NORMAL or URGENT",
+		}
+	}
+	if params.PullRequestName != nil && strings.TrimSpace(*params.PullRequestName) == "" {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "pull_request_name must not be empty",
+		}
+	}
+
+	pr, changes, err := s.changeDetails(ctx, params.PullRequestID, "cannot update %s PR", func(pr *models.PullRequest) ([]models.FieldChange, error) {
+		var changes []models.FieldChange
+		if params.PullRequestName != nil && *params.PullRequestName != pr.PullRequestName {
+			changes = append(changes, models.FieldChange{Field: "pull_request_name", Old: pr.PullRequestName, New: *params.PullRequestName})
+			pr.PullRequestName = *params.PullRequestName
+		}
+		if params.Labels != nil {
+			labels := normalizeLabels(params.Labels)
+			if !sameMembers(labels, pr.Labels) {
+				changes = append(changes, models.FieldChange{Field: "labels", Old: pr.Labels, New: labels})
+				pr.Labels = labels
+			}
+		}
+		if params.ChangedPaths != nil {
+			paths := normalizePaths(params.ChangedPaths)
+			slices.Sort(paths)
+			if !sameMembers(paths, pr.ChangedPaths) {
+				changes = append(changes, models.FieldChange{Field: "changed_paths", Old: pr.ChangedPaths, New: paths})
+				pr.ChangedPaths = paths
+			}
+		}
+		if params.Repository != nil {
+			repoName := normalizeRepository(*params.Repository)
+			if repoName != pr.Repository {
+				if err := s.checkRepository(ctx, repoName); err != nil {
+					return nil, err
+				}
+				changes = append(changes, models.FieldChange{Field: "repository", Old: pr.Repository, New: repoName})
+				pr.Repository = repoName
+			}
+		}
+		if params.Priority != "" && params.Priority != pr.Priority {
+			changes = append(changes, models.FieldChange{Field: "priority", Old: pr.Priority, New: params.Priority})
+			pr.Priority = params.Priority
+		}
+		return changes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publishChanges(ctx, pr, changes)
+	return pr, nil
+}
+
+// publishChanges publishes a pr.updated event with changes, unless there
+// are none.
+func (s *Service) publishChanges(ctx context.Context, pr *models.PullRequest, changes []models.FieldChange) {
+	if len(changes) == 0 {
+		return
+	}
+	event := s.event(models.EventPRUpdated, pr, "", "", nil)
+	event.Changes = changes
+	s.events.Publish(ctx, event)
+}
+
+// changeDetails applies change to the open PR prID and saves its details
+// when change reports any changed. Status and reviewer changes save only
+// those, so details are read and saved under the lock of the author's team
+// to keep concurrent changes of them from undoing each other. A PR that is
+// not open, or no longer open when saved, is refused with format.
+func (s *Service) changeDetails(ctx context.Context, prID, format string, change func(pr *models.PullRequest) ([]models.FieldChange, error)) (*models.PullRequest, []models.FieldChange, error) {
+	pr, err := s.repo.GetPullRequest(ctx, prID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pr == nil {
+		return nil, nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "PR not found",
+		}
+	}
+	if pr.Status != models.StatusOpen {
+		return nil, nil, notOpen(pr, format)
+	}
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if author == nil {
+		return nil, nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "author not found",
+		}
+	}
+
+	var changes []models.FieldChange
+	err = s.repo.WithTeamLock(ctx, author.TeamName, func(repo repository.Storage) error {
+		pr, err = repo.GetPullRequest(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if pr == nil {
+			return &ServiceError{
+				Code:    models.ErrNotFound,
+				Message: "PR not found",
+			}
+		}
+		if pr.Status != models.StatusOpen {
+			return notOpen(pr, format)
+		}
+
+		changes, err = change(pr)
+		if err != nil || len(changes) == 0 {
+			return err
+		}
+		updated, err := repo.UpdatePullRequestDetails(ctx, pr)
+		if err != nil || updated {
+			return err
+		}
+		// Merges and closes do not take the lock.
+		return noLongerOpen(ctx, repo, prID, format)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return pr, changes, nil
+}
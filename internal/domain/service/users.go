@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// LookupUser returns the user with the username, ignoring case, or the one
+// whose email identity is email, for UIs and bots that know people but not
+// their user IDs. Exactly one of them must be given.
+func (s *Service) LookupUser(ctx context.Context, username, email string) (*models.User, error) {
+	username, email = strings.TrimSpace(username), strings.TrimSpace(email)
+	if (username == "") == (email == "") {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "either username or email is required",
+		}
+	}
+	if email != "" {
+		return s.ResolveIdentity(ctx, models.ProviderEmail, email)
+	}
+
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "user not found",
+		}
+	}
+	return user, nil
+}
+
+// SearchUsers returns a page of the users whose username or user ID starts
+// with query, then those whose username resembles it.
+func (s *Service) SearchUsers(ctx context.Context, query string, limit, offset int) ([]models.User, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "q is required",
+		}
+	}
+	limit, offset = pageBounds(limit, offset)
+	return s.repo.SearchUsers(ctx, query, limit, offset)
+}
+
+// ListUsers returns a page of the users matching the filter, with the
+// reviews each of them has open, for admin tables and scripts.
+func (s *Service) ListUsers(ctx context.Context, filter models.UserFilter) ([]models.UserSummary, error) {
+	filter.Limit, filter.Offset = pageBounds(filter.Limit, filter.Offset)
+	return s.repo.ListUsers(ctx, filter)
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+const (
+	// defaultWebhookAttempts is how often a webhook delivery is tried before
+	// it is dead, unless WithWebhookDeliveries says otherwise.
+	defaultWebhookAttempts = 10
+	// webhookBatch is how many deliveries one DeliverWebhooks call claims.
+	webhookBatch = 100
+	// webhookLease keeps a delivery that is being attempted from being
+	// claimed by another instance.
+	webhookLease = 5 * time.Minute
+	// deliveredRetention is how long delivered deliveries are kept.
+	deliveredRetention = 7 * 24 * time.Hour
+)
+
+// WithWebhookDeliveries records every notification sender posts to a
+// webhook and retries those that fail, with exponential backoff, until
+// maxAttempts failures, or defaultWebhookAttempts when it is zero, make
+// them dead. Notifications without a webhook still go to the notifier.
+func WithWebhookDeliveries(sender repository.WebhookSender, maxAttempts int) Option {
+	return func(s *Service) {
+		s.webhooks = sender
+		s.webhookAttempts = maxAttempts
+		if s.webhookAttempts <= 0 {
+			s.webhookAttempts = defaultWebhookAttempts
+		}
+	}
+}
+
+// notify sends n as a recorded webhook delivery when they are enabled, or
+// through the notifier otherwise. Once recorded the notification is the
+// delivery's to send: a failed first attempt only schedules a retry and is
+// not reported, so callers do not send it a second time.
+func (s *Service) notify(ctx context.Context, n models.Notification) error {
+	if s.webhooks == nil {
+		return s.notifier.Notify(ctx, n)
+	}
+	d, err := s.webhooks.Render(n)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return s.notifier.Notify(ctx, n)
+	}
+
+	// The delivery is leased to this first attempt.
+	leaseUntil := s.clock.Now().Add(webhookLease)
+	d.Status = models.DeliveryPending
+	d.NextAttemptAt = &leaseUntil
+	if err := s.repo.AddWebhookDelivery(ctx, d); err != nil {
+		return err
+	}
+	s.attemptDelivery(ctx, *d)
+	return nil
+}
+
+// DeliverWebhooks retries the due webhook deliveries and returns how many
+// went through. Deliveries delivered more than deliveredRetention ago are
+// deleted.
+func (s *Service) DeliverWebhooks(ctx context.Context) (int, error) {
+	if s.webhooks == nil {
+		return 0, nil
+	}
+
+	now := s.clock.Now()
+	deliveries, err := s.repo.ClaimWebhookDeliveries(ctx, now, now.Add(webhookLease), webhookBatch)
+	if err != nil {
+		return 0, err
+	}
+	delivered := 0
+	for _, d := range deliveries {
+		if s.attemptDelivery(ctx, d).Status == models.DeliveryDelivered {
+			delivered++
+		}
+	}
+
+	if _, err := s.repo.PurgeWebhookDeliveries(ctx, now.Add(-deliveredRetention)); err != nil {
+		return delivered, err
+	}
+	return delivered, nil
+}
+
+// attemptDelivery sends d and records the outcome: delivered, a retry, or
+// dead once it has run out of attempts. It returns d as recorded.
+func (s *Service) attemptDelivery(ctx context.Context, d models.WebhookDelivery) models.WebhookDelivery {
+	status, sendErr := s.webhooks.Send(ctx, d)
+	now := s.clock.Now()
+	d.Attempts++
+	d.LastStatus = status
+	switch {
+	case sendErr == nil:
+		d.Status = models.DeliveryDelivered
+		d.LastError = ""
+		d.NextAttemptAt = nil
+		d.DeliveredAt = &now
+	case d.Attempts < s.webhookAttempts:
+		at := now.Add(retryDelay(d.Attempts))
+		d.LastError = sendErr.Error()
+		d.NextAttemptAt = &at
+		log.Printf("Webhook delivery %d (%s) failed, retrying at %s: %v", d.ID, d.Event, at.Format(time.RFC3339), sendErr)
+	default:
+		d.Status = models.DeliveryDead
+		d.LastError = sendErr.Error()
+		d.NextAttemptAt = nil
+		log.Printf("Webhook delivery %d (%s) failed %d times, giving up: %v", d.ID, d.Event, d.Attempts, sendErr)
+	}
+
+	if err := s.repo.RecordWebhookAttempt(ctx, d); err != nil {
+		// The lease runs out and the delivery is attempted again.
+		log.Printf("Cannot record attempt of webhook delivery %d: %v", d.ID, err)
+	}
+	return d
+}
+
+func (s *Service) ListWebhookDeliveries(ctx context.Context, filter models.DeliveryFilter) ([]models.WebhookDelivery, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, &ServiceError{
+			Code:    models.ErrValidation,
+			Message: "status must be pending, delivered or dead",
+		}
+	}
+	filter.Limit, filter.Offset = pageBounds(filter.Limit, filter.Offset)
+	return s.repo.ListWebhookDeliveries(ctx, filter)
+}
+
+// RetryWebhookDelivery gives a dead delivery one more attempt, made right
+// away, and returns it as it stands after the attempt. Should that attempt
+// fail too the delivery is dead again.
+func (s *Service) RetryWebhookDelivery(ctx context.Context, id int64) (*models.WebhookDelivery, error) {
+	retried, err := s.repo.RetryWebhookDelivery(ctx, id, s.clock.Now().Add(webhookLease))
+	if err != nil {
+		return nil, err
+	}
+	d, err := s.repo.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, &ServiceError{
+			Code:    models.ErrNotFound,
+			Message: "delivery not found",
+		}
+	}
+	if !retried {
+		return nil, &ServiceError{
+			Code:    models.ErrDeliveryNotDead,
+			Message: "only dead deliveries can be retried",
+		}
+	}
+
+	if s.webhooks != nil {
+		*d = s.attemptDelivery(ctx, *d)
+	}
+	return d, nil
+}
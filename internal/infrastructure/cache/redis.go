@@ -0,0 +1,209 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+const (
+	redisKeyPrefix     = "pr-reviewer:"
+	redisStatisticsKey = redisKeyPrefix + "statistics"
+	redisTeamGenKey    = redisKeyPrefix + "team-gen"
+)
+
+// RedisStorage caches team lookups and statistics in Redis so that all
+// replicas share one cache. Team keys embed a generation counter that is
+// bumped on every membership change, which invalidates all teams at once
+// without scanning the keyspace. Any Redis failure falls back to the
+// wrapped Storage.
+type RedisStorage struct {
+	repository.Storage
+
+	client redis.UniversalClient
+	ttl    time.Duration
+}
+
+var _ repository.Storage = (*RedisStorage)(nil)
+
+func NewRedisStorage(storage repository.Storage, client redis.UniversalClient, ttl time.Duration) *RedisStorage {
+	return &RedisStorage{Storage: storage, client: client, ttl: ttl}
+}
+
+func (r *RedisStorage) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	key, err := r.teamKey(ctx, teamName)
+	if err == nil {
+		var team models.Team
+		if r.get(ctx, key, &team) {
+			return &team, nil
+		}
+	}
+
+	team, err := r.Storage.GetTeam(ctx, teamName)
+	if err != nil || team == nil || key == "" {
+		return team, err
+	}
+	r.set(ctx, key, team)
+	return team, nil
+}
+
+func (r *RedisStorage) GetStatistics(ctx context.Context) (*models.Statistics, error) {
+	var stats models.Statistics
+	if r.get(ctx, redisStatisticsKey, &stats) {
+		return &stats, nil
+	}
+
+	result, err := r.Storage.GetStatistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.set(ctx, redisStatisticsKey, result)
+	return result, nil
+}
+
+func (r *RedisStorage) RefreshStatistics(ctx context.Context) error {
+	defer r.invalidate(ctx, redisStatisticsKey)
+	return r.Storage.RefreshStatistics(ctx)
+}
+
+func (r *RedisStorage) CreateTeam(ctx context.Context, team *models.Team) error {
+	defer r.invalidateTeams(ctx)
+	return r.Storage.CreateTeam(ctx, team)
+}
+
+func (r *RedisStorage) CreateUser(ctx context.Context, user *models.User) error {
+	defer r.invalidateTeams(ctx)
+	return r.Storage.CreateUser(ctx, user)
+}
+
+func (r *RedisStorage) UpdateUser(ctx context.Context, user *models.User) error {
+	defer r.invalidateTeams(ctx)
+	return r.Storage.UpdateUser(ctx, user)
+}
+
+func (r *RedisStorage) EraseUser(ctx context.Context, userID string) error {
+	defer r.invalidateTeams(ctx)
+	return r.Storage.EraseUser(ctx, userID)
+}
+
+func (r *RedisStorage) EraseInactiveUsers(ctx context.Context, deactivatedBefore time.Time) (int64, error) {
+	defer r.invalidateTeams(ctx)
+	return r.Storage.EraseInactiveUsers(ctx, deactivatedBefore)
+}
+
+func (r *RedisStorage) DeleteUser(ctx context.Context, userID string) (bool, error) {
+	defer r.invalidateTeams(ctx)
+	return r.Storage.DeleteUser(ctx, userID)
+}
+
+func (r *RedisStorage) RestoreUser(ctx context.Context, userID string) (bool, error) {
+	defer r.invalidateTeams(ctx)
+	return r.Storage.RestoreUser(ctx, userID)
+}
+
+func (r *RedisStorage) DeleteTeam(ctx context.Context, teamName string) (bool, error) {
+	defer r.invalidateTeams(ctx)
+	return r.Storage.DeleteTeam(ctx, teamName)
+}
+
+func (r *RedisStorage) RestoreTeam(ctx context.Context, teamName string) (bool, error) {
+	defer r.invalidateTeams(ctx)
+	return r.Storage.RestoreTeam(ctx, teamName)
+}
+
+func (r *RedisStorage) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	defer r.invalidateTeams(ctx)
+	return r.Storage.PurgeDeletedUsers(ctx, deletedBefore)
+}
+
+func (r *RedisStorage) PurgeDeletedTeams(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	defer r.invalidateTeams(ctx)
+	return r.Storage.PurgeDeletedTeams(ctx, deletedBefore)
+}
+
+func (r *RedisStorage) Restore(ctx context.Context, backup *models.Backup, dryRun bool) (map[string]int64, error) {
+	defer r.invalidate(ctx, redisStatisticsKey)
+	defer r.invalidateTeams(ctx)
+	return r.Storage.Restore(ctx, backup, dryRun)
+}
+
+func (r *RedisStorage) CreatePullRequest(ctx context.Context, pr *models.PullRequest) error {
+	defer r.invalidate(ctx, redisStatisticsKey)
+	return r.Storage.CreatePullRequest(ctx, pr)
+}
+
+func (r *RedisStorage) UpdatePullRequest(ctx context.Context, pr *models.PullRequest) (bool, error) {
+	defer r.invalidate(ctx, redisStatisticsKey)
+	return r.Storage.UpdatePullRequest(ctx, pr)
+}
+
+func (r *RedisStorage) UpdatePullRequestStatus(ctx context.Context, pr *models.PullRequest, from models.PullRequestStatus) (bool, error) {
+	defer r.invalidate(ctx, redisStatisticsKey)
+	return r.Storage.UpdatePullRequestStatus(ctx, pr, from)
+}
+
+func (r *RedisStorage) UpdatePullRequestDetails(ctx context.Context, pr *models.PullRequest) (bool, error) {
+	defer r.invalidate(ctx, redisStatisticsKey)
+	return r.Storage.UpdatePullRequestDetails(ctx, pr)
+}
+
+func (r *RedisStorage) WithTeamLock(ctx context.Context, teamName string, fn func(repo repository.Storage) error) error {
+	defer r.invalidate(ctx, redisStatisticsKey)
+	return r.Storage.WithTeamLock(ctx, teamName, fn)
+}
+
+func (r *RedisStorage) teamKey(ctx context.Context, teamName string) (string, error) {
+	gen, err := r.client.Get(ctx, redisTeamGenKey).Result()
+	if errors.Is(err, redis.Nil) {
+		gen, err = "0", nil
+	}
+	if err != nil {
+		log.Printf("Redis cache unavailable: %v", err)
+		return "", err
+	}
+	return redisKeyPrefix + "team:" + gen + ":" + teamName, nil
+}
+
+func (r *RedisStorage) get(ctx context.Context, key string, dest interface{}) bool {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Printf("Redis cache get %s: %v", key, err)
+		}
+		return false
+	}
+	return json.Unmarshal(data, dest) == nil
+}
+
+func (r *RedisStorage) set(ctx context.Context, key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	if err := r.client.Set(ctx, key, data, r.ttl).Err(); err != nil {
+		log.Printf("Redis cache set %s: %v", key, err)
+	}
+}
+
+// Invalidation must happen even if the caller's request was cancelled right
+// after the mutation committed, hence context.WithoutCancel.
+func (r *RedisStorage) invalidateTeams(ctx context.Context) {
+	ctx = context.WithoutCancel(ctx)
+	if err := r.client.Incr(ctx, redisTeamGenKey).Err(); err != nil {
+		log.Printf("Redis cache invalidate teams: %v", err)
+	}
+	r.invalidate(ctx, redisStatisticsKey)
+}
+
+func (r *RedisStorage) invalidate(ctx context.Context, key string) {
+	if err := r.client.Del(context.WithoutCancel(ctx), key).Err(); err != nil {
+		log.Printf("Redis cache invalidate %s: %v", key, err)
+	}
+}
@@ -0,0 +1,210 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// CachedStorage decorates a Storage with an in-process cache for team
+// membership and open review counts, the two lookups every PR creation and
+// reassignment performs, and for feature flags, which gated code paths
+// check on every call. Methods that are not cached pass straight through
+// to the embedded Storage; mutations invalidate the affected entries.
+type CachedStorage struct {
+	repository.Storage
+
+	teams  *ttlMap[string, []models.User]
+	counts *ttlMap[string, int]
+	// flags holds the whole flag list under the empty key.
+	flags *ttlMap[string, []models.FeatureFlag]
+}
+
+var _ repository.Storage = (*CachedStorage)(nil)
+
+func NewCachedStorage(storage repository.Storage, ttl time.Duration) *CachedStorage {
+	return &CachedStorage{
+		Storage: storage,
+		teams:   newTTLMap[string, []models.User](ttl),
+		counts:  newTTLMap[string, int](ttl),
+		flags:   newTTLMap[string, []models.FeatureFlag](ttl),
+	}
+}
+
+func (c *CachedStorage) GetUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	if users, ok := c.teams.get(teamName); ok {
+		return copyUsers(users), nil
+	}
+
+	users, err := c.Storage.GetUsersByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	c.teams.set(teamName, copyUsers(users))
+	return users, nil
+}
+
+func (c *CachedStorage) GetReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(userIDs))
+	var missing []string
+	for _, userID := range userIDs {
+		if count, ok := c.counts.get(userID); ok {
+			counts[userID] = count
+		} else {
+			missing = append(missing, userID)
+		}
+	}
+	if len(missing) == 0 {
+		return counts, nil
+	}
+
+	fetched, err := c.Storage.GetReviewCounts(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range missing {
+		counts[userID] = fetched[userID]
+		c.counts.set(userID, fetched[userID])
+	}
+	return counts, nil
+}
+
+func (c *CachedStorage) GetFeatureFlags(ctx context.Context) ([]models.FeatureFlag, error) {
+	if flags, ok := c.flags.get(""); ok {
+		return copyFlags(flags), nil
+	}
+
+	flags, err := c.Storage.GetFeatureFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.flags.set("", copyFlags(flags))
+	return flags, nil
+}
+
+// Flag changes only reach this replica's cache; other replicas pick them up
+// once their entry expires.
+func (c *CachedStorage) UpsertFeatureFlag(ctx context.Context, flag *models.FeatureFlag) error {
+	defer c.flags.clear()
+	return c.Storage.UpsertFeatureFlag(ctx, flag)
+}
+
+func (c *CachedStorage) DeleteFeatureFlag(ctx context.Context, name string) (bool, error) {
+	defer c.flags.clear()
+	return c.Storage.DeleteFeatureFlag(ctx, name)
+}
+
+// Team mutations may move existing users between teams (CreateTeam upserts
+// members), so every cached team is dropped rather than guessing which ones
+// changed.
+func (c *CachedStorage) CreateTeam(ctx context.Context, team *models.Team) error {
+	defer c.teams.clear()
+	return c.Storage.CreateTeam(ctx, team)
+}
+
+func (c *CachedStorage) CreateUser(ctx context.Context, user *models.User) error {
+	defer c.teams.delete(user.TeamName)
+	return c.Storage.CreateUser(ctx, user)
+}
+
+func (c *CachedStorage) UpdateUser(ctx context.Context, user *models.User) error {
+	defer c.teams.clear()
+	return c.Storage.UpdateUser(ctx, user)
+}
+
+func (c *CachedStorage) EraseUser(ctx context.Context, userID string) error {
+	defer c.teams.clear()
+	return c.Storage.EraseUser(ctx, userID)
+}
+
+func (c *CachedStorage) EraseInactiveUsers(ctx context.Context, deactivatedBefore time.Time) (int64, error) {
+	defer c.teams.clear()
+	return c.Storage.EraseInactiveUsers(ctx, deactivatedBefore)
+}
+
+func (c *CachedStorage) DeleteUser(ctx context.Context, userID string) (bool, error) {
+	defer c.teams.clear()
+	return c.Storage.DeleteUser(ctx, userID)
+}
+
+func (c *CachedStorage) RestoreUser(ctx context.Context, userID string) (bool, error) {
+	defer c.teams.clear()
+	return c.Storage.RestoreUser(ctx, userID)
+}
+
+func (c *CachedStorage) DeleteTeam(ctx context.Context, teamName string) (bool, error) {
+	defer c.teams.clear()
+	return c.Storage.DeleteTeam(ctx, teamName)
+}
+
+func (c *CachedStorage) RestoreTeam(ctx context.Context, teamName string) (bool, error) {
+	defer c.teams.clear()
+	return c.Storage.RestoreTeam(ctx, teamName)
+}
+
+func (c *CachedStorage) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	defer c.teams.clear()
+	return c.Storage.PurgeDeletedUsers(ctx, deletedBefore)
+}
+
+func (c *CachedStorage) PurgeDeletedTeams(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	defer c.teams.clear()
+	return c.Storage.PurgeDeletedTeams(ctx, deletedBefore)
+}
+
+func (c *CachedStorage) Restore(ctx context.Context, backup *models.Backup, dryRun bool) (map[string]int64, error) {
+	defer c.teams.clear()
+	defer c.counts.clear()
+	defer c.flags.clear()
+	return c.Storage.Restore(ctx, backup, dryRun)
+}
+
+func (c *CachedStorage) CreatePullRequest(ctx context.Context, pr *models.PullRequest) error {
+	defer c.invalidateCounts(pr.AssignedReviewers)
+	return c.Storage.CreatePullRequest(ctx, pr)
+}
+
+// UpdatePullRequest can swap reviewers and UpdatePullRequestStatus open or
+// close the reviews of reviewers pr may not show; the reviewers saved are
+// unknown here, so all counts are dropped.
+func (c *CachedStorage) UpdatePullRequest(ctx context.Context, pr *models.PullRequest) (bool, error) {
+	defer c.counts.clear()
+	return c.Storage.UpdatePullRequest(ctx, pr)
+}
+
+func (c *CachedStorage) UpdatePullRequestStatus(ctx context.Context, pr *models.PullRequest, from models.PullRequestStatus) (bool, error) {
+	defer c.counts.clear()
+	return c.Storage.UpdatePullRequestStatus(ctx, pr, from)
+}
+
+// WithTeamLock reads bypass the cache on purpose: under the lock the caller
+// needs the counts other replicas just committed, not a local snapshot.
+func (c *CachedStorage) WithTeamLock(ctx context.Context, teamName string, fn func(repo repository.Storage) error) error {
+	defer c.counts.clear()
+	return c.Storage.WithTeamLock(ctx, teamName, fn)
+}
+
+func (c *CachedStorage) invalidateCounts(userIDs []string) {
+	for _, userID := range userIDs {
+		c.counts.delete(userID)
+	}
+}
+
+// copyFlags copies flags deeply enough that callers cannot change the cached
+// team lists.
+func copyFlags(flags []models.FeatureFlag) []models.FeatureFlag {
+	result := make([]models.FeatureFlag, len(flags))
+	for i, f := range flags {
+		f.Teams = append([]string{}, f.Teams...)
+		result[i] = f
+	}
+	return result
+}
+
+func copyUsers(users []models.User) []models.User {
+	result := make([]models.User, len(users))
+	copy(result, users)
+	return result
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// ttlMap is a mutex-guarded map whose entries expire after a fixed TTL.
+// Expired entries are dropped lazily on lookup.
+type ttlMap[K comparable, V any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[K]entry[V]
+}
+
+func newTTLMap[K comparable, V any](ttl time.Duration) *ttlMap[K, V] {
+	return &ttlMap[K, V]{ttl: ttl, entries: make(map[K]entry[V])}
+}
+
+func (m *ttlMap[K, V]) get(key K) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(m.entries, key)
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+func (m *ttlMap[K, V]) set(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = entry[V]{value: value, expiresAt: time.Now().Add(m.ttl)}
+}
+
+func (m *ttlMap[K, V]) delete(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
+
+func (m *ttlMap[K, V]) clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[K]entry[V])
+}
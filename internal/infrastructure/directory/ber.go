@@ -0,0 +1,189 @@
+package directory
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// BER tags of the ASN.1 types and LDAP operations the client uses
+// (RFC 4511). Application tags are constructed unless the operation is a
+// bare value, like UnbindRequest.
+const (
+	tagBoolean     = 0x01
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagEnumerated  = 0x0a
+	tagSequence    = 0x30
+	tagSet         = 0x31
+
+	tagBindRequest       = 0x60
+	tagBindResponse      = 0x61
+	tagUnbindRequest     = 0x42
+	tagSearchRequest     = 0x63
+	tagSearchResultEntry = 0x64
+	tagSearchResultDone  = 0x65
+	tagSearchResultRef   = 0x73
+	tagControls          = 0xa0
+	tagSimpleAuth        = 0x80
+
+	constructedBit = 0x20
+)
+
+// maxPacketSize bounds what the client reads for a single message.
+const maxPacketSize = 16 << 20
+
+// berPacket is a decoded BER element: value holds the contents of primitive
+// elements, children the elements inside constructed ones.
+type berPacket struct {
+	tag      byte
+	value    []byte
+	children []*berPacket
+}
+
+func (p *berPacket) child(i int) *berPacket {
+	if i < len(p.children) {
+		return p.children[i]
+	}
+	return &berPacket{}
+}
+
+func (p *berPacket) integer() int {
+	n := 0
+	for i, b := range p.value {
+		if i == 0 && b&0x80 != 0 {
+			n = -1
+		}
+		n = n<<8 | int(b)
+	}
+	return n
+}
+
+func (p *berPacket) str() string {
+	return string(p.value)
+}
+
+func berElement(tag byte, content []byte) []byte {
+	n := len(content)
+	b := []byte{tag}
+	switch {
+	case n < 0x80:
+		b = append(b, byte(n))
+	case n <= 0xff:
+		b = append(b, 0x81, byte(n))
+	case n <= 0xffff:
+		b = append(b, 0x82, byte(n>>8), byte(n))
+	default:
+		b = append(b, 0x84, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+	return append(b, content...)
+}
+
+func berConstructed(tag byte, parts ...[]byte) []byte {
+	content := []byte{}
+	for _, p := range parts {
+		content = append(content, p...)
+	}
+	return berElement(tag, content)
+}
+
+func berString(tag byte, s string) []byte {
+	return berElement(tag, []byte(s))
+}
+
+func berInt(tag byte, n int) []byte {
+	content := []byte{byte(n)}
+	for n > 0x7f || n < -0x80 {
+		n >>= 8
+		content = append([]byte{byte(n)}, content...)
+	}
+	return berElement(tag, content)
+}
+
+func berBool(b bool) []byte {
+	if b {
+		return berElement(tagBoolean, []byte{0xff})
+	}
+	return berElement(tagBoolean, []byte{0x00})
+}
+
+// readPacket reads one BER element from r.
+func readPacket(r *bufio.Reader) (*berPacket, error) {
+	header := make([]byte, 2, 6)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if n := int(header[1] & 0x7f); header[1]&0x80 != 0 {
+		if n == 0 || n > 4 {
+			return nil, errors.New("ber: unsupported length encoding")
+		}
+		header = header[:2+n]
+		if _, err := io.ReadFull(r, header[2:]); err != nil {
+			return nil, err
+		}
+	}
+	length, _, err := berLength(header[1:])
+	if err != nil {
+		return nil, err
+	}
+	if length > maxPacketSize {
+		return nil, fmt.Errorf("ber: message of %d bytes is too large", length)
+	}
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, err
+	}
+	return parsePacket(header[0], content)
+}
+
+// berLength decodes a definite length, returning it and how many bytes it
+// took.
+func berLength(b []byte) (int, int, error) {
+	if len(b) == 0 {
+		return 0, 0, errors.New("ber: truncated length")
+	}
+	if b[0]&0x80 == 0 {
+		return int(b[0]), 1, nil
+	}
+	n := int(b[0] & 0x7f)
+	if n == 0 || n > 4 || len(b) < 1+n {
+		return 0, 0, errors.New("ber: unsupported length encoding")
+	}
+	length := 0
+	for _, c := range b[1 : 1+n] {
+		length = length<<8 | int(c)
+	}
+	if length < 0 {
+		return 0, 0, errors.New("ber: invalid length")
+	}
+	return length, 1 + n, nil
+}
+
+func parsePacket(tag byte, content []byte) (*berPacket, error) {
+	p := &berPacket{tag: tag}
+	if tag&constructedBit == 0 {
+		p.value = content
+		return p, nil
+	}
+	for len(content) > 0 {
+		if len(content) < 2 {
+			return nil, errors.New("ber: truncated element")
+		}
+		length, size, err := berLength(content[1:])
+		if err != nil {
+			return nil, err
+		}
+		start := 1 + size
+		if len(content)-start < length {
+			return nil, errors.New("ber: truncated element")
+		}
+		child, err := parsePacket(content[0], content[start:start+length])
+		if err != nil {
+			return nil, err
+		}
+		p.children = append(p.children, child)
+		content = content[start+length:]
+	}
+	return p, nil
+}
@@ -0,0 +1,32 @@
+// Package directory implements repository.DirectorySource for LDAP servers
+// and SCIM 2.0 endpoints.
+package directory
+
+import (
+	"sort"
+	"strings"
+)
+
+// defaultPageSize is how many users or groups one request asks for.
+const defaultPageSize = 500
+
+// teamFor maps a user's groups to their team: the groups named with prefix
+// are teams, named without it. A user in several such groups gets the
+// first by name, so the result does not depend on the order the directory
+// lists them in; a user in none gets no team.
+func teamFor(groups []string, prefix string) string {
+	teams := []string{}
+	for _, g := range groups {
+		if !strings.HasPrefix(g, prefix) {
+			continue
+		}
+		if team := strings.TrimPrefix(g, prefix); team != "" {
+			teams = append(teams, team)
+		}
+	}
+	if len(teams) == 0 {
+		return ""
+	}
+	sort.Strings(teams)
+	return teams[0]
+}
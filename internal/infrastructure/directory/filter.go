@@ -0,0 +1,210 @@
+package directory
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Context-specific tags of the Filter choice (RFC 4511, section 4.5.1).
+const (
+	filterAnd            = 0xa0
+	filterOr             = 0xa1
+	filterNot            = 0xa2
+	filterEquality       = 0xa3
+	filterSubstrings     = 0xa4
+	filterGreaterOrEqual = 0xa5
+	filterLessOrEqual    = 0xa6
+	filterPresent        = 0x87
+	filterApproxMatch    = 0xa8
+
+	substringInitial = 0x80
+	substringAny     = 0x81
+	substringFinal   = 0x82
+)
+
+// maxFilterDepth bounds how deeply filters may nest.
+const maxFilterDepth = 32
+
+// encodeFilter compiles a filter in its string form (RFC 4515), like
+// "(&(objectClass=person)(!(uid=svc-*)))", to BER. Extensible matches are
+// not supported.
+func encodeFilter(s string) ([]byte, error) {
+	p := &filterParser{s: strings.TrimSpace(s)}
+	b, err := p.filter(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, p.errorf("unexpected %q", p.s[p.pos:])
+	}
+	return b, nil
+}
+
+type filterParser struct {
+	s   string
+	pos int
+}
+
+func (p *filterParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("ldap filter %q at %d: %s", p.s, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *filterParser) expect(c byte) error {
+	if p.pos >= len(p.s) || p.s[p.pos] != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *filterParser) filter(depth int) ([]byte, error) {
+	if depth > maxFilterDepth {
+		return nil, p.errorf("nested too deeply")
+	}
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	if p.pos >= len(p.s) {
+		return nil, p.errorf("unterminated filter")
+	}
+
+	var b []byte
+	var err error
+	switch c := p.s[p.pos]; c {
+	case '&', '|':
+		p.pos++
+		tag := byte(filterAnd)
+		if c == '|' {
+			tag = filterOr
+		}
+		items := [][]byte{}
+		for p.pos < len(p.s) && p.s[p.pos] == '(' {
+			item, err := p.filter(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		if len(items) == 0 {
+			return nil, p.errorf("%q needs at least one filter", c)
+		}
+		b = berConstructed(tag, items...)
+	case '!':
+		p.pos++
+		item, err := p.filter(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		b = berConstructed(filterNot, item)
+	default:
+		b, err = p.item()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// item parses a simple comparison: attr=value, attr~=value, attr>=value,
+// attr<=value, attr=* or a substring match like attr=ab*cd*.
+func (p *filterParser) item() ([]byte, error) {
+	end := strings.IndexAny(p.s[p.pos:], "=~<>():")
+	if end <= 0 {
+		return nil, p.errorf("expected an attribute")
+	}
+	attr := p.s[p.pos : p.pos+end]
+	p.pos += end
+
+	var tag byte
+	switch {
+	case strings.HasPrefix(p.s[p.pos:], "~="):
+		tag = filterApproxMatch
+	case strings.HasPrefix(p.s[p.pos:], ">="):
+		tag = filterGreaterOrEqual
+	case strings.HasPrefix(p.s[p.pos:], "<="):
+		tag = filterLessOrEqual
+	case strings.HasPrefix(p.s[p.pos:], "="):
+		tag = filterEquality
+	default:
+		return nil, p.errorf("unsupported operator")
+	}
+	if tag == filterEquality {
+		p.pos++
+	} else {
+		p.pos += 2
+	}
+
+	end = strings.IndexAny(p.s[p.pos:], "()")
+	if end < 0 {
+		return nil, p.errorf("unterminated value")
+	}
+	raw := p.s[p.pos : p.pos+end]
+	p.pos += end
+
+	if tag == filterEquality && raw == "*" {
+		return berString(filterPresent, attr), nil
+	}
+	if tag == filterEquality && strings.Contains(raw, "*") {
+		return p.substrings(attr, raw)
+	}
+	value, err := p.unescape(raw)
+	if err != nil {
+		return nil, err
+	}
+	return berConstructed(tag, berString(tagOctetString, attr), berString(tagOctetString, value)), nil
+}
+
+func (p *filterParser) substrings(attr, raw string) ([]byte, error) {
+	parts := strings.Split(raw, "*")
+	encoded := [][]byte{}
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		value, err := p.unescape(part)
+		if err != nil {
+			return nil, err
+		}
+		tag := byte(substringAny)
+		switch i {
+		case 0:
+			tag = substringInitial
+		case len(parts) - 1:
+			tag = substringFinal
+		}
+		encoded = append(encoded, berString(tag, value))
+	}
+	return berConstructed(filterSubstrings,
+		berString(tagOctetString, attr),
+		berConstructed(tagSequence, encoded...),
+	), nil
+}
+
+// unescape decodes the \XX escapes of an assertion value.
+func (p *filterParser) unescape(raw string) (string, error) {
+	if !strings.Contains(raw, `\`) {
+		return raw, nil
+	}
+	var sb strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			sb.WriteByte(raw[i])
+			continue
+		}
+		if i+3 > len(raw) {
+			return "", p.errorf("truncated escape in %q", raw)
+		}
+		decoded, err := hex.DecodeString(raw[i+1 : i+3])
+		if err != nil {
+			return "", p.errorf("invalid escape in %q", raw)
+		}
+		sb.Write(decoded)
+		i += 2
+	}
+	return sb.String(), nil
+}
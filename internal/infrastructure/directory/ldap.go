@@ -0,0 +1,387 @@
+package directory
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+const (
+	// pagedResultsOID is the Simple Paged Results control (RFC 2696).
+	pagedResultsOID = "1.2.840.113556.1.4.319"
+
+	// Active Directory marks disabled accounts with bit 2 of
+	// userAccountControl; other servers do not return the attribute.
+	userAccountControlAttr = "userAccountControl"
+	accountDisabled        = 0x2
+)
+
+// LDAPConfig points an LDAPSource at an LDAP v3 server. URL is ldap:// or
+// ldaps://host[:port]; users are the entries below BaseDN matching
+// UserFilter, read with a simple bind as BindDN, or anonymously when it is
+// empty.
+type LDAPConfig struct {
+	URL          string
+	BindDN       string
+	BindPassword string
+	BaseDN       string
+	UserFilter   string
+	UserIDAttr   string
+	NameAttr     string
+	GroupAttr    string
+	GroupPrefix  string
+	Timeout      time.Duration
+}
+
+// LDAPSource reads users with a paged subtree search. A user's user_id is
+// the UserIDAttr value, their username the NameAttr value, and their groups
+// the GroupAttr values: either group names or group DNs like memberOf
+// returns, named by their first RDN.
+type LDAPSource struct {
+	cfg    LDAPConfig
+	addr   string
+	host   string
+	tls    bool
+	filter []byte
+}
+
+var _ repository.DirectorySource = (*LDAPSource)(nil)
+
+// NewLDAPSource checks the URL and compiles the user filter, so a broken
+// configuration fails at startup instead of on every sync.
+func NewLDAPSource(cfg LDAPConfig) (*LDAPSource, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("ldap url: %w", err)
+	}
+	s := &LDAPSource{cfg: cfg, host: u.Hostname()}
+	port := u.Port()
+	switch u.Scheme {
+	case "ldap":
+		if port == "" {
+			port = "389"
+		}
+	case "ldaps":
+		s.tls = true
+		if port == "" {
+			port = "636"
+		}
+	default:
+		return nil, fmt.Errorf("ldap url: unsupported scheme %q", u.Scheme)
+	}
+	if s.host == "" {
+		return nil, errors.New("ldap url: host is required")
+	}
+	s.addr = net.JoinHostPort(s.host, port)
+
+	s.filter, err = encodeFilter(cfg.UserFilter)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *LDAPSource) Users(ctx context.Context) ([]models.DirectoryUser, error) {
+	c, err := s.dial(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ldap: %w", err)
+	}
+	defer c.close()
+
+	users, err := s.readUsers(c)
+	if err != nil && ctx.Err() != nil {
+		// The connection was cut because ctx is done.
+		return nil, ctx.Err()
+	}
+	return users, err
+}
+
+func (s *LDAPSource) readUsers(c *ldapConn) ([]models.DirectoryUser, error) {
+	if s.cfg.BindDN != "" {
+		if err := c.bind(s.cfg.BindDN, s.cfg.BindPassword); err != nil {
+			return nil, err
+		}
+	}
+
+	attrs := []string{s.cfg.UserIDAttr, s.cfg.NameAttr, s.cfg.GroupAttr, userAccountControlAttr}
+	users := []models.DirectoryUser{}
+	var cookie []byte
+	for {
+		entries, next, err := c.search(s.cfg.BaseDN, s.filter, attrs, cookie)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if user, ok := s.directoryUser(e); ok {
+				users = append(users, user)
+			}
+		}
+		if len(next) == 0 {
+			return users, nil
+		}
+		cookie = next
+	}
+}
+
+// directoryUser maps an entry's attributes, keyed by their lowercased
+// names, to a directory user. Entries without an ID are ignored.
+func (s *LDAPSource) directoryUser(attrs map[string][]string) (models.DirectoryUser, bool) {
+	first := func(name string) string {
+		if values := attrs[strings.ToLower(name)]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	user := models.DirectoryUser{
+		UserID:   first(s.cfg.UserIDAttr),
+		Username: first(s.cfg.NameAttr),
+		Active:   true,
+	}
+	if user.UserID == "" {
+		return user, false
+	}
+
+	groups := []string{}
+	for _, v := range attrs[strings.ToLower(s.cfg.GroupAttr)] {
+		groups = append(groups, groupName(v))
+	}
+	user.TeamName = teamFor(groups, s.cfg.GroupPrefix)
+
+	if flags, err := strconv.Atoi(first(userAccountControlAttr)); err == nil && flags&accountDisabled != 0 {
+		user.Active = false
+	}
+	return user, true
+}
+
+// groupName returns the value of a DN's first RDN, so
+// "cn=backend,ou=groups,dc=example,dc=com" names the group backend. Values
+// that are not DNs are names already.
+func groupName(dn string) string {
+	eq := strings.IndexByte(dn, '=')
+	if eq < 0 {
+		return dn
+	}
+	var sb strings.Builder
+	for i := eq + 1; i < len(dn); i++ {
+		switch c := dn[i]; {
+		case c == '\\' && i+1 < len(dn):
+			i++
+			sb.WriteByte(dn[i])
+		case c == ',' || c == '+':
+			return sb.String()
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// ldapConn is one LDAP session. Requests are sent one at a time, so
+// responses are read in order.
+type ldapConn struct {
+	conn   net.Conn
+	r      *bufio.Reader
+	lastID int
+	stop   func() bool
+}
+
+// dial connects to the server. The session shares a deadline of one
+// timeout, and is cut when ctx is done.
+func (s *LDAPSource) dial(ctx context.Context) (*ldapConn, error) {
+	dialer := &net.Dialer{Timeout: s.cfg.Timeout}
+	var conn net.Conn
+	var err error
+	if s.tls {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.host}}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", s.addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", s.addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cfg.Timeout > 0 {
+		deadline := time.Now().Add(s.cfg.Timeout)
+		if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+			deadline = d
+		}
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return &ldapConn{
+		conn: conn,
+		r:    bufio.NewReader(conn),
+		stop: context.AfterFunc(ctx, func() { conn.Close() }),
+	}, nil
+}
+
+func (c *ldapConn) close() {
+	c.stop()
+	_ = c.send(berElement(tagUnbindRequest, nil))
+	c.conn.Close()
+}
+
+func (c *ldapConn) send(op []byte, controls ...[]byte) error {
+	c.lastID++
+	parts := [][]byte{berInt(tagInteger, c.lastID), op}
+	if len(controls) > 0 {
+		parts = append(parts, berConstructed(tagControls, controls...))
+	}
+	_, err := c.conn.Write(berConstructed(tagSequence, parts...))
+	return err
+}
+
+// receive reads the next response to the last request: the message ID,
+// operation and controls.
+func (c *ldapConn) receive() (*berPacket, error) {
+	for {
+		msg, err := readPacket(c.r)
+		if err != nil {
+			return nil, fmt.Errorf("ldap: %w", err)
+		}
+		if msg.tag != tagSequence || len(msg.children) < 2 {
+			return nil, errors.New("ldap: malformed message")
+		}
+		switch msg.child(0).integer() {
+		case c.lastID:
+			return msg, nil
+		case 0:
+			// An unsolicited notification, sent before the server
+			// drops the connection.
+			if err := resultError("server notice", msg.child(1)); err != nil {
+				return nil, err
+			}
+			return nil, errors.New("ldap: connection closed by the server")
+		}
+	}
+}
+
+func (c *ldapConn) bind(dn, password string) error {
+	err := c.send(berConstructed(tagBindRequest,
+		berInt(tagInteger, 3),
+		berString(tagOctetString, dn),
+		berString(tagSimpleAuth, password),
+	))
+	if err != nil {
+		return fmt.Errorf("ldap bind: %w", err)
+	}
+	msg, err := c.receive()
+	if err != nil {
+		return err
+	}
+	if msg.child(1).tag != tagBindResponse {
+		return errors.New("ldap bind: unexpected response")
+	}
+	return resultError("bind", msg.child(1))
+}
+
+// search runs one page of a subtree search, returning the entries'
+// attributes and the cookie of the next page, empty after the last.
+func (c *ldapConn) search(baseDN string, filter []byte, attrs []string, cookie []byte) ([]map[string][]string, []byte, error) {
+	attrList := [][]byte{}
+	for _, a := range attrs {
+		attrList = append(attrList, berString(tagOctetString, a))
+	}
+	paging := berConstructed(tagSequence,
+		berInt(tagInteger, defaultPageSize),
+		berElement(tagOctetString, cookie),
+	)
+	err := c.send(
+		berConstructed(tagSearchRequest,
+			berString(tagOctetString, baseDN),
+			berInt(tagEnumerated, 2), // wholeSubtree
+			berInt(tagEnumerated, 0), // neverDerefAliases
+			berInt(tagInteger, 0),    // no size limit
+			berInt(tagInteger, 0),    // no time limit
+			berBool(false),
+			filter,
+			berConstructed(tagSequence, attrList...),
+		),
+		berConstructed(tagSequence,
+			berString(tagOctetString, pagedResultsOID),
+			berElement(tagOctetString, paging),
+		),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ldap search: %w", err)
+	}
+
+	entries := []map[string][]string{}
+	for {
+		msg, err := c.receive()
+		if err != nil {
+			return nil, nil, err
+		}
+		op := msg.child(1)
+		switch op.tag {
+		case tagSearchResultEntry:
+			entry := make(map[string][]string)
+			for _, attr := range op.child(1).children {
+				name := strings.ToLower(attr.child(0).str())
+				for _, v := range attr.child(1).children {
+					entry[name] = append(entry[name], v.str())
+				}
+			}
+			entries = append(entries, entry)
+		case tagSearchResultRef:
+			// Referrals to other servers are not followed.
+		case tagSearchResultDone:
+			if err := resultError("search", op); err != nil {
+				return nil, nil, err
+			}
+			return entries, nextPageCookie(msg), nil
+		default:
+			return nil, nil, errors.New("ldap search: unexpected response")
+		}
+	}
+}
+
+// nextPageCookie returns the cookie of the paged results control in a
+// SearchResultDone message, if any.
+func nextPageCookie(msg *berPacket) []byte {
+	controls := msg.child(2)
+	if controls.tag != tagControls {
+		return nil
+	}
+	for _, control := range controls.children {
+		if control.child(0).str() != pagedResultsOID {
+			continue
+		}
+		value := control.children[len(control.children)-1]
+		paging, err := parsePacket(tagSequence, value.value)
+		if err != nil || len(paging.children) != 1 {
+			return nil
+		}
+		return paging.child(0).child(1).value
+	}
+	return nil
+}
+
+// resultError reports a failed LDAPResult; a partial search result, like
+// one cut by a size limit, is a failure too, since the sync must see every
+// user.
+func resultError(operation string, result *berPacket) error {
+	code := result.child(0).integer()
+	if code == 0 {
+		return nil
+	}
+	msg := result.child(2).str()
+	if msg == "" {
+		return fmt.Errorf("ldap %s: result code %d", operation, code)
+	}
+	return fmt.Errorf("ldap %s: result code %d: %s", operation, code, msg)
+}
@@ -0,0 +1,144 @@
+package directory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// SCIMConfig points a SCIMSource at a SCIM 2.0 service provider. URL is the
+// base the /Users and /Groups endpoints live under; Token, when set, is
+// sent as a bearer token.
+type SCIMConfig struct {
+	URL         string
+	Token       string
+	GroupPrefix string
+	Timeout     time.Duration
+}
+
+// SCIMSource reads users from /Users and team membership from the members
+// of /Groups. A user's user_id is their userName and their username the
+// displayName, falling back to the formatted name.
+type SCIMSource struct {
+	cfg    SCIMConfig
+	client *http.Client
+}
+
+var _ repository.DirectorySource = (*SCIMSource)(nil)
+
+func NewSCIMSource(cfg SCIMConfig) *SCIMSource {
+	cfg.URL = strings.TrimRight(cfg.URL, "/")
+	return &SCIMSource{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+type scimUser struct {
+	ID          string `json:"id"`
+	UserName    string `json:"userName"`
+	DisplayName string `json:"displayName"`
+	Name        struct {
+		Formatted string `json:"formatted"`
+	} `json:"name"`
+	// Active is optional; users without it count as active.
+	Active *bool `json:"active"`
+}
+
+type scimGroup struct {
+	DisplayName string `json:"displayName"`
+	Members     []struct {
+		Value string `json:"value"`
+	} `json:"members"`
+}
+
+type scimListResponse[T any] struct {
+	TotalResults int `json:"totalResults"`
+	Resources    []T `json:"Resources"`
+}
+
+func (s *SCIMSource) Users(ctx context.Context) ([]models.DirectoryUser, error) {
+	users, err := scimList[scimUser](ctx, s, "/Users")
+	if err != nil {
+		return nil, err
+	}
+	groups, err := scimList[scimGroup](ctx, s, "/Groups")
+	if err != nil {
+		return nil, err
+	}
+
+	memberOf := make(map[string][]string)
+	for _, g := range groups {
+		for _, m := range g.Members {
+			memberOf[m.Value] = append(memberOf[m.Value], g.DisplayName)
+		}
+	}
+
+	result := make([]models.DirectoryUser, 0, len(users))
+	for _, u := range users {
+		name := u.DisplayName
+		if name == "" {
+			name = u.Name.Formatted
+		}
+		result = append(result, models.DirectoryUser{
+			UserID:   u.UserName,
+			Username: name,
+			TeamName: teamFor(memberOf[u.ID], s.cfg.GroupPrefix),
+			Active:   u.Active == nil || *u.Active,
+		})
+	}
+	return result, nil
+}
+
+// scimList reads every page of a SCIM list endpoint.
+func scimList[T any](ctx context.Context, s *SCIMSource, path string) ([]T, error) {
+	items := []T{}
+	for start := 1; ; {
+		query := url.Values{
+			"startIndex": {strconv.Itoa(start)},
+			"count":      {strconv.Itoa(defaultPageSize)},
+		}
+		var page scimListResponse[T]
+		if err := s.get(ctx, path+"?"+query.Encode(), &page); err != nil {
+			return nil, err
+		}
+		items = append(items, page.Resources...)
+		start += len(page.Resources)
+		if len(page.Resources) == 0 || start > page.TotalResults {
+			return items, nil
+		}
+	}
+}
+
+func (s *SCIMSource) get(ctx context.Context, path string, dst interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.cfg.URL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/scim+json, application/json")
+	if s.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.cfg.Token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("scim: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("scim %s: unexpected status %d", path, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("scim %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Package events implements repository.EventPublisher as an in-process
+// broadcast bus.
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before
+// it starts missing them.
+const subscriberBuffer = 64
+
+// Bus fans published events out to every current subscriber. Events for a
+// subscriber whose buffer is full are dropped rather than blocking the
+// publisher.
+type Bus struct {
+	mu     sync.Mutex
+	nextID uint64
+	subs   map[chan models.Event]struct{}
+	closed bool
+}
+
+var _ repository.EventPublisher = (*Bus)(nil)
+
+func NewBus() *Bus {
+	return &Bus{subs: make(map[chan models.Event]struct{})}
+}
+
+func (b *Bus) Publish(_ context.Context, event models.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.nextID++
+	event.ID = b.nextID
+	for ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("Event bus: subscriber is behind, dropped event %d (%s)", event.ID, event.Type)
+		}
+	}
+}
+
+// Subscribe returns a channel receiving every event published from now on
+// and a function that ends the subscription. The channel is closed when the
+// subscription ends or the bus is closed.
+func (b *Bus) Subscribe() (<-chan models.Event, func()) {
+	ch := make(chan models.Event, subscriberBuffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subs[ch] = struct{}{}
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[ch]; ok {
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Close ends all subscriptions, letting streaming clients finish before the
+// server shuts down.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subs {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,36 @@
+package events
+
+import (
+	"context"
+	"log"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// EventStore is where Log appends events.
+type EventStore interface {
+	AppendEvent(ctx context.Context, event models.Event) error
+}
+
+// Log appends every event to the event log before handing it on, so that
+// what derives from the events can be rebuilt by replaying them. Events
+// are logged after their change is committed: one that fails to be logged
+// is reported and still handed on.
+type Log struct {
+	store EventStore
+	next  repository.EventPublisher
+}
+
+var _ repository.EventPublisher = (*Log)(nil)
+
+func NewLog(store EventStore, next repository.EventPublisher) *Log {
+	return &Log{store: store, next: next}
+}
+
+func (l *Log) Publish(ctx context.Context, event models.Event) {
+	if err := l.store.AppendEvent(ctx, event); err != nil {
+		log.Printf("Event log: cannot append %s of PR %s: %v", event.Type, event.PullRequestID, err)
+	}
+	l.next.Publish(ctx, event)
+}
@@ -0,0 +1,190 @@
+// Package github requests reviews on GitHub PRs through its REST API, which
+// both github.com and GitHub Enterprise Server serve.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// Config points a Requester at the GitHub API: https://api.github.com, or
+// https://HOST/api/v3 for GitHub Enterprise Server. Token is a token
+// allowed to write pull requests of the repositories.
+//
+// Repositories maps the repositories of local PRs to GitHub's owner/repo;
+// PRs of other repositories are not on GitHub. A PR is on GitHub when its
+// ID refers to it as GitHub does, owner/repo#number, with the owner/repo
+// its repository maps to; IDs of other hosts or made up locally are not.
+type Config struct {
+	URL          string
+	Token        string
+	Repositories map[string]string
+	Timeout      time.Duration
+}
+
+// Requester is a repository.ReviewRequester asking for reviews through the
+// requested reviewers of GitHub PRs.
+type Requester struct {
+	cfg    Config
+	client *http.Client
+	now    func() time.Time
+
+	// mu guards resetAt, until which GitHub refuses calls because the
+	// token's rate limit is used up.
+	mu      sync.Mutex
+	resetAt time.Time
+}
+
+var _ repository.ReviewRequester = (*Requester)(nil)
+
+func New(cfg Config) *Requester {
+	cfg.URL = strings.TrimRight(cfg.URL, "/")
+	return &Requester{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		now:    time.Now,
+	}
+}
+
+// prRef matches GitHub's references to PRs, owner/repo#number.
+var prRef = regexp.MustCompile(`^([A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)#([0-9]+)$`)
+
+func (r *Requester) Hosts(pr models.PullRequest) bool {
+	return r.path(pr) != ""
+}
+
+func (r *Requester) RequestedReviewers(ctx context.Context, pr models.PullRequest) ([]string, error) {
+	var requested struct {
+		Users []struct {
+			Login string `json:"login"`
+		} `json:"users"`
+	}
+	if err := r.do(ctx, http.MethodGet, r.path(pr), nil, &requested); err != nil {
+		return nil, err
+	}
+	logins := make([]string, 0, len(requested.Users))
+	for _, u := range requested.Users {
+		logins = append(logins, u.Login)
+	}
+	return logins, nil
+}
+
+func (r *Requester) RequestReviewers(ctx context.Context, pr models.PullRequest, logins []string) error {
+	return r.do(ctx, http.MethodPost, r.path(pr), map[string]interface{}{"reviewers": logins}, nil)
+}
+
+func (r *Requester) RemoveReviewRequests(ctx context.Context, pr models.PullRequest, logins []string) error {
+	return r.do(ctx, http.MethodDelete, r.path(pr), map[string]interface{}{"reviewers": logins}, nil)
+}
+
+// path returns the API path of the requested reviewers of the GitHub PR
+// pr stands for, or "" when it is not on GitHub.
+func (r *Requester) path(pr models.PullRequest) string {
+	repo := r.cfg.Repositories[pr.Repository]
+	ref := prRef.FindStringSubmatch(pr.PullRequestID)
+	if repo == "" || ref == nil || !strings.EqualFold(ref[1], repo) {
+		return ""
+	}
+	return "/repos/" + repo + "/pulls/" + ref[2] + "/requested_reviewers"
+}
+
+func (r *Requester) do(ctx context.Context, method, path string, body, dst interface{}) error {
+	if path == "" {
+		return fmt.Errorf("github: %w: PR is not on GitHub", repository.ErrRejected)
+	}
+	// Calls made while the rate limit is used up would only be refused.
+	r.mu.Lock()
+	resetAt := r.resetAt
+	r.mu.Unlock()
+	if r.now().Before(resetAt) {
+		return &repository.RateLimitError{Reset: resetAt}
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, r.cfg.URL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("Authorization", "Bearer "+r.cfg.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("github: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if limited := r.rateLimit(resp); limited != nil {
+		return limited
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("github %s %s: unexpected status %d: %s", method, path, resp.StatusCode, bytes.TrimSpace(msg))
+		// GitHub answers 422 to reviewers who cannot review the PR, such
+		// as its author, and 404 when the PR is not there.
+		if resp.StatusCode == http.StatusUnprocessableEntity || resp.StatusCode == http.StatusNotFound {
+			err = fmt.Errorf("%w: %w", repository.ErrRejected, err)
+		}
+		return err
+	}
+	if dst == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("github %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// rateLimit records when the rate limit resets once a response used it up,
+// and returns the error of a response refused for exceeding it: a 403 or
+// 429 with no requests remaining, or with Retry-After as secondary limits
+// answer.
+func (r *Requester) rateLimit(resp *http.Response) error {
+	var reset time.Time
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if sec, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			reset = time.Unix(sec, 0)
+		}
+	}
+	refused := resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests
+	if sec, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && refused {
+		reset = r.now().Add(time.Duration(sec) * time.Second)
+	}
+	if reset.IsZero() {
+		return nil
+	}
+
+	r.mu.Lock()
+	if reset.After(r.resetAt) {
+		r.resetAt = reset
+	}
+	r.mu.Unlock()
+	if !refused {
+		return nil
+	}
+	return &repository.RateLimitError{Reset: reset}
+}
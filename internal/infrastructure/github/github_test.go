@@ -0,0 +1,137 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+var now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// newRequester returns a Requester for a GitHub served by handler, with
+// repository api mapped to acme/api.
+func newRequester(t *testing.T, handler http.HandlerFunc) *Requester {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	r := New(Config{URL: srv.URL + "/", Token: "t0ken", Repositories: map[string]string{"api": "acme/api"}, Timeout: time.Second})
+	r.now = func() time.Time { return now }
+	return r
+}
+
+func TestPath(t *testing.T) {
+	r := New(Config{Repositories: map[string]string{"api": "acme/api"}})
+	tests := []struct {
+		id, repository string
+		want           string
+	}{
+		{"acme/api#1042", "api", "/repos/acme/api/pulls/1042/requested_reviewers"},
+		{"Acme/API#1042", "api", "/repos/acme/api/pulls/1042/requested_reviewers"},
+		{"acme/api#1042", "web", ""},
+		{"acme/web#1042", "api", ""},
+		{"pr-1042", "api", ""},
+		{"gerrit:1042", "api", ""},
+		{"bitbucket:acme:api:1042", "api", ""},
+		{"acme/api#1042x", "api", ""},
+	}
+	for _, tt := range tests {
+		pr := models.PullRequest{PullRequestID: tt.id, Repository: tt.repository}
+		if got := r.path(pr); got != tt.want {
+			t.Errorf("path(%s in %s) = %q, want %q", tt.id, tt.repository, got, tt.want)
+		}
+		if r.Hosts(pr) != (tt.want != "") {
+			t.Errorf("Hosts(%s in %s) = %v", tt.id, tt.repository, r.Hosts(pr))
+		}
+	}
+}
+
+func TestRequestedReviewers(t *testing.T) {
+	r := newRequester(t, func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/repos/acme/api/pulls/7/requested_reviewers" || req.Header.Get("Authorization") != "Bearer t0ken" {
+			t.Errorf("request %s %s, authorization %q", req.Method, req.URL.Path, req.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"users": [{"login": "Bob"}, {"login": "carol"}]}`))
+	})
+
+	logins, err := r.RequestedReviewers(context.Background(), models.PullRequest{PullRequestID: "acme/api#7", Repository: "api"})
+	if err != nil || len(logins) != 2 || logins[0] != "Bob" {
+		t.Errorf("RequestedReviewers = %v, %v; want [Bob carol]", logins, err)
+	}
+	if err := r.RequestReviewers(context.Background(), models.PullRequest{PullRequestID: "pr-7", Repository: "api"}, []string{"bob"}); !errors.Is(err, repository.ErrRejected) {
+		t.Errorf("PR not on GitHub: error = %v, want %v", err, repository.ErrRejected)
+	}
+}
+
+func TestRejected(t *testing.T) {
+	pr := models.PullRequest{PullRequestID: "acme/api#7", Repository: "api"}
+	for _, tt := range []struct {
+		status   int
+		rejected bool
+	}{
+		{http.StatusUnprocessableEntity, true},
+		{http.StatusNotFound, true},
+		{http.StatusInternalServerError, false},
+	} {
+		r := newRequester(t, func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(tt.status)
+		})
+		err := r.RequestReviewers(context.Background(), pr, []string{"bob"})
+		if err == nil || errors.Is(err, repository.ErrRejected) != tt.rejected {
+			t.Errorf("status %d: error = %v, want rejected %v", tt.status, err, tt.rejected)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	pr := models.PullRequest{PullRequestID: "acme/api#7", Repository: "api"}
+	reset := now.Add(10 * time.Minute).Truncate(time.Second)
+	calls := 0
+	r := newRequester(t, func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	var limited *repository.RateLimitError
+	if err := r.RequestReviewers(context.Background(), pr, []string{"bob"}); !errors.As(err, &limited) || !limited.Reset.Equal(reset) {
+		t.Fatalf("exhausted limit: error = %v, want a rate limit until %s", err, reset)
+	}
+	// Until the reset, calls are refused without asking GitHub.
+	if err := r.RemoveReviewRequests(context.Background(), pr, []string{"bob"}); !errors.As(err, &limited) || calls != 1 {
+		t.Errorf("call before reset: error = %v after %d calls, want a rate limit after 1", err, calls)
+	}
+	r.now = func() time.Time { return reset.Add(time.Second) }
+	if r.RequestReviewers(context.Background(), pr, []string{"bob"}); calls != 2 {
+		t.Errorf("call after reset made %d calls, want 2", calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	pr := models.PullRequest{PullRequestID: "acme/api#7", Repository: "api"}
+	r := newRequester(t, func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	var limited *repository.RateLimitError
+	if err := r.RequestReviewers(context.Background(), pr, []string{"bob"}); !errors.As(err, &limited) || !limited.Reset.Equal(now.Add(time.Minute)) {
+		t.Errorf("secondary limit: error = %v, want a rate limit until %s", err, now.Add(time.Minute))
+	}
+
+	// Retry-After on a response that was not refused sets no limit.
+	r = newRequester(t, func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusCreated)
+	})
+	if err := r.RequestReviewers(context.Background(), pr, []string{"bob"}); err != nil {
+		t.Errorf("accepted request: error = %v", err)
+	}
+}
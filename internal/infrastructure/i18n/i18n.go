@@ -0,0 +1,138 @@
+// Package i18n translates the human-readable texts of the service: error
+// messages and notifications. Texts are written in English in the code and
+// looked up in a catalog when another locale is wanted, so error codes and
+// anything clients match on stay the same in every language.
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// entry translates the messages made from format, a fmt format string.
+// Its arguments are put into translation, which refers to them with %s or
+// %[n]s, after being translated themselves.
+type entry struct {
+	pattern     *regexp.Regexp
+	translation string
+}
+
+// verb matches the fmt verbs of catalog formats: %d takes digits, the
+// others any text.
+var verb = regexp.MustCompile(`%%|%d|%(\.\d+)?[sqvf]`)
+
+func compile(catalog map[string]string) []entry {
+	formats := make([]string, 0, len(catalog))
+	for format := range catalog {
+		formats = append(formats, format)
+	}
+	// Longer formats are more specific: "user %s not found in team" has to
+	// win over "user %s not found".
+	sort.Slice(formats, func(i, j int) bool {
+		if len(formats[i]) != len(formats[j]) {
+			return len(formats[i]) > len(formats[j])
+		}
+		return formats[i] < formats[j]
+	})
+
+	entries := make([]entry, len(formats))
+	for i, format := range formats {
+		var expr strings.Builder
+		expr.WriteString("^")
+		last := 0
+		for _, loc := range verb.FindAllStringIndex(format, -1) {
+			expr.WriteString(regexp.QuoteMeta(format[last:loc[0]]))
+			switch v := format[loc[0]:loc[1]]; v {
+			case "%%":
+				expr.WriteString("%")
+			case "%d":
+				expr.WriteString(`(-?\d+)`)
+			default:
+				expr.WriteString("(.*?)")
+			}
+			last = loc[1]
+		}
+		expr.WriteString(regexp.QuoteMeta(format[last:]))
+		expr.WriteString("$")
+		entries[i] = entry{pattern: regexp.MustCompile(expr.String()), translation: catalog[format]}
+	}
+	return entries
+}
+
+var catalogs = map[models.Locale][]entry{
+	models.LocaleRussian: compile(russian),
+}
+
+// Translate returns message in locale, line by line. Lines the catalog of
+// locale does not know, and every line in English or an unknown locale,
+// are returned as they are.
+func Translate(locale models.Locale, message string) string {
+	entries, ok := catalogs[locale]
+	if !ok || message == "" {
+		return message
+	}
+	lines := strings.Split(message, "\n")
+	for i, line := range lines {
+		lines[i] = translateLine(entries, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func translateLine(entries []entry, line string) string {
+	for _, e := range entries {
+		m := e.pattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		args := make([]interface{}, len(m)-1)
+		for i, arg := range m[1:] {
+			args[i] = translateLine(entries, arg)
+		}
+		return fmt.Sprintf(e.translation, args...)
+	}
+	// Lists of problems are joined with "; ", as in invalid CODEOWNERS
+	// files; their parts may be known on their own.
+	if parts := strings.Split(line, "; "); len(parts) > 1 {
+		for i, part := range parts {
+			parts[i] = translateLine(entries, part)
+		}
+		return strings.Join(parts, "; ")
+	}
+	return line
+}
+
+// Negotiate picks the supported locale the Accept-Language header prefers,
+// or "" when it names none.
+func Negotiate(acceptLanguage string) models.Locale {
+	type choice struct {
+		locale models.Locale
+		q      float64
+	}
+	var choices []choice
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		// Only the primary language subtag matters: en-US is English.
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if locale := models.Locale(primary); locale.Valid() && q > 0 {
+			choices = append(choices, choice{locale, q})
+		}
+	}
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].q > choices[j].q })
+	if len(choices) == 0 {
+		return ""
+	}
+	return choices[0].locale
+}
@@ -0,0 +1,215 @@
+package i18n
+
+// russian translates the error messages and notifications of the service.
+// API identifiers such as field names and enum values are kept as they are.
+var russian = map[string]string{
+	// Request handling.
+	"invalid request body":                   "некорректное тело запроса",
+	"request validation failed":              "запрос не прошёл проверку",
+	"resource already exists":                "запись уже существует",
+	"username is already taken":              "имя пользователя уже занято",
+	"referenced resource does not exist":     "запись, на которую ссылается запрос, не существует",
+	"storage is temporarily unavailable":     "хранилище временно недоступно",
+	"request deadline exceeded":              "истекло время обработки запроса",
+	"internal server error":                  "внутренняя ошибка сервера",
+	"method not allowed":                     "метод не поддерживается",
+	"route not found":                        "путь не найден",
+	"bearer token required":                  "нужен bearer-токен",
+	"cannot watch another user's queue":      "нельзя следить за очередью другого пользователя",
+	"only a verified bot can name the actor": "указать actor может только проверенный бот",
+	"%s is required":                         "%s обязателен",
+	"%s must be a boolean":                   "%s должен быть логическим значением",
+	"%s must be an integer":                  "%s должен быть целым числом",
+	"%s must be a non-negative integer":      "%s должен быть неотрицательным целым числом",
+	"%s must be an RFC 3339 timestamp":       "%s должен быть временем в формате RFC 3339",
+	"format must be one of json, csv, xlsx":  "format должен быть одним из json, csv, xlsx",
+
+	// Inbound webhooks.
+	"webhook signature is missing":                   "нет подписи вебхука",
+	"webhook signature is invalid":                   "неверная подпись вебхука",
+	"webhook timestamp is outside the replay window": "время вебхука вне окна повторов",
+	"webhook delivery was received already":          "доставка вебхука уже получена",
+	"webhook body is too large":                      "тело вебхука слишком большое",
+
+	// Maintenance mode.
+	"service is in read-only maintenance mode":                   "сервис в режиме обслуживания и доступен только для чтения",
+	"service is in read-only maintenance mode: %s":               "сервис в режиме обслуживания и доступен только для чтения: %s",
+	"database schema %d is newer than %d this instance supports": "схема базы %s новее версии %s, которую поддерживает этот экземпляр",
+
+	// Field validation.
+	"is required":                    "обязательное поле",
+	"must be at most %d":             "должно быть не больше %s",
+	"must be at least %d":            "должно быть не меньше %s",
+	"must be at most %d characters":  "должно быть не длиннее %s символов",
+	"must be at least %d characters": "должно быть не короче %s символов",
+	"must be at most %d items":       "должно содержать не больше %s элементов",
+	"must be at least %d items":      "должно содержать не меньше %s элементов",
+	"must be one of %s":              "должно быть одним из %s",
+	"members[%d]: %s":                "members[%s]: %s",
+
+	// Lookups.
+	"team not found":                       "команда не найдена",
+	"user not found":                       "пользователь не найден",
+	"deleted user not found":               "удалённый пользователь не найден",
+	"deleted team not found":               "удалённая команда не найдена",
+	"either username or email is required": "нужно указать username или email",
+	"user not found in team":               "пользователь не найден в команде",
+	"user %s not found in team":            "пользователь %s не найден в команде",
+	"author not found":                     "автор не найден",
+	"author of PR %s not found":            "автор PR %s не найден",
+	"manager not found":                    "руководитель не найден",
+	"PR not found":                         "PR не найден",
+	"PR %s not found":                      "PR %s не найден",
+	"old reviewer not found":               "прежний ревьювер не найден",
+	"identity not found":                   "учётная запись не найдена",
+	"exclusion rule not found":             "правило исключения не найдено",
+	"routing rule not found":               "правило маршрутизации не найдено",
+	"repository rule not found":            "правило репозитория не найдено",
+	"repository not found":                 "репозиторий не найден",
+	"feature flag not found":               "флаг функции не найден",
+	"delivery not found":                   "доставка не найдена",
+	"no user is linked to %s identity %s":  "к учётной записи %s %s не привязан ни один пользователь",
+
+	// Conflicts.
+	"team_name already exists":                                              "команда с таким team_name уже существует",
+	"PR id already exists":                                                  "PR с таким id уже существует",
+	"repository already exists":                                             "репозиторий уже существует",
+	"%s identity %s is linked to another user":                              "учётная запись %s %s привязана к другому пользователю",
+	"restore needs an empty database":                                       "восстановление возможно только в пустую базу",
+	"PR is on hold":                                                         "PR приостановлен",
+	"PR has %d of %d required approvals":                                    "у PR %s из %s необходимых одобрений",
+	"no active replacement candidate in team":                               "в команде нет активного кандидата на замену",
+	"team requires a senior reviewer, but no senior candidate is available": "команде нужен senior-ревьювер, но подходящего кандидата нет",
+	"reassignment is older than %s and can no longer be undone":             "переназначение старше %s и уже не может быть отменено",
+	"PR has no reassignment to undo":                                        "у PR нет переназначения для отмены",
+	"previous reviewer %s is no longer active":                              "прежний ревьювер %s больше не активен",
+	"only dead deliveries can be retried":                                   "повторить можно только доставку в статусе dead",
+	"reviewer %s is no longer assigned to this PR":                          "ревьювер %s больше не назначен на этот PR",
+
+	// Merged PRs.
+	"cannot reassign on merged PR":           "нельзя переназначить ревьювера в слитом PR",
+	"cannot change reviewers on merged PR":   "нельзя менять ревьюверов слитого PR",
+	"cannot change labels on merged PR":      "нельзя менять метки слитого PR",
+	"cannot update merged PR":                "нельзя изменить слитый PR",
+	"cannot mark merged PR as ready":         "нельзя отметить слитый PR как готовый",
+	"cannot review merged PR":                "нельзя оставить ревью на слитый PR",
+	"cannot suggest reviewers for merged PR": "нельзя подбирать ревьюверов для слитого PR",
+	"cannot undo reassignment on merged PR":  "нельзя отменить переназначение в слитом PR",
+	"cannot swap reviewers on merged PR %s":  "нельзя менять ревьюверов слитого PR %s",
+	"cannot close merged PR":                 "нельзя закрыть слитый PR",
+
+	// Closed PRs.
+	"cannot merge closed PR":                 "нельзя слить закрытый PR",
+	"cannot reassign on closed PR":           "нельзя переназначить ревьювера в закрытом PR",
+	"cannot change reviewers on closed PR":   "нельзя менять ревьюверов закрытого PR",
+	"cannot change labels on closed PR":      "нельзя менять метки закрытого PR",
+	"cannot update closed PR":                "нельзя изменить закрытый PR",
+	"cannot mark closed PR as ready":         "нельзя отметить закрытый PR как готовый",
+	"cannot review closed PR":                "нельзя оставить ревью на закрытый PR",
+	"cannot suggest reviewers for closed PR": "нельзя подбирать ревьюверов для закрытого PR",
+	"cannot undo reassignment on closed PR":  "нельзя отменить переназначение в закрытом PR",
+	"cannot swap reviewers on closed PR %s":  "нельзя менять ревьюверов закрытого PR %s",
+
+	// Reviewers.
+	"reviewer is not assigned to this PR":                  "ревьювер не назначен на этот PR",
+	"reviewer %s is already on this PR":                    "ревьювер %s уже назначен на этот PR",
+	"reviewer %s is already on PR %s":                      "ревьювер %s уже назначен на PR %s",
+	"reviewer %s is not assigned to PR %s":                 "ревьювер %s не назначен на PR %s",
+	"reviewer %s %s":                                       "ревьювер %s %s",
+	"reviewer %s %s (PR %s)":                               "ревьювер %s %s (PR %s)",
+	"PR already has %d reviewers":                          "у PR уже %s ревьюверов",
+	"at most %d preferred_reviewers allowed":               "допускается не больше %s preferred_reviewers",
+	"preferred reviewer %s %s":                             "предпочтительный ревьювер %s %s",
+	"is the author":                                        "является автором",
+	"is not in the author's team":                          "не состоит в команде автора",
+	"is not active":                                        "не активен",
+	"is a mentee":                                          "является менти",
+	"is excluded from the author's PRs":                    "исключён из ревью PR автора",
+	"cannot swap reviewers within one PR":                  "нельзя поменять ревьюверов внутри одного PR",
+	"cannot swap a reviewer with themselves":               "нельзя поменять ревьювера с самим собой",
+	"PRs belong to different teams":                        "PR относятся к разным командам",
+	"only the PR author or the reviewer can reassign them": "переназначить ревьювера может только автор PR или сам ревьювер",
+	"only the PR author and reviewers can hold it":         "приостановить PR могут только его автор и ревьюверы",
+	"a user cannot be their own manager":                   "пользователь не может быть своим руководителем",
+	"manager_id would make the user their own manager":     "с таким manager_id пользователь станет своим же руководителем",
+
+	// Input checks.
+	"team_name is required":                                                                "team_name обязателен",
+	"actor is required":                                                                    "actor обязателен",
+	"repository is required":                                                               "repository обязателен",
+	"external_id is required":                                                              "external_id обязателен",
+	"team_name, label and user_id are required":                                            "team_name, label и user_id обязательны",
+	"team_name, repository and user_ids are required":                                      "team_name, repository и user_ids обязательны",
+	"team_name, reviewer_id and author_id are required":                                    "team_name, reviewer_id и author_id обязательны",
+	"either pull_request_id or author_id is required":                                      "нужен pull_request_id или author_id",
+	"reviewer_id and author_id must differ":                                                "reviewer_id и author_id должны различаться",
+	"status must be OPEN, MERGED or CLOSED":                                                "status должен быть OPEN, MERGED или CLOSED",
+	"status must be pending, delivered or dead":                                            "status должен быть pending, delivered или dead",
+	"priority must be LOW, NORMAL or URGENT":                                               "priority должен быть LOW, NORMAL или URGENT",
+	"state must be one of APPROVED, CHANGES_REQUESTED, COMMENTED":                          "state должен быть одним из APPROVED, CHANGES_REQUESTED, COMMENTED",
+	"role must be reviewer or shadow":                                                      "role должен быть reviewer или shadow",
+	"seniority must be junior, middle or senior":                                           "seniority должен быть junior, middle или senior",
+	"pull_request_name must not be empty":                                                  "pull_request_name не может быть пустым",
+	"lines_changed and files_changed must not be negative":                                 "lines_changed и files_changed не могут быть отрицательными",
+	"count must not be negative":                                                           "count не может быть отрицательным",
+	"days must be between 1 and 365":                                                       "days должен быть от 1 до 365",
+	"weeks must be between 1 and %d":                                                       "weeks должен быть от 1 до %s",
+	"since must be before until":                                                           "since должен быть раньше until",
+	"range must end after it starts":                                                       "конец периода должен быть позже начала",
+	"unknown metric %s":                                                                    "неизвестная метрика %s",
+	"annotations must be merges or escalations":                                            "аннотации должны быть merges или escalations",
+	"method must be moving_average or exponential_smoothing":                               "method должен быть moving_average или exponential_smoothing",
+	"name must be 1-64 lowercase letters, digits or underscores":                           "name должен состоять из 1-64 строчных латинских букв, цифр или подчёркиваний",
+	"name must be a repository name without components":                                    "name должен быть именем репозитория без компонентов",
+	"percentage must be between 0 and 100":                                                 "percentage должен быть от 0 до 100",
+	"reviewer_count must be between 0 and 10":                                              "reviewer_count должен быть от 0 до 10",
+	"required_approvals must be between 0 and 10":                                          "required_approvals должен быть от 0 до 10",
+	"review_weight must be greater than 0 and at most 10":                                  "review_weight должен быть больше 0 и не больше 10",
+	"unknown assignment_strategy":                                                          "неизвестная assignment_strategy",
+	"max_open_reviews must not be negative":                                                "max_open_reviews не может быть отрицательным",
+	"stale_threshold_hours must not be negative":                                           "stale_threshold_hours не может быть отрицательным",
+	"escalation hours must not be negative":                                                "часы эскалации не могут быть отрицательными",
+	"remind_after_hours, escalate_after_hours and reassign_after_hours must increase":      "remind_after_hours, escalate_after_hours и reassign_after_hours должны возрастать",
+	"notification_channel must be an http(s) URL":                                          "notification_channel должен быть http(s) URL",
+	"notification_provider must be webhook, slack, msteams or mattermost":                  "notification_provider должен быть webhook, slack, msteams или mattermost",
+	"notification_template: %s":                                                            "notification_template: %s",
+	"locale must be en or ru":                                                              "locale должен быть en или ru",
+	"channel must be an http(s) URL":                                                       "channel должен быть http(s) URL",
+	"provider must be webhook, slack, msteams or mattermost":                               "provider должен быть webhook, slack, msteams или mattermost",
+	"provider must be github, gitlab, slack, email, jira, bitbucket or gerrit":             "provider должен быть github, gitlab, slack, email, jira, bitbucket или gerrit",
+	"send_at must look like 09:00":                                                         "send_at должен иметь вид 09:00",
+	"timezone must be an IANA time zone name, e.g. Europe/Moscow":                          "timezone должен быть именем часового пояса IANA, например Europe/Moscow",
+	"working_hours must look like 09:00-18:00":                                             "working_hours должен иметь вид 09:00-18:00",
+	"external_id must be a %s login":                                                       "external_id должен быть логином %s",
+	"external_id must be a Slack member ID":                                                "external_id должен быть ID участника Slack",
+	"external_id must be an email address":                                                 "external_id должен быть адресом электронной почты",
+	"external_id must be a Jira account ID or username":                                    "external_id должен быть ID аккаунта или именем пользователя Jira",
+	"external_id must be a Bitbucket account ID or username":                               "external_id должен быть ID аккаунта или именем пользователя Bitbucket",
+	"change.number is required":                                                            "change.number обязателен",
+	"external_id must be a Gerrit username":                                                "external_id должен быть именем пользователя Gerrit",
+	"action must be opened, updated, merged, declined, reviewer_added or reviewer_removed": "action должен быть opened, updated, merged, declined, reviewer_added или reviewer_removed",
+	"repository %s is not registered":                                                      "репозиторий %s не зарегистрирован",
+	"unsupported backup format, want %d":                                                   "неподдерживаемый формат резервной копии, нужен %s",
+	"invalid CODEOWNERS: %s":                                                               "некорректный CODEOWNERS: %s",
+	"line %d: %s":                                                                          "строка %s: %s",
+	"line %d: unknown owner %s":                                                            "строка %s: неизвестный владелец %s",
+	"line %d: owner %s is not a user ID":                                                   "строка %s: владелец %s не является ID пользователя",
+	"and %d more":                                                                          "и ещё %s",
+	"negated patterns are not supported":                                                   "шаблоны с отрицанием не поддерживаются",
+	"character classes and escapes are not supported":                                      "классы символов и экранирование не поддерживаются",
+	"empty pattern":                      "пустой шаблон",
+	"no command found; supported: %s":    "команда не найдена; поддерживаются: %s",
+	"unknown command %s; supported: %s":  "неизвестная команда %s; поддерживаются: %s",
+	"directory sync is not configured":   "синхронизация с каталогом не настроена",
+	"directory source returned no users": "каталог не вернул ни одного пользователя",
+	"directory source: %s":               "каталог: %s",
+
+	// Notifications.
+	"PR %s is stale and still waiting for review":                "PR %s устарел и всё ещё ждёт ревью",
+	"PR %s has been waiting for your review for %d hours":        "PR %s ждёт вашего ревью уже %s ч",
+	"%s has not reviewed PR %s for %d hours":                     "%s не проверяет PR %s уже %s ч",
+	"Review digest: %d open, %d overdue, %d new since yesterday": "Сводка ревью: открыто %s, просрочено %s, новых со вчерашнего дня %s",
+	"- %s (overdue)": "- %s (просрочен)",
+	"%s got %d of %d review assignments of team %s in the last %d days (%.0f%%)":    "%[1]s получил %[2]s из %[3]s назначений ревьюверов команды %[4]s за последние %[5]s дн. (%[6]s%%)",
+	"Team %s merged %d PRs in the last week, %.0f%% below its weekly average of %s": "Команда %s слила за последнюю неделю %s PR, на %s%% меньше своего среднего за неделю (%s)",
+}
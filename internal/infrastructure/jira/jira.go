@@ -0,0 +1,197 @@
+// Package jira files review tasks in Jira through its REST API v2, which
+// both Jira Cloud and Jira Server/Data Center serve.
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// Config points a Tracker at a Jira site. With User the Token is an API
+// token sent with basic auth, as Jira Cloud expects; without it, a personal
+// access token sent as a bearer token, as Jira Server expects.
+//
+// Review issues are filed as IssueType in the project Projects maps the
+// author's team to, or DefaultProject, and review tasks as SubtaskType
+// under them. Tasks take the transitions named ApproveTransition and
+// MergeTransition. AssigneeField is accountId on Jira Cloud and name on
+// Jira Server.
+type Config struct {
+	URL   string
+	User  string
+	Token string
+
+	Projects          map[string]string
+	DefaultProject    string
+	IssueType         string
+	SubtaskType       string
+	ApproveTransition string
+	MergeTransition   string
+	AssigneeField     string
+	Timeout           time.Duration
+}
+
+// Tracker is a repository.IssueTracker filing review tasks as Jira
+// sub-tasks.
+type Tracker struct {
+	cfg    Config
+	client *http.Client
+}
+
+var _ repository.IssueTracker = (*Tracker)(nil)
+
+func New(cfg Config) *Tracker {
+	cfg.URL = strings.TrimRight(cfg.URL, "/")
+	return &Tracker{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// issueKey matches Jira issue keys such as REV-123.
+var issueKey = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[0-9]+\b`)
+
+// ReferencedIssue returns the first issue key in the PR name.
+func (t *Tracker) ReferencedIssue(pr models.PullRequest) string {
+	return issueKey.FindString(pr.PullRequestName)
+}
+
+func (t *Tracker) CreateIssue(ctx context.Context, pr models.PullRequest, teamName string) (string, error) {
+	project := t.cfg.Projects[teamName]
+	if project == "" {
+		project = t.cfg.DefaultProject
+	}
+	if project == "" {
+		return "", fmt.Errorf("jira: no project for team %s", teamName)
+	}
+	return t.createIssue(ctx, map[string]interface{}{
+		"project":     map[string]string{"key": project},
+		"issuetype":   map[string]string{"name": t.cfg.IssueType},
+		"summary":     "Review " + summary(pr),
+		"description": description(pr),
+	})
+}
+
+func (t *Tracker) CreateTask(ctx context.Context, parentKey string, pr models.PullRequest, reviewer models.User, assignee string) (string, error) {
+	project, _, _ := strings.Cut(parentKey, "-")
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": project},
+		"parent":      map[string]string{"key": parentKey},
+		"issuetype":   map[string]string{"name": t.cfg.SubtaskType},
+		"summary":     fmt.Sprintf("Review by %s: %s", reviewer.Username, summary(pr)),
+		"description": description(pr),
+	}
+	if assignee != "" {
+		fields["assignee"] = map[string]string{t.cfg.AssigneeField: assignee}
+	}
+	return t.createIssue(ctx, fields)
+}
+
+// TransitionTask takes the transition configured for event, when the task
+// offers it in its current status.
+func (t *Tracker) TransitionTask(ctx context.Context, key string, event models.ReviewTaskEvent) error {
+	name := t.cfg.ApproveTransition
+	if event == models.ReviewTaskMerged {
+		name = t.cfg.MergeTransition
+	}
+
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/transitions"
+	var available struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"transitions"`
+	}
+	if err := t.do(ctx, http.MethodGet, path, nil, &available); err != nil {
+		return err
+	}
+	for _, tr := range available.Transitions {
+		if strings.EqualFold(tr.Name, name) {
+			body := map[string]interface{}{"transition": map[string]string{"id": tr.ID}}
+			return t.do(ctx, http.MethodPost, path, body, nil)
+		}
+	}
+	return nil
+}
+
+func (t *Tracker) createIssue(ctx context.Context, fields map[string]interface{}) (string, error) {
+	var created struct {
+		Key string `json:"key"`
+	}
+	err := t.do(ctx, http.MethodPost, "/rest/api/2/issue", map[string]interface{}{"fields": fields}, &created)
+	if err != nil {
+		return "", err
+	}
+	if created.Key == "" {
+		return "", fmt.Errorf("jira: created issue has no key")
+	}
+	return created.Key, nil
+}
+
+func (t *Tracker) do(ctx context.Context, method, path string, body, dst interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, t.cfg.URL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if t.cfg.User != "" {
+		req.SetBasicAuth(t.cfg.User, t.cfg.Token)
+	} else if t.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.cfg.Token)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Jira explains rejected fields in the body; keep it short for logs.
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("jira %s %s: unexpected status %d: %s", method, path, resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	if dst == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("jira %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+func summary(pr models.PullRequest) string {
+	if pr.PullRequestName == "" {
+		return "PR " + pr.PullRequestID
+	}
+	return pr.PullRequestName
+}
+
+func description(pr models.PullRequest) string {
+	if pr.Repository == "" {
+		return "Pull request " + pr.PullRequestID
+	}
+	return fmt.Sprintf("Pull request %s in %s", pr.PullRequestID, pr.Repository)
+}
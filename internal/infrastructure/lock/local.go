@@ -0,0 +1,57 @@
+package lock
+
+import (
+	"context"
+	"sync"
+)
+
+// LocalLocker serializes work within a single process. Locks for keys that
+// are no longer held are removed so the map does not grow without bound.
+type LocalLocker struct {
+	mu    sync.Mutex
+	locks map[string]*localLock
+}
+
+type localLock struct {
+	ch      chan struct{}
+	waiters int
+}
+
+func NewLocalLocker() *LocalLocker {
+	return &LocalLocker{locks: make(map[string]*localLock)}
+}
+
+func (l *LocalLocker) Lock(ctx context.Context, key string) (func(), error) {
+	l.mu.Lock()
+	lk, ok := l.locks[key]
+	if !ok {
+		lk = &localLock{ch: make(chan struct{}, 1)}
+		l.locks[key] = lk
+	}
+	lk.waiters++
+	l.mu.Unlock()
+
+	select {
+	case lk.ch <- struct{}{}:
+		return func() { l.release(key, lk) }, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		lk.waiters--
+		if lk.waiters == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (l *LocalLocker) release(key string, lk *localLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	<-lk.ch
+	lk.waiters--
+	if lk.waiters == 0 {
+		delete(l.locks, key)
+	}
+}
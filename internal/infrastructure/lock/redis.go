@@ -0,0 +1,84 @@
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+const (
+	redisLockPrefix     = "pr-reviewer:lock:"
+	redisLockRetryDelay = 50 * time.Millisecond
+)
+
+// unlockScript deletes the lock only if it still holds our token, so an
+// expired lock taken over by another replica is never released by us.
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// RedisLocker implements repository.Locker with SET NX PX locks shared by
+// all replicas. When Redis cannot be reached it falls back to the given
+// in-process locker, which still serializes work within this replica.
+type RedisLocker struct {
+	client   redis.UniversalClient
+	ttl      time.Duration
+	fallback repository.Locker
+}
+
+func NewRedisLocker(client redis.UniversalClient, ttl time.Duration, fallback repository.Locker) *RedisLocker {
+	return &RedisLocker{client: client, ttl: ttl, fallback: fallback}
+}
+
+func (l *RedisLocker) Lock(ctx context.Context, key string) (func(), error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	redisKey := redisLockPrefix + key
+
+	for {
+		ok, err := l.client.SetNX(ctx, redisKey, token, l.ttl).Result()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log.Printf("Redis lock %s unavailable, falling back to local lock: %v", key, err)
+			return l.fallback.Lock(ctx, key)
+		}
+		if ok {
+			return func() { l.unlock(redisKey, token) }, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(redisLockRetryDelay):
+		}
+	}
+}
+
+func (l *RedisLocker) unlock(redisKey, token string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := unlockScript.Run(ctx, l.client, []string{redisKey}, token).Err(); err != nil {
+		log.Printf("Failed to release Redis lock %s: %v", redisKey, err)
+	}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
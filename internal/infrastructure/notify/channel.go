@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// Channel turns a notification into the body a chat's incoming webhook
+// accepts.
+type Channel interface {
+	// Mention returns how the chat mentions a recipient with the given
+	// external identities.
+	Mention(userID string, identities []models.UserIdentity) string
+	// Payload returns the JSON body for n, whose text is rendered already.
+	Payload(n models.Notification, text string) interface{}
+}
+
+// channels are the supported providers. Notifications of an unknown
+// provider go out as plain webhooks.
+var channels = map[models.NotificationProvider]Channel{
+	models.NotifyWebhook:    webhookChannel{},
+	models.NotifySlack:      slackChannel{},
+	models.NotifyMSTeams:    teamsChannel{},
+	models.NotifyMattermost: mattermostChannel{},
+}
+
+// defaultTemplate is used by teams without a template of their own.
+var defaultTemplate = template.Must(template.New("notification").Parse(
+	`{{.Message}}{{range .Mentions}} {{.}}{{end}}`))
+
+// webhookChannel posts the notification itself, leaving its presentation to
+// the receiver. The message is replaced only by a team's own template.
+type webhookChannel struct{}
+
+func (webhookChannel) Mention(userID string, _ []models.UserIdentity) string {
+	return userID
+}
+
+func (webhookChannel) Payload(n models.Notification, text string) interface{} {
+	if n.Template != "" {
+		n.Message = text
+	}
+	return n
+}
+
+// slackChannel mentions users by their Slack member ID.
+type slackChannel struct{}
+
+func (slackChannel) Mention(userID string, identities []models.UserIdentity) string {
+	if id := externalID(identities, userID, models.ProviderSlack); id != "" {
+		return "<@" + id + ">"
+	}
+	return "@" + userID
+}
+
+func (slackChannel) Payload(_ models.Notification, text string) interface{} {
+	return map[string]string{"text": text}
+}
+
+// mattermostChannel mentions users by user_id, which is expected to match
+// their Mattermost username.
+type mattermostChannel struct{}
+
+func (mattermostChannel) Mention(userID string, _ []models.UserIdentity) string {
+	return "@" + userID
+}
+
+func (mattermostChannel) Payload(_ models.Notification, text string) interface{} {
+	return map[string]string{"text": text}
+}
+
+// teamsChannel posts a MessageCard, the format of Microsoft Teams incoming
+// webhooks. Cards cannot mention people by name alone, so recipients are
+// named by their email address when they have one.
+type teamsChannel struct{}
+
+type messageCard struct {
+	Type       string `json:"@type"`
+	Context    string `json:"@context"`
+	Summary    string `json:"summary"`
+	ThemeColor string `json:"themeColor"`
+	Title      string `json:"title"`
+	Text       string `json:"text"`
+}
+
+func (teamsChannel) Mention(userID string, identities []models.UserIdentity) string {
+	if email := externalID(identities, userID, models.ProviderEmail); email != "" {
+		return email
+	}
+	return userID
+}
+
+func (teamsChannel) Payload(n models.Notification, text string) interface{} {
+	return messageCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		Summary:    strings.SplitN(text, "\n", 2)[0],
+		ThemeColor: "0076D7",
+		Title:      "PR " + n.PullRequestID,
+		Text:       text,
+	}
+}
+
+func externalID(identities []models.UserIdentity, userID string, provider models.IdentityProvider) string {
+	for _, identity := range identities {
+		if identity.UserID == userID && identity.Provider == provider {
+			return identity.ExternalID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+// Package notify implements repository.Notifier.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+	"github.com/Thorlik/avito_internship/internal/infrastructure/i18n"
+)
+
+// LogNotifier writes notifications to the service log. It is used when no
+// delivery channel is configured.
+type LogNotifier struct{}
+
+var _ repository.Notifier = LogNotifier{}
+
+func (LogNotifier) Notify(_ context.Context, n models.Notification) error {
+	log.Printf("Notification %s for PR %s (team %s): %s", n.Event, n.PullRequestID, n.TeamName, n.Message)
+	return nil
+}
+
+// WebhookNotifier posts every notification to the incoming webhook of the
+// notification's channel, or to the default URL when it has none, in the
+// format of the notification's provider or the default one and translated
+// to its locale. Notifications with neither URL are logged.
+type WebhookNotifier struct {
+	url      string
+	provider models.NotificationProvider
+	client   *http.Client
+}
+
+var (
+	_ repository.Notifier      = (*WebhookNotifier)(nil)
+	_ repository.WebhookSender = (*WebhookNotifier)(nil)
+)
+
+func NewWebhookNotifier(url string, provider models.NotificationProvider, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:      url,
+		provider: provider,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// DeliveryIDHeader carries the ID of a recorded delivery, the same on
+// every attempt, so receivers can tell a retry from a new notification.
+const DeliveryIDHeader = "X-Delivery-Id"
+
+func (w *WebhookNotifier) Notify(ctx context.Context, n models.Notification) error {
+	d, err := w.Render(n)
+	if err != nil {
+		return err
+	}
+	if d == nil {
+		return LogNotifier{}.Notify(ctx, n)
+	}
+	_, err = w.Send(ctx, *d)
+	return err
+}
+
+func (w *WebhookNotifier) Render(n models.Notification) (*models.WebhookDelivery, error) {
+	url, provider := n.Channel, n.Provider
+	if url == "" {
+		url = w.url
+	}
+	if provider == "" {
+		provider = w.provider
+	}
+	if url == "" {
+		return nil, nil
+	}
+	n.Message = i18n.Translate(n.Locale, n.Message)
+	channel, ok := channels[provider]
+	if !ok {
+		channel = webhookChannel{}
+	}
+
+	body, err := json.Marshal(channel.Payload(n, render(channel, n)))
+	if err != nil {
+		return nil, err
+	}
+	return &models.WebhookDelivery{
+		Event:         n.Event,
+		TeamName:      n.TeamName,
+		PullRequestID: n.PullRequestID,
+		URL:           url,
+		Payload:       body,
+	}, nil
+}
+
+// Send posts d to its URL. Deliveries that were not recorded, and have no
+// ID, go out without the DeliveryIDHeader.
+func (w *WebhookNotifier) Send(ctx context.Context, d models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if d.ID != 0 {
+		req.Header.Set(DeliveryIDHeader, strconv.FormatInt(d.ID, 10))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("notify webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("notify webhook: unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// render writes the text of n with the team's template, or the default one.
+// A template that fails, e.g. on data it did not expect, falls back to the
+// default so the notification still goes out.
+func render(channel Channel, n models.Notification) string {
+	view := models.NotificationView{Notification: n, Mentions: []string{}}
+	for _, userID := range n.Recipients {
+		view.Mentions = append(view.Mentions, channel.Mention(userID, n.Identities))
+	}
+
+	if n.Template != "" {
+		var text strings.Builder
+		tmpl, err := template.New("notification").Parse(n.Template)
+		if err == nil {
+			err = tmpl.Execute(&text, view)
+		}
+		if err == nil {
+			return text.String()
+		}
+		log.Printf("Notification template of team %s failed, using the default: %v", n.TeamName, err)
+	}
+
+	var text strings.Builder
+	if err := defaultTemplate.Execute(&text, view); err != nil {
+		return n.Message
+	}
+	return text.String()
+}
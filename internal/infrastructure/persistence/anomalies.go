@@ -0,0 +1,124 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) GetAssignmentCounts(ctx context.Context, since time.Time) (_ []models.AssignmentCount, err error) {
+	ctx, done := s.query(ctx, "GetAssignmentCounts", &err)
+	defer done()
+
+	rows, err := s.r.Query(ctx,
+		`SELECT u.team_name, h.user_id, COUNT(*)
+		 FROM assignment_history h
+		 JOIN pull_requests pr ON pr.pull_request_id = h.pull_request_id
+		 JOIN users u ON u.user_id = pr.author_id
+		 WHERE h.role = 'reviewer' AND h.action <> 'removed' AND h.created_at >= $1
+		 GROUP BY u.team_name, h.user_id
+		 ORDER BY u.team_name, h.user_id`,
+		since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []models.AssignmentCount{}
+	for rows.Next() {
+		var c models.AssignmentCount
+		if err := rows.Scan(&c.TeamName, &c.UserID, &c.Assignments); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// anomalyKey identifies a finding across anomaly checks.
+type anomalyKey struct {
+	kind     models.AnomalyKind
+	teamName string
+	userID   string
+}
+
+func (s *PostgresStorage) ReplaceAnomalies(ctx context.Context, anomalies []models.Anomaly, at time.Time) (_ []models.Anomaly, err error) {
+	ctx, done := s.query(ctx, "ReplaceAnomalies", &err)
+	defer done()
+
+	added := []models.Anomaly{}
+	err = s.inTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, "SELECT kind, team_name, user_id, detected_at FROM anomalies FOR UPDATE")
+		if err != nil {
+			return err
+		}
+		current := make(map[anomalyKey]time.Time)
+		for rows.Next() {
+			var key anomalyKey
+			var detectedAt time.Time
+			if err := rows.Scan(&key.kind, &key.teamName, &key.userID, &detectedAt); err != nil {
+				rows.Close()
+				return err
+			}
+			current[key] = detectedAt
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, "DELETE FROM anomalies"); err != nil {
+			return err
+		}
+		for i := range anomalies {
+			a := &anomalies[i]
+			detectedAt, ok := current[anomalyKey{a.Kind, a.TeamName, a.UserID}]
+			if !ok {
+				detectedAt = at
+			}
+			a.DetectedAt = &detectedAt
+			_, err := tx.Exec(ctx,
+				`INSERT INTO anomalies (kind, team_name, user_id, value, threshold, message, detected_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+				a.Kind, a.TeamName, a.UserID, a.Value, a.Threshold, a.Message, detectedAt)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				added = append(added, *a)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+func (s *PostgresStorage) GetAnomalies(ctx context.Context) (_ []models.Anomaly, err error) {
+	ctx, done := s.query(ctx, "GetAnomalies", &err)
+	defer done()
+
+	rows, err := s.r.Query(ctx,
+		`SELECT kind, team_name, user_id, value, threshold, message, detected_at
+		 FROM anomalies
+		 ORDER BY team_name, kind, user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	anomalies := []models.Anomaly{}
+	for rows.Next() {
+		var a models.Anomaly
+		if err := rows.Scan(&a.Kind, &a.TeamName, &a.UserID, &a.Value, &a.Threshold, &a.Message, &a.DetectedAt); err != nil {
+			return nil, err
+		}
+		anomalies = append(anomalies, a)
+	}
+	return anomalies, rows.Err()
+}
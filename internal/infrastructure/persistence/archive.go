@@ -0,0 +1,88 @@
+package persistence
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) ArchivePullRequests(ctx context.Context, mergedBefore time.Time) (_ int64, err error) {
+	ctx, done := s.query(ctx, "ArchivePullRequests", &err)
+	defer done()
+
+	res, err := s.q.Exec(ctx,
+		`UPDATE pull_requests
+		 SET archived = true, archived_at = CURRENT_TIMESTAMP
+		 WHERE status = 'MERGED' AND NOT archived AND merged_at < $1`,
+		mergedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
+
+func (s *PostgresStorage) ListPullRequests(ctx context.Context, filter models.PullRequestFilter) (_ []models.PullRequestShort, err error) {
+	ctx, done := s.query(ctx, "ListPullRequests", &err)
+	defer done()
+
+	var conds []string
+	var args []interface{}
+	if !filter.IncludeArchived {
+		conds = append(conds, "NOT archived")
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conds = append(conds, "status = $"+strconv.Itoa(len(args)))
+	}
+	if filter.Priority != "" {
+		args = append(args, filter.Priority)
+		conds = append(conds, "priority = $"+strconv.Itoa(len(args)))
+	}
+	if filter.AuthorID != "" {
+		args = append(args, filter.AuthorID)
+		conds = append(conds, "author_id = $"+strconv.Itoa(len(args)))
+	}
+	if filter.Repository != "" {
+		args = append(args, filter.Repository)
+		n := strconv.Itoa(len(args))
+		conds = append(conds, "(repository = $"+n+" OR repository LIKE $"+n+" || '/%')")
+	}
+	if filter.TeamName != "" {
+		args = append(args, filter.TeamName)
+		conds = append(conds, "author_id IN (SELECT user_id FROM users WHERE team_name = $"+strconv.Itoa(len(args))+")")
+	}
+	order := "created_at DESC, pull_request_id"
+	if filter.Query != "" {
+		// name_search is a generated column served by idx_pr_name_search.
+		args = append(args, filter.Query)
+		tsquery := "websearch_to_tsquery('simple', $" + strconv.Itoa(len(args)) + ")"
+		conds = append(conds, "name_search @@ "+tsquery)
+		order = "ts_rank(name_search, " + tsquery + ") DESC, " + order
+	}
+
+	query := `SELECT pull_request_id, pull_request_name, author_id, status, priority, is_draft, archived FROM pull_requests`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += " ORDER BY " + order + " LIMIT $" + strconv.Itoa(len(args)-1) + " OFFSET $" + strconv.Itoa(len(args))
+
+	rows, err := s.r.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prs := []models.PullRequestShort{}
+	for rows.Next() {
+		var pr models.PullRequestShort
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.Priority, &pr.IsDraft, &pr.Archived); err != nil {
+			return nil, err
+		}
+		prs = append(prs, pr)
+	}
+	return prs, rows.Err()
+}
@@ -0,0 +1,237 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// backupTables are the tables a backup holds, parents before children so
+// that restoring them in this order satisfies the foreign keys.
+var backupTables = []string{
+	"teams",
+	"repositories",
+	"repository_code_owners",
+	"team_settings",
+	"team_rotation",
+	"users",
+	"user_identities",
+	"digest_preferences",
+	"team_routing_rules",
+	"team_exclusion_rules",
+	"team_repository_rules",
+	"pull_requests",
+	"pr_paths",
+	"reviews",
+	"assignment_history",
+	"domain_events",
+	"review_issues",
+	"review_tasks",
+	"review_escalations",
+	"feature_flags",
+}
+
+// errDryRun rolls back a dry-run restore.
+var errDryRun = errors.New("dry run")
+
+// setBackupStatementTimeout lifts statement_timeout to the backup timeout
+// for the rest of tx, since copying a whole table may take longer than any
+// other statement.
+func (s *PostgresStorage) setBackupStatementTimeout(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, "SELECT set_config('statement_timeout', $1, true)",
+		strconv.FormatInt(s.backupTimeout.Milliseconds(), 10))
+	return err
+}
+
+func (s *PostgresStorage) Backup(ctx context.Context) (_ *models.Backup, err error) {
+	ctx, done := s.queryWithin(ctx, "Backup", s.backupTimeout, &err)
+	defer done()
+
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	if err := s.setBackupStatementTimeout(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	backup := &models.Backup{
+		Format: models.BackupFormat,
+		Tables: make(map[string][]json.RawMessage, len(backupTables)),
+	}
+	if err := tx.QueryRow(ctx, "SELECT CURRENT_TIMESTAMP").Scan(&backup.CreatedAt); err != nil {
+		return nil, err
+	}
+	for _, table := range backupTables {
+		var data []byte
+		err := tx.QueryRow(ctx,
+			"SELECT COALESCE(json_agg(t), '[]') FROM "+pgx.Identifier{table}.Sanitize()+" t").Scan(&data)
+		if err != nil {
+			return nil, err
+		}
+		var rows []json.RawMessage
+		if err := json.Unmarshal(data, &rows); err != nil {
+			return nil, err
+		}
+		backup.Tables[table] = rows
+	}
+	return backup, tx.Commit(ctx)
+}
+
+func (s *PostgresStorage) Restore(ctx context.Context, backup *models.Backup, dryRun bool) (_ map[string]int64, err error) {
+	ctx, done := s.queryWithin(ctx, "Restore", s.backupTimeout, &err)
+	defer done()
+
+	for table := range backup.Tables {
+		if !isBackupTable(table) {
+			return nil, fmt.Errorf("%w: unknown table %q", repository.ErrInvalidBackup, table)
+		}
+	}
+
+	restored := make(map[string]int64, len(backupTables))
+	err = s.inTx(ctx, func(tx pgx.Tx) error {
+		if err := s.setBackupStatementTimeout(ctx, tx); err != nil {
+			return err
+		}
+		quoted := make([]string, len(backupTables))
+		for i, table := range backupTables {
+			quoted[i] = pgx.Identifier{table}.Sanitize()
+		}
+		// Keep writers out until commit so the database stays empty while
+		// it is being restored.
+		if _, err := tx.Exec(ctx, "LOCK TABLE "+strings.Join(quoted, ", ")+" IN EXCLUSIVE MODE"); err != nil {
+			return err
+		}
+		for i, table := range backupTables {
+			var exists bool
+			if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM "+quoted[i]+")").Scan(&exists); err != nil {
+				return err
+			}
+			if exists {
+				return fmt.Errorf("%w: %s has rows", repository.ErrNotEmpty, table)
+			}
+		}
+
+		// Rows are copied in as JSON documents and converted to each table's
+		// row type from there, so every table loads with a single COPY
+		// whatever its columns.
+		if _, err := tx.Exec(ctx, "CREATE TEMP TABLE restore_rows (doc jsonb) ON COMMIT DROP"); err != nil {
+			return err
+		}
+		for i, table := range backupTables {
+			n, err := restoreTable(ctx, tx, table, quoted[i], backup.Tables[table])
+			if err != nil {
+				return err
+			}
+			restored[table] = n
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRun) {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// restoreTable copies rows into the restore_rows staging table, inserts them
+// into table and moves its id sequence, if any, past the restored ids.
+// Generated columns are left for the database to compute.
+func restoreTable(ctx context.Context, tx pgx.Tx, table, quoted string, rows []json.RawMessage) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	columns, err := insertableColumns(ctx, tx, table)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(ctx, "TRUNCATE restore_rows"); err != nil {
+		return 0, err
+	}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"restore_rows"}, []string{"doc"},
+		pgx.CopyFromSlice(len(rows), func(i int) ([]interface{}, error) {
+			return []interface{}{rows[i]}, nil
+		}))
+	if err != nil {
+		return 0, err
+	}
+
+	fields := make([]string, len(columns))
+	for i, column := range columns {
+		fields[i] = "r." + column
+	}
+	res, err := tx.Exec(ctx,
+		"INSERT INTO "+quoted+" ("+strings.Join(columns, ", ")+") SELECT "+strings.Join(fields, ", ")+
+			" FROM restore_rows, jsonb_populate_record(NULL::"+quoted+", restore_rows.doc) r")
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && (strings.HasPrefix(pgErr.Code, "22") || strings.HasPrefix(pgErr.Code, "23")) {
+		// Bad rows are the backup's fault; report them as such rather than
+		// as a conflict with existing data.
+		return 0, fmt.Errorf("%w: %s: %s", repository.ErrInvalidBackup, table, pgErr.Message)
+	}
+	if err != nil {
+		return 0, err
+	}
+	n := res.RowsAffected()
+
+	for _, column := range columns {
+		if column == `"id"` {
+			_, err = tx.Exec(ctx,
+				"SELECT setval(pg_get_serial_sequence($1, 'id'), (SELECT COALESCE(MAX(id), 0) + 1 FROM "+quoted+"), false)",
+				table)
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// insertableColumns lists the table's quoted column names, except generated
+// columns.
+func insertableColumns(ctx context.Context, tx pgx.Tx, table string) ([]string, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT column_name FROM information_schema.columns
+		 WHERE table_schema = current_schema() AND table_name = $1 AND is_generated = 'NEVER'
+		 ORDER BY ordinal_position`,
+		table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		columns = append(columns, pgx.Identifier{column}.Sanitize())
+	}
+	return columns, rows.Err()
+}
+
+func isBackupTable(name string) bool {
+	for _, table := range backupTables {
+		if table == name {
+			return true
+		}
+	}
+	return false
+}
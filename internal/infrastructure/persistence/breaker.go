@@ -0,0 +1,127 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+const (
+	breakerFailureThreshold = 3
+	breakerCooldown         = 5 * time.Second
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker stops dialing the database after consecutive connection
+// failures. While open, new connections fail fast with ErrUnavailable; after
+// the cooldown a single probe connection is let through to test recovery.
+type circuitBreaker struct {
+	// name identifies the database in log messages.
+	name string
+
+	mu        sync.Mutex
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	threshold int
+	cooldown  time.Duration
+}
+
+func newCircuitBreaker(name string, threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{name: name, threshold: threshold, cooldown: cooldown}
+}
+
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) >= b.cooldown {
+			b.state = breakerHalfOpen
+			return nil
+		}
+		return repository.ErrUnavailable
+	case breakerHalfOpen:
+		return repository.ErrUnavailable
+	}
+	return nil
+}
+
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != breakerClosed {
+		log.Printf("%s connection restored, circuit breaker closed", b.name)
+	}
+	b.state = breakerClosed
+	b.failures = 0
+}
+
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == breakerHalfOpen || (b.state == breakerClosed && b.failures >= b.threshold) {
+		if b.state == breakerClosed {
+			log.Printf("%s unavailable after %d failed connection attempts, circuit breaker opened", b.name, b.failures)
+		}
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// release gives up a half-open probe slot without judging the database, e.g.
+// when the caller's context was cancelled mid-dial.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
+
+func (b *circuitBreaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state != breakerClosed
+}
+
+// dial wraps next so that new pool connections go through the breaker.
+func (b *circuitBreaker) dial(next pgconn.DialFunc) pgconn.DialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if err := b.allow(); err != nil {
+			return nil, err
+		}
+
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			if ctx.Err() != nil {
+				b.release()
+				return nil, err
+			}
+			b.failure()
+			return nil, fmt.Errorf("%w: %v", repository.ErrUnavailable, err)
+		}
+
+		b.success()
+		return conn, nil
+	}
+}
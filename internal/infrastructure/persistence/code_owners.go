@@ -0,0 +1,64 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) GetCodeOwners(ctx context.Context, repository string) (_ []models.CodeOwnersRule, err error) {
+	ctx, done := s.query(ctx, "GetCodeOwners", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		`SELECT line, pattern, owners
+		 FROM repository_code_owners
+		 WHERE repository = $1
+		 ORDER BY line`,
+		repository)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.CodeOwnersRule{}
+	for rows.Next() {
+		var rule models.CodeOwnersRule
+		var ownersJSON []byte
+		if err := rows.Scan(&rule.Line, &rule.Pattern, &ownersJSON); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(ownersJSON, &rule.Owners); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (s *PostgresStorage) SetCodeOwners(ctx context.Context, repository string, rules []models.CodeOwnersRule) (err error) {
+	ctx, done := s.query(ctx, "SetCodeOwners", &err)
+	defer done()
+
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "DELETE FROM repository_code_owners WHERE repository = $1", repository); err != nil {
+			return err
+		}
+
+		batch := &pgx.Batch{}
+		for _, rule := range rules {
+			ownersJSON, err := marshalStrings(rule.Owners)
+			if err != nil {
+				return err
+			}
+			batch.Queue(
+				`INSERT INTO repository_code_owners (repository, line, pattern, owners)
+				 VALUES ($1, $2, $3, $4)`,
+				repository, rule.Line, rule.Pattern, ownersJSON)
+		}
+		return tx.SendBatch(ctx, batch).Close()
+	})
+}
@@ -0,0 +1,142 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// restorableUser matches deleted users that were not erased by the purge
+// since, so restoring them brings back their data as it was.
+const restorableUser = "u.deleted_at IS NOT NULL AND (u.erased_at IS NULL OR u.erased_at < u.deleted_at)"
+
+func (s *PostgresStorage) DeleteUser(ctx context.Context, userID string) (_ bool, err error) {
+	ctx, done := s.query(ctx, "DeleteUser", &err)
+	defer done()
+
+	res, err := s.q.Exec(ctx,
+		`UPDATE users SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		 WHERE user_id = $1 AND deleted_at IS NULL`,
+		userID)
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+// RestoreUser restores the deleted user unless their team is deleted too;
+// those come back with the team.
+func (s *PostgresStorage) RestoreUser(ctx context.Context, userID string) (_ bool, err error) {
+	ctx, done := s.query(ctx, "RestoreUser", &err)
+	defer done()
+
+	res, err := s.q.Exec(ctx,
+		`UPDATE users u SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+		 WHERE u.user_id = $1 AND `+restorableUser+`
+		   AND EXISTS (SELECT 1 FROM teams t WHERE t.team_name = u.team_name AND t.deleted_at IS NULL)`,
+		userID)
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+// DeleteTeam deletes the team and its members at the same instant, which
+// RestoreTeam relies on to tell them from members deleted before.
+func (s *PostgresStorage) DeleteTeam(ctx context.Context, teamName string) (_ bool, err error) {
+	ctx, done := s.query(ctx, "DeleteTeam", &err)
+	defer done()
+
+	var n int64
+	err = s.q.QueryRow(ctx,
+		`WITH deleted AS (
+			UPDATE teams SET deleted_at = CURRENT_TIMESTAMP
+			WHERE team_name = $1 AND deleted_at IS NULL
+			RETURNING team_name, deleted_at
+		), members AS (
+			UPDATE users u SET deleted_at = d.deleted_at, updated_at = CURRENT_TIMESTAMP
+			FROM deleted d
+			WHERE u.team_name = d.team_name AND u.deleted_at IS NULL
+			RETURNING 1
+		)
+		SELECT COUNT(*) FROM deleted`,
+		teamName).Scan(&n)
+	return n > 0, err
+}
+
+// RestoreTeam restores the deleted team along with the members deleted with
+// it. Members deleted on their own before stay deleted.
+func (s *PostgresStorage) RestoreTeam(ctx context.Context, teamName string) (_ bool, err error) {
+	ctx, done := s.query(ctx, "RestoreTeam", &err)
+	defer done()
+
+	// Both updates see the team as it was before the statement, so members
+	// are matched against the old deleted_at.
+	var n int64
+	err = s.q.QueryRow(ctx,
+		`WITH restored AS (
+			UPDATE teams SET deleted_at = NULL
+			WHERE team_name = $1 AND deleted_at IS NOT NULL
+			RETURNING team_name
+		), members AS (
+			UPDATE users u SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+			FROM teams t
+			WHERE t.team_name = $1 AND t.team_name IN (SELECT team_name FROM restored)
+			  AND u.team_name = t.team_name AND u.deleted_at = t.deleted_at
+			  AND `+restorableUser+`
+			RETURNING 1
+		)
+		SELECT COUNT(*) FROM restored`,
+		teamName).Scan(&n)
+	return n > 0, err
+}
+
+// PurgeDeletedUsers removes users deleted before deletedBefore for good when
+// nothing refers to them. Those who authored PRs, are on one or were ever
+// assigned one are erased instead, so the history stays consistent.
+func (s *PostgresStorage) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (n int64, err error) {
+	ctx, done := s.query(ctx, "PurgeDeletedUsers", &err)
+	defer done()
+
+	err = s.inTx(ctx, func(tx pgx.Tx) error {
+		res, err := tx.Exec(ctx,
+			`DELETE FROM users u
+			 WHERE u.deleted_at < $1
+			   AND NOT EXISTS (SELECT 1 FROM pull_requests pr WHERE pr.author_id = u.user_id
+			                   OR pr.assigned_reviewers ? u.user_id OR pr.shadow_reviewers ? u.user_id)
+			   AND NOT EXISTS (SELECT 1 FROM assignment_history h WHERE h.user_id = u.user_id OR h.replaced_user_id = u.user_id)
+			   AND NOT EXISTS (SELECT 1 FROM reviews v WHERE v.reviewer_id = u.user_id)`,
+			deletedBefore)
+		if err != nil {
+			return err
+		}
+
+		var erased int64
+		err = tx.QueryRow(ctx,
+			eraseUsersQuery("deleted_at < $1 AND (erased_at IS NULL OR erased_at < deleted_at)"),
+			deletedBefore).Scan(&erased)
+		n = res.RowsAffected() + erased
+		return err
+	})
+	return n, err
+}
+
+// PurgeDeletedTeams removes teams deleted before deletedBefore for good,
+// with their settings and rules. Teams still holding users, erased ones
+// included, or set as the default team of a repository are kept.
+func (s *PostgresStorage) PurgeDeletedTeams(ctx context.Context, deletedBefore time.Time) (_ int64, err error) {
+	ctx, done := s.query(ctx, "PurgeDeletedTeams", &err)
+	defer done()
+
+	res, err := s.q.Exec(ctx,
+		`DELETE FROM teams t
+		 WHERE t.deleted_at < $1
+		   AND NOT EXISTS (SELECT 1 FROM users u WHERE u.team_name = t.team_name)
+		   AND NOT EXISTS (SELECT 1 FROM repositories r WHERE r.default_team = t.team_name)`,
+		deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
@@ -0,0 +1,79 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) GetDigestPreferences(ctx context.Context, userID string) (_ *models.DigestPreferences, err error) {
+	ctx, done := s.query(ctx, "GetDigestPreferences", &err)
+	defer done()
+
+	var p models.DigestPreferences
+	err = s.q.QueryRow(ctx,
+		`SELECT user_id, enabled, send_at, channel, provider, last_sent_at
+		 FROM digest_preferences WHERE user_id = $1`,
+		userID).Scan(&p.UserID, &p.Enabled, &p.SendAt, &p.Channel, &p.Provider, &p.LastSentAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *PostgresStorage) SetDigestPreferences(ctx context.Context, prefs *models.DigestPreferences) (err error) {
+	ctx, done := s.query(ctx, "SetDigestPreferences", &err)
+	defer done()
+
+	return s.q.QueryRow(ctx,
+		`INSERT INTO digest_preferences (user_id, enabled, send_at, channel, provider)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (user_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			send_at = EXCLUDED.send_at,
+			channel = EXCLUDED.channel,
+			provider = EXCLUDED.provider,
+			updated_at = CURRENT_TIMESTAMP
+		 RETURNING last_sent_at`,
+		prefs.UserID, prefs.Enabled, prefs.SendAt, prefs.Channel, prefs.Provider).
+		Scan(&prefs.LastSentAt)
+}
+
+func (s *PostgresStorage) GetEnabledDigests(ctx context.Context) (_ []models.DigestPreferences, err error) {
+	ctx, done := s.query(ctx, "GetEnabledDigests", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		`SELECT user_id, enabled, send_at, channel, provider, last_sent_at
+		 FROM digest_preferences WHERE enabled
+		 ORDER BY user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prefs := []models.DigestPreferences{}
+	for rows.Next() {
+		var p models.DigestPreferences
+		if err := rows.Scan(&p.UserID, &p.Enabled, &p.SendAt, &p.Channel, &p.Provider, &p.LastSentAt); err != nil {
+			return nil, err
+		}
+		prefs = append(prefs, p)
+	}
+	return prefs, rows.Err()
+}
+
+func (s *PostgresStorage) MarkDigestSent(ctx context.Context, userID string, at time.Time) (err error) {
+	ctx, done := s.query(ctx, "MarkDigestSent", &err)
+	defer done()
+
+	_, err = s.q.Exec(ctx, "UPDATE digest_preferences SET last_sent_at = $2 WHERE user_id = $1", userID, at)
+	return err
+}
@@ -0,0 +1,67 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) GetDirectoryManagedUsers(ctx context.Context) (_ []models.User, err error) {
+	ctx, done := s.query(ctx, "GetDirectoryManagedUsers", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		"SELECT "+userColumns+" FROM users WHERE directory_managed AND deleted_at IS NULL ORDER BY user_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (s *PostgresStorage) GetDeletedUserIDs(ctx context.Context, userIDs []string) (_ []string, err error) {
+	ctx, done := s.query(ctx, "GetDeletedUserIDs", &err)
+	defer done()
+
+	ids := []string{}
+	if len(userIDs) == 0 {
+		return ids, nil
+	}
+
+	rows, err := s.q.Query(ctx,
+		"SELECT user_id FROM users WHERE user_id = ANY($1) AND deleted_at IS NOT NULL ORDER BY user_id",
+		userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (s *PostgresStorage) TeamDeleted(ctx context.Context, teamName string) (_ bool, err error) {
+	ctx, done := s.query(ctx, "TeamDeleted", &err)
+	defer done()
+
+	var deleted bool
+	err = s.q.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1 AND deleted_at IS NOT NULL)",
+		teamName).Scan(&deleted)
+	return deleted, err
+}
@@ -0,0 +1,120 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+const (
+	// pgQueryCanceled is reported both for statement_timeout and for queries
+	// cancelled because their context was done.
+	pgQueryCanceled = "57014"
+
+	// The server is shutting down, crashed or is still starting up.
+	pgAdminShutdown    = "57P01"
+	pgCrashShutdown    = "57P02"
+	pgCannotConnectNow = "57P03"
+
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+
+	// usernameIndex enforces case-insensitive unique usernames.
+	usernameIndex = "idx_users_username"
+)
+
+// query applies the per-query timeout to ctx and returns a function that
+// releases it, translates *err into repository errors and records the call
+// under name in the query metrics. Storage methods use it with a named error
+// result:
+//
+//	ctx, done := s.query(ctx, "GetUser", &err)
+//	defer done()
+func (s *PostgresStorage) query(ctx context.Context, name string, err *error) (context.Context, func()) {
+	return s.queryWithin(ctx, name, s.queryTimeout, err)
+}
+
+// queryWithin is query with its own timeout instead of the per-query one;
+// zero means none.
+func (s *PostgresStorage) queryWithin(ctx context.Context, name string, timeout time.Duration, err *error) (context.Context, func()) {
+	start := time.Now()
+	cancel := func() {}
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	return ctx, func() {
+		if *err != nil {
+			// A connection lost mid-query counts toward the primary's
+			// breaker like a failed dial, which the breaker counted itself.
+			if lostConnection(ctx, *err) {
+				s.breaker.failure()
+			}
+			*err = translateError(ctx, *err)
+		}
+		cancel()
+		s.metrics.observe(name, time.Since(start), *err)
+	}
+}
+
+// connectionLost reports whether err means the database could not be reached
+// or dropped the connection, as opposed to refusing the statement: a network
+// error, a connection closed mid-reply, a connection exception (SQLSTATE
+// class 08) or the server shutting down (57P01 to 57P03).
+func connectionLost(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "08") ||
+			pgErr.Code == pgAdminShutdown || pgErr.Code == pgCrashShutdown || pgErr.Code == pgCannotConnectNow
+	}
+	var netErr net.Error
+	return pgconn.SafeToRetry(err) || errors.As(err, &netErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || strings.Contains(err.Error(), "conn closed")
+}
+
+// lostConnection reports whether err is a connection lost while ctx was
+// still live, and not yet counted by a breaker.
+func lostConnection(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && !errors.Is(err, repository.ErrUnavailable) && connectionLost(err)
+}
+
+func translateError(ctx context.Context, err error) error {
+	if errors.Is(err, repository.ErrUnavailable) || errors.Is(err, repository.ErrTimeout) {
+		return err
+	}
+
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return fmt.Errorf("%w: %v", repository.ErrTimeout, err)
+	case context.Canceled:
+		return fmt.Errorf("%w: %v", context.Canceled, err)
+	}
+
+	if connectionLost(err) {
+		return fmt.Errorf("%w: %v", repository.ErrUnavailable, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgQueryCanceled:
+			return fmt.Errorf("%w: %v", repository.ErrTimeout, err)
+		case pgUniqueViolation:
+			if pgErr.ConstraintName == usernameIndex {
+				return fmt.Errorf("%w: %v", repository.ErrUsernameTaken, err)
+			}
+			return fmt.Errorf("%w: %v", repository.ErrAlreadyExists, err)
+		case pgForeignKeyViolation:
+			return fmt.Errorf("%w: %v", repository.ErrForeignKeyMissing, err)
+		}
+	}
+	return err
+}
@@ -0,0 +1,102 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// GetIdleReviews dates every assignment from the reviewer's latest
+// assignment to the PR, or the PR's creation for reviewers assigned before
+// assignment history was kept.
+func (s *PostgresStorage) GetIdleReviews(ctx context.Context) (_ []models.IdleReview, err error) {
+	ctx, done := s.query(ctx, "GetIdleReviews", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		`WITH assigned AS (
+			SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, u.team_name, r.reviewer_id,
+				COALESCE((
+					SELECT MAX(h.created_at) FROM assignment_history h
+					WHERE h.pull_request_id = pr.pull_request_id AND h.user_id = r.reviewer_id
+					  AND h.role = 'reviewer' AND h.action <> 'removed'
+				), pr.created_at) AS assigned_at
+			FROM pull_requests pr
+			JOIN users u ON u.user_id = pr.author_id
+			JOIN team_settings ts ON ts.team_name = u.team_name
+			CROSS JOIN jsonb_array_elements_text(pr.assigned_reviewers) AS r(reviewer_id)
+			WHERE pr.status = 'OPEN' AND NOT pr.archived AND NOT pr.is_draft
+			  AND (ts.remind_after_hours > 0 OR ts.escalate_after_hours > 0 OR ts.reassign_after_hours > 0)
+		)
+		SELECT a.pull_request_id, a.pull_request_name, a.author_id, a.team_name, a.reviewer_id, a.assigned_at,
+			COALESCE((
+				SELECT array_agg(e.action ORDER BY e.id) FROM review_escalations e
+				WHERE e.pull_request_id = a.pull_request_id AND e.user_id = a.reviewer_id
+				  AND e.assigned_at = a.assigned_at
+			), '{}')
+		FROM assigned a
+		WHERE NOT EXISTS (
+			SELECT 1 FROM reviews rv
+			WHERE rv.pull_request_id = a.pull_request_id AND rv.reviewer_id = a.reviewer_id
+			  AND rv.created_at >= a.assigned_at
+		)
+		ORDER BY a.assigned_at, a.pull_request_id, a.reviewer_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	idle := []models.IdleReview{}
+	for rows.Next() {
+		var r models.IdleReview
+		var taken []string
+		if err := rows.Scan(&r.PullRequestID, &r.PullRequestName, &r.AuthorID, &r.TeamName,
+			&r.UserID, &r.AssignedAt, &taken); err != nil {
+			return nil, err
+		}
+		for _, action := range taken {
+			r.Taken = append(r.Taken, models.EscalationAction(action))
+		}
+		idle = append(idle, r)
+	}
+	return idle, rows.Err()
+}
+
+func (s *PostgresStorage) AddEscalation(ctx context.Context, e *models.Escalation) (err error) {
+	ctx, done := s.query(ctx, "AddEscalation", &err)
+	defer done()
+
+	return s.q.QueryRow(ctx,
+		`INSERT INTO review_escalations (pull_request_id, user_id, team_name, action, target_id, assigned_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id, created_at`,
+		e.PullRequestID, e.UserID, e.TeamName, e.Action, e.TargetID, e.AssignedAt).
+		Scan(&e.ID, &e.CreatedAt)
+}
+
+func (s *PostgresStorage) ListEscalations(ctx context.Context, teamName string, limit, offset int) (_ []models.Escalation, err error) {
+	ctx, done := s.query(ctx, "ListEscalations", &err)
+	defer done()
+
+	rows, err := s.r.Query(ctx,
+		`SELECT id, pull_request_id, user_id, team_name, action, target_id, assigned_at, created_at
+		 FROM review_escalations WHERE team_name = $1
+		 ORDER BY created_at DESC, id DESC
+		 LIMIT $2 OFFSET $3`,
+		teamName, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	escalations := []models.Escalation{}
+	for rows.Next() {
+		var e models.Escalation
+		if err := rows.Scan(&e.ID, &e.PullRequestID, &e.UserID, &e.TeamName, &e.Action,
+			&e.TargetID, &e.AssignedAt, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		escalations = append(escalations, e)
+	}
+	return escalations, rows.Err()
+}
@@ -0,0 +1,117 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) AppendEvent(ctx context.Context, event models.Event) (err error) {
+	ctx, done := s.query(ctx, "AppendEvent", &err)
+	defer done()
+
+	if event.PullRequest != nil {
+		// Reviews are left out: erasing a user clears their comments,
+		// which the log would otherwise keep.
+		pr := *event.PullRequest
+		pr.Reviews = nil
+		event.PullRequest = &pr
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = s.q.Exec(ctx,
+		`INSERT INTO domain_events (type, pull_request_id, payload, created_at)
+		 VALUES ($1, $2, $3, $4)`,
+		event.Type, event.PullRequestID, payload, event.CreatedAt)
+	return err
+}
+
+func (s *PostgresStorage) GetEventPullRequests(ctx context.Context, afterID string, limit int) (_ []string, err error) {
+	ctx, done := s.query(ctx, "GetEventPullRequests", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		`SELECT DISTINCT pull_request_id FROM domain_events
+		 WHERE pull_request_id > $1
+		 ORDER BY pull_request_id
+		 LIMIT $2`,
+		afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prIDs := []string{}
+	for rows.Next() {
+		var prID string
+		if err := rows.Scan(&prID); err != nil {
+			return nil, err
+		}
+		prIDs = append(prIDs, prID)
+	}
+	return prIDs, rows.Err()
+}
+
+func (s *PostgresStorage) CountEventPullRequests(ctx context.Context) (_ int64, err error) {
+	ctx, done := s.query(ctx, "CountEventPullRequests", &err)
+	defer done()
+
+	var n int64
+	err = s.q.QueryRow(ctx, "SELECT COUNT(DISTINCT pull_request_id) FROM domain_events").Scan(&n)
+	return n, err
+}
+
+func (s *PostgresStorage) GetPullRequestEvents(ctx context.Context, prID string) (_ []models.Event, err error) {
+	ctx, done := s.query(ctx, "GetPullRequestEvents", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		"SELECT id, payload FROM domain_events WHERE pull_request_id = $1 ORDER BY id",
+		prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.Event{}
+	for rows.Next() {
+		var id int64
+		var payload []byte
+		if err := rows.Scan(&id, &payload); err != nil {
+			return nil, err
+		}
+		var event models.Event
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, err
+		}
+		event.ID = uint64(id)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// ReplaceAssignmentHistory swaps the PR's history for events in one
+// transaction.
+func (s *PostgresStorage) ReplaceAssignmentHistory(ctx context.Context, prID string, events []models.AssignmentEvent) (err error) {
+	ctx, done := s.query(ctx, "ReplaceAssignmentHistory", &err)
+	defer done()
+
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "DELETE FROM assignment_history WHERE pull_request_id = $1", prID); err != nil {
+			return err
+		}
+		batch := &pgx.Batch{}
+		for _, e := range events {
+			batch.Queue(
+				`INSERT INTO assignment_history (pull_request_id, user_id, role, action, reason, replaced_user_id, created_at)
+				 VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)`,
+				prID, e.UserID, e.Role, e.Action, e.Reason, e.ReplacedUserID, e.CreatedAt)
+		}
+		return tx.SendBatch(ctx, batch).Close()
+	})
+}
@@ -0,0 +1,56 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) CreateExclusionRule(ctx context.Context, rule *models.ExclusionRule) (err error) {
+	ctx, done := s.query(ctx, "CreateExclusionRule", &err)
+	defer done()
+
+	return s.q.QueryRow(ctx,
+		`INSERT INTO team_exclusion_rules (team_name, reviewer_id, author_id)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (team_name, reviewer_id, author_id) DO UPDATE SET reviewer_id = EXCLUDED.reviewer_id
+		 RETURNING id, created_at`,
+		rule.TeamName, rule.ReviewerID, rule.AuthorID).Scan(&rule.ID, &rule.CreatedAt)
+}
+
+func (s *PostgresStorage) GetExclusionRules(ctx context.Context, teamName string) (_ []models.ExclusionRule, err error) {
+	ctx, done := s.query(ctx, "GetExclusionRules", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		`SELECT id, team_name, reviewer_id, author_id, created_at
+		 FROM team_exclusion_rules
+		 WHERE team_name = $1
+		 ORDER BY reviewer_id, author_id`,
+		teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.ExclusionRule{}
+	for rows.Next() {
+		var rule models.ExclusionRule
+		if err := rows.Scan(&rule.ID, &rule.TeamName, &rule.ReviewerID, &rule.AuthorID, &rule.CreatedAt); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (s *PostgresStorage) DeleteExclusionRule(ctx context.Context, id int64) (_ bool, err error) {
+	ctx, done := s.query(ctx, "DeleteExclusionRule", &err)
+	defer done()
+
+	res, err := s.q.Exec(ctx, "DELETE FROM team_exclusion_rules WHERE id = $1", id)
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
@@ -0,0 +1,58 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) GetFeatureFlags(ctx context.Context) (_ []models.FeatureFlag, err error) {
+	ctx, done := s.query(ctx, "GetFeatureFlags", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		`SELECT name, enabled, teams, percentage, updated_at
+		 FROM feature_flags
+		 ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	flags := []models.FeatureFlag{}
+	for rows.Next() {
+		var flag models.FeatureFlag
+		if err := rows.Scan(&flag.Name, &flag.Enabled, &flag.Teams, &flag.Percentage, &flag.UpdatedAt); err != nil {
+			return nil, err
+		}
+		flags = append(flags, flag)
+	}
+	return flags, rows.Err()
+}
+
+func (s *PostgresStorage) UpsertFeatureFlag(ctx context.Context, flag *models.FeatureFlag) (err error) {
+	ctx, done := s.query(ctx, "UpsertFeatureFlag", &err)
+	defer done()
+
+	return s.q.QueryRow(ctx,
+		`INSERT INTO feature_flags (name, enabled, teams, percentage)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (name) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			teams = EXCLUDED.teams,
+			percentage = EXCLUDED.percentage,
+			updated_at = CURRENT_TIMESTAMP
+		 RETURNING updated_at`,
+		flag.Name, flag.Enabled, flag.Teams, flag.Percentage).Scan(&flag.UpdatedAt)
+}
+
+func (s *PostgresStorage) DeleteFeatureFlag(ctx context.Context, name string) (_ bool, err error) {
+	ctx, done := s.query(ctx, "DeleteFeatureFlag", &err)
+	defer done()
+
+	res, err := s.q.Exec(ctx, "DELETE FROM feature_flags WHERE name = $1", name)
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
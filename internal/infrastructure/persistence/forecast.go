@@ -0,0 +1,88 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) GetTeamActivity(ctx context.Context, now time.Time, weeks int) (_ []models.TeamActivity, err error) {
+	ctx, done := s.query(ctx, "GetTeamActivity", &err)
+	defer done()
+
+	rows, err := s.r.Query(ctx,
+		`WITH opened AS (
+			SELECT u.team_name,
+				FLOOR(EXTRACT(EPOCH FROM ($1::timestamp - pr.created_at)) / 604800)::int AS weeks_ago,
+				COUNT(*) AS prs
+			FROM pull_requests pr
+			JOIN users u ON u.user_id = pr.author_id
+			WHERE pr.created_at >= $1::timestamp - $2::int * INTERVAL '7 days' AND pr.created_at < $1
+			GROUP BY 1, 2
+		), merged AS (
+			SELECT u.team_name,
+				FLOOR(EXTRACT(EPOCH FROM ($1::timestamp - pr.merged_at)) / 604800)::int AS weeks_ago,
+				COUNT(*) AS prs
+			FROM pull_requests pr
+			JOIN users u ON u.user_id = pr.author_id
+			WHERE pr.merged_at >= $1::timestamp - $2::int * INTERVAL '7 days' AND pr.merged_at < $1
+			GROUP BY 1, 2
+		), pool AS (
+			SELECT u.team_name, COUNT(*) AS eligible, COALESCE(SUM(o.open_reviews), 0) AS open_reviews
+			FROM users u
+			CROSS JOIN LATERAL (
+				SELECT COUNT(*) AS open_reviews FROM pull_requests pr
+				WHERE pr.status = 'OPEN' AND pr.assigned_reviewers ? u.user_id
+			) o
+			WHERE u.is_active AND NOT u.is_mentee AND u.deleted_at IS NULL
+			GROUP BY u.team_name
+		)
+		SELECT t.team_name, COALESCE(p.eligible, 0), COALESCE(p.open_reviews, 0),
+			COALESCE((SELECT jsonb_object_agg(w.weeks_ago, w.prs) FROM opened w WHERE w.team_name = t.team_name), '{}'),
+			COALESCE((SELECT jsonb_object_agg(w.weeks_ago, w.prs) FROM merged w WHERE w.team_name = t.team_name), '{}')
+		FROM teams t
+		LEFT JOIN pool p ON p.team_name = t.team_name
+		WHERE t.deleted_at IS NULL
+		ORDER BY t.team_name`,
+		now, weeks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activity := []models.TeamActivity{}
+	for rows.Next() {
+		var a models.TeamActivity
+		var openedJSON, mergedJSON []byte
+		if err := rows.Scan(&a.TeamName, &a.EligibleReviewers, &a.OpenReviews, &openedJSON, &mergedJSON); err != nil {
+			return nil, err
+		}
+		if a.WeeklyPullRequests, err = weeklyCounts(openedJSON, weeks); err != nil {
+			return nil, err
+		}
+		if a.WeeklyMerges, err = weeklyCounts(mergedJSON, weeks); err != nil {
+			return nil, err
+		}
+		activity = append(activity, a)
+	}
+	return activity, rows.Err()
+}
+
+// weeklyCounts turns a JSON object of counts keyed by weeks ago into counts
+// for each of the weeks, oldest first.
+func weeklyCounts(data []byte, weeks int) ([]int, error) {
+	var byWeeksAgo map[string]int
+	if err := json.Unmarshal(data, &byWeeksAgo); err != nil {
+		return nil, err
+	}
+	counts := make([]int, weeks)
+	for key, n := range byWeeksAgo {
+		if ago, err := strconv.Atoi(key); err == nil && ago >= 0 && ago < weeks {
+			counts[weeks-1-ago] = n
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,129 @@
+package persistence
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) AddAssignmentEvents(ctx context.Context, events []models.AssignmentEvent) (err error) {
+	ctx, done := s.query(ctx, "AddAssignmentEvents", &err)
+	defer done()
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		for i := range events {
+			e := &events[i]
+			err := tx.QueryRow(ctx,
+				`INSERT INTO assignment_history (pull_request_id, user_id, role, action, reason, replaced_user_id, created_at)
+				 VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), COALESCE($7::timestamp, CURRENT_TIMESTAMP))
+				 RETURNING id, created_at`,
+				e.PullRequestID, e.UserID, e.Role, e.Action, e.Reason, e.ReplacedUserID, e.CreatedAt).Scan(&e.ID, &e.CreatedAt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *PostgresStorage) GetAssignmentHistory(ctx context.Context, prID string) (_ []models.AssignmentEvent, err error) {
+	ctx, done := s.query(ctx, "GetAssignmentHistory", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		`SELECT id, pull_request_id, user_id, role, action, reason, COALESCE(replaced_user_id, ''), created_at
+		 FROM assignment_history
+		 WHERE pull_request_id = $1
+		 ORDER BY created_at, id`,
+		prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.AssignmentEvent{}
+	for rows.Next() {
+		var e models.AssignmentEvent
+		if err := rows.Scan(&e.ID, &e.PullRequestID, &e.UserID, &e.Role, &e.Action, &e.Reason, &e.ReplacedUserID, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// GetUserHistory lists the user's assignments with the PR's current state,
+// the user's latest review of it and, if the user was later reassigned away
+// or removed, the first reassignment or removal that took them off.
+func (s *PostgresStorage) GetUserHistory(ctx context.Context, filter models.ReviewHistoryFilter) (_ []models.ReviewHistoryEntry, err error) {
+	ctx, done := s.query(ctx, "GetUserHistory", &err)
+	defer done()
+
+	args := []interface{}{filter.UserID}
+	conds := []string{"h.user_id = $1", "h.action <> 'removed'"}
+	if !filter.Since.IsZero() {
+		args = append(args, filter.Since)
+		conds = append(conds, "h.created_at >= $"+strconv.Itoa(len(args)))
+	}
+	if !filter.Until.IsZero() {
+		args = append(args, filter.Until)
+		conds = append(conds, "h.created_at < $"+strconv.Itoa(len(args)))
+	}
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := s.r.Query(ctx,
+		`SELECT h.pull_request_id, pr.pull_request_name, pr.author_id, h.role, h.reason, h.created_at,
+			pr.status, pr.merged_at, COALESCE(rep.user_id, ''), COALESCE(rep.action, ''), rep.created_at, COALESCE(rv.state, '')
+		 FROM assignment_history h
+		 JOIN pull_requests pr ON pr.pull_request_id = h.pull_request_id
+		 LEFT JOIN LATERAL (
+			SELECT r.user_id, r.action, r.created_at FROM assignment_history r
+			WHERE r.pull_request_id = h.pull_request_id
+			  AND (r.replaced_user_id = h.user_id OR r.action = 'removed' AND r.user_id = h.user_id)
+			  AND (r.created_at, r.id) > (h.created_at, h.id)
+			ORDER BY r.created_at, r.id
+			LIMIT 1
+		 ) rep ON true
+		 LEFT JOIN LATERAL (
+			SELECT v.state FROM reviews v
+			WHERE v.pull_request_id = h.pull_request_id AND v.reviewer_id = h.user_id
+			ORDER BY v.created_at DESC, v.id DESC
+			LIMIT 1
+		 ) rv ON true
+		 WHERE `+strings.Join(conds, " AND ")+`
+		 ORDER BY h.created_at DESC, h.id DESC
+		 LIMIT $`+strconv.Itoa(len(args)-1)+` OFFSET $`+strconv.Itoa(len(args)),
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.ReviewHistoryEntry{}
+	for rows.Next() {
+		var e models.ReviewHistoryEntry
+		var endedBy string
+		var endAction models.AssignmentAction
+		var endedAt *time.Time
+		if err := rows.Scan(&e.PullRequestID, &e.PullRequestName, &e.AuthorID, &e.Role, &e.Reason, &e.AssignedAt,
+			&e.Status, &e.MergedAt, &endedBy, &endAction, &endedAt, &e.LastReview); err != nil {
+			return nil, err
+		}
+		if endAction == models.ActionRemoved {
+			e.RemovedAt = endedAt
+		} else if endedBy != "" {
+			e.ReplacedBy, e.ReassignedAt = endedBy, endedAt
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
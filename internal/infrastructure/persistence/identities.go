@@ -0,0 +1,83 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) SetUserIdentity(ctx context.Context, identity *models.UserIdentity) (err error) {
+	ctx, done := s.query(ctx, "SetUserIdentity", &err)
+	defer done()
+
+	return s.q.QueryRow(ctx,
+		`INSERT INTO user_identities (user_id, provider, external_id)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (user_id, provider) DO UPDATE
+		 SET external_id = EXCLUDED.external_id, updated_at = CURRENT_TIMESTAMP
+		 RETURNING updated_at`,
+		identity.UserID, identity.Provider, identity.ExternalID).
+		Scan(&identity.UpdatedAt)
+}
+
+func (s *PostgresStorage) GetUserIdentities(ctx context.Context, userIDs []string) (_ []models.UserIdentity, err error) {
+	ctx, done := s.query(ctx, "GetUserIdentities", &err)
+	defer done()
+
+	identities := []models.UserIdentity{}
+	if len(userIDs) == 0 {
+		return identities, nil
+	}
+
+	rows, err := s.q.Query(ctx,
+		`SELECT user_id, provider, external_id, updated_at
+		 FROM user_identities
+		 WHERE user_id = ANY($1)
+		 ORDER BY user_id, provider`,
+		userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var identity models.UserIdentity
+		if err := rows.Scan(&identity.UserID, &identity.Provider, &identity.ExternalID, &identity.UpdatedAt); err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	return identities, rows.Err()
+}
+
+func (s *PostgresStorage) DeleteUserIdentity(ctx context.Context, userID string, provider models.IdentityProvider) (_ bool, err error) {
+	ctx, done := s.query(ctx, "DeleteUserIdentity", &err)
+	defer done()
+
+	res, err := s.q.Exec(ctx, "DELETE FROM user_identities WHERE user_id = $1 AND provider = $2", userID, provider)
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (s *PostgresStorage) FindUserByIdentity(ctx context.Context, provider models.IdentityProvider, externalID string) (_ *models.User, err error) {
+	ctx, done := s.query(ctx, "FindUserByIdentity", &err)
+	defer done()
+
+	user, err := scanUser(s.q.QueryRow(ctx,
+		"SELECT "+userColumns+` FROM users
+		 WHERE user_id = (SELECT user_id FROM user_identities WHERE provider = $1 AND external_id = $2)
+		   AND deleted_at IS NULL`,
+		provider, externalID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
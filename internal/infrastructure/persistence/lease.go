@@ -0,0 +1,42 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AcquireLease takes the named lease for holder, or renews it when holder
+// already has it, until ttl from now by the database clock. It reports false
+// while another holder's lease has not expired.
+func (s *PostgresStorage) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (_ bool, err error) {
+	ctx, done := s.query(ctx, "AcquireLease", &err)
+	defer done()
+
+	err = s.q.QueryRow(ctx,
+		`INSERT INTO scheduler_leases (name, holder, expires_at)
+		 VALUES ($1, $2, CURRENT_TIMESTAMP + make_interval(secs => $3))
+		 ON CONFLICT (name) DO UPDATE SET holder = EXCLUDED.holder, expires_at = EXCLUDED.expires_at
+		 WHERE scheduler_leases.holder = EXCLUDED.holder OR scheduler_leases.expires_at < CURRENT_TIMESTAMP
+		 RETURNING name`,
+		name, holder, ttl.Seconds()).Scan(&name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseLease gives up holder's lease so another instance can take it
+// without waiting for it to expire.
+func (s *PostgresStorage) ReleaseLease(ctx context.Context, name, holder string) (err error) {
+	ctx, done := s.query(ctx, "ReleaseLease", &err)
+	defer done()
+
+	_, err = s.q.Exec(ctx, "DELETE FROM scheduler_leases WHERE name = $1 AND holder = $2", name, holder)
+	return err
+}
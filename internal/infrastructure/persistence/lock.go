@@ -0,0 +1,52 @@
+package persistence
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// teamLockNamespace is the first key of the two-key advisory lock form, so
+// team locks never collide with advisory locks taken for other purposes.
+const teamLockNamespace = 1
+
+// WithTeamLock runs fn while holding a transaction-scoped advisory lock for
+// the team. fn receives a Storage bound to the same transaction: its queries
+// run on the locked connection, so waiting replicas never starve the lock
+// holder of pool connections, and everything fn writes is committed before
+// the lock is released.
+func (s *PostgresStorage) WithTeamLock(ctx context.Context, teamName string, fn func(repo repository.Storage) error) (err error) {
+	defer func() {
+		if err != nil {
+			err = translateError(ctx, err)
+		}
+	}()
+
+	if s.tx != nil {
+		return fn(s)
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1, hashtext($2))", teamLockNamespace, teamName); err != nil {
+		return err
+	}
+
+	if err := fn(s.withTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *PostgresStorage) withTx(tx pgx.Tx) *PostgresStorage {
+	bound := *s
+	bound.q = tx
+	bound.r = tx
+	bound.tx = tx
+	return &bound
+}
@@ -0,0 +1,113 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// likeEscaper escapes the LIKE wildcards of a search query, so that it only
+// matches itself.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (s *PostgresStorage) GetUserByUsername(ctx context.Context, username string) (_ *models.User, err error) {
+	ctx, done := s.query(ctx, "GetUserByUsername", &err)
+	defer done()
+
+	user, err := scanUser(s.q.QueryRow(ctx,
+		"SELECT "+userColumns+` FROM users
+		 WHERE LOWER(username) = LOWER($1) AND erased_at IS NULL AND deleted_at IS NULL`,
+		username))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *PostgresStorage) SearchUsers(ctx context.Context, query string, limit, offset int) (_ []models.User, err error) {
+	ctx, done := s.query(ctx, "SearchUsers", &err)
+	defer done()
+
+	// % is pg_trgm's similarity operator, served by idx_users_username_trgm
+	// like the prefix match.
+	query = strings.ToLower(query)
+	rows, err := s.r.Query(ctx,
+		"SELECT "+userColumns+` FROM users
+		 WHERE erased_at IS NULL AND deleted_at IS NULL
+		   AND (LOWER(username) LIKE $1 OR LOWER(user_id) LIKE $1 OR LOWER(username) % $2)
+		 ORDER BY (LOWER(username) LIKE $1 OR LOWER(user_id) LIKE $1) DESC,
+		          similarity(LOWER(username), $2) DESC, user_id
+		 LIMIT $3 OFFSET $4`,
+		likeEscaper.Replace(query)+"%", query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (s *PostgresStorage) ListUsers(ctx context.Context, filter models.UserFilter) (_ []models.UserSummary, err error) {
+	ctx, done := s.query(ctx, "ListUsers", &err)
+	defer done()
+
+	conds := []string{"deleted_at IS NULL"}
+	var args []interface{}
+	if filter.TeamName != "" {
+		args = append(args, filter.TeamName)
+		conds = append(conds, "team_name = $"+strconv.Itoa(len(args)))
+	}
+	if filter.IsActive != nil {
+		args = append(args, *filter.IsActive)
+		conds = append(conds, "is_active = $"+strconv.Itoa(len(args)))
+	}
+	switch filter.Role {
+	case models.RoleShadow:
+		conds = append(conds, "is_mentee")
+	case models.RoleReviewer:
+		conds = append(conds, "NOT is_mentee")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+
+	// Archived PRs are left out of the open reviews, as from the workload.
+	rows, err := s.r.Query(ctx,
+		"SELECT "+userColumns+`,
+			(SELECT COUNT(*) FROM pull_requests pr
+			 WHERE pr.status = 'OPEN' AND NOT pr.archived AND pr.assigned_reviewers ? users.user_id)
+		 FROM users
+		 WHERE `+strings.Join(conds, " AND ")+`
+		 ORDER BY team_name, user_id
+		 LIMIT $`+strconv.Itoa(len(args)-1)+" OFFSET $"+strconv.Itoa(len(args)),
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.UserSummary{}
+	for rows.Next() {
+		var u models.UserSummary
+		if err := rows.Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive, &u.IsMentee, &u.ReviewWeight, &u.Timezone,
+			&u.WorkingHours, &u.Seniority, &u.ManagerID, &u.DirectoryManaged, &u.OpenReviews); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
@@ -0,0 +1,42 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) GetMaintenance(ctx context.Context) (_ *models.Maintenance, err error) {
+	ctx, done := s.query(ctx, "GetMaintenance", &err)
+	defer done()
+
+	var m models.Maintenance
+	err = s.q.QueryRow(ctx,
+		`SELECT enabled, message, updated_at FROM maintenance`).
+		Scan(&m.Enabled, &m.Message, &m.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (s *PostgresStorage) SetMaintenance(ctx context.Context, m *models.Maintenance) (err error) {
+	ctx, done := s.query(ctx, "SetMaintenance", &err)
+	defer done()
+
+	return s.q.QueryRow(ctx,
+		`INSERT INTO maintenance (enabled, message)
+		 VALUES ($1, $2)
+		 ON CONFLICT (id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			message = EXCLUDED.message,
+			updated_at = CURRENT_TIMESTAMP
+		 RETURNING updated_at`,
+		m.Enabled, m.Message).Scan(&m.UpdatedAt)
+}
@@ -0,0 +1,86 @@
+package persistence
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// QueryStats describes the calls of one storage method so far.
+type QueryStats struct {
+	Name          string `json:"name"`
+	Calls         int64  `json:"calls"`
+	Errors        int64  `json:"errors"`
+	Slow          int64  `json:"slow"`
+	TotalDuration string `json:"total_duration"`
+	AvgDuration   string `json:"avg_duration"`
+	MaxDuration   string `json:"max_duration"`
+}
+
+type queryTotals struct {
+	calls, errors, slow int64
+	total, max          time.Duration
+}
+
+// queryMetrics aggregates call durations per storage method and logs calls
+// slower than slowThreshold. A zero threshold disables the log.
+type queryMetrics struct {
+	slowThreshold time.Duration
+
+	mu     sync.Mutex
+	byName map[string]*queryTotals
+}
+
+func newQueryMetrics(slowThreshold time.Duration) *queryMetrics {
+	return &queryMetrics{slowThreshold: slowThreshold, byName: make(map[string]*queryTotals)}
+}
+
+func (m *queryMetrics) observe(name string, d time.Duration, err error) {
+	slow := m.slowThreshold > 0 && d >= m.slowThreshold
+	if slow {
+		log.Printf("Slow query %s took %s (threshold %s, error: %v)", name, d.Round(time.Millisecond), m.slowThreshold, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.byName[name]
+	if !ok {
+		t = &queryTotals{}
+		m.byName[name] = t
+	}
+	t.calls++
+	t.total += d
+	if d > t.max {
+		t.max = d
+	}
+	if err != nil {
+		t.errors++
+	}
+	if slow {
+		t.slow++
+	}
+}
+
+// QueryStats reports the calls of every storage method that ran so far,
+// ordered by name.
+func (s *PostgresStorage) QueryStats() []QueryStats {
+	m := s.metrics
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]QueryStats, 0, len(m.byName))
+	for name, t := range m.byName {
+		stats = append(stats, QueryStats{
+			Name:          name,
+			Calls:         t.calls,
+			Errors:        t.errors,
+			Slow:          t.slow,
+			TotalDuration: t.total.String(),
+			AvgDuration:   (t.total / time.Duration(t.calls)).String(),
+			MaxDuration:   t.max.String(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
@@ -0,0 +1,151 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) AddOutboxMessages(ctx context.Context, messages []models.OutboxMessage) (err error) {
+	ctx, done := s.query(ctx, "AddOutboxMessages", &err)
+	defer done()
+
+	if len(messages) == 0 {
+		return nil
+	}
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for _, m := range messages {
+			batch.Queue(
+				`INSERT INTO outbox (kind, pull_request_id, user_id, next_attempt_at)
+				 VALUES ($1, $2, $3, $4)`,
+				m.Kind, m.PullRequestID, m.UserID, m.NextAttemptAt)
+		}
+		return tx.SendBatch(ctx, batch).Close()
+	})
+}
+
+func (s *PostgresStorage) ClaimOutboxMessages(ctx context.Context, now, leaseUntil time.Time, limit int) (_ []models.OutboxMessage, err error) {
+	ctx, done := s.query(ctx, "ClaimOutboxMessages", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		`UPDATE outbox SET next_attempt_at = $2
+		 WHERE id IN (
+		     SELECT o.id FROM outbox o
+		     WHERE o.next_attempt_at <= $1
+		       AND NOT EXISTS (
+		           SELECT 1 FROM outbox p
+		           WHERE p.pull_request_id = o.pull_request_id AND p.id < o.id
+		             AND p.next_attempt_at IS NOT NULL)
+		     ORDER BY o.id
+		     LIMIT $3
+		     FOR UPDATE SKIP LOCKED)
+		 RETURNING id, kind, pull_request_id, user_id, attempts, last_error, next_attempt_at, created_at`,
+		now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []models.OutboxMessage{}
+	for rows.Next() {
+		var m models.OutboxMessage
+		err := rows.Scan(&m.ID, &m.Kind, &m.PullRequestID, &m.UserID, &m.Attempts, &m.LastError, &m.NextAttemptAt, &m.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].ID < messages[j].ID
+	})
+	return messages, nil
+}
+
+func (s *PostgresStorage) DeleteOutboxMessage(ctx context.Context, id int64) (err error) {
+	ctx, done := s.query(ctx, "DeleteOutboxMessage", &err)
+	defer done()
+
+	_, err = s.q.Exec(ctx, "DELETE FROM outbox WHERE id = $1", id)
+	return err
+}
+
+func (s *PostgresStorage) FailOutboxMessage(ctx context.Context, id int64, lastError string, nextAttemptAt *time.Time) (err error) {
+	ctx, done := s.query(ctx, "FailOutboxMessage", &err)
+	defer done()
+
+	_, err = s.q.Exec(ctx,
+		`UPDATE outbox
+		 SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3
+		 WHERE id = $1`,
+		id, lastError, nextAttemptAt)
+	return err
+}
+
+func (s *PostgresStorage) GetReviewIssue(ctx context.Context, prID string) (_ string, err error) {
+	ctx, done := s.query(ctx, "GetReviewIssue", &err)
+	defer done()
+
+	var key string
+	err = s.q.QueryRow(ctx, "SELECT issue_key FROM review_issues WHERE pull_request_id = $1", prID).Scan(&key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return key, err
+}
+
+func (s *PostgresStorage) SetReviewIssue(ctx context.Context, prID, issueKey string) (err error) {
+	ctx, done := s.query(ctx, "SetReviewIssue", &err)
+	defer done()
+
+	_, err = s.q.Exec(ctx,
+		`INSERT INTO review_issues (pull_request_id, issue_key) VALUES ($1, $2)
+		 ON CONFLICT (pull_request_id) DO UPDATE SET issue_key = EXCLUDED.issue_key`,
+		prID, issueKey)
+	return err
+}
+
+func (s *PostgresStorage) GetReviewTasks(ctx context.Context, prID string) (_ []models.ReviewTask, err error) {
+	ctx, done := s.query(ctx, "GetReviewTasks", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		`SELECT pull_request_id, user_id, issue_key FROM review_tasks
+		 WHERE pull_request_id = $1
+		 ORDER BY user_id`,
+		prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []models.ReviewTask{}
+	for rows.Next() {
+		var t models.ReviewTask
+		if err := rows.Scan(&t.PullRequestID, &t.UserID, &t.IssueKey); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
+}
+
+func (s *PostgresStorage) AddReviewTask(ctx context.Context, task models.ReviewTask) (err error) {
+	ctx, done := s.query(ctx, "AddReviewTask", &err)
+	defer done()
+
+	_, err = s.q.Exec(ctx,
+		`INSERT INTO review_tasks (pull_request_id, user_id, issue_key) VALUES ($1, $2, $3)
+		 ON CONFLICT (pull_request_id, user_id) DO UPDATE SET issue_key = EXCLUDED.issue_key`,
+		task.PullRequestID, task.UserID, task.IssueKey)
+	return err
+}
@@ -2,71 +2,243 @@ package persistence
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"strconv"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
 	"github.com/Thorlik/avito_internship/internal/domain/models"
-	_ "github.com/lib/pq"
 )
 
+// querier is satisfied by both *pgxpool.Pool and pgx.Tx, so the same storage
+// code runs on the pool or inside a transaction opened by WithTeamLock.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
 type PostgresStorage struct {
-	db *sql.DB
+	pool    *pgxpool.Pool
+	q       querier
+	tx      pgx.Tx
+	breaker *circuitBreaker
+	// r runs reads that tolerate replication lag: on the read replica when
+	// one is configured, otherwise like q.
+	r              querier
+	replica        *pgxpool.Pool
+	replicaBreaker *circuitBreaker
+
+	queryTimeout  time.Duration
+	backupTimeout time.Duration
+	metrics       *queryMetrics
+	done          chan struct{}
+}
+
+type Options struct {
+	MaxOpenConns int
+	// MinConns connections are kept open even while the pool is idle.
+	MinConns        int
+	ConnMaxLifetime time.Duration
+	// StatementTimeout is set as statement_timeout on every connection of
+	// both pools, so Postgres aborts statements that outlive it even when
+	// the client that issued them is gone. A statement_timeout in the DSN
+	// takes precedence.
+	StatementTimeout time.Duration
+	QueryTimeout     time.Duration
+	// BackupTimeout bounds Backup and Restore in place of QueryTimeout and
+	// of StatementTimeout, which a whole database outgrows. Zero means no
+	// limit.
+	BackupTimeout time.Duration
+	// ReplicaDSN, when set, connects a read-only replica that serves reads
+	// tolerating replication lag.
+	ReplicaDSN string
+	// SlowQueryThreshold logs storage calls taking at least this long. Zero
+	// disables the log.
+	SlowQueryThreshold time.Duration
 }
 
-func NewPostgresStorage(connectionString string) (*PostgresStorage, error) {
-	db, err := sql.Open("postgres", connectionString)
+// NewPostgresStorage connects a pgx pool to connectionString. Every query
+// goes through pgx's per-connection statement cache, so Postgres parses and
+// plans each distinct query once per connection.
+func NewPostgresStorage(connectionString string, opts Options) (*PostgresStorage, error) {
+	pool, breaker, err := openPool("Database", connectionString, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
-
-	if err := db.Ping(); err != nil {
+	if err := pool.Ping(context.Background()); err != nil {
+		pool.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	s := &PostgresStorage{
+		pool:          pool,
+		q:             pool,
+		breaker:       breaker,
+		r:             pool,
+		queryTimeout:  opts.QueryTimeout,
+		backupTimeout: opts.BackupTimeout,
+		metrics:       newQueryMetrics(opts.SlowQueryThreshold),
+		done:          make(chan struct{}),
+	}
+	go s.probe(pool, breaker, breakerCooldown)
 
-	return &PostgresStorage{db: db}, nil
+	if opts.ReplicaDSN != "" {
+		replica, replicaBreaker, err := openPool("Read replica", opts.ReplicaDSN, opts)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("failed to open read replica: %w", err)
+		}
+		// A replica that is down only costs the primary some extra reads,
+		// so it does not stop the service from starting.
+		if err := replica.Ping(context.Background()); err != nil {
+			log.Printf("Read replica is unavailable, reading from the primary: %v", err)
+		}
+		s.replica, s.replicaBreaker = replica, replicaBreaker
+		s.r = &replicaQuerier{replica: replica, primary: pool, breaker: replicaBreaker}
+		go s.probe(replica, replicaBreaker, breakerCooldown)
+	}
+
+	return s, nil
+}
+
+// openPool opens a connection pool whose dials go through a circuit breaker
+// named name.
+func openPool(name, dsn string, opts Options) (*pgxpool.Pool, *circuitBreaker, error) {
+	config, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.MaxOpenConns > 0 {
+		config.MaxConns = int32(opts.MaxOpenConns)
+	}
+	config.MinConns = int32(opts.MinConns)
+	if opts.ConnMaxLifetime > 0 {
+		config.MaxConnLifetime = opts.ConnMaxLifetime
+	}
+	if _, set := config.ConnConfig.RuntimeParams["statement_timeout"]; !set && opts.StatementTimeout > 0 {
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(opts.StatementTimeout.Milliseconds(), 10)
+	}
+
+	breaker := newCircuitBreaker(name, breakerFailureThreshold, breakerCooldown)
+	config.ConnConfig.DialFunc = breaker.dial(config.ConnConfig.DialFunc)
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pool, breaker, nil
 }
 
 func (s *PostgresStorage) Close() error {
-	return s.db.Close()
+	close(s.done)
+	if s.replica != nil {
+		s.replica.Close()
+	}
+	s.pool.Close()
+	return nil
 }
 
-func (s *PostgresStorage) CreateTeam(ctx context.Context, team *models.Team) error {
-	tx, err := s.db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
+// probe pings pool while its circuit breaker is open so the service recovers
+// even when no requests arrive to test the connection.
+func (s *PostgresStorage) probe(pool *pgxpool.Pool, breaker *circuitBreaker, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if !breaker.isOpen() {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			if err := pool.Ping(ctx); err == nil {
+				breaker.success()
+			}
+			cancel()
+		}
+	}
+}
+
+// userColumns lists the users columns in the order scanUser expects.
+const userColumns = "user_id, username, team_name, is_active, is_mentee, review_weight, timezone, working_hours, seniority, COALESCE(manager_id, ''), directory_managed"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row rowScanner) (models.User, error) {
+	var u models.User
+	err := row.Scan(&u.UserID, &u.Username, &u.TeamName, &u.IsActive, &u.IsMentee, &u.ReviewWeight, &u.Timezone, &u.WorkingHours, &u.Seniority, &u.ManagerID, &u.DirectoryManaged)
+	return u, err
+}
+
+// CreateTeam inserts the team and upserts its members in one batch, so the
+// whole team costs a single round trip.
+func (s *PostgresStorage) CreateTeam(ctx context.Context, team *models.Team) (err error) {
+	ctx, done := s.query(ctx, "CreateTeam", &err)
+	defer done()
+
+	batch := &pgx.Batch{}
+	batch.Queue("INSERT INTO teams (team_name) VALUES ($1)", team.TeamName)
+	for _, member := range team.Members {
+		queueUpsertUser(batch, &models.User{
+			UserID:       member.UserID,
+			Username:     member.Username,
+			TeamName:     team.TeamName,
+			IsActive:     member.IsActive,
+			IsMentee:     member.IsMentee,
+			ReviewWeight: member.ReviewWeight,
+		})
 	}
-	defer tx.Rollback()
 
-	_, err = tx.ExecContext(ctx, "INSERT INTO teams (team_name) VALUES ($1)", team.TeamName)
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		return tx.SendBatch(ctx, batch).Close()
+	})
+}
+
+// inTx runs fn in a transaction. When the storage is already bound to a
+// transaction fn joins it instead of opening a nested one.
+func (s *PostgresStorage) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	if s.tx != nil {
+		return fn(s.tx)
+	}
+
+	tx, err := s.pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback(ctx)
 
-	for _, member := range team.Members {
-		user := &models.User{
-			UserID:   member.UserID,
-			Username: member.Username,
-			TeamName: team.TeamName,
-			IsActive: member.IsActive,
-		}
-		err = s.upsertUserTx(ctx, tx, user)
-		if err != nil {
-			return err
-		}
+	if err := fn(tx); err != nil {
+		return err
 	}
+	return tx.Commit(ctx)
+}
+
+func (s *PostgresStorage) GetTeam(ctx context.Context, teamName string) (_ *models.Team, err error) {
+	ctx, done := s.query(ctx, "GetTeam", &err)
+	defer done()
+	return getTeam(ctx, s.r, teamName)
+}
 
-	return tx.Commit()
+func (s *PostgresStorage) GetTeamFromPrimary(ctx context.Context, teamName string) (_ *models.Team, err error) {
+	ctx, done := s.query(ctx, "GetTeamFromPrimary", &err)
+	defer done()
+	return getTeam(ctx, s.q, teamName)
 }
 
-func (s *PostgresStorage) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+func getTeam(ctx context.Context, q querier, teamName string) (*models.Team, error) {
 	var exists bool
-	err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", teamName).Scan(&exists)
+	err := q.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1 AND deleted_at IS NULL)", teamName).Scan(&exists)
 	if err != nil {
 		return nil, err
 	}
@@ -74,8 +246,8 @@ func (s *PostgresStorage) GetTeam(ctx context.Context, teamName string) (*models
 		return nil, nil
 	}
 
-	rows, err := s.db.QueryContext(ctx,
-		"SELECT user_id, username, is_active FROM users WHERE team_name = $1 ORDER BY user_id",
+	rows, err := q.Query(ctx,
+		"SELECT user_id, username, is_active, is_mentee, review_weight FROM users WHERE team_name = $1 AND deleted_at IS NULL ORDER BY user_id",
 		teamName)
 	if err != nil {
 		return nil, err
@@ -85,7 +257,7 @@ func (s *PostgresStorage) GetTeam(ctx context.Context, teamName string) (*models
 	members := []models.TeamMember{}
 	for rows.Next() {
 		var member models.TeamMember
-		if err := rows.Scan(&member.UserID, &member.Username, &member.IsActive); err != nil {
+		if err := rows.Scan(&member.UserID, &member.Username, &member.IsActive, &member.IsMentee, &member.ReviewWeight); err != nil {
 			return nil, err
 		}
 		members = append(members, member)
@@ -97,46 +269,61 @@ func (s *PostgresStorage) GetTeam(ctx context.Context, teamName string) (*models
 	}, nil
 }
 
-func (s *PostgresStorage) TeamExists(ctx context.Context, teamName string) (bool, error) {
+func (s *PostgresStorage) TeamExists(ctx context.Context, teamName string) (_ bool, err error) {
+	ctx, done := s.query(ctx, "TeamExists", &err)
+	defer done()
+
 	var exists bool
-	err := s.db.QueryRowContext(ctx,
-		"SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)",
+	err = s.q.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1 AND deleted_at IS NULL)",
 		teamName).Scan(&exists)
 	return exists, err
 }
 
-func (s *PostgresStorage) CreateUser(ctx context.Context, user *models.User) error {
-	_, err := s.db.ExecContext(ctx,
-		"INSERT INTO users (user_id, username, team_name, is_active) VALUES ($1, $2, $3, $4)",
-		user.UserID, user.Username, user.TeamName, user.IsActive)
+func (s *PostgresStorage) CreateUser(ctx context.Context, user *models.User) (err error) {
+	ctx, done := s.query(ctx, "CreateUser", &err)
+	defer done()
+
+	_, err = s.q.Exec(ctx,
+		`INSERT INTO users (user_id, username, team_name, is_active, is_mentee, review_weight, timezone, working_hours, seniority, manager_id, directory_managed, deactivated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11, CASE WHEN $4 THEN NULL ELSE CURRENT_TIMESTAMP END)`,
+		user.UserID, user.Username, user.TeamName, user.IsActive, user.IsMentee, user.ReviewWeight, user.Timezone, user.WorkingHours, user.Seniority, user.ManagerID, user.DirectoryManaged)
 	return err
 }
 
-func (s *PostgresStorage) UpdateUser(ctx context.Context, user *models.User) error {
-	_, err := s.db.ExecContext(ctx,
-		"UPDATE users SET username = $1, team_name = $2, is_active = $3 WHERE user_id = $4",
-		user.Username, user.TeamName, user.IsActive, user.UserID)
+func (s *PostgresStorage) UpdateUser(ctx context.Context, user *models.User) (err error) {
+	ctx, done := s.query(ctx, "UpdateUser", &err)
+	defer done()
+
+	_, err = s.q.Exec(ctx,
+		`UPDATE users SET username = $1, team_name = $2, is_active = $3, is_mentee = $4, review_weight = $5,
+		     timezone = $6, working_hours = $7, seniority = $8, manager_id = NULLIF($9, ''),
+		     directory_managed = $10, updated_at = CURRENT_TIMESTAMP,
+		     deactivated_at = CASE WHEN $3 THEN NULL ELSE COALESCE(deactivated_at, CURRENT_TIMESTAMP) END
+		 WHERE user_id = $11`,
+		user.Username, user.TeamName, user.IsActive, user.IsMentee, user.ReviewWeight, user.Timezone, user.WorkingHours, user.Seniority, user.ManagerID, user.DirectoryManaged, user.UserID)
 	return err
 }
 
-func (s *PostgresStorage) GetUser(ctx context.Context, userID string) (*models.User, error) {
-	user := &models.User{}
-	err := s.db.QueryRowContext(ctx,
-		"SELECT user_id, username, team_name, is_active FROM users WHERE user_id = $1",
-		userID).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive)
-	if err == sql.ErrNoRows {
+func (s *PostgresStorage) GetUser(ctx context.Context, userID string) (_ *models.User, err error) {
+	ctx, done := s.query(ctx, "GetUser", &err)
+	defer done()
+
+	user, err := scanUser(s.q.QueryRow(ctx, "SELECT "+userColumns+" FROM users WHERE user_id = $1 AND deleted_at IS NULL", userID))
+	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	return user, nil
+	return &user, nil
 }
 
-func (s *PostgresStorage) GetUsersByTeam(ctx context.Context, teamName string) ([]models.User, error) {
-	rows, err := s.db.QueryContext(ctx,
-		"SELECT user_id, username, team_name, is_active FROM users WHERE team_name = $1",
-		teamName)
+func (s *PostgresStorage) GetUsersByTeam(ctx context.Context, teamName string) (_ []models.User, err error) {
+	ctx, done := s.query(ctx, "GetUsersByTeam", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx, "SELECT "+userColumns+" FROM users WHERE team_name = $1 AND deleted_at IS NULL", teamName)
 	if err != nil {
 		return nil, err
 	}
@@ -144,8 +331,8 @@ func (s *PostgresStorage) GetUsersByTeam(ctx context.Context, teamName string) (
 
 	users := []models.User{}
 	for rows.Next() {
-		var user models.User
-		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
+		user, err := scanUser(rows)
+		if err != nil {
 			return nil, err
 		}
 		users = append(users, user)
@@ -153,40 +340,100 @@ func (s *PostgresStorage) GetUsersByTeam(ctx context.Context, teamName string) (
 	return users, nil
 }
 
-func (s *PostgresStorage) upsertUserTx(ctx context.Context, tx *sql.Tx, user *models.User) error {
-	_, err := tx.ExecContext(ctx,
-		`INSERT INTO users (user_id, username, team_name, is_active) 
-		 VALUES ($1, $2, $3, $4)
+func (s *PostgresStorage) GetUsersByIDs(ctx context.Context, userIDs []string) (_ []models.User, err error) {
+	ctx, done := s.query(ctx, "GetUsersByIDs", &err)
+	defer done()
+
+	users := []models.User{}
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+
+	rows, err := s.q.Query(ctx,
+		"SELECT "+userColumns+" FROM users WHERE user_id = ANY($1) AND deleted_at IS NULL ORDER BY user_id",
+		userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func queueUpsertUser(batch *pgx.Batch, user *models.User) {
+	batch.Queue(
+		`INSERT INTO users (user_id, username, team_name, is_active, is_mentee, review_weight, deactivated_at) 
+		 VALUES ($1, $2, $3, $4, $5, $6, CASE WHEN $4 THEN NULL ELSE CURRENT_TIMESTAMP END)
 		 ON CONFLICT (user_id) 
-		 DO UPDATE SET username = $2, team_name = $3, is_active = $4`,
-		user.UserID, user.Username, user.TeamName, user.IsActive)
-	return err
+		 DO UPDATE SET username = $2, team_name = $3, is_active = $4, is_mentee = $5, review_weight = $6, updated_at = CURRENT_TIMESTAMP,
+		     deactivated_at = CASE WHEN $4 THEN NULL ELSE COALESCE(users.deactivated_at, CURRENT_TIMESTAMP) END,
+		     erased_at = NULL, deleted_at = NULL`,
+		user.UserID, user.Username, user.TeamName, user.IsActive, user.IsMentee, user.ReviewWeight)
 }
 
-func (s *PostgresStorage) CreatePullRequest(ctx context.Context, pr *models.PullRequest) error {
+func (s *PostgresStorage) CreatePullRequest(ctx context.Context, pr *models.PullRequest) (err error) {
+	ctx, done := s.query(ctx, "CreatePullRequest", &err)
+	defer done()
+
 	reviewersJSON, err := json.Marshal(pr.AssignedReviewers)
 	if err != nil {
 		return err
 	}
+	shadowJSON, err := marshalStrings(pr.ShadowReviewers)
+	if err != nil {
+		return err
+	}
+	labelsJSON, err := marshalStrings(pr.Labels)
+	if err != nil {
+		return err
+	}
+	requestedJSON, err := marshalStrings(pr.RequestedReviewers)
+	if err != nil {
+		return err
+	}
 
-	_, err = s.db.ExecContext(ctx,
-		`INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, assigned_reviewers, created_at)
-		 VALUES ($1, $2, $3, $4, $5, $6)`,
-		pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, reviewersJSON, pr.CreatedAt)
-	return err
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, priority, is_draft, assigned_reviewers, shadow_reviewers, labels, repository, requested_reviewers, lines_changed, files_changed, created_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+			pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, pr.Priority, pr.IsDraft, reviewersJSON, shadowJSON, labelsJSON, pr.Repository, requestedJSON, pr.LinesChanged, pr.FilesChanged, pr.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		if len(pr.ChangedPaths) > 0 {
+			_, err = tx.Exec(ctx,
+				`INSERT INTO pr_paths (pull_request_id, path)
+				 SELECT $1, unnest($2::text[])
+				 ON CONFLICT DO NOTHING`,
+				pr.PullRequestID, pr.ChangedPaths)
+		}
+		return err
+	})
 }
 
-func (s *PostgresStorage) GetPullRequest(ctx context.Context, prID string) (*models.PullRequest, error) {
+func (s *PostgresStorage) GetPullRequest(ctx context.Context, prID string) (_ *models.PullRequest, err error) {
+	ctx, done := s.query(ctx, "GetPullRequest", &err)
+	defer done()
+
 	var pr models.PullRequest
-	var reviewersJSON []byte
-	var createdAt, mergedAt sql.NullTime
+	var reviewersJSON, shadowJSON, labelsJSON, requestedJSON []byte
 
-	err := s.db.QueryRowContext(ctx,
-		`SELECT pull_request_id, pull_request_name, author_id, status, assigned_reviewers, created_at, merged_at
-		 FROM pull_requests WHERE pull_request_id = $1`,
-		prID).Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &reviewersJSON, &createdAt, &mergedAt)
+	err = s.q.QueryRow(ctx,
+		`SELECT pull_request_id, pull_request_name, author_id, status, priority, is_draft, assigned_reviewers, shadow_reviewers, labels, repository, requested_reviewers, lines_changed, files_changed, archived, created_at, merged_at,
+			ARRAY(SELECT path FROM pr_paths pp WHERE pp.pull_request_id = pr.pull_request_id ORDER BY path)
+		 FROM pull_requests pr WHERE pull_request_id = $1`,
+		prID).Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.Priority, &pr.IsDraft, &reviewersJSON, &shadowJSON, &labelsJSON, &pr.Repository, &requestedJSON, &pr.LinesChanged, &pr.FilesChanged, &pr.Archived, &pr.CreatedAt, &pr.MergedAt,
+		&pr.ChangedPaths)
 
-	if err == sql.ErrNoRows {
+	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
 	}
 	if err != nil {
@@ -196,45 +443,123 @@ func (s *PostgresStorage) GetPullRequest(ctx context.Context, prID string) (*mod
 	if err := json.Unmarshal(reviewersJSON, &pr.AssignedReviewers); err != nil {
 		return nil, err
 	}
-
-	if createdAt.Valid {
-		pr.CreatedAt = &createdAt.Time
+	if err := json.Unmarshal(shadowJSON, &pr.ShadowReviewers); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(labelsJSON, &pr.Labels); err != nil {
+		return nil, err
 	}
-	if mergedAt.Valid {
-		pr.MergedAt = &mergedAt.Time
+	if err := json.Unmarshal(requestedJSON, &pr.RequestedReviewers); err != nil {
+		return nil, err
+	}
+
+	pr.Reviews, err = s.getReviews(ctx, prID)
+	if err != nil {
+		return nil, err
 	}
 
 	return &pr, nil
 }
 
-func (s *PostgresStorage) UpdatePullRequest(ctx context.Context, pr *models.PullRequest) error {
+func (s *PostgresStorage) UpdatePullRequest(ctx context.Context, pr *models.PullRequest) (_ bool, err error) {
+	ctx, done := s.query(ctx, "UpdatePullRequest", &err)
+	defer done()
+
 	reviewersJSON, err := json.Marshal(pr.AssignedReviewers)
 	if err != nil {
-		return err
+		return false, err
+	}
+	shadowJSON, err := marshalStrings(pr.ShadowReviewers)
+	if err != nil {
+		return false, err
+	}
+	requestedJSON, err := marshalStrings(pr.RequestedReviewers)
+	if err != nil {
+		return false, err
 	}
 
-	_, err = s.db.ExecContext(ctx,
-		`UPDATE pull_requests 
-		 SET pull_request_name = $1, author_id = $2, status = $3, assigned_reviewers = $4, merged_at = $5
-		 WHERE pull_request_id = $6`,
-		pr.PullRequestName, pr.AuthorID, pr.Status, reviewersJSON, pr.MergedAt, pr.PullRequestID)
-	return err
+	tag, err := s.q.Exec(ctx,
+		`UPDATE pull_requests
+		 SET assigned_reviewers = $1, shadow_reviewers = $2, requested_reviewers = $3, is_draft = $4
+		 WHERE pull_request_id = $5 AND status = 'OPEN'`,
+		reviewersJSON, shadowJSON, requestedJSON, pr.IsDraft, pr.PullRequestID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (s *PostgresStorage) UpdatePullRequestStatus(ctx context.Context, pr *models.PullRequest, from models.PullRequestStatus) (_ bool, err error) {
+	ctx, done := s.query(ctx, "UpdatePullRequestStatus", &err)
+	defer done()
+
+	tag, err := s.q.Exec(ctx,
+		`UPDATE pull_requests SET status = $1, merged_at = $2
+		 WHERE pull_request_id = $3 AND status = $4`,
+		pr.Status, pr.MergedAt, pr.PullRequestID, from)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (s *PostgresStorage) UpdatePullRequestDetails(ctx context.Context, pr *models.PullRequest) (_ bool, err error) {
+	ctx, done := s.query(ctx, "UpdatePullRequestDetails", &err)
+	defer done()
+
+	labelsJSON, err := marshalStrings(pr.Labels)
+	if err != nil {
+		return false, err
+	}
+
+	updated := false
+	err = s.inTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx,
+			`UPDATE pull_requests
+			 SET pull_request_name = $1, labels = $2, priority = $3, repository = $4, lines_changed = $5
+			 WHERE pull_request_id = $6 AND status = 'OPEN'`,
+			pr.PullRequestName, labelsJSON, pr.Priority, pr.Repository, pr.LinesChanged, pr.PullRequestID)
+		if err != nil {
+			return err
+		}
+		if updated = tag.RowsAffected() > 0; !updated {
+			return nil
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM pr_paths WHERE pull_request_id = $1`, pr.PullRequestID); err != nil {
+			return err
+		}
+		if len(pr.ChangedPaths) > 0 {
+			_, err = tx.Exec(ctx,
+				`INSERT INTO pr_paths (pull_request_id, path)
+				 SELECT $1, unnest($2::text[])
+				 ON CONFLICT DO NOTHING`,
+				pr.PullRequestID, pr.ChangedPaths)
+		}
+		return err
+	})
+	return updated, err
 }
 
-func (s *PostgresStorage) PullRequestExists(ctx context.Context, prID string) (bool, error) {
+func (s *PostgresStorage) PullRequestExists(ctx context.Context, prID string) (_ bool, err error) {
+	ctx, done := s.query(ctx, "PullRequestExists", &err)
+	defer done()
+
 	var exists bool
-	err := s.db.QueryRowContext(ctx,
-		"SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)",
-		prID).Scan(&exists)
+	err = s.q.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)", prID).Scan(&exists)
 	return exists, err
 }
 
-func (s *PostgresStorage) GetPullRequestsByReviewer(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
-	rows, err := s.db.QueryContext(ctx,
-		`SELECT pull_request_id, pull_request_name, author_id, status
+func (s *PostgresStorage) GetPullRequestsByReviewer(ctx context.Context, userID string) (_ []models.PullRequestShort, err error) {
+	ctx, done := s.query(ctx, "GetPullRequestsByReviewer", &err)
+	defer done()
+
+	rows, err := s.r.Query(ctx,
+		`SELECT pull_request_id, pull_request_name, author_id, status, priority,
+			CASE WHEN assigned_reviewers ? $1 THEN 'reviewer' ELSE 'shadow' END
 		 FROM pull_requests
-		 WHERE assigned_reviewers::jsonb ? $1
-		 ORDER BY created_at DESC`,
+		 WHERE (assigned_reviewers::jsonb ? $1 OR shadow_reviewers ? $1) AND NOT archived
+		 ORDER BY priority = 'URGENT' DESC, created_at DESC`,
 		userID)
 	if err != nil {
 		return nil, err
@@ -244,7 +569,7 @@ func (s *PostgresStorage) GetPullRequestsByReviewer(ctx context.Context, userID
 	prs := []models.PullRequestShort{}
 	for rows.Next() {
 		var pr models.PullRequestShort
-		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status); err != nil {
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.Priority, &pr.Role); err != nil {
 			return nil, err
 		}
 		prs = append(prs, pr)
@@ -252,15 +577,18 @@ func (s *PostgresStorage) GetPullRequestsByReviewer(ctx context.Context, userID
 	return prs, nil
 }
 
-func (s *PostgresStorage) GetReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+func (s *PostgresStorage) GetReviewCounts(ctx context.Context, userIDs []string) (_ map[string]int, err error) {
+	ctx, done := s.query(ctx, "GetReviewCounts", &err)
+	defer done()
+
 	if len(userIDs) == 0 {
 		return map[string]int{}, nil
 	}
 
-	rows, err := s.db.QueryContext(ctx,
-		`SELECT jsonb_array_elements_text(assigned_reviewers) as reviewer_id, COUNT(*) as count
+	rows, err := s.q.Query(ctx,
+		`SELECT jsonb_array_elements_text(assigned_reviewers || shadow_reviewers) as reviewer_id, COUNT(*) as count
 		 FROM pull_requests
-		 WHERE status = 'OPEN'
+		 WHERE status = 'OPEN' AND NOT archived AND NOT is_draft
 		 GROUP BY reviewer_id`)
 	if err != nil {
 		return nil, err
@@ -286,57 +614,79 @@ func (s *PostgresStorage) GetReviewCounts(ctx context.Context, userIDs []string)
 	return counts, nil
 }
 
-func (s *PostgresStorage) GetStatistics(ctx context.Context) (*models.Statistics, error) {
-	stats := &models.Statistics{}
+// GetPathExpertise scores each user by how often they authored or reviewed
+// earlier PRs touching the given paths: an exact file match counts 2, a file
+// in the same directory counts 1.
+func (s *PostgresStorage) GetPathExpertise(ctx context.Context, paths []string, userIDs []string) (_ map[string]int, err error) {
+	ctx, done := s.query(ctx, "GetPathExpertise", &err)
+	defer done()
 
-	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM teams").Scan(&stats.TotalTeams)
-	if err != nil {
-		return nil, err
+	scores := make(map[string]int, len(userIDs))
+	if len(paths) == 0 || len(userIDs) == 0 {
+		return scores, nil
 	}
 
-	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*), COUNT(*) FILTER (WHERE is_active = true) FROM users").
-		Scan(&stats.TotalUsers, &stats.ActiveUsers)
+	rows, err := s.q.Query(ctx,
+		`WITH participants AS (
+			SELECT pull_request_id, author_id AS user_id FROM pull_requests
+			UNION
+			SELECT pull_request_id, jsonb_array_elements_text(assigned_reviewers) FROM pull_requests
+		)
+		SELECT p.user_id,
+			SUM(CASE WHEN pp.path = ANY($1) THEN 2 ELSE 1 END) AS score
+		FROM pr_paths pp
+		JOIN participants p ON p.pull_request_id = pp.pull_request_id
+		WHERE p.user_id = ANY($2)
+		  AND (pp.path = ANY($1)
+		       OR pp.dir = ANY(SELECT regexp_replace(path, '/[^/]*$', '') FROM unnest($1::text[]) AS path))
+		GROUP BY p.user_id`,
+		paths, userIDs)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	err = s.db.QueryRowContext(ctx,
-		`SELECT 
-			COUNT(*), 
-			COUNT(*) FILTER (WHERE status = 'OPEN'),
-			COUNT(*) FILTER (WHERE status = 'MERGED')
-		FROM pull_requests`).
-		Scan(&stats.TotalPRs, &stats.OpenPRs, &stats.MergedPRs)
-	if err != nil {
-		return nil, err
+	for rows.Next() {
+		var userID string
+		var score int
+		if err := rows.Scan(&userID, &score); err != nil {
+			return nil, err
+		}
+		scores[userID] = score
 	}
+	return scores, rows.Err()
+}
 
-	rows, err := s.db.QueryContext(ctx,
-		`SELECT 
+func (s *PostgresStorage) GetWorkload(ctx context.Context, since7d, since30d time.Time) (_ []models.UserWorkload, err error) {
+	ctx, done := s.query(ctx, "GetWorkload", &err)
+	defer done()
+
+	rows, err := s.r.Query(ctx,
+		`SELECT
 			u.user_id,
 			u.username,
-			COUNT(*) FILTER (WHERE pr.status = 'OPEN') as open_reviews,
-			COUNT(*) FILTER (WHERE pr.status = 'MERGED') as completed_reviews,
-			COUNT(*) as total_reviews
+			u.team_name,
+			COUNT(pr.pull_request_id) FILTER (WHERE pr.status = 'OPEN') as open_reviews,
+			COUNT(pr.pull_request_id) FILTER (WHERE pr.created_at >= $1) as assigned_7d,
+			COUNT(pr.pull_request_id) FILTER (WHERE pr.created_at >= $2) as assigned_30d
 		FROM users u
 		LEFT JOIN pull_requests pr ON pr.assigned_reviewers::jsonb ? u.user_id
-		GROUP BY u.user_id, u.username
-		HAVING COUNT(*) > 0
-		ORDER BY total_reviews DESC, open_reviews DESC
-		LIMIT 10`)
+		WHERE u.is_active = true AND u.deleted_at IS NULL
+		GROUP BY u.user_id, u.username, u.team_name
+		ORDER BY u.team_name, u.user_id`,
+		since7d, since30d)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	stats.TopReviewers = []models.ReviewerStats{}
+	workload := []models.UserWorkload{}
 	for rows.Next() {
-		var rs models.ReviewerStats
-		if err := rows.Scan(&rs.UserID, &rs.Username, &rs.OpenReviews, &rs.CompletedReviews, &rs.TotalReviews); err != nil {
+		var uw models.UserWorkload
+		if err := rows.Scan(&uw.UserID, &uw.Username, &uw.TeamName, &uw.OpenReviews, &uw.Assigned7d, &uw.Assigned30d); err != nil {
 			return nil, err
 		}
-		stats.TopReviewers = append(stats.TopReviewers, rs)
+		workload = append(workload, uw)
 	}
-
-	return stats, nil
+	return workload, rows.Err()
 }
@@ -0,0 +1,183 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+	"github.com/Thorlik/avito_internship/internal/domain/repository/storagetest"
+)
+
+// openTestStorage connects to TEST_DATABASE_URL, a migrated database, and
+// skips the test when it is not set.
+func openTestStorage(t *testing.T, opts Options) *PostgresStorage {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	s, err := NewPostgresStorage(dsn, opts)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// TestPostgresStorage runs the storage contract against the test database,
+// emptying it before every case. The outbox, the anomaly findings and the
+// maintenance mode are not backed up, but must be emptied too.
+func TestPostgresStorage(t *testing.T) {
+	s := openTestStorage(t, Options{MaxOpenConns: 20})
+
+	tables := []string{}
+	for _, table := range append(backupTables, "outbox", "webhook_deliveries", "anomalies", "maintenance") {
+		tables = append(tables, pgx.Identifier{table}.Sanitize())
+	}
+	storagetest.Run(t, func(t *testing.T) repository.Storage {
+		_, err := s.pool.Exec(context.Background(),
+			"TRUNCATE "+strings.Join(tables, ", ")+" RESTART IDENTITY CASCADE")
+		if err != nil {
+			t.Fatalf("empty database: %v", err)
+		}
+		return s
+	})
+}
+
+// sleep runs pg_sleep like a storage method would.
+func (s *PostgresStorage) sleep(ctx context.Context, d time.Duration) (err error) {
+	ctx, done := s.query(ctx, "Sleep", &err)
+	defer done()
+
+	_, err = s.q.Exec(ctx, "SELECT pg_sleep($1)", d.Seconds())
+	return err
+}
+
+func TestStatementTimeout(t *testing.T) {
+	s := openTestStorage(t, Options{StatementTimeout: 100 * time.Millisecond})
+
+	start := time.Now()
+	err := s.sleep(context.Background(), 5*time.Second)
+	if !errors.Is(err, repository.ErrTimeout) {
+		t.Fatalf("err = %v, want %v", err, repository.ErrTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("statement ran for %s despite the 100ms statement_timeout", elapsed)
+	}
+}
+
+// TestCancelledQueryStops checks that cancelling the caller's context stops
+// the statement on the server instead of leaving it running.
+func TestCancelledQueryStops(t *testing.T) {
+	s := openTestStorage(t, Options{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	if err := s.sleep(ctx, 30*time.Second); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want %v", err, context.Canceled)
+	}
+
+	// The cancel request reaches the server asynchronously.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var running int
+		err := s.pool.QueryRow(context.Background(),
+			`SELECT COUNT(*) FROM pg_stat_activity
+			 WHERE state = 'active' AND query LIKE 'SELECT pg_sleep%' AND pid <> pg_backend_pid()`).Scan(&running)
+		if err != nil {
+			t.Fatalf("pg_stat_activity: %v", err)
+		}
+		if running == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("pg_sleep is still running on the server after its context was cancelled")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestLeases(t *testing.T) {
+	s := openTestStorage(t, Options{})
+	ctx := context.Background()
+	if _, err := s.pool.Exec(ctx, "DELETE FROM scheduler_leases"); err != nil {
+		t.Fatalf("empty leases: %v", err)
+	}
+
+	steps := []struct {
+		holder string
+		want   bool
+	}{
+		{"a", true},
+		{"b", false},
+		{"a", true},
+	}
+	for _, step := range steps {
+		got, err := s.AcquireLease(ctx, "jobs", step.holder, time.Minute)
+		if err != nil {
+			t.Fatalf("acquire by %s: %v", step.holder, err)
+		}
+		if got != step.want {
+			t.Fatalf("acquire by %s = %v, want %v", step.holder, got, step.want)
+		}
+	}
+
+	if err := s.ReleaseLease(ctx, "jobs", "b"); err != nil {
+		t.Fatalf("release by b: %v", err)
+	}
+	if got, _ := s.AcquireLease(ctx, "jobs", "b", time.Minute); got {
+		t.Fatal("b acquired the lease after releasing a lease it did not hold")
+	}
+	if err := s.ReleaseLease(ctx, "jobs", "a"); err != nil {
+		t.Fatalf("release by a: %v", err)
+	}
+	if got, err := s.AcquireLease(ctx, "jobs", "b", time.Minute); err != nil || !got {
+		t.Fatalf("acquire by b after release = %v, %v; want true", got, err)
+	}
+}
+
+func TestConnectionErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{&pgconn.PgError{Code: "08006"}, repository.ErrUnavailable},
+		{&pgconn.PgError{Code: "57P01"}, repository.ErrUnavailable},
+		{&pgconn.PgError{Code: "57P03"}, repository.ErrUnavailable},
+		{fmt.Errorf("read reply: %w", io.ErrUnexpectedEOF), repository.ErrUnavailable},
+		{&net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}, repository.ErrUnavailable},
+		{errors.New("conn closed"), repository.ErrUnavailable},
+		{&pgconn.PgError{Code: "23505"}, repository.ErrAlreadyExists},
+		{pgx.ErrNoRows, pgx.ErrNoRows},
+	}
+	for _, tt := range tests {
+		if got := translateError(context.Background(), tt.err); !errors.Is(got, tt.want) {
+			t.Errorf("translateError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+
+	// Connections lost mid-query open the breaker like failed dials.
+	s := &PostgresStorage{
+		breaker: newCircuitBreaker("Database", 3, time.Minute),
+		metrics: newQueryMetrics(0),
+	}
+	for i := 0; i < 3; i++ {
+		err := error(&pgconn.PgError{Code: "57P01"})
+		_, done := s.query(context.Background(), "Lost", &err)
+		done()
+	}
+	if !s.breaker.isOpen() {
+		t.Error("breaker closed after 3 lost connections, want open")
+	}
+}
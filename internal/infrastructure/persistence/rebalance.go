@@ -0,0 +1,49 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// GetOpenPullRequestsByTeam returns the open, unarchived PRs authored by
+// members of the team. Reviews and changed paths are not loaded.
+func (s *PostgresStorage) GetOpenPullRequestsByTeam(ctx context.Context, teamName string) (_ []models.PullRequest, err error) {
+	ctx, done := s.query(ctx, "GetOpenPullRequestsByTeam", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		`SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.priority,
+			pr.assigned_reviewers, pr.shadow_reviewers, pr.labels, pr.repository, pr.lines_changed, pr.created_at
+		 FROM pull_requests pr
+		 JOIN users u ON u.user_id = pr.author_id
+		 WHERE u.team_name = $1 AND pr.status = 'OPEN' AND NOT pr.archived
+		 ORDER BY pr.created_at, pr.pull_request_id`,
+		teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prs := []models.PullRequest{}
+	for rows.Next() {
+		var pr models.PullRequest
+		var reviewersJSON, shadowJSON, labelsJSON []byte
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.Priority,
+			&reviewersJSON, &shadowJSON, &labelsJSON, &pr.Repository, &pr.LinesChanged, &pr.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(reviewersJSON, &pr.AssignedReviewers); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(shadowJSON, &pr.ShadowReviewers); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(labelsJSON, &pr.Labels); err != nil {
+			return nil, err
+		}
+		prs = append(prs, pr)
+	}
+	return prs, rows.Err()
+}
@@ -0,0 +1,76 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/Thorlik/avito_internship/internal/domain/repository"
+)
+
+// replicaQuerier sends reads to the replica and falls back to the primary
+// while the replica's circuit breaker is open or when a read fails because
+// the replica cannot be reached or drops the connection, which counts
+// toward its breaker. Writes always go to the primary.
+type replicaQuerier struct {
+	replica *pgxpool.Pool
+	primary *pgxpool.Pool
+	breaker *circuitBreaker
+}
+
+func (q *replicaQuerier) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return q.primary.Exec(ctx, sql, args...)
+}
+
+func (q *replicaQuerier) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if !q.breaker.isOpen() {
+		rows, err := q.replica.Query(ctx, sql, args...)
+		if !q.unreachable(ctx, err) {
+			return rows, err
+		}
+	}
+	return q.primary.Query(ctx, sql, args...)
+}
+
+func (q *replicaQuerier) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if q.breaker.isOpen() {
+		return q.primary.QueryRow(ctx, sql, args...)
+	}
+	return &fallbackRow{
+		row:         q.replica.QueryRow(ctx, sql, args...),
+		unreachable: func(err error) bool { return q.unreachable(ctx, err) },
+		fallback:    func() pgx.Row { return q.primary.QueryRow(ctx, sql, args...) },
+	}
+}
+
+// unreachable reports whether err means the replica could not serve the
+// read, counting a lost connection toward its breaker.
+func (q *replicaQuerier) unreachable(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+	if lostConnection(ctx, err) {
+		q.breaker.failure()
+		return true
+	}
+	return errors.Is(err, repository.ErrUnavailable)
+}
+
+// fallbackRow scans the replica's row, or the primary's when the replica
+// turns out to be unreachable. pgx reports QueryRow errors only on Scan.
+type fallbackRow struct {
+	row         pgx.Row
+	unreachable func(err error) bool
+	fallback    func() pgx.Row
+}
+
+func (r *fallbackRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	if r.unreachable(err) {
+		return r.fallback().Scan(dest...)
+	}
+	return err
+}
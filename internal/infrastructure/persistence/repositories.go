@@ -0,0 +1,97 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+const repositoryColumns = "name, COALESCE(default_team, ''), reviewer_count, required_approvals, created_at, updated_at"
+
+func scanRepository(row rowScanner) (models.Repository, error) {
+	var r models.Repository
+	err := row.Scan(&r.Name, &r.DefaultTeam, &r.Settings.ReviewerCount, &r.Settings.RequiredApprovals, &r.CreatedAt, &r.UpdatedAt)
+	return r, err
+}
+
+func (s *PostgresStorage) CreateRepository(ctx context.Context, r *models.Repository) (err error) {
+	ctx, done := s.query(ctx, "CreateRepository", &err)
+	defer done()
+
+	return s.q.QueryRow(ctx,
+		`INSERT INTO repositories (name, default_team, reviewer_count, required_approvals)
+		 VALUES ($1, NULLIF($2, ''), $3, $4)
+		 RETURNING created_at, updated_at`,
+		r.Name, r.DefaultTeam, r.Settings.ReviewerCount, r.Settings.RequiredApprovals).
+		Scan(&r.CreatedAt, &r.UpdatedAt)
+}
+
+func (s *PostgresStorage) GetRepository(ctx context.Context, name string) (_ *models.Repository, err error) {
+	ctx, done := s.query(ctx, "GetRepository", &err)
+	defer done()
+
+	r, err := scanRepository(s.q.QueryRow(ctx, "SELECT "+repositoryColumns+" FROM repositories WHERE name = $1", name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (s *PostgresStorage) ListRepositories(ctx context.Context, teamName string) (_ []models.Repository, err error) {
+	ctx, done := s.query(ctx, "ListRepositories", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		"SELECT "+repositoryColumns+` FROM repositories
+		 WHERE $1 = '' OR default_team = $1
+		 ORDER BY name`,
+		teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	repos := []models.Repository{}
+	for rows.Next() {
+		r, err := scanRepository(rows)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, r)
+	}
+	return repos, rows.Err()
+}
+
+func (s *PostgresStorage) UpdateRepository(ctx context.Context, r *models.Repository) (_ bool, err error) {
+	ctx, done := s.query(ctx, "UpdateRepository", &err)
+	defer done()
+
+	err = s.q.QueryRow(ctx,
+		`UPDATE repositories SET default_team = NULLIF($2, ''), reviewer_count = $3, required_approvals = $4,
+		     updated_at = CURRENT_TIMESTAMP
+		 WHERE name = $1
+		 RETURNING created_at, updated_at`,
+		r.Name, r.DefaultTeam, r.Settings.ReviewerCount, r.Settings.RequiredApprovals).
+		Scan(&r.CreatedAt, &r.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *PostgresStorage) DeleteRepository(ctx context.Context, name string) (_ bool, err error) {
+	ctx, done := s.query(ctx, "DeleteRepository", &err)
+	defer done()
+
+	res, err := s.q.Exec(ctx, "DELETE FROM repositories WHERE name = $1", name)
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
@@ -0,0 +1,64 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) CreateRepositoryRule(ctx context.Context, rule *models.RepositoryRule) (err error) {
+	ctx, done := s.query(ctx, "CreateRepositoryRule", &err)
+	defer done()
+
+	userIDsJSON, err := marshalStrings(rule.UserIDs)
+	if err != nil {
+		return err
+	}
+	return s.q.QueryRow(ctx,
+		`INSERT INTO team_repository_rules (team_name, repository, user_ids)
+		 VALUES ($1, $2, $3)
+		 RETURNING id, created_at`,
+		rule.TeamName, rule.Repository, userIDsJSON).Scan(&rule.ID, &rule.CreatedAt)
+}
+
+func (s *PostgresStorage) GetRepositoryRules(ctx context.Context, teamName string) (_ []models.RepositoryRule, err error) {
+	ctx, done := s.query(ctx, "GetRepositoryRules", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		`SELECT id, team_name, repository, user_ids, created_at
+		 FROM team_repository_rules
+		 WHERE team_name = $1
+		 ORDER BY id`,
+		teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.RepositoryRule{}
+	for rows.Next() {
+		var rule models.RepositoryRule
+		var userIDsJSON []byte
+		if err := rows.Scan(&rule.ID, &rule.TeamName, &rule.Repository, &userIDsJSON, &rule.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(userIDsJSON, &rule.UserIDs); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (s *PostgresStorage) DeleteRepositoryRule(ctx context.Context, id int64) (_ bool, err error) {
+	ctx, done := s.query(ctx, "DeleteRepositoryRule", &err)
+	defer done()
+
+	res, err := s.q.Exec(ctx, "DELETE FROM team_repository_rules WHERE id = $1", id)
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
@@ -0,0 +1,78 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// eraseUsersQuery anonymizes the users matching cond, removes their external
+// identities, digest preferences and manager and clears their review
+// comments, archived PRs included, and the reviews older event log entries
+// still carry, returning how many users were erased.
+// User IDs are kept so that PRs, reviews and assignment history stay
+// consistent.
+func eraseUsersQuery(cond string) string {
+	return `WITH erased AS (
+		UPDATE users
+		SET username = '` + models.ErasedUsername + `', is_active = false, is_mentee = false,
+		    timezone = '', working_hours = '', manager_id = NULL, updated_at = CURRENT_TIMESTAMP,
+		    deactivated_at = COALESCE(deactivated_at, CURRENT_TIMESTAMP), erased_at = CURRENT_TIMESTAMP
+		WHERE ` + cond + `
+		RETURNING user_id
+	), cleared AS (
+		UPDATE reviews SET comment = ''
+		WHERE reviewer_id IN (SELECT user_id FROM erased) AND comment <> ''
+		RETURNING 1
+	), scrubbed AS (
+		UPDATE domain_events SET payload = payload #- '{pull_request,reviews}'
+		WHERE EXISTS (
+			SELECT 1 FROM jsonb_array_elements(payload->'pull_request'->'reviews') AS r(review)
+			WHERE review->>'reviewer_id' IN (SELECT user_id FROM erased)
+		)
+		RETURNING 1
+	), unlinked AS (
+		DELETE FROM user_identities
+		WHERE user_id IN (SELECT user_id FROM erased)
+		RETURNING 1
+	), unsubscribed AS (
+		DELETE FROM digest_preferences
+		WHERE user_id IN (SELECT user_id FROM erased)
+		RETURNING 1
+	)
+	SELECT COUNT(*) FROM erased`
+}
+
+func (s *PostgresStorage) EraseUser(ctx context.Context, userID string) (err error) {
+	ctx, done := s.query(ctx, "EraseUser", &err)
+	defer done()
+
+	var n int64
+	return s.q.QueryRow(ctx, eraseUsersQuery("user_id = $1"), userID).Scan(&n)
+}
+
+func (s *PostgresStorage) EraseInactiveUsers(ctx context.Context, deactivatedBefore time.Time) (n int64, err error) {
+	ctx, done := s.query(ctx, "EraseInactiveUsers", &err)
+	defer done()
+
+	err = s.q.QueryRow(ctx,
+		eraseUsersQuery("NOT is_active AND erased_at IS NULL AND deactivated_at < $1"),
+		deactivatedBefore).Scan(&n)
+	return n, err
+}
+
+// PurgeArchivedPullRequests deletes PRs archived before archivedBefore along
+// with their reviews and assignment history.
+func (s *PostgresStorage) PurgeArchivedPullRequests(ctx context.Context, archivedBefore time.Time) (_ int64, err error) {
+	ctx, done := s.query(ctx, "PurgeArchivedPullRequests", &err)
+	defer done()
+
+	res, err := s.q.Exec(ctx,
+		"DELETE FROM pull_requests WHERE archived AND archived_at < $1",
+		archivedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
@@ -0,0 +1,43 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) CreateReview(ctx context.Context, review *models.Review) (err error) {
+	ctx, done := s.query(ctx, "CreateReview", &err)
+	defer done()
+
+	return s.q.QueryRow(ctx,
+		`INSERT INTO reviews (pull_request_id, reviewer_id, state, comment)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id, created_at`,
+		review.PullRequestID, review.ReviewerID, review.State, review.Comment).Scan(&review.ID, &review.CreatedAt)
+}
+
+// getReviews loads the reviews of a PR in submission order. It expects ctx to
+// already carry the query timeout of the calling storage method.
+func (s *PostgresStorage) getReviews(ctx context.Context, prID string) ([]models.Review, error) {
+	rows, err := s.q.Query(ctx,
+		`SELECT id, pull_request_id, reviewer_id, state, comment, created_at
+		 FROM reviews
+		 WHERE pull_request_id = $1
+		 ORDER BY created_at, id`,
+		prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []models.Review{}
+	for rows.Next() {
+		var r models.Review
+		if err := rows.Scan(&r.ID, &r.PullRequestID, &r.ReviewerID, &r.State, &r.Comment, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, r)
+	}
+	return reviews, rows.Err()
+}
@@ -0,0 +1,37 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetRotationCursor returns the last user the team's round-robin rotation
+// assigned, or "" before the first assignment. The row stays locked until
+// the surrounding transaction ends.
+func (s *PostgresStorage) GetRotationCursor(ctx context.Context, teamName string) (_ string, err error) {
+	ctx, done := s.query(ctx, "GetRotationCursor", &err)
+	defer done()
+
+	var userID string
+	err = s.q.QueryRow(ctx,
+		"SELECT last_user_id FROM team_rotation WHERE team_name = $1 FOR UPDATE",
+		teamName).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return userID, err
+}
+
+func (s *PostgresStorage) SetRotationCursor(ctx context.Context, teamName, userID string) (err error) {
+	ctx, done := s.query(ctx, "SetRotationCursor", &err)
+	defer done()
+
+	_, err = s.q.Exec(ctx,
+		`INSERT INTO team_rotation (team_name, last_user_id)
+		 VALUES ($1, $2)
+		 ON CONFLICT (team_name) DO UPDATE SET last_user_id = EXCLUDED.last_user_id, updated_at = CURRENT_TIMESTAMP`,
+		teamName, userID)
+	return err
+}
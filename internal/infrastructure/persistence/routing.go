@@ -0,0 +1,66 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) CreateRoutingRule(ctx context.Context, rule *models.RoutingRule) (err error) {
+	ctx, done := s.query(ctx, "CreateRoutingRule", &err)
+	defer done()
+
+	return s.q.QueryRow(ctx,
+		`INSERT INTO team_routing_rules (team_name, label, user_id)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (team_name, label, user_id) DO UPDATE SET label = EXCLUDED.label
+		 RETURNING id, created_at`,
+		rule.TeamName, rule.Label, rule.UserID).Scan(&rule.ID, &rule.CreatedAt)
+}
+
+func (s *PostgresStorage) GetRoutingRules(ctx context.Context, teamName string) (_ []models.RoutingRule, err error) {
+	ctx, done := s.query(ctx, "GetRoutingRules", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		`SELECT id, team_name, label, user_id, created_at
+		 FROM team_routing_rules
+		 WHERE team_name = $1
+		 ORDER BY label, user_id`,
+		teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.RoutingRule{}
+	for rows.Next() {
+		var rule models.RoutingRule
+		if err := rows.Scan(&rule.ID, &rule.TeamName, &rule.Label, &rule.UserID, &rule.CreatedAt); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+func (s *PostgresStorage) DeleteRoutingRule(ctx context.Context, id int64) (_ bool, err error) {
+	ctx, done := s.query(ctx, "DeleteRoutingRule", &err)
+	defer done()
+
+	res, err := s.q.Exec(ctx, "DELETE FROM team_routing_rules WHERE id = $1", id)
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+// marshalStrings encodes a string list for a JSONB array column, writing
+// nil as [] to keep the NOT NULL DEFAULT '[]' invariant.
+func marshalStrings(values []string) ([]byte, error) {
+	if values == nil {
+		values = []string{}
+	}
+	return json.Marshal(values)
+}
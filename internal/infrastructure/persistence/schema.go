@@ -0,0 +1,36 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// SchemaVersion is the migration this code is written against. It must be
+// raised with every new migration.
+const SchemaVersion = 47
+
+func (s *PostgresStorage) GetSchemaMigrations(ctx context.Context) (_ []models.SchemaMigration, err error) {
+	ctx, done := s.query(ctx, "GetSchemaMigrations", &err)
+	defer done()
+
+	// The primary, since replicas may lag behind a migration.
+	rows, err := s.q.Query(ctx,
+		`SELECT version, name, applied_at
+		 FROM schema_migrations
+		 ORDER BY version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	migrations := []models.SchemaMigration{}
+	for rows.Next() {
+		var m models.SchemaMigration
+		if err := rows.Scan(&m.Version, &m.Name, &m.AppliedAt); err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, m)
+	}
+	return migrations, rows.Err()
+}
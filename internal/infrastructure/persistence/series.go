@@ -0,0 +1,98 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// seriesSources says where the events of every metric are recorded: the
+// table, the column holding their time and a condition selecting them.
+var seriesSources = map[models.SeriesMetric]struct {
+	table, column, cond string
+}{
+	models.SeriesPullRequestsOpened:  {"pull_requests", "created_at", "true"},
+	models.SeriesPullRequestsMerged:  {"pull_requests", "merged_at", "true"},
+	models.SeriesReviewsSubmitted:    {"reviews", "created_at", "true"},
+	models.SeriesReviewerAssignments: {"assignment_history", "created_at", "role = 'reviewer' AND action <> 'removed'"},
+}
+
+func (s *PostgresStorage) GetSeries(ctx context.Context, metric models.SeriesMetric, from, to time.Time, step time.Duration) (_ []models.SeriesPoint, err error) {
+	ctx, done := s.query(ctx, "GetSeries", &err)
+	defer done()
+
+	src, ok := seriesSources[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown series metric %q", metric)
+	}
+	rows, err := s.r.Query(ctx,
+		`SELECT $1::timestamp + FLOOR(EXTRACT(EPOCH FROM (`+src.column+` - $1::timestamp)) / $3::float8) * $3::float8 * INTERVAL '1 second' AS bucket,
+			COUNT(*)
+		 FROM `+src.table+`
+		 WHERE `+src.column+` >= $1 AND `+src.column+` < $2 AND `+src.cond+`
+		 GROUP BY bucket
+		 ORDER BY bucket`,
+		from, to, step.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []models.SeriesPoint{}
+	for rows.Next() {
+		var p models.SeriesPoint
+		var n int64
+		if err := rows.Scan(&p.Time, &n); err != nil {
+			return nil, err
+		}
+		p.Value = float64(n)
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+func (s *PostgresStorage) GetAnnotations(ctx context.Context, kind models.AnnotationKind, from, to time.Time, limit int) (_ []models.Annotation, err error) {
+	ctx, done := s.query(ctx, "GetAnnotations", &err)
+	defer done()
+
+	var query string
+	switch kind {
+	case models.AnnotationMerges:
+		query = `SELECT pr.merged_at, 'Merged ' || pr.pull_request_name,
+				pr.pull_request_id || ' by ' || pr.author_id,
+				ARRAY[u.team_name, pr.priority]
+			 FROM pull_requests pr
+			 JOIN users u ON u.user_id = pr.author_id
+			 WHERE pr.merged_at >= $1 AND pr.merged_at < $2
+			 ORDER BY pr.merged_at, pr.pull_request_id
+			 LIMIT $3`
+	case models.AnnotationEscalations:
+		query = `SELECT created_at, 'Review escalation: ' || action,
+				user_id || ' on ' || pull_request_id || CASE WHEN target_id <> '' THEN ', ' || target_id ELSE '' END,
+				ARRAY[team_name, action]
+			 FROM review_escalations
+			 WHERE created_at >= $1 AND created_at < $2
+			 ORDER BY created_at, id
+			 LIMIT $3`
+	default:
+		return nil, fmt.Errorf("unknown annotation kind %q", kind)
+	}
+
+	rows, err := s.r.Query(ctx, query, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	annotations := []models.Annotation{}
+	for rows.Next() {
+		var a models.Annotation
+		if err := rows.Scan(&a.Time, &a.Title, &a.Text, &a.Tags); err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, a)
+	}
+	return annotations, rows.Err()
+}
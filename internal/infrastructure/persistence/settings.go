@@ -0,0 +1,65 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) GetTeamSettings(ctx context.Context, teamName string) (_ *models.TeamSettings, err error) {
+	ctx, done := s.query(ctx, "GetTeamSettings", &err)
+	defer done()
+
+	var ts models.TeamSettings
+	err = s.q.QueryRow(ctx,
+		`SELECT team_name, reviewer_count, assignment_strategy, max_open_reviews, stale_threshold_hours, notification_channel,
+			notification_provider, notification_template, prefer_working_hours, require_senior, peer_review_only,
+			remind_after_hours, escalate_after_hours, reassign_after_hours, locale, updated_at
+		 FROM team_settings WHERE team_name = $1`,
+		teamName).Scan(&ts.TeamName, &ts.ReviewerCount, &ts.AssignmentStrategy, &ts.MaxOpenReviews,
+		&ts.StaleThresholdHours, &ts.NotificationChannel, &ts.NotificationProvider, &ts.NotificationTemplate,
+		&ts.PreferWorkingHours, &ts.RequireSenior, &ts.PeerReviewOnly,
+		&ts.RemindAfterHours, &ts.EscalateAfterHours, &ts.ReassignAfterHours, &ts.Locale, &ts.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ts, nil
+}
+
+func (s *PostgresStorage) UpsertTeamSettings(ctx context.Context, ts *models.TeamSettings) (err error) {
+	ctx, done := s.query(ctx, "UpsertTeamSettings", &err)
+	defer done()
+
+	return s.q.QueryRow(ctx,
+		`INSERT INTO team_settings (team_name, reviewer_count, assignment_strategy, max_open_reviews, stale_threshold_hours, notification_channel,
+			notification_provider, notification_template, prefer_working_hours, require_senior, peer_review_only,
+			remind_after_hours, escalate_after_hours, reassign_after_hours, locale)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		 ON CONFLICT (team_name) DO UPDATE SET
+			reviewer_count = EXCLUDED.reviewer_count,
+			assignment_strategy = EXCLUDED.assignment_strategy,
+			max_open_reviews = EXCLUDED.max_open_reviews,
+			stale_threshold_hours = EXCLUDED.stale_threshold_hours,
+			notification_channel = EXCLUDED.notification_channel,
+			notification_provider = EXCLUDED.notification_provider,
+			notification_template = EXCLUDED.notification_template,
+			prefer_working_hours = EXCLUDED.prefer_working_hours,
+			require_senior = EXCLUDED.require_senior,
+			peer_review_only = EXCLUDED.peer_review_only,
+			remind_after_hours = EXCLUDED.remind_after_hours,
+			escalate_after_hours = EXCLUDED.escalate_after_hours,
+			reassign_after_hours = EXCLUDED.reassign_after_hours,
+			locale = EXCLUDED.locale,
+			updated_at = CURRENT_TIMESTAMP
+		 RETURNING updated_at`,
+		ts.TeamName, ts.ReviewerCount, ts.AssignmentStrategy, ts.MaxOpenReviews, ts.StaleThresholdHours, ts.NotificationChannel,
+		ts.NotificationProvider, ts.NotificationTemplate, ts.PreferWorkingHours, ts.RequireSenior, ts.PeerReviewOnly,
+		ts.RemindAfterHours, ts.EscalateAfterHours, ts.ReassignAfterHours, ts.Locale).
+		Scan(&ts.UpdatedAt)
+}
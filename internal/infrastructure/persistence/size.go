@@ -0,0 +1,42 @@
+package persistence
+
+import (
+	"context"
+)
+
+// GetReviewSizes returns, for each user, the lines changed by every open PR
+// they review, regular or shadow. Drafts and archived PRs are left out.
+func (s *PostgresStorage) GetReviewSizes(ctx context.Context, userIDs []string) (_ map[string][]int, err error) {
+	ctx, done := s.query(ctx, "GetReviewSizes", &err)
+	defer done()
+
+	sizes := make(map[string][]int, len(userIDs))
+	for _, userID := range userIDs {
+		sizes[userID] = []int{}
+	}
+	if len(userIDs) == 0 {
+		return sizes, nil
+	}
+
+	rows, err := s.q.Query(ctx,
+		`SELECT r.reviewer_id, pr.lines_changed
+		 FROM pull_requests pr,
+			jsonb_array_elements_text(pr.assigned_reviewers || pr.shadow_reviewers) AS r(reviewer_id)
+		 WHERE pr.status = 'OPEN' AND NOT pr.archived AND NOT pr.is_draft
+		   AND r.reviewer_id = ANY($1)`,
+		userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID string
+		var lines int
+		if err := rows.Scan(&userID, &lines); err != nil {
+			return nil, err
+		}
+		sizes[userID] = append(sizes[userID], lines)
+	}
+	return sizes, rows.Err()
+}
@@ -0,0 +1,76 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// MarkStalePullRequests flags open PRs older than their team's stale
+// threshold, or defaultThreshold for teams without one. URGENT PRs go stale
+// after a quarter of that; drafts never do.
+func (s *PostgresStorage) MarkStalePullRequests(ctx context.Context, now time.Time, defaultThreshold time.Duration) (_ []string, err error) {
+	ctx, done := s.query(ctx, "MarkStalePullRequests", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		`UPDATE pull_requests pr
+		 SET stale_since = CURRENT_TIMESTAMP
+		 FROM users u
+		 LEFT JOIN team_settings ts ON ts.team_name = u.team_name
+		 WHERE u.user_id = pr.author_id
+		   AND pr.status = 'OPEN' AND NOT pr.archived AND NOT pr.is_draft AND pr.stale_since IS NULL
+		   AND pr.created_at < $1::timestamp - INTERVAL '1 second' *
+			CASE WHEN ts.stale_threshold_hours > 0 THEN ts.stale_threshold_hours * 3600 ELSE $2 END *
+			CASE WHEN pr.priority = 'URGENT' THEN 0.25 ELSE 1 END
+		 RETURNING pr.pull_request_id`,
+		now, defaultThreshold.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (s *PostgresStorage) GetStalePullRequests(ctx context.Context) (_ []models.StalePullRequest, err error) {
+	ctx, done := s.query(ctx, "GetStalePullRequests", &err)
+	defer done()
+
+	rows, err := s.r.Query(ctx,
+		`SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, u.team_name,
+			pr.assigned_reviewers, pr.created_at, pr.stale_since
+		 FROM pull_requests pr
+		 JOIN users u ON u.user_id = pr.author_id
+		 WHERE pr.status = 'OPEN' AND NOT pr.archived AND pr.stale_since IS NOT NULL
+		 ORDER BY pr.created_at, pr.pull_request_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prs := []models.StalePullRequest{}
+	for rows.Next() {
+		var pr models.StalePullRequest
+		var reviewersJSON []byte
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.TeamName,
+			&reviewersJSON, &pr.CreatedAt, &pr.StaleSince); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(reviewersJSON, &pr.AssignedReviewers); err != nil {
+			return nil, err
+		}
+		prs = append(prs, pr)
+	}
+	return prs, rows.Err()
+}
@@ -0,0 +1,109 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// statisticsViews are the materialized views GetStatistics reads.
+var statisticsViews = []string{"statistics_totals", "statistics_open_by_priority", "statistics_reviewers", "statistics_by_repository"}
+
+// GetStatistics reads the pre-aggregated statistics as of their last
+// refresh, which ComputedAt reports.
+func (s *PostgresStorage) GetStatistics(ctx context.Context) (_ *models.Statistics, err error) {
+	ctx, done := s.query(ctx, "GetStatistics", &err)
+	defer done()
+
+	stats := &models.Statistics{}
+	err = s.r.QueryRow(ctx,
+		`SELECT total_teams, total_users, active_users, total_prs, open_prs, merged_prs, draft_prs, refreshed_at
+		 FROM statistics_totals`).
+		Scan(&stats.TotalTeams, &stats.TotalUsers, &stats.ActiveUsers, &stats.TotalPRs, &stats.OpenPRs,
+			&stats.MergedPRs, &stats.DraftPRs, &stats.ComputedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	priorityRows, err := s.r.Query(ctx, "SELECT priority, open_prs FROM statistics_open_by_priority")
+	if err != nil {
+		return nil, err
+	}
+	defer priorityRows.Close()
+
+	stats.OpenByPriority = map[models.PullRequestPriority]int{
+		models.PriorityLow:    0,
+		models.PriorityNormal: 0,
+		models.PriorityUrgent: 0,
+	}
+	for priorityRows.Next() {
+		var priority models.PullRequestPriority
+		var count int
+		if err := priorityRows.Scan(&priority, &count); err != nil {
+			return nil, err
+		}
+		stats.OpenByPriority[priority] = count
+	}
+	if err := priorityRows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.r.Query(ctx,
+		`SELECT user_id, username, open_reviews, completed_reviews, total_reviews
+		 FROM statistics_reviewers
+		 ORDER BY total_reviews DESC, open_reviews DESC, user_id
+		 LIMIT 10`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats.TopReviewers = []models.ReviewerStats{}
+	for rows.Next() {
+		var rs models.ReviewerStats
+		if err := rows.Scan(&rs.UserID, &rs.Username, &rs.OpenReviews, &rs.CompletedReviews, &rs.TotalReviews); err != nil {
+			return nil, err
+		}
+		stats.TopReviewers = append(stats.TopReviewers, rs)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	repoRows, err := s.r.Query(ctx,
+		`SELECT repository, total_prs, open_prs, merged_prs, draft_prs
+		 FROM statistics_by_repository
+		 ORDER BY repository`)
+	if err != nil {
+		return nil, err
+	}
+	defer repoRows.Close()
+
+	stats.ByRepository = []models.RepositoryStatistics{}
+	for repoRows.Next() {
+		var rs models.RepositoryStatistics
+		if err := repoRows.Scan(&rs.Repository, &rs.TotalPRs, &rs.OpenPRs, &rs.MergedPRs, &rs.DraftPRs); err != nil {
+			return nil, err
+		}
+		stats.ByRepository = append(stats.ByRepository, rs)
+	}
+	return stats, repoRows.Err()
+}
+
+// RefreshStatistics recomputes the statistics views. Refreshing
+// concurrently keeps GetStatistics answering from the old data meanwhile.
+func (s *PostgresStorage) RefreshStatistics(ctx context.Context) (err error) {
+	ctx, done := s.query(ctx, "RefreshStatistics", &err)
+	defer done()
+
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		for _, view := range statisticsViews {
+			if _, err := tx.Exec(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY "+view); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
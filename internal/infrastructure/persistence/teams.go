@@ -0,0 +1,45 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+func (s *PostgresStorage) ListTeams(ctx context.Context, filter models.TeamFilter) (_ []models.TeamSummary, err error) {
+	ctx, done := s.query(ctx, "ListTeams", &err)
+	defer done()
+
+	// A PR belongs to its author's team, as elsewhere; drafts are not
+	// waiting for review yet.
+	rows, err := s.r.Query(ctx,
+		`SELECT t.team_name,
+			(SELECT COUNT(*) FROM users u WHERE u.team_name = t.team_name AND u.deleted_at IS NULL),
+			(SELECT COUNT(*) FROM users u WHERE u.team_name = t.team_name AND u.deleted_at IS NULL AND u.is_active),
+			(SELECT COUNT(*) FROM pull_requests pr JOIN users a ON a.user_id = pr.author_id
+			 WHERE a.team_name = t.team_name AND pr.status = 'OPEN' AND NOT pr.archived AND NOT pr.is_draft),
+			COALESCE(ts.reviewer_count, 0), COALESCE(ts.assignment_strategy, ''), COALESCE(ts.max_open_reviews, 0),
+			COALESCE(ts.stale_threshold_hours, 0), COALESCE(ts.locale, '')
+		 FROM teams t
+		 LEFT JOIN team_settings ts ON ts.team_name = t.team_name
+		 WHERE t.deleted_at IS NULL AND t.team_name LIKE $1
+		 ORDER BY t.team_name
+		 LIMIT $2 OFFSET $3`,
+		likeEscaper.Replace(filter.Prefix)+"%", filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := []models.TeamSummary{}
+	for rows.Next() {
+		var t models.TeamSummary
+		if err := rows.Scan(&t.TeamName, &t.Members, &t.ActiveMembers, &t.OpenPullRequests,
+			&t.Settings.ReviewerCount, &t.Settings.AssignmentStrategy, &t.Settings.MaxOpenReviews,
+			&t.Settings.StaleThresholdHours, &t.Settings.Locale); err != nil {
+			return nil, err
+		}
+		teams = append(teams, t)
+	}
+	return teams, rows.Err()
+}
@@ -0,0 +1,103 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+// GetUserStatistics counts the user's open reviews now and, within the
+// period: PRs merged with the user as a reviewer, the user's first approval
+// of each PR and how long after their assignment it came, their
+// CHANGES_REQUESTED reviews and the reassignments that replaced them.
+func (s *PostgresStorage) GetUserStatistics(ctx context.Context, userID string, since, until time.Time) (_ *models.UserStatistics, err error) {
+	ctx, done := s.query(ctx, "GetUserStatistics", &err)
+	defer done()
+
+	// A zero bound leaves that end of the period open.
+	var from, to *time.Time
+	if !since.IsZero() {
+		from = &since
+	}
+	if !until.IsZero() {
+		to = &until
+	}
+
+	stats := &models.UserStatistics{UserID: userID}
+	err = s.r.QueryRow(ctx,
+		`WITH first_approvals AS (
+			SELECT v.pull_request_id, MIN(v.created_at) AS approved_at
+			FROM reviews v
+			WHERE v.reviewer_id = $1 AND v.state = 'APPROVED'
+			GROUP BY v.pull_request_id
+		)
+		SELECT
+			(SELECT COUNT(*) FROM pull_requests
+			 WHERE status = 'OPEN' AND NOT archived AND NOT is_draft AND assigned_reviewers ? $1),
+			(SELECT COUNT(*) FROM pull_requests
+			 WHERE status = 'MERGED' AND assigned_reviewers ? $1
+			   AND ($2::timestamp IS NULL OR merged_at >= $2) AND ($3::timestamp IS NULL OR merged_at < $3)),
+			(SELECT COUNT(*) FROM first_approvals fa
+			 WHERE ($2::timestamp IS NULL OR fa.approved_at >= $2) AND ($3::timestamp IS NULL OR fa.approved_at < $3)),
+			(SELECT COALESCE(AVG(EXTRACT(EPOCH FROM fa.approved_at - h.assigned_at)), 0)
+			 FROM first_approvals fa
+			 JOIN LATERAL (
+				SELECT MAX(created_at) AS assigned_at FROM assignment_history
+				WHERE pull_request_id = fa.pull_request_id AND user_id = $1 AND action <> 'removed'
+				  AND created_at <= fa.approved_at
+			 ) h ON h.assigned_at IS NOT NULL
+			 WHERE ($2::timestamp IS NULL OR fa.approved_at >= $2) AND ($3::timestamp IS NULL OR fa.approved_at < $3)),
+			(SELECT COUNT(*) FROM reviews
+			 WHERE reviewer_id = $1 AND state = 'CHANGES_REQUESTED'
+			   AND ($2::timestamp IS NULL OR created_at >= $2) AND ($3::timestamp IS NULL OR created_at < $3)),
+			(SELECT COUNT(*) FROM assignment_history
+			 WHERE replaced_user_id = $1
+			   AND ($2::timestamp IS NULL OR created_at >= $2) AND ($3::timestamp IS NULL OR created_at < $3))`,
+		userID, from, to).Scan(&stats.OpenReviews, &stats.CompletedReviews, &stats.Approvals,
+		&stats.AvgTimeToFirstApprovalSeconds, &stats.Declines, &stats.ReassignedAway)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetLeaderboard counts, for every member of the team, PRs merged since the
+// given time with the member as a reviewer, the median time from assignment
+// to the member's first review for reviews since then, and current open
+// reviews.
+func (s *PostgresStorage) GetLeaderboard(ctx context.Context, teamName string, since time.Time) (_ []models.LeaderboardEntry, err error) {
+	ctx, done := s.query(ctx, "GetLeaderboard", &err)
+	defer done()
+
+	rows, err := s.r.Query(ctx,
+		`SELECT u.user_id, u.username,
+			(SELECT COUNT(*) FROM pull_requests pr
+			 WHERE pr.status = 'MERGED' AND pr.assigned_reviewers ? u.user_id AND pr.merged_at >= $2),
+			(SELECT PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM fr.reviewed_at - h.created_at))
+			 FROM assignment_history h
+			 JOIN LATERAL (
+				SELECT MIN(v.created_at) AS reviewed_at FROM reviews v
+				WHERE v.pull_request_id = h.pull_request_id AND v.reviewer_id = h.user_id AND v.created_at >= h.created_at
+			 ) fr ON fr.reviewed_at IS NOT NULL
+			 WHERE h.user_id = u.user_id AND h.action <> 'removed' AND fr.reviewed_at >= $2),
+			(SELECT COUNT(*) FROM pull_requests pr
+			 WHERE pr.status = 'OPEN' AND NOT pr.archived AND NOT pr.is_draft AND pr.assigned_reviewers ? u.user_id)
+		 FROM users u
+		 WHERE u.team_name = $1 AND u.deleted_at IS NULL`,
+		teamName, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.LeaderboardEntry{}
+	for rows.Next() {
+		var e models.LeaderboardEntry
+		if err := rows.Scan(&e.UserID, &e.Username, &e.CompletedReviews, &e.MedianTurnaroundSeconds, &e.OpenReviews); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
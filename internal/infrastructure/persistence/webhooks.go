@@ -0,0 +1,149 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/Thorlik/avito_internship/internal/domain/models"
+)
+
+const webhookDeliveryColumns = `id, event, team_name, pull_request_id, url, payload, status, attempts,
+	last_status, last_error, next_attempt_at, created_at, delivered_at`
+
+func scanWebhookDelivery(row rowScanner) (models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	err := row.Scan(&d.ID, &d.Event, &d.TeamName, &d.PullRequestID, &d.URL, &d.Payload, &d.Status, &d.Attempts,
+		&d.LastStatus, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.DeliveredAt)
+	return d, err
+}
+
+func collectWebhookDeliveries(rows pgx.Rows) ([]models.WebhookDelivery, error) {
+	defer rows.Close()
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (s *PostgresStorage) AddWebhookDelivery(ctx context.Context, d *models.WebhookDelivery) (err error) {
+	ctx, done := s.query(ctx, "AddWebhookDelivery", &err)
+	defer done()
+
+	return s.q.QueryRow(ctx,
+		`INSERT INTO webhook_deliveries (event, team_name, pull_request_id, url, payload, status, next_attempt_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 RETURNING id, created_at`,
+		d.Event, d.TeamName, d.PullRequestID, d.URL, d.Payload, d.Status, d.NextAttemptAt,
+	).Scan(&d.ID, &d.CreatedAt)
+}
+
+func (s *PostgresStorage) GetWebhookDelivery(ctx context.Context, id int64) (_ *models.WebhookDelivery, err error) {
+	ctx, done := s.query(ctx, "GetWebhookDelivery", &err)
+	defer done()
+
+	d, err := scanWebhookDelivery(s.q.QueryRow(ctx,
+		"SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (s *PostgresStorage) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) (_ []models.WebhookDelivery, err error) {
+	ctx, done := s.query(ctx, "ClaimWebhookDeliveries", &err)
+	defer done()
+
+	rows, err := s.q.Query(ctx,
+		`UPDATE webhook_deliveries SET next_attempt_at = $2
+		 WHERE id IN (
+		     SELECT id FROM webhook_deliveries
+		     WHERE status = 'pending' AND next_attempt_at <= $1
+		     ORDER BY id
+		     LIMIT $3
+		     FOR UPDATE SKIP LOCKED)
+		 RETURNING `+webhookDeliveryColumns,
+		now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := collectWebhookDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].ID < deliveries[j].ID
+	})
+	return deliveries, nil
+}
+
+func (s *PostgresStorage) RecordWebhookAttempt(ctx context.Context, d models.WebhookDelivery) (err error) {
+	ctx, done := s.query(ctx, "RecordWebhookAttempt", &err)
+	defer done()
+
+	_, err = s.q.Exec(ctx,
+		`UPDATE webhook_deliveries
+		 SET status = $2, attempts = $3, last_status = $4, last_error = $5, next_attempt_at = $6, delivered_at = $7
+		 WHERE id = $1`,
+		d.ID, d.Status, d.Attempts, d.LastStatus, d.LastError, d.NextAttemptAt, d.DeliveredAt)
+	return err
+}
+
+func (s *PostgresStorage) ListWebhookDeliveries(ctx context.Context, filter models.DeliveryFilter) (_ []models.WebhookDelivery, err error) {
+	ctx, done := s.query(ctx, "ListWebhookDeliveries", &err)
+	defer done()
+
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries"
+	args := []interface{}{}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		query += " WHERE status = $" + strconv.Itoa(len(args))
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += " ORDER BY id DESC LIMIT $" + strconv.Itoa(len(args)-1) + " OFFSET $" + strconv.Itoa(len(args))
+
+	rows, err := s.r.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return collectWebhookDeliveries(rows)
+}
+
+func (s *PostgresStorage) RetryWebhookDelivery(ctx context.Context, id int64, nextAttemptAt time.Time) (_ bool, err error) {
+	ctx, done := s.query(ctx, "RetryWebhookDelivery", &err)
+	defer done()
+
+	tag, err := s.q.Exec(ctx,
+		`UPDATE webhook_deliveries SET status = 'pending', next_attempt_at = $2
+		 WHERE id = $1 AND status = 'dead'`,
+		id, nextAttemptAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (s *PostgresStorage) PurgeWebhookDeliveries(ctx context.Context, cutoff time.Time) (_ int64, err error) {
+	ctx, done := s.query(ctx, "PurgeWebhookDeliveries", &err)
+	defer done()
+
+	tag, err := s.q.Exec(ctx,
+		"DELETE FROM webhook_deliveries WHERE status = 'delivered' AND delivered_at < $1",
+		cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
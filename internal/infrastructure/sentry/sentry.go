@@ -0,0 +1,192 @@
+// Package sentry reports recovered panics to Sentry through its envelope
+// API, without the SDK: one event per panic, with the stack trace and the
+// request it broke.
+package sentry
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config points a Reporter at a Sentry project. DSN is the project's client
+// key URL, https://<key>@<host>/<project>. Environment and ServerName tag
+// the events.
+type Config struct {
+	DSN         string
+	Environment string
+	ServerName  string
+	Timeout     time.Duration
+}
+
+// Reporter sends panics to Sentry. Events go out in the background, so a
+// report never holds up the response; failures are only logged.
+type Reporter struct {
+	cfg      Config
+	endpoint string
+	auth     string
+	client   *http.Client
+}
+
+func New(cfg Config) (*Reporter, error) {
+	u, err := url.Parse(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("sentry: parse DSN: %w", err)
+	}
+	key := u.User.Username()
+	path, project, _ := cutLast(strings.TrimRight(u.Path, "/"), "/")
+	if key == "" || project == "" || u.Host == "" {
+		return nil, fmt.Errorf("sentry: DSN must look like https://<key>@<host>/<project>")
+	}
+	return &Reporter{
+		cfg:      cfg,
+		endpoint: fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, path, project),
+		auth:     "Sentry sentry_version=7, sentry_client=avito-internship/1.0, sentry_key=" + key,
+		client:   &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+type event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Environment string            `json:"environment,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Exception   exceptions        `json:"exception"`
+	Request     request           `json:"request"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+type exceptions struct {
+	Values []exception `json:"values"`
+}
+
+type exception struct {
+	Type       string     `json:"type"`
+	Value      string     `json:"value"`
+	Stacktrace stacktrace `json:"stacktrace"`
+}
+
+type stacktrace struct {
+	Frames []frame `json:"frames"`
+}
+
+type frame struct {
+	Function string `json:"function"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+}
+
+// request leaves out headers and the query string, which may carry tokens.
+type request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// ReportPanic sends the panic value with stack, as returned by debug.Stack
+// in the deferred call that recovered it, tagged with the request ID.
+func (s *Reporter) ReportPanic(r *http.Request, requestID string, value interface{}, stack []byte) {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	e := event{
+		EventID:     hex.EncodeToString(id),
+		Timestamp:   time.Now().UTC(),
+		Platform:    "go",
+		Level:       "error",
+		Environment: s.cfg.Environment,
+		ServerName:  s.cfg.ServerName,
+		Exception: exceptions{Values: []exception{{
+			Type:       "panic",
+			Value:      fmt.Sprint(value),
+			Stacktrace: stacktrace{Frames: frames(stack)},
+		}}},
+		Request: request{Method: r.Method, URL: r.URL.Path},
+	}
+	if requestID != "" {
+		e.Tags = map[string]string{"request_id": requestID}
+	}
+	go func() {
+		if err := s.send(e); err != nil {
+			log.Printf("Cannot report panic to Sentry: %v", err)
+		}
+	}()
+}
+
+func (s *Reporter) send(e event) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	enc.Encode(map[string]string{"event_id": e.EventID})
+	enc.Encode(map[string]string{"type": "event"})
+	if err := enc.Encode(e); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", s.auth)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// frames turns a goroutine trace into Sentry frames, oldest call first as
+// Sentry expects. Frames down to the panic itself belong to the recovery
+// and are left out.
+func frames(stack []byte) []frame {
+	lines := strings.Split(strings.TrimSpace(string(stack)), "\n")
+	var result []frame
+	// lines[0] is the goroutine header; then a function line and a
+	// tab-indented location line for every call.
+	for i := 1; i+1 < len(lines); i += 2 {
+		function := lines[i]
+		if strings.HasPrefix(function, "panic(") {
+			result = result[:0]
+			continue
+		}
+		function = strings.TrimPrefix(function, "created by ")
+		if name, _, ok := cutLast(function, "("); ok && strings.HasSuffix(function, ")") {
+			function = name
+		} else if name, _, ok := strings.Cut(function, " in goroutine "); ok {
+			function = name
+		}
+
+		location, _, _ := strings.Cut(strings.TrimSpace(lines[i+1]), " ")
+		file, line, _ := cutLast(location, ":")
+		lineno, _ := strconv.Atoi(line)
+		result = append(result, frame{Function: function, AbsPath: file, Lineno: lineno})
+	}
+
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
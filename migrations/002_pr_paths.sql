@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS pr_paths (
+    pull_request_id VARCHAR(255) NOT NULL,
+    path VARCHAR(1024) NOT NULL,
+    dir VARCHAR(1024) GENERATED ALWAYS AS (regexp_replace(path, '/[^/]*$', '')) STORED,
+    PRIMARY KEY (pull_request_id, path),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_paths_path ON pr_paths(path);
+CREATE INDEX IF NOT EXISTS idx_pr_paths_dir ON pr_paths(dir);
@@ -0,0 +1,14 @@
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS idx_pr_labels ON pull_requests USING GIN (labels);
+
+CREATE TABLE IF NOT EXISTS team_routing_rules (
+    id BIGSERIAL PRIMARY KEY,
+    team_name VARCHAR(255) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (team_name, label, user_id),
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
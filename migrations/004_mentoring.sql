@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_mentee BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS shadow_reviewers JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS idx_pr_shadow_reviewers ON pull_requests USING GIN (shadow_reviewers);
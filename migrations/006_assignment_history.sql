@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS assignment_history (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    role VARCHAR(32) NOT NULL DEFAULT 'reviewer',
+    action VARCHAR(32) NOT NULL CHECK (action IN ('assigned', 'reassigned')),
+    replaced_user_id VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_history_pr ON assignment_history(pull_request_id, created_at);
+
+-- Backfill the initial assignments of PRs created before history was kept.
+INSERT INTO assignment_history (pull_request_id, user_id, role, action, created_at)
+SELECT pr.pull_request_id, r.user_id, 'reviewer', 'assigned', pr.created_at
+FROM pull_requests pr
+CROSS JOIN LATERAL jsonb_array_elements_text(pr.assigned_reviewers) AS r(user_id)
+WHERE NOT EXISTS (SELECT 1 FROM assignment_history h WHERE h.pull_request_id = pr.pull_request_id);
@@ -0,0 +1,5 @@
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS archived BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_pr_hot_status ON pull_requests(status) WHERE NOT archived;
+CREATE INDEX IF NOT EXISTS idx_pr_archive_candidates ON pull_requests(merged_at) WHERE status = 'MERGED' AND NOT archived;
@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS stale_since TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_pr_open_created ON pull_requests(created_at) WHERE status = 'OPEN' AND NOT archived;
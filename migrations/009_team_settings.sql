@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_name VARCHAR(255) PRIMARY KEY,
+    reviewer_count INT NOT NULL DEFAULT 0 CHECK (reviewer_count >= 0),
+    assignment_strategy VARCHAR(64) NOT NULL DEFAULT '',
+    max_open_reviews INT NOT NULL DEFAULT 0 CHECK (max_open_reviews >= 0),
+    stale_threshold_hours INT NOT NULL DEFAULT 0 CHECK (stale_threshold_hours >= 0),
+    notification_channel TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE
+);
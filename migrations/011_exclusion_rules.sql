@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS team_exclusion_rules (
+    id BIGSERIAL PRIMARY KEY,
+    team_name VARCHAR(255) NOT NULL,
+    reviewer_id VARCHAR(255) NOT NULL,
+    author_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (team_name, reviewer_id, author_id),
+    CHECK (reviewer_id <> author_id),
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE,
+    FOREIGN KEY (reviewer_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
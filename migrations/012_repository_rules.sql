@@ -0,0 +1,14 @@
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS repository VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_pr_repository ON pull_requests(repository);
+
+CREATE TABLE IF NOT EXISTS team_repository_rules (
+    id BIGSERIAL PRIMARY KEY,
+    team_name VARCHAR(255) NOT NULL,
+    repository VARCHAR(255) NOT NULL,
+    user_ids JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_repository_rules_team ON team_repository_rules(team_name);
@@ -0,0 +1 @@
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS requested_reviewers JSONB NOT NULL DEFAULT '[]';
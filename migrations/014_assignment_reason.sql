@@ -0,0 +1 @@
+ALTER TABLE assignment_history ADD COLUMN IF NOT EXISTS reason VARCHAR(32) NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS team_rotation (
+    team_name VARCHAR(255) PRIMARY KEY,
+    last_user_id VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (team_name) REFERENCES teams(team_name) ON DELETE CASCADE
+);
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS working_hours VARCHAR(11) NOT NULL DEFAULT '';
+
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS prefer_working_hours BOOLEAN NOT NULL DEFAULT false;
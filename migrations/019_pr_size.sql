@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS lines_changed INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS files_changed INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_assignment_history_user ON assignment_history(user_id, created_at);
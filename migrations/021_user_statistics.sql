@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_reviews_reviewer ON reviews(reviewer_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_assignment_history_replaced ON assignment_history(replaced_user_id, created_at) WHERE replaced_user_id IS NOT NULL;
@@ -0,0 +1,34 @@
+-- Statistics are served from these views and refreshed periodically, so
+-- GET /statistics does not scan pull_requests on every call.
+CREATE MATERIALIZED VIEW IF NOT EXISTS statistics_totals AS
+SELECT 1 AS id,
+    (SELECT COUNT(*) FROM teams) AS total_teams,
+    (SELECT COUNT(*) FROM users) AS total_users,
+    (SELECT COUNT(*) FROM users WHERE is_active) AS active_users,
+    (SELECT COUNT(*) FROM pull_requests) AS total_prs,
+    (SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN' AND NOT is_draft) AS open_prs,
+    (SELECT COUNT(*) FROM pull_requests WHERE status = 'MERGED') AS merged_prs,
+    (SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN' AND is_draft) AS draft_prs,
+    CURRENT_TIMESTAMP::timestamp AS refreshed_at;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_statistics_totals ON statistics_totals(id);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS statistics_open_by_priority AS
+SELECT priority, COUNT(*) AS open_prs
+FROM pull_requests
+WHERE status = 'OPEN' AND NOT is_draft
+GROUP BY priority;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_statistics_open_by_priority ON statistics_open_by_priority(priority);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS statistics_reviewers AS
+SELECT u.user_id, u.username,
+    COUNT(*) FILTER (WHERE pr.status = 'OPEN') AS open_reviews,
+    COUNT(*) FILTER (WHERE pr.status = 'MERGED') AS completed_reviews,
+    COUNT(*) AS total_reviews
+FROM users u
+JOIN pull_requests pr ON pr.assigned_reviewers ? u.user_id
+GROUP BY u.user_id, u.username;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_statistics_reviewers ON statistics_reviewers(user_id);
+CREATE INDEX IF NOT EXISTS idx_statistics_reviewers_rank ON statistics_reviewers(total_reviews DESC, open_reviews DESC);
@@ -0,0 +1,7 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP;
+
+UPDATE users SET deactivated_at = updated_at WHERE NOT is_active AND deactivated_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_erase_candidates ON users(deactivated_at) WHERE erased_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_pr_purge_candidates ON pull_requests(archived_at) WHERE archived;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS scheduler_leases (
+    name VARCHAR(64) PRIMARY KEY,
+    holder VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
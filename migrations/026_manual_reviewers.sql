@@ -0,0 +1,4 @@
+-- Reviewers added or removed by hand are recorded with their own actions.
+ALTER TABLE assignment_history DROP CONSTRAINT IF EXISTS assignment_history_action_check;
+ALTER TABLE assignment_history ADD CONSTRAINT assignment_history_action_check
+    CHECK (action IN ('assigned', 'reassigned', 'added', 'removed'));
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS seniority VARCHAR(16) NOT NULL DEFAULT ''
+    CHECK (seniority IN ('', 'junior', 'middle', 'senior'));
+
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS require_senior BOOLEAN NOT NULL DEFAULT false;
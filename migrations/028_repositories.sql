@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS repositories (
+    name VARCHAR(255) PRIMARY KEY,
+    default_team VARCHAR(255) REFERENCES teams(team_name),
+    reviewer_count INTEGER NOT NULL DEFAULT 0 CHECK (reviewer_count BETWEEN 0 AND 10),
+    required_approvals INTEGER NOT NULL DEFAULT 0 CHECK (required_approvals BETWEEN 0 AND 10),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_repositories_default_team ON repositories(default_team);
+
+-- PRs name a repository or one of its components ("repo/component"); the
+-- breakdown counts them under the repository.
+CREATE MATERIALIZED VIEW IF NOT EXISTS statistics_by_repository AS
+SELECT split_part(repository, '/', 1) AS repository,
+    COUNT(*) AS total_prs,
+    COUNT(*) FILTER (WHERE status = 'OPEN' AND NOT is_draft) AS open_prs,
+    COUNT(*) FILTER (WHERE status = 'MERGED') AS merged_prs,
+    COUNT(*) FILTER (WHERE status = 'OPEN' AND is_draft) AS draft_prs
+FROM pull_requests
+WHERE repository <> ''
+GROUP BY split_part(repository, '/', 1);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_statistics_by_repository ON statistics_by_repository(repository);
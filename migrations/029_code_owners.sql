@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS repository_code_owners (
+    repository VARCHAR(255) NOT NULL REFERENCES repositories(name) ON DELETE CASCADE,
+    line INTEGER NOT NULL,
+    pattern TEXT NOT NULL,
+    owners JSONB NOT NULL,
+    PRIMARY KEY (repository, line)
+);
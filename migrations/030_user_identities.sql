@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS user_identities (
+    user_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    provider VARCHAR(16) NOT NULL CHECK (provider IN ('github', 'gitlab', 'slack', 'email')),
+    external_id VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, provider),
+    UNIQUE (provider, external_id)
+);
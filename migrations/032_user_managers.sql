@@ -0,0 +1,7 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS manager_id VARCHAR(255)
+    REFERENCES users(user_id) ON DELETE SET NULL
+    CHECK (manager_id <> user_id);
+
+CREATE INDEX IF NOT EXISTS idx_users_manager_id ON users(manager_id) WHERE manager_id IS NOT NULL;
+
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS peer_review_only BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,33 @@
+ALTER TABLE user_identities DROP CONSTRAINT IF EXISTS user_identities_provider_check;
+ALTER TABLE user_identities ADD CONSTRAINT user_identities_provider_check
+    CHECK (provider IN ('github', 'gitlab', 'slack', 'email', 'jira'));
+
+-- Calls to external systems, written with the change causing them and
+-- deleted once delivered. Messages that ran out of attempts keep a NULL
+-- next_attempt_at.
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(64) NOT NULL,
+    pull_request_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_next_attempt_at ON outbox(next_attempt_at) WHERE next_attempt_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_pull_request_id ON outbox(pull_request_id, id);
+
+CREATE TABLE IF NOT EXISTS review_issues (
+    pull_request_id VARCHAR(255) PRIMARY KEY REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    issue_key VARCHAR(255) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS review_tasks (
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    issue_key VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (pull_request_id, user_id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS notification_provider VARCHAR(32) NOT NULL DEFAULT ''
+    CHECK (notification_provider IN ('', 'webhook', 'slack', 'msteams', 'mattermost'));
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS notification_template TEXT NOT NULL DEFAULT '';
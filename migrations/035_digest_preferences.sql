@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS digest_preferences (
+    user_id VARCHAR(255) PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    send_at VARCHAR(5) NOT NULL DEFAULT '09:00',
+    channel VARCHAR(2048) NOT NULL DEFAULT '',
+    provider VARCHAR(32) NOT NULL DEFAULT ''
+        CHECK (provider IN ('', 'webhook', 'slack', 'msteams', 'mattermost')),
+    last_sent_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_digest_preferences_enabled ON digest_preferences(user_id) WHERE enabled;
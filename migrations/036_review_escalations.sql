@@ -0,0 +1,19 @@
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS remind_after_hours INT NOT NULL DEFAULT 0 CHECK (remind_after_hours >= 0);
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS escalate_after_hours INT NOT NULL DEFAULT 0 CHECK (escalate_after_hours >= 0);
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS reassign_after_hours INT NOT NULL DEFAULT 0 CHECK (reassign_after_hours >= 0);
+
+-- Audit log of the escalation steps taken on idle reviewers. assigned_at is
+-- when the reviewer was assigned, so every assignment is escalated afresh.
+CREATE TABLE IF NOT EXISTS review_escalations (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    team_name VARCHAR(255) NOT NULL,
+    action VARCHAR(32) NOT NULL CHECK (action IN ('reminded', 'lead_notified', 'reassigned')),
+    target_id VARCHAR(255) NOT NULL DEFAULT '',
+    assigned_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (pull_request_id, user_id, assigned_at, action)
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_escalations_team ON review_escalations(team_name, created_at);
@@ -0,0 +1,12 @@
+-- Findings of the last anomaly check. detected_at is when a finding was
+-- first made; it is kept for as long as later checks make it again.
+CREATE TABLE IF NOT EXISTS anomalies (
+    kind VARCHAR(64) NOT NULL,
+    team_name VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL DEFAULT '',
+    value DOUBLE PRECISION NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    message TEXT NOT NULL,
+    detected_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (kind, team_name, user_id)
+);
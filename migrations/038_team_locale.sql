@@ -0,0 +1,2 @@
+-- Language of the team's notifications; empty keeps the service default.
+ALTER TABLE team_settings ADD COLUMN IF NOT EXISTS locale VARCHAR(8) NOT NULL DEFAULT '';
@@ -0,0 +1,8 @@
+-- The read-only maintenance mode, kept in a single row so it survives
+-- restarts and applies to every instance.
+CREATE TABLE IF NOT EXISTS maintenance (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    enabled BOOLEAN NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
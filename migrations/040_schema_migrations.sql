@@ -0,0 +1,51 @@
+-- Applied migrations, checked by the server against the schema version it
+-- requires. Every migration from this one on records itself at its end.
+-- The migrations before it ran in order, so they are recorded here.
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO schema_migrations (version, name) VALUES
+    (1, 'init'),
+    (2, 'pr_paths'),
+    (3, 'labels_routing'),
+    (4, 'mentoring'),
+    (5, 'reviews'),
+    (6, 'assignment_history'),
+    (7, 'archive'),
+    (8, 'stale'),
+    (9, 'team_settings'),
+    (10, 'review_weight'),
+    (11, 'exclusion_rules'),
+    (12, 'repository_rules'),
+    (13, 'requested_reviewers'),
+    (14, 'assignment_reason'),
+    (15, 'rotation_cursor'),
+    (16, 'working_hours'),
+    (17, 'priority'),
+    (18, 'drafts'),
+    (19, 'pr_size'),
+    (20, 'history_user_index'),
+    (21, 'user_statistics'),
+    (22, 'statistics_views'),
+    (23, 'retention'),
+    (24, 'scheduler_leases'),
+    (25, 'feature_flags'),
+    (26, 'manual_reviewers'),
+    (27, 'seniority'),
+    (28, 'repositories'),
+    (29, 'code_owners'),
+    (30, 'user_identities'),
+    (31, 'directory_sync'),
+    (32, 'user_managers'),
+    (33, 'review_tasks'),
+    (34, 'notification_providers'),
+    (35, 'digest_preferences'),
+    (36, 'review_escalations'),
+    (37, 'anomalies'),
+    (38, 'team_locale'),
+    (39, 'maintenance'),
+    (40, 'schema_migrations')
+ON CONFLICT (version) DO NOTHING;
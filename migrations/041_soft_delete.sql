@@ -0,0 +1,25 @@
+-- Deleted users and teams are kept with deleted_at set so they can be
+-- restored; the purge job removes them for good later.
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS idx_teams_deleted ON teams(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_users_deleted ON users(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Totals count live teams and users only.
+DROP MATERIALIZED VIEW IF EXISTS statistics_totals;
+
+CREATE MATERIALIZED VIEW statistics_totals AS
+SELECT 1 AS id,
+    (SELECT COUNT(*) FROM teams WHERE deleted_at IS NULL) AS total_teams,
+    (SELECT COUNT(*) FROM users WHERE deleted_at IS NULL) AS total_users,
+    (SELECT COUNT(*) FROM users WHERE deleted_at IS NULL AND is_active) AS active_users,
+    (SELECT COUNT(*) FROM pull_requests) AS total_prs,
+    (SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN' AND NOT is_draft) AS open_prs,
+    (SELECT COUNT(*) FROM pull_requests WHERE status = 'MERGED') AS merged_prs,
+    (SELECT COUNT(*) FROM pull_requests WHERE status = 'OPEN' AND is_draft) AS draft_prs,
+    CURRENT_TIMESTAMP::timestamp AS refreshed_at;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_statistics_totals ON statistics_totals(id);
+
+INSERT INTO schema_migrations (version, name) VALUES (41, 'soft_delete') ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,32 @@
+-- Usernames are unique regardless of case, so people can be looked up by
+-- them. Erased users all share one placeholder name and are left out;
+-- deleted ones keep theirs so they can be restored. Emails need no index of
+-- their own: they are email identities, lowercased when set and already
+-- unique per provider.
+--
+-- Names that differ only in case cannot be told apart by the index, so the
+-- migration stops and lists them; rename all but one of each and rerun it.
+DO $$
+DECLARE
+    conflicts TEXT;
+BEGIN
+    SELECT string_agg(name || ' (' || user_ids || ')', ', ' ORDER BY name) INTO conflicts
+    FROM (
+        SELECT LOWER(username) AS name, string_agg(user_id, ', ' ORDER BY user_id) AS user_ids
+        FROM users
+        WHERE erased_at IS NULL
+        GROUP BY LOWER(username)
+        HAVING COUNT(*) > 1
+    ) AS duplicates;
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'usernames must be unique regardless of case; rename the users sharing: %', conflicts;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (LOWER(username)) WHERE erased_at IS NULL;
+
+-- Trigram index for prefix and fuzzy user search.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS idx_users_username_trgm ON users USING GIN (LOWER(username) gin_trgm_ops);
+
+INSERT INTO schema_migrations (version, name) VALUES (42, 'username_lookup') ON CONFLICT (version) DO NOTHING;
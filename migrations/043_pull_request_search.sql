@@ -0,0 +1,7 @@
+-- Full-text search over PR names. The simple configuration does no
+-- stemming, as names mix languages, code identifiers and ticket keys.
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS name_search TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', pull_request_name)) STORED;
+CREATE INDEX IF NOT EXISTS idx_pr_name_search ON pull_requests USING GIN (name_search);
+
+INSERT INTO schema_migrations (version, name) VALUES (43, 'pull_request_search') ON CONFLICT (version) DO NOTHING;
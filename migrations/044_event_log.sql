@@ -0,0 +1,14 @@
+-- Every published PR lifecycle event, with the PR as it was after the
+-- change and the assignment history entries it recorded, so derived data
+-- can be rebuilt by replaying them. Events go with their PR.
+CREATE TABLE IF NOT EXISTS domain_events (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(64) NOT NULL,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_domain_events_pr ON domain_events (pull_request_id, id);
+
+INSERT INTO schema_migrations (version, name) VALUES (44, 'event_log') ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,23 @@
+-- Notifications posted to chat webhooks, kept with the outcome of their
+-- last attempt. Pending deliveries are retried at next_attempt_at; those
+-- that ran out of attempts are dead until retried by hand.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    event VARCHAR(64) NOT NULL,
+    team_name VARCHAR(255) NOT NULL DEFAULT '',
+    pull_request_id VARCHAR(255) NOT NULL DEFAULT '',
+    url TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_status INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries (status, id);
+
+INSERT INTO schema_migrations (version, name) VALUES (45, 'webhook_deliveries') ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,10 @@
+-- PRs declined on their Git host are closed without a merge.
+ALTER TABLE pull_requests DROP CONSTRAINT IF EXISTS pull_requests_status_check;
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_status_check
+    CHECK (status IN ('OPEN', 'MERGED', 'CLOSED'));
+
+ALTER TABLE user_identities DROP CONSTRAINT IF EXISTS user_identities_provider_check;
+ALTER TABLE user_identities ADD CONSTRAINT user_identities_provider_check
+    CHECK (provider IN ('github', 'gitlab', 'slack', 'email', 'jira', 'bitbucket'));
+
+INSERT INTO schema_migrations (version, name) VALUES (46, 'git_host_events') ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,5 @@
+ALTER TABLE user_identities DROP CONSTRAINT IF EXISTS user_identities_provider_check;
+ALTER TABLE user_identities ADD CONSTRAINT user_identities_provider_check
+    CHECK (provider IN ('github', 'gitlab', 'slack', 'email', 'jira', 'bitbucket', 'gerrit'));
+
+INSERT INTO schema_migrations (version, name) VALUES (47, 'gerrit_identities') ON CONFLICT (version) DO NOTHING;
@@ -1,3 +1,7 @@
+//go:build e2e
+
+// End-to-end tests run against a live server: go test -tags e2e ./tests/.
+// E2E_BASE_URL points them at a server other than localhost:8080.
 package main
 
 import (
@@ -6,11 +10,19 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"testing"
 	"time"
 )
 
-const baseURL = "http://localhost:8080"
+const defaultBaseURL = "http://localhost:8080"
+
+func baseURL() string {
+	if url := os.Getenv("E2E_BASE_URL"); url != "" {
+		return url
+	}
+	return defaultBaseURL
+}
 
 type TestClient struct {
 	baseURL string
@@ -19,7 +31,7 @@ type TestClient struct {
 
 func NewTestClient() *TestClient {
 	return &TestClient{
-		baseURL: baseURL,
+		baseURL: baseURL(),
 		client: &http.Client{
 			Timeout: 10 * time.Second,
 		},
@@ -217,6 +229,70 @@ func TestE2E_FullWorkflow(t *testing.T) {
 		}
 	})
 
+	t.Run("GetPullRequest", func(t *testing.T) {
+		if prID == "" {
+			t.Skip("PR not created, skipping get test")
+		}
+
+		resp, err := client.get("/pullRequest/get?pull_request_id=" + prID)
+		if err != nil {
+			t.Fatalf("Failed to get PR: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("Expected status 200, got %d. Body: %s", resp.StatusCode, string(body))
+		}
+
+		var result struct {
+			PR struct {
+				PullRequestID     string   `json:"pull_request_id"`
+				AssignedReviewers []string `json:"assigned_reviewers"`
+			} `json:"pr"`
+			History []struct {
+				UserID string `json:"user_id"`
+				Action string `json:"action"`
+			} `json:"history"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		if result.PR.PullRequestID != prID {
+			t.Errorf("Expected PR %s, got %s", prID, result.PR.PullRequestID)
+		}
+		if len(result.History) < len(result.PR.AssignedReviewers) {
+			t.Errorf("Expected at least %d history entries, got %d", len(result.PR.AssignedReviewers), len(result.History))
+		}
+	})
+
+	t.Run("GetPullRequest_Versions", func(t *testing.T) {
+		if prID == "" {
+			t.Skip("PR not created, skipping versions test")
+		}
+
+		fields := map[string]string{"/api/v1": "createdAt", "/api/v2": "created_at"}
+		for prefix, field := range fields {
+			resp, err := client.get(prefix + "/pull-requests/" + prID)
+			if err != nil {
+				t.Fatalf("Failed to get PR via %s: %v", prefix, err)
+			}
+
+			var result struct {
+				PR map[string]interface{} `json:"pr"`
+			}
+			err = json.NewDecoder(resp.Body).Decode(&result)
+			resp.Body.Close()
+			if err != nil {
+				t.Fatalf("Failed to decode %s response: %v", prefix, err)
+			}
+			if _, ok := result.PR[field]; !ok {
+				t.Errorf("Expected %s response to contain %s, got %v", prefix, field, result.PR)
+			}
+		}
+	})
+
 	t.Run("MergePullRequest", func(t *testing.T) {
 		if prID == "" {
 			t.Skip("PR not created, skipping merge test")
@@ -294,6 +370,27 @@ func TestE2E_FullWorkflow(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("GetWorkload", func(t *testing.T) {
+		resp, err := client.get("/statistics/workload")
+		if err != nil {
+			t.Fatalf("Failed to get workload: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+		}
+
+		var result map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode workload: %v", err)
+		}
+
+		if _, ok := result["users"]; !ok {
+			t.Error("Expected users field in workload response")
+		}
+	})
 }
 
 func TestE2E_ErrorCases(t *testing.T) {
@@ -377,4 +474,54 @@ func TestE2E_ErrorCases(t *testing.T) {
 			t.Errorf("Expected 409 for duplicate PR, got %d", resp2.StatusCode)
 		}
 	})
+
+	t.Run("CreatePR_ValidationError", func(t *testing.T) {
+		payload := map[string]interface{}{
+			"pull_request_id": "invalid_pr",
+		}
+
+		resp, err := client.post("/pullRequest/create", payload)
+		if err != nil {
+			t.Fatalf("Failed to create PR: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("Expected 400 for missing fields, got %d", resp.StatusCode)
+		}
+
+		var result struct {
+			Error struct {
+				Code   string `json:"code"`
+				Fields []struct {
+					Field string `json:"field"`
+				} `json:"fields"`
+			} `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		if result.Error.Code != "VALIDATION_ERROR" {
+			t.Errorf("Expected VALIDATION_ERROR, got %s", result.Error.Code)
+		}
+		if len(result.Error.Fields) != 2 {
+			t.Errorf("Expected 2 field errors, got %d", len(result.Error.Fields))
+		}
+	})
+
+	t.Run("MergePR_WrongMethod", func(t *testing.T) {
+		resp, err := client.get("/pullRequest/merge")
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("Expected 405 for GET /pullRequest/merge, got %d", resp.StatusCode)
+		}
+		if allow := resp.Header.Get("Allow"); allow != http.MethodPost {
+			t.Errorf("Expected Allow: POST, got %q", allow)
+		}
+	})
 }